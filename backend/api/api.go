@@ -198,6 +198,7 @@ type HandlerDeps struct {
 	Template          *services.TemplateService
 	ContainerRegistry *services.ContainerRegistryService
 	System            *services.SystemService
+	PruneSchedule     *services.PruneScheduleService
 	SystemUpgrade     *services.SystemUpgradeService
 	Diagnostics       *services.DiagnosticsService
 	Updater           *services.UpdaterService
@@ -268,8 +269,9 @@ func SetupAPI(e *echo.Echo, apiGroup *echo.Group, appCtx handlers.ActivityAppCon
 	api := humaecho.NewWithGroup(e, apiGroup, humaConfig)
 
 	// Add authentication middleware
-	api.UseMiddleware(middleware.NewAuthBridge(api, deps.Auth, deps.ApiKey, deps.Role, deps.Environment, cfg))
+	api.UseMiddleware(middleware.NewAuthBridge(api, deps.Auth, deps.ApiKey, deps.Role, deps.Environment, deps.Settings, cfg))
 	api.UseMiddleware(middleware.NewActivityBatchID())
+	api.UseMiddleware(middleware.NewMaintenanceGuard(api, deps.Settings))
 
 	// Register all Huma handlers
 	registerHandlersInternal(api, deps, appCtx, cfg)
@@ -383,7 +385,7 @@ func registerHandlersInternal(api huma.API, deps HandlerDeps, handlerAppCtx hand
 	handlers.RegisterSettings(api, deps.Settings, deps.SettingsSearch, deps.Environment, cfg)
 	handlers.RegisterJobSchedules(api, deps.JobSchedule, deps.Environment)
 	handlers.RegisterVolumes(api, deps.Docker, deps.Volume, deps.Activity, handlerAppCtx)
-	handlers.RegisterContainers(api, deps.Container, deps.Docker, deps.Settings, deps.Activity, handlerAppCtx)
+	handlers.RegisterContainers(api, deps.Container, deps.Docker, deps.Settings, deps.Activity, handlerAppCtx, cfg)
 	handlers.RegisterPorts(api, deps.Port)
 	handlers.RegisterNetworks(api, deps.Network, deps.Docker, deps.Activity, handlerAppCtx)
 	handlers.RegisterSwarm(api, deps.Swarm, deps.Environment, deps.Event, cfg)
@@ -391,6 +393,7 @@ func registerHandlersInternal(api huma.API, deps HandlerDeps, handlerAppCtx hand
 	handlers.RegisterUpdater(api, deps.Updater, handlerAppCtx)
 	handlers.RegisterCustomize(api, deps.CustomizeSearch)
 	handlers.RegisterSystem(api, deps.Docker, deps.System, deps.SystemUpgrade, deps.Environment, cfg, deps.Activity, handlerAppCtx)
+	handlers.RegisterPruneSchedules(api, deps.PruneSchedule)
 	handlers.RegisterDiagnostics(api, deps.Diagnostics)
 	handlers.RegisterGitRepositories(api, deps.GitRepository)
 	handlers.RegisterGitOpsSyncs(api, deps.GitOpsSync)
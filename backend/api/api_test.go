@@ -10,6 +10,7 @@ import (
 
 	humav2 "github.com/danielgtaylor/huma/v2"
 	"github.com/getarcaneapp/arcane/backend/v2/api/handlers"
+	"github.com/getarcaneapp/arcane/backend/v2/api/middleware"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
 	basetypes "github.com/getarcaneapp/arcane/types/v2/base"
@@ -328,3 +329,87 @@ func TestEasyJoinRoutesDeclareSwarmJoinPermission(t *testing.T) {
 		})
 	}
 }
+
+// maintenanceExemptOperations lists the environment-scoped write operations that
+// stay available in maintenance mode. They either only read despite using a
+// request body, or change Arcane's own records for the environment rather than
+// its containers, images, networks, volumes, projects or swarm objects.
+var maintenanceExemptOperations = map[string]string{
+	"set-environment-maintenance":            "turns maintenance mode off again",
+	"update-settings":                        "environment settings",
+	"testConnection":                         "environment management",
+	"updateHeartbeat":                        "environment management",
+	"pairAgent":                              "environment management",
+	"repairAgent":                            "environment management",
+	"syncEnvironment":                        "environment management",
+	"cancel-activity":                        "activity bookkeeping",
+	"clear-activity-history":                 "activity bookkeeping",
+	"search-container-logs":                  "read-only",
+	"render-swarm-stack-config":              "read-only",
+	"convert-docker-run":                     "read-only",
+	"get-image-vulnerability-summaries":      "read-only",
+	"check-image-update-by-id-post":          "registry lookup",
+	"check-multiple-images":                  "registry lookup",
+	"check-all-images":                       "registry lookup",
+	"set-container-auto-update":              "auto-update setting",
+	"archive-project":                        "project bookkeeping",
+	"unarchive-project":                      "project bookkeeping",
+	"update-job-schedules":                   "schedule settings",
+	"create-prune-schedule":                  "schedule settings",
+	"update-prune-schedule":                  "schedule settings",
+	"delete-prune-schedule":                  "schedule settings",
+	"create-or-update-notification-settings": "notification settings",
+	"delete-notification-settings":           "notification settings",
+	"test-notification":                      "notification settings",
+	"create-webhook":                         "webhook settings",
+	"update-webhook":                         "webhook settings",
+	"delete-webhook":                         "webhook settings",
+	"ignore-vulnerability":                   "scan bookkeeping",
+	"unignore-vulnerability":                 "scan bookkeeping",
+	"updateGlobalVariables":                  "template variables",
+	"get-swarm-node-agent-deployment":        "agent registration",
+	"reconcile-swarm-node-agents":            "agent registration",
+	"put-swarm-node-agent-binding":           "agent registration",
+	"delete-swarm-node-agent-binding":        "agent registration",
+	"delete-swarm-node-agent-deployment":     "agent registration",
+}
+
+func TestSetupAPIForSpec_MutatingEnvironmentRoutesBlockedInMaintenance(t *testing.T) {
+	api := SetupAPIForSpec()
+
+	for path, pathItem := range api.OpenAPI().Paths {
+		if !strings.HasPrefix(path, "/environments/{id}/") {
+			continue
+		}
+		for method, operation := range map[string]*humav2.Operation{
+			"POST":   pathItem.Post,
+			"PUT":    pathItem.Put,
+			"PATCH":  pathItem.Patch,
+			"DELETE": pathItem.Delete,
+		} {
+			if operation == nil {
+				continue
+			}
+			if _, exempt := maintenanceExemptOperations[operation.OperationID]; exempt {
+				continue
+			}
+			require.Equalf(t, true, operation.Metadata[middleware.MetaBlockedInMaintenance], "%s %s (%s) must set BlockedInMaintenance metadata or be listed as exempt", method, path, operation.OperationID)
+		}
+	}
+}
+
+func TestMaintenanceExemptOperationsAreRegistered(t *testing.T) {
+	api := SetupAPIForSpec()
+
+	registered := map[string]bool{}
+	for _, pathItem := range api.OpenAPI().Paths {
+		for _, operation := range []*humav2.Operation{pathItem.Post, pathItem.Put, pathItem.Patch, pathItem.Delete} {
+			if operation != nil {
+				registered[operation.OperationID] = true
+			}
+		}
+	}
+	for operationID := range maintenanceExemptOperations {
+		require.Truef(t, registered[operationID], "exempt operation %q is not registered", operationID)
+	}
+}
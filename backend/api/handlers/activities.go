@@ -34,7 +34,7 @@ type ActivityHandler struct {
 type ListActivitiesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"desc" doc:"Sort direction"`
 	Start         int    `query:"start" default:"0" doc:"Start index"`
 	Limit         int    `query:"limit" default:"50" doc:"Limit"`
@@ -23,7 +23,7 @@ type ApiKeyHandler struct {
 
 type ListApiKeysInput struct {
 	Search string `query:"search" doc:"Search query for filtering by name or description"`
-	Sort   string `query:"sort" doc:"Column to sort by"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order  string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start  int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit  int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -207,6 +207,9 @@ func (h *ApiKeyHandler) CreateApiKey(ctx context.Context, input *CreateApiKeyInp
 		if errors.Is(err, services.ErrApiKeyPermissionEscalation) {
 			return nil, huma.Error403Forbidden(err.Error())
 		}
+		if errors.Is(err, services.ErrApiKeyInvalidGrant) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, huma.Error500InternalServerError("Failed to create API key")
 	}
 
@@ -251,7 +254,7 @@ func (h *ApiKeyHandler) UpdateApiKey(ctx context.Context, input *UpdateApiKeyInp
 		if errors.Is(err, services.ErrApiKeyPermissionEscalation) {
 			return nil, huma.Error403Forbidden(err.Error())
 		}
-		if errors.Is(err, services.ErrApiKeyPersonalNoGrants) {
+		if errors.Is(err, services.ErrApiKeyPersonalNoGrants) || errors.Is(err, services.ErrApiKeyInvalidGrant) {
 			return nil, huma.Error400BadRequest(err.Error())
 		}
 		return nil, huma.Error500InternalServerError("Failed to update API key")
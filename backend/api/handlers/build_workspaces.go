@@ -61,6 +61,7 @@ func RegisterBuildWorkspaces(api huma.API, workspaceService *services.BuildWorks
 		Description: "Upload a file into the builds workspace root",
 		Tags:        []string{"Builds"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 		RequestBody: &huma.RequestBody{
 			Content: map[string]*huma.MediaType{
 				"multipart/form-data": {
@@ -88,6 +89,7 @@ func RegisterBuildWorkspaces(api huma.API, workspaceService *services.BuildWorks
 		Description: "Create a directory under the builds workspace root",
 		Tags:        []string{"Builds"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermBuildWorkspacesManage, h.CreateDirectory)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -98,6 +100,7 @@ func RegisterBuildWorkspaces(api huma.API, workspaceService *services.BuildWorks
 		Description: "Delete a file or directory under the builds workspace root",
 		Tags:        []string{"Builds"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermBuildWorkspacesManage, h.DeleteFile)
 }
 
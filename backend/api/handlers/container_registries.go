@@ -29,7 +29,7 @@ type ContainerRegistryHandler struct {
 
 type ListContainerRegistriesInput struct {
 	Search string `query:"search" doc:"Search query"`
-	Sort   string `query:"sort" doc:"Column to sort by"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order  string `query:"order" default:"asc" doc:"Sort direction"`
 	Start  int    `query:"start" default:"0" doc:"Start index"`
 	Limit  int    `query:"limit" default:"20" doc:"Items per page"`
@@ -77,7 +77,7 @@ type TestContainerRegistryInput struct {
 }
 
 type TestContainerRegistryOutput struct {
-	Body base.ApiResponse[base.MessageResponse]
+	Body base.ApiResponse[containerregistry.TestResult]
 }
 
 type GetContainerRegistryPullUsageOutput struct {
@@ -185,7 +185,7 @@ func RegisterContainerRegistries(api huma.API, registryService *services.Contain
 		Method:      "POST",
 		Path:        "/container-registries/{id}/test",
 		Summary:     "Test a container registry",
-		Description: "Test connectivity and authentication to a container registry",
+		Description: "Test connectivity and authentication to a container registry and report the token scope the registry grants",
 		Tags:        []string{"Container Registries"},
 		Security:    defaultOperationSecurityInternal(),
 		Middlewares: humamw.RequirePermission(api, authz.PermRegistriesTest),
@@ -315,7 +315,8 @@ func (h *ContainerRegistryHandler) DeleteRegistry(ctx context.Context, input *De
 	}, nil
 }
 
-// TestRegistry tests connectivity to a container registry.
+// TestRegistry tests a container registry's credentials with a daemon login, then
+// fetches a token directly from the registry to report the granted scope.
 func (h *ContainerRegistryHandler) TestRegistry(ctx context.Context, input *TestContainerRegistryInput) (*TestContainerRegistryOutput, error) {
 	reg, err := h.registryService.GetRegistryByID(ctx, input.ID)
 	if err != nil {
@@ -323,41 +324,41 @@ func (h *ContainerRegistryHandler) TestRegistry(ctx context.Context, input *Test
 		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to retrieve registry").Error())
 	}
 
+	var msg string
 	// ECR registries use a different auth flow: generate a temporary token via AWS API.
 	if reg.RegistryType == "ecr" {
 		if err := h.registryService.TestECRRegistry(ctx, reg); err != nil {
 			return nil, huma.Error400BadRequest(errors.WithMessage(err, "Registry test failed").Error())
 		}
-		return &TestContainerRegistryOutput{
-			Body: base.ApiResponse[base.MessageResponse]{
-				Success: true,
-				Data: base.MessageResponse{
-					Message: "ECR authentication succeeded",
-				},
-			},
-		}, nil
-	}
+		msg = "ECR authentication succeeded"
+	} else {
+		decryptedToken, err := crypto.Decrypt(reg.Token)
+		if err != nil {
+			return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to decrypt token").Error())
+		}
 
-	decryptedToken, err := crypto.Decrypt(reg.Token)
-	if err != nil {
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to decrypt token").Error())
-	}
+		if err := h.registryService.TestRegistry(ctx, reg.URL, reg.Username, decryptedToken); err != nil {
+			return nil, huma.Error400BadRequest(errors.WithMessage(err, "Registry test failed").Error())
+		}
 
-	if err := h.registryService.TestRegistry(ctx, reg.URL, reg.Username, decryptedToken); err != nil {
-		return nil, huma.Error400BadRequest(errors.WithMessage(err, "Registry test failed").Error())
+		msg = "Authentication succeeded"
+		if strings.TrimSpace(reg.Username) == "" && strings.TrimSpace(decryptedToken) == "" {
+			msg = "Registry saved (no credentials to test)"
+		}
 	}
 
-	msg := "Authentication succeeded"
-	if strings.TrimSpace(reg.Username) == "" && strings.TrimSpace(decryptedToken) == "" {
-		msg = "Registry saved (no credentials to test)"
+	result := containerregistry.TestResult{Message: msg}
+	if probe, err := h.registryService.ProbeRegistryAuth(ctx, reg); err != nil {
+		result.ProbeError = err.Error()
+	} else {
+		probe.Message = msg
+		result = *probe
 	}
 
 	return &TestContainerRegistryOutput{
-		Body: base.ApiResponse[base.MessageResponse]{
+		Body: base.ApiResponse[containerregistry.TestResult]{
 			Success: true,
-			Data: base.MessageResponse{
-				Message: msg,
-			},
+			Data:    result,
 		},
 	}, nil
 }
@@ -1,17 +1,24 @@
 package handlers
 
 import (
+	"compress/gzip"
 	"context"
+	json "encoding/json/v2"
 	"fmt"
 	"io"
 	"maps"
+	"mime/multipart"
 	"net/http"
 	"net/netip"
+	"strconv"
 	"strings"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
@@ -19,8 +26,11 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane"
 	activitylib "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/activity"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/httpx"
 	"github.com/getarcaneapp/arcane/types/v2/base"
 	containertypes "github.com/getarcaneapp/arcane/types/v2/container"
+	imagetypes "github.com/getarcaneapp/arcane/types/v2/image"
+	volumetypes "github.com/getarcaneapp/arcane/types/v2/volume"
 	dockercontainer "github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
 	"github.com/samber/mo"
@@ -32,6 +42,7 @@ type ContainerHandler struct {
 	settingsService  *services.SettingsService
 	activityService  *services.ActivityService
 	appCtx           context.Context
+	fileMaxBytes     int64
 }
 
 // ContainerPaginatedResponse is the paginated list response for containers.
@@ -46,7 +57,7 @@ type ContainerPaginatedResponse struct {
 type ListContainersInput struct {
 	EnvironmentID   string `path:"id" doc:"Environment ID"`
 	Search          string `query:"search" doc:"Search query"`
-	Sort            string `query:"sort" doc:"Column to sort by"`
+	Sort            string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order           string `query:"order" default:"asc" doc:"Sort direction"`
 	Start           int    `query:"start" default:"0" doc:"Start index"`
 	Limit           int    `query:"limit" default:"20" doc:"Limit"`
@@ -54,6 +65,21 @@ type ListContainersInput struct {
 	IncludeInternal bool   `query:"includeInternal" default:"false" doc:"Include internal containers"`
 	Updates         string `query:"updates" doc:"Filter by update status (has_update, up_to_date, error, unknown)"`
 	Standalone      string `query:"standalone" doc:"Filter standalone containers only (true/false)"`
+	IncludeGPUs     bool   `query:"includeGpus" default:"false" doc:"Inspect each returned container to include its GPU device requests"`
+	IncludeHealth   bool   `query:"includeHealth" default:"false" doc:"Inspect each returned container to include its health-check status"`
+	Filter          string `query:"filter" doc:"Comma-separated range filters on created, e.g. created:>7d or created:2024-01-01..2024-06-30"`
+}
+
+type ExportContainersInput struct {
+	EnvironmentID   string `path:"id" doc:"Environment ID"`
+	Format          string `query:"format" default:"csv" enum:"csv" doc:"Export format"`
+	Search          string `query:"search" doc:"Search query"`
+	Sort            string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order           string `query:"order" default:"asc" doc:"Sort direction"`
+	IncludeInternal bool   `query:"includeInternal" default:"false" doc:"Include internal containers"`
+	Updates         string `query:"updates" doc:"Filter by update status (has_update, up_to_date, error, unknown)"`
+	Standalone      string `query:"standalone" doc:"Filter standalone containers only (true/false)"`
+	Filter          string `query:"filter" doc:"Comma-separated range filters on created, e.g. created:>7d or created:2024-01-01..2024-06-30"`
 }
 
 type ListContainersOutput struct {
@@ -87,6 +113,45 @@ type GetContainerOutput struct {
 	Body base.ApiResponse[containertypes.Details]
 }
 
+type GetContainerHealthInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Limit         int    `query:"limit" default:"5" minimum:"0" doc:"Maximum number of recent health-check results to return (0 for all)"`
+}
+
+type GetContainerHealthOutput struct {
+	Body base.ApiResponse[containertypes.Health]
+}
+
+type GetContainerTopInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	PsArgs        string `query:"psArgs" maxLength:"64" doc:"Arguments passed to ps inside the container (default -ef)"`
+}
+
+type GetContainerTopOutput struct {
+	Body base.ApiResponse[containertypes.Top]
+}
+
+type GetContainerChangesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+}
+
+type GetContainerChangesOutput struct {
+	Body base.ApiResponse[containertypes.Changes]
+}
+
+type RunContainerExecInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Body          containertypes.ExecRunRequest
+}
+
+type RunContainerExecOutput struct {
+	Body base.ApiResponse[containertypes.ExecRunResult]
+}
+
 type ContainerActionInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ContainerID   string `path:"containerId" doc:"Container ID"`
@@ -120,6 +185,14 @@ type SetAutoUpdateOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+// StopContainerInput carries the optional grace period and signal overrides
+// for a container stop or restart.
+type StopContainerInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Body          *containertypes.StopRequest
+}
+
 // KillContainerInput carries the optional signal for a container kill.
 type KillContainerInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
@@ -137,13 +210,104 @@ type CommitContainerOutput struct {
 	Body base.ApiResponse[containertypes.CommitResult]
 }
 
-func RegisterContainers(api huma.API, containerSvc *services.ContainerService, dockerSvc *services.DockerClientService, settingsSvc *services.SettingsService, activitySvc *services.ActivityService, appCtx ActivityAppContext) {
+type UpdateContainerResourcesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Body          containertypes.ResourceUpdate
+}
+
+type UpdateContainerResourcesOutput struct {
+	Body base.ApiResponse[containertypes.ResourceUpdateResult]
+}
+
+type BrowseContainerFilesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Path          string `query:"path" default:"/" doc:"Directory path inside the container"`
+	Filter        string `query:"filter" doc:"Glob matched against entry names (e.g. *.conf)"`
+	Offset        int    `query:"offset" default:"0" minimum:"0" doc:"Number of matching entries to skip"`
+	Limit         int    `query:"limit" default:"0" minimum:"0" doc:"Maximum entries to return; 0 returns all"`
+}
+
+type BrowseContainerFilesOutput struct {
+	TotalCount int `header:"X-Total-Count" doc:"Number of entries matching the filter"`
+	Body       base.ApiResponse[[]volumetypes.FileEntry]
+}
+
+type PutContainerFileInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Path          string `query:"path" required:"true" doc:"Absolute path of the file inside the container"`
+	Body          containertypes.WriteFileRequest
+}
+
+type UploadContainerFileInput struct {
+	EnvironmentID string         `path:"id" doc:"Environment ID"`
+	ContainerID   string         `path:"containerId" doc:"Container ID"`
+	Path          string         `query:"path" default:"/" doc:"Destination directory inside the container"`
+	RawBody       multipart.Form `contentType:"multipart/form-data"`
+}
+
+type RecreateContainerInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Body          *containertypes.RecreateRequest
+}
+
+type GetContainerEnvInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Reveal        bool   `query:"reveal" default:"false" doc:"Show secret-looking values unmasked (admins only)"`
+}
+
+type GetContainerEnvOutput struct {
+	Body base.ApiResponse[[]containertypes.EnvVar]
+}
+
+type SetContainerEnvInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Body          containertypes.EnvUpdate
+}
+
+type BatchContainerActionInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Body          containertypes.BatchActionRequest
+}
+
+type BatchContainerActionOutput struct {
+	Body base.ApiResponse[containertypes.BatchActionResponse]
+}
+
+type ExportContainerLogsInput struct {
+	EnvironmentID  string `path:"id" doc:"Environment ID"`
+	ContainerID    string `path:"containerId" doc:"Container ID"`
+	Tail           string `query:"tail" default:"all" doc:"Number of lines to export from the end of the log"`
+	Since          string `query:"since" doc:"Only export logs since this timestamp"`
+	AcceptEncoding string `header:"Accept-Encoding"`
+}
+
+type SearchContainerLogsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Body          containertypes.LogSearchRequest
+}
+
+type SearchContainerLogsOutput struct {
+	Body base.ApiResponse[containertypes.LogSearchResult]
+}
+
+func RegisterContainers(api huma.API, containerSvc *services.ContainerService, dockerSvc *services.DockerClientService, settingsSvc *services.SettingsService, activitySvc *services.ActivityService, appCtx ActivityAppContext, cfg *config.Config) {
 	h := &ContainerHandler{
 		containerService: containerSvc,
 		dockerService:    dockerSvc,
 		settingsService:  settingsSvc,
 		activityService:  activitySvc,
 		appCtx:           appCtx.contextInternal(),
+		fileMaxBytes:     defaultContainerFileMaxBytes,
+	}
+	if cfg != nil && cfg.ContainerFileMaxBytes > 0 {
+		h.fileMaxBytes = int64(cfg.ContainerFileMaxBytes)
 	}
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -156,6 +320,16 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermContainersList, h.ListContainers)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "export-containers",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/export",
+		Summary:     "Export containers",
+		Description: "Download every container matching the search and filters as CSV",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersList, h.ExportContainers)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "container-status-counts",
 		Method:      http.MethodGet,
@@ -172,8 +346,20 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Summary:     "Create container",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersCreate, h.CreateContainer)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "create-container-stream",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/create/stream",
+		Summary:     "Create container with pull progress",
+		Description: "Create a container, streaming image pull progress as NDJSON while a missing image is pulled",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermContainersCreate, h.CreateContainerStream)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "get-container",
 		Method:      http.MethodGet,
@@ -183,6 +369,47 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermContainersRead, h.GetContainer)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-container-health",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/health",
+		Summary:     "Get container health",
+		Description: "Health-check status and the most recent health-check results of a container",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersRead, h.GetContainerHealth)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-container-top",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/top",
+		Summary:     "List container processes",
+		Description: "Processes running in a container, as reported by ps on the Docker host; PIDs are host PIDs",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersRead, h.GetContainerTop)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-container-changes",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/changes",
+		Summary:     "List container filesystem changes",
+		Description: "Paths added, changed or deleted in a container's writable layer relative to its image",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersRead, h.GetContainerChanges)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "run-container-exec",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/exec/run",
+		Summary:     "Run a command in a container",
+		Description: "Run a command without a TTY, wait for it to finish and return its exit code with stdout and stderr captured separately",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermContainersExec, h.RunContainerExec)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "start-container",
 		Method:      http.MethodPost,
@@ -190,6 +417,7 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Summary:     "Start container",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersStart, h.StartContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -197,8 +425,10 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Method:      http.MethodPost,
 		Path:        "/environments/{id}/containers/{containerId}/stop",
 		Summary:     "Stop container",
+		Description: "Stop a container, optionally overriding the grace period and stop signal",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersStop, h.StopContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -206,8 +436,10 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Method:      http.MethodPost,
 		Path:        "/environments/{id}/containers/{containerId}/restart",
 		Summary:     "Restart container",
+		Description: "Restart a container, optionally overriding the grace period and stop signal",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersRestart, h.RestartContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -218,6 +450,7 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Description: "Send a signal to the container's main process (default SIGKILL)",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersKill, h.KillContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -227,6 +460,7 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Summary:     "Pause container",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersPause, h.PauseContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -236,6 +470,7 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Summary:     "Unpause container",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersPause, h.UnpauseContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -246,6 +481,7 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Description: "Create an image from a container",
 		Tags:        []string{"Containers", "Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImagesCommit, h.CommitContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -256,8 +492,52 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Description: "Pull latest image and recreate container",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersRedeploy, h.RedeployContainer)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "recreate-container",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/recreate",
+		Summary:     "Recreate container",
+		Description: "Recreate a container from its existing configuration, optionally with a different image or environment",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermContainersRedeploy, h.RecreateContainer)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-container-env",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/env",
+		Summary:     "Get container environment",
+		Description: "List a container's environment variables, masking secret-looking values",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersRead, h.GetContainerEnv)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "set-container-env",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/containers/{containerId}/env",
+		Summary:     "Set container environment",
+		Description: "Recreate a container from its existing configuration with a new set of environment variables",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermContainersRedeploy, h.SetContainerEnv)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "update-container-resources",
+		Method:      http.MethodPatch,
+		Path:        "/environments/{id}/containers/{containerId}",
+		Summary:     "Update container resources",
+		Description: "Change a container's restart policy and CPU/memory limits in place, without recreating it",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermContainersRedeploy, h.UpdateContainerResources)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "delete-container",
 		Method:      http.MethodDelete,
@@ -265,6 +545,7 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Summary:     "Delete container",
 		Tags:        []string{"Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersDelete, h.DeleteContainer)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -276,6 +557,109 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Tags:        []string{"Containers", "Updater"},
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermContainersAutoUpdate, h.SetAutoUpdate)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "browse-container-files",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/files",
+		Summary:     "Browse container files",
+		Description: "List a directory inside a container, directories first, with optional name filter and pagination",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersExec, h.BrowseContainerFiles)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID:  "put-container-file",
+		Method:       http.MethodPut,
+		Path:         "/environments/{id}/containers/{containerId}/files",
+		Summary:      "Write container file",
+		Description:  "Replace a text file inside a container",
+		Tags:         []string{"Containers"},
+		Security:     defaultOperationSecurityInternal(),
+		Metadata:     humamw.BlockedInMaintenance(),
+		MaxBodyBytes: containerFileBodyLimitInternal(h.fileMaxBytes),
+	}, authz.PermContainersExec, h.PutContainerFile)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID:  "upload-container-file",
+		Method:       http.MethodPost,
+		Path:         "/environments/{id}/containers/{containerId}/files/upload",
+		Summary:      "Upload file to container",
+		Description:  "Copy an uploaded file into a directory inside a container",
+		Tags:         []string{"Containers"},
+		Security:     defaultOperationSecurityInternal(),
+		Metadata:     humamw.BlockedInMaintenance(),
+		MaxBodyBytes: containerFileBodyLimitInternal(h.fileMaxBytes),
+		RequestBody: &huma.RequestBody{
+			Content: map[string]*huma.MediaType{
+				"multipart/form-data": {
+					Schema: &huma.Schema{
+						Type: "object",
+						Properties: map[string]*huma.Schema{
+							"file": {
+								Type:        "string",
+								Format:      "binary",
+								Description: "File to upload",
+							},
+						},
+						Required: []string{"file"},
+					},
+				},
+			},
+		},
+	}, authz.PermContainersExec, h.UploadContainerFile)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "export-container-logs",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/logs/export",
+		Summary:     "Export container logs",
+		Description: "Download the container's timestamped log as a .log file, gzip-encoded when the client accepts it",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersLogs, h.ExportContainerLogs)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "search-container-logs",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/logs/search",
+		Summary:     "Search container logs",
+		Description: "Search the container's full log for lines matching a regular expression, returning matches with line numbers up to a cap",
+		Tags:        []string{"Containers"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermContainersLogs, h.SearchContainerLogs)
+
+	// Batch actions carry the action in the path so each one is gated by the same
+	// permission as its single-container counterpart, locally and through the
+	// remote environment proxy.
+	for _, batch := range []struct {
+		action, perm string
+	}{
+		{services.ContainerBatchActionStart, authz.PermContainersStart},
+		{services.ContainerBatchActionStop, authz.PermContainersStop},
+		{services.ContainerBatchActionRestart, authz.PermContainersRestart},
+		{services.ContainerBatchActionRemove, authz.PermContainersDelete},
+	} {
+		humamw.RegisterWithPermission(api, huma.Operation{
+			OperationID: "batch-" + batch.action + "-containers",
+			Method:      http.MethodPost,
+			Path:        "/environments/{id}/containers/batch/" + batch.action,
+			Summary:     "Batch " + batch.action + " containers",
+			Description: "Run " + batch.action + " against several containers, reporting the outcome per container",
+			Tags:        []string{"Containers"},
+			Security:    defaultOperationSecurityInternal(),
+			Metadata:    humamw.BlockedInMaintenance(),
+		}, batch.perm, h.batchContainerActionInternal(batch.action))
+	}
+}
+
+// defaultContainerFileMaxBytes mirrors the 1MB read cap used by the file viewers.
+const defaultContainerFileMaxBytes int64 = 1 << 20
+
+// containerFileBodyLimitInternal leaves room for JSON escaping and multipart framing so
+// oversize files reach the service and get a clear 413 instead of a generic body error.
+func containerFileBodyLimitInternal(fileMaxBytes int64) int64 {
+	return fileMaxBytes*2 + 64<<10
 }
 
 func (h *ContainerHandler) ListContainers(ctx context.Context, input *ListContainersInput) (*ListContainersOutput, error) {
@@ -286,10 +670,18 @@ func (h *ContainerHandler) ListContainers(ctx context.Context, input *ListContai
 	if input.Standalone != "" {
 		params.Filters["standalone"] = input.Standalone
 	}
+	rangeFilters, err := parseRangeFiltersInternal(input.Filter, h.containerService.ContainerPaginationConfig())
+	if err != nil {
+		return nil, err
+	}
+	params.RangeFilters = rangeFilters
 
-	result, err := h.containerService.ListContainersPaginated(ctx, params, true, input.IncludeInternal, input.GroupBy)
+	result, err := h.containerService.ListContainersPaginated(ctx, params, true, input.IncludeInternal, input.GroupBy, services.ContainerSummaryEnrichment{
+		GPUs:   input.IncludeGPUs,
+		Health: input.IncludeHealth,
+	})
 	if err != nil {
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to list containers").Error())
+		return nil, dockerErrorInternal(err, "Failed to list containers")
 	}
 
 	return &ListContainersOutput{
@@ -303,10 +695,32 @@ func (h *ContainerHandler) ListContainers(ctx context.Context, input *ListContai
 	}, nil
 }
 
+func (h *ContainerHandler) ExportContainers(ctx context.Context, input *ExportContainersInput) (*huma.StreamResponse, error) {
+	params := buildPaginationParamsInternal(0, -1, input.Sort, input.Order, input.Search)
+	if input.Updates != "" {
+		params.Filters["updates"] = input.Updates
+	}
+	if input.Standalone != "" {
+		params.Filters["standalone"] = input.Standalone
+	}
+	rangeFilters, err := parseRangeFiltersInternal(input.Filter, h.containerService.ContainerPaginationConfig())
+	if err != nil {
+		return nil, err
+	}
+	params.RangeFilters = rangeFilters
+
+	result, err := h.containerService.ListContainersPaginated(ctx, params, true, input.IncludeInternal, "", services.ContainerSummaryEnrichment{})
+	if err != nil {
+		return nil, dockerErrorInternal(err, "Failed to export containers")
+	}
+
+	return csvExportResponseInternal("containers.csv", result.Items, h.containerService.ContainerPaginationConfig()), nil
+}
+
 func (h *ContainerHandler) GetContainerStatusCounts(ctx context.Context, input *GetContainerStatusCountsInput) (*GetContainerStatusCountsOutput, error) {
 	containers, _, _, _, err := h.dockerService.GetAllContainers(ctx)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to get container counts").Error())
+		return nil, dockerErrorInternal(err, "Failed to get container counts")
 	}
 
 	if !input.IncludeInternal {
@@ -523,6 +937,50 @@ func applyLegacyResourceLimits(body containertypes.Create, hostConfig *dockercon
 	}
 }
 
+// buildNetworkAttachmentsInternal converts network attachments into endpoint
+// settings and returns the primary (first) network. Docker versions that cannot
+// attach several networks on create get the rest through NetworkConnect in
+// libarcane.ContainerCreateWithCompatibility, which removes the container again
+// if a connect fails.
+func buildNetworkAttachmentsInternal(attachments []containertypes.NetworkAttachmentCreate) (*network.NetworkingConfig, string, error) {
+	networkingConfig := &network.NetworkingConfig{EndpointsConfig: make(map[string]*network.EndpointSettings, len(attachments))}
+	primary := ""
+	for _, attachment := range attachments {
+		name := strings.TrimSpace(attachment.Name)
+		if name == "" {
+			return nil, "", errors.New("network name is required")
+		}
+		if _, exists := networkingConfig.EndpointsConfig[name]; exists {
+			return nil, "", errors.Errorf("network %s is listed more than once", name)
+		}
+
+		endpoint := &network.EndpointSettings{Aliases: attachment.Aliases}
+		if ipv4 := strings.TrimSpace(attachment.IPv4Address); ipv4 != "" {
+			addr, err := netip.ParseAddr(ipv4)
+			if err != nil || !addr.Is4() {
+				return nil, "", errors.Errorf("invalid IPv4 address %q for network %s", ipv4, name)
+			}
+			endpoint.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: addr}
+		}
+		if ipv6 := strings.TrimSpace(attachment.IPv6Address); ipv6 != "" {
+			addr, err := netip.ParseAddr(ipv6)
+			if err != nil || !addr.Is6() || addr.Is4In6() {
+				return nil, "", errors.Errorf("invalid IPv6 address %q for network %s", ipv6, name)
+			}
+			if endpoint.IPAMConfig == nil {
+				endpoint.IPAMConfig = &network.EndpointIPAMConfig{}
+			}
+			endpoint.IPAMConfig.IPv6Address = addr
+		}
+
+		networkingConfig.EndpointsConfig[name] = endpoint
+		if primary == "" {
+			primary = name
+		}
+	}
+	return networkingConfig, primary, nil
+}
+
 func buildNetworkingConfig(body containertypes.Create) *network.NetworkingConfig {
 	if body.NetworkingConfig != nil && len(body.NetworkingConfig.EndpointsConfig) > 0 {
 		networkingConfig := &network.NetworkingConfig{EndpointsConfig: make(map[string]*network.EndpointSettings)}
@@ -549,42 +1007,130 @@ func (h *ContainerHandler) CreateContainer(ctx context.Context, input *CreateCon
 		return nil, err
 	}
 
-	config := buildContainerConfig(input.Body)
-	portBindings := network.PortMap{}
-	if err := applyLegacyPortBindings(input.Body, config, portBindings); err != nil {
-		return nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid port format").Error())
-	}
-	if err := applyExposedPorts(input.Body.ExposedPorts, config); err != nil {
-		return nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid port format").Error())
+	config, hostConfig, networkingConfig, err := buildCreateContainerConfigsInternal(input.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	hostConfig := buildHostConfigBase(input.Body, portBindings)
-	if err := applyHostConfigOverrides(input.Body, config, hostConfig, portBindings); err != nil {
-		return nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid port format").Error())
+	containerJSON, err := h.containerService.CreateContainer(ctx, config, hostConfig, networkingConfig, input.Body.Name, *user, input.Body.Credentials, input.Body.SkipPortCheck, nil)
+	if err != nil {
+		if errors.Is(err, common.ErrContainerHostPortInUse) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to create container")
 	}
-	applyLegacyResourceLimits(input.Body, hostConfig)
 
-	networkingConfig := buildNetworkingConfig(input.Body)
+	return &CreateContainerOutput{
+		Body: base.ApiResponse[containertypes.Created]{
+			Success: true,
+			Data:    newCreatedContainerInternal(containerJSON),
+		},
+	}, nil
+}
 
-	containerJSON, err := h.containerService.CreateContainer(ctx, config, hostConfig, networkingConfig, input.Body.Name, *user, input.Body.Credentials)
+// CreateContainerStream creates a container like CreateContainer, streaming
+// image pull progress as NDJSON while a missing image is pulled. Each progress
+// update is a {"type":"pull",...} frame; the stream ends with a
+// {"done":true,"data":...} frame carrying the created container, or an
+// {"error":...} frame.
+func (h *ContainerHandler) CreateContainerStream(ctx context.Context, input *CreateContainerInput) (*huma.StreamResponse, error) {
+	user, err := requireUserInternal(ctx)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to create container").Error())
+		return nil, err
 	}
 
-	out := containertypes.Created{
+	config, hostConfig, networkingConfig, err := buildCreateContainerConfigsInternal(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) { //nolint:contextcheck // context is obtained from humaCtx.Context()
+			httpx.SetJSONStreamHeaders(humaCtx)
+			writer := humaCtx.BodyWriter()
+			writeFrame := func(frame any) {
+				if err := json.MarshalWrite(writer, frame); err != nil {
+					return
+				}
+				_, _ = io.WriteString(writer, "\n")
+				if f, ok := writer.(http.Flusher); ok {
+					f.Flush()
+				}
+			}
+
+			progressChan := make(chan imagetypes.PullProgress, 64)
+			type createResult struct {
+				container *dockercontainer.InspectResponse
+				err       error
+			}
+			resultChan := make(chan createResult, 1)
+			go func() {
+				defer close(progressChan)
+				created, err := h.containerService.CreateContainer(humaCtx.Context(), config, hostConfig, networkingConfig, input.Body.Name, *user, input.Body.Credentials, input.Body.SkipPortCheck, progressChan)
+				resultChan <- createResult{container: created, err: err}
+			}()
+
+			for progress := range progressChan {
+				writeFrame(struct {
+					Type string `json:"type"`
+					imagetypes.PullProgress
+				}{Type: "pull", PullProgress: progress})
+			}
+
+			result := <-resultChan
+			if result.err != nil {
+				if errors.Is(result.err, common.ErrContainerHostPortInUse) {
+					writeFrame(map[string]string{"error": result.err.Error()})
+					return
+				}
+				writeFrame(map[string]string{"error": errors.WithMessage(result.err, "Failed to create container").Error()})
+				return
+			}
+			writeFrame(map[string]any{"done": true, "data": newCreatedContainerInternal(result.container)})
+		},
+	}, nil
+}
+
+// buildCreateContainerConfigsInternal converts a create request into the Docker
+// container, host and networking configs, mapping invalid ports to 400.
+func buildCreateContainerConfigsInternal(body containertypes.Create) (*dockercontainer.Config, *dockercontainer.HostConfig, *network.NetworkingConfig, error) {
+	config := buildContainerConfig(body)
+	portBindings := network.PortMap{}
+	if err := applyLegacyPortBindings(body, config, portBindings); err != nil {
+		return nil, nil, nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid port format").Error())
+	}
+	if err := applyExposedPorts(body.ExposedPorts, config); err != nil {
+		return nil, nil, nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid port format").Error())
+	}
+
+	hostConfig := buildHostConfigBase(body, portBindings)
+	if err := applyHostConfigOverrides(body, config, hostConfig, portBindings); err != nil {
+		return nil, nil, nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid port format").Error())
+	}
+	applyLegacyResourceLimits(body, hostConfig)
+
+	if len(body.NetworkAttachments) > 0 {
+		networkingConfig, primary, err := buildNetworkAttachmentsInternal(body.NetworkAttachments)
+		if err != nil {
+			return nil, nil, nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid network attachments").Error())
+		}
+		if strings.TrimSpace(string(hostConfig.NetworkMode)) == "" {
+			hostConfig.NetworkMode = dockercontainer.NetworkMode(primary)
+		}
+		return config, hostConfig, networkingConfig, nil
+	}
+
+	return config, hostConfig, buildNetworkingConfig(body), nil
+}
+
+func newCreatedContainerInternal(containerJSON *dockercontainer.InspectResponse) containertypes.Created {
+	return containertypes.Created{
 		ID:      containerJSON.ID,
 		Name:    containerJSON.Name,
 		Image:   containerJSON.Config.Image,
 		Status:  string(containerJSON.State.Status),
 		Created: containerJSON.Created,
 	}
-
-	return &CreateContainerOutput{
-		Body: base.ApiResponse[containertypes.Created]{
-			Success: true,
-			Data:    out,
-		},
-	}, nil
 }
 
 func (h *ContainerHandler) GetContainer(ctx context.Context, input *GetContainerInput) (*GetContainerOutput, error) {
@@ -601,6 +1147,93 @@ func (h *ContainerHandler) GetContainer(ctx context.Context, input *GetContainer
 	}, nil
 }
 
+func (h *ContainerHandler) GetContainerHealth(ctx context.Context, input *GetContainerHealthInput) (*GetContainerHealthOutput, error) {
+	health, err := h.containerService.GetHealthLog(ctx, input.ContainerID, input.Limit)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to retrieve container health").Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to retrieve container health")
+	}
+
+	return &GetContainerHealthOutput{
+		Body: base.ApiResponse[containertypes.Health]{
+			Success: true,
+			Data:    health,
+		},
+	}, nil
+}
+
+func (h *ContainerHandler) GetContainerTop(ctx context.Context, input *GetContainerTopInput) (*GetContainerTopOutput, error) {
+	top, err := h.containerService.TopContainer(ctx, input.ContainerID, input.PsArgs)
+	if err != nil {
+		switch {
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, huma.Error400BadRequest(err.Error())
+		case cerrdefs.IsNotFound(err):
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to list container processes").Error())
+		case errors.Is(err, common.ErrContainerNotRunning):
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to list container processes")
+	}
+
+	return &GetContainerTopOutput{
+		Body: base.ApiResponse[containertypes.Top]{
+			Success: true,
+			Data:    top,
+		},
+	}, nil
+}
+
+func (h *ContainerHandler) GetContainerChanges(ctx context.Context, input *GetContainerChangesInput) (*GetContainerChangesOutput, error) {
+	changes, err := h.containerService.GetContainerChanges(ctx, input.ContainerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to list container changes").Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to list container changes")
+	}
+
+	return &GetContainerChangesOutput{
+		Body: base.ApiResponse[containertypes.Changes]{
+			Success: true,
+			Data:    changes,
+		},
+	}, nil
+}
+
+func (h *ContainerHandler) RunContainerExec(ctx context.Context, input *RunContainerExecInput) (*RunContainerExecOutput, error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.containerService.RunExec(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		switch {
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, huma.Error400BadRequest(err.Error())
+		case cerrdefs.IsNotFound(err):
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to run command").Error())
+		case errors.Is(err, common.ErrContainerNotRunning):
+			return nil, huma.Error409Conflict(err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, huma.Error504GatewayTimeout(err.Error())
+		case errors.Is(err, context.Canceled):
+			return nil, huma.NewError(statusClientClosedRequest, err.Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to run command")
+	}
+
+	return &RunContainerExecOutput{
+		Body: base.ApiResponse[containertypes.ExecRunResult]{
+			Success: true,
+			Data:    result,
+		},
+	}, nil
+}
+
 func (h *ContainerHandler) StartContainer(ctx context.Context, input *ContainerActionInput) (*ContainerActionOutput, error) {
 	return h.runContainerActionInternal(ctx, input, containerActionConfigInternal{
 		ActivityType:    models.ActivityTypeContainerStart,
@@ -612,43 +1245,56 @@ func (h *ContainerHandler) StartContainer(ctx context.Context, input *ContainerA
 			return h.containerService.StartContainer(runtimeCtx, containerID, user)
 		},
 		Error: func(err error) error {
-			return huma.Error500InternalServerError(errors.WithMessage(err, "Failed to start container").Error())
+			return dockerErrorInternal(err, "Failed to start container")
 		},
 	})
 }
 
-func (h *ContainerHandler) StopContainer(ctx context.Context, input *ContainerActionInput) (*ContainerActionOutput, error) {
-	return h.runContainerActionInternal(ctx, input, containerActionConfigInternal{
+func (h *ContainerHandler) StopContainer(ctx context.Context, input *StopContainerInput) (*ContainerActionOutput, error) {
+	opts := input.stopOptionsInternal()
+	return h.runContainerActionInternal(ctx, &ContainerActionInput{EnvironmentID: input.EnvironmentID, ContainerID: input.ContainerID}, containerActionConfigInternal{
 		ActivityType:    models.ActivityTypeContainerStop,
 		Step:            "Stopping container",
 		StartMessage:    "Container stop requested",
 		CompleteMessage: "Container stopped",
 		SuccessMessage:  "Container stopped successfully",
 		Action: func(runtimeCtx context.Context, containerID string, user models.User) error {
-			return h.containerService.StopContainer(runtimeCtx, containerID, user)
+			return h.containerService.StopContainer(runtimeCtx, containerID, opts, user)
 		},
 		Error: func(err error) error {
-			return huma.Error500InternalServerError(errors.WithMessage(err, "Failed to stop container").Error())
+			return dockerErrorInternal(err, "Failed to stop container")
 		},
 	})
 }
 
-func (h *ContainerHandler) RestartContainer(ctx context.Context, input *ContainerActionInput) (*ContainerActionOutput, error) {
-	return h.runContainerActionInternal(ctx, input, containerActionConfigInternal{
+func (h *ContainerHandler) RestartContainer(ctx context.Context, input *StopContainerInput) (*ContainerActionOutput, error) {
+	opts := input.stopOptionsInternal()
+	return h.runContainerActionInternal(ctx, &ContainerActionInput{EnvironmentID: input.EnvironmentID, ContainerID: input.ContainerID}, containerActionConfigInternal{
 		ActivityType:    models.ActivityTypeContainerRestart,
 		Step:            "Restarting container",
 		StartMessage:    "Container restart requested",
 		CompleteMessage: "Container restarted",
 		SuccessMessage:  "Container restarted successfully",
 		Action: func(runtimeCtx context.Context, containerID string, user models.User) error {
-			return h.containerService.RestartContainer(runtimeCtx, containerID, user)
+			return h.containerService.RestartContainer(runtimeCtx, containerID, opts, user)
 		},
 		Error: func(err error) error {
-			return huma.Error500InternalServerError(errors.WithMessage(err, "Failed to restart container").Error())
+			return dockerErrorInternal(err, "Failed to restart container")
 		},
 	})
 }
 
+func (input *StopContainerInput) stopOptionsInternal() services.ContainerStopOptions {
+	if input.Body == nil {
+		return services.ContainerStopOptions{}
+	}
+	opts := services.ContainerStopOptions{Signal: strings.TrimSpace(input.Body.Signal)}
+	if input.Body.Timeout != nil {
+		opts.Timeout = new(*input.Body.Timeout)
+	}
+	return opts
+}
+
 func (h *ContainerHandler) KillContainer(ctx context.Context, input *KillContainerInput) (*ContainerActionOutput, error) {
 	signal := strings.TrimSpace(input.Signal)
 	return h.runContainerActionInternal(ctx, &ContainerActionInput{EnvironmentID: input.EnvironmentID, ContainerID: input.ContainerID}, containerActionConfigInternal{
@@ -661,7 +1307,7 @@ func (h *ContainerHandler) KillContainer(ctx context.Context, input *KillContain
 			return h.containerService.KillContainer(runtimeCtx, containerID, signal, user)
 		},
 		Error: func(err error) error {
-			return huma.Error500InternalServerError(errors.WithMessage(err, "Failed to kill container").Error())
+			return dockerErrorInternal(err, "Failed to kill container")
 		},
 	})
 }
@@ -677,7 +1323,7 @@ func (h *ContainerHandler) PauseContainer(ctx context.Context, input *ContainerA
 			return h.containerService.PauseContainer(runtimeCtx, containerID, user)
 		},
 		Error: func(err error) error {
-			return huma.Error500InternalServerError(errors.WithMessage(err, "Failed to pause container").Error())
+			return dockerErrorInternal(err, "Failed to pause container")
 		},
 	})
 }
@@ -693,7 +1339,7 @@ func (h *ContainerHandler) UnpauseContainer(ctx context.Context, input *Containe
 			return h.containerService.UnpauseContainer(runtimeCtx, containerID, user)
 		},
 		Error: func(err error) error {
-			return huma.Error500InternalServerError(errors.WithMessage(err, "Failed to unpause container").Error())
+			return dockerErrorInternal(err, "Failed to unpause container")
 		},
 	})
 }
@@ -718,6 +1364,12 @@ func (h *ContainerHandler) runContainerActionInternal(ctx context.Context, input
 	activityID, runtimeCtx := activitylib.StartHandlerActivityForUser(runtimeCtx, h.activityService, input.EnvironmentID, cfg.ActivityType, "container", input.ContainerID, input.ContainerID, user, cfg.Step, cfg.StartMessage, models.JSON{"containerID": input.ContainerID})
 	if err := cfg.Action(runtimeCtx, input.ContainerID, *user); err != nil {
 		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, cfg.CompleteMessage, err)
+		switch {
+		case errors.Is(err, common.ErrContainerOperationInProgress):
+			return nil, huma.Error409Conflict(err.Error())
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, cfg.Error(err)
 	}
 	activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, cfg.CompleteMessage, nil)
@@ -742,7 +1394,7 @@ func (h *ContainerHandler) CommitContainer(ctx context.Context, input *CommitCon
 
 	out, err := h.containerService.CommitContainer(ctx, input.ContainerID, input.Body, *user)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(fmt.Sprintf("failed to commit container: %v", err))
+		return nil, dockerErrorInternal(err, "Failed to commit container")
 	}
 
 	return &CommitContainerOutput{
@@ -753,6 +1405,36 @@ func (h *ContainerHandler) CommitContainer(ctx context.Context, input *CommitCon
 	}, nil
 }
 
+// UpdateContainerResources applies a live restart-policy or resource-limit
+// change to a container. It is gated like recreate, which is the alternative
+// way to make the same change.
+func (h *ContainerHandler) UpdateContainerResources(ctx context.Context, input *UpdateContainerResourcesInput) (*UpdateContainerResourcesOutput, error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.containerService.UpdateContainerResources(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrContainerOperationInProgress):
+			return nil, huma.Error409Conflict(err.Error())
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, huma.Error400BadRequest(err.Error())
+		case cerrdefs.IsNotFound(err):
+			return nil, huma.Error404NotFound("Container not found")
+		}
+		return nil, dockerErrorInternal(err, "Failed to update container")
+	}
+
+	return &UpdateContainerResourcesOutput{
+		Body: base.ApiResponse[containertypes.ResourceUpdateResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
 func (h *ContainerHandler) RedeployContainer(ctx context.Context, input *ContainerActionInput) (*GetContainerOutput, error) {
 	user, err := requireUserInternal(ctx)
 	if err != nil {
@@ -768,35 +1450,115 @@ func (h *ContainerHandler) RedeployContainer(ctx context.Context, input *Contain
 	if err != nil {
 		activitylib.FlushWriter(activityWriter)
 		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container redeploy failed", err)
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to redeploy container").Error())
+		return nil, dockerErrorInternal(err, "Failed to redeploy container")
 	}
 	activitylib.FlushWriter(activityWriter)
 	activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container redeployed", nil)
 
-	// Fetch full container details to return (consistent with other endpoints)
-	details, inspectErr := h.containerService.GetContainerDetails(runtimeCtx, newContainerID)
-	if inspectErr == nil {
-		details.ActivityID = mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()
+	return h.replacedContainerOutputInternal(runtimeCtx, newContainerID, activityID), nil
+}
 
-		return &GetContainerOutput{
-			Body: base.ApiResponse[containertypes.Details]{
-				Success: true,
-				Data:    details,
-			},
-		}, nil
+func (h *ContainerHandler) RecreateContainer(ctx context.Context, input *RecreateContainerInput) (*GetContainerOutput, error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides containertypes.RecreateRequest
+	if input.Body != nil {
+		overrides = *input.Body
+	}
+
+	runtimeCtx := utils.ActivityRuntimeContext(ctx, h.appCtx)
+	activityID, runtimeCtx := activitylib.StartQueuedHandlerActivityForUser(runtimeCtx, h.activityService, input.EnvironmentID, models.ActivityTypeContainerRedeploy, "container", input.ContainerID, input.ContainerID, user, "Starting recreate", "Container recreate requested", models.JSON{"containerID": input.ContainerID, "image": overrides.Image})
+	activitylib.AwaitHandlerActivitySlot(runtimeCtx, h.activityService, activityID, input.EnvironmentID)
+	activityWriter := activitylib.NewWriter(runtimeCtx, h.activityService, activityID, io.Discard, "Recreating container")
+	recreateCtx := context.WithValue(runtimeCtx, dockerutils.ProgressWriterKey{}, activityWriter)
+	newContainerID, err := h.containerService.RecreateContainer(recreateCtx, input.ContainerID, overrides, *user)
+	if err != nil {
+		activitylib.FlushWriter(activityWriter)
+		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container recreate failed", err)
+		return nil, dockerErrorInternal(err, "Failed to recreate container")
+	}
+	activitylib.FlushWriter(activityWriter)
+	activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container recreated", nil)
+
+	return h.replacedContainerOutputInternal(runtimeCtx, newContainerID, activityID), nil
+}
+
+// GetContainerEnv lists a container's environment variables. Values of
+// secret-looking names are masked unless a global admin asks to reveal them.
+func (h *ContainerHandler) GetContainerEnv(ctx context.Context, input *GetContainerEnvInput) (*GetContainerEnvOutput, error) {
+	reveal := false
+	if input.Reveal {
+		ps, _ := humamw.PermissionsFromContext(ctx)
+		if !ps.IsGlobalAdmin() {
+			return nil, huma.Error403Forbidden("Only admins can reveal masked environment values")
+		}
+		reveal = true
+	}
+
+	env, err := h.containerService.GetContainerEnv(ctx, input.ContainerID, reveal)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound("Container not found")
+		}
+		return nil, dockerErrorInternal(err, "Failed to get container environment")
 	}
 
-	// Container was redeployed successfully, but we couldn't fetch full details.
-	// Return minimal response with just the ID so frontend can still navigate.
+	return &GetContainerEnvOutput{
+		Body: base.ApiResponse[[]containertypes.EnvVar]{
+			Success: true,
+			Data:    env,
+		},
+	}, nil
+}
+
+// SetContainerEnv recreates a container with the given environment.
+func (h *ContainerHandler) SetContainerEnv(ctx context.Context, input *SetContainerEnvInput) (*GetContainerOutput, error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeCtx := utils.ActivityRuntimeContext(ctx, h.appCtx)
+	activityID, runtimeCtx := activitylib.StartQueuedHandlerActivityForUser(runtimeCtx, h.activityService, input.EnvironmentID, models.ActivityTypeContainerRedeploy, "container", input.ContainerID, input.ContainerID, user, "Starting environment update", "Container environment update requested", models.JSON{"containerID": input.ContainerID, "variables": len(input.Body.Env)})
+	activitylib.AwaitHandlerActivitySlot(runtimeCtx, h.activityService, activityID, input.EnvironmentID)
+	activityWriter := activitylib.NewWriter(runtimeCtx, h.activityService, activityID, io.Discard, "Recreating container")
+	recreateCtx := context.WithValue(runtimeCtx, dockerutils.ProgressWriterKey{}, activityWriter)
+	newContainerID, err := h.containerService.SetContainerEnv(recreateCtx, input.ContainerID, input.Body.Env, *user)
+	if err != nil {
+		activitylib.FlushWriter(activityWriter)
+		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container environment update failed", err)
+		if cerrdefs.IsInvalidArgument(err) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to update container environment")
+	}
+	activitylib.FlushWriter(activityWriter)
+	activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container environment updated", nil)
+
+	return h.replacedContainerOutputInternal(runtimeCtx, newContainerID, activityID), nil
+}
+
+// replacedContainerOutputInternal returns the details of a container created by a
+// redeploy or recreate. If they cannot be fetched, only the new ID is returned so
+// the frontend can still navigate to it.
+func (h *ContainerHandler) replacedContainerOutputInternal(ctx context.Context, newContainerID, activityID string) *GetContainerOutput {
+	activityRef := mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()
+
+	details, err := h.containerService.GetContainerDetails(ctx, newContainerID)
+	if err != nil {
+		details = containertypes.Details{ID: newContainerID}
+	}
+	details.ActivityID = activityRef
+
 	return &GetContainerOutput{
 		Body: base.ApiResponse[containertypes.Details]{
 			Success: true,
-			Data: containertypes.Details{
-				ID:         newContainerID,
-				ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer(),
-			},
+			Data:    details,
 		},
-	}, nil
+	}
 }
 
 func (h *ContainerHandler) DeleteContainer(ctx context.Context, input *DeleteContainerInput) (*DeleteContainerOutput, error) {
@@ -809,7 +1571,10 @@ func (h *ContainerHandler) DeleteContainer(ctx context.Context, input *DeleteCon
 	activityID, runtimeCtx := activitylib.StartHandlerActivityForUser(runtimeCtx, h.activityService, input.EnvironmentID, models.ActivityTypeContainerDelete, "container", input.ContainerID, input.ContainerID, user, "Deleting container", "Container delete requested", models.JSON{"containerID": input.ContainerID, "force": input.Force, "removeVolumes": input.RemoveVolumes})
 	if err := h.containerService.DeleteContainer(runtimeCtx, input.ContainerID, input.Force, input.RemoveVolumes, *user); err != nil {
 		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container deleted", err)
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to delete container").Error())
+		if errors.Is(err, common.ErrContainerOperationInProgress) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to delete container")
 	}
 	activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Container deleted", nil)
 
@@ -845,3 +1610,187 @@ func (h *ContainerHandler) SetAutoUpdate(ctx context.Context, input *SetAutoUpda
 		},
 	}, nil
 }
+
+// BrowseContainerFiles lists one page of a directory inside a container.
+func (h *ContainerHandler) BrowseContainerFiles(ctx context.Context, input *BrowseContainerFilesInput) (*BrowseContainerFilesOutput, error) {
+	entries, total, err := h.containerService.ListContainerDirectory(ctx, input.ContainerID, input.Path, volumetypes.ListDirectoryOptions{
+		Filter: input.Filter,
+		Offset: input.Offset,
+		Limit:  input.Limit,
+	})
+	if err != nil {
+		switch {
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, huma.Error400BadRequest(err.Error())
+		case cerrdefs.IsNotFound(err):
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to browse files").Error())
+		default:
+			return nil, dockerErrorInternal(err, "Failed to browse files")
+		}
+	}
+
+	return &BrowseContainerFilesOutput{
+		TotalCount: total,
+		Body: base.ApiResponse[[]volumetypes.FileEntry]{
+			Success: true,
+			Data:    entries,
+		},
+	}, nil
+}
+
+// PutContainerFile replaces a file inside a container with the request content.
+func (h *ContainerHandler) PutContainerFile(ctx context.Context, input *PutContainerFileInput) (*base.ApiResponse[base.MessageResponse], error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.containerService.PutContainerFileContent(ctx, input.ContainerID, input.Path, []byte(input.Body.Content), h.fileMaxBytes, *user); err != nil {
+		return nil, containerFileWriteErrorInternal(err)
+	}
+
+	return &base.ApiResponse[base.MessageResponse]{
+		Success: true,
+		Data:    base.MessageResponse{Message: "File saved successfully"},
+	}, nil
+}
+
+// UploadContainerFile copies a multipart upload into a directory inside a container.
+func (h *ContainerHandler) UploadContainerFile(ctx context.Context, input *UploadContainerFileInput) (*base.ApiResponse[base.MessageResponse], error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, fileHeader, err := openUploadedFileInternal(input.RawBody)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := h.containerService.UploadContainerFile(ctx, input.ContainerID, input.Path, fileHeader.Filename, file, h.fileMaxBytes, *user); err != nil {
+		return nil, containerFileWriteErrorInternal(err)
+	}
+
+	return &base.ApiResponse[base.MessageResponse]{
+		Success: true,
+		Data:    base.MessageResponse{Message: "File uploaded successfully"},
+	}, nil
+}
+
+func (h *ContainerHandler) batchContainerActionInternal(action string) func(context.Context, *BatchContainerActionInput) (*BatchContainerActionOutput, error) {
+	return func(ctx context.Context, input *BatchContainerActionInput) (*BatchContainerActionOutput, error) {
+		user, err := requireUserInternal(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		runtimeCtx := utils.ActivityRuntimeContext(ctx, h.appCtx)
+		resp, err := h.containerService.BatchAction(runtimeCtx, input.Body.IDs, action, input.Body.Force, *user)
+		if err != nil {
+			if cerrdefs.IsInvalidArgument(err) {
+				return nil, huma.Error400BadRequest(err.Error())
+			}
+			return nil, dockerErrorInternal(err, "Failed to run batch container action")
+		}
+
+		return &BatchContainerActionOutput{
+			Body: base.ApiResponse[containertypes.BatchActionResponse]{
+				Success: true,
+				Data:    resp,
+			},
+		}, nil
+	}
+}
+
+func (h *ContainerHandler) ExportContainerLogs(ctx context.Context, input *ExportContainerLogsInput) (*huma.StreamResponse, error) {
+	logs, name, err := h.containerService.ExportLogs(ctx, input.ContainerID, services.ContainerLogExportOptions{
+		Tail:  input.Tail,
+		Since: input.Since,
+	})
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to export container logs").Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to export container logs")
+	}
+
+	useGzip := acceptsGzipInternal(input.AcceptEncoding)
+
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) {
+			defer func() { _ = logs.Close() }()
+
+			humaCtx.SetHeader("Content-Type", "text/plain; charset=utf-8")
+			humaCtx.SetHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".log"))
+			humaCtx.SetHeader("Vary", "Accept-Encoding")
+
+			writer := humaCtx.BodyWriter()
+			if !useGzip {
+				_, _ = io.Copy(writer, logs)
+				return
+			}
+
+			humaCtx.SetHeader("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(writer)
+			_, _ = io.Copy(gz, logs)
+			_ = gz.Close()
+		},
+	}, nil
+}
+
+func (h *ContainerHandler) SearchContainerLogs(ctx context.Context, input *SearchContainerLogsInput) (*SearchContainerLogsOutput, error) {
+	result, err := h.containerService.SearchLogs(ctx, input.ContainerID, input.Body.Pattern, services.ContainerLogSearchOptions{
+		CaseInsensitive: input.Body.CaseInsensitive,
+		Since:           input.Body.Since,
+		Until:           input.Body.Until,
+		MaxMatches:      input.Body.MaxMatches,
+	})
+	if err != nil {
+		switch {
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, huma.Error400BadRequest(err.Error())
+		case cerrdefs.IsNotFound(err):
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Failed to search container logs").Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to search container logs")
+	}
+
+	return &SearchContainerLogsOutput{
+		Body: base.ApiResponse[containertypes.LogSearchResult]{
+			Success: true,
+			Data:    result,
+		},
+	}, nil
+}
+
+// acceptsGzipInternal reports whether an Accept-Encoding header allows gzip,
+// honouring an explicit q=0 refusal.
+func acceptsGzipInternal(acceptEncoding string) bool {
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(v, 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func containerFileWriteErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrContainerFileTooLarge):
+		return huma.Error413RequestEntityTooLarge(err.Error())
+	case cerrdefs.IsInvalidArgument(err):
+		return huma.Error400BadRequest(err.Error())
+	case cerrdefs.IsNotFound(err):
+		return huma.Error404NotFound(errors.WithMessage(err, "Failed to write file").Error())
+	default:
+		return dockerErrorInternal(err, "Failed to write file")
+	}
+}
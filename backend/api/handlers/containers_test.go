@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	containertypes "github.com/getarcaneapp/arcane/types/v2/container"
+	dockercontainer "github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCreateContainerConfigsWithNetworkAttachments(t *testing.T) {
+	_, hostConfig, networkingConfig, err := buildCreateContainerConfigsInternal(containertypes.Create{
+		Name:  "web",
+		Image: "nginx:alpine",
+		NetworkAttachments: []containertypes.NetworkAttachmentCreate{
+			{Name: "frontend", Aliases: []string{"web", "www"}},
+			{Name: "backend", IPv4Address: "172.20.0.10", IPv6Address: "fd00::10"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, dockercontainer.NetworkMode("frontend"), hostConfig.NetworkMode)
+	require.Len(t, networkingConfig.EndpointsConfig, 2)
+	require.Equal(t, []string{"web", "www"}, networkingConfig.EndpointsConfig["frontend"].Aliases)
+	require.Nil(t, networkingConfig.EndpointsConfig["frontend"].IPAMConfig)
+
+	backend := networkingConfig.EndpointsConfig["backend"]
+	require.NotNil(t, backend.IPAMConfig)
+	require.Equal(t, netip.MustParseAddr("172.20.0.10"), backend.IPAMConfig.IPv4Address)
+	require.Equal(t, netip.MustParseAddr("fd00::10"), backend.IPAMConfig.IPv6Address)
+}
+
+func TestBuildNetworkAttachmentsInternalRejectsInvalidInput(t *testing.T) {
+	tests := map[string][]containertypes.NetworkAttachmentCreate{
+		"missing name":   {{Name: " "}},
+		"duplicate name": {{Name: "frontend"}, {Name: "frontend"}},
+		"ipv6 as ipv4":   {{Name: "frontend", IPv4Address: "fd00::10"}},
+		"ipv4 as ipv6":   {{Name: "frontend", IPv6Address: "172.20.0.10"}},
+		"malformed ip":   {{Name: "frontend", IPv4Address: "not-an-ip"}},
+	}
+	for name, attachments := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := buildNetworkAttachmentsInternal(attachments)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDockerErrorInternalMapsUnavailableDaemonTo503(t *testing.T) {
+	cause := common.Classify(common.ErrDockerUnavailable, errors.New("failed to create Docker client: connection refused"))
+
+	var statusErr huma.StatusError
+	require.ErrorAs(t, dockerErrorInternal(errors.WrapIf(cause, "failed to connect to Docker"), "Failed to list containers"), &statusErr)
+	require.Equal(t, http.StatusServiceUnavailable, statusErr.GetStatus())
+	require.Contains(t, statusErr.Error(), "connection refused")
+
+	require.ErrorAs(t, dockerErrorInternal(errors.New("boom"), "Failed to list containers"), &statusErr)
+	require.Equal(t, http.StatusInternalServerError, statusErr.GetStatus())
+}
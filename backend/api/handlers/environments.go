@@ -18,6 +18,7 @@ import (
 	"emperror.dev/errors"
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
@@ -48,13 +49,21 @@ type EnvironmentHandler struct {
 
 type ListEnvironmentsInput struct {
 	Search string `query:"search" doc:"Search query for filtering by name or API URL"`
-	Sort   string `query:"sort" doc:"Column to sort by"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order  string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start  int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit  int    `query:"limit" default:"20" doc:"Items per page"`
 	Type   string `query:"type" doc:"Filter by environment type (comma-separated: http,edge,websocket,grpc,polling)"`
 }
 
+type ExportEnvironmentsInput struct {
+	Format string `query:"format" default:"csv" enum:"csv" doc:"Export format"`
+	Search string `query:"search" doc:"Search query for filtering by name or API URL"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order  string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
+	Type   string `query:"type" doc:"Filter by environment type (comma-separated: http,edge,websocket,grpc,polling)"`
+}
+
 type ListEnvironmentsOutput struct {
 	Body base.Paginated[environment.Environment]
 }
@@ -98,6 +107,14 @@ type DeleteEnvironmentOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type ReorderEnvironmentsInput struct {
+	Body environment.ReorderRequest
+}
+
+type ReorderEnvironmentsOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
 type TestConnectionInput struct {
 	ID   string                             `path:"id" doc:"Environment ID"`
 	Body *environment.TestConnectionRequest `json:"body,omitempty"`
@@ -107,6 +124,12 @@ type TestConnectionOutput struct {
 	Body base.ApiResponse[environment.Test]
 }
 
+type RefreshEnvironmentsInput struct{}
+
+type RefreshEnvironmentsOutput struct {
+	Body base.ApiResponse[environment.RefreshResult]
+}
+
 type UpdateHeartbeatInput struct {
 	ID string `path:"id" doc:"Environment ID"`
 }
@@ -116,14 +139,31 @@ type UpdateHeartbeatOutput struct {
 }
 
 type PairAgentInput struct {
-	ID   string                        `path:"id" doc:"Environment ID (must be 0 for local)"`
-	Body *environment.AgentPairRequest `json:"body,omitempty"`
+	ID            string                        `path:"id" doc:"Environment ID (must be 0 for local)"`
+	ControllerURL string                        `header:"X-Arcane-Controller-Url" doc:"URL of the controller pairing with the agent"`
+	Body          *environment.AgentPairRequest `json:"body,omitempty"`
 }
 
 type PairAgentOutput struct {
 	Body base.ApiResponse[environment.AgentPairResponse]
 }
 
+type GetAgentInfoInput struct {
+	ID string `path:"id" doc:"Environment ID (must be 0 for local)"`
+}
+
+type GetAgentInfoOutput struct {
+	Body base.ApiResponse[environment.AgentInfo]
+}
+
+type RepairAgentInput struct {
+	ID string `path:"id" doc:"Environment ID"`
+}
+
+type RepairAgentOutput struct {
+	Body base.ApiResponse[environment.Test]
+}
+
 type SyncEnvironmentInput struct {
 	ID string `path:"id" doc:"Environment ID"`
 }
@@ -217,6 +257,18 @@ func RegisterEnvironments(api huma.API, environmentService *services.Environment
 		// Management mutations (create/update/delete) remain global-gated below.
 	}, h.ListEnvironments)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "exportEnvironments",
+		Method:      "GET",
+		Path:        "/environments/export",
+		Summary:     "Export environments",
+		Description: "Download every environment matching the search and filters as CSV",
+		Tags:        []string{"Environments"},
+		Security:    defaultOperationSecurityInternal(),
+		// Gated like listEnvironments: the handler limits the export to the
+		// environments the caller can access.
+	}, h.ExportEnvironments)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "createEnvironment",
 		Method:      "POST",
@@ -228,6 +280,17 @@ func RegisterEnvironments(api huma.API, environmentService *services.Environment
 		Middlewares: humamw.RequirePermission(api, authz.PermEnvironmentsCreate),
 	}, h.CreateEnvironment)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "reorderEnvironments",
+		Method:      "PATCH",
+		Path:        "/environments/reorder",
+		Summary:     "Reorder environments",
+		Description: "Persist the display order of environments from an ordered list of IDs",
+		Tags:        []string{"Environments"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermEnvironmentsUpdate),
+	}, h.ReorderEnvironments)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getEnvironment",
 		Method:      "GET",
@@ -271,6 +334,17 @@ func RegisterEnvironments(api huma.API, environmentService *services.Environment
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermEnvironmentsRead, h.TestConnection)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "refreshEnvironments",
+		Method:      "POST",
+		Path:        "/environments/refresh",
+		Summary:     "Refresh all environment connections",
+		Description: "Test connectivity to every enabled environment and return each status",
+		Tags:        []string{"Environments"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequireAnyEnvironmentPermission(api, authz.PermEnvironmentsRead),
+	}, h.RefreshEnvironments)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "updateHeartbeat",
 		Method:      "POST",
@@ -291,6 +365,26 @@ func RegisterEnvironments(api huma.API, environmentService *services.Environment
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermEnvironmentsPair, h.PairAgent)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "getAgentInfo",
+		Method:      "GET",
+		Path:        "/environments/{id}/agent/info",
+		Summary:     "Get local agent pairing info",
+		Description: "Get the local agent's pairing status, token age, and the controller that last paired with it",
+		Tags:        []string{"Environments"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermEnvironmentsRead, h.GetAgentInfo)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "repairAgent",
+		Method:      "POST",
+		Path:        "/environments/{id}/agent/repair",
+		Summary:     "Repair agent pairing",
+		Description: "Re-pair an environment whose agent rejects its access token, using the stored bootstrap token",
+		Tags:        []string{"Environments"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermEnvironmentsPair, h.RepairAgent)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "syncEnvironment",
 		Method:      "POST",
@@ -406,6 +500,34 @@ func (h *EnvironmentHandler) ListEnvironments(ctx context.Context, input *ListEn
 	}, nil
 }
 
+// ExportEnvironments streams every environment the caller can access that
+// matches the search and filters as CSV.
+func (h *EnvironmentHandler) ExportEnvironments(ctx context.Context, input *ExportEnvironmentsInput) (*huma.StreamResponse, error) {
+	ps, ok := humamw.PermissionsFromContext(ctx)
+	if !ok {
+		return nil, huma.Error403Forbidden("permission denied")
+	}
+	var accessibleEnvIDs []string // nil = no restriction
+	if !environmentListerSeesAllInternal(ps) {
+		accessibleEnvIDs = accessibleEnvironmentIDsInternal(ps)
+	}
+
+	params := buildPaginationParamsInternal(0, -1, input.Sort, input.Order, input.Search)
+	if input.Type != "" {
+		params.Filters["type"] = input.Type
+	}
+
+	envs, _, err := h.environmentService.ListEnvironmentsPaginated(ctx, params, accessibleEnvIDs)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to export environments")
+	}
+	for i := range envs {
+		h.applyEdgeRuntimeStateInternal(&envs[i])
+	}
+
+	return csvExportResponseInternal("environments.csv", envs, h.environmentService.EnvironmentPaginationConfig()), nil
+}
+
 // environmentListerSeesAllInternal reports whether the caller may list every
 // environment. True for sudo callers, global admins, and holders of the
 // org-level environments:list permission (Allows short-circuits on sudo and
@@ -518,6 +640,12 @@ func (h *EnvironmentHandler) createEnvironmentLegacyInternal(ctx context.Context
 		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to create environment").Error())
 	}
 
+	if body.BootstrapToken != nil && *body.BootstrapToken != "" {
+		if err := h.environmentService.SetBootstrapToken(ctx, created.ID, *body.BootstrapToken); err != nil {
+			return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to store bootstrap token").Error())
+		}
+	}
+
 	// Sync registries and git repositories in background (intentionally detached from request context)
 	if created.AccessToken != nil && *created.AccessToken != "" {
 		h.triggerEnvironmentResourceSyncInternal(ctx, created.ID, created.Name, "environment creation")
@@ -582,6 +710,11 @@ func (h *EnvironmentHandler) UpdateEnvironment(ctx context.Context, input *Updat
 	if updateErr != nil {
 		return nil, huma.Error500InternalServerError("Failed to update environment")
 	}
+	if !isLocalEnv && input.Body.BootstrapToken != nil {
+		if err := h.environmentService.SetBootstrapToken(ctx, input.ID, *input.Body.BootstrapToken); err != nil {
+			return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to store bootstrap token").Error())
+		}
+	}
 
 	h.triggerPostUpdateTasksInternal(ctx, input.ID, updated, &input.Body)
 
@@ -651,6 +784,25 @@ func (h *EnvironmentHandler) applyEdgeRuntimeStateInternal(env *environment.Envi
 	services.ApplyEnvironmentRuntimeState(env)
 }
 
+// ReorderEnvironments persists the display order of environments.
+func (h *EnvironmentHandler) ReorderEnvironments(ctx context.Context, input *ReorderEnvironmentsInput) (*ReorderEnvironmentsOutput, error) {
+	if err := h.environmentService.ReorderEnvironments(ctx, input.Body.IDs); err != nil {
+		if errors.Is(err, common.ErrEnvironmentOrderInvalid) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to reorder environments").Error())
+	}
+
+	return &ReorderEnvironmentsOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Environments reordered successfully",
+			},
+		},
+	}, nil
+}
+
 // DeleteEnvironment deletes an environment.
 func (h *EnvironmentHandler) DeleteEnvironment(ctx context.Context, input *DeleteEnvironmentInput) (*DeleteEnvironmentOutput, error) {
 	if input.ID == localDockerEnvironmentID {
@@ -684,8 +836,7 @@ func (h *EnvironmentHandler) TestConnection(ctx context.Context, input *TestConn
 		apiUrl = input.Body.ApiUrl
 	}
 
-	status, err := h.environmentService.TestConnection(ctx, input.ID, apiUrl)
-	resp := environment.Test{Status: status}
+	resp, err := h.environmentService.TestConnection(ctx, input.ID, apiUrl)
 	if err != nil {
 		resp.Message = new(err.Error())
 		return &TestConnectionOutput{
@@ -704,6 +855,29 @@ func (h *EnvironmentHandler) TestConnection(ctx context.Context, input *TestConn
 	}, nil
 }
 
+// RefreshEnvironments tests every enabled environment the caller may read and
+// returns their statuses.
+func (h *EnvironmentHandler) RefreshEnvironments(ctx context.Context, _ *RefreshEnvironmentsInput) (*RefreshEnvironmentsOutput, error) {
+	ps, ok := humamw.PermissionsFromContext(ctx)
+	if !ok {
+		return nil, huma.Error403Forbidden("permission denied")
+	}
+
+	statuses, err := h.environmentService.RefreshAllConnections(ctx, func(id string) bool {
+		return ps.Allows(authz.PermEnvironmentsRead, id)
+	})
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to refresh environments")
+	}
+
+	return &RefreshEnvironmentsOutput{
+		Body: base.ApiResponse[environment.RefreshResult]{
+			Success: true,
+			Data:    environment.RefreshResult{Statuses: statuses},
+		},
+	}, nil
+}
+
 // UpdateHeartbeat updates the heartbeat for an environment.
 func (h *EnvironmentHandler) UpdateHeartbeat(ctx context.Context, input *UpdateHeartbeatInput) (*UpdateHeartbeatOutput, error) {
 	if err := h.environmentService.UpdateEnvironmentHeartbeat(ctx, input.ID); err != nil {
@@ -727,7 +901,8 @@ func (h *EnvironmentHandler) PairAgent(ctx context.Context, input *PairAgentInpu
 	}
 
 	shouldRotate := input.Body != nil && input.Body.Rotate != nil && *input.Body.Rotate
-	if h.cfg.AgentToken == "" || shouldRotate {
+	issued := h.cfg.AgentToken == "" || shouldRotate
+	if issued {
 		h.cfg.AgentToken = utils.GenerateRandomString(48)
 	}
 
@@ -735,6 +910,19 @@ func (h *EnvironmentHandler) PairAgent(ctx context.Context, input *PairAgentInpu
 		return nil, huma.Error500InternalServerError("Failed to persist agent token")
 	}
 
+	now := time.Now()
+	if issued {
+		if err := h.settingsService.RecordAgentTokenIssued(ctx, now); err != nil {
+			slog.WarnContext(ctx, "Failed to record agent token creation time", "error", err)
+		}
+	}
+	// Controllers identify themselves when pairing; local token rotation does not.
+	if controllerURL := strings.TrimSpace(input.ControllerURL); controllerURL != "" {
+		if err := h.settingsService.RecordAgentPairing(ctx, controllerURL, now); err != nil {
+			slog.WarnContext(ctx, "Failed to record agent pairing", "controllerURL", controllerURL, "error", err)
+		}
+	}
+
 	return &PairAgentOutput{
 		Body: base.ApiResponse[environment.AgentPairResponse]{
 			Success: true,
@@ -745,6 +933,21 @@ func (h *EnvironmentHandler) PairAgent(ctx context.Context, input *PairAgentInpu
 	}, nil
 }
 
+// GetAgentInfo returns the local agent's pairing status, token age, and the
+// controller that last paired with it.
+func (h *EnvironmentHandler) GetAgentInfo(ctx context.Context, input *GetAgentInfoInput) (*GetAgentInfoOutput, error) {
+	if input.ID != localDockerEnvironmentID {
+		return nil, huma.Error404NotFound("Not found")
+	}
+
+	return &GetAgentInfoOutput{
+		Body: base.ApiResponse[environment.AgentInfo]{
+			Success: true,
+			Data:    h.settingsService.GetAgentInfo(ctx, h.cfg.AgentToken != ""),
+		},
+	}, nil
+}
+
 // SyncEnvironment syncs container registries and git repositories to an environment.
 func (h *EnvironmentHandler) SyncEnvironment(ctx context.Context, input *SyncEnvironmentInput) (*SyncEnvironmentOutput, error) {
 	// Sync registries
@@ -786,6 +989,9 @@ func (h *EnvironmentHandler) buildUpdateMapInternal(req *environment.Update, isL
 	if req.Name != nil {
 		updates["name"] = *req.Name
 	}
+	if req.Pinned != nil {
+		updates["pinned"] = *req.Pinned
+	}
 
 	return updates
 }
@@ -806,10 +1012,10 @@ func (h *EnvironmentHandler) triggerPostUpdateTasksInternal(ctx context.Context,
 	if updated.Enabled {
 		detachedCtx := context.WithoutCancel(ctx)
 		go func(syncCtx context.Context, envID string, envName string) {
-			status, err := h.environmentService.TestConnection(syncCtx, envID, nil)
+			result, err := h.environmentService.TestConnection(syncCtx, envID, nil)
 			if err != nil {
 				slog.WarnContext(syncCtx, "Failed to test connection after environment update",
-					"environment_id", envID, "environment_name", envName, "status", status, "error", err)
+					"environment_id", envID, "environment_name", envName, "status", result.Status, "error", err)
 			}
 		}(detachedCtx, environmentID, updated.Name)
 	}
@@ -847,6 +1053,39 @@ func (h *EnvironmentHandler) triggerEnvironmentResourceSyncInternal(ctx context.
 	}(detachedCtx, environmentID, environmentName, reason)
 }
 
+// RepairAgent re-pairs an environment whose agent rejects its access token.
+func (h *EnvironmentHandler) RepairAgent(ctx context.Context, input *RepairAgentInput) (*RepairAgentOutput, error) {
+	if _, err := h.environmentService.GetEnvironmentByID(ctx, input.ID); err != nil {
+		return nil, huma.Error404NotFound("Environment not found")
+	}
+
+	user, _ := humamw.GetCurrentUserFromContext(ctx)
+	var userID, username *string
+	if user != nil {
+		userID = new(user.ID)
+		username = new(user.Username)
+	}
+
+	result, err := h.environmentService.RepairAgentToken(ctx, input.ID, userID, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrEnvironmentRepairUnsupported), errors.Is(err, common.ErrEnvironmentBootstrapTokenMissing):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, common.ErrEnvironmentAuthFailed):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to repair agent pairing").Error())
+		}
+	}
+
+	return &RepairAgentOutput{
+		Body: base.ApiResponse[environment.Test]{
+			Success: true,
+			Data:    result,
+		},
+	}, nil
+}
+
 // PairEnvironment handles agent pairing callback with API key.
 func (h *EnvironmentHandler) PairEnvironment(ctx context.Context, input *PairEnvironmentInput) (*PairEnvironmentOutput, error) {
 	if input.XAPIKey == "" {
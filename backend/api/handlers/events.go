@@ -3,9 +3,11 @@ package handlers
 import (
 	"context"
 	json "encoding/json/v2"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/danielgtaylor/huma/v2"
@@ -13,6 +15,7 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	pkgutils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/event"
@@ -24,24 +27,49 @@ type EventHandler struct {
 	eventService *services.EventService
 }
 
+// maxEventExportRows caps how many events one CSV export returns, so an
+// unfiltered export of a long event history cannot exhaust memory. Narrow the
+// filters or time range to export older events.
+const maxEventExportRows = 50000
+
 // ============================================================================
 // Input/Output Types
 // ============================================================================
 
+// EventFilterQuery holds the event filters shared by the list and export endpoints.
+type EventFilterQuery struct {
+	Severity      string `query:"severity" doc:"Filter by severity"`
+	Type          string `query:"type" doc:"Filter by event type (exact type or category prefix, comma-separated)"`
+	ResourceType  string `query:"resourceType" doc:"Filter by resource type (comma-separated)"`
+	ResourceID    string `query:"resourceId" doc:"Filter by resource ID (comma-separated)"`
+	UserID        string `query:"userId" doc:"Filter by acting user ID (comma-separated)"`
+	Username      string `query:"username" doc:"Filter by acting username (comma-separated)"`
+	EnvironmentID string `query:"environmentId" doc:"Filter by environment ID (comma-separated)"`
+	From          string `query:"from" doc:"Only events at or after this time: RFC 3339, a date (2006-01-02), or a relative age such as 12h or 7d"`
+	To            string `query:"to" doc:"Only events at or before this time, in the same formats as from"`
+}
+
 type ListEventsInput struct {
-	Search   string `query:"search" doc:"Search query"`
-	Sort     string `query:"sort" doc:"Column to sort by"`
-	Order    string `query:"order" default:"asc" doc:"Sort direction"`
-	Start    int    `query:"start" default:"0" doc:"Start index"`
-	Limit    int    `query:"limit" default:"20" doc:"Limit"`
-	Severity string `query:"severity" doc:"Filter by severity"`
-	Type     string `query:"type" doc:"Filter by event type (exact type or category prefix, comma-separated)"`
+	Search string `query:"search" doc:"Search query"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order  string `query:"order" default:"asc" doc:"Sort direction"`
+	Start  int    `query:"start" default:"0" doc:"Start index"`
+	Limit  int    `query:"limit" default:"20" doc:"Limit"`
+	EventFilterQuery
 }
 
 type ListEventsOutput struct {
 	Body base.Paginated[event.Event]
 }
 
+type ExportEventsInput struct {
+	Format string `query:"format" default:"csv" enum:"csv" doc:"Export format"`
+	Search string `query:"search" doc:"Search query"`
+	Sort   string `query:"sort" default:"timestamp" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order  string `query:"order" default:"desc" doc:"Sort direction"`
+	EventFilterQuery
+}
+
 type GetEventStatsInput struct{}
 
 type GetEventStatsOutput struct {
@@ -51,7 +79,7 @@ type GetEventStatsOutput struct {
 type GetEventsByEnvironmentInput struct {
 	EnvironmentID string `path:"environmentId" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction"`
 	Start         int    `query:"start" default:"0" doc:"Start index"`
 	Limit         int    `query:"limit" default:"20" doc:"Limit"`
@@ -153,6 +181,17 @@ func RegisterEvents(api huma.API, eventService *services.EventService) {
 		Middlewares: humamw.RequirePermission(api, authz.PermEventsRead),
 	}, h.ListEvents)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "exportEvents",
+		Method:      "GET",
+		Path:        "/events/export",
+		Summary:     "Export events",
+		Description: fmt.Sprintf("Download the events matching the search and filters as CSV, at most %d rows. The X-Truncated header is true when more events matched", maxEventExportRows),
+		Tags:        []string{"Events"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermEventsRead),
+	}, h.ExportEvents)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getEventStats",
 		Method:      "GET",
@@ -194,15 +233,12 @@ func RegisterEvents(api huma.API, eventService *services.EventService) {
 // ListEvents returns a paginated list of events.
 func (h *EventHandler) ListEvents(ctx context.Context, input *ListEventsInput) (*ListEventsOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-
-	if input.Severity != "" {
-		params.Filters["severity"] = input.Severity
-	}
-	if input.Type != "" {
-		params.Filters["type"] = input.Type
+	filter, err := buildEventQueryFilterInternal(input.EventFilterQuery)
+	if err != nil {
+		return nil, err
 	}
 
-	events, paginationResp, err := h.eventService.ListEventsPaginated(ctx, params)
+	events, paginationResp, err := h.eventService.QueryEvents(ctx, filter, params)
 	if err != nil {
 		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to list events").Error())
 	}
@@ -216,6 +252,32 @@ func (h *EventHandler) ListEvents(ctx context.Context, input *ListEventsInput) (
 	}, nil
 }
 
+// ExportEvents streams the events matching the search and filters as CSV,
+// newest first unless another sort is requested, up to maxEventExportRows.
+// When more events match, the response carries X-Truncated: true.
+func (h *EventHandler) ExportEvents(ctx context.Context, input *ExportEventsInput) (*huma.StreamResponse, error) {
+	params := buildPaginationParamsInternal(0, maxEventExportRows, input.Sort, input.Order, input.Search)
+	filter, err := buildEventQueryFilterInternal(input.EventFilterQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	events, paginationResp, err := h.eventService.QueryEvents(ctx, filter, params)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to export events").Error())
+	}
+
+	resp := csvExportResponseInternal("events.csv", events, services.EventCSVConfig())
+	if paginationResp.TotalItems > int64(len(events)) {
+		writeBody := resp.Body
+		resp.Body = func(humaCtx huma.Context) {
+			humaCtx.SetHeader("X-Truncated", "true")
+			writeBody(humaCtx)
+		}
+	}
+	return resp, nil
+}
+
 // GetEventStats returns global event counts grouped by severity.
 func (h *EventHandler) GetEventStats(ctx context.Context, _ *GetEventStatsInput) (*GetEventStatsOutput, error) {
 	counts, err := h.eventService.GetEventSeverityCounts(ctx)
@@ -238,15 +300,13 @@ func (h *EventHandler) GetEventsByEnvironment(ctx context.Context, input *GetEve
 	}
 
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-
-	if input.Severity != "" {
-		params.Filters["severity"] = input.Severity
-	}
-	if input.Type != "" {
-		params.Filters["type"] = input.Type
+	filter := services.EventQueryFilter{
+		Severity:      input.Severity,
+		Type:          input.Type,
+		EnvironmentID: input.EnvironmentID,
 	}
 
-	events, paginationResp, err := h.eventService.GetEventsByEnvironmentPaginated(ctx, input.EnvironmentID, params)
+	events, paginationResp, err := h.eventService.QueryEvents(ctx, filter, params)
 	if err != nil {
 		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to list events").Error())
 	}
@@ -279,3 +339,40 @@ func (h *EventHandler) DeleteEvent(ctx context.Context, input *DeleteEventInput)
 		},
 	}, nil
 }
+
+// buildEventQueryFilterInternal converts the shared event query filters into a
+// service filter, parsing the from/to bounds.
+func buildEventQueryFilterInternal(query EventFilterQuery) (services.EventQueryFilter, error) {
+	filter := services.EventQueryFilter{
+		Severity:      query.Severity,
+		Type:          query.Type,
+		ResourceType:  query.ResourceType,
+		ResourceID:    query.ResourceID,
+		UserID:        query.UserID,
+		Username:      query.Username,
+		EnvironmentID: query.EnvironmentID,
+	}
+	var err error
+	if filter.From, err = parseEventTimeBoundInternal("from", query.From); err != nil {
+		return services.EventQueryFilter{}, err
+	}
+	if filter.To, err = parseEventTimeBoundInternal("to", query.To); err != nil {
+		return services.EventQueryFilter{}, err
+	}
+	return filter, nil
+}
+
+// parseEventTimeBoundInternal parses a from/to query value with the same
+// syntax as range filters (RFC 3339, a date, or a relative age such as 7d).
+// An empty value leaves the bound open; an unparseable one is a 400.
+func parseEventTimeBoundInternal(name, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := pagination.ParseRangeTime(value, time.Now())
+	if err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid %s: %v", name, err))
+	}
+	return &t, nil
+}
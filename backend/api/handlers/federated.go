@@ -28,7 +28,7 @@ type federatedTokenExchangeError struct {
 
 type ListFederatedCredentialsInput struct {
 	Search string `query:"search" doc:"Search query for filtering by name, issuer, or subject"`
-	Sort   string `query:"sort" doc:"Column to sort by"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order  string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start  int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit  int    `query:"limit" default:"20" doc:"Number of items per page"`
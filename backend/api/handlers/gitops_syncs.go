@@ -25,7 +25,7 @@ type GitOpsSyncHandler struct {
 type ListGitOpsSyncsInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction"`
 	Start         int    `query:"start" default:"0" doc:"Start index"`
 	Limit         int    `query:"limit" default:"20" doc:"Items per page"`
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/danielgtaylor/huma/v2"
+	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/types/v2/system"
 )
 
@@ -24,11 +26,12 @@ func RegisterHealth(api huma.API) {
 		Tags:        []string{"Health"},
 		Security:    []map[string][]string{},
 	}, func(ctx context.Context, input *struct{}) (*HealthOutput, error) {
-		return &HealthOutput{
-			Body: system.HealthResponse{
-				Status: "UP",
-			},
-		}, nil
+		out := &HealthOutput{Body: system.HealthResponse{Status: "UP"}}
+		// The route is public; only authenticated callers learn the version.
+		if _, ok := humamw.GetCurrentUserFromContext(ctx); ok {
+			out.Body.Version = config.Version
+		}
+		return out, nil
 	})
 
 	huma.Register(api, huma.Operation{
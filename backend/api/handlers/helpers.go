@@ -4,6 +4,8 @@ import (
 	"context"
 	json "encoding/json/v2"
 	stderrors "errors"
+	"fmt"
+	"log/slog"
 	"mime/multipart"
 	"strings"
 
@@ -11,12 +13,14 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/remenv"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/mapper"
 	"github.com/getarcaneapp/arcane/types/v2/base"
+	"github.com/moby/moby/client"
 )
 
 // ActivityAppContext carries the app lifecycle context through handler registration.
@@ -50,10 +54,7 @@ func buildPaginationParamsInternal(start, limit int, sortCol, sortDir, search st
 		SearchQuery: pagination.SearchQuery{
 			Search: strings.TrimSpace(search),
 		},
-		SortParams: pagination.SortParams{
-			Sort:  strings.TrimSpace(sortCol),
-			Order: pagination.SortOrder(sortDir),
-		},
+		SortParams: pagination.ParseSortParams(sortCol, sortDir),
 		Params: pagination.Params{
 			Start: start,
 			Limit: limit,
@@ -62,6 +63,35 @@ func buildPaginationParamsInternal(start, limit int, sortCol, sortDir, search st
 	}
 }
 
+// parseRangeFiltersInternal parses the filter query parameter and checks it
+// against config's RangeBindings, returning 400 Bad Request naming the
+// offending expression or key.
+func parseRangeFiltersInternal[T any](raw string, config pagination.Config[T]) ([]pagination.RangeFilter, error) {
+	filters, err := pagination.ParseRangeFilters(raw)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err := pagination.ValidateRangeFilters(filters, config.RangeBindings); err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	return filters, nil
+}
+
+// csvExportResponseInternal streams items as a CSV attachment, one column per
+// SearchAccessor in config. Callers load items with pagination disabled so the
+// export covers every match of the active search and filters.
+func csvExportResponseInternal[T any](fileName string, items []T, config pagination.Config[T]) *huma.StreamResponse {
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) { //nolint:contextcheck // streaming work must use humaCtx.Context()
+			humaCtx.SetHeader("Content-Type", "text/csv; charset=utf-8")
+			humaCtx.SetHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+			if err := pagination.WriteCSV(humaCtx.BodyWriter(), items, config); err != nil {
+				slog.WarnContext(humaCtx.Context(), "Failed to write CSV export", "file", fileName, "error", err)
+			}
+		},
+	}
+}
+
 // toPaginationResponseInternal converts the pagination package's response into the API response shape.
 func toPaginationResponseInternal(p pagination.Response) base.PaginationResponse {
 	return base.PaginationResponse{
@@ -82,6 +112,48 @@ func requireUserInternal(ctx context.Context) (*models.User, error) {
 	return user, nil
 }
 
+// dockerUnavailableErrorInternal returns a 503 error when err shows the Docker
+// daemon cannot be reached, either because the client could not be created or
+// because a request on the cached client failed to connect. It returns nil for
+// any other error.
+func dockerUnavailableErrorInternal(err error) error {
+	if err == nil || (!errors.Is(err, common.ErrDockerUnavailable) && !client.IsErrConnectionFailed(err)) {
+		return nil
+	}
+	return huma.Error503ServiceUnavailable(errors.WithMessage(err, "Docker daemon is unavailable; check that Docker is running and DOCKER_HOST is reachable").Error())
+}
+
+// statusClientClosedRequest is the non-standard status (popularized by nginx)
+// reported when the client went away before the operation finished. The
+// response is never read, but it keeps such requests out of the 5xx counts.
+const statusClientClosedRequest = 499
+
+// dockerErrorInternal maps a failed Docker-backed operation to a 503 when the
+// daemon is unreachable and to a 500 prefixed with message otherwise.
+func dockerErrorInternal(err error, message string) error {
+	if apiErr := dockerUnavailableErrorInternal(err); apiErr != nil {
+		return apiErr
+	}
+	return huma.Error500InternalServerError(errors.WithMessage(err, message).Error())
+}
+
+// validationErrorInternal maps a common.ErrValidation error to a 400 and, when
+// the service attached a "field" detail, reports it as the body location so
+// clients can highlight the offending input.
+func validationErrorInternal(err error, message string) error {
+	apiErr := models.ToAPIError(err)
+	detail := errors.WithMessage(err, message).Error()
+	if details, ok := apiErr.Details.(map[string]any); ok {
+		if field, ok := details["field"].(string); ok && field != "" {
+			return huma.NewError(apiErr.HTTPStatus(), detail, &huma.ErrorDetail{
+				Message:  err.Error(),
+				Location: "body." + field,
+			})
+		}
+	}
+	return huma.NewError(apiErr.HTTPStatus(), detail)
+}
+
 func openUploadedFileInternal(form multipart.Form) (multipart.File, *multipart.FileHeader, error) {
 	files := form.File["file"]
 	if len(files) == 0 {
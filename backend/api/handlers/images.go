@@ -43,12 +43,13 @@ type ImageHandler struct {
 type ListImagesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
 	InUse         string `query:"inUse" doc:"Filter by in-use status (true/false)"`
 	Updates       string `query:"updates" doc:"Filter by update availability (true/false)"`
+	Filter        string `query:"filter" doc:"Comma-separated range filters on size or created, e.g. size:>=100000000,created:>7d"`
 }
 
 type ListImagesOutput struct {
@@ -132,7 +133,7 @@ type BuildImageInput struct {
 type ListImageBuildsInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"desc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -245,6 +246,7 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 		Description: "Add a repository tag to an image",
 		Tags:        []string{"Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImagesTag, h.TagImage)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -285,6 +287,7 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 		Description: "Remove a Docker image by ID",
 		Tags:        []string{"Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImagesDelete, h.RemoveImage)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -295,6 +298,7 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 		Description: "Pull a Docker image from a registry with streaming progress output",
 		Tags:        []string{"Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImagesPull, h.PullImage)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -305,6 +309,7 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 		Description: "Build a Docker image using BuildKit with streaming progress output",
 		Tags:        []string{"Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImagesBuild, h.BuildImage)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -335,6 +340,7 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 		Description: "Remove unused Docker images",
 		Tags:        []string{"Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImagesPrune, h.PruneImages)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -345,6 +351,7 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 		Description: "Upload a Docker image from a tar archive",
 		Tags:        []string{"Images"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 		RequestBody: &huma.RequestBody{
 			Content: map[string]*huma.MediaType{
 				"multipart/form-data": {
@@ -374,6 +381,11 @@ func (h *ImageHandler) ListImages(ctx context.Context, input *ListImagesInput) (
 	if input.Updates != "" {
 		params.Filters["updates"] = input.Updates
 	}
+	rangeFilters, err := parseRangeFiltersInternal(input.Filter, h.imageService.ImagePaginationConfig())
+	if err != nil {
+		return nil, err
+	}
+	params.RangeFilters = rangeFilters
 
 	if params.Limit == 0 {
 		params.Limit = 20
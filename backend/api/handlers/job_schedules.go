@@ -90,6 +90,7 @@ func RegisterJobSchedules(api huma.API, jobSvc *services.JobService, envSvc *ser
 		Description: "Manually trigger a background job to run immediately",
 		Tags:        []string{"JobSchedules"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermJobsManage, h.RunJob)
 }
 
@@ -9,8 +9,10 @@ import (
 	"strings"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
@@ -33,7 +35,7 @@ type NetworkHandler struct {
 type ListNetworksInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -72,6 +74,15 @@ type GetNetworkOutput struct {
 	Body base.ApiResponse[networktypes.Inspect]
 }
 
+type GetNetworkUsageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	NetworkID     string `path:"networkId" doc:"Network ID"`
+}
+
+type GetNetworkUsageOutput struct {
+	Body base.ApiResponse[networktypes.Usage]
+}
+
 type GetNetworkTopologyInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 }
@@ -131,6 +142,7 @@ func RegisterNetworks(api huma.API, networkSvc *services.NetworkService, dockerS
 		Summary:     "Create network",
 		Tags:        []string{"Networks"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermNetworksCreate, h.CreateNetwork)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -151,6 +163,15 @@ func RegisterNetworks(api huma.API, networkSvc *services.NetworkService, dockerS
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermNetworksRead, h.GetNetwork)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-network-usage",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/networks/{networkId}/usage",
+		Summary:     "List containers and swarm services using a network",
+		Tags:        []string{"Networks"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermNetworksRead, h.GetNetworkUsage)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "delete-network",
 		Method:      http.MethodDelete,
@@ -158,6 +179,7 @@ func RegisterNetworks(api huma.API, networkSvc *services.NetworkService, dockerS
 		Summary:     "Delete network",
 		Tags:        []string{"Networks"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermNetworksDelete, h.DeleteNetwork)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -167,6 +189,7 @@ func RegisterNetworks(api huma.API, networkSvc *services.NetworkService, dockerS
 		Summary:     "Prune networks",
 		Tags:        []string{"Networks"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermNetworksPrune, h.PruneNetworks)
 }
 
@@ -215,9 +238,6 @@ func (h *NetworkHandler) CreateNetwork(ctx context.Context, input *CreateNetwork
 		return nil, err
 	}
 
-	// Convert to Docker SDK options
-	dockerOptions := input.Body.Options.ToDockerCreateOptions()
-
 	var response *dockernetwork.CreateResponse
 	runtimeCtx := utils.ActivityRuntimeContext(ctx, h.appCtx)
 	activityID, err := activitylib.RunHandlerActivity(runtimeCtx, h.activityService, activitylib.HandlerOptions{
@@ -236,11 +256,14 @@ func (h *NetworkHandler) CreateNetwork(ctx context.Context, input *CreateNetwork
 		},
 	}, func(runtimeCtx context.Context) error {
 		var createErr error
-		response, createErr = h.networkService.CreateNetwork(runtimeCtx, input.Body.Name, dockerOptions, *user)
+		response, createErr = h.networkService.CreateNetwork(runtimeCtx, input.Body, *user)
 		return createErr
 	})
+	if errors.Is(err, common.ErrValidation) {
+		return nil, validationErrorInternal(err, "Invalid network configuration")
+	}
 	if err != nil {
-		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to create network").Error())
+		return nil, dockerErrorInternal(err, "Failed to create network")
 	}
 
 	out, err := mapper.MapOne[dockernetwork.CreateResponse, networktypes.CreateResponse](*response)
@@ -276,22 +299,7 @@ func (h *NetworkHandler) GetNetwork(ctx context.Context, input *GetNetworkInput)
 	// Populate ContainersList
 	out.ContainersList = make([]networktypes.ContainerEndpoint, 0, len(out.Containers))
 	for id, container := range out.Containers {
-		ipv4Address := ""
-		if container.IPv4Address.IsValid() {
-			ipv4Address = container.IPv4Address.String()
-		}
-		ipv6Address := ""
-		if container.IPv6Address.IsValid() {
-			ipv6Address = container.IPv6Address.String()
-		}
-		out.ContainersList = append(out.ContainersList, networktypes.ContainerEndpoint{
-			ID:          id,
-			Name:        container.Name,
-			EndpointID:  container.EndpointID,
-			IPv4Address: ipv4Address,
-			IPv6Address: ipv6Address,
-			MacAddress:  container.MacAddress.String(),
-		})
+		out.ContainersList = append(out.ContainersList, networktypes.NewContainerEndpoint(id, container))
 	}
 
 	// Sort ContainersList
@@ -359,6 +367,23 @@ func (h *NetworkHandler) GetNetworkTopology(ctx context.Context, input *GetNetwo
 	}, nil
 }
 
+func (h *NetworkHandler) GetNetworkUsage(ctx context.Context, input *GetNetworkUsageInput) (*GetNetworkUsageOutput, error) {
+	usage, err := h.networkService.GetNetworkUsage(ctx, input.NetworkID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Network not found").Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to get network usage")
+	}
+
+	return &GetNetworkUsageOutput{
+		Body: base.ApiResponse[networktypes.Usage]{
+			Success: true,
+			Data:    *usage,
+		},
+	}, nil
+}
+
 func (h *NetworkHandler) DeleteNetwork(ctx context.Context, input *DeleteNetworkInput) (*DeleteNetworkOutput, error) {
 	user, err := requireUserInternal(ctx)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
@@ -67,6 +68,31 @@ type TestNotificationOutput struct {
 	Body base.ApiResponse[notification.TestResponse]
 }
 
+type TestNotificationProviderInput struct {
+	Provider string `path:"provider" doc:"Provider"`
+}
+
+type TestNotificationProviderOutput struct {
+	Body base.ApiResponse[notification.ProviderTestResult]
+}
+
+type ListNotificationLogsInput struct {
+	Search        string `query:"search" doc:"Search the rendered title and subject"`
+	Sort          string `query:"sort" default:"timestamp" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order         string `query:"order" default:"desc" doc:"Sort direction"`
+	Start         int    `query:"start" default:"0" doc:"Start index"`
+	Limit         int    `query:"limit" default:"20" doc:"Limit"`
+	Provider      string `query:"provider" doc:"Filter by provider (comma-separated)"`
+	Status        string `query:"status" doc:"Filter by delivery status: success, failed or skipped (comma-separated)"`
+	EnvironmentID string `query:"environmentId" doc:"Filter by environment ID (comma-separated)"`
+	From          string `query:"from" doc:"Only attempts at or after this time: RFC 3339, a date (2006-01-02), or a relative age such as 12h or 7d"`
+	To            string `query:"to" doc:"Only attempts at or before this time, in the same formats as from"`
+}
+
+type ListNotificationLogsOutput struct {
+	Body base.Paginated[notification.LogEntry]
+}
+
 type DispatchNotificationInput struct {
 	APIKey string `header:"X-API-Key" doc:"Remote environment access token"`
 	Body   notification.DispatchRequest
@@ -150,6 +176,30 @@ func RegisterNotifications(api huma.API, notificationSvc *services.NotificationS
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermNotificationsManage, h.TestNotification)
 
+	// Notification settings are global, so previewing a provider requires the
+	// permission at global scope.
+	huma.Register(api, huma.Operation{
+		OperationID: "test-notification-provider",
+		Method:      http.MethodPost,
+		Path:        "/notifications/{provider}/test",
+		Summary:     "Test a notification provider",
+		Description: "Send a sample notification through one provider and return the rendered message",
+		Tags:        []string{"Notifications"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermNotificationsManage),
+	}, h.TestNotificationProvider)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-notification-logs",
+		Method:      http.MethodGet,
+		Path:        "/notifications/logs",
+		Summary:     "List notification logs",
+		Description: "Get a paginated history of notification delivery attempts with their status and error",
+		Tags:        []string{"Notifications"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermNotificationsManage),
+	}, h.ListNotificationLogs)
+
 	// Environment tokens are authenticated by ApiKeyAuth and revalidated by the
 	// handler. RBAC middleware cannot scope this route because it has no environment ID.
 	huma.Register(api, huma.Operation{
@@ -227,6 +277,9 @@ func (h *NotificationHandler) CreateOrUpdateNotificationSettings(ctx context.Con
 		input.Body.Enabled,
 		models.JSON(input.Body.Config),
 	)
+	if errors.Is(err, common.ErrNotificationMessageTemplateInvalid) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 	if err != nil {
 		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to update notification settings").Error())
 	}
@@ -285,6 +338,60 @@ func (h *NotificationHandler) TestNotification(ctx context.Context, input *TestN
 	}, nil
 }
 
+func (h *NotificationHandler) TestNotificationProvider(ctx context.Context, input *TestNotificationProviderInput) (*TestNotificationProviderOutput, error) {
+	if err := h.rejectIfAgentModeInternal(); err != nil {
+		return nil, err
+	}
+
+	result, err := h.notificationService.TestProvider(ctx, models.NotificationProvider(input.Provider))
+	if err != nil {
+		return nil, huma.Error400BadRequest(errors.WithMessage(err, "Failed to test notification provider").Error())
+	}
+
+	return &TestNotificationProviderOutput{
+		Body: base.ApiResponse[notification.ProviderTestResult]{
+			Success: true,
+			Data:    result,
+		},
+	}, nil
+}
+
+func (h *NotificationHandler) ListNotificationLogs(ctx context.Context, input *ListNotificationLogsInput) (*ListNotificationLogsOutput, error) {
+	if err := h.rejectIfAgentModeInternal(); err != nil {
+		return nil, err
+	}
+
+	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
+	filter := services.NotificationLogFilter{
+		Provider:      input.Provider,
+		Status:        input.Status,
+		EnvironmentID: input.EnvironmentID,
+	}
+	var err error
+	if filter.From, err = parseEventTimeBoundInternal("from", input.From); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseEventTimeBoundInternal("to", input.To); err != nil {
+		return nil, err
+	}
+
+	logs, paginationResp, err := h.notificationService.ListNotificationLogs(ctx, filter, params)
+	if errors.Is(err, common.ErrValidation) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to list notification logs").Error())
+	}
+
+	return &ListNotificationLogsOutput{
+		Body: base.Paginated[notification.LogEntry]{
+			Success:    true,
+			Data:       logs,
+			Pagination: toPaginationResponseInternal(paginationResp),
+		},
+	}, nil
+}
+
 func (h *NotificationHandler) DispatchNotification(ctx context.Context, input *DispatchNotificationInput) (*DispatchNotificationOutput, error) {
 	if err := h.rejectIfAgentModeInternal(); err != nil {
 		return nil, err
@@ -39,7 +39,7 @@ func setupNotificationHandlerTestService(t *testing.T) (*database.DB, *services.
 	databaseDB := &database.DB{DB: db}
 	envSvc := services.NewEnvironmentService(databaseDB, nil, nil, nil, nil, nil)
 
-	return databaseDB, services.NewNotificationService(databaseDB, &config.Config{}, envSvc, nil)
+	return databaseDB, services.NewNotificationService(databaseDB, &config.Config{}, envSvc, nil, nil)
 }
 
 func TestIsSupportedNotificationTestType(t *testing.T) {
@@ -179,7 +179,7 @@ func TestDispatchNotificationRoute_AuthenticatesEnvironmentAccessToken(t *testin
 		return strings.NewReplacer("/", "_", ".", "_").Replace(t.PkgPath()) + "_" + name
 	})
 	api := humaecho.NewWithGroup(router, router.Group("/api"), humaConfig)
-	api.UseMiddleware(humamw.NewAuthBridge(api, &services.AuthService{}, nil, nil, envSvc, cfg))
+	api.UseMiddleware(humamw.NewAuthBridge(api, &services.AuthService{}, nil, nil, envSvc, nil, cfg))
 	RegisterNotifications(api, svc, cfg)
 
 	payload, err := json.Marshal(notificationdto.DispatchRequest{
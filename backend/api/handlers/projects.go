@@ -40,7 +40,7 @@ type ProjectHandler struct {
 type ListProjectsInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -184,6 +184,19 @@ type UnarchiveProjectOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type GetProjectOrphansOutput struct {
+	Body base.ApiResponse[project.Orphans]
+}
+
+type PruneProjectOrphansInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+}
+
+type PruneProjectOrphansOutput struct {
+	Body base.ApiResponse[project.PruneOrphansResult]
+}
+
 type PullProjectImagesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ProjectID     string `path:"projectId" doc:"Project ID"`
@@ -237,6 +250,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Deploy a Docker Compose project (docker-compose up)",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsDeploy, h.DeployProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -247,6 +261,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Bring down a Docker Compose project (docker-compose down)",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsDown, h.DownProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -257,6 +272,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Create a new Docker Compose project",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsCreate, h.CreateProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -319,6 +335,27 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermProjectsRead, h.GetProjectFile)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-project-orphans",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/projects/{projectId}/orphans",
+		Summary:     "Get project orphans",
+		Description: "List containers and networks labelled with the project that its compose file no longer defines",
+		Tags:        []string{"Projects"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermProjectsRead, h.GetProjectOrphans)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "prune-project-orphans",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/projects/{projectId}/orphans/prune",
+		Summary:     "Prune project orphans",
+		Description: "Remove the project's orphaned containers and networks",
+		Tags:        []string{"Projects"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermProjectsDeploy, h.PruneProjectOrphans)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "redeploy-project",
 		Method:      http.MethodPost,
@@ -327,6 +364,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Redeploy a Docker Compose project (down + up)",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsDeploy, h.RedeployProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -337,6 +375,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Destroy a Docker Compose project and optionally remove files/volumes",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsDelete, h.DestroyProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -347,6 +386,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Update a Docker Compose project configuration",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsUpdate, h.UpdateProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -357,6 +397,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Update an include file within a Docker Compose project",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsUpdate, h.UpdateProjectInclude)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -367,6 +408,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Restart all containers in a Docker Compose project",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsRestart, h.RestartProject)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -377,6 +419,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Pull latest images and recreate the given services (all services when none are specified)",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsUpdate, h.UpdateProjectServices)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -407,6 +450,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Pull all images for a Docker Compose project with streaming progress output",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsDeploy, h.PullProjectImages)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -417,6 +461,7 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService, act
 		Description: "Build Docker Compose services with build directives using BuildKit",
 		Tags:        []string{"Projects"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermProjectsDeploy, h.BuildProjectImages)
 }
 
@@ -1156,6 +1201,52 @@ func (h *ProjectHandler) UnarchiveProject(ctx context.Context, input *UnarchiveP
 	}, nil
 }
 
+// GetProjectOrphans lists resources left behind by earlier revisions of a project's compose file.
+func (h *ProjectHandler) GetProjectOrphans(ctx context.Context, input *GetProjectInput) (*GetProjectOrphansOutput, error) {
+	if input.ProjectID == "" {
+		return nil, huma.Error400BadRequest("Project ID is required")
+	}
+
+	orphans, err := h.projectService.GetProjectOrphans(ctx, input.ProjectID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to get project orphans").Error())
+	}
+
+	return &GetProjectOrphansOutput{
+		Body: base.ApiResponse[project.Orphans]{
+			Success: true,
+			Data:    *orphans,
+		},
+	}, nil
+}
+
+// PruneProjectOrphans removes a project's orphaned containers and networks.
+func (h *ProjectHandler) PruneProjectOrphans(ctx context.Context, input *PruneProjectOrphansInput) (*PruneProjectOrphansOutput, error) {
+	if input.ProjectID == "" {
+		return nil, huma.Error400BadRequest("Project ID is required")
+	}
+
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.projectService.PruneProjectOrphans(ctx, input.ProjectID, *user)
+	if err != nil {
+		if errors.Is(err, common.ErrProjectArchived) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to prune project orphans").Error())
+	}
+
+	return &PruneProjectOrphansOutput{
+		Body: base.ApiResponse[project.PruneOrphansResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
 // PullProjectImages pulls all images for a project with streaming progress.
 func (h *ProjectHandler) PullProjectImages(ctx context.Context, input *PullProjectImagesInput) (*huma.StreamResponse, error) {
 	if input.ProjectID == "" {
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/danielgtaylor/huma/v2"
+	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
+	"github.com/getarcaneapp/arcane/types/v2/base"
+	"github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+// PruneScheduleHandler handles scheduled prune policy endpoints.
+type PruneScheduleHandler struct {
+	scheduleService *services.PruneScheduleService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type ListPruneSchedulesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ListPruneSchedulesOutput struct {
+	Body base.ApiResponse[[]system.PruneSchedule]
+}
+
+type CreatePruneScheduleInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Body          system.CreatePruneScheduleRequest
+}
+
+type PruneScheduleOutput struct {
+	Body base.ApiResponse[system.PruneSchedule]
+}
+
+type GetPruneScheduleInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ScheduleID    string `path:"scheduleId" doc:"Prune schedule ID"`
+}
+
+type UpdatePruneScheduleInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ScheduleID    string `path:"scheduleId" doc:"Prune schedule ID"`
+	Body          system.UpdatePruneScheduleRequest
+}
+
+type DeletePruneScheduleOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+type RunPruneScheduleOutput struct {
+	Body base.ApiResponse[system.PruneScheduleRun]
+}
+
+type ListPruneScheduleRunsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ScheduleID    string `path:"scheduleId" doc:"Prune schedule ID"`
+	Limit         int    `query:"limit" default:"20" minimum:"0" maximum:"100" doc:"Maximum number of runs to return, newest first (0 for all retained runs)"`
+}
+
+type ListPruneScheduleRunsOutput struct {
+	Body base.ApiResponse[[]system.PruneScheduleRun]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterPruneSchedules registers the scheduled prune policy endpoints.
+func RegisterPruneSchedules(api huma.API, scheduleService *services.PruneScheduleService) {
+	h := &PruneScheduleHandler{scheduleService: scheduleService}
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "list-prune-schedules",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/prune/schedules",
+		Summary:     "List prune schedules",
+		Description: "List the scheduled prune policies configured for an environment",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemRead, h.ListSchedules)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "create-prune-schedule",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/system/prune/schedules",
+		Summary:     "Create a prune schedule",
+		Description: "Create a prune policy that runs on a cron schedule",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemPrune, h.CreateSchedule)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-prune-schedule",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/prune/schedules/{scheduleId}",
+		Summary:     "Get a prune schedule",
+		Description: "Get a scheduled prune policy and its last-run summary",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemRead, h.GetSchedule)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "update-prune-schedule",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/system/prune/schedules/{scheduleId}",
+		Summary:     "Update a prune schedule",
+		Description: "Change the name, cron expression, policy or enabled state of a prune schedule",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemPrune, h.UpdateSchedule)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "delete-prune-schedule",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/system/prune/schedules/{scheduleId}",
+		Summary:     "Delete a prune schedule",
+		Description: "Delete a prune schedule and its run history",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemPrune, h.DeleteSchedule)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "run-prune-schedule",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/system/prune/schedules/{scheduleId}/run",
+		Summary:     "Run a prune schedule now",
+		Description: "Run a prune schedule's policy immediately and record the run in its history",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+	}, authz.PermSystemPrune, h.RunSchedule)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "list-prune-schedule-runs",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/prune/schedules/{scheduleId}/runs",
+		Summary:     "List prune schedule runs",
+		Description: "List the recorded runs of a prune schedule, newest first",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemRead, h.ListRuns)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// ListSchedules returns every prune schedule in an environment.
+func (h *PruneScheduleHandler) ListSchedules(ctx context.Context, input *ListPruneSchedulesInput) (*ListPruneSchedulesOutput, error) {
+	schedules, err := h.scheduleService.ListSchedules(ctx, input.EnvironmentID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to list prune schedules").Error())
+	}
+
+	return &ListPruneSchedulesOutput{
+		Body: base.ApiResponse[[]system.PruneSchedule]{
+			Success: true,
+			Data:    schedules,
+		},
+	}, nil
+}
+
+// CreateSchedule creates a prune schedule.
+func (h *PruneScheduleHandler) CreateSchedule(ctx context.Context, input *CreatePruneScheduleInput) (*PruneScheduleOutput, error) {
+	schedule, err := h.scheduleService.CreateSchedule(ctx, input.EnvironmentID, input.Body)
+	if err != nil {
+		apiErr := models.ToAPIError(err)
+		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to create prune schedule").Error())
+	}
+
+	return &PruneScheduleOutput{
+		Body: base.ApiResponse[system.PruneSchedule]{
+			Success: true,
+			Data:    *schedule,
+		},
+	}, nil
+}
+
+// GetSchedule returns a prune schedule by ID.
+func (h *PruneScheduleHandler) GetSchedule(ctx context.Context, input *GetPruneScheduleInput) (*PruneScheduleOutput, error) {
+	schedule, err := h.scheduleService.GetSchedule(ctx, input.EnvironmentID, input.ScheduleID)
+	if err != nil {
+		apiErr := models.ToAPIError(err)
+		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to retrieve prune schedule").Error())
+	}
+
+	return &PruneScheduleOutput{
+		Body: base.ApiResponse[system.PruneSchedule]{
+			Success: true,
+			Data:    *schedule,
+		},
+	}, nil
+}
+
+// UpdateSchedule updates a prune schedule.
+func (h *PruneScheduleHandler) UpdateSchedule(ctx context.Context, input *UpdatePruneScheduleInput) (*PruneScheduleOutput, error) {
+	schedule, err := h.scheduleService.UpdateSchedule(ctx, input.EnvironmentID, input.ScheduleID, input.Body)
+	if err != nil {
+		apiErr := models.ToAPIError(err)
+		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to update prune schedule").Error())
+	}
+
+	return &PruneScheduleOutput{
+		Body: base.ApiResponse[system.PruneSchedule]{
+			Success: true,
+			Data:    *schedule,
+		},
+	}, nil
+}
+
+// DeleteSchedule deletes a prune schedule.
+func (h *PruneScheduleHandler) DeleteSchedule(ctx context.Context, input *GetPruneScheduleInput) (*DeletePruneScheduleOutput, error) {
+	if err := h.scheduleService.DeleteSchedule(ctx, input.EnvironmentID, input.ScheduleID); err != nil {
+		apiErr := models.ToAPIError(err)
+		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to delete prune schedule").Error())
+	}
+
+	return &DeletePruneScheduleOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Prune schedule deleted successfully",
+			},
+		},
+	}, nil
+}
+
+// RunSchedule runs a prune schedule immediately.
+func (h *PruneScheduleHandler) RunSchedule(ctx context.Context, input *GetPruneScheduleInput) (*RunPruneScheduleOutput, error) {
+	run, err := h.scheduleService.RunScheduleNow(ctx, input.EnvironmentID, input.ScheduleID)
+	if err != nil {
+		apiErr := models.ToAPIError(err)
+		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to run prune schedule").Error())
+	}
+
+	return &RunPruneScheduleOutput{
+		Body: base.ApiResponse[system.PruneScheduleRun]{
+			Success: run.Status != system.PruneScheduleRunStatusFailed,
+			Data:    *run,
+		},
+	}, nil
+}
+
+// ListRuns returns the run history of a prune schedule.
+func (h *PruneScheduleHandler) ListRuns(ctx context.Context, input *ListPruneScheduleRunsInput) (*ListPruneScheduleRunsOutput, error) {
+	runs, err := h.scheduleService.ListScheduleRuns(ctx, input.EnvironmentID, input.ScheduleID, input.Limit)
+	if err != nil {
+		apiErr := models.ToAPIError(err)
+		return nil, huma.NewError(apiErr.HTTPStatus(), errors.WithMessage(err, "Failed to list prune schedule runs").Error())
+	}
+
+	return &ListPruneScheduleRunsOutput{
+		Body: base.ApiResponse[[]system.PruneScheduleRun]{
+			Success: true,
+			Data:    runs,
+		},
+	}, nil
+}
@@ -23,7 +23,7 @@ type RoleHandler struct {
 
 type ListRolesInput struct {
 	Search string `query:"search" doc:"Search by role name or description"`
-	Sort   string `query:"sort" doc:"Column to sort by"`
+	Sort   string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order  string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start  int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit  int    `query:"limit" default:"20" doc:"Items per page"`
@@ -389,6 +389,18 @@ func buildPermissionsManifestInternal() roletypes.PermissionsManifest {
 		Resources:      resources,
 		AccessSurfaces: buildAccessSurfaceManifestInternal(),
 		Presets: []roletypes.PermissionPreset{
+			{
+				Key:         "read-only",
+				Label:       "Read-only",
+				Description: "Matches the built-in Viewer permission set. No mutations.",
+				Permissions: authz.BuiltInViewerPermissions(),
+			},
+			{
+				Key:         "deployer",
+				Label:       "Deploy",
+				Description: "Matches the built-in Deployer permission set: lifecycle actions plus read access.",
+				Permissions: authz.BuiltInDeployerPermissions(),
+			},
 			{
 				Key:         "editor",
 				Label:       "All permissions (non-admin)",
@@ -3,8 +3,11 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -16,10 +19,12 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
+	utilsregistry "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/registryauth"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/mapper"
 	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/category"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
 	"github.com/getarcaneapp/arcane/types/v2/search"
 	"github.com/getarcaneapp/arcane/types/v2/settings"
 )
@@ -71,6 +76,23 @@ type GetCategoriesOutput struct {
 	Body []category.Category
 }
 
+type GetMaintenanceInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetMaintenanceOutput struct {
+	Body base.ApiResponse[environment.Maintenance]
+}
+
+type SetMaintenanceInput struct {
+	EnvironmentID string                        `path:"id" doc:"Environment ID"`
+	Body          environment.MaintenanceUpdate `doc:"Maintenance mode state"`
+}
+
+type SetMaintenanceOutput struct {
+	Body base.ApiResponse[environment.Maintenance]
+}
+
 // validateProjectsDirectoryValueInternal validates a projects directory value allowing:
 // - Unix absolute paths (/...)
 // - Windows drive paths (C:/..., C:\...)
@@ -151,6 +173,26 @@ func RegisterSettings(api huma.API, settingsService *services.SettingsService, s
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermSettingsWrite, h.UpdateSettings)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-environment-maintenance",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/maintenance",
+		Summary:     "Get maintenance mode",
+		Description: "Report whether the environment is in maintenance mode",
+		Tags:        []string{"Settings"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSettingsRead, h.GetMaintenance)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "set-environment-maintenance",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/maintenance",
+		Summary:     "Set maintenance mode",
+		Description: "Turn maintenance mode on or off. While on, deploys and other mutating operations return 409 unless a global admin sends X-Arcane-Maintenance-Override: true",
+		Tags:        []string{"Settings"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSettingsWrite, h.SetMaintenance)
+
 	// Top-level settings endpoints (not environment-scoped)
 	huma.Register(api, huma.Operation{
 		OperationID: "search-settings",
@@ -305,6 +347,30 @@ func (h *SettingsHandler) GetSettings(ctx context.Context, input *GetSettingsInp
 	return &GetSettingsOutput{Body: h.appendRuntimeSettingsInternal(settingsDto, true)}, nil
 }
 
+// GetMaintenance returns the environment's maintenance-mode state.
+func (h *SettingsHandler) GetMaintenance(ctx context.Context, _ *GetMaintenanceInput) (*GetMaintenanceOutput, error) {
+	return &GetMaintenanceOutput{
+		Body: base.ApiResponse[environment.Maintenance]{
+			Success: true,
+			Data:    h.settingsService.GetMaintenance(ctx),
+		},
+	}, nil
+}
+
+// SetMaintenance turns the environment's maintenance mode on or off.
+func (h *SettingsHandler) SetMaintenance(ctx context.Context, input *SetMaintenanceInput) (*SetMaintenanceOutput, error) {
+	maintenance, err := h.settingsService.SetMaintenance(ctx, input.Body)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to update maintenance mode").Error())
+	}
+	return &SetMaintenanceOutput{
+		Body: base.ApiResponse[environment.Maintenance]{
+			Success: true,
+			Data:    maintenance,
+		},
+	}, nil
+}
+
 // UpdateSettings updates settings for an environment.
 func (h *SettingsHandler) UpdateSettings(ctx context.Context, input *UpdateSettingsInput) (*UpdateSettingsOutput, error) {
 	if err := h.validateSettingsUpdateInput(input.Body); err != nil {
@@ -347,6 +413,20 @@ func (h *SettingsHandler) validateSettingsUpdateInput(input settings.Update) err
 		}
 	}
 
+	if input.DefaultContainerCpuLimit != nil && strings.TrimSpace(*input.DefaultContainerCpuLimit) != "" {
+		value, err := strconv.ParseFloat(strings.TrimSpace(*input.DefaultContainerCpuLimit), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+			return huma.Error400BadRequest("defaultContainerCpuLimit must be a non-negative number of cores")
+		}
+	}
+
+	if input.DefaultContainerMemoryLimitMb != nil && strings.TrimSpace(*input.DefaultContainerMemoryLimitMb) != "" {
+		value, err := strconv.ParseInt(strings.TrimSpace(*input.DefaultContainerMemoryLimitMb), 10, 64)
+		if err != nil || value < 0 {
+			return huma.Error400BadRequest("defaultContainerMemoryLimitMb must be a non-negative whole number of MB")
+		}
+	}
+
 	return nil
 }
 
@@ -382,6 +462,13 @@ func (h *SettingsHandler) updateSettingsForLocalEnvironment(ctx context.Context,
 		}
 	}
 
+	if input.RegistryMirrors != nil && strings.TrimSpace(*input.RegistryMirrors) != "" &&
+		*input.RegistryMirrors != h.settingsService.GetSettingsConfig().RegistryMirrors.Value {
+		if err := validateRegistryMirrorsInternal(ctx, *input.RegistryMirrors); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedSettings, err := h.settingsService.UpdateSettings(ctx, input)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("Failed to update settings")
@@ -437,3 +524,30 @@ func (h *SettingsHandler) GetCategories(ctx context.Context, input *struct{}) (*
 	categories := filterSettingsCategoriesInternal(ps, h.settingsSearchService.GetSettingsCategories())
 	return &GetCategoriesOutput{Body: categories}, nil
 }
+
+// validateRegistryMirrorsInternal parses the registryMirrors value and checks that
+// every mirror is reachable, so a typo is rejected when it is saved rather than
+// when a later pull stalls. Failures are returned as a 400 whose detail points
+// at the registryMirrors field and names the mirror.
+func validateRegistryMirrorsInternal(ctx context.Context, value string) error {
+	mirrors, err := utilsregistry.ParseMirrors(value)
+	if err != nil {
+		return registryMirrorsFieldErrorInternal(errors.WrapIf(err, "invalid registry mirrors").Error(), value)
+	}
+
+	for _, registry := range slices.Sorted(maps.Keys(mirrors)) {
+		mirror := mirrors[registry]
+		if err := utilsregistry.ProbeMirror(ctx, mirror); err != nil {
+			return registryMirrorsFieldErrorInternal(fmt.Sprintf("registry mirror %q for %s is not reachable: %v", mirror, registry, err), mirror)
+		}
+	}
+	return nil
+}
+
+func registryMirrorsFieldErrorInternal(message, value string) error {
+	return huma.NewError(http.StatusBadRequest, message, &huma.ErrorDetail{
+		Message:  message,
+		Location: "body.registryMirrors",
+		Value:    value,
+	})
+}
@@ -98,6 +98,61 @@ func TestSettingsHandler_UpdateLocalEnvironment_RejectsUnreadableProjectsDirecto
 	require.Equal(t, originalDir, settingsSvc.GetSettingsConfig().ProjectsDirectory.Value, "projectsDirectory must not be persisted on validation failure")
 }
 
+func TestSettingsHandler_UpdateLocalEnvironment_RejectsUnreachableRegistryMirror(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.SettingVariable{}))
+
+	settingsSvc, err := services.NewSettingsService(ctx, &database.DB{DB: db})
+	require.NoError(t, err)
+
+	handler := &SettingsHandler{settingsService: settingsSvc, cfg: &config.Config{}}
+
+	// Port 1 on loopback is closed, so the connection is refused immediately.
+	_, err = handler.updateSettingsForLocalEnvironment(ctx, apitypes.Update{RegistryMirrors: new("docker.io=127.0.0.1:1/dockerhub")})
+	require.Error(t, err)
+
+	var statusErr huma.StatusError
+	require.ErrorAs(t, err, &statusErr, "expected a huma status error")
+	require.Equal(t, 400, statusErr.GetStatus())
+
+	var model *huma.ErrorModel
+	require.ErrorAs(t, err, &model)
+	require.Len(t, model.Errors, 1)
+	require.Equal(t, "body.registryMirrors", model.Errors[0].Location)
+	require.Contains(t, model.Errors[0].Message, `"127.0.0.1:1/dockerhub"`)
+
+	require.Empty(t, settingsSvc.GetSettingsConfig().RegistryMirrors.Value, "registryMirrors must not be persisted on validation failure")
+}
+
+func TestSettingsHandler_ValidateSettingsUpdateInput_DefaultContainerLimits(t *testing.T) {
+	handler := &SettingsHandler{cfg: &config.Config{}}
+
+	for _, update := range []apitypes.Update{
+		{DefaultContainerCpuLimit: new("1.5")},
+		{DefaultContainerCpuLimit: new("0")},
+		{DefaultContainerMemoryLimitMb: new("512")},
+		{DefaultContainerMemoryLimitMb: new("")},
+	} {
+		require.NoError(t, handler.validateSettingsUpdateInput(update))
+	}
+
+	for _, update := range []apitypes.Update{
+		{DefaultContainerCpuLimit: new("-1")},
+		{DefaultContainerCpuLimit: new("two")},
+		{DefaultContainerCpuLimit: new("NaN")},
+		{DefaultContainerMemoryLimitMb: new("-256")},
+		{DefaultContainerMemoryLimitMb: new("1.5")},
+	} {
+		err := handler.validateSettingsUpdateInput(update)
+		var statusErr huma.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		require.Equal(t, 400, statusErr.GetStatus())
+	}
+}
+
 func runtimeSettingKeysInternal(settings []apitypes.PublicSetting) map[string]string {
 	keys := make(map[string]string, len(settings))
 	for _, setting := range settings {
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"maps"
+	"mime/multipart"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/containerd/errdefs"
@@ -32,10 +39,20 @@ type SwarmHandler struct {
 type ListSwarmServicesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
+	Filter        string `query:"filter" doc:"Comma-separated range filters on replicas, runningReplicas, created or updated, e.g. replicas:>=3"`
+}
+
+type ExportSwarmServicesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Format        string `query:"format" default:"csv" enum:"csv" doc:"Export format"`
+	Search        string `query:"search" doc:"Search query"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
+	Filter        string `query:"filter" doc:"Comma-separated range filters on replicas, runningReplicas, created or updated, e.g. replicas:>=3"`
 }
 
 type ListSwarmServicesOutput struct {
@@ -51,6 +68,15 @@ type GetSwarmServiceOutput struct {
 	Body base.ApiResponse[swarmtypes.ServiceInspect]
 }
 
+type GetSwarmServiceStatusInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ServiceID     string `path:"serviceId" doc:"Service ID"`
+}
+
+type GetSwarmServiceStatusOutput struct {
+	Body base.ApiResponse[swarmtypes.ServiceUpdateStatus]
+}
+
 type CreateSwarmServiceInput struct {
 	EnvironmentID string                          `path:"id" doc:"Environment ID"`
 	Body          swarmtypes.ServiceCreateRequest `doc:"Service creation request"`
@@ -60,6 +86,11 @@ type CreateSwarmServiceOutput struct {
 	Body base.ApiResponse[swarmtypes.ServiceCreateResponse]
 }
 
+type CreateSwarmServiceFromComposeInput struct {
+	EnvironmentID string                               `path:"id" doc:"Environment ID"`
+	Body          swarmtypes.ServiceFromComposeRequest `doc:"Compose fragment declaring one service"`
+}
+
 type UpdateSwarmServiceInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ServiceID     string `path:"serviceId" doc:"Service ID"`
@@ -83,7 +114,7 @@ type ListSwarmServiceTasksInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ServiceID     string `path:"serviceId" doc:"Service ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -102,6 +133,24 @@ type RollbackSwarmServiceOutput struct {
 	Body base.ApiResponse[swarmtypes.ServiceUpdateResponse]
 }
 
+type RestartSwarmServiceInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ServiceID     string `path:"serviceId" doc:"Service ID"`
+}
+
+type RestartSwarmServiceOutput struct {
+	Body base.ApiResponse[swarmtypes.ServiceUpdateResponse]
+}
+
+type PinSwarmServiceImageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ServiceID     string `path:"serviceId" doc:"Service ID"`
+}
+
+type PinSwarmServiceImageOutput struct {
+	Body base.ApiResponse[swarmtypes.ServiceImagePinResponse]
+}
+
 type ScaleSwarmServiceInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ServiceID     string `path:"serviceId" doc:"Service ID"`
@@ -112,15 +161,43 @@ type ScaleSwarmServiceOutput struct {
 	Body base.ApiResponse[swarmtypes.ServiceUpdateResponse]
 }
 
+type UpdateSwarmServicePlacementInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ServiceID     string `path:"serviceId" doc:"Service ID"`
+	Body          swarmtypes.ServicePlacementRequest
+}
+
+type UpdateSwarmServicePlacementOutput struct {
+	Body base.ApiResponse[swarmtypes.ServiceUpdateResponse]
+}
+
+type UpdateSwarmServiceResourcesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ServiceID     string `path:"serviceId" doc:"Service ID"`
+	Body          swarmtypes.ServiceResourcesRequest
+}
+
+type UpdateSwarmServiceResourcesOutput struct {
+	Body base.ApiResponse[swarmtypes.ServiceUpdateResponse]
+}
+
 type ListSwarmNodesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
 }
 
+type ExportSwarmNodesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Format        string `query:"format" default:"csv" enum:"csv" doc:"Export format"`
+	Search        string `query:"search" doc:"Search query"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
+}
+
 type ListSwarmNodesOutput struct {
 	Body base.Paginated[swarmtypes.NodeSummary]
 }
@@ -206,6 +283,25 @@ type UpdateSwarmNodeOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type PatchSwarmNodeLabelsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	NodeID        string `path:"nodeId" doc:"Node ID"`
+	Body          swarmtypes.NodeLabelsPatchRequest
+}
+
+type PatchSwarmNodeLabelsOutput struct {
+	Body base.ApiResponse[swarmtypes.NodeLabelsResponse]
+}
+
+type GetSwarmTaskLogsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	TaskID        string `path:"taskId" doc:"Task ID"`
+	Follow        bool   `query:"follow" default:"false" doc:"Keep streaming new output while the task runs"`
+	Tail          string `query:"tail" default:"100" doc:"Number of lines to show from the end of the log, or all"`
+	Since         string `query:"since" doc:"Only return logs since this timestamp"`
+	Timestamps    bool   `query:"timestamps" default:"false" doc:"Prefix each line with its timestamp"`
+}
+
 type DeleteSwarmNodeInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	NodeID        string `path:"nodeId" doc:"Node ID"`
@@ -216,6 +312,16 @@ type DeleteSwarmNodeOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type DrainSwarmNodeInput struct {
+	EnvironmentID  string `path:"id" doc:"Environment ID"`
+	NodeID         string `path:"nodeId" doc:"Node ID"`
+	TimeoutSeconds int    `query:"timeout" default:"60" minimum:"1" maximum:"600" doc:"Seconds to wait for tasks to leave the node"`
+}
+
+type DrainSwarmNodeOutput struct {
+	Body base.ApiResponse[swarmtypes.NodeDrainResponse]
+}
+
 type PromoteSwarmNodeInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	NodeID        string `path:"nodeId" doc:"Node ID"`
@@ -238,7 +344,7 @@ type ListSwarmNodeTasksInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	NodeID        string `path:"nodeId" doc:"Node ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -251,12 +357,20 @@ type ListSwarmNodeTasksOutput struct {
 type ListSwarmTasksInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
 }
 
+type ExportSwarmTasksInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Format        string `query:"format" default:"csv" enum:"csv" doc:"Export format"`
+	Search        string `query:"search" doc:"Search query"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
+}
+
 type ListSwarmTasksOutput struct {
 	Body base.Paginated[swarmtypes.TaskSummary]
 }
@@ -264,7 +378,7 @@ type ListSwarmTasksOutput struct {
 type ListSwarmStacksInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -283,6 +397,20 @@ type DeploySwarmStackOutput struct {
 	Body base.ApiResponse[swarmtypes.StackDeployResponse]
 }
 
+type ExportSwarmStacksInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ImportSwarmStacksInput struct {
+	EnvironmentID string         `path:"id" doc:"Environment ID"`
+	DryRun        bool           `query:"dryRun" doc:"Plan each stack without deploying it or saving its source"`
+	RawBody       multipart.Form `contentType:"multipart/form-data"`
+}
+
+type ImportSwarmStacksOutput struct {
+	Body base.ApiResponse[swarmtypes.StackImportResponse]
+}
+
 type GetSwarmStackInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Name          string `path:"name" doc:"Stack name"`
@@ -292,6 +420,23 @@ type GetSwarmStackOutput struct {
 	Body base.ApiResponse[swarmtypes.StackInspect]
 }
 
+type GetSwarmStackRemovalPlanInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Name          string `path:"name" doc:"Stack name"`
+}
+
+type GetSwarmStackRemovalPlanOutput struct {
+	Body base.ApiResponse[swarmtypes.StackRemovalPlan]
+}
+
+type ListOrphanedSwarmStackSourcesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ListOrphanedSwarmStackSourcesOutput struct {
+	Body base.ApiResponse[[]swarmtypes.OrphanedStackSource]
+}
+
 type GetSwarmStackSourceInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Name          string `path:"name" doc:"Stack name"`
@@ -301,6 +446,26 @@ type GetSwarmStackSourceOutput struct {
 	Body base.ApiResponse[swarmtypes.StackSource]
 }
 
+type GetSwarmStackHistoryInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Name          string `path:"name" doc:"Stack name"`
+}
+
+type GetSwarmStackHistoryOutput struct {
+	Body base.ApiResponse[[]swarmtypes.StackSourceVersion]
+}
+
+type GetSwarmStackHistoryDiffInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Name          string `path:"name" doc:"Stack name"`
+	From          string `query:"from" required:"true" doc:"Base version ID, or current"`
+	To            string `query:"to" default:"current" doc:"Compared version ID, or current"`
+}
+
+type GetSwarmStackHistoryDiffOutput struct {
+	Body base.ApiResponse[swarmtypes.StackSourceDiff]
+}
+
 type UpdateSwarmStackSourceInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Name          string `path:"name" doc:"Stack name"`
@@ -311,6 +476,16 @@ type UpdateSwarmStackSourceOutput struct {
 	Body base.ApiResponse[swarmtypes.StackSource]
 }
 
+type ScaleSwarmStackInput struct {
+	EnvironmentID string                       `path:"id" doc:"Environment ID"`
+	Name          string                       `path:"name" doc:"Stack name"`
+	Body          swarmtypes.StackScaleRequest `doc:"Desired replica counts by service name"`
+}
+
+type ScaleSwarmStackOutput struct {
+	Body base.ApiResponse[[]swarmtypes.StackScaleResult]
+}
+
 type DeleteSwarmStackInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Name          string `path:"name" doc:"Stack name"`
@@ -324,7 +499,7 @@ type ListSwarmStackServicesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Name          string `path:"name" doc:"Stack name"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -338,7 +513,7 @@ type ListSwarmStackTasksInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Name          string `path:"name" doc:"Stack name"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -357,6 +532,14 @@ type RenderSwarmStackConfigOutput struct {
 	Body base.ApiResponse[swarmtypes.StackRenderConfigResponse]
 }
 
+type GetSwarmResourcesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetSwarmResourcesOutput struct {
+	Body base.ApiResponse[swarmtypes.ClusterResources]
+}
+
 type GetSwarmInfoInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 }
@@ -462,10 +645,15 @@ type UpdateSwarmSpecOutput struct {
 
 type ListSwarmConfigsInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Search        string `query:"search" doc:"Search query"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
+	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
+	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
 }
 
 type ListSwarmConfigsOutput struct {
-	Body base.ApiResponse[[]swarmtypes.ConfigSummary]
+	Body base.Paginated[swarmtypes.ConfigSummary]
 }
 
 type GetSwarmConfigInput struct {
@@ -477,6 +665,15 @@ type GetSwarmConfigOutput struct {
 	Body base.ApiResponse[swarmtypes.ConfigSummary]
 }
 
+type GetSwarmConfigUsageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ConfigID      string `path:"configId" doc:"Config ID"`
+}
+
+type GetSwarmConfigUsageOutput struct {
+	Body base.ApiResponse[[]swarmtypes.ServiceMountUsage]
+}
+
 type CreateSwarmConfigInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Body          swarmtypes.ConfigCreateRequest
@@ -507,10 +704,15 @@ type DeleteSwarmConfigOutput struct {
 
 type ListSwarmSecretsInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Search        string `query:"search" doc:"Search query"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order         string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
+	Start         int    `query:"start" default:"0" doc:"Start index for pagination"`
+	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
 }
 
 type ListSwarmSecretsOutput struct {
-	Body base.ApiResponse[[]swarmtypes.SecretSummary]
+	Body base.Paginated[swarmtypes.SecretSummary]
 }
 
 type GetSwarmSecretInput struct {
@@ -522,6 +724,15 @@ type GetSwarmSecretOutput struct {
 	Body base.ApiResponse[swarmtypes.SecretSummary]
 }
 
+type GetSwarmSecretUsageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	SecretID      string `path:"secretId" doc:"Secret ID"`
+}
+
+type GetSwarmSecretUsageOutput struct {
+	Body base.ApiResponse[[]swarmtypes.ServiceMountUsage]
+}
+
 type CreateSwarmSecretInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	Body          swarmtypes.SecretCreateRequest
@@ -541,6 +752,16 @@ type UpdateSwarmSecretOutput struct {
 	Status int `status:"204"`
 }
 
+type RotateSwarmSecretInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	SecretID      string `path:"secretId" doc:"Secret ID"`
+	Body          swarmtypes.SecretRotateRequest
+}
+
+type RotateSwarmSecretOutput struct {
+	Body base.ApiResponse[swarmtypes.SecretRotateResponse]
+}
+
 type DeleteSwarmSecretInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	SecretID      string `path:"secretId" doc:"Secret ID"`
@@ -570,64 +791,87 @@ func RegisterSwarm(api huma.API, swarmSvc *services.SwarmService, environmentSvc
 	}
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-services", Method: http.MethodGet, Path: "/environments/{id}/swarm/services", Summary: "List swarm services", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListServices)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "export-swarm-services", Method: http.MethodGet, Path: "/environments/{id}/swarm/services/export", Summary: "Export swarm services as CSV", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ExportServices)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-service", Method: http.MethodGet, Path: "/environments/{id}/swarm/services/{serviceId}", Summary: "Get swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetService)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services", Summary: "Create swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.CreateService)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-service", Method: http.MethodPut, Path: "/environments/{id}/swarm/services/{serviceId}", Summary: "Update swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.UpdateService)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-service", Method: http.MethodDelete, Path: "/environments/{id}/swarm/services/{serviceId}", Summary: "Delete swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.DeleteService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-service-status", Method: http.MethodGet, Path: "/environments/{id}/swarm/services/{serviceId}/status", Summary: "Get swarm service update status", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetServiceStatus)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services", Summary: "Create swarm service", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.CreateService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-service-from-compose", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/from-compose", Summary: "Create swarm service from a compose fragment", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.CreateServiceFromCompose)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-service", Method: http.MethodPut, Path: "/environments/{id}/swarm/services/{serviceId}", Summary: "Update swarm service", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.UpdateService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-service", Method: http.MethodDelete, Path: "/environments/{id}/swarm/services/{serviceId}", Summary: "Delete swarm service", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.DeleteService)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-service-tasks", Method: http.MethodGet, Path: "/environments/{id}/swarm/services/{serviceId}/tasks", Summary: "List tasks for a swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListServiceTasks)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "rollback-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/rollback", Summary: "Rollback a swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.RollbackService)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "scale-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/scale", Summary: "Scale a swarm service", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.ScaleService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "rollback-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/rollback", Summary: "Rollback a swarm service", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.RollbackService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "restart-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/restart", Summary: "Force a rolling restart of a swarm service", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.RestartService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "pin-swarm-service-image", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/pin", Summary: "Pin a swarm service to its current image digest", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.PinServiceImage)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "scale-swarm-service", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/scale", Summary: "Scale a swarm service", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.ScaleService)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-service-placement", Method: http.MethodPost, Path: "/environments/{id}/swarm/services/{serviceId}/placement", Summary: "Update swarm service placement", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.UpdateServicePlacement)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-service-resources", Method: http.MethodPatch, Path: "/environments/{id}/swarm/services/{serviceId}/resources", Summary: "Update swarm service resource limits and reservations", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmServices, h.UpdateServiceResources)
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-nodes", Method: http.MethodGet, Path: "/environments/{id}/swarm/nodes", Summary: "List swarm nodes", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListNodes)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "export-swarm-nodes", Method: http.MethodGet, Path: "/environments/{id}/swarm/nodes/export", Summary: "Export swarm nodes as CSV", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ExportNodes)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-node", Method: http.MethodGet, Path: "/environments/{id}/swarm/nodes/{nodeId}", Summary: "Get swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetNode)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-node-agent-deployment", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/{nodeId}/agent/deployment", Summary: "Get swarm node agent deployment snippets", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.GetNodeAgentDeployment)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "reconcile-swarm-node-agents", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/agents/reconcile", Summary: "Reconcile swarm node agent bindings", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.ReconcileNodeAgents)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "put-swarm-node-agent-binding", Method: http.MethodPut, Path: "/environments/{id}/swarm/nodes/{nodeId}/agent/binding", Summary: "Attach a visible environment to a swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.PutNodeAgentBinding)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-node-agent-binding", Method: http.MethodDelete, Path: "/environments/{id}/swarm/nodes/{nodeId}/agent/binding", Summary: "Detach a visible environment from a swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DeleteNodeAgentBinding)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-node-agent-deployment", Method: http.MethodDelete, Path: "/environments/{id}/swarm/nodes/{nodeId}/agent/deployment", Summary: "Remove a dedicated swarm node agent registration", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DeleteNodeAgentDeployment)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-node", Method: http.MethodPatch, Path: "/environments/{id}/swarm/nodes/{nodeId}", Summary: "Update swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.UpdateNode)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-node", Method: http.MethodDelete, Path: "/environments/{id}/swarm/nodes/{nodeId}", Summary: "Delete swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DeleteNode)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "promote-swarm-node", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/{nodeId}/promote", Summary: "Promote swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.PromoteNode)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "demote-swarm-node", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/{nodeId}/demote", Summary: "Demote swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DemoteNode)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-node", Method: http.MethodPatch, Path: "/environments/{id}/swarm/nodes/{nodeId}", Summary: "Update swarm node", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.UpdateNode)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "patch-swarm-node-labels", Method: http.MethodPatch, Path: "/environments/{id}/swarm/nodes/{nodeId}/labels", Summary: "Set and remove swarm node labels", Description: "Merge label changes into the node's current labels server-side instead of replacing them all", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.PatchNodeLabels)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-node", Method: http.MethodDelete, Path: "/environments/{id}/swarm/nodes/{nodeId}", Summary: "Delete swarm node", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DeleteNode)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "promote-swarm-node", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/{nodeId}/promote", Summary: "Promote swarm node", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.PromoteNode)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "demote-swarm-node", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/{nodeId}/demote", Summary: "Demote swarm node", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DemoteNode)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "drain-swarm-node", Method: http.MethodPost, Path: "/environments/{id}/swarm/nodes/{nodeId}/drain", Summary: "Drain swarm node and wait for tasks to move", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmNodes, h.DrainNode)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-node-tasks", Method: http.MethodGet, Path: "/environments/{id}/swarm/nodes/{nodeId}/tasks", Summary: "List tasks for a swarm node", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListNodeTasks)
 	huma.Register(api, huma.Operation{OperationID: "get-swarm-node-identity", Method: http.MethodGet, Path: "/swarm/node-identity", Summary: "Get local swarm node identity", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal(), Middlewares: humamw.RequirePermission(api, authz.PermSwarmRead)}, h.GetNodeIdentity)
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-tasks", Method: http.MethodGet, Path: "/environments/{id}/swarm/tasks", Summary: "List swarm tasks", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListTasks)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "export-swarm-tasks", Method: http.MethodGet, Path: "/environments/{id}/swarm/tasks/export", Summary: "Export swarm tasks as CSV", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ExportTasks)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-task-logs", Method: http.MethodGet, Path: "/environments/{id}/swarm/tasks/{taskId}/logs", Summary: "Get swarm task logs", Description: "Stream one task's log output as plain text, including the last lines of tasks that have already exited", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmServicesLogs, h.GetTaskLogs)
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-stacks", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks", Summary: "List swarm stacks", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListStacks)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "deploy-swarm-stack", Method: http.MethodPost, Path: "/environments/{id}/swarm/stacks", Summary: "Deploy swarm stack", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.DeployStack)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "deploy-swarm-stack", Method: http.MethodPost, Path: "/environments/{id}/swarm/stacks", Summary: "Deploy swarm stack", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.DeployStack)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "export-swarm-stacks", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/export", Summary: "Export swarm stack sources", Description: "Download the saved source of every stack as a gzip-compressed tar bundle", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.ExportStacks)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "import-swarm-stacks", Method: http.MethodPost, Path: "/environments/{id}/swarm/stacks/import", Summary: "Import swarm stack sources", Description: "Deploy every stack in an uploaded bundle, or plan them with dryRun", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.ImportStacks)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-stack", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}", Summary: "Get swarm stack", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetStack)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-orphaned-swarm-stack-sources", Method: http.MethodGet, Path: "/environments/{id}/swarm/stack-sources/orphaned", Summary: "List orphaned swarm stack sources", Description: "List saved stack sources that have no stack deployed under the same name", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.ListOrphanedStackSources)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-stack-source", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}/source", Summary: "Get swarm stack source", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.GetStackSource)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-stack-source", Method: http.MethodPut, Path: "/environments/{id}/swarm/stacks/{name}/source", Summary: "Update swarm stack source", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.UpdateStackSource)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-stack", Method: http.MethodDelete, Path: "/environments/{id}/swarm/stacks/{name}", Summary: "Delete swarm stack", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.DeleteStack)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-stack-source", Method: http.MethodPut, Path: "/environments/{id}/swarm/stacks/{name}/source", Summary: "Update swarm stack source", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.UpdateStackSource)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-stack-history", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}/history", Summary: "List swarm stack source history", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.GetStackHistory)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-stack-history-diff", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}/history/diff", Summary: "Diff swarm stack source versions", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.GetStackHistoryDiff)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-stack-removal-plan", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}/removal-plan", Summary: "Preview swarm stack removal", Description: "List the services, configs, secrets and networks that deleting the stack would remove, and the shared or ingress networks it would keep", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetStackRemovalPlan)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-stack", Method: http.MethodDelete, Path: "/environments/{id}/swarm/stacks/{name}", Summary: "Delete swarm stack", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.DeleteStack)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "scale-swarm-stack", Method: http.MethodPost, Path: "/environments/{id}/swarm/stacks/{name}/scale", Summary: "Scale swarm stack services", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmStacks, h.ScaleStack)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-stack-services", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}/services", Summary: "List swarm stack services", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListStackServices)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-stack-tasks", Method: http.MethodGet, Path: "/environments/{id}/swarm/stacks/{name}/tasks", Summary: "List swarm stack tasks", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListStackTasks)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "render-swarm-stack-config", Method: http.MethodPost, Path: "/environments/{id}/swarm/stacks/config/render", Summary: "Render/validate swarm stack config", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.RenderStackConfig)
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-status", Method: http.MethodGet, Path: "/environments/{id}/swarm/status", Summary: "Get swarm status", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetSwarmStatus)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-info", Method: http.MethodGet, Path: "/environments/{id}/swarm/info", Summary: "Get swarm info", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetSwarmInfo)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "init-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/init", Summary: "Initialize swarm", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmInit, h.InitSwarm)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "join-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/join", Summary: "Join swarm", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmJoin, h.JoinSwarm)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-resources", Method: http.MethodGet, Path: "/environments/{id}/swarm/resources", Summary: "Get swarm cluster resources", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetClusterResources)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "init-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/init", Summary: "Initialize swarm", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmInit, h.InitSwarm)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "join-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/join", Summary: "Join swarm", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmJoin, h.JoinSwarm)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-join-candidates", Method: http.MethodGet, Path: "/environments/{id}/swarm/join-candidates", Summary: "List environments available for Easy Join", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmJoin, h.GetJoinCandidates)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "join-swarm-environments", Method: http.MethodPost, Path: "/environments/{id}/swarm/join-environments", Summary: "Join environments to a swarm", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmJoin, h.JoinEnvironments)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "leave-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/leave", Summary: "Leave swarm", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmLeave, h.LeaveSwarm)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "unlock-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/unlock", Summary: "Unlock swarm", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmUnlock, h.UnlockSwarm)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "join-swarm-environments", Method: http.MethodPost, Path: "/environments/{id}/swarm/join-environments", Summary: "Join environments to a swarm", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmJoin, h.JoinEnvironments)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "leave-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/leave", Summary: "Leave swarm", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmLeave, h.LeaveSwarm)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "unlock-swarm", Method: http.MethodPost, Path: "/environments/{id}/swarm/unlock", Summary: "Unlock swarm", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmUnlock, h.UnlockSwarm)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-unlock-key", Method: http.MethodGet, Path: "/environments/{id}/swarm/unlock-key", Summary: "Get swarm unlock key", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmUnlock, h.GetUnlockKey)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-join-tokens", Method: http.MethodGet, Path: "/environments/{id}/swarm/join-tokens", Summary: "Get swarm join tokens", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmUnlock, h.GetJoinTokens)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "rotate-swarm-join-tokens", Method: http.MethodPost, Path: "/environments/{id}/swarm/join-tokens/rotate", Summary: "Rotate swarm join tokens", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmUnlock, h.RotateJoinTokens)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-spec", Method: http.MethodPut, Path: "/environments/{id}/swarm/spec", Summary: "Update swarm spec", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmSpec, h.UpdateSwarmSpec)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "rotate-swarm-join-tokens", Method: http.MethodPost, Path: "/environments/{id}/swarm/join-tokens/rotate", Summary: "Rotate swarm join tokens", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmUnlock, h.RotateJoinTokens)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-spec", Method: http.MethodPut, Path: "/environments/{id}/swarm/spec", Summary: "Update swarm spec", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmSpec, h.UpdateSwarmSpec)
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-configs", Method: http.MethodGet, Path: "/environments/{id}/swarm/configs", Summary: "List swarm configs", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListConfigs)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-config", Method: http.MethodGet, Path: "/environments/{id}/swarm/configs/{configId}", Summary: "Get swarm config", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetConfig)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-config", Method: http.MethodPost, Path: "/environments/{id}/swarm/configs", Summary: "Create swarm config", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmConfigs, h.CreateConfig)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-config", Method: http.MethodPut, Path: "/environments/{id}/swarm/configs/{configId}", Summary: "Update swarm config", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmConfigs, h.UpdateConfig)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-config", Method: http.MethodDelete, Path: "/environments/{id}/swarm/configs/{configId}", Summary: "Delete swarm config", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmConfigs, h.DeleteConfig)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-config-usage", Method: http.MethodGet, Path: "/environments/{id}/swarm/configs/{configId}/usage", Summary: "List services using a swarm config", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetConfigUsage)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-config", Method: http.MethodPost, Path: "/environments/{id}/swarm/configs", Summary: "Create swarm config", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmConfigs, h.CreateConfig)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-config", Method: http.MethodPut, Path: "/environments/{id}/swarm/configs/{configId}", Summary: "Update swarm config", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmConfigs, h.UpdateConfig)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-config", Method: http.MethodDelete, Path: "/environments/{id}/swarm/configs/{configId}", Summary: "Delete swarm config", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmConfigs, h.DeleteConfig)
 
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "list-swarm-secrets", Method: http.MethodGet, Path: "/environments/{id}/swarm/secrets", Summary: "List swarm secrets", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.ListSecrets)
 	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-secret", Method: http.MethodGet, Path: "/environments/{id}/swarm/secrets/{secretId}", Summary: "Get swarm secret", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetSecret)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-secret", Method: http.MethodPost, Path: "/environments/{id}/swarm/secrets", Summary: "Create swarm secret", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.CreateSecret)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-secret", Method: http.MethodPut, Path: "/environments/{id}/swarm/secrets/{secretId}", Summary: "Update swarm secret", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.UpdateSecret)
-	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-secret", Method: http.MethodDelete, Path: "/environments/{id}/swarm/secrets/{secretId}", Summary: "Delete swarm secret", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.DeleteSecret)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "get-swarm-secret-usage", Method: http.MethodGet, Path: "/environments/{id}/swarm/secrets/{secretId}/usage", Summary: "List services using a swarm secret", Tags: []string{"Swarm"}, Security: defaultOperationSecurityInternal()}, authz.PermSwarmRead, h.GetSecretUsage)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "create-swarm-secret", Method: http.MethodPost, Path: "/environments/{id}/swarm/secrets", Summary: "Create swarm secret", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.CreateSecret)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "update-swarm-secret", Method: http.MethodPut, Path: "/environments/{id}/swarm/secrets/{secretId}", Summary: "Update swarm secret", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.UpdateSecret)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "rotate-swarm-secret", Method: http.MethodPost, Path: "/environments/{id}/swarm/secrets/{secretId}/rotate", Summary: "Rotate swarm secret", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.RotateSecret)
+	humamw.RegisterWithPermission(api, huma.Operation{OperationID: "delete-swarm-secret", Method: http.MethodDelete, Path: "/environments/{id}/swarm/secrets/{secretId}", Summary: "Delete swarm secret", Tags: []string{"Swarm"}, Metadata: humamw.BlockedInMaintenance(), Security: defaultOperationSecurityInternal()}, authz.PermSwarmSecrets, h.DeleteSecret)
 }
 
 // ListServices lists swarm services for an environment and returns a paginated response.
@@ -637,14 +881,21 @@ func RegisterSwarm(api huma.API, swarmSvc *services.SwarmService, environmentSvc
 // when no services are found.
 //
 // ctx carries request-scoped cancellation and auth context.
-// input supplies the environment ID plus optional search, sorting, and pagination values.
+// input supplies the environment ID plus optional search, sorting, range
+// filter, and pagination values.
 //
 // Returns a successful response containing service summaries and pagination metadata.
-// Returns an HTTP-shaped error if the swarm service is unavailable or if the
-// underlying swarm lookup fails.
+// Returns `400 Bad Request` for a malformed filter expression or an unknown
+// filter key, and an HTTP-shaped error if the swarm service is unavailable or
+// if the underlying swarm lookup fails.
 func (h *SwarmHandler) ListServices(ctx context.Context, input *ListSwarmServicesInput) (*ListSwarmServicesOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-	items, paginationResp, err := h.swarmService.ListServicesPaginated(ctx, params)
+	rangeFilters, err := parseRangeFiltersInternal(input.Filter, h.swarmService.ServicePaginationConfig())
+	if err != nil {
+		return nil, err
+	}
+	params.RangeFilters = rangeFilters
+	items, paginationResp, err := h.swarmService.ListServicesPaginated(ctx, input.EnvironmentID, params)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to list swarm services").Error())
 	}
@@ -655,6 +906,23 @@ func (h *SwarmHandler) ListServices(ctx context.Context, input *ListSwarmService
 	return &ListSwarmServicesOutput{Body: base.Paginated[swarmtypes.ServiceSummary]{Success: true, Data: items, Pagination: toPaginationResponseInternal(paginationResp)}}, nil
 }
 
+// ExportServices streams every swarm service matching the search and filters as CSV.
+func (h *SwarmHandler) ExportServices(ctx context.Context, input *ExportSwarmServicesInput) (*huma.StreamResponse, error) {
+	params := buildPaginationParamsInternal(0, -1, input.Sort, input.Order, input.Search)
+	rangeFilters, err := parseRangeFiltersInternal(input.Filter, h.swarmService.ServicePaginationConfig())
+	if err != nil {
+		return nil, err
+	}
+	params.RangeFilters = rangeFilters
+
+	items, _, err := h.swarmService.ListServicesPaginated(ctx, input.EnvironmentID, params)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to export swarm services").Error())
+	}
+
+	return csvExportResponseInternal("swarm-services.csv", items, h.swarmService.ServicePaginationConfig()), nil
+}
+
 // GetService returns detailed information for a single swarm service.
 //
 // It loads the service by ID through the swarm service and converts lookup
@@ -667,7 +935,7 @@ func (h *SwarmHandler) ListServices(ctx context.Context, input *ListSwarmService
 // Returns `404 Not Found` when the service does not exist and other mapped HTTP
 // errors when the inspection fails.
 func (h *SwarmHandler) GetService(ctx context.Context, input *GetSwarmServiceInput) (*GetSwarmServiceOutput, error) {
-	service, err := h.swarmService.GetService(ctx, input.ServiceID)
+	service, err := h.swarmService.GetService(ctx, input.EnvironmentID, input.ServiceID)
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound(errors.WithMessage(err, "Swarm service not found").Error())
@@ -678,6 +946,27 @@ func (h *SwarmHandler) GetService(ctx context.Context, input *GetSwarmServiceInp
 	return &GetSwarmServiceOutput{Body: base.ApiResponse[swarmtypes.ServiceInspect]{Success: true, Data: *service}}, nil
 }
 
+// GetServiceStatus returns the rollout progress of a single swarm service.
+//
+// It reports Docker's update status together with running and desired replica
+// counts so clients can poll for convergence after a deploy, update, or scale
+// and surface paused rollouts.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment and the swarm service to inspect.
+//
+// Returns a successful response containing the service update status.
+// Returns `404 Not Found` when the service does not exist and other mapped HTTP
+// errors when the inspection fails.
+func (h *SwarmHandler) GetServiceStatus(ctx context.Context, input *GetSwarmServiceStatusInput) (*GetSwarmServiceStatusOutput, error) {
+	status, err := h.swarmService.GetServiceUpdateStatus(ctx, input.EnvironmentID, input.ServiceID)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to get swarm service status").Error())
+	}
+
+	return &GetSwarmServiceStatusOutput{Body: base.ApiResponse[swarmtypes.ServiceUpdateStatus]{Success: true, Data: *status}}, nil
+}
+
 // CreateService creates a new swarm service in the target environment.
 //
 // It requires admin privileges, forwards the create request to the swarm
@@ -690,7 +979,7 @@ func (h *SwarmHandler) GetService(ctx context.Context, input *GetSwarmServiceInp
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when validation or creation fails.
 func (h *SwarmHandler) CreateService(ctx context.Context, input *CreateSwarmServiceInput) (*CreateSwarmServiceOutput, error) {
-	resp, err := h.swarmService.CreateService(ctx, input.Body)
+	resp, err := h.swarmService.CreateService(ctx, input.EnvironmentID, input.Body)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to create swarm service").Error())
 	}
@@ -700,6 +989,28 @@ func (h *SwarmHandler) CreateService(ctx context.Context, input *CreateSwarmServ
 	return &CreateSwarmServiceOutput{Body: base.ApiResponse[swarmtypes.ServiceCreateResponse]{Success: true, Data: *resp}}, nil
 }
 
+// CreateServiceFromCompose creates a swarm service from a compose fragment.
+//
+// It converts the fragment's single service into a service spec, creates it,
+// and records an audit event after a successful mutation.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input contains the environment ID, the compose content and an optional name.
+//
+// Returns a successful response containing the created service ID and any Docker warnings.
+// Returns 400 when the fragment is invalid or references non-external resources,
+// or mapped HTTP errors when creation fails.
+func (h *SwarmHandler) CreateServiceFromCompose(ctx context.Context, input *CreateSwarmServiceFromComposeInput) (*CreateSwarmServiceOutput, error) {
+	resp, err := h.swarmService.CreateServiceFromCompose(ctx, input.EnvironmentID, input.Body.Compose, input.Body.Name)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to create swarm service").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "service.create", "swarm_service", resp.ID, "", map[string]any{"serviceId": resp.ID, "source": "compose"})
+
+	return &CreateSwarmServiceOutput{Body: base.ApiResponse[swarmtypes.ServiceCreateResponse]{Success: true, Data: *resp}}, nil
+}
+
 // UpdateService updates an existing swarm service.
 //
 // It requires admin privileges, submits the requested versioned update to the
@@ -712,7 +1023,7 @@ func (h *SwarmHandler) CreateService(ctx context.Context, input *CreateSwarmServ
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when the update request is invalid or the underlying update fails.
 func (h *SwarmHandler) UpdateService(ctx context.Context, input *UpdateSwarmServiceInput) (*UpdateSwarmServiceOutput, error) {
-	resp, err := h.swarmService.UpdateService(ctx, input.ServiceID, input.Body)
+	resp, err := h.swarmService.UpdateService(ctx, input.EnvironmentID, input.ServiceID, input.Body)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to update swarm service").Error())
 	}
@@ -735,7 +1046,7 @@ func (h *SwarmHandler) UpdateService(ctx context.Context, input *UpdateSwarmServ
 // Returns an authorization error for non-admin callers, `404 Not Found` when
 // the service does not exist, or another mapped HTTP error when removal fails.
 func (h *SwarmHandler) DeleteService(ctx context.Context, input *DeleteSwarmServiceInput) (*DeleteSwarmServiceOutput, error) {
-	if err := h.swarmService.RemoveService(ctx, input.ServiceID); err != nil {
+	if err := h.swarmService.RemoveService(ctx, input.EnvironmentID, input.ServiceID); err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound(errors.WithMessage(err, "Swarm service not found").Error())
 		}
@@ -759,7 +1070,7 @@ func (h *SwarmHandler) DeleteService(ctx context.Context, input *DeleteSwarmServ
 // Returns a mapped HTTP error when the swarm task lookup fails.
 func (h *SwarmHandler) ListServiceTasks(ctx context.Context, input *ListSwarmServiceTasksInput) (*ListSwarmServiceTasksOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-	items, paginationResp, err := h.swarmService.ListServiceTasksPaginated(ctx, input.ServiceID, params)
+	items, paginationResp, err := h.swarmService.ListServiceTasksPaginated(ctx, input.EnvironmentID, input.ServiceID, params)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to list swarm tasks").Error())
 	}
@@ -782,7 +1093,7 @@ func (h *SwarmHandler) ListServiceTasks(ctx context.Context, input *ListSwarmSer
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when the rollback cannot be performed.
 func (h *SwarmHandler) RollbackService(ctx context.Context, input *RollbackSwarmServiceInput) (*RollbackSwarmServiceOutput, error) {
-	resp, err := h.swarmService.RollbackService(ctx, input.ServiceID)
+	resp, err := h.swarmService.RollbackService(ctx, input.EnvironmentID, input.ServiceID)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to update swarm service").Error())
 	}
@@ -792,6 +1103,52 @@ func (h *SwarmHandler) RollbackService(ctx context.Context, input *RollbackSwarm
 	return &RollbackSwarmServiceOutput{Body: base.ApiResponse[swarmtypes.ServiceUpdateResponse]{Success: true, Data: *resp}}, nil
 }
 
+// RestartService forces a rolling restart of every task in a swarm service.
+//
+// It bumps the service's force-update counter through the swarm service so
+// Docker redeploys all tasks without spec changes, which picks up rotated
+// configs, secrets, or re-pulled images, and records an audit event.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the environment and service to restart.
+//
+// Returns a successful response containing any warnings reported by Docker.
+// Returns `403 Forbidden` on worker nodes and other mapped HTTP errors when the
+// update fails.
+func (h *SwarmHandler) RestartService(ctx context.Context, input *RestartSwarmServiceInput) (*RestartSwarmServiceOutput, error) {
+	resp, err := h.swarmService.ForceUpdateService(ctx, input.EnvironmentID, input.ServiceID)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to restart swarm service").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "service.force_update", "swarm_service", input.ServiceID, "", map[string]any{"serviceId": input.ServiceID})
+
+	return &RestartSwarmServiceOutput{Body: base.ApiResponse[swarmtypes.ServiceUpdateResponse]{Success: true, Data: *resp}}, nil
+}
+
+// PinServiceImage pins a swarm service to the image digest it currently runs.
+//
+// It rewrites the service image to its digest-qualified reference through the
+// swarm service, so later stack deploys keep the service on that digest, and
+// records an audit event with the pinned digest.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the environment and service to pin.
+//
+// Returns a successful response containing the pinned image and any warnings
+// reported by Docker. Returns `400 Bad Request` when the service image has no
+// resolved digest and other mapped HTTP errors when the update fails.
+func (h *SwarmHandler) PinServiceImage(ctx context.Context, input *PinSwarmServiceImageInput) (*PinSwarmServiceImageOutput, error) {
+	resp, err := h.swarmService.PinServiceImage(ctx, input.EnvironmentID, input.ServiceID)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to pin swarm service image").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "service.pin_image", "swarm_service", input.ServiceID, "", map[string]any{"serviceId": input.ServiceID, "digest": resp.Digest})
+
+	return &PinSwarmServiceImageOutput{Body: base.ApiResponse[swarmtypes.ServiceImagePinResponse]{Success: true, Data: *resp}}, nil
+}
+
 // ScaleService changes the replica count of a swarm service.
 //
 // It requires admin privileges, forwards the requested replica count to the
@@ -804,7 +1161,7 @@ func (h *SwarmHandler) RollbackService(ctx context.Context, input *RollbackSwarm
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when scaling is invalid or the update fails.
 func (h *SwarmHandler) ScaleService(ctx context.Context, input *ScaleSwarmServiceInput) (*ScaleSwarmServiceOutput, error) {
-	resp, err := h.swarmService.ScaleService(ctx, input.ServiceID, input.Body.Replicas)
+	resp, err := h.swarmService.ScaleService(ctx, input.EnvironmentID, input.ServiceID, input.Body.Replicas)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to update swarm service").Error())
 	}
@@ -814,6 +1171,66 @@ func (h *SwarmHandler) ScaleService(ctx context.Context, input *ScaleSwarmServic
 	return &ScaleSwarmServiceOutput{Body: base.ApiResponse[swarmtypes.ServiceUpdateResponse]{Success: true, Data: *resp}}, nil
 }
 
+// UpdateServicePlacement replaces the placement constraints and preferences of a swarm service.
+//
+// It forwards the requested placement to the swarm service, which validates
+// constraint syntax before submitting the update, and records the new
+// placement in the audit metadata.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the service and supplies the constraints and preferences to apply.
+//
+// Returns a successful response containing any warnings reported by Docker.
+// Returns `400 Bad Request` for malformed constraints or preferences and other
+// mapped HTTP errors when the update fails.
+func (h *SwarmHandler) UpdateServicePlacement(ctx context.Context, input *UpdateSwarmServicePlacementInput) (*UpdateSwarmServicePlacementOutput, error) {
+	resp, err := h.swarmService.UpdateServicePlacement(ctx, input.EnvironmentID, input.ServiceID, input.Body)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to update swarm service placement").Error())
+	}
+
+	metadata := map[string]any{"serviceId": input.ServiceID}
+	if input.Body.Constraints != nil {
+		metadata["constraints"] = *input.Body.Constraints
+	}
+	if input.Body.Preferences != nil {
+		metadata["preferences"] = *input.Body.Preferences
+	}
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "service.placement", "swarm_service", input.ServiceID, "", metadata)
+
+	return &UpdateSwarmServicePlacementOutput{Body: base.ApiResponse[swarmtypes.ServiceUpdateResponse]{Success: true, Data: *resp}}, nil
+}
+
+// UpdateServiceResources merges CPU and memory limits and reservations into a swarm service.
+//
+// Fields the request omits keep their current value and zero removes one.
+// The merged values are recorded in the audit metadata.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the service and supplies the limits and reservations to apply.
+//
+// Returns a successful response containing Docker's warnings plus a warning for
+// each reservation no schedulable node can satisfy.
+// Returns `400 Bad Request` for values below the swarm minimum or reservations
+// above their limit, and other mapped HTTP errors when the update fails.
+func (h *SwarmHandler) UpdateServiceResources(ctx context.Context, input *UpdateSwarmServiceResourcesInput) (*UpdateSwarmServiceResourcesOutput, error) {
+	resp, err := h.swarmService.UpdateServiceResources(ctx, input.EnvironmentID, input.ServiceID, input.Body.Limits, input.Body.Reservations)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to update swarm service resources").Error())
+	}
+
+	metadata := map[string]any{"serviceId": input.ServiceID}
+	if input.Body.Limits != nil {
+		metadata["limits"] = *input.Body.Limits
+	}
+	if input.Body.Reservations != nil {
+		metadata["reservations"] = *input.Body.Reservations
+	}
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "service.resources", "swarm_service", input.ServiceID, "", metadata)
+
+	return &UpdateSwarmServiceResourcesOutput{Body: base.ApiResponse[swarmtypes.ServiceUpdateResponse]{Success: true, Data: *resp}}, nil
+}
+
 // ListNodes lists swarm nodes for an environment and returns a paginated response.
 //
 // It applies the requested search, sort, and pagination values and guarantees a
@@ -837,6 +1254,17 @@ func (h *SwarmHandler) ListNodes(ctx context.Context, input *ListSwarmNodesInput
 	return &ListSwarmNodesOutput{Body: base.Paginated[swarmtypes.NodeSummary]{Success: true, Data: items, Pagination: toPaginationResponseInternal(paginationResp)}}, nil
 }
 
+// ExportNodes streams every swarm node matching the search as CSV.
+func (h *SwarmHandler) ExportNodes(ctx context.Context, input *ExportSwarmNodesInput) (*huma.StreamResponse, error) {
+	params := buildPaginationParamsInternal(0, -1, input.Sort, input.Order, input.Search)
+	items, _, err := h.swarmService.ListNodesPaginated(ctx, input.EnvironmentID, params)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to export swarm nodes").Error())
+	}
+
+	return csvExportResponseInternal("swarm-nodes.csv", items, h.swarmService.NodePaginationConfig()), nil
+}
+
 // GetNode returns detailed information for a single swarm node.
 //
 // It loads the node through the swarm service and translates not-found
@@ -920,7 +1348,7 @@ func (h *SwarmHandler) GetNodeAgentDeployment(ctx context.Context, input *GetSwa
 
 	updatedNode, err := h.swarmService.GetNode(ctx, input.EnvironmentID, input.NodeID)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(err.Error())
+		return nil, mapSwarmServiceError(err, err.Error())
 	}
 
 	return &GetSwarmNodeAgentDeploymentOutput{
@@ -998,11 +1426,15 @@ func (h *SwarmHandler) DeleteNodeAgentDeployment(ctx context.Context, input *Del
 // The input value is unused because the endpoint has no parameters.
 //
 // Returns the local swarm node identity when it can be determined.
-// Returns `500 Internal Server Error` when the swarm service is unavailable or
-// identity discovery fails.
+// Returns `503 Service Unavailable` when the Docker daemon cannot be reached and
+// `500 Internal Server Error` when the swarm service is unavailable or identity
+// discovery fails.
 func (h *SwarmHandler) GetNodeIdentity(ctx context.Context, _ *GetSwarmNodeIdentityInput) (*GetSwarmNodeIdentityOutput, error) {
 	identity, err := h.swarmService.GetLocalNodeIdentity(ctx)
 	if err != nil {
+		if apiErr := dockerUnavailableErrorInternal(err); apiErr != nil {
+			return nil, apiErr
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 
@@ -1026,7 +1458,7 @@ func (h *SwarmHandler) GetNodeIdentity(ctx context.Context, _ *GetSwarmNodeIdent
 // Returns an authorization error for non-admin callers or a mapped HTTP error
 // when the node update fails.
 func (h *SwarmHandler) UpdateNode(ctx context.Context, input *UpdateSwarmNodeInput) (*UpdateSwarmNodeOutput, error) {
-	if err := h.swarmService.UpdateNode(ctx, input.NodeID, input.Body); err != nil {
+	if err := h.swarmService.UpdateNode(ctx, input.EnvironmentID, input.NodeID, input.Body); err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Swarm node not found").Error())
 	}
 
@@ -1035,6 +1467,57 @@ func (h *SwarmHandler) UpdateNode(ctx context.Context, input *UpdateSwarmNodeInp
 	return &UpdateSwarmNodeOutput{Body: base.ApiResponse[base.MessageResponse]{Success: true, Data: base.MessageResponse{Message: "Swarm node updated successfully"}}}, nil
 }
 
+// PatchNodeLabels sets and removes individual labels on a swarm node.
+//
+// Unlike UpdateNode, which replaces the whole label map, the changes are merged
+// into the node's current labels server-side, so two operators editing
+// different labels at the same time do not overwrite each other.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the node and contains the labels to set and remove.
+//
+// Returns the node's labels after the patch.
+// Returns a 400 error when the patch is empty or contradictory, or a mapped
+// HTTP error when the node update fails.
+func (h *SwarmHandler) PatchNodeLabels(ctx context.Context, input *PatchSwarmNodeLabelsInput) (*PatchSwarmNodeLabelsOutput, error) {
+	result, err := h.swarmService.PatchNodeLabels(ctx, input.EnvironmentID, input.NodeID, input.Body.Set, input.Body.Remove)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to update swarm node labels").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "node.labels.patch", "swarm_node", input.NodeID, "", map[string]any{
+		"nodeId": input.NodeID,
+		"set":    slices.Sorted(maps.Keys(input.Body.Set)),
+		"remove": input.Body.Remove,
+	})
+
+	return &PatchSwarmNodeLabelsOutput{Body: base.ApiResponse[swarmtypes.NodeLabelsResponse]{Success: true, Data: *result}}, nil
+}
+
+// DrainNode drains a swarm node and waits for its tasks to be rescheduled.
+//
+// It sets the node availability to drain, then polls the node's tasks until
+// none remain active or the requested timeout elapses. A timeout still leaves
+// the node drained; the response reports how many tasks have not moved yet so
+// callers can decide whether it is safe to take the node down.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the node and the maximum time to wait.
+//
+// Returns the number of tasks moved and still remaining on the node.
+// Returns `404 Not Found` when the node does not exist or another mapped HTTP
+// error when the update fails.
+func (h *SwarmHandler) DrainNode(ctx context.Context, input *DrainSwarmNodeInput) (*DrainSwarmNodeOutput, error) {
+	resp, err := h.swarmService.DrainNode(ctx, input.EnvironmentID, input.NodeID, time.Duration(input.TimeoutSeconds)*time.Second)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to drain swarm node").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "node.drain", "swarm_node", input.NodeID, "", map[string]any{"nodeId": input.NodeID, "tasksMoved": resp.TasksMoved, "remainingTasks": resp.RemainingTasks})
+
+	return &DrainSwarmNodeOutput{Body: base.ApiResponse[swarmtypes.NodeDrainResponse]{Success: true, Data: *resp}}, nil
+}
+
 // DeleteNode removes a swarm node from the cluster.
 //
 // It requires admin privileges, supports forced removal when requested, and
@@ -1047,7 +1530,7 @@ func (h *SwarmHandler) UpdateNode(ctx context.Context, input *UpdateSwarmNodeInp
 // Returns an authorization error for non-admin callers or a mapped HTTP error
 // when the node cannot be removed.
 func (h *SwarmHandler) DeleteNode(ctx context.Context, input *DeleteSwarmNodeInput) (*DeleteSwarmNodeOutput, error) {
-	if err := h.swarmService.RemoveNode(ctx, input.NodeID, input.Force); err != nil {
+	if err := h.swarmService.RemoveNode(ctx, input.EnvironmentID, input.NodeID, input.Force); err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Swarm node not found").Error())
 	}
 
@@ -1068,7 +1551,7 @@ func (h *SwarmHandler) DeleteNode(ctx context.Context, input *DeleteSwarmNodeInp
 // Returns an authorization error for non-admin callers or a mapped HTTP error
 // when the promotion fails.
 func (h *SwarmHandler) PromoteNode(ctx context.Context, input *PromoteSwarmNodeInput) (*PromoteSwarmNodeOutput, error) {
-	if err := h.swarmService.PromoteNode(ctx, input.NodeID); err != nil {
+	if err := h.swarmService.PromoteNode(ctx, input.EnvironmentID, input.NodeID); err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Swarm node not found").Error())
 	}
 
@@ -1089,7 +1572,7 @@ func (h *SwarmHandler) PromoteNode(ctx context.Context, input *PromoteSwarmNodeI
 // Returns an authorization error for non-admin callers or a mapped HTTP error
 // when the demotion fails.
 func (h *SwarmHandler) DemoteNode(ctx context.Context, input *DemoteSwarmNodeInput) (*DemoteSwarmNodeOutput, error) {
-	if err := h.swarmService.DemoteNode(ctx, input.NodeID); err != nil {
+	if err := h.swarmService.DemoteNode(ctx, input.EnvironmentID, input.NodeID); err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Swarm node not found").Error())
 	}
 
@@ -1110,7 +1593,7 @@ func (h *SwarmHandler) DemoteNode(ctx context.Context, input *DemoteSwarmNodeInp
 // Returns a mapped HTTP error when the underlying lookup fails.
 func (h *SwarmHandler) ListNodeTasks(ctx context.Context, input *ListSwarmNodeTasksInput) (*ListSwarmNodeTasksOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-	items, paginationResp, err := h.swarmService.ListNodeTasksPaginated(ctx, input.NodeID, params)
+	items, paginationResp, err := h.swarmService.ListNodeTasksPaginated(ctx, input.EnvironmentID, input.NodeID, params)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to list swarm tasks").Error())
 	}
@@ -1133,7 +1616,7 @@ func (h *SwarmHandler) ListNodeTasks(ctx context.Context, input *ListSwarmNodeTa
 // Returns a mapped HTTP error when task enumeration fails.
 func (h *SwarmHandler) ListTasks(ctx context.Context, input *ListSwarmTasksInput) (*ListSwarmTasksOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-	items, paginationResp, err := h.swarmService.ListTasksPaginated(ctx, params)
+	items, paginationResp, err := h.swarmService.ListTasksPaginated(ctx, input.EnvironmentID, params)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to list swarm tasks").Error())
 	}
@@ -1144,6 +1627,78 @@ func (h *SwarmHandler) ListTasks(ctx context.Context, input *ListSwarmTasksInput
 	return &ListSwarmTasksOutput{Body: base.Paginated[swarmtypes.TaskSummary]{Success: true, Data: items, Pagination: toPaginationResponseInternal(paginationResp)}}, nil
 }
 
+// ExportTasks streams every swarm task matching the search as CSV.
+func (h *SwarmHandler) ExportTasks(ctx context.Context, input *ExportSwarmTasksInput) (*huma.StreamResponse, error) {
+	params := buildPaginationParamsInternal(0, -1, input.Sort, input.Order, input.Search)
+	items, _, err := h.swarmService.ListTasksPaginated(ctx, input.EnvironmentID, params)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to export swarm tasks").Error())
+	}
+
+	return csvExportResponseInternal("swarm-tasks.csv", items, h.swarmService.TaskPaginationConfig()), nil
+}
+
+// GetTaskLogs streams one swarm task's log output as plain text.
+//
+// It is the drill-down from a failed task in the tasks list: exited tasks
+// return their last lines, running tasks keep streaming when follow is set.
+// Nothing is written until the first line or error arrives, so a missing task
+// or non-manager node still gets its mapped HTTP status instead of an empty 200.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the task and the tail, since and follow options.
+//
+// Returns a streaming text/plain response.
+// Returns a mapped HTTP status and message when the logs cannot be read.
+func (h *SwarmHandler) GetTaskLogs(ctx context.Context, input *GetSwarmTaskLogsInput) (*huma.StreamResponse, error) {
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) { //nolint:contextcheck // context is obtained from humaCtx.Context()
+			streamCtx, cancel := context.WithCancel(humaCtx.Context())
+			defer cancel()
+
+			logsChan := make(chan string, 256)
+			errChan := make(chan error, 1)
+			go func() {
+				defer close(logsChan)
+				errChan <- h.swarmService.StreamTaskLogs(streamCtx, input.EnvironmentID, input.TaskID, logsChan, input.Follow, input.Tail, input.Since, input.Timestamps)
+			}()
+
+			writer := humaCtx.BodyWriter()
+			started := false
+			for line := range logsChan {
+				if !started {
+					humaCtx.SetHeader("Content-Type", "text/plain; charset=utf-8")
+					started = true
+				}
+				if _, err := io.WriteString(writer, line+"\n"); err != nil {
+					return
+				}
+				if f, ok := writer.(http.Flusher); ok {
+					f.Flush()
+				}
+			}
+
+			err := <-errChan
+			if err == nil || errors.Is(err, context.Canceled) {
+				return
+			}
+			if started {
+				slog.WarnContext(ctx, "Swarm task log stream ended with error", "taskID", input.TaskID, "error", err)
+				return
+			}
+			mapped := mapSwarmServiceError(err, errors.WithMessage(err, "Failed to get swarm task logs").Error())
+			status := http.StatusInternalServerError
+			var statusErr huma.StatusError
+			if errors.As(mapped, &statusErr) {
+				status = statusErr.GetStatus()
+			}
+			humaCtx.SetHeader("Content-Type", "text/plain; charset=utf-8")
+			humaCtx.SetStatus(status)
+			_, _ = io.WriteString(writer, mapped.Error())
+		},
+	}, nil
+}
+
 // ListStacks lists swarm stacks for the current environment.
 //
 // It applies search, sort, and pagination values supplied by the caller and
@@ -1171,7 +1726,8 @@ func (h *SwarmHandler) ListStacks(ctx context.Context, input *ListSwarmStacksInp
 //
 // It requires admin privileges, submits the stack deployment request to the
 // swarm service, and records an audit event keyed by the stack name after the
-// deployment succeeds.
+// deployment succeeds. When the request sets dryRun, nothing is applied or
+// audited and the response carries the computed deployment plan instead.
 //
 // ctx carries request-scoped cancellation, auth, and audit context.
 // input identifies the target environment and provides the stack deployment request body.
@@ -1185,11 +1741,86 @@ func (h *SwarmHandler) DeployStack(ctx context.Context, input *DeploySwarmStackI
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to deploy swarm stack").Error())
 	}
 
+	if input.Body.DryRun {
+		return &DeploySwarmStackOutput{Body: base.ApiResponse[swarmtypes.StackDeployResponse]{Success: true, Data: *resp}}, nil
+	}
+
 	h.auditSwarmMutation(ctx, input.EnvironmentID, "stack.deploy", "swarm_stack", input.Body.Name, input.Body.Name, map[string]any{"stack": input.Body.Name})
 
 	return &DeploySwarmStackOutput{Body: base.ApiResponse[swarmtypes.StackDeployResponse]{Success: true, Data: *resp}}, nil
 }
 
+// ExportStacks streams the saved source of every stack in the environment as a
+// gzip-compressed tar bundle.
+//
+// The bundle is built before the response starts so a read failure is still
+// reported as an HTTP error.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment whose stack sources should be exported.
+//
+// Returns the bundle as an attachment.
+// Returns a mapped HTTP error when a stack source cannot be read.
+func (h *SwarmHandler) ExportStacks(ctx context.Context, input *ExportSwarmStacksInput) (*huma.StreamResponse, error) {
+	var bundle bytes.Buffer
+	count, err := h.swarmService.ExportStacks(ctx, input.EnvironmentID, &bundle)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to export swarm stacks").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "stack.export", "swarm_stack", "", "", map[string]any{"stacks": count})
+
+	fileName := fmt.Sprintf("swarm-stacks-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) { //nolint:contextcheck // context is obtained from humaCtx.Context()
+			humaCtx.SetHeader("Content-Type", "application/gzip")
+			humaCtx.SetHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+			humaCtx.SetHeader("Content-Length", strconv.Itoa(bundle.Len()))
+
+			if _, writeErr := humaCtx.BodyWriter().Write(bundle.Bytes()); writeErr != nil {
+				slog.WarnContext(humaCtx.Context(), "Failed to stream swarm stack bundle", "environmentID", input.EnvironmentID, "error", writeErr)
+			}
+		},
+	}, nil
+}
+
+// ImportStacks deploys every stack in an uploaded bundle produced by
+// ExportStacks.
+//
+// With dryRun set, each stack is planned and nothing is changed. Per-stack
+// failures are reported in the response rather than failing the request.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment and carries the uploaded bundle file.
+//
+// Returns the result of each stack in the bundle.
+// Returns `400 Bad Request` when the bundle is missing or malformed, or another
+// mapped HTTP error when the environment is not a swarm manager.
+func (h *SwarmHandler) ImportStacks(ctx context.Context, input *ImportSwarmStacksInput) (*ImportSwarmStacksOutput, error) {
+	file, fileHeader, err := openUploadedFileInternal(input.RawBody)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	resp, err := h.swarmService.ImportStacks(ctx, input.EnvironmentID, file, input.DryRun)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to import swarm stacks").Error())
+	}
+
+	if !input.DryRun {
+		deployed := make([]string, 0, len(resp.Stacks))
+		for _, result := range resp.Stacks {
+			if result.Deployed {
+				deployed = append(deployed, result.Name)
+			}
+		}
+		h.auditSwarmMutation(ctx, input.EnvironmentID, "stack.import", "swarm_stack", "", "", map[string]any{"filename": fileHeader.Filename, "stacks": deployed})
+	}
+
+	return &ImportSwarmStacksOutput{Body: base.ApiResponse[swarmtypes.StackImportResponse]{Success: true, Data: *resp}}, nil
+}
+
 // GetStack returns detailed information for a specific swarm stack.
 //
 // It looks up the stack by name through the swarm service and maps missing
@@ -1213,6 +1844,25 @@ func (h *SwarmHandler) GetStack(ctx context.Context, input *GetSwarmStackInput)
 	return &GetSwarmStackOutput{Body: base.ApiResponse[swarmtypes.StackInspect]{Success: true, Data: *stack}}, nil
 }
 
+// ListOrphanedStackSources returns saved stack sources without a deployed stack.
+//
+// Sources become orphaned when a stack is removed outside Arcane or the swarm
+// is rebuilt; listing them lets users redeploy or clean them up.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment whose sources should be checked.
+//
+// Returns the orphaned sources sorted by name.
+// Returns a mapped HTTP error when listing services or sources fails.
+func (h *SwarmHandler) ListOrphanedStackSources(ctx context.Context, input *ListOrphanedSwarmStackSourcesInput) (*ListOrphanedSwarmStackSourcesOutput, error) {
+	sources, err := h.swarmService.ListOrphanedStackSources(ctx, input.EnvironmentID)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, "Failed to list orphaned swarm stack sources")
+	}
+
+	return &ListOrphanedSwarmStackSourcesOutput{Body: base.ApiResponse[[]swarmtypes.OrphanedStackSource]{Success: true, Data: sources}}, nil
+}
+
 // GetStackSource returns the stored source content for a swarm stack.
 //
 // It requires admin privileges because stack source content can include
@@ -1252,11 +1902,76 @@ func (h *SwarmHandler) UpdateStackSource(ctx context.Context, input *UpdateSwarm
 	return &UpdateSwarmStackSourceOutput{Body: base.ApiResponse[swarmtypes.StackSource]{Success: true, Data: *source}}, nil
 }
 
+// GetStackHistory lists the saved source snapshots of a swarm stack.
+//
+// A snapshot is recorded whenever the stack source is deployed or saved with
+// changed content, up to the configured retention count. Access requires the
+// same permission as reading the source because snapshots include env files.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment and stack whose history should be listed.
+//
+// Returns the snapshots ordered newest first, or an empty list when none exist.
+// Returns a mapped HTTP error when the history cannot be read.
+func (h *SwarmHandler) GetStackHistory(ctx context.Context, input *GetSwarmStackHistoryInput) (*GetSwarmStackHistoryOutput, error) {
+	versions, err := h.swarmService.GetStackSourceHistory(ctx, input.EnvironmentID, input.Name)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, "Failed to load swarm stack history")
+	}
+
+	return &GetSwarmStackHistoryOutput{Body: base.ApiResponse[[]swarmtypes.StackSourceVersion]{Success: true, Data: versions}}, nil
+}
+
+// GetStackHistoryDiff returns a unified diff between two swarm stack source versions.
+//
+// Versions are snapshot IDs from the history listing; "current" refers to the
+// live source on disk and is the default for the compared side.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the stack and the two versions to compare.
+//
+// Returns the unified diff of the compose, override, and env files.
+// Returns `404 Not Found` when a version does not exist and `400 Bad Request`
+// when a version ID is malformed.
+func (h *SwarmHandler) GetStackHistoryDiff(ctx context.Context, input *GetSwarmStackHistoryDiffInput) (*GetSwarmStackHistoryDiffOutput, error) {
+	diff, err := h.swarmService.GetStackSourceDiff(ctx, input.EnvironmentID, input.Name, input.From, input.To)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to diff swarm stack source").Error())
+	}
+
+	return &GetSwarmStackHistoryDiffOutput{Body: base.ApiResponse[swarmtypes.StackSourceDiff]{Success: true, Data: *diff}}, nil
+}
+
+// GetStackRemovalPlan previews what deleting a swarm stack would remove.
+//
+// Nothing is changed; the plan lists the stack's services, configs, secrets
+// and networks, with networks still used by services outside the stack (and
+// the ingress network) reported as kept.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment and stack name to plan for.
+//
+// Returns the removal plan when the stack exists.
+// Returns `404 Not Found` when the stack does not exist or another mapped HTTP
+// error when listing the stack's resources fails.
+func (h *SwarmHandler) GetStackRemovalPlan(ctx context.Context, input *GetSwarmStackRemovalPlanInput) (*GetSwarmStackRemovalPlanOutput, error) {
+	plan, err := h.swarmService.PlanStackRemoval(ctx, input.EnvironmentID, input.Name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound("Swarm stack not found")
+		}
+		return nil, mapSwarmServiceError(err, "Failed to plan swarm stack removal")
+	}
+
+	return &GetSwarmStackRemovalPlanOutput{Body: base.ApiResponse[swarmtypes.StackRemovalPlan]{Success: true, Data: *plan}}, nil
+}
+
 // DeleteStack removes a swarm stack and its managed resources.
 //
 // It requires admin privileges, delegates the removal to the swarm service,
 // maps missing stacks to `404 Not Found`, and records an audit event after
-// deletion completes.
+// deletion completes. Networks shared with services outside the stack are kept;
+// GetStackRemovalPlan previews exactly what is removed.
 //
 // ctx carries request-scoped cancellation, auth, and audit context.
 // input identifies the environment and stack name to remove.
@@ -1277,6 +1992,30 @@ func (h *SwarmHandler) DeleteStack(ctx context.Context, input *DeleteSwarmStackI
 	return &DeleteSwarmStackOutput{Body: base.ApiResponse[base.MessageResponse]{Success: true, Data: base.MessageResponse{Message: "Swarm stack removed successfully"}}}, nil
 }
 
+// ScaleStack changes the replica counts of several services in a swarm stack.
+//
+// Every requested service is validated before any update is applied, so an
+// unknown or non-replicated service rejects the whole request. Once validation
+// passes, each service is scaled independently and its outcome is reported in
+// the response; the audit event records the requested replica targets.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the stack and supplies desired replica counts by service name.
+//
+// Returns a successful response containing one result per requested service.
+// Returns `404 Not Found` when the stack or a service is missing and
+// `400 Bad Request` when a service cannot be scaled.
+func (h *SwarmHandler) ScaleStack(ctx context.Context, input *ScaleSwarmStackInput) (*ScaleSwarmStackOutput, error) {
+	results, err := h.swarmService.ScaleStack(ctx, input.EnvironmentID, input.Name, input.Body.Services)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to scale swarm stack").Error())
+	}
+
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "stack.scale", "swarm_stack", input.Name, input.Name, map[string]any{"stack": input.Name, "services": input.Body.Services})
+
+	return &ScaleSwarmStackOutput{Body: base.ApiResponse[[]swarmtypes.StackScaleResult]{Success: true, Data: results}}, nil
+}
+
 // ListStackServices lists services belonging to a swarm stack.
 //
 // It applies search, sort, and pagination options, ensures the response uses an
@@ -1290,7 +2029,7 @@ func (h *SwarmHandler) DeleteStack(ctx context.Context, input *DeleteSwarmStackI
 // error when the lookup fails.
 func (h *SwarmHandler) ListStackServices(ctx context.Context, input *ListSwarmStackServicesInput) (*ListSwarmStackServicesOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-	items, paginationResp, err := h.swarmService.ListStackServicesPaginated(ctx, input.Name, params)
+	items, paginationResp, err := h.swarmService.ListStackServicesPaginated(ctx, input.EnvironmentID, input.Name, params)
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound("Swarm stack not found")
@@ -1317,7 +2056,7 @@ func (h *SwarmHandler) ListStackServices(ctx context.Context, input *ListSwarmSt
 // error when the lookup fails.
 func (h *SwarmHandler) ListStackTasks(ctx context.Context, input *ListSwarmStackTasksInput) (*ListSwarmStackTasksOutput, error) {
 	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
-	items, paginationResp, err := h.swarmService.ListStackTasksPaginated(ctx, input.Name, params)
+	items, paginationResp, err := h.swarmService.ListStackTasksPaginated(ctx, input.EnvironmentID, input.Name, params)
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound("Swarm stack not found")
@@ -1342,7 +2081,7 @@ func (h *SwarmHandler) ListStackTasks(ctx context.Context, input *ListSwarmStack
 // Returns the rendered compose content together with discovered resource names.
 // Returns a mapped HTTP error when parsing, interpolation, or rendering fails.
 func (h *SwarmHandler) RenderStackConfig(ctx context.Context, input *RenderSwarmStackConfigInput) (*RenderSwarmStackConfigOutput, error) {
-	resp, err := h.swarmService.RenderStackConfig(ctx, input.Body)
+	resp, err := h.swarmService.RenderStackConfig(ctx, input.EnvironmentID, input.Body)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to render swarm stack config")
 	}
@@ -1363,6 +2102,9 @@ func (h *SwarmHandler) RenderStackConfig(ctx context.Context, input *RenderSwarm
 func (h *SwarmHandler) GetSwarmStatus(ctx context.Context, input *GetSwarmStatusInput) (*GetSwarmStatusOutput, error) {
 	enabled, err := h.swarmService.IsEnabled(ctx)
 	if err != nil {
+		if apiErr := dockerUnavailableErrorInternal(err); apiErr != nil {
+			return nil, apiErr
+		}
 		return nil, huma.Error500InternalServerError("failed to read swarm status")
 	}
 
@@ -1374,6 +2116,25 @@ func (h *SwarmHandler) GetSwarmStatus(ctx context.Context, input *GetSwarmStatus
 	}, nil
 }
 
+// GetClusterResources returns the swarm's CPU and memory capacity.
+//
+// It delegates to the swarm service, which sums node resources and subtracts
+// the reservations of running tasks.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment whose swarm should be summarized.
+//
+// Returns total, allocated and available capacity for the cluster and per node.
+// Returns a mapped HTTP error when swarm mode is unavailable or listing fails.
+func (h *SwarmHandler) GetClusterResources(ctx context.Context, input *GetSwarmResourcesInput) (*GetSwarmResourcesOutput, error) {
+	resources, err := h.swarmService.GetClusterResources(ctx, input.EnvironmentID)
+	if err != nil {
+		return nil, mapSwarmServiceError(err, "Failed to get swarm cluster resources")
+	}
+
+	return &GetSwarmResourcesOutput{Body: base.ApiResponse[swarmtypes.ClusterResources]{Success: true, Data: *resources}}, nil
+}
+
 // GetSwarmInfo returns the current swarm cluster metadata for an environment.
 //
 // It delegates to the swarm service to inspect the local swarm state and maps
@@ -1385,7 +2146,7 @@ func (h *SwarmHandler) GetSwarmStatus(ctx context.Context, input *GetSwarmStatus
 // Returns the current swarm information when swarm mode is available.
 // Returns a mapped HTTP error when swarm inspection fails.
 func (h *SwarmHandler) GetSwarmInfo(ctx context.Context, input *GetSwarmInfoInput) (*GetSwarmInfoOutput, error) {
-	info, err := h.swarmService.GetSwarmInfo(ctx)
+	info, err := h.swarmService.GetSwarmInfo(ctx, input.EnvironmentID)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to inspect swarm").Error())
 	}
@@ -1405,7 +2166,7 @@ func (h *SwarmHandler) GetSwarmInfo(ctx context.Context, input *GetSwarmInfoInpu
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when initialization fails.
 func (h *SwarmHandler) InitSwarm(ctx context.Context, input *InitSwarmInput) (*InitSwarmOutput, error) {
-	resp, err := h.swarmService.InitSwarm(ctx, input.Body)
+	resp, err := h.swarmService.InitSwarm(ctx, input.EnvironmentID, input.Body)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to initialize swarm")
 	}
@@ -1427,7 +2188,7 @@ func (h *SwarmHandler) InitSwarm(ctx context.Context, input *InitSwarmInput) (*I
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when the join operation fails.
 func (h *SwarmHandler) JoinSwarm(ctx context.Context, input *JoinSwarmInput) (*JoinSwarmOutput, error) {
-	if err := h.swarmService.JoinSwarm(ctx, input.Body); err != nil {
+	if err := h.swarmService.JoinSwarm(ctx, input.EnvironmentID, input.Body); err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to join swarm")
 	}
 
@@ -1538,7 +2299,7 @@ func requireEasyJoinManagerPermissionsInternal(ctx context.Context, environmentI
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when the leave operation fails.
 func (h *SwarmHandler) LeaveSwarm(ctx context.Context, input *LeaveSwarmInput) (*LeaveSwarmOutput, error) {
-	if err := h.swarmService.LeaveSwarm(ctx, input.Body); err != nil {
+	if err := h.swarmService.LeaveSwarm(ctx, input.EnvironmentID, input.Body); err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to leave swarm")
 	}
 
@@ -1559,7 +2320,7 @@ func (h *SwarmHandler) LeaveSwarm(ctx context.Context, input *LeaveSwarmInput) (
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when the unlock operation fails.
 func (h *SwarmHandler) UnlockSwarm(ctx context.Context, input *UnlockSwarmInput) (*UnlockSwarmOutput, error) {
-	if err := h.swarmService.UnlockSwarm(ctx, input.Body); err != nil {
+	if err := h.swarmService.UnlockSwarm(ctx, input.EnvironmentID, input.Body); err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to unlock swarm")
 	}
 
@@ -1579,7 +2340,7 @@ func (h *SwarmHandler) UnlockSwarm(ctx context.Context, input *UnlockSwarmInput)
 // Returns the current manager unlock key.
 // Returns a mapped HTTP error when the unlock key cannot be retrieved.
 func (h *SwarmHandler) GetUnlockKey(ctx context.Context, input *GetSwarmUnlockKeyInput) (*GetSwarmUnlockKeyOutput, error) {
-	resp, err := h.swarmService.GetSwarmUnlockKey(ctx)
+	resp, err := h.swarmService.GetSwarmUnlockKey(ctx, input.EnvironmentID)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to get swarm unlock key")
 	}
@@ -1598,7 +2359,7 @@ func (h *SwarmHandler) GetUnlockKey(ctx context.Context, input *GetSwarmUnlockKe
 // Returns the current worker and manager join tokens.
 // Returns a mapped HTTP error when token lookup fails.
 func (h *SwarmHandler) GetJoinTokens(ctx context.Context, input *GetSwarmJoinTokensInput) (*GetSwarmJoinTokensOutput, error) {
-	resp, err := h.swarmService.GetSwarmJoinTokens(ctx)
+	resp, err := h.swarmService.GetSwarmJoinTokens(ctx, input.EnvironmentID)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to get swarm join tokens")
 	}
@@ -1618,7 +2379,7 @@ func (h *SwarmHandler) GetJoinTokens(ctx context.Context, input *GetSwarmJoinTok
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when token rotation fails.
 func (h *SwarmHandler) RotateJoinTokens(ctx context.Context, input *RotateSwarmJoinTokensInput) (*RotateSwarmJoinTokensOutput, error) {
-	if err := h.swarmService.RotateSwarmJoinTokens(ctx, input.Body); err != nil {
+	if err := h.swarmService.RotateSwarmJoinTokens(ctx, input.EnvironmentID, input.Body); err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to rotate swarm join tokens")
 	}
 
@@ -1639,7 +2400,7 @@ func (h *SwarmHandler) RotateJoinTokens(ctx context.Context, input *RotateSwarmJ
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when the spec update fails.
 func (h *SwarmHandler) UpdateSwarmSpec(ctx context.Context, input *UpdateSwarmSpecInput) (*UpdateSwarmSpecOutput, error) {
-	if err := h.swarmService.UpdateSwarmSpec(ctx, input.Body); err != nil {
+	if err := h.swarmService.UpdateSwarmSpec(ctx, input.EnvironmentID, input.Body); err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to update swarm spec")
 	}
 
@@ -1648,26 +2409,29 @@ func (h *SwarmHandler) UpdateSwarmSpec(ctx context.Context, input *UpdateSwarmSp
 	return &UpdateSwarmSpecOutput{Body: base.ApiResponse[base.MessageResponse]{Success: true, Data: base.MessageResponse{Message: "Swarm spec updated successfully"}}}, nil
 }
 
-// ListConfigs lists swarm configs in the current environment.
+// ListConfigs lists swarm configs in the current environment and returns a
+// paginated response.
 //
-// It delegates to the swarm service and normalizes nil config slices to empty
-// arrays in the response body.
+// It delegates to the swarm service, which searches configs by name, ID and
+// labels, and normalizes nil config slices to empty arrays in the response body.
 //
 // ctx carries request-scoped cancellation and auth context.
-// input identifies the environment whose configs should be listed.
+// input identifies the environment and carries search, sort and pagination
+// parameters.
 //
-// Returns the current swarm configs.
+// Returns the requested page of swarm configs.
 // Returns a mapped HTTP error when config enumeration fails.
 func (h *SwarmHandler) ListConfigs(ctx context.Context, input *ListSwarmConfigsInput) (*ListSwarmConfigsOutput, error) {
-	items, err := h.swarmService.ListConfigs(ctx)
+	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
+	items, paginationResp, err := h.swarmService.ListConfigsPaginated(ctx, input.EnvironmentID, params)
 	if err != nil {
-		return nil, mapSwarmServiceError(err, "Failed to list swarm configs")
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to list swarm configs").Error())
 	}
 	if items == nil {
 		items = []swarmtypes.ConfigSummary{}
 	}
 
-	return &ListSwarmConfigsOutput{Body: base.ApiResponse[[]swarmtypes.ConfigSummary]{Success: true, Data: items}}, nil
+	return &ListSwarmConfigsOutput{Body: base.Paginated[swarmtypes.ConfigSummary]{Success: true, Data: items, Pagination: toPaginationResponseInternal(paginationResp)}}, nil
 }
 
 // GetConfig returns details for a single swarm config.
@@ -1682,7 +2446,7 @@ func (h *SwarmHandler) ListConfigs(ctx context.Context, input *ListSwarmConfigsI
 // Returns `404 Not Found` when the config does not exist or another mapped HTTP
 // error when inspection fails.
 func (h *SwarmHandler) GetConfig(ctx context.Context, input *GetSwarmConfigInput) (*GetSwarmConfigOutput, error) {
-	cfg, err := h.swarmService.GetConfig(ctx, input.ConfigID)
+	cfg, err := h.swarmService.GetConfig(ctx, input.EnvironmentID, input.ConfigID)
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound("Swarm config not found")
@@ -1693,6 +2457,31 @@ func (h *SwarmHandler) GetConfig(ctx context.Context, input *GetSwarmConfigInput
 	return &GetSwarmConfigOutput{Body: base.ApiResponse[swarmtypes.ConfigSummary]{Success: true, Data: *cfg}}, nil
 }
 
+// GetConfigUsage lists the services that mount a swarm config.
+//
+// It delegates to the swarm service, which scans every service's task
+// template for references to the config, and maps a missing config to
+// `404 Not Found`. Callers use it before deletion to see which services would
+// break on their next task restart.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment and config to look up.
+//
+// Returns one entry per mount, including the target file and mode.
+// Returns `404 Not Found` when the config does not exist or another mapped HTTP
+// error when inspection fails.
+func (h *SwarmHandler) GetConfigUsage(ctx context.Context, input *GetSwarmConfigUsageInput) (*GetSwarmConfigUsageOutput, error) {
+	usage, err := h.swarmService.GetConfigUsage(ctx, input.EnvironmentID, input.ConfigID)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound("Swarm config not found")
+		}
+		return nil, mapSwarmServiceError(err, "Failed to inspect swarm config usage")
+	}
+
+	return &GetSwarmConfigUsageOutput{Body: base.ApiResponse[[]swarmtypes.ServiceMountUsage]{Success: true, Data: usage}}, nil
+}
+
 // CreateConfig creates a new swarm config.
 //
 // It requires admin privileges, delegates the creation request to the swarm
@@ -1705,7 +2494,7 @@ func (h *SwarmHandler) GetConfig(ctx context.Context, input *GetSwarmConfigInput
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when validation or creation fails.
 func (h *SwarmHandler) CreateConfig(ctx context.Context, input *CreateSwarmConfigInput) (*CreateSwarmConfigOutput, error) {
-	cfg, err := h.swarmService.CreateConfig(ctx, input.Body)
+	cfg, err := h.swarmService.CreateConfig(ctx, input.EnvironmentID, input.Body)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to create swarm config")
 	}
@@ -1746,7 +2535,7 @@ func (h *SwarmHandler) UpdateConfig(ctx context.Context, input *UpdateSwarmConfi
 // Returns an authorization error for non-admin callers, `404 Not Found` when
 // the config does not exist, or another mapped HTTP error when removal fails.
 func (h *SwarmHandler) DeleteConfig(ctx context.Context, input *DeleteSwarmConfigInput) (*DeleteSwarmConfigOutput, error) {
-	if err := h.swarmService.RemoveConfig(ctx, input.ConfigID); err != nil {
+	if err := h.swarmService.RemoveConfig(ctx, input.EnvironmentID, input.ConfigID); err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound("Swarm config not found")
 		}
@@ -1758,26 +2547,29 @@ func (h *SwarmHandler) DeleteConfig(ctx context.Context, input *DeleteSwarmConfi
 	return &DeleteSwarmConfigOutput{Body: base.ApiResponse[base.MessageResponse]{Success: true, Data: base.MessageResponse{Message: "Swarm config removed successfully"}}}, nil
 }
 
-// ListSecrets lists swarm secrets in the current environment.
+// ListSecrets lists swarm secrets in the current environment and returns a
+// paginated response.
 //
-// It delegates to the swarm service and normalizes nil secret slices to empty
-// arrays in the response body.
+// It delegates to the swarm service, which searches secrets by name, ID and
+// labels, and normalizes nil secret slices to empty arrays in the response body.
 //
 // ctx carries request-scoped cancellation and auth context.
-// input identifies the environment whose secrets should be listed.
+// input identifies the environment and carries search, sort and pagination
+// parameters.
 //
-// Returns the current swarm secrets.
+// Returns the requested page of swarm secrets.
 // Returns a mapped HTTP error when secret enumeration fails.
 func (h *SwarmHandler) ListSecrets(ctx context.Context, input *ListSwarmSecretsInput) (*ListSwarmSecretsOutput, error) {
-	items, err := h.swarmService.ListSecrets(ctx)
+	params := buildPaginationParamsInternal(input.Start, input.Limit, input.Sort, input.Order, input.Search)
+	items, paginationResp, err := h.swarmService.ListSecretsPaginated(ctx, input.EnvironmentID, params)
 	if err != nil {
-		return nil, mapSwarmServiceError(err, "Failed to list swarm secrets")
+		return nil, mapSwarmServiceError(err, errors.WithMessage(err, "Failed to list swarm secrets").Error())
 	}
 	if items == nil {
 		items = []swarmtypes.SecretSummary{}
 	}
 
-	return &ListSwarmSecretsOutput{Body: base.ApiResponse[[]swarmtypes.SecretSummary]{Success: true, Data: items}}, nil
+	return &ListSwarmSecretsOutput{Body: base.Paginated[swarmtypes.SecretSummary]{Success: true, Data: items, Pagination: toPaginationResponseInternal(paginationResp)}}, nil
 }
 
 // GetSecret returns details for a single swarm secret.
@@ -1792,7 +2584,7 @@ func (h *SwarmHandler) ListSecrets(ctx context.Context, input *ListSwarmSecretsI
 // Returns `404 Not Found` when the secret does not exist or another mapped HTTP
 // error when inspection fails.
 func (h *SwarmHandler) GetSecret(ctx context.Context, input *GetSwarmSecretInput) (*GetSwarmSecretOutput, error) {
-	secret, err := h.swarmService.GetSecret(ctx, input.SecretID)
+	secret, err := h.swarmService.GetSecret(ctx, input.EnvironmentID, input.SecretID)
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound("Swarm secret not found")
@@ -1803,6 +2595,31 @@ func (h *SwarmHandler) GetSecret(ctx context.Context, input *GetSwarmSecretInput
 	return &GetSwarmSecretOutput{Body: base.ApiResponse[swarmtypes.SecretSummary]{Success: true, Data: *secret}}, nil
 }
 
+// GetSecretUsage lists the services that mount a swarm secret.
+//
+// It delegates to the swarm service, which scans every service's task
+// template for references to the secret, and maps a missing secret to
+// `404 Not Found`. Callers use it before deletion to see which services would
+// break on their next task restart.
+//
+// ctx carries request-scoped cancellation and auth context.
+// input identifies the environment and secret to look up.
+//
+// Returns one entry per mount, including the target file and mode.
+// Returns `404 Not Found` when the secret does not exist or another mapped HTTP
+// error when inspection fails.
+func (h *SwarmHandler) GetSecretUsage(ctx context.Context, input *GetSwarmSecretUsageInput) (*GetSwarmSecretUsageOutput, error) {
+	usage, err := h.swarmService.GetSecretUsage(ctx, input.EnvironmentID, input.SecretID)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound("Swarm secret not found")
+		}
+		return nil, mapSwarmServiceError(err, "Failed to inspect swarm secret usage")
+	}
+
+	return &GetSwarmSecretUsageOutput{Body: base.ApiResponse[[]swarmtypes.ServiceMountUsage]{Success: true, Data: usage}}, nil
+}
+
 // CreateSecret creates a new swarm secret.
 //
 // It requires admin privileges, delegates the creation request to the swarm
@@ -1815,7 +2632,7 @@ func (h *SwarmHandler) GetSecret(ctx context.Context, input *GetSwarmSecretInput
 // Returns an authorization error for non-admin callers or mapped HTTP errors
 // when validation or creation fails.
 func (h *SwarmHandler) CreateSecret(ctx context.Context, input *CreateSwarmSecretInput) (*CreateSwarmSecretOutput, error) {
-	secret, err := h.swarmService.CreateSecret(ctx, input.Body)
+	secret, err := h.swarmService.CreateSecret(ctx, input.EnvironmentID, input.Body)
 	if err != nil {
 		return nil, mapSwarmServiceError(err, "Failed to create swarm secret")
 	}
@@ -1844,6 +2661,46 @@ func (h *SwarmHandler) UpdateSecret(ctx context.Context, input *UpdateSwarmSecre
 	return &UpdateSwarmSecretOutput{}, nil
 }
 
+// RotateSecret replaces a swarm secret with a new version.
+//
+// It delegates to the swarm service, which creates the new secret, updates
+// every service referencing the old one, and removes the old secret once no
+// tasks still mount it. An audit event records the old and new secret IDs and
+// the updated services, or the error when rotation fails and is rolled back.
+//
+// ctx carries request-scoped cancellation, auth, and audit context.
+// input identifies the secret to rotate and contains the new secret value.
+//
+// Returns the new secret, the updated services, and whether the old secret was
+// removed.
+// Returns `404 Not Found` when the secret does not exist or another mapped HTTP
+// error when rotation fails.
+func (h *SwarmHandler) RotateSecret(ctx context.Context, input *RotateSwarmSecretInput) (*RotateSwarmSecretOutput, error) {
+	result, err := h.swarmService.RotateSecret(ctx, input.EnvironmentID, input.SecretID, input.Body.Data)
+	if err != nil {
+		h.auditSwarmMutationFailure(ctx, input.EnvironmentID, "secret.rotate", "swarm_secret", input.SecretID, "", err, map[string]any{
+			"secretId": input.SecretID,
+		})
+		if errdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound("Swarm secret not found")
+		}
+		return nil, mapSwarmServiceError(err, "Failed to rotate swarm secret")
+	}
+
+	serviceIDs := make([]string, 0, len(result.UpdatedServices))
+	for _, service := range result.UpdatedServices {
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+	h.auditSwarmMutation(ctx, input.EnvironmentID, "secret.rotate", "swarm_secret", result.Secret.ID, result.Secret.Spec.Name, map[string]any{
+		"secretId":              result.Secret.ID,
+		"previousSecretId":      result.PreviousSecretID,
+		"previousSecretRemoved": result.PreviousSecretRemoved,
+		"serviceIds":            serviceIDs,
+	})
+
+	return &RotateSwarmSecretOutput{Body: base.ApiResponse[swarmtypes.SecretRotateResponse]{Success: true, Data: *result}}, nil
+}
+
 // DeleteSecret removes a swarm secret.
 //
 // It requires admin privileges, delegates removal to the swarm service, maps
@@ -1856,7 +2713,7 @@ func (h *SwarmHandler) UpdateSecret(ctx context.Context, input *UpdateSwarmSecre
 // Returns an authorization error for non-admin callers, `404 Not Found` when
 // the secret does not exist, or another mapped HTTP error when removal fails.
 func (h *SwarmHandler) DeleteSecret(ctx context.Context, input *DeleteSwarmSecretInput) (*DeleteSwarmSecretOutput, error) {
-	if err := h.swarmService.RemoveSecret(ctx, input.SecretID); err != nil {
+	if err := h.swarmService.RemoveSecret(ctx, input.EnvironmentID, input.SecretID); err != nil {
 		if errdefs.IsNotFound(err) {
 			return nil, huma.Error404NotFound("Swarm secret not found")
 		}
@@ -1882,6 +2739,18 @@ func (h *SwarmHandler) DeleteSecret(ctx context.Context, input *DeleteSwarmSecre
 // resourceName provides a human-readable resource name when one exists.
 // metadata supplies additional structured audit fields to attach to the event.
 func (h *SwarmHandler) auditSwarmMutation(ctx context.Context, environmentID, action, resourceType, resourceID, resourceName string, metadata map[string]any) {
+	h.auditSwarmEventInternal(ctx, models.EventSeverityInfo, "Swarm operation '"+action+"' completed", environmentID, action, resourceType, resourceID, resourceName, metadata)
+}
+
+// auditSwarmMutationFailure writes an error event for a swarm mutation that
+// failed, recording cause under the "error" metadata key.
+func (h *SwarmHandler) auditSwarmMutationFailure(ctx context.Context, environmentID, action, resourceType, resourceID, resourceName string, cause error, metadata map[string]any) {
+	meta := map[string]any{"error": cause.Error()}
+	maps.Copy(meta, metadata)
+	h.auditSwarmEventInternal(ctx, models.EventSeverityError, "Swarm operation '"+action+"' failed", environmentID, action, resourceType, resourceID, resourceName, meta)
+}
+
+func (h *SwarmHandler) auditSwarmEventInternal(ctx context.Context, severity models.EventSeverity, description, environmentID, action, resourceType, resourceID, resourceName string, metadata map[string]any) {
 	if h.eventService == nil {
 		return
 	}
@@ -1917,9 +2786,9 @@ func (h *SwarmHandler) auditSwarmMutation(ctx context.Context, environmentID, ac
 
 	_, err := h.eventService.CreateEvent(ctx, services.CreateEventRequest{
 		Type:          models.EventType("swarm." + action),
-		Severity:      models.EventSeverityInfo,
+		Severity:      severity,
 		Title:         "Swarm operation: " + action,
-		Description:   "Swarm operation '" + action + "' completed",
+		Description:   description,
 		ResourceType:  resourceTypePtr,
 		ResourceID:    resourceIDPtr,
 		ResourceName:  resourceNamePtr,
@@ -1947,12 +2816,18 @@ func mapSwarmServiceError(err error, fallback string) error {
 	if err == nil {
 		return nil
 	}
+	if apiErr := dockerUnavailableErrorInternal(err); apiErr != nil {
+		return apiErr
+	}
 	if errors.Is(err, common.ErrSwarmNotEnabled) {
 		return huma.Error409Conflict("Swarm mode is not enabled")
 	}
 	if errors.Is(err, common.ErrSwarmManagerRequired) {
 		return huma.Error403Forbidden("Swarm manager access required")
 	}
+	if errors.Is(err, common.ErrSwarmRemoteEnvironment) {
+		return huma.Error400BadRequest("Swarm requests for remote environments must be sent through the environment proxy")
+	}
 	if errors.Is(err, common.ErrSwarmConfigImmutable) || errors.Is(err, common.ErrSwarmSecretImmutable) {
 		return huma.Error400BadRequest(err.Error())
 	}
@@ -50,6 +50,16 @@ type SystemHealthOutput struct {
 	Status int `status:"200"`
 }
 
+type SystemReadyInput struct {
+	EnvironmentID      string  `path:"id" doc:"Environment ID"`
+	MinFreeDiskPercent float64 `query:"minFreeDiskPercent" default:"5" minimum:"0" maximum:"100" doc:"Minimum free space on the disk usage path, in percent, for the disk check to pass"`
+}
+
+type SystemReadyOutput struct {
+	Status int
+	Body   base.ApiResponse[system.ReadinessResponse]
+}
+
 type GetDockerInfoInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 }
@@ -58,6 +68,15 @@ type GetDockerInfoOutput struct {
 	Body dockerinfo.Info
 }
 
+type GetDiskUsageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Refresh       bool   `query:"refresh" default:"false" doc:"Bypass the short-lived cache and query the daemon"`
+}
+
+type GetDiskUsageOutput struct {
+	Body base.ApiResponse[system.DiskUsage]
+}
+
 type PruneAllInput struct {
 	EnvironmentID string                 `path:"id" doc:"Environment ID"`
 	Body          system.PruneAllRequest `doc:"Prune options"`
@@ -163,6 +182,16 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		Security:      defaultOperationSecurityInternal(),
 	}, authz.PermSystemRead, h.Health)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "system-ready",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/ready",
+		Summary:     "Check system readiness",
+		Description: "Report per-subsystem readiness (Docker, database, disk space, swarm control plane); responds 503 when a critical check fails",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemRead, h.Ready)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "get-docker-info",
 		Method:      http.MethodGet,
@@ -173,6 +202,16 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		Security:    defaultOperationSecurityInternal(),
 	}, authz.PermSystemRead, h.GetDockerInfo)
 
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "get-system-disk-usage",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/df",
+		Summary:     "Get Docker disk usage",
+		Description: "Get disk usage and reclaimable space for images, containers, volumes and build cache",
+		Tags:        []string{"System"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermSystemRead, h.GetDiskUsage)
+
 	humamw.RegisterWithPermission(api, huma.Operation{
 		OperationID: "prune-all",
 		Method:      http.MethodPost,
@@ -181,6 +220,7 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		Description: "Remove unused Docker resources (containers, images, volumes, networks)",
 		Tags:        []string{"System"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermSystemPrune, h.PruneAll)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -191,6 +231,7 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		Description: "Start all Docker containers",
 		Tags:        []string{"System"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersStart, h.StartAllContainers)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -201,6 +242,7 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		Description: "Start all stopped Docker containers",
 		Tags:        []string{"System"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersStart, h.StartAllStoppedContainers)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -211,6 +253,7 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		Description: "Stop all running Docker containers",
 		Tags:        []string{"System"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermContainersStop, h.StopAllContainers)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -242,6 +285,7 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		DefaultStatus: http.StatusAccepted,
 		Tags:          []string{"System"},
 		Security:      defaultOperationSecurityInternal(),
+		Metadata:      humamw.BlockedInMaintenance(),
 	}, authz.PermSystemUpgrade, h.TriggerUpgrade)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -253,6 +297,7 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		DefaultStatus: http.StatusAccepted,
 		Tags:          []string{"System"},
 		Security:      defaultOperationSecurityInternal(),
+		Metadata:      humamw.BlockedInMaintenance(),
 	}, authz.PermSystemUpgrade, h.TriggerUpdateAll)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -289,6 +334,21 @@ func (h *SystemHandler) Health(ctx context.Context, input *SystemHealthInput) (*
 	return &SystemHealthOutput{}, nil
 }
 
+// Ready reports the readiness of each subsystem the environment depends on,
+// responding 503 with the failing subsystems named when a critical check fails.
+func (h *SystemHandler) Ready(ctx context.Context, input *SystemReadyInput) (*SystemReadyOutput, error) {
+	report := h.systemService.CheckReadiness(ctx, input.MinFreeDiskPercent)
+
+	output := &SystemReadyOutput{
+		Status: http.StatusOK,
+		Body:   base.ApiResponse[system.ReadinessResponse]{Success: report.Ready, Data: report},
+	}
+	if !report.Ready {
+		output.Status = http.StatusServiceUnavailable
+	}
+	return output, nil
+}
+
 // GetDockerInfo returns Docker daemon version and system information.
 func (h *SystemHandler) GetDockerInfo(ctx context.Context, input *GetDockerInfoInput) (*GetDockerInfoOutput, error) {
 	dockerClient, err := h.dockerService.GetClient(ctx)
@@ -336,6 +396,11 @@ func (h *SystemHandler) GetDockerInfo(ctx context.Context, input *GetDockerInfoI
 
 	gitCommit, goVersion, buildTime := extractVersionDetailsFromComponents(version.Components)
 
+	var gpuType string
+	if gpuMonitor := h.systemService.GPUMonitor(); gpuMonitor != nil {
+		gpuType = gpuMonitor.DetectedType(ctx)
+	}
+
 	return &GetDockerInfoOutput{
 		Body: dockerinfo.Info{
 			Success:    true,
@@ -345,6 +410,7 @@ func (h *SystemHandler) GetDockerInfo(ctx context.Context, input *GetDockerInfoI
 			Os:         version.Os,
 			Arch:       version.Arch,
 			BuildTime:  buildTime,
+			GPUType:    gpuType,
 			Info:       info,
 		},
 	}, nil
@@ -377,6 +443,21 @@ func extractVersionDetailsFromComponents(components []dockersystem.ComponentVers
 	return gitCommit, goVersion, buildTime
 }
 
+// GetDiskUsage returns the Docker disk usage breakdown (docker system df -v).
+func (h *SystemHandler) GetDiskUsage(ctx context.Context, input *GetDiskUsageInput) (*GetDiskUsageOutput, error) {
+	usage, err := h.systemService.GetDiskUsage(ctx, input.Refresh)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to get disk usage").Error())
+	}
+
+	return &GetDiskUsageOutput{
+		Body: base.ApiResponse[system.DiskUsage]{
+			Success: true,
+			Data:    *usage,
+		},
+	}, nil
+}
+
 // PruneAll removes unused Docker resources.
 func (h *SystemHandler) PruneAll(ctx context.Context, input *PruneAllInput) (*PruneAllOutput, error) {
 	slog.InfoContext(ctx, "System prune operation initiated",
@@ -387,7 +468,10 @@ func (h *SystemHandler) PruneAll(ctx context.Context, input *PruneAllInput) (*Pr
 		"build_cache", input.Body.BuildCache)
 
 	runtimeCtx := utils.ActivityRuntimeContext(ctx, h.appCtx)
-	result := h.systemService.StartPruneAll(runtimeCtx, input.EnvironmentID, input.Body)
+	result, err := h.systemService.StartPruneAll(runtimeCtx, input.EnvironmentID, input.Body)
+	if err != nil {
+		return nil, huma.Error400BadRequest(errors.WithMessage(err, "Invalid prune request").Error())
+	}
 
 	slog.InfoContext(runtimeCtx, "System prune background activity started", "activityId", result.ActivityID)
 
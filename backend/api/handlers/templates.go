@@ -4,6 +4,7 @@ import (
 	"context"
 	json "encoding/json/v2"
 	"net/url"
+	"strings"
 
 	"emperror.dev/errors"
 	"github.com/danielgtaylor/huma/v2"
@@ -13,8 +14,10 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/mapper"
+	"github.com/getarcaneapp/arcane/types/v2"
 	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/template"
+	"github.com/samber/mo"
 )
 
 // TemplateHandler handles template management endpoints.
@@ -27,12 +30,14 @@ type TemplateHandler struct {
 // ============================================================================
 
 type ListTemplatesInput struct {
-	Search string `query:"search" doc:"Search query"`
-	Sort   string `query:"sort" doc:"Column to sort by"`
-	Order  string `query:"order" default:"asc" doc:"Sort direction"`
-	Start  int    `query:"start" default:"0" doc:"Start index"`
-	Limit  int    `query:"limit" default:"20" doc:"Items per page"`
-	Type   string `query:"type" doc:"Filter by template type (comma-separated: false,true)"`
+	Search   string `query:"search" doc:"Search query"`
+	Sort     string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
+	Order    string `query:"order" default:"asc" doc:"Sort direction"`
+	Start    int    `query:"start" default:"0" doc:"Start index"`
+	Limit    int    `query:"limit" default:"20" doc:"Items per page"`
+	Type     string `query:"type" doc:"Filter by template type (comma-separated: false,true)"`
+	Category string `query:"category" doc:"Filter by category (comma-separated, matches any)"`
+	Tags     string `query:"tags" doc:"Filter by tag (comma-separated, matches any)"`
 }
 
 type ListTemplatesOutput struct {
@@ -45,6 +50,12 @@ type GetAllTemplatesOutput struct {
 	Body base.ApiResponse[[]template.Template]
 }
 
+type GetTemplateCategoriesInput struct{}
+
+type GetTemplateCategoriesOutput struct {
+	Body base.ApiResponse[[]string]
+}
+
 type GetTemplateInput struct {
 	ID string `path:"id" doc:"Template ID"`
 }
@@ -94,6 +105,39 @@ type DownloadTemplateOutput struct {
 	Body base.ApiResponse[template.Template]
 }
 
+type ValidateTemplateInput struct {
+	ID   string                    `path:"id" doc:"Template ID"`
+	Body *template.ValidateRequest `json:"body,omitempty"`
+}
+
+type ValidateTemplateOutput struct {
+	Body base.ApiResponse[template.ValidationResult]
+}
+
+type CheckTemplateUpdatesInput struct{}
+
+type CheckTemplateUpdatesOutput struct {
+	Body base.ApiResponse[[]template.UpdateStatus]
+}
+
+type RefreshTemplateInput struct {
+	ID string `path:"id" doc:"Template ID"`
+}
+
+type RefreshTemplateOutput struct {
+	Body base.ApiResponse[template.Template]
+}
+
+type DeployTemplateInput struct {
+	ID                  string `path:"id" doc:"Template ID"`
+	MaintenanceOverride string `header:"X-Arcane-Maintenance-Override" doc:"Set to true to deploy while the environment is in maintenance mode (global admins only)"`
+	Body                template.DeployRequest
+}
+
+type DeployTemplateOutput struct {
+	Body base.ApiResponse[template.DeployResult]
+}
+
 type GetDefaultTemplatesInput struct{}
 
 type GetDefaultTemplatesOutput struct {
@@ -140,7 +184,10 @@ type DeleteTemplateRegistryOutput struct {
 }
 
 type FetchTemplateRegistryInput struct {
-	URL string `query:"url" required:"true" doc:"Registry URL"`
+	URL      string `query:"url" required:"true" doc:"Registry URL"`
+	AuthType string `header:"X-Registry-Auth-Type" doc:"Authentication method for a private registry (none, bearer, basic)"`
+	Username string `header:"X-Registry-Username" doc:"Username for basic authentication"`
+	Token    string `header:"X-Registry-Token" doc:"Bearer token, or password for basic authentication"`
 }
 
 type FetchTemplateRegistryOutput struct {
@@ -187,6 +234,16 @@ func RegisterTemplates(api huma.API, templateService *services.TemplateService)
 		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesList),
 	}, h.GetAllTemplates)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "getTemplateCategories",
+		Method:      "GET",
+		Path:        "/templates/categories",
+		Summary:     "List template categories",
+		Description: "Get the distinct categories used by local and remote templates",
+		Tags:        []string{"Templates"},
+		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesList),
+	}, h.GetTemplateCategories)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getTemplate",
 		Method:      "GET",
@@ -252,6 +309,50 @@ func RegisterTemplates(api huma.API, templateService *services.TemplateService)
 		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesRead),
 	}, h.DownloadTemplate)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "validateTemplate",
+		Method:      "POST",
+		Path:        "/templates/{id}/validate",
+		Summary:     "Validate template variables",
+		Description: "Check that every variable referenced by a template has a value before deploying it",
+		Tags:        []string{"Templates"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesRead),
+	}, h.ValidateTemplate)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "checkTemplateUpdates",
+		Method:      "GET",
+		Path:        "/templates/updates",
+		Summary:     "Check downloaded templates for updates",
+		Description: "Re-fetch the upstream source of every downloaded template and report which ones changed",
+		Tags:        []string{"Templates"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesList),
+	}, h.CheckTemplateUpdates)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "refreshTemplate",
+		Method:      "POST",
+		Path:        "/templates/{id}/refresh",
+		Summary:     "Refresh a downloaded template",
+		Description: "Replace a downloaded template's content with its current upstream content",
+		Tags:        []string{"Templates"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesUpdate),
+	}, h.RefreshTemplate)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "deployTemplate",
+		Method:      "POST",
+		Path:        "/templates/{id}/deploy",
+		Summary:     "Deploy a template",
+		Description: "Create a stack from a template with the given variables and bring it up",
+		Tags:        []string{"Templates"},
+		Security:    defaultOperationSecurityInternal(),
+		Middlewares: humamw.RequirePermission(api, authz.PermTemplatesRead),
+	}, h.DeployTemplate)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getDefaultTemplates",
 		Method:      "GET",
@@ -332,6 +433,12 @@ func (h *TemplateHandler) ListTemplates(ctx context.Context, input *ListTemplate
 	if input.Type != "" {
 		params.Filters["type"] = input.Type
 	}
+	if input.Category != "" {
+		params.Filters["category"] = input.Category
+	}
+	if input.Tags != "" {
+		params.Filters["tags"] = input.Tags
+	}
 
 	templates, paginationResp, err := h.templateService.GetAllTemplatesPaginated(ctx, params)
 	if err != nil {
@@ -367,6 +474,21 @@ func (h *TemplateHandler) GetAllTemplates(ctx context.Context, _ *GetAllTemplate
 	}, nil
 }
 
+// GetTemplateCategories returns the distinct template categories.
+func (h *TemplateHandler) GetTemplateCategories(ctx context.Context, _ *GetTemplateCategoriesInput) (*GetTemplateCategoriesOutput, error) {
+	categories, err := h.templateService.GetTemplateCategories(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to get template categories").Error())
+	}
+
+	return &GetTemplateCategoriesOutput{
+		Body: base.ApiResponse[[]string]{
+			Success: true,
+			Data:    categories,
+		},
+	}, nil
+}
+
 // GetTemplate returns a template by ID.
 func (h *TemplateHandler) GetTemplate(ctx context.Context, input *GetTemplateInput) (*GetTemplateOutput, error) {
 	if input.ID == "" {
@@ -434,10 +556,14 @@ func (h *TemplateHandler) CreateTemplate(ctx context.Context, input *CreateTempl
 	tmpl := &models.ComposeTemplate{
 		Name:        input.Body.Name,
 		Description: input.Body.Description,
+		Category:    input.Body.Category,
 		Content:     input.Body.Content,
 		IsCustom:    true,
 		IsRemote:    false,
 	}
+	if len(input.Body.Tags) > 0 {
+		tmpl.Metadata = &models.ComposeTemplateMetadata{Tags: input.Body.Tags}
+	}
 	if input.Body.EnvContent != "" {
 		tmpl.EnvContent = &input.Body.EnvContent
 	}
@@ -473,8 +599,12 @@ func (h *TemplateHandler) UpdateTemplate(ctx context.Context, input *UpdateTempl
 	updates := &models.ComposeTemplate{
 		Name:        input.Body.Name,
 		Description: input.Body.Description,
+		Category:    input.Body.Category,
 		Content:     input.Body.Content,
 	}
+	if input.Body.Tags != nil {
+		updates.Metadata = &models.ComposeTemplateMetadata{Tags: input.Body.Tags}
+	}
 	if input.Body.EnvContent != "" {
 		updates.EnvContent = &input.Body.EnvContent
 	} else {
@@ -574,6 +704,138 @@ func (h *TemplateHandler) DownloadTemplate(ctx context.Context, input *DownloadT
 	}, nil
 }
 
+// CheckTemplateUpdates reports which downloaded templates changed upstream.
+func (h *TemplateHandler) CheckTemplateUpdates(ctx context.Context, _ *CheckTemplateUpdatesInput) (*CheckTemplateUpdatesOutput, error) {
+	updates, err := h.templateService.CheckTemplateUpdates(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to check template updates").Error())
+	}
+
+	return &CheckTemplateUpdatesOutput{
+		Body: base.ApiResponse[[]template.UpdateStatus]{
+			Success: true,
+			Data:    updates,
+		},
+	}, nil
+}
+
+// RefreshTemplate pulls the current upstream content of a downloaded template.
+func (h *TemplateHandler) RefreshTemplate(ctx context.Context, input *RefreshTemplateInput) (*RefreshTemplateOutput, error) {
+	if input.ID == "" {
+		return nil, huma.Error400BadRequest("Template ID is required")
+	}
+
+	id, decodeErr := url.PathUnescape(input.ID)
+	if decodeErr != nil {
+		return nil, huma.Error400BadRequest("Template ID is required")
+	}
+
+	refreshed, err := h.templateService.RefreshTemplate(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrTemplateNotFound):
+			return nil, huma.Error404NotFound("Template not found")
+		case errors.Is(err, common.ErrTemplateNoSource):
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to refresh template").Error())
+	}
+
+	var out template.Template
+	if mapErr := mapper.MapStruct(refreshed, &out); mapErr != nil {
+		return nil, huma.Error500InternalServerError(errors.WithMessage(mapErr, "Failed to map templates").Error())
+	}
+
+	return &RefreshTemplateOutput{
+		Body: base.ApiResponse[template.Template]{
+			Success: true,
+			Data:    out,
+		},
+	}, nil
+}
+
+// ValidateTemplate reports missing and unused variables for a template.
+func (h *TemplateHandler) ValidateTemplate(ctx context.Context, input *ValidateTemplateInput) (*ValidateTemplateOutput, error) {
+	if input.ID == "" {
+		return nil, huma.Error400BadRequest("Template ID is required")
+	}
+
+	id, decodeErr := url.PathUnescape(input.ID)
+	if decodeErr != nil {
+		return nil, huma.Error400BadRequest("Template ID is required")
+	}
+
+	var providedVars map[string]string
+	if input.Body != nil {
+		providedVars = input.Body.Variables
+	}
+
+	result, err := h.templateService.ValidateTemplate(ctx, id, providedVars)
+	if err != nil {
+		if errors.Is(err, common.ErrTemplateNotFound) {
+			return nil, huma.Error404NotFound("Template not found")
+		}
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to validate template").Error())
+	}
+
+	return &ValidateTemplateOutput{
+		Body: base.ApiResponse[template.ValidationResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// DeployTemplate creates and starts a stack from a template.
+func (h *TemplateHandler) DeployTemplate(ctx context.Context, input *DeployTemplateInput) (*DeployTemplateOutput, error) {
+	if input.ID == "" {
+		return nil, huma.Error400BadRequest("Template ID is required")
+	}
+
+	id, decodeErr := url.PathUnescape(input.ID)
+	if decodeErr != nil {
+		return nil, huma.Error400BadRequest("Template ID is required")
+	}
+
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The route is not environment-scoped, so check the target environment's
+	// project permissions here.
+	environmentID := mo.EmptyableToOption(strings.TrimSpace(input.Body.EnvironmentID)).OrElse(types.LOCAL_DOCKER_ENVIRONMENT_ID)
+	ps, _ := humamw.PermissionsFromContext(ctx)
+	for _, perm := range []string{authz.PermProjectsCreate, authz.PermProjectsDeploy} {
+		if !ps.Allows(perm, environmentID) {
+			return nil, huma.Error403Forbidden("permission denied: " + perm)
+		}
+	}
+
+	override := humamw.MaintenanceOverrideAllowed(ps, input.MaintenanceOverride)
+	result, err := h.templateService.DeployTemplate(ctx, id, environmentID, input.Body.StackName, input.Body.Variables, override, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrEnvironmentMaintenance):
+			return nil, huma.Error409Conflict(err.Error())
+		case errors.Is(err, common.ErrTemplateNotFound):
+			return nil, huma.Error404NotFound("Template not found")
+		case errors.Is(err, common.ErrTemplateVariablesMissing):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, common.ErrTemplateStackExists):
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to deploy template").Error())
+	}
+
+	return &DeployTemplateOutput{
+		Body: base.ApiResponse[template.DeployResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
 // GetDefaultTemplates returns the default compose and env templates.
 func (h *TemplateHandler) GetDefaultTemplates(ctx context.Context, _ *GetDefaultTemplatesInput) (*GetDefaultTemplatesOutput, error) {
 	composeTemplate := h.templateService.GetComposeTemplate()
@@ -651,8 +913,14 @@ func (h *TemplateHandler) CreateRegistry(ctx context.Context, input *CreateTempl
 		URL:         input.Body.URL,
 		Description: input.Body.Description,
 		Enabled:     input.Body.Enabled,
+		AuthType:    input.Body.AuthType,
+		Username:    input.Body.Username,
+		Credential:  input.Body.Token,
 	}
 	if err := h.templateService.CreateRegistry(ctx, registry); err != nil {
+		if errors.Is(err, common.ErrTemplateRegistryAuthInvalid) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to create registry").Error())
 	}
 
@@ -680,11 +948,17 @@ func (h *TemplateHandler) UpdateRegistry(ctx context.Context, input *UpdateTempl
 		URL:         input.Body.URL,
 		Description: input.Body.Description,
 		Enabled:     input.Body.Enabled,
+		AuthType:    mo.PointerToOption(input.Body.AuthType).OrEmpty(),
+		Username:    mo.PointerToOption(input.Body.Username).OrEmpty(),
+		Credential:  mo.PointerToOption(input.Body.Token).OrEmpty(),
 	}
 	if err := h.templateService.UpdateRegistry(ctx, input.ID, updates); err != nil {
 		if err.Error() == "registry not found" {
 			return nil, huma.Error404NotFound("Registry not found")
 		}
+		if errors.Is(err, common.ErrTemplateRegistryAuthInvalid) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(errors.WithMessage(err, "Failed to update registry").Error())
 	}
 
@@ -727,7 +1001,12 @@ func (h *TemplateHandler) FetchRegistry(ctx context.Context, input *FetchTemplat
 		return nil, huma.Error400BadRequest("Query parameter is required")
 	}
 
-	body, err := h.templateService.FetchRaw(ctx, input.URL)
+	auth, err := services.NewRegistryAuth(input.AuthType, input.Username, input.Token)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	body, err := h.templateService.FetchRaw(ctx, input.URL, auth)
 	if err != nil {
 		return nil, huma.Error502BadGateway("Failed to fetch registry")
 	}
@@ -142,7 +142,7 @@ func newTemplateFetchTestRouter(t *testing.T, httpClient *http.Client) *echo.Ech
 	}).Error)
 
 	authService := services.NewAuthService(userService, nil, nil, services.NewSessionService(databaseDB), nil, "test-secret", &config.Config{}, nil)
-	templateService := services.NewTemplateService(context.Background(), nil, httpClient, nil)
+	templateService := services.NewTemplateService(context.Background(), nil, httpClient, nil, nil, nil)
 
 	router := echo.New()
 	apiGroup := router.Group("/api")
@@ -165,7 +165,7 @@ func newTemplateFetchTestRouter(t *testing.T, httpClient *http.Client) *echo.Ech
 	}
 
 	api := humaecho.NewWithGroup(router, apiGroup, humaConfig)
-	api.UseMiddleware(humamiddleware.NewAuthBridge(api, authService, nil, sudoPermResolver{}, nil, &config.Config{}))
+	api.UseMiddleware(humamiddleware.NewAuthBridge(api, authService, nil, sudoPermResolver{}, nil, nil, &config.Config{}))
 	RegisterHealth(api)
 	RegisterTemplates(api, templateService)
 
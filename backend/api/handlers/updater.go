@@ -73,6 +73,7 @@ func RegisterUpdater(api huma.API, updaterService *services.UpdaterService, appC
 		Description: "Apply pending container updates",
 		Tags:        []string{"Updater"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImageUpdatesCheck, h.RunUpdater)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -103,6 +104,7 @@ func RegisterUpdater(api huma.API, updaterService *services.UpdaterService, appC
 		Description: "Pull the latest image and apply the appropriate update strategy for a specific container",
 		Tags:        []string{"Updater", "Containers"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermImageUpdatesCheck, h.UpdateContainer)
 }
 
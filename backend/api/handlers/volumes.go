@@ -8,8 +8,10 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
@@ -45,7 +47,7 @@ type VolumeUsageCountsData struct {
 type ListVolumesInput struct {
 	EnvironmentID   string `path:"id" doc:"Environment ID"`
 	Search          string `query:"search" doc:"Search query"`
-	Sort            string `query:"sort" doc:"Column to sort by"`
+	Sort            string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order           string `query:"order" default:"asc" doc:"Sort direction (asc or desc)"`
 	Start           int    `query:"start" default:"0" doc:"Start index for pagination"`
 	Limit           int    `query:"limit" default:"20" doc:"Number of items per page"`
@@ -146,10 +148,14 @@ type BrowseDirectoryInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	VolumeName    string `path:"volumeName" doc:"Volume name"`
 	Path          string `query:"path" default:"/" doc:"Directory path to browse"`
+	Filter        string `query:"filter" doc:"Glob matched against entry names (e.g. *.conf)"`
+	Offset        int    `query:"offset" default:"0" minimum:"0" doc:"Number of matching entries to skip"`
+	Limit         int    `query:"limit" default:"0" minimum:"0" doc:"Maximum entries to return; 0 returns all"`
 }
 
 type BrowseDirectoryOutput struct {
-	Body base.ApiResponse[[]volumetypes.FileEntry]
+	TotalCount int `header:"X-Total-Count" doc:"Number of entries matching the filter"`
+	Body       base.ApiResponse[[]volumetypes.FileEntry]
 }
 
 type GetFileContentInput struct {
@@ -197,7 +203,7 @@ type ListBackupsInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	VolumeName    string `path:"volumeName" doc:"Volume name"`
 	Search        string `query:"search" doc:"Search query"`
-	Sort          string `query:"sort" doc:"Column to sort by"`
+	Sort          string `query:"sort" doc:"Column to sort by; comma-separate additional columns as tie-breakers"`
 	Order         string `query:"order" default:"asc" doc:"Sort direction"`
 	Start         int    `query:"start" default:"0" doc:"Start index"`
 	Limit         int    `query:"limit" default:"20" doc:"Limit"`
@@ -293,6 +299,27 @@ type UploadAndRestoreOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type BackupVolumeInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	VolumeName    string `path:"volumeName" doc:"Volume name"`
+}
+
+type RestoreVolumeInput struct {
+	EnvironmentID string         `path:"id" doc:"Environment ID"`
+	VolumeName    string         `path:"volumeName" doc:"Volume name"`
+	RawBody       multipart.Form `contentType:"multipart/form-data"`
+}
+
+type VolumeRestoreResponse struct {
+	Success  bool                 `json:"success"`
+	Data     base.MessageResponse `json:"data"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+type RestoreVolumeOutput struct {
+	Body VolumeRestoreResponse
+}
+
 // RegisterVolumes registers volume management routes using Huma.
 func RegisterVolumes(api huma.API, dockerService *services.DockerClientService, volumeService *services.VolumeService, activityService *services.ActivityService, appCtx ActivityAppContext) {
 	h := &VolumeHandler{
@@ -340,6 +367,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Description: "Create a new Docker volume",
 		Tags:        []string{"Volumes"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesCreate, h.CreateVolume)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -350,6 +378,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Description: "Remove a Docker volume by name",
 		Tags:        []string{"Volumes"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesDelete, h.RemoveVolume)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -360,6 +389,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Description: "Remove all unused Docker volumes",
 		Tags:        []string{"Volumes"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesPrune, h.PruneVolumes)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -418,6 +448,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Upload file to volume",
 		Tags:        []string{"Volume Browser"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 		RequestBody: &huma.RequestBody{
 			Content: map[string]*huma.MediaType{
 				"multipart/form-data": {
@@ -444,6 +475,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Create directory in volume",
 		Tags:        []string{"Volume Browser"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesUpload, h.CreateDirectory)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -453,6 +485,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Delete file or directory in volume",
 		Tags:        []string{"Volume Browser"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesDelete, h.DeleteFile)
 
 	// --- Volume Backup Endpoints ---
@@ -473,6 +506,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Create volume backup",
 		Tags:        []string{"Volume Backup"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesBackup, h.CreateBackup)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -482,6 +516,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Restore volume backup",
 		Tags:        []string{"Volume Backup"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesBackup, h.RestoreBackup)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -491,6 +526,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Restore specific files from a volume backup",
 		Tags:        []string{"Volume Backup"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesBackup, h.RestoreBackupFiles)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -500,6 +536,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Delete volume backup",
 		Tags:        []string{"Volume Backup"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVolumesBackup, h.DeleteBackup)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -536,6 +573,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Summary:     "Upload and restore volume backup",
 		Tags:        []string{"Volume Backup"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 		RequestBody: &huma.RequestBody{
 			Content: map[string]*huma.MediaType{
 				"multipart/form-data": {
@@ -554,6 +592,42 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 			},
 		},
 	}, authz.PermVolumesUpload, h.UploadAndRestore)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "backup-volume",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/volumes/{volumeName}/backup",
+		Summary:     "Stream a tar archive of a volume's contents",
+		Tags:        []string{"Volume Backup"},
+		Security:    defaultOperationSecurityInternal(),
+	}, authz.PermVolumesBrowse, h.BackupVolume)
+
+	humamw.RegisterWithPermission(api, huma.Operation{
+		OperationID: "restore-volume",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/{volumeName}/restore",
+		Summary:     "Replace a volume's contents from a tar archive",
+		Tags:        []string{"Volume Backup"},
+		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
+		RequestBody: &huma.RequestBody{
+			Content: map[string]*huma.MediaType{
+				"multipart/form-data": {
+					Schema: &huma.Schema{
+						Type: "object",
+						Properties: map[string]*huma.Schema{
+							"file": {
+								Type:        "string",
+								Format:      "binary",
+								Description: "Volume archive (tar or tar.gz)",
+							},
+						},
+						Required: []string{"file"},
+					},
+				},
+			},
+		},
+	}, authz.PermVolumesUpload, h.RestoreVolume)
 }
 
 // ListVolumes returns a paginated list of volumes.
@@ -791,11 +865,19 @@ func (h *VolumeHandler) GetVolumeSizes(ctx context.Context, input *GetVolumeSize
 // --- Volume Browser Handler Methods ---
 
 func (h *VolumeHandler) BrowseDirectory(ctx context.Context, input *BrowseDirectoryInput) (*BrowseDirectoryOutput, error) {
-	entries, err := h.volumeService.ListDirectory(ctx, input.VolumeName, input.Path)
+	entries, total, err := h.volumeService.ListDirectory(ctx, input.VolumeName, input.Path, volumetypes.ListDirectoryOptions{
+		Filter: input.Filter,
+		Offset: input.Offset,
+		Limit:  input.Limit,
+	})
 	if err != nil {
+		if cerrdefs.IsInvalidArgument(err) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 	return &BrowseDirectoryOutput{
+		TotalCount: total,
 		Body: base.ApiResponse[[]volumetypes.FileEntry]{
 			Success: true,
 			Data:    entries,
@@ -943,10 +1025,7 @@ func (h *VolumeHandler) ListBackups(ctx context.Context, input *ListBackupsInput
 		SearchQuery: pagination.SearchQuery{
 			Search: input.Search,
 		},
-		SortParams: pagination.SortParams{
-			Sort:  input.Sort,
-			Order: pagination.SortOrder(input.Order),
-		},
+		SortParams: pagination.ParseSortParams(input.Sort, input.Order),
 		Params: pagination.Params{
 			Start: input.Start,
 			Limit: input.Limit,
@@ -1215,3 +1294,85 @@ func (h *VolumeHandler) UploadAndRestore(ctx context.Context, input *UploadAndRe
 		},
 	}, nil
 }
+
+// BackupVolume streams the volume's current contents as a tar archive. When
+// running containers use the volume, the in-use warning is sent in the
+// X-Arcane-Warning header.
+func (h *VolumeHandler) BackupVolume(ctx context.Context, input *BackupVolumeInput) (*huma.StreamResponse, error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, warnings, err := h.volumeService.BackupVolume(ctx, input.VolumeName, *user)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, huma.Error404NotFound(errors.WithMessage(err, "Volume not found").Error())
+		}
+		return nil, dockerErrorInternal(err, "Failed to back up volume")
+	}
+
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) {
+			defer func() { _ = reader.Close() }()
+
+			humaCtx.SetHeader("Content-Type", "application/x-tar")
+			humaCtx.SetHeader("Content-Disposition", "attachment; filename="+input.VolumeName+"-"+time.Now().UTC().Format("20060102-150405")+".tar")
+			if len(warnings) > 0 {
+				humaCtx.SetHeader("X-Arcane-Warning", strings.Join(warnings, "; "))
+			}
+
+			writer := humaCtx.BodyWriter()
+			_, _ = io.Copy(writer, reader)
+		},
+	}, nil
+}
+
+// RestoreVolume replaces the volume's contents with an uploaded tar archive.
+func (h *VolumeHandler) RestoreVolume(ctx context.Context, input *RestoreVolumeInput) (*RestoreVolumeOutput, error) {
+	user, err := requireUserInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, fileHeader, err := openUploadedFileInternal(input.RawBody)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var warnings []string
+	runtimeCtx := utils.ActivityRuntimeContext(ctx, h.appCtx)
+	activityID, err := activitylib.RunHandlerActivity(runtimeCtx, h.activityService, activitylib.HandlerOptions{
+		EnvironmentID:  input.EnvironmentID,
+		Type:           models.ActivityTypeResourceAction,
+		ResourceType:   "volume",
+		ResourceID:     input.VolumeName,
+		ResourceName:   input.VolumeName,
+		User:           user,
+		Step:           "Restoring volume",
+		Message:        "Restoring volume from archive",
+		SuccessMessage: "Volume restored successfully",
+		Metadata: models.JSON{
+			"action":   "restore_volume",
+			"filename": fileHeader.Filename,
+		},
+	}, func(runtimeCtx context.Context) error {
+		var restoreErr error
+		warnings, restoreErr = h.volumeService.RestoreVolume(runtimeCtx, input.VolumeName, file, *user)
+		return restoreErr
+	})
+	if err != nil {
+		if cerrdefs.IsInvalidArgument(err) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &RestoreVolumeOutput{
+		Body: VolumeRestoreResponse{
+			Success:  true,
+			Data:     base.MessageResponse{Message: "Volume restored successfully", ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()},
+			Warnings: warnings,
+		},
+	}, nil
+}
@@ -138,6 +138,7 @@ func RegisterVulnerability(api huma.API, vulnerabilityService *services.Vulnerab
 		Description: "Initiates a vulnerability scan for the specified image using Trivy",
 		Tags:        []string{"Vulnerabilities"},
 		Security:    defaultOperationSecurityInternal(),
+		Metadata:    humamw.BlockedInMaintenance(),
 	}, authz.PermVulnsScan, h.ScanImage)
 
 	humamw.RegisterWithPermission(api, huma.Operation{
@@ -85,6 +85,12 @@ type PermissionResolver interface {
 	ResolveApiKeyPermissions(ctx context.Context, apiKeyID string) (*authz.PermissionSet, error)
 }
 
+// AgentControllerRecorder records the controller an agent-token request came
+// from. Implemented by services.SettingsService.
+type AgentControllerRecorder interface {
+	RecordAgentController(ctx context.Context, controllerURL string) error
+}
+
 // parseSecurityRequirementsInternal extracts security requirements from a Huma operation.
 func parseSecurityRequirementsInternal(api huma.API, ctx operationProvider) securityRequirements {
 	reqs := securityRequirements{}
@@ -215,8 +221,10 @@ func createEnvironmentUserInternal(env *models.Environment) *models.User {
 // NewAuthBridge creates a Huma middleware that validates credentials and
 // enforces security requirements defined on operations. It also resolves the
 // caller's effective PermissionSet via permResolver and stashes it on the
-// request context for downstream RequirePermission checks.
-func NewAuthBridge(api huma.API, authService *services.AuthService, apiKeyService *services.ApiKeyService, permResolver PermissionResolver, envTokenResolver environmentAccessTokenResolver, cfg *config.Config) func(ctx huma.Context, next func(huma.Context)) {
+// request context for downstream RequirePermission checks. On agents,
+// controllerRecorder (optional) is told which controller authenticated with
+// the agent token.
+func NewAuthBridge(api huma.API, authService *services.AuthService, apiKeyService *services.ApiKeyService, permResolver PermissionResolver, envTokenResolver environmentAccessTokenResolver, controllerRecorder AgentControllerRecorder, cfg *config.Config) func(ctx huma.Context, next func(huma.Context)) {
 	return func(ctx huma.Context, next func(huma.Context)) {
 		ctx = huma.WithContext(ctx, context.WithValue(ctx.Context(), ContextKeyRemoteAddr, ctx.RemoteAddr()))
 		if authService == nil {
@@ -225,6 +233,7 @@ func NewAuthBridge(api huma.API, authService *services.AuthService, apiKeyServic
 		}
 
 		if newCtx, ok := tryAgentAuthCtxInternal(ctx, cfg); ok {
+			recordAgentControllerInternal(ctx, controllerRecorder)
 			next(newCtx)
 			return
 		}
@@ -271,6 +280,22 @@ func tryAgentAuthCtxInternal(ctx huma.Context, cfg *config.Config) (huma.Context
 	return huma.WithContext(ctx, setUserInContextWithSudoInternal(ctx.Context(), user)), true
 }
 
+// recordAgentControllerInternal records the controller URL sent alongside a
+// successful agent-token request. Pairing requests are recorded by the pairing
+// handler itself.
+func recordAgentControllerInternal(ctx huma.Context, recorder AgentControllerRecorder) {
+	if recorder == nil || strings.HasPrefix(ctx.URL().Path, pkgutils.AgentPairingPrefix) {
+		return
+	}
+	controllerURL := ctx.Header(pkgutils.HeaderControllerURL)
+	if controllerURL == "" {
+		return
+	}
+	if err := recorder.RecordAgentController(ctx.Context(), controllerURL); err != nil {
+		slog.WarnContext(ctx.Context(), "Failed to record agent controller", "controllerURL", controllerURL, "error", err)
+	}
+}
+
 // opportunisticBearerAuthInternal populates the user/session context if a valid
 // bearer token is present, but never fails the request. Used for public routes
 // (e.g. logout) that still need to know who the caller is when a token exists.
@@ -101,7 +101,7 @@ func TestNewAuthBridge_AcceptsEnvironmentAccessTokenViaAPIKey(t *testing.T) {
 			Name:        "Self Target",
 			AccessToken: &token,
 		},
-	}, &config.Config{}))
+	}, nil, &config.Config{}))
 
 	huma.Register(api, huma.Operation{
 		OperationID: "secure",
@@ -165,7 +165,7 @@ func TestNewAuthBridge_UsesBearerWhenLoopbackProxySendsEnvironmentAccessToken(t
 			Name:        "Remote Env",
 			AccessToken: &envToken,
 		},
-	}, &config.Config{}))
+	}, nil, &config.Config{}))
 
 	huma.Register(api, huma.Operation{
 		OperationID: "loopback-start",
@@ -215,7 +215,7 @@ func TestNewAuthBridge_RejectsApiKeyOnBearerOnlyOperation(t *testing.T) {
 	}
 
 	api := humaecho.NewWithGroup(router, apiGroup, humaConfig)
-	api.UseMiddleware(NewAuthBridge(api, &services.AuthService{}, nil, nil, nil, &config.Config{}))
+	api.UseMiddleware(NewAuthBridge(api, &services.AuthService{}, nil, nil, nil, nil, &config.Config{}))
 
 	huma.Register(api, huma.Operation{
 		OperationID: "bearer-only",
@@ -236,6 +236,54 @@ func TestNewAuthBridge_RejectsApiKeyOnBearerOnlyOperation(t *testing.T) {
 	require.Equal(t, http.StatusUnauthorized, rec.Code)
 }
 
+type recordingControllerRecorder struct {
+	urls []string
+}
+
+func (r *recordingControllerRecorder) RecordAgentController(_ context.Context, controllerURL string) error {
+	r.urls = append(r.urls, controllerURL)
+	return nil
+}
+
+func TestNewAuthBridge_RecordsControllerOnAgentTokenAuth(t *testing.T) {
+	router := echo.New()
+	apiGroup := router.Group("/api")
+
+	humaConfig := huma.DefaultConfig("test", "1.0.0")
+	humaConfig.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"ApiKeyAuth": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+	}
+
+	recorder := &recordingControllerRecorder{}
+	api := humaecho.NewWithGroup(router, apiGroup, humaConfig)
+	api.UseMiddleware(NewAuthBridge(api, &services.AuthService{}, nil, nil, nil, recorder, &config.Config{AgentMode: true, AgentToken: "agent-secret"}))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "secure",
+		Method:      http.MethodGet,
+		Path:        "/secure",
+		Security:    []map[string][]string{{"ApiKeyAuth": {}}},
+	}, func(ctx context.Context, _ *secureInput) (*secureOutput, error) {
+		return &secureOutput{}, nil
+	})
+
+	send := func(token, controllerURL string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/secure", nil)
+		req.Header.Set("X-Arcane-Agent-Token", token)
+		if controllerURL != "" {
+			req.Header.Set("X-Arcane-Controller-Url", controllerURL)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, send("agent-secret", "https://controller.example.com"))
+	require.Equal(t, http.StatusOK, send("agent-secret", ""))
+	require.Equal(t, http.StatusUnauthorized, send("wrong-token", "https://attacker.example.com"))
+	require.Equal(t, []string{"https://controller.example.com"}, recorder.urls)
+}
+
 type testOperationProvider struct {
 	operation *huma.Operation
 }
@@ -353,7 +401,7 @@ func TestNewAuthBridge_OpportunisticAuthOnPublicRoute(t *testing.T) {
 		"BearerAuth": {Type: "http", Scheme: "bearer"},
 	}
 	api := humaecho.NewWithGroup(router, apiGroup, humaConfig)
-	api.UseMiddleware(NewAuthBridge(api, authSvc, nil, nil, nil, &config.Config{}))
+	api.UseMiddleware(NewAuthBridge(api, authSvc, nil, nil, nil, nil, &config.Config{}))
 
 	var sawSessionID string
 	huma.Register(api, huma.Operation{
@@ -447,7 +495,7 @@ func TestNewAuthBridge_VersionMismatchIsRecoverable(t *testing.T) {
 		"BearerAuth": {Type: "http", Scheme: "bearer"},
 	}
 	api := humaecho.NewWithGroup(router, apiGroup, humaConfig)
-	api.UseMiddleware(NewAuthBridge(api, authSvc, nil, nil, nil, &config.Config{}))
+	api.UseMiddleware(NewAuthBridge(api, authSvc, nil, nil, nil, nil, &config.Config{}))
 
 	huma.Register(api, huma.Operation{
 		OperationID: "protected",
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
+	pkgutils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
+)
+
+// MetaBlockedInMaintenance marks an operation that maintenance mode rejects. Set
+// it with BlockedInMaintenance when registering a mutating operation.
+const MetaBlockedInMaintenance = "arcane:blockedInMaintenance"
+
+// MaintenanceChecker reports whether the local environment currently rejects
+// mutating operations. Implemented by services.SettingsService.
+type MaintenanceChecker interface {
+	CheckMaintenance(ctx context.Context, override bool) error
+}
+
+// BlockedInMaintenance returns operation metadata that opts an operation into
+// the maintenance guard:
+//
+//	humamw.RegisterWithPermission(api, huma.Operation{..., Metadata: humamw.BlockedInMaintenance()}, perm, h.Handler)
+func BlockedInMaintenance() map[string]any {
+	return map[string]any{MetaBlockedInMaintenance: true}
+}
+
+// NewMaintenanceGuard rejects operations marked with BlockedInMaintenance with
+// 409 Conflict while the environment is in maintenance mode; every other
+// operation, including all reads, passes through. It must run after the auth
+// bridge so the caller's permissions are available for the admin override.
+func NewMaintenanceGuard(api huma.API, checker MaintenanceChecker) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if checker == nil || op == nil || op.Metadata[MetaBlockedInMaintenance] != true {
+			next(ctx)
+			return
+		}
+
+		ps, _ := PermissionsFromContext(ctx.Context())
+		if err := checker.CheckMaintenance(ctx.Context(), MaintenanceOverrideAllowed(ps, ctx.Header(pkgutils.HeaderMaintenanceOverride))); err != nil {
+			if err := huma.WriteErr(api, ctx, http.StatusConflict, err.Error()); err != nil {
+				slog.WarnContext(ctx.Context(), "failed to write 409 response", "error", err)
+			}
+			return
+		}
+		next(ctx)
+	}
+}
+
+// MaintenanceOverrideAllowed reports whether the caller asked to bypass
+// maintenance mode via HeaderMaintenanceOverride and is a global admin. The
+// environment proxy strips the header from non-admin requests before they reach
+// a remote agent, whose sudo permission set would otherwise always qualify.
+func MaintenanceOverrideAllowed(ps *authz.PermissionSet, header string) bool {
+	override, _ := strconv.ParseBool(strings.TrimSpace(header))
+	return override && ps.IsGlobalAdmin()
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humaecho"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
+	pkgutils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
+)
+
+type fakeMaintenanceChecker struct {
+	enabled bool
+}
+
+func (f *fakeMaintenanceChecker) CheckMaintenance(_ context.Context, override bool) error {
+	if f.enabled && !override {
+		return errors.New("Environment is in maintenance mode: upgrading disks")
+	}
+	return nil
+}
+
+func newMaintenanceTestAPIInternal(t *testing.T, ps *authz.PermissionSet) (*echo.Echo, *bool) {
+	t.Helper()
+
+	router := echo.New()
+	api := humaecho.NewWithGroup(router, router.Group("/api"), huma.DefaultConfig("test", "1.0.0"))
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		next(huma.WithContext(ctx, context.WithValue(ctx.Context(), ContextKeyUserPermissions, ps)))
+	})
+	api.UseMiddleware(NewMaintenanceGuard(api, &fakeMaintenanceChecker{enabled: true}))
+
+	handlerRan := false
+	handler := func(_ context.Context, _ *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		handlerRan = true
+		return nil, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "blocked-deploy",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/deploy",
+		Metadata:    BlockedInMaintenance(),
+	}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "unblocked-read",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/status",
+	}, handler)
+
+	return router, &handlerRan
+}
+
+func TestMaintenanceGuard_RejectsBlockedOperation(t *testing.T) {
+	ps := authz.NewPermissionSet()
+	ps.AddEnv("env-1", authz.PermProjectsDeploy)
+	router, handlerRan := newMaintenanceTestAPIInternal(t, ps)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/environments/env-1/deploy", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.Contains(t, rec.Body.String(), "upgrading disks")
+	require.False(t, *handlerRan)
+}
+
+func TestMaintenanceGuard_AllowsUnmarkedOperation(t *testing.T) {
+	router, handlerRan := newMaintenanceTestAPIInternal(t, authz.NewPermissionSet())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/environments/env-1/status", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Less(t, rec.Code, 300)
+	require.True(t, *handlerRan)
+}
+
+func TestMaintenanceGuard_AdminOverride(t *testing.T) {
+	router, handlerRan := newMaintenanceTestAPIInternal(t, authz.SudoPermissionSet())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/environments/env-1/deploy", nil)
+	req.Header.Set(pkgutils.HeaderMaintenanceOverride, "true")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Less(t, rec.Code, 300)
+	require.True(t, *handlerRan)
+}
+
+func TestMaintenanceGuard_IgnoresOverrideFromNonAdmin(t *testing.T) {
+	ps := authz.NewPermissionSet()
+	ps.AddEnv("env-1", authz.PermProjectsDeploy)
+	router, handlerRan := newMaintenanceTestAPIInternal(t, ps)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/environments/env-1/deploy", nil)
+	req.Header.Set(pkgutils.HeaderMaintenanceOverride, "true")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.False(t, *handlerRan)
+}
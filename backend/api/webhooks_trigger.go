@@ -6,6 +6,7 @@ import (
 	"emperror.dev/errors"
 
 	"github.com/getarcaneapp/arcane/backend/v2/api/handlers"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	"github.com/labstack/echo/v5"
@@ -28,6 +29,8 @@ func RegisterWebhookTrigger(g *echo.Group, webhookService *services.WebhookServi
 				status = http.StatusNotFound
 			} else if errors.Is(err, services.ErrWebhookDisabled) {
 				status = http.StatusForbidden
+			} else if errors.Is(err, common.ErrEnvironmentMaintenance) {
+				status = http.StatusConflict
 			}
 			msg := err.Error()
 			if status == http.StatusInternalServerError {
@@ -0,0 +1,106 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v5"
+
+	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+// dockerEventsPongWait is the read deadline refreshed by client pongs while
+// Docker events stream.
+const dockerEventsPongWait = 60 * time.Second
+
+// DockerEvents streams the environment's Docker engine events over WebSocket.
+// Each message is a JSON DockerEvent. The optional "type" and "label" query
+// parameters may be repeated or comma-separated; a label is "key" or
+// "key=value" and every listed label must match. An invalid filter closes the
+// socket with a policy violation.
+//
+//	@Summary		Get Docker events via WebSocket
+//	@Description	Stream Docker engine events, optionally filtered by type and label
+//	@Tags			WebSocket
+//	@Param			id		path	string	true	"Environment ID"
+//	@Param			type	query	string	false	"Event types, e.g. container,image"
+//	@Param			label	query	string	false	"Label filters, e.g. com.docker.compose.project=shop"
+//	@Router			/api/environments/{id}/ws/system/events [get]
+func (h *WebSocketHandler) DockerEvents(c *echo.Context) error {
+	query := c.Request().URL.Query()
+	filter := systemtypes.DockerEventFilter{
+		Types:  splitQueryValuesInternal(query["type"]),
+		Labels: splitQueryValuesInternal(query["label"]),
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		slog.DebugContext(c.Request().Context(), "Failed to upgrade WebSocket for Docker events", "error", err)
+		return nil
+	}
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindDockerEvents, ""))
+	defer h.wsMetrics.UnregisterConnection(connID)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close Docker events websocket connection", "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(dockerEventsPongWait))
+	conn.SetPongHandler(func(string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(dockerEventsPongWait))
+		return nil
+	})
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, nil)
+	go h.pingExecConnInternal(ctx, conn, dockerEventsPongWait*9/10)
+
+	eventsChan := make(chan systemtypes.DockerEvent, 64)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- h.systemService.StreamEvents(ctx, filter, eventsChan)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-streamErr:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				slog.DebugContext(ctx, "Docker event stream stopped", "error", err)
+				code := websocket.CloseInternalServerErr
+				if errors.Is(err, cerrdefs.ErrInvalidArgument) {
+					code = websocket.ClosePolicyViolation
+				}
+				writeStreamErrorCloseInternal(conn, code, err)
+			}
+			return nil
+		case event := <-eventsChan:
+			_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteJSON(event); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// splitQueryValuesInternal flattens repeated and comma-separated query values,
+// dropping empty entries.
+func splitQueryValuesInternal(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for part := range strings.SplitSeq(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -21,13 +23,19 @@ import (
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/disk"
 	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
 	"github.com/shirou/gopsutil/v4/mem"
+	gopsnet "github.com/shirou/gopsutil/v4/net"
 
+	humamw "github.com/getarcaneapp/arcane/backend/v2/api/middleware"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/middleware"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
+	dockerutils "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/system"
 	wshub "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/ws"
+	pkgutils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	httputil "github.com/getarcaneapp/arcane/backend/v2/pkg/utils/httpx"
 	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
 	"go.getarcane.app/sys/cgroup"
@@ -35,6 +43,14 @@ import (
 
 const cgroupCacheTTL = 30 * time.Second
 
+const (
+	// systemStatsSampleInterval is how often the shared sampler refreshes while any viewer is focused.
+	systemStatsSampleInterval = time.Second
+	// systemStatsBackgroundInterval is used for sampling and sending once every viewer
+	// has reported that its tab is in the background.
+	systemStatsBackgroundInterval = 10 * time.Second
+)
+
 var defaultWebSocketMetrics = wshub.NewWebSocketMetrics()
 
 // ============================================================================
@@ -46,9 +62,12 @@ var defaultWebSocketMetrics = wshub.NewWebSocketMetrics()
 type WebSocketHandler struct {
 	projectService     *services.ProjectService
 	containerService   *services.ContainerService
+	imageService       *services.ImageService
 	swarmService       *services.SwarmService
 	systemService      *services.SystemService
+	settingsService    *services.SettingsService
 	diagnosticsService *services.DiagnosticsService
+	activityService    *services.ActivityService
 	wsUpgrader         websocket.Upgrader
 	wsMetrics          *wshub.WebSocketMetrics
 	activeConnections  sync.Map
@@ -71,9 +90,13 @@ type WebSocketHandler struct {
 		timestamp   time.Time
 		lifecycleMu sync.Mutex
 		clients     int
+		background  int
 		cancel      context.CancelFunc
 		ready       chan struct{}
 		running     bool
+		// ioMu guards ioPrev, the last I/O counter reading that throughput rates are derived from.
+		ioMu   sync.Mutex
+		ioPrev ioCounterSample
 	}
 	containerStatsHubs sync.Map
 	cgroupCache        *cgroup.Cache
@@ -117,7 +140,7 @@ func buildWSConnectionInfoInternal(c *echo.Context, kind, resourceID string) sys
 	}
 }
 
-func buildLogStreamKeyInternal(envID, kind, resourceID, format string, batched, follow bool, tail, since string, timestamps bool) string {
+func buildLogStreamKeyInternal(envID, kind, resourceID, format string, batched, follow bool, tail, since string, timestamps, details bool, mergeWindow time.Duration, filter dockerutils.LogFilterOptions) string {
 	return strings.Join([]string{
 		envID,
 		kind,
@@ -128,9 +151,27 @@ func buildLogStreamKeyInternal(envID, kind, resourceID, format string, batched,
 		tail,
 		since,
 		strconv.FormatBool(timestamps),
+		strconv.FormatBool(details),
+		mergeWindow.String(),
+		filter.Grep,
+		strconv.FormatBool(filter.GrepRegex),
+		strconv.Itoa(filter.MaxLinesPerSecond),
+		strconv.FormatInt(filter.BytesLimit, 10),
 	}, "|")
 }
 
+// checkMaintenanceInternal applies the maintenance guard to a mutating stream
+// before it is upgraded, since Echo routes bypass the Huma operation guard. It
+// returns the maintenance error unless the caller may override it.
+func (h *WebSocketHandler) checkMaintenanceInternal(c *echo.Context) error {
+	if h.settingsService == nil {
+		return nil
+	}
+	ps, _ := c.Get("userPermissions").(*authz.PermissionSet)
+	override := humamw.MaintenanceOverrideAllowed(ps, c.Request().Header.Get(pkgutils.HeaderMaintenanceOverride))
+	return h.settingsService.CheckMaintenance(c.Request().Context(), override)
+}
+
 func (h *WebSocketHandler) streamProjectLogsInternal(ctx context.Context, projectID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool) error {
 	if h.projectLogStreamer != nil {
 		return h.projectLogStreamer(ctx, projectID, logsChan, follow, tail, since, timestamps)
@@ -142,7 +183,18 @@ func (h *WebSocketHandler) streamContainerLogsInternal(ctx context.Context, cont
 	if h.containerLogStreamer != nil {
 		return h.containerLogStreamer(ctx, containerID, logsChan, follow, tail, since, timestamps)
 	}
-	return h.containerService.StreamLogs(ctx, containerID, logsChan, follow, tail, since, timestamps)
+	return h.containerService.StreamLogs(ctx, containerID, logsChan, follow, tail, since, timestamps, nil)
+}
+
+// containerLogStreamerWithFilterInternal binds a compiled log filter to the container streamer.
+// The test hook bypasses filtering since it never reads from Docker.
+func (h *WebSocketHandler) containerLogStreamerWithFilterInternal(filter *dockerutils.LogFilter) func(context.Context, string, chan<- string, bool, string, string, bool) error {
+	return func(ctx context.Context, containerID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool) error {
+		if h.containerLogStreamer != nil {
+			return h.streamContainerLogsInternal(ctx, containerID, logsChan, follow, tail, since, timestamps)
+		}
+		return h.containerService.StreamLogs(ctx, containerID, logsChan, follow, tail, since, timestamps, filter)
+	}
 }
 
 func (h *WebSocketHandler) getOrCreateLogStreamInternal(key string, create func(onEmpty func(*wsLogStream)) *wsLogStream) *wsLogStream {
@@ -213,22 +265,28 @@ func NewWebSocketHandler(
 	group *echo.Group,
 	projectService *services.ProjectService,
 	containerService *services.ContainerService,
+	imageService *services.ImageService,
 	swarmService *services.SwarmService,
 	systemService *services.SystemService,
+	settingsService *services.SettingsService,
 	diagnosticsService *services.DiagnosticsService,
+	activityService *services.ActivityService,
 	authMiddleware *middleware.AuthMiddleware,
 	cfg *config.Config,
 ) {
 	handler := &WebSocketHandler{
 		projectService:     projectService,
 		containerService:   containerService,
+		imageService:       imageService,
 		swarmService:       swarmService,
 		systemService:      systemService,
+		settingsService:    settingsService,
 		diagnosticsService: diagnosticsService,
+		activityService:    activityService,
 		wsMetrics:          defaultWebSocketMetrics,
 		logStreams:         make(map[string]*wsLogStream),
 		cgroupCache:        cgroup.NewCache(cgroupCacheTTL),
-		gpuMonitor:         system.NewGPUMonitor(cfg.GPUMonitoringEnabled, cfg.GPUType),
+		gpuMonitor:         systemService.GPUMonitor(),
 		diskUsagePathCache: hot.NewHotCache[struct{}, string](hot.LRU, 1).
 			WithTTL(5 * time.Minute).
 			Build(),
@@ -258,6 +316,10 @@ type logStreamParams struct {
 	follow     bool
 	timestamps bool
 	batched    bool
+	details    bool
+	// mergeWindow re-orders swarm service log lines by timestamp; zero disables it.
+	mergeWindow time.Duration
+	filter      dockerutils.LogFilterOptions
 }
 
 func parseLogStreamParamsInternal(c *echo.Context) logStreamParams {
@@ -278,7 +340,48 @@ func parseLogStreamParamsInternal(c *echo.Context) logStreamParams {
 		timestamps: queryParamWithDefaultInternal(c, "timestamps", "false") == "true",
 		format:     format,
 		batched:    queryParamWithDefaultInternal(c, "batched", "false") == "true",
+		details:    queryParamWithDefaultInternal(c, "details", "false") == "true",
+		filter:     parseLogFilterOptionsInternal(c),
+	}
+}
+
+// parseLogFilterOptionsInternal reads the optional server-side filter params.
+// Malformed numbers are passed through as -1 so NewLogFilter rejects them.
+func parseLogFilterOptionsInternal(c *echo.Context) dockerutils.LogFilterOptions {
+	opts := dockerutils.LogFilterOptions{
+		Grep:      c.QueryParam("grep"),
+		GrepRegex: c.QueryParam("grepRegex") == "true",
+	}
+	if v := c.QueryParam("maxLinesPerSecond"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			n = -1
+		}
+		opts.MaxLinesPerSecond = n
+	}
+	if v := c.QueryParam("bytesLimit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			n = -1
+		}
+		opts.BytesLimit = n
 	}
+	return opts
+}
+
+// maxLogMergeWindow bounds how long service log lines may be held back for merging.
+const maxLogMergeWindow = 10 * time.Second
+
+// parseLogMergeWindowInternal parses the mergeWindow query param; empty means no merging.
+func parseLogMergeWindowInternal(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil || window < 0 || window > maxLogMergeWindow {
+		return 0, errors.Errorf("mergeWindow must be a duration between 0s and %s", maxLogMergeWindow)
+	}
+	return window, nil
 }
 
 func queryParamWithDefaultInternal(c *echo.Context, key, def string) string {
@@ -303,7 +406,7 @@ func (h *WebSocketHandler) serveLogStreamInternal(
 		return
 	}
 
-	streamKey := buildLogStreamKeyInternal(c.Param("id"), kind, resourceID, params.format, params.batched, params.follow, params.tail, params.since, params.timestamps)
+	streamKey := buildLogStreamKeyInternal(c.Param("id"), kind, resourceID, params.format, params.batched, params.follow, params.tail, params.since, params.timestamps, params.details, params.mergeWindow, params.filter)
 	stream := h.getOrCreateLogStreamInternal(streamKey, func(onEmpty func(*wsLogStream)) *wsLogStream {
 		return hubBuilder(streamKey, onEmpty)
 	})
@@ -565,6 +668,10 @@ func mapLogLinesInternal[T any](ctx context.Context, lines <-chan string, transf
 //	@Param			timestamps	query	bool	false	"Show timestamps"				default(false)
 //	@Param			format		query	string	false	"Output format (text or json)"	default(text)
 //	@Param			batched		query	bool	false	"Batch log messages"			default(false)
+//	@Param			grep		query	string	false	"Only stream lines containing this text"
+//	@Param			grepRegex	query	bool	false	"Treat grep as a regular expression"	default(false)
+//	@Param			maxLinesPerSecond	query	int	false	"Throttle output, replacing excess lines with a suppression marker"
+//	@Param			bytesLimit	query	int	false	"Stop non-follow reads after this many bytes"
 //	@Router			/api/environments/{id}/ws/containers/{containerId}/logs [get]
 func (h *WebSocketHandler) ContainerLogs(c *echo.Context) error {
 	containerID := c.Param("containerId")
@@ -573,13 +680,18 @@ func (h *WebSocketHandler) ContainerLogs(c *echo.Context) error {
 	}
 
 	params := parseLogStreamParamsInternal(c)
+	filter, err := dockerutils.NewLogFilter(params.filter)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+	}
+
 	h.serveLogStreamInternal(c, systemtypes.WSKindContainerLogs, containerID, params, func(streamKey string, onEmpty func(*wsLogStream)) *wsLogStream {
 		return h.startLogHubInternal(
 			streamKey,
 			containerID,
 			"container",
 			params,
-			h.streamContainerLogsInternal,
+			h.containerLogStreamerWithFilterInternal(filter),
 			normalizeContainerLogMessageInternal,
 			nil,
 			onEmpty,
@@ -605,21 +717,31 @@ func (h *WebSocketHandler) ContainerLogs(c *echo.Context) error {
 //	@Param			timestamps	query	bool	false	"Show timestamps"				default(false)
 //	@Param			format		query	string	false	"Output format (text or json)"	default(text)
 //	@Param			batched		query	bool	false	"Batch log messages"			default(false)
+//	@Param			details		query	bool	false	"Label lines with task ID and node"	default(false)
+//	@Param			mergeWindow	query	string	false	"With timestamps, merge lines from all tasks in timestamp order, buffering for this duration (e.g. 500ms) while following"
 //	@Router			/api/environments/{id}/ws/swarm/services/{serviceId}/logs [get]
 func (h *WebSocketHandler) ServiceLogs(c *echo.Context) error {
+	environmentID := c.Param("id")
 	serviceID := c.Param("serviceId")
 	if strings.TrimSpace(serviceID) == "" {
 		return c.JSON(http.StatusBadRequest, map[string]any{"success": false, "error": "Service ID is required"})
 	}
 
 	params := parseLogStreamParamsInternal(c)
+	mergeWindow, err := parseLogMergeWindowInternal(c.QueryParam("mergeWindow"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+	}
+	params.mergeWindow = mergeWindow
 	h.serveLogStreamInternal(c, systemtypes.WSKindServiceLogs, serviceID, params, func(streamKey string, onEmpty func(*wsLogStream)) *wsLogStream {
 		return h.startLogHubInternal(
 			streamKey,
 			serviceID,
 			"service",
 			params,
-			h.swarmService.StreamServiceLogs,
+			func(ctx context.Context, serviceID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool) error {
+				return h.swarmService.StreamServiceLogs(ctx, environmentID, serviceID, logsChan, follow, tail, since, timestamps, params.details, params.mergeWindow)
+			},
 			normalizeContainerLogMessageInternal,
 			nil,
 			onEmpty,
@@ -742,10 +864,12 @@ func (h *WebSocketHandler) runContainerStatsHubInternal(containerID string, hub
 	}()
 }
 
-// ContainerExec provides interactive terminal access to a container.
+// ContainerExec provides interactive terminal access to a container. Input is
+// sent as binary frames; a text frame of the form
+// {"type":"resize","rows":40,"cols":120} resizes the TTY.
 //
 //	@Summary		Execute command in container via WebSocket
-//	@Description	Interactive terminal access to a container over WebSocket
+//	@Description	Interactive terminal access to a container over WebSocket. Send input as binary frames and resize control messages as JSON text frames.
 //	@Tags			WebSocket
 //	@Param			id			path	string	true	"Environment ID"
 //	@Param			containerId	path	string	true	"Container ID"
@@ -758,6 +882,11 @@ func (h *WebSocketHandler) ContainerExec(c *echo.Context) error {
 	}
 
 	shell := queryParamWithDefaultInternal(c, "shell", "/bin/sh")
+	resize := c.QueryParam(execResizeQueryParam) == "1"
+
+	if err := h.checkMaintenanceInternal(c); err != nil {
+		return c.JSON(http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	}
 
 	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
 	if err != nil {
@@ -782,7 +911,7 @@ func (h *WebSocketHandler) ContainerExec(c *echo.Context) error {
 	})
 	go h.pingExecConnInternal(ctx, conn, execPongWait*9/10)
 
-	h.runContainerExecInternal(ctx, cancel, conn, containerID, shell)
+	h.runContainerExecInternal(ctx, cancel, conn, containerID, shell, resize)
 	return nil
 }
 
@@ -804,7 +933,11 @@ func (h *WebSocketHandler) pingExecConnInternal(ctx context.Context, conn *webso
 	}
 }
 
-func (h *WebSocketHandler) runContainerExecInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, containerID, shell string) {
+// runContainerExecInternal runs shell in the container and pipes it over conn.
+// With resize set, the client asked to negotiate TTY resizing: the handler
+// acknowledges before any output and then treats resize frames as control
+// messages. Without it, every frame goes to stdin as older agents do.
+func (h *WebSocketHandler) runContainerExecInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, containerID, shell string, resize bool) {
 	// Create exec instance
 	execID, err := h.containerService.CreateExec(ctx, containerID, []string{shell})
 	if err != nil {
@@ -822,9 +955,24 @@ func (h *WebSocketHandler) runContainerExecInternal(ctx context.Context, cancel
 	defer cleanup()
 	h.watchExecContextInternal(ctx, execID, containerID, cleanup)
 
+	var resizer *execResizer
+	if resize {
+		// Written before the output pipe starts, which owns the connection's writes afterwards.
+		if err := conn.WriteMessage(websocket.TextMessage, execResizeReadyMessage); err != nil {
+			slog.Debug("Exec websocket write error", "execID", execID, "containerID", containerID, "error", err)
+			return
+		}
+		resizer = newExecResizerInternal(execResizeDebounce, func(rows, cols uint) {
+			if err := execSession.Resize(ctx, rows, cols); err != nil {
+				slog.Debug("Failed to resize exec TTY", "execID", execID, "containerID", containerID, "rows", rows, "cols", cols, "error", err)
+			}
+		})
+		defer resizer.stopInternal()
+	}
+
 	done := make(chan struct{})
 	go h.pipeExecOutputInternal(ctx, conn, execSession.Stdout(), execID, containerID, done)
-	go h.pipeExecInputInternal(ctx, cancel, conn, execSession.Stdin(), execID, containerID)
+	go h.pipeExecInputInternal(ctx, cancel, conn, execSession.Stdin(), resizer, execID, containerID)
 
 	<-done
 }
@@ -877,7 +1025,11 @@ func (h *WebSocketHandler) pipeExecOutputInternal(ctx context.Context, conn *web
 	}
 }
 
-func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, stdin io.Writer, execID, containerID string) {
+// pipeExecInputInternal forwards terminal input to the exec stdin. Binary
+// frames are always input. When resizing was negotiated, text frames are
+// checked for a resize control message first so older clients that send
+// keystrokes as text keep working; a nil resizer forwards every frame.
+func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, stdin io.Writer, resizer *execResizer, execID, containerID string) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -885,12 +1037,18 @@ func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel con
 		default:
 		}
 
-		_, data, err := conn.ReadMessage()
+		msgType, data, err := conn.ReadMessage()
 		if err != nil {
 			slog.Debug("Exec websocket read error", "execID", execID, "containerID", containerID, "error", err)
 			cancel()
 			return
 		}
+		if msgType == websocket.TextMessage && resizer != nil {
+			if rows, cols, ok := parseExecResizeMessageInternal(data); ok {
+				resizer.requestInternal(rows, cols)
+				continue
+			}
+		}
 		if _, err := stdin.Write(data); err != nil {
 			slog.Debug("Exec stdin write error", "execID", execID, "containerID", containerID, "error", err)
 			return
@@ -898,6 +1056,100 @@ func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel con
 	}
 }
 
+// execResizeDebounce is how long the exec TTY size must stay unchanged before
+// it is applied, so dragging a window edge results in one ExecResize call.
+const execResizeDebounce = 100 * time.Millisecond
+
+// execResizeQueryParam is the exec websocket query parameter a client sets to
+// "1" to negotiate resize control messages.
+const execResizeQueryParam = "resize"
+
+// execResizeReadyMessage acknowledges resize negotiation. Agents that predate
+// it never send this, so the client keeps resize frames away from their stdin.
+var execResizeReadyMessage = []byte(`{"type":"resize-ready"}`)
+
+// maxExecTTYDimension bounds rows and cols to what a TTY winsize can hold.
+const maxExecTTYDimension = 1<<16 - 1
+
+// execControlMessage is a JSON control frame the terminal client sends as a
+// text message, e.g. {"type":"resize","rows":40,"cols":120}.
+type execControlMessage struct {
+	Type string `json:"type"`
+	Rows int    `json:"rows"`
+	Cols int    `json:"cols"`
+}
+
+// parseExecResizeMessageInternal reports whether payload is a resize control
+// message. Out-of-range dimensions are returned as zero so they are ignored
+// rather than forwarded to the shell as input.
+func parseExecResizeMessageInternal(payload []byte) (rows, cols uint, ok bool) {
+	if len(payload) == 0 || payload[0] != '{' {
+		return 0, 0, false
+	}
+	var msg execControlMessage
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Type != "resize" {
+		return 0, 0, false
+	}
+	if msg.Rows <= 0 || msg.Cols <= 0 || msg.Rows > maxExecTTYDimension || msg.Cols > maxExecTTYDimension {
+		return 0, 0, true
+	}
+	return uint(msg.Rows), uint(msg.Cols), true
+}
+
+// execResizer coalesces resize requests and applies only the latest size once
+// no new request has arrived for the debounce delay.
+type execResizer struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	timer   *time.Timer
+	rows    uint
+	cols    uint
+	stopped bool
+	resize  func(rows, cols uint)
+}
+
+func newExecResizerInternal(delay time.Duration, resize func(rows, cols uint)) *execResizer {
+	return &execResizer{delay: delay, resize: resize}
+}
+
+func (r *execResizer) requestInternal(rows, cols uint) {
+	if rows == 0 || cols == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	r.rows, r.cols = rows, cols
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.timer = time.AfterFunc(r.delay, r.fireInternal)
+}
+
+func (r *execResizer) fireInternal() {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	rows, cols := r.rows, r.cols
+	r.mu.Unlock()
+
+	r.resize(rows, cols)
+}
+
+func (r *execResizer) stopInternal() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stopped = true
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
+
 // ============================================================================
 // System WebSocket Endpoints
 // ============================================================================
@@ -990,6 +1242,7 @@ func (h *WebSocketHandler) releaseSystemStatsSamplerInternal() {
 		h.systemStatsSampler.clients--
 	}
 	if h.systemStatsSampler.clients == 0 && h.systemStatsSampler.running {
+		h.systemStatsSampler.background = 0
 		cancel = h.systemStatsSampler.cancel
 		h.systemStatsSampler.cancel = nil
 		h.systemStatsSampler.ready = nil
@@ -1002,15 +1255,47 @@ func (h *WebSocketHandler) releaseSystemStatsSamplerInternal() {
 	}
 }
 
+// setSystemStatsClientBackgroundInternal records a viewer moving between the
+// foreground and background so the shared sampler can slow down when nobody is looking.
+func (h *WebSocketHandler) setSystemStatsClientBackgroundInternal(background bool) {
+	h.systemStatsSampler.lifecycleMu.Lock()
+	defer h.systemStatsSampler.lifecycleMu.Unlock()
+
+	if background {
+		h.systemStatsSampler.background++
+	} else if h.systemStatsSampler.background > 0 {
+		h.systemStatsSampler.background--
+	}
+}
+
+// systemStatsSamplerIntervalInternal returns the background interval only when every
+// connected viewer is in the background; a single focused viewer keeps 1s sampling.
+func (h *WebSocketHandler) systemStatsSamplerIntervalInternal() time.Duration {
+	h.systemStatsSampler.lifecycleMu.Lock()
+	defer h.systemStatsSampler.lifecycleMu.Unlock()
+
+	if h.systemStatsSampler.clients > 0 && h.systemStatsSampler.background >= h.systemStatsSampler.clients {
+		return systemStatsBackgroundInterval
+	}
+	return systemStatsSampleInterval
+}
+
+// runSystemStatsSamplerInternal ticks every second but only samples once the current
+// interval has elapsed, so a viewer returning to the foreground gets fresh data quickly.
 func (h *WebSocketHandler) runSystemStatsSamplerInternal(ctx context.Context) {
-	ticker := time.NewTicker(time.Second)
+	ticker := time.NewTicker(systemStatsSampleInterval)
 	defer ticker.Stop()
 
+	lastSample := time.Now()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case now := <-ticker.C:
+			if now.Sub(lastSample) < h.systemStatsSamplerIntervalInternal() {
+				continue
+			}
+			lastSample = now
 			h.updateCPUCacheInternal(0)
 			h.storeSystemStatsSnapshotInternal(h.collectSystemStatsSnapshotInternal(ctx))
 		}
@@ -1047,14 +1332,17 @@ func (h *WebSocketHandler) collectSystemStats(ctx context.Context) systemtypes.S
 	cpuCount := h.getCPUCount()
 	memUsed, memTotal := h.getMemoryInfo()
 	cpuCount, memUsed, memTotal = h.applyCgroupLimits(cpuCount, memUsed, memTotal)
+	swapUsed, swapTotal := h.getSwapInfoInternal()
 	diskUsed, diskTotal := h.getDiskInfo(ctx)
 	hostname := h.getHostname()
 	gpuStats, gpuCount := h.getGPUInfo(ctx)
 
-	return systemtypes.SystemStats{
+	stats := systemtypes.SystemStats{
 		CPUUsage:     cpuUsage,
 		MemoryUsage:  memUsed,
 		MemoryTotal:  memTotal,
+		SwapUsed:     swapUsed,
+		SwapTotal:    swapTotal,
 		DiskUsage:    diskUsed,
 		DiskTotal:    diskTotal,
 		CPUCount:     cpuCount,
@@ -1064,6 +1352,11 @@ func (h *WebSocketHandler) collectSystemStats(ctx context.Context) systemtypes.S
 		GPUCount:     gpuCount,
 		GPUs:         gpuStats,
 	}
+	if avg := getLoadAverageInternal(); avg != nil {
+		stats.Load1, stats.Load5, stats.Load15 = &avg.Load1, &avg.Load5, &avg.Load15
+	}
+	h.applyIOThroughputInternal(ctx, &stats)
+	return stats
 }
 
 // getCPUCount returns the number of CPUs.
@@ -1106,6 +1399,147 @@ func (h *WebSocketHandler) getMemoryInfo() (uint64, uint64) {
 	return used, memInfo.Total
 }
 
+// getSwapInfoInternal returns swap usage and total.
+func (h *WebSocketHandler) getSwapInfoInternal() (uint64, uint64) {
+	swapInfo, err := mem.SwapMemory()
+	if err != nil || swapInfo == nil {
+		return 0, 0
+	}
+	return swapInfo.Used, swapInfo.Total
+}
+
+// getLoadAverageInternal returns the host load averages, or nil on platforms
+// such as Windows that have no load average.
+func getLoadAverageInternal() *load.AvgStat {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	avg, err := load.Avg()
+	if err != nil {
+		return nil
+	}
+	return avg
+}
+
+// ioCounterSample is one reading of the host's cumulative disk and network byte
+// counters. hasDisk/hasNet are false where the platform exposes no counters.
+type ioCounterSample struct {
+	at        time.Time
+	hasDisk   bool
+	diskRead  uint64
+	diskWrite uint64
+	hasNet    bool
+	netRx     uint64
+	netTx     uint64
+}
+
+// applyIOThroughputInternal reads the I/O counters and fills the throughput fields
+// with the rate since the previous sample. The first sample after the sampler starts,
+// and any counter that went backwards (device removed or counter reset), leaves them unset.
+func (h *WebSocketHandler) applyIOThroughputInternal(ctx context.Context, stats *systemtypes.SystemStats) {
+	current := readIOCountersInternal(ctx)
+
+	h.systemStatsSampler.ioMu.Lock()
+	prev := h.systemStatsSampler.ioPrev
+	h.systemStatsSampler.ioPrev = current
+	h.systemStatsSampler.ioMu.Unlock()
+
+	// A gap longer than the slowest sampling interval means the sampler was stopped
+	// in between; averaging over it would report a misleadingly low rate.
+	elapsed := current.at.Sub(prev.at)
+	if prev.at.IsZero() || elapsed <= 0 || elapsed > 3*systemStatsBackgroundInterval {
+		return
+	}
+	seconds := elapsed.Seconds()
+	if current.hasDisk && prev.hasDisk {
+		stats.DiskReadBytesPerSec = ioRateInternal(prev.diskRead, current.diskRead, seconds)
+		stats.DiskWriteBytesPerSec = ioRateInternal(prev.diskWrite, current.diskWrite, seconds)
+	}
+	if current.hasNet && prev.hasNet {
+		stats.NetRxBytesPerSec = ioRateInternal(prev.netRx, current.netRx, seconds)
+		stats.NetTxBytesPerSec = ioRateInternal(prev.netTx, current.netTx, seconds)
+	}
+}
+
+func ioRateInternal(prev, current uint64, elapsedSeconds float64) *float64 {
+	if current < prev {
+		return nil
+	}
+	return new(float64(current-prev) / elapsedSeconds)
+}
+
+func readIOCountersInternal(ctx context.Context) ioCounterSample {
+	sample := ioCounterSample{at: time.Now()}
+	if counters, err := disk.IOCountersWithContext(ctx); err == nil && len(counters) > 0 {
+		sample.diskRead, sample.diskWrite = sumDiskIOCountersInternal(counters, isPhysicalBlockDeviceInternal)
+		sample.hasDisk = true
+	}
+	if counters, err := gopsnet.IOCountersWithContext(ctx, true); err == nil && len(counters) > 0 {
+		sample.netRx, sample.netTx = sumNetIOCountersInternal(counters, isPhysicalNetInterfaceInternal)
+		sample.hasNet = true
+	}
+	return sample
+}
+
+// sumDiskIOCountersInternal totals bytes read and written across physical disks.
+// Linux lists partitions, device-mapper and md devices next to the disks backing
+// them, so only devices isPhysical accepts are counted to avoid double counting.
+func sumDiskIOCountersInternal(counters map[string]disk.IOCountersStat, isPhysical func(name string) bool) (read, write uint64) {
+	for name, counter := range counters {
+		if !isPhysical(name) {
+			continue
+		}
+		read += counter.ReadBytes
+		write += counter.WriteBytes
+	}
+	return read, write
+}
+
+// sumNetIOCountersInternal totals received and sent bytes, skipping loopback. When
+// any physical NIC is visible only those are counted, so bridge and veth traffic is
+// not added on top of it; inside a bridged container, where every interface is
+// virtual, all non-loopback interfaces are counted instead.
+func sumNetIOCountersInternal(counters []gopsnet.IOCountersStat, isPhysical func(name string) bool) (rx, tx uint64) {
+	physicalOnly := false
+	for _, counter := range counters {
+		if counter.Name != "lo" && isPhysical(counter.Name) {
+			physicalOnly = true
+			break
+		}
+	}
+	for _, counter := range counters {
+		if counter.Name == "lo" || strings.HasPrefix(counter.Name, "Loopback") {
+			continue
+		}
+		if physicalOnly && !isPhysical(counter.Name) {
+			continue
+		}
+		rx += counter.BytesRecv
+		tx += counter.BytesSent
+	}
+	return rx, tx
+}
+
+// isPhysicalBlockDeviceInternal reports whether a block device is backed by
+// hardware. Outside Linux the counters already cover whole disks only.
+func isPhysicalBlockDeviceInternal(name string) bool {
+	if runtime.GOOS != "linux" {
+		return true
+	}
+	_, err := os.Stat(filepath.Join("/sys/block", name, "device"))
+	return err == nil
+}
+
+// isPhysicalNetInterfaceInternal reports whether a network interface is backed by
+// hardware; bridges, veth pairs and tunnels have no device link in sysfs.
+func isPhysicalNetInterfaceInternal(name string) bool {
+	if runtime.GOOS != "linux" {
+		return true
+	}
+	_, err := os.Stat(filepath.Join("/sys/class/net", name, "device"))
+	return err == nil
+}
+
 // applyCgroupLimits applies cgroup limits when running in an LXC (or similar)
 // container where the limits represent the real hardware budget.
 //
@@ -1236,7 +1670,9 @@ func (h *WebSocketHandler) getCachedCgroupLimitsInternal() *cgroup.Limits {
 	return h.cgroupCache.Get()
 }
 
-// SystemStats streams system stats over WebSocket.
+// SystemStats streams system stats over WebSocket. Clients may send "background"
+// when hidden to slow updates to every 10s and "foreground" to resume the
+// requested interval.
 //
 //	@Summary		Get system stats via WebSocket
 //	@Description	Stream system resource statistics over WebSocket connection
@@ -1285,7 +1721,8 @@ func (h *WebSocketHandler) SystemStats(c *echo.Context) error {
 		return nil
 	})
 
-	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	foregroundInterval := time.Duration(interval) * time.Second
+	ticker := time.NewTicker(foregroundInterval)
 	defer ticker.Stop()
 	pingTicker := time.NewTicker(statsPingPeriod)
 	defer pingTicker.Stop()
@@ -1298,7 +1735,24 @@ func (h *WebSocketHandler) SystemStats(c *echo.Context) error {
 	}
 	defer h.releaseSystemStatsSamplerInternal()
 
-	go h.readSystemStatsPumpInternal(ctx, cancel, conn)
+	visibility := make(chan bool, 1)
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, func(payload []byte) {
+		background, ok := parseSystemStatsVisibilityInternal(payload)
+		if !ok {
+			return
+		}
+		select {
+		case visibility <- background:
+		case <-ctx.Done():
+		}
+	})
+
+	inBackground := false
+	defer func() {
+		if inBackground {
+			h.setSystemStatsClientBackgroundInternal(false)
+		}
+	}()
 
 	send := func() error {
 		stats := h.latestSystemStatsSnapshotInternal()
@@ -1318,6 +1772,20 @@ func (h *WebSocketHandler) SystemStats(c *echo.Context) error {
 			if err := send(); err != nil {
 				return nil
 			}
+		case background := <-visibility:
+			if background == inBackground {
+				continue
+			}
+			inBackground = background
+			h.setSystemStatsClientBackgroundInternal(background)
+			if background {
+				ticker.Reset(max(foregroundInterval, systemStatsBackgroundInterval))
+				continue
+			}
+			ticker.Reset(foregroundInterval)
+			if err := send(); err != nil {
+				return nil
+			}
 		case <-pingTicker.C:
 			_ = conn.SetWriteDeadline(time.Now().Add(statsPingWriteWait))
 			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
@@ -1328,21 +1796,39 @@ func (h *WebSocketHandler) SystemStats(c *echo.Context) error {
 }
 
 // readSystemStatsPumpInternal is the single reader for the SystemStats websocket.
-// Do not add additional readers for this connection.
-func (h *WebSocketHandler) readSystemStatsPumpInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn) {
+// Do not add additional readers for this connection. Text messages are passed to
+// onMessage when it is non-nil.
+func (h *WebSocketHandler) readSystemStatsPumpInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, onMessage func([]byte)) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			if _, _, err := conn.ReadMessage(); err != nil {
+			messageType, payload, err := conn.ReadMessage()
+			if err != nil {
 				cancel()
 				return
 			}
+			if onMessage != nil && messageType == websocket.TextMessage {
+				onMessage(payload)
+			}
 		}
 	}
 }
 
+// parseSystemStatsVisibilityInternal recognizes the "background" and "foreground"
+// messages a stats viewer sends when its tab loses or regains focus.
+func parseSystemStatsVisibilityInternal(payload []byte) (background bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(string(payload))) {
+	case "background":
+		return true, true
+	case "foreground":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 func (h *WebSocketHandler) getDiskUsagePath(ctx context.Context) string {
 	if h.diskUsagePathCache == nil {
 		h.diskUsagePathCache = hot.NewHotCache[struct{}, string](hot.LRU, 1).
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v5"
+	"github.com/shirou/gopsutil/v4/disk"
+	gopsnet "github.com/shirou/gopsutil/v4/net"
 	"github.com/stretchr/testify/require"
 
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/system"
@@ -620,3 +623,190 @@ func TestWebSocketHandler_GetCachedCgroupLimitsInternal_DeduplicatesRefresh(t *t
 	}
 	require.Equal(t, int32(1), calls.Load())
 }
+
+func TestWebSocketHandler_SystemStatsSamplerInterval_BacksOffWhenAllViewersBackground(t *testing.T) {
+	handler := newTestWebSocketHandler()
+	handler.systemStatsSampler.clients = 2
+
+	require.Equal(t, systemStatsSampleInterval, handler.systemStatsSamplerIntervalInternal())
+
+	handler.setSystemStatsClientBackgroundInternal(true)
+	require.Equal(t, systemStatsSampleInterval, handler.systemStatsSamplerIntervalInternal())
+
+	handler.setSystemStatsClientBackgroundInternal(true)
+	require.Equal(t, systemStatsBackgroundInterval, handler.systemStatsSamplerIntervalInternal())
+
+	handler.setSystemStatsClientBackgroundInternal(false)
+	require.Equal(t, systemStatsSampleInterval, handler.systemStatsSamplerIntervalInternal())
+}
+
+func TestParseSystemStatsVisibilityInternal(t *testing.T) {
+	background, ok := parseSystemStatsVisibilityInternal([]byte(" Background\n"))
+	require.True(t, ok)
+	require.True(t, background)
+
+	background, ok = parseSystemStatsVisibilityInternal([]byte("foreground"))
+	require.True(t, ok)
+	require.False(t, background)
+
+	_, ok = parseSystemStatsVisibilityInternal([]byte(`{"type":"ping"}`))
+	require.False(t, ok)
+}
+
+func TestSumDiskIOCountersInternal_SkipsVirtualDevices(t *testing.T) {
+	counters := map[string]disk.IOCountersStat{
+		"sda":     {ReadBytes: 1000, WriteBytes: 2000},
+		"sda1":    {ReadBytes: 900, WriteBytes: 1800},
+		"dm-0":    {ReadBytes: 900, WriteBytes: 1800},
+		"nvme0n1": {ReadBytes: 10, WriteBytes: 20},
+	}
+	physical := func(name string) bool { return name == "sda" || name == "nvme0n1" }
+
+	read, write := sumDiskIOCountersInternal(counters, physical)
+	require.Equal(t, uint64(1010), read)
+	require.Equal(t, uint64(2020), write)
+}
+
+func TestSumNetIOCountersInternal(t *testing.T) {
+	counters := []gopsnet.IOCountersStat{
+		{Name: "lo", BytesRecv: 5000, BytesSent: 5000},
+		{Name: "eth0", BytesRecv: 100, BytesSent: 200},
+		{Name: "docker0", BytesRecv: 40, BytesSent: 80},
+		{Name: "veth1a2b", BytesRecv: 40, BytesSent: 80},
+	}
+
+	rx, tx := sumNetIOCountersInternal(counters, func(name string) bool { return name == "eth0" })
+	require.Equal(t, uint64(100), rx)
+	require.Equal(t, uint64(200), tx)
+
+	// Inside a bridged container every interface is virtual.
+	rx, tx = sumNetIOCountersInternal(counters, func(string) bool { return false })
+	require.Equal(t, uint64(180), rx)
+	require.Equal(t, uint64(360), tx)
+}
+
+func TestIORateInternal(t *testing.T) {
+	rate := ioRateInternal(1000, 5000, 2)
+	require.NotNil(t, rate)
+	require.InDelta(t, 2000, *rate, 0.001)
+
+	require.Nil(t, ioRateInternal(5000, 1000, 2), "counter reset yields no rate")
+}
+
+func TestParseExecResizeMessageInternal(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantRows uint
+		wantCols uint
+		wantOK   bool
+	}{
+		{name: "resize", payload: `{"type":"resize","rows":40,"cols":120}`, wantRows: 40, wantCols: 120, wantOK: true},
+		{name: "zero dimensions", payload: `{"type":"resize","rows":0,"cols":80}`, wantOK: true},
+		{name: "negative dimensions", payload: `{"type":"resize","rows":-1,"cols":80}`, wantOK: true},
+		{name: "oversized dimensions", payload: `{"type":"resize","rows":70000,"cols":80}`, wantOK: true},
+		{name: "other control type", payload: `{"type":"ping"}`},
+		{name: "plain input", payload: "ls -la\r"},
+		{name: "brace input", payload: "{"},
+		{name: "empty", payload: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, cols, ok := parseExecResizeMessageInternal([]byte(tt.payload))
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantRows, rows)
+			require.Equal(t, tt.wantCols, cols)
+		})
+	}
+}
+
+func TestExecResizerInternal_CoalescesBurstToLatestSize(t *testing.T) {
+	type size struct{ rows, cols uint }
+	applied := make(chan size, 4)
+	resizer := newExecResizerInternal(20*time.Millisecond, func(rows, cols uint) {
+		applied <- size{rows, cols}
+	})
+	defer resizer.stopInternal()
+
+	resizer.requestInternal(24, 80)
+	resizer.requestInternal(30, 100)
+	resizer.requestInternal(0, 100)
+	resizer.requestInternal(40, 120)
+
+	select {
+	case got := <-applied:
+		require.Equal(t, size{40, 120}, got)
+	case <-time.After(time.Second):
+		t.Fatal("resize was not applied")
+	}
+
+	select {
+	case got := <-applied:
+		t.Fatalf("unexpected extra resize %+v", got)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestExecResizerInternal_StopDropsPendingResize(t *testing.T) {
+	var calls atomic.Int32
+	resizer := newExecResizerInternal(10*time.Millisecond, func(uint, uint) {
+		calls.Add(1)
+	})
+
+	resizer.requestInternal(24, 80)
+	resizer.stopInternal()
+	resizer.requestInternal(30, 100)
+
+	time.Sleep(40 * time.Millisecond)
+	require.Zero(t, calls.Load())
+}
+
+func TestPipeExecInputInternal_ResizeFramesNeedNegotiation(t *testing.T) {
+	frame := []byte(`{"type":"resize","rows":40,"cols":120}`)
+	h := &WebSocketHandler{}
+
+	t.Run("not negotiated", func(t *testing.T) {
+		clientConn, serverConn, cleanup := newTestWSPairInternal(t)
+		defer cleanup()
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		stdin, stdinWriter := io.Pipe()
+		go h.pipeExecInputInternal(ctx, cancel, serverConn, stdinWriter, nil, "exec-1", "container-1")
+
+		require.NoError(t, clientConn.WriteMessage(websocket.TextMessage, frame))
+		got := make([]byte, len(frame))
+		_, err := io.ReadFull(stdin, got)
+		require.NoError(t, err)
+		require.Equal(t, frame, got)
+	})
+
+	t.Run("negotiated", func(t *testing.T) {
+		clientConn, serverConn, cleanup := newTestWSPairInternal(t)
+		defer cleanup()
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		applied := make(chan [2]uint, 1)
+		resizer := newExecResizerInternal(time.Millisecond, func(rows, cols uint) { applied <- [2]uint{rows, cols} })
+		defer resizer.stopInternal()
+
+		stdin, stdinWriter := io.Pipe()
+		go h.pipeExecInputInternal(ctx, cancel, serverConn, stdinWriter, resizer, "exec-1", "container-1")
+
+		require.NoError(t, clientConn.WriteMessage(websocket.TextMessage, frame))
+		require.NoError(t, clientConn.WriteMessage(websocket.BinaryMessage, []byte("ls\r")))
+		got := make([]byte, len("ls\r"))
+		_, err := io.ReadFull(stdin, got)
+		require.NoError(t, err)
+		require.Equal(t, "ls\r", string(got))
+
+		select {
+		case size := <-applied:
+			require.Equal(t, [2]uint{40, 120}, size)
+		case <-time.After(time.Second):
+			t.Fatal("resize was not applied")
+		}
+	})
+}
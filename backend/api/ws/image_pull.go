@@ -0,0 +1,116 @@
+package ws
+
+import (
+	"context"
+	json "encoding/json/v2"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v5"
+
+	imagetypes "github.com/getarcaneapp/arcane/types/v2/image"
+	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+const (
+	// pullRequestWait bounds how long the server waits for the pull options message.
+	pullRequestWait = 10 * time.Second
+	// pullPongWait is the read deadline refreshed by client pongs while a pull runs.
+	pullPongWait = 60 * time.Second
+)
+
+// imagePullFrame is one message on the image pull socket: a layer progress
+// update, or the final frame with Done or Error set.
+type imagePullFrame struct {
+	imagetypes.PullProgress
+	Done  bool   `json:"done,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ImagePull pulls an image and streams per-layer progress over WebSocket.
+// The client sends the pull options as the first JSON message (same shape as the
+// POST /images/pull body); the server then emits a PullProgress frame for each
+// Docker progress update, ending with {"done":true} or {"error":"..."}. Closing
+// the socket cancels the pull.
+//
+//	@Summary		Pull an image with progress via WebSocket
+//	@Description	Pull an image and stream per-layer progress until the pull completes
+//	@Tags			WebSocket
+//	@Param			id	path	string	true	"Environment ID"
+//	@Router			/api/environments/{id}/ws/images/pull [get]
+func (h *WebSocketHandler) ImagePull(c *echo.Context) error {
+	if err := h.checkMaintenanceInternal(c); err != nil {
+		return c.JSON(http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return nil
+	}
+	environmentID := c.Param("id")
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindImagePull, ""))
+	defer h.wsMetrics.UnregisterConnection(connID)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close image pull websocket connection", "environmentID", environmentID, "error", err)
+		}
+	}()
+
+	conn.SetReadLimit(64 * 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(pullRequestWait))
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		return nil
+	}
+	var req imagetypes.PullOptions
+	if err := json.Unmarshal(payload, &req); err != nil {
+		h.writeImagePullFrameInternal(conn, imagePullFrame{Error: "Invalid pull request: " + err.Error()})
+		return nil
+	}
+	imageName := req.GetFullImageName()
+	if imageName == "" {
+		h.writeImagePullFrameInternal(conn, imagePullFrame{Error: "Image name is required"})
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(pullPongWait))
+	conn.SetPongHandler(func(string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(pullPongWait))
+		return nil
+	})
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, nil)
+	go h.pingExecConnInternal(ctx, conn, pullPongWait*9/10)
+
+	progressChan := make(chan imagetypes.PullProgress, 64)
+	pullErr := make(chan error, 1)
+	go func() {
+		defer close(progressChan)
+		pullErr <- h.imageService.PullWithProgress(ctx, imageName, req.GetCredentials(), progressChan)
+	}()
+
+	for progress := range progressChan {
+		h.writeImagePullFrameInternal(conn, imagePullFrame{PullProgress: progress})
+	}
+	if err := <-pullErr; err != nil {
+		h.writeImagePullFrameInternal(conn, imagePullFrame{Error: err.Error()})
+	} else {
+		h.writeImagePullFrameInternal(conn, imagePullFrame{Done: true})
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return nil
+}
+
+// writeImagePullFrameInternal sends one pull frame; write failures are ignored because
+// a closed socket already cancels the pull through the read pump.
+func (h *WebSocketHandler) writeImagePullFrameInternal(conn *websocket.Conn, frame imagePullFrame) {
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteJSON(frame); err != nil {
+		slog.Debug("Failed to write image pull progress", "error", err)
+	}
+}
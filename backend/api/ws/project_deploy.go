@@ -0,0 +1,241 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	json "encoding/json/v2"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v5"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	dockerutils "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
+	activitylib "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/activity"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
+	projecttypes "github.com/getarcaneapp/arcane/types/v2/project"
+	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+const (
+	// deployRequestWait bounds how long the server waits for the deploy options message.
+	deployRequestWait = 10 * time.Second
+	// deployPongWait is the read deadline refreshed by client pongs while a deploy runs.
+	deployPongWait = 60 * time.Second
+)
+
+// ProjectDeploy brings a project up and streams staged progress over WebSocket.
+// The client sends the deploy options as the first JSON message (same shape as
+// the POST /projects/{projectId}/up body, "{}" for defaults); the server then
+// emits a DeployProgressEvent as each stage starts and completes, one per compose
+// resource update attributed to its service, and plain progress lines. The final
+// "done" event carries every service's last status and the services that failed.
+// Closing the socket stops the stream but not the deploy, which finishes and
+// leaves the project status consistent as it does for the HTTP deploy. The deploy
+// is recorded as an activity, queued and completed the same way as the HTTP one.
+//
+//	@Summary		Deploy a project with progress via WebSocket
+//	@Description	Bring a project up and stream per-stage and per-service progress until it completes
+//	@Tags			WebSocket
+//	@Param			id			path	string	true	"Environment ID"
+//	@Param			projectId	path	string	true	"Project ID"
+//	@Router			/api/environments/{id}/ws/projects/{projectId}/up [get]
+func (h *WebSocketHandler) ProjectDeploy(c *echo.Context) error {
+	projectID := c.Param("projectId")
+	if strings.TrimSpace(projectID) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]any{"success": false, "error": "Project ID is required"})
+	}
+	user, ok := c.Get("currentUser").(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]any{"success": false, "error": "Authentication required"})
+	}
+	if err := h.checkMaintenanceInternal(c); err != nil {
+		return c.JSON(http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		slog.DebugContext(c.Request().Context(), "Failed to upgrade WebSocket for project deploy", "projectID", projectID, "error", err)
+		return nil
+	}
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindProjectDeploy, projectID))
+	defer h.wsMetrics.UnregisterConnection(connID)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close project deploy websocket connection", "projectID", projectID, "error", err)
+		}
+	}()
+
+	stream := &projectDeployStream{conn: conn, services: make(map[string]projecttypes.DeployServiceProgress)}
+
+	conn.SetReadLimit(64 * 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(deployRequestWait))
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		return nil
+	}
+	var options projecttypes.DeployOptions
+	if err := json.Unmarshal(payload, &options); err != nil {
+		stream.finish("Invalid deploy request: " + err.Error())
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(deployPongWait))
+	conn.SetPongHandler(func(string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(deployPongWait))
+		return nil
+	})
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, nil)
+	go h.pingExecConnInternal(ctx, conn, deployPongWait*9/10)
+
+	environmentID := c.Param("id")
+	activityID, runtimeCtx := activitylib.StartQueuedHandlerActivityForUser(
+		context.WithoutCancel(ctx),
+		h.activityService,
+		environmentID,
+		models.ActivityTypeProjectDeploy,
+		"project",
+		projectID,
+		projectID,
+		user,
+		"Starting deployment",
+		"Project deployment started",
+		models.JSON{"projectID": projectID},
+	)
+	activitylib.AwaitHandlerActivitySlot(runtimeCtx, h.activityService, activityID, environmentID)
+
+	writer := activitylib.NewWriter(runtimeCtx, h.activityService, activityID, stream, "Deploying project")
+	deployCtx := context.WithValue(runtimeCtx, dockerutils.ProgressWriterKey{}, writer)
+	deployCtx = projects.WithDeployObserver(deployCtx, stream)
+	err = h.projectService.DeployProject(deployCtx, projectID, *user, &options)
+	activitylib.FlushWriter(writer)
+	if err != nil {
+		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Project deployment failed", err)
+		stream.finish(err.Error())
+	} else {
+		activitylib.CompleteHandlerActivity(runtimeCtx, h.activityService, activityID, "Project deployment completed", nil)
+		stream.finish("")
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return nil
+}
+
+// projectDeployStream turns a deploy's stage reports, compose resource events
+// and {"log":...} progress frames into DeployProgressEvents on one socket.
+// Compose reports from several goroutines, so every write holds mu.
+type projectDeployStream struct {
+	conn *websocket.Conn
+
+	mu       sync.Mutex
+	stage    projecttypes.DeployProgressStage
+	services map[string]projecttypes.DeployServiceProgress
+	buffer   []byte
+}
+
+func (s *projectDeployStream) DeployStage(stage projecttypes.DeployProgressStage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stage != "" {
+		s.writeInternal(projecttypes.DeployProgressEvent{Stage: s.stage, Status: projecttypes.DeployProgressStatusCompleted})
+	}
+	s.stage = stage
+	s.writeInternal(projecttypes.DeployProgressEvent{Stage: stage, Status: projecttypes.DeployProgressStatusRunning})
+}
+
+func (s *projectDeployStream) DeployResource(event projecttypes.DeployProgressEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.Service != "" {
+		s.services[event.Service] = projecttypes.DeployServiceProgress{
+			Service: event.Service,
+			Status:  event.ResourceStatus,
+			Text:    event.Text,
+			Details: event.Details,
+		}
+	}
+	s.writeInternal(event)
+}
+
+// Write receives the deploy's progress writer output, which is NDJSON
+// {"log":"<line>"} frames, and forwards each line as a Line event for the
+// current stage. Anything that is not a log frame is forwarded verbatim.
+func (s *projectDeployStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buffer = append(s.buffer, p...)
+	for {
+		idx := bytes.IndexByte(s.buffer, '\n')
+		if idx < 0 {
+			break
+		}
+		raw := bytes.TrimSpace(s.buffer[:idx])
+		s.buffer = s.buffer[idx+1:]
+		if len(raw) == 0 {
+			continue
+		}
+		line := string(raw)
+		var frame struct {
+			Log string `json:"log"`
+		}
+		if err := json.Unmarshal(raw, &frame); err == nil && frame.Log != "" {
+			line = frame.Log
+		}
+		s.writeInternal(projecttypes.DeployProgressEvent{
+			Stage:  s.stage,
+			Status: projecttypes.DeployProgressStatusRunning,
+			Line:   line,
+		})
+	}
+	return len(p), nil
+}
+
+// finish closes out the current stage and sends the final "done" event; an
+// empty errMsg means the deploy succeeded.
+func (s *projectDeployStream) finish(errMsg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := projecttypes.DeployProgressStatusCompleted
+	if errMsg != "" {
+		status = projecttypes.DeployProgressStatusFailed
+	}
+	if s.stage != "" {
+		s.writeInternal(projecttypes.DeployProgressEvent{Stage: s.stage, Status: status, Error: errMsg})
+	}
+
+	done := projecttypes.DeployProgressEvent{
+		Stage:  projecttypes.DeployProgressStageDone,
+		Status: status,
+		Error:  errMsg,
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.services)) {
+		service := s.services[name]
+		done.Services = append(done.Services, service)
+		if service.Status == projecttypes.DeployResourceStatusError {
+			done.FailedServices = append(done.FailedServices, name)
+		}
+	}
+	s.writeInternal(done)
+}
+
+// writeInternal sends one event; write failures are ignored because the deploy
+// keeps running after the client goes away. Callers hold mu.
+func (s *projectDeployStream) writeInternal(event projecttypes.DeployProgressEvent) {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := s.conn.WriteJSON(event); err != nil {
+		slog.Debug("Failed to write project deploy progress", "stage", event.Stage, "error", err)
+	}
+}
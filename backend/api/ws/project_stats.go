@@ -0,0 +1,86 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v5"
+
+	projecttypes "github.com/getarcaneapp/arcane/types/v2/project"
+	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+// projectStatsPongWait is the read deadline refreshed by client pongs while
+// project stats stream.
+const projectStatsPongWait = 60 * time.Second
+
+// ProjectStats streams combined resource usage for a project's running
+// containers over WebSocket. Each message is a JSON project Stats snapshot with
+// summed CPU and memory plus a per-container breakdown; containers that start or
+// stop while the socket is open are picked up within a few seconds.
+//
+//	@Summary		Get project stats via WebSocket
+//	@Description	Stream combined CPU and memory usage across a project's containers
+//	@Tags			WebSocket
+//	@Param			id			path	string	true	"Environment ID"
+//	@Param			projectId	path	string	true	"Project ID"
+//	@Router			/api/environments/{id}/ws/projects/{projectId}/stats [get]
+func (h *WebSocketHandler) ProjectStats(c *echo.Context) error {
+	projectID := c.Param("projectId")
+	if strings.TrimSpace(projectID) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]any{"success": false, "error": "Project ID is required"})
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		slog.DebugContext(c.Request().Context(), "Failed to upgrade WebSocket for project stats", "projectID", projectID, "error", err)
+		return nil
+	}
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindProjectStats, projectID))
+	defer h.wsMetrics.UnregisterConnection(connID)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close project stats websocket connection", "projectID", projectID, "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(projectStatsPongWait))
+	conn.SetPongHandler(func(string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(projectStatsPongWait))
+		return nil
+	})
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, nil)
+	go h.pingExecConnInternal(ctx, conn, projectStatsPongWait*9/10)
+
+	statsChan := make(chan projecttypes.Stats, 4)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- h.projectService.StreamProjectStats(ctx, projectID, statsChan)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-streamErr:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				slog.DebugContext(ctx, "Project stats stream stopped", "projectID", projectID, "error", err)
+				writeStreamErrorCloseInternal(conn, websocket.CloseInternalServerErr, err)
+			}
+			return nil
+		case stats := <-statsChan:
+			_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteJSON(stats); err != nil {
+				return nil
+			}
+		}
+	}
+}
@@ -23,11 +23,17 @@ type proxiedWSRoute struct {
 func (h *WebSocketHandler) proxiedRoutes() []proxiedWSRoute {
 	return []proxiedWSRoute{
 		{"/projects/:projectId/logs", h.ProjectLogs, authz.PermProjectsLogs},
+		{"/projects/:projectId/stats", h.ProjectStats, authz.PermProjectsRead},
+		{"/projects/:projectId/up", h.ProjectDeploy, authz.PermProjectsDeploy},
 		{"/containers/:containerId/logs", h.ContainerLogs, authz.PermContainersLogs},
 		{"/containers/:containerId/stats", h.ContainerStats, authz.PermContainersRead},
 		{"/containers/:containerId/terminal", h.ContainerExec, authz.PermContainersExec},
 		{"/swarm/services/:serviceId/logs", h.ServiceLogs, authz.PermSwarmServicesLogs},
+		{"/swarm/services/:serviceId/events", h.ServiceEvents, authz.PermSwarmRead},
 		{"/system/stats", h.SystemStats, authz.PermSystemRead},
+		{"/system/events", h.DockerEvents, authz.PermSystemRead},
+		{"/system/prune", h.SystemPrune, authz.PermSystemPrune},
+		{"/images/pull", h.ImagePull, authz.PermImagesPull},
 	}
 }
 
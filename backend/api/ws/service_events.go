@@ -0,0 +1,99 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v5"
+
+	swarmtypes "github.com/getarcaneapp/arcane/types/v2/swarm"
+	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+// serviceEventsPongWait is the read deadline refreshed by client pongs while
+// service events stream.
+const serviceEventsPongWait = 60 * time.Second
+
+// maxCloseReasonBytes is the longest reason a WebSocket close frame can carry.
+const maxCloseReasonBytes = 123
+
+// ServiceEvents streams Docker events for a swarm service and its tasks over
+// WebSocket. Each message is a JSON ServiceEvent; the stream ends when the
+// client disconnects.
+//
+//	@Summary		Get swarm service events via WebSocket
+//	@Description	Stream Docker events for a swarm service and the containers of its tasks
+//	@Tags			WebSocket
+//	@Param			id			path	string	true	"Environment ID"
+//	@Param			serviceId	path	string	true	"Service ID"
+//	@Router			/api/environments/{id}/ws/swarm/services/{serviceId}/events [get]
+func (h *WebSocketHandler) ServiceEvents(c *echo.Context) error {
+	environmentID := c.Param("id")
+	serviceID := c.Param("serviceId")
+	if strings.TrimSpace(serviceID) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]any{"success": false, "error": "Service ID is required"})
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		slog.DebugContext(c.Request().Context(), "Failed to upgrade WebSocket for service events", "serviceID", serviceID, "error", err)
+		return nil
+	}
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindServiceEvents, serviceID))
+	defer h.wsMetrics.UnregisterConnection(connID)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close service events websocket connection", "serviceID", serviceID, "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(serviceEventsPongWait))
+	conn.SetPongHandler(func(string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(serviceEventsPongWait))
+		return nil
+	})
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, nil)
+	go h.pingExecConnInternal(ctx, conn, serviceEventsPongWait*9/10)
+
+	eventsChan := make(chan swarmtypes.ServiceEvent, 64)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- h.swarmService.StreamServiceEvents(ctx, environmentID, serviceID, eventsChan)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-streamErr:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				slog.DebugContext(ctx, "Swarm service event stream stopped", "serviceID", serviceID, "error", err)
+				writeStreamErrorCloseInternal(conn, websocket.CloseInternalServerErr, err)
+			}
+			return nil
+		case event := <-eventsChan:
+			_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteJSON(event); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// writeStreamErrorCloseInternal closes conn with code and err as the reason,
+// truncated to fit a close frame.
+func writeStreamErrorCloseInternal(conn *websocket.Conn, code int, err error) {
+	reason := err.Error()
+	if len(reason) > maxCloseReasonBytes {
+		reason = reason[:maxCloseReasonBytes]
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+}
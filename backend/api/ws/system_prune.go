@@ -0,0 +1,109 @@
+package ws
+
+import (
+	"context"
+	json "encoding/json/v2"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v5"
+
+	systemtypes "github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+const (
+	// pruneRequestWait bounds how long the server waits for the prune options message.
+	pruneRequestWait = 10 * time.Second
+	// prunePongWait is the read deadline refreshed by client pongs while a prune runs.
+	prunePongWait = 60 * time.Second
+)
+
+// SystemPrune runs a system prune and streams staged progress over WebSocket.
+// The client sends the prune options as the first JSON message (same shape as the
+// POST /system/prune body); the server then emits a PruneProgressEvent as each
+// category starts and completes, ending with a "done" event that carries the full
+// result. Closing the socket cancels the remaining prune calls.
+//
+//	@Summary		Prune Docker resources with progress via WebSocket
+//	@Description	Run a system prune and stream per-category progress until the final result
+//	@Tags			WebSocket
+//	@Param			id	path	string	true	"Environment ID"
+//	@Router			/api/environments/{id}/ws/system/prune [get]
+func (h *WebSocketHandler) SystemPrune(c *echo.Context) error {
+	if err := h.checkMaintenanceInternal(c); err != nil {
+		return c.JSON(http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return nil
+	}
+	environmentID := c.Param("id")
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindSystemPrune, ""))
+	defer h.wsMetrics.UnregisterConnection(connID)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close system prune websocket connection", "environmentID", environmentID, "error", err)
+		}
+	}()
+
+	conn.SetReadLimit(64 * 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(pruneRequestWait))
+	_, payload, err := conn.ReadMessage()
+	if err != nil {
+		return nil
+	}
+	var req systemtypes.PruneAllRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		h.writePruneEventInternal(conn, systemtypes.PruneProgressEvent{
+			Stage:  systemtypes.PruneProgressStageDone,
+			Status: systemtypes.PruneProgressStatusFailed,
+			Error:  "Invalid prune request: " + err.Error(),
+		})
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(prunePongWait))
+	conn.SetPongHandler(func(string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(prunePongWait))
+		return nil
+	})
+	go h.readSystemStatsPumpInternal(ctx, cancel, conn, nil)
+	go h.pingExecConnInternal(ctx, conn, prunePongWait*9/10)
+
+	result, started, err := h.systemService.PruneAllWithProgress(ctx, environmentID, req, func(event systemtypes.PruneProgressEvent) {
+		h.writePruneEventInternal(conn, event)
+	})
+	switch {
+	case err != nil:
+		h.writePruneEventInternal(conn, systemtypes.PruneProgressEvent{
+			Stage:  systemtypes.PruneProgressStageDone,
+			Status: systemtypes.PruneProgressStatusFailed,
+			Error:  err.Error(),
+		})
+	case !started:
+		h.writePruneEventInternal(conn, systemtypes.PruneProgressEvent{
+			Stage:  systemtypes.PruneProgressStageDone,
+			Status: systemtypes.PruneProgressStatusFailed,
+			Error:  "A system prune is already running for this environment",
+			Result: result,
+		})
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return nil
+}
+
+// writePruneEventInternal sends one progress event; write failures are ignored because
+// a closed socket already cancels the prune through the read pump.
+func (h *WebSocketHandler) writePruneEventInternal(conn *websocket.Conn, event systemtypes.PruneProgressEvent) {
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteJSON(event); err != nil {
+		slog.Debug("Failed to write system prune progress", "stage", event.Stage, "error", err)
+	}
+}
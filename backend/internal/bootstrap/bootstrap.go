@@ -152,17 +152,18 @@ type initializeStartupStateParams struct {
 	Config     *config.Config
 	HTTPClient *http.Client
 
-	Volume      *services.VolumeService
-	Settings    *services.SettingsService
-	Environment *services.EnvironmentService
-	GitOpsSync  *services.GitOpsSyncService
-	Project     *services.ProjectService
-	Variable    *services.VariableService
-	Docker      *services.DockerClientService
-	Swarm       *services.SwarmService
-	Role        *services.RoleService
-	User        *services.UserService
-	ApiKey      *services.ApiKeyService
+	Volume        *services.VolumeService
+	Settings      *services.SettingsService
+	Environment   *services.EnvironmentService
+	GitOpsSync    *services.GitOpsSyncService
+	Project       *services.ProjectService
+	Variable      *services.VariableService
+	Docker        *services.DockerClientService
+	Swarm         *services.SwarmService
+	Role          *services.RoleService
+	User          *services.UserService
+	ApiKey        *services.ApiKeyService
+	PruneSchedule *services.PruneScheduleService
 }
 
 func initializeStartupState(p initializeStartupStateParams) {
@@ -267,6 +268,13 @@ func initializeStartupState(p initializeStartupStateParams) {
 			return nil
 		},
 	)
+	// The legacy scheduled prune settings are no longer known keys, so import
+	// them as a prune schedule before unknown settings are pruned.
+	if p.PruneSchedule != nil {
+		if err := p.PruneSchedule.ImportLegacyScheduledPrune(appCtx); err != nil {
+			slog.WarnContext(appCtx, "Failed to import legacy scheduled prune", "error", err)
+		}
+	}
 	startup.CleanupUnknownSettings(appCtx, p.Settings)
 
 	runRoleStartupTasks(appCtx, p.Role, cfg, cfg.AgentMode)
@@ -278,6 +286,8 @@ func initializeStartupState(p initializeStartupStateParams) {
 	if cfg.AgentMode && cfg.EdgeAgent && cfg.AgentToken != "" && cfg.ManagerApiUrl != "" {
 		if err := handleAgentBootstrapPairing(appCtx, cfg, httpClient); err != nil {
 			slog.WarnContext(appCtx, "Failed to auto-pair agent with manager", "error", err)
+		} else if err := p.Settings.RecordAgentPairing(appCtx, cfg.GetManagerBaseURL(), time.Now()); err != nil {
+			slog.WarnContext(appCtx, "Failed to record agent pairing", "error", err)
 		}
 	} else if cfg.AgentMode && !cfg.EdgeAgent {
 		slog.InfoContext(appCtx, "Direct mode active: agent operates as a passive HTTP server; no outbound connection to manager required")
@@ -343,7 +343,7 @@ func TestJobSchedulerStopCancelsItsPrivateContextInternal(t *testing.T) {
 	defer cancelApp()
 
 	lifecycle := fxtest.NewLifecycle(t)
-	jobScheduler := newJobScheduler(appCtx, lifecycle, &config.Config{}, nil, nil, nil)
+	jobScheduler := newJobScheduler(appCtx, lifecycle, &config.Config{}, nil, nil, nil, nil)
 	watcher := &blockingBusWatcherInternal{
 		started: make(chan struct{}),
 		stopped: make(chan struct{}),
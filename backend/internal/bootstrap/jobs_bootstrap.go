@@ -13,13 +13,14 @@ import (
 	"go.uber.org/fx"
 )
 
-func newJobScheduler(appCtx context.Context, lc fx.Lifecycle, cfg *config.Config, imageUpdateWatcher *scheduler.ImageUpdateWatcher, analytics *scheduler.AnalyticsJob, systemUpgrade *services.SystemUpgradeService) *scheduler.JobScheduler {
+func newJobScheduler(appCtx context.Context, lc fx.Lifecycle, cfg *config.Config, imageUpdateWatcher *scheduler.ImageUpdateWatcher, containerEventWatcher *scheduler.ContainerEventWatcher, analytics *scheduler.AnalyticsJob, systemUpgrade *services.SystemUpgradeService) *scheduler.JobScheduler {
 	schedulerCtx, cancelScheduler := context.WithCancel(appCtx)
 	jobScheduler := scheduler.NewJobScheduler(schedulerCtx, cfg.GetLocation())
 	lc.Append(fx.Hook{
 		OnStart: func(context.Context) error {
 			slog.InfoContext(appCtx, "Starting scheduler")
 			jobScheduler.RegisterBusWatcher(imageUpdateWatcher, true)
+			jobScheduler.RegisterBusWatcher(containerEventWatcher, false)
 			jobScheduler.StartScheduler()
 			if analytics != nil {
 				go analytics.Run(schedulerCtx)
@@ -50,11 +51,13 @@ type registerJobsParams struct {
 	Config    *config.Config
 	Scheduler *scheduler.JobScheduler
 
-	Activity    *services.ActivityService
-	GitOpsSync  *services.GitOpsSyncService
-	Environment *services.EnvironmentService
-	JobSchedule *services.JobService
-	Settings    *services.SettingsService
+	Activity      *services.ActivityService
+	GitOpsSync    *services.GitOpsSyncService
+	PruneSchedule *services.PruneScheduleService
+	Environment   *services.EnvironmentService
+	JobSchedule   *services.JobService
+	Settings      *services.SettingsService
+	Swarm         *services.SwarmService
 
 	AutoUpdate             *scheduler.AutoUpdateJob
 	ImageUpdateWatcher     *scheduler.ImageUpdateWatcher
@@ -63,7 +66,6 @@ type registerJobsParams struct {
 	EventCleanup           *scheduler.EventCleanupJob
 	PruningVolumeHelper    *scheduler.PruningVolumeHelperJob
 	ExpiredSessionsCleanup *scheduler.ExpiredSessionsCleanupJob
-	ScheduledPrune         *scheduler.ScheduledPruneJob
 	FilesystemWatcher      *scheduler.FilesystemWatcherJob
 	VulnerabilityScan      *scheduler.VulnerabilityScanJob
 	AutoHeal               *scheduler.AutoHealJob
@@ -103,7 +105,6 @@ func registerJobs(params registerJobsParams) {
 	params.Scheduler.RegisterJob(params.EventCleanup)
 	params.Scheduler.RegisterJob(params.PruningVolumeHelper)
 	params.Scheduler.RegisterJob(params.ExpiredSessionsCleanup)
-	params.Scheduler.RegisterJob(params.ScheduledPrune)
 	// FilesystemWatcher is intentionally not scheduler-registered; it watches inline
 	// and is only rebound on settings changes below.
 	params.Scheduler.RegisterJob(params.VulnerabilityScan)
@@ -115,12 +116,13 @@ func registerJobs(params registerJobsParams) {
 	// GitOps sync and environment health are no longer single global jobs; each
 	// entity registers its own dynamic job.
 	registerDynamicJobs(dynamicJobsParams{
-		AppCtx:      params.AppCtx,
-		Config:      params.Config,
-		Scheduler:   params.Scheduler,
-		GitOpsSync:  params.GitOpsSync,
-		Environment: params.Environment,
-		JobSchedule: params.JobSchedule,
+		AppCtx:        params.AppCtx,
+		Config:        params.Config,
+		Scheduler:     params.Scheduler,
+		GitOpsSync:    params.GitOpsSync,
+		PruneSchedule: params.PruneSchedule,
+		Environment:   params.Environment,
+		JobSchedule:   params.JobSchedule,
 	})
 
 	setupSettingsCallbacks(settingsCallbacksParams{
@@ -129,22 +131,23 @@ func registerJobs(params registerJobsParams) {
 		Scheduler:          params.Scheduler,
 		Settings:           params.Settings,
 		Environment:        params.Environment,
+		Swarm:              params.Swarm,
 		AutoUpdate:         params.AutoUpdate,
 		ImageUpdateWatcher: params.ImageUpdateWatcher,
 		FilesystemWatcher:  params.FilesystemWatcher,
-		ScheduledPrune:     params.ScheduledPrune,
 		VulnerabilityScan:  params.VulnerabilityScan,
 		AutoHeal:           params.AutoHeal,
 	})
 }
 
 type dynamicJobsParams struct {
-	AppCtx      context.Context
-	Config      *config.Config
-	Scheduler   *scheduler.JobScheduler
-	GitOpsSync  *services.GitOpsSyncService
-	Environment *services.EnvironmentService
-	JobSchedule *services.JobService
+	AppCtx        context.Context
+	Config        *config.Config
+	Scheduler     *scheduler.JobScheduler
+	GitOpsSync    *services.GitOpsSyncService
+	PruneSchedule *services.PruneScheduleService
+	Environment   *services.EnvironmentService
+	JobSchedule   *services.JobService
 }
 
 // registerDynamicJobs injects the scheduler into the services that own per-entity
@@ -157,6 +160,12 @@ func registerDynamicJobs(params dynamicJobsParams) {
 		params.GitOpsSync.RegisterAutoSyncJobsOnStartup(params.AppCtx)
 	}
 
+	// Prune schedules: one job per enabled schedule (runs on manager and agents).
+	if params.PruneSchedule != nil {
+		params.PruneSchedule.SetScheduler(params.AppCtx, params.Scheduler)
+		params.PruneSchedule.RegisterSchedulesOnStartup(params.AppCtx)
+	}
+
 	// Environment health: one job per enabled environment (manager only). The Jobs
 	// UI still addresses "environment-health" by ID, so bridge its reschedule and
 	// run-now back to EnvironmentService.
@@ -178,11 +187,11 @@ type settingsCallbacksParams struct {
 	Scheduler    *scheduler.JobScheduler
 	Settings     *services.SettingsService
 	Environment  *services.EnvironmentService
+	Swarm        *services.SwarmService
 
 	AutoUpdate         *scheduler.AutoUpdateJob
 	ImageUpdateWatcher *scheduler.ImageUpdateWatcher
 	FilesystemWatcher  *scheduler.FilesystemWatcherJob
-	ScheduledPrune     *scheduler.ScheduledPruneJob
 	VulnerabilityScan  *scheduler.VulnerabilityScanJob
 	AutoHeal           *scheduler.AutoHealJob
 }
@@ -218,9 +227,17 @@ func setupSettingsCallbacks(params settingsCallbacksParams) {
 			}
 		}
 	}
-	params.Settings.OnScheduledPruneSettingsChanged = func(_ context.Context) {
-		if err := params.Scheduler.RescheduleJob(params.LifecycleCtx, params.ScheduledPrune); err != nil {
-			slog.WarnContext(params.LifecycleCtx, "Failed to reschedule scheduled-prune job", "error", err)
+	params.Settings.OnSwarmStackSourcesDirectoryChanged = func(_ context.Context, oldDir, newDir string) {
+		if params.Swarm == nil {
+			return
+		}
+		moved, err := params.Swarm.MigrateStackSources(params.LifecycleCtx, oldDir, newDir)
+		if err != nil {
+			slog.WarnContext(params.LifecycleCtx, "Failed to migrate swarm stack sources", "from", oldDir, "to", newDir, "moved", moved, "error", err)
+			return
+		}
+		if moved > 0 {
+			slog.InfoContext(params.LifecycleCtx, "Migrated swarm stack sources", "from", oldDir, "to", newDir, "moved", moved)
 		}
 	}
 	params.Settings.OnVulnerabilityScanSettingsChanged = func(_ context.Context) {
@@ -40,6 +40,7 @@ var loggerSkipPatterns = []string{
 	"GET /api/environments/*/ws/containers/*/terminal",
 	"GET /api/environments/*/ws/projects/*/logs",
 	"GET /api/environments/*/ws/system/stats",
+	"GET /api/environments/*/ws/system/events",
 	"GET /_app/*",
 	"GET /img",
 	"GET /api/health",
@@ -228,6 +229,7 @@ func newRouter(p RouterParams) (*echo.Echo, *edge.TunnelServer) {
 		envResolver,
 		createAuthValidatorInternal(deps),
 		permissionMatcher,
+		deps.Environment,
 	)
 	apiGroup.Use(envProxyMiddleware)
 
@@ -245,7 +247,7 @@ func newRouter(p RouterParams) (*echo.Echo, *edge.TunnelServer) {
 	}
 
 	// Remaining echo handlers (WebSocket/streaming)
-	ws.NewWebSocketHandler(apiGroup, deps.Project, deps.Container, deps.Swarm, deps.System, deps.Diagnostics, authMiddleware, cfg)
+	ws.NewWebSocketHandler(apiGroup, deps.Project, deps.Container, deps.Image, deps.Swarm, deps.System, deps.Settings, deps.Diagnostics, deps.Activity, authMiddleware, cfg)
 
 	// Register edge tunnel endpoint for manager to accept agent connections
 	// This is only registered when NOT in agent mode (i.e., running as manager)
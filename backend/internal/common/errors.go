@@ -60,6 +60,11 @@ var (
 	ErrUpgradeInProgress                       = Classify(ErrConflict, errors.Sentinel("an upgrade is already in progress"))
 	ErrUpdateAllInProgress                     = Classify(ErrConflict, errors.Sentinel("an update-all job is already in progress"))
 	ErrTemplateNotFound                        = Classify(ErrNotFound, errors.Sentinel("Template not found"))
+	ErrTemplateRegistryAuthInvalid             = Classify(ErrValidation, errors.Sentinel("Invalid template registry authentication"))
+	ErrTemplateVariablesMissing                = Classify(ErrValidation, errors.Sentinel("Template variables are missing"))
+	ErrTemplateStackExists                     = Classify(ErrConflict, errors.Sentinel("A stack with this name already exists"))
+	ErrTemplateNoSource                        = Classify(ErrValidation, errors.Sentinel("Template has no upstream source"))
+	ErrTemplatePathInvalid                     = Classify(ErrValidation, errors.Sentinel("Template directory is outside the templates directory"))
 	ErrInvalidEnvKey                           = Classify(ErrValidation, errors.Sentinel("Invalid environment key"))
 	ErrGlobalVariableNotFound                  = Classify(ErrNotFound, errors.Sentinel("Global variable not found"))
 	ErrGlobalVariableConflict                  = Classify(ErrConflict, errors.Sentinel("Global variable already exists"))
@@ -69,6 +74,7 @@ var (
 	ErrSwarmManagerRequired                    = Classify(ErrForbidden, errors.Sentinel("Swarm manager access required"))
 	ErrSwarmConfigImmutable                    = Classify(ErrConflict, errors.Sentinel("Swarm configs are immutable; create a new config and update services to use it"))
 	ErrSwarmSecretImmutable                    = Classify(ErrConflict, errors.Sentinel("Swarm secrets are immutable; create a new secret and update services to use it"))
+	ErrSwarmRemoteEnvironment                  = Classify(ErrBadRequest, errors.Sentinel("Swarm requests for remote environments must be sent through the environment proxy"))
 	ErrRoleNotFound                            = Classify(ErrNotFound, errors.Sentinel("Role not found"))
 	ErrRoleBuiltIn                             = Classify(ErrForbidden, errors.Sentinel("Built-in role cannot be modified"))
 	ErrRoleNameTaken                           = Classify(ErrConflict, errors.Sentinel("Role name already in use"))
@@ -92,8 +98,20 @@ var (
 	ErrProjectComposeFileNotFound              = Classify(ErrNotFound, errors.Sentinel("Project compose file not found"))
 	ErrComposeFileNotFound                     = Classify(ErrNotFound, errors.Sentinel("no compose file found"))
 	ErrEnvironmentInvalidProxyTarget           = Classify(ErrBadRequest, errors.Sentinel("Invalid proxy target URL"))
+	ErrEnvironmentAuthFailed                   = Classify(ErrUnauthorized, errors.Sentinel("Environment agent rejected the access token"))
+	ErrEnvironmentBootstrapTokenMissing        = Classify(ErrValidation, errors.Sentinel("No bootstrap token is stored for this environment"))
+	ErrEnvironmentRepairUnsupported            = Classify(ErrValidation, errors.Sentinel("Agent repair is only available for direct remote environments"))
+	ErrEnvironmentOrderInvalid                 = Classify(ErrValidation, errors.Sentinel("Invalid environment order"))
+	ErrEnvironmentCircuitOpen                  = Classify(ErrUnavailable, errors.Sentinel("Environment is temporarily unavailable after repeated failures"))
+	ErrEnvironmentMaintenance                  = Classify(ErrConflict, errors.Sentinel("Environment is in maintenance mode"))
 	ErrUnsafeRemoteURL                         = Classify(ErrBadRequest, errors.Sentinel("Remote URL is not allowed"))
+	ErrNotificationMessageTemplateInvalid      = Classify(ErrValidation, errors.Sentinel("Invalid notification message template"))
 	ErrImageScanInProgress                     = Classify(ErrConflict, errors.Sentinel("an image update check is already in progress"))
+	ErrContainerOperationInProgress            = Classify(ErrConflict, errors.Sentinel("another operation is already in progress for this container"))
+	ErrContainerHostPortInUse                  = Classify(ErrConflict, errors.Sentinel("Host port is already in use"))
+	ErrContainerNotRunning                     = Classify(ErrConflict, errors.Sentinel("Container is not running"))
+	ErrDockerUnavailable                       = Classify(ErrUnavailable, errors.Sentinel("Docker daemon is unavailable"))
+	ErrPruneScheduleNotFound                   = Classify(ErrNotFound, errors.Sentinel("Prune schedule not found"))
 	ErrRedeployAfterSyncFailed                 = errors.Sentinel("redeploy failed")
 	ErrGitOpsSyncProjectBindingBroken          = errors.Sentinel("GitOps sync project binding broken")
 )
@@ -70,6 +70,7 @@ type Config struct {
 	TemplatesDirectory      string `env:"TEMPLATES_DIRECTORY" default:"/app/data/templates"`
 	ProjectScanMaxDepth     int    `env:"PROJECT_SCAN_MAX_DEPTH" default:"3"`
 	ProjectFileTreeMaxDepth int    `env:"PROJECT_FILE_TREE_MAX_DEPTH" default:"20"`
+	ContainerFileMaxBytes   int    `env:"CONTAINER_FILE_MAX_BYTES" default:"1048576"`
 	ProjectScanSkipDirs     string `env:"PROJECT_SCAN_SKIP_DIRS" default:".git,node_modules,vendor,.venv,venv,__pycache__,.cache,dist,build,target,.next,.nuxt,.svelte-kit"`
 	LogJson                 bool   `env:"LOG_JSON" default:"false"`
 	LogLevel                string `env:"LOG_LEVEL" default:"info" options:"toLower"`
@@ -106,6 +107,14 @@ type Config struct {
 	ProxyRequestTimeout    int    `env:"PROXY_REQUEST_TIMEOUT" default:"0"`
 	BackupVolumeName       string `env:"ARCANE_BACKUP_VOLUME_NAME" default:"arcane-backups"`
 
+	// Maximum parallel container inspects when the container list is asked for
+	// per-row details such as GPUs or health.
+	ContainerListInspectConcurrency int `env:"CONTAINER_LIST_INSPECT_CONCURRENCY" default:"5"`
+
+	// Seconds that swarm node and network lists are shared between list
+	// requests before Docker is asked again. 0 disables the cache.
+	SwarmMetadataCacheTTL int `env:"SWARM_METADATA_CACHE_TTL" default:"2"`
+
 	// Timezone for cron job scheduling. Uses IANA timezone names (e.g., "America/New_York", "Europe/London").
 	// "Local" uses the system's local timezone, "UTC" for Coordinated Universal Time.
 	Timezone string `env:"TZ" default:"Local"`
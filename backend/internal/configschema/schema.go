@@ -96,35 +96,17 @@ var overrideDocRules = map[string]overrideDocRule{
 	"autoUpdateInterval": {
 		requires: "AUTO_UPDATE=true to have effect at runtime.",
 	},
-	"scheduledPruneInterval": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true to have effect at runtime.",
-	},
-	"pruneContainerMode": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true to have effect at runtime.",
-	},
 	"pruneContainerUntil": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true and PRUNE_CONTAINER_MODE=olderThan to have effect at runtime.",
-	},
-	"pruneImageMode": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true to have effect at runtime.",
+		requires: "PRUNE_CONTAINER_MODE=olderThan to have effect at runtime.",
 	},
 	"pruneImageUntil": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true and PRUNE_IMAGE_MODE=olderThan to have effect at runtime.",
-	},
-	"pruneVolumeMode": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true to have effect at runtime.",
-	},
-	"pruneNetworkMode": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true to have effect at runtime.",
+		requires: "PRUNE_IMAGE_MODE=olderThan to have effect at runtime.",
 	},
 	"pruneNetworkUntil": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true and PRUNE_NETWORK_MODE=olderThan to have effect at runtime.",
-	},
-	"pruneBuildCacheMode": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true to have effect at runtime.",
+		requires: "PRUNE_NETWORK_MODE=olderThan to have effect at runtime.",
 	},
 	"pruneBuildCacheUntil": {
-		requires: "SCHEDULED_PRUNE_ENABLED=true and PRUNE_BUILD_CACHE_MODE=olderThan to have effect at runtime.",
+		requires: "PRUNE_BUILD_CACHE_MODE=olderThan to have effect at runtime.",
 	},
 	"vulnerabilityScanInterval": {
 		requires: "VULNERABILITY_SCAN_ENABLED=true to have effect at runtime.",
@@ -201,6 +201,8 @@ var expectedEnvConfigVars = []string{
 	"ARCANE_BACKUP_VOLUME_NAME",
 	"AUTO_LOGIN_PASSWORD",
 	"AUTO_LOGIN_USERNAME",
+	"CONTAINER_FILE_MAX_BYTES",
+	"CONTAINER_LIST_INSPECT_CONCURRENCY",
 	"DATABASE_URL",
 	"DIR_PERM",
 	"DOCKER_API_TIMEOUT",
@@ -251,6 +253,7 @@ var expectedEnvConfigVars = []string{
 	"PROXY_REQUEST_TIMEOUT",
 	"PUID",
 	"REGISTRY_TIMEOUT",
+	"SWARM_METADATA_CACHE_TTL",
 	"TEMPLATES_DIRECTORY",
 	"TLS_CERT_FILE",
 	"TLS_ENABLED",
@@ -282,6 +285,9 @@ var expectedSettingOverrideKeys = []string{
 	"buildProvider",
 	"buildTimeout",
 	"buildsDirectory",
+	"defaultContainerCpuLimit",
+	"defaultContainerMemoryLimitMb",
+	"defaultContainerRestartPolicy",
 	"defaultDeployPullPolicy",
 	"defaultShell",
 	"depotProjectId",
@@ -294,6 +300,7 @@ var expectedSettingOverrideKeys = []string{
 	"enableGravatar",
 	"environmentHealthInterval",
 	"eventCleanupInterval",
+	"eventRetentionHours",
 	"expiredSessionsCleanupInterval",
 	"followProjectSymlinks",
 	"gitOperationTimeout",
@@ -301,11 +308,13 @@ var expectedSettingOverrideKeys = []string{
 	"gitSyncMaxFiles",
 	"gitSyncMaxTotalSizeMb",
 	"httpClientTimeout",
+	"imageUpdateNotificationCooldown",
 	"lifecycleDefaultRunnerImage",
 	"lifecycleEnabled",
 	"lifecycleMaxTimeoutSec",
 	"maxConcurrentActivities",
 	"maxImageUploadSize",
+	"notificationLogRetentionDays",
 	"oidcAuthorizationEndpoint",
 	"oidcAutoRedirectToProvider",
 	"oidcClientId",
@@ -336,10 +345,15 @@ var expectedSettingOverrideKeys = []string{
 	"pruneNetworkMode",
 	"pruneNetworkUntil",
 	"pruneVolumeMode",
+	"registryMirrors",
 	"registryTimeout",
-	"scheduledPruneEnabled",
-	"scheduledPruneInterval",
+	"swarmStackSourceHistoryLimit",
 	"swarmStackSourcesDirectory",
+	"systemGpuVendor",
+	"systemIntelGpuTopPath",
+	"systemNvidiaSmiExtraFields",
+	"systemNvidiaSmiPath",
+	"systemTegrastatsPath",
 	"templatesDirectory",
 	"trivyConcurrentScanContainers",
 	"trivyConfig",
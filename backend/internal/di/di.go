@@ -38,12 +38,12 @@ var ServiceOptions = fx.Options(
 		provideProjectServiceInternal,
 		services.NewContainerService,
 		services.NewDashboardService,
-		services.NewNetworkService,
 		services.NewPortService,
 		services.NewSwarmService,
 		services.NewTemplateService,
 		services.NewOidcService,
 		services.NewSystemService,
+		services.NewPruneScheduleService,
 		services.NewSystemUpgradeService,
 		services.NewDiagnosticsService,
 		services.NewGitOpsSyncService,
@@ -54,6 +54,7 @@ var ServiceOptions = fx.Options(
 		provideVersionServiceInternal,
 		provideGitRepositoryServiceInternal,
 		provideVolumeServiceInternal,
+		provideNetworkServiceInternal,
 		provideAuthServiceInternal,
 		provideContainerRegistryServiceInternal,
 		provideUpdaterServiceInternal,
@@ -70,12 +71,12 @@ var JobOptions = fx.Options(
 	fx.Provide(
 		scheduler.NewAutoUpdateJob,
 		scheduler.NewImageUpdateWatcher,
+		scheduler.NewContainerEventWatcher,
 		scheduler.NewDockerClientRefreshJob,
 		provideAnalyticsJobInternal,
 		scheduler.NewEventCleanupJob,
 		scheduler.NewPruningVolumeHelperJob,
 		scheduler.NewExpiredSessionsCleanupJob,
-		scheduler.NewScheduledPruneJob,
 		provideFilesystemWatcherJobInternal,
 		scheduler.NewVulnerabilityScanJob,
 		scheduler.NewAutoHealJob,
@@ -43,6 +43,7 @@ type graphParams struct {
 	Template          *services.TemplateService
 	ContainerRegistry *services.ContainerRegistryService
 	System            *services.SystemService
+	PruneSchedule     *services.PruneScheduleService
 	SystemUpgrade     *services.SystemUpgradeService
 	Diagnostics       *services.DiagnosticsService
 	Updater           *services.UpdaterService
@@ -63,12 +64,12 @@ type graphParams struct {
 
 	AutoUpdate             *scheduler.AutoUpdateJob
 	ImageUpdateWatcher     *scheduler.ImageUpdateWatcher
+	ContainerEventWatcher  *scheduler.ContainerEventWatcher
 	DockerClientRefresh    *scheduler.DockerClientRefreshJob
 	Analytics              *scheduler.AnalyticsJob
 	EventCleanup           *scheduler.EventCleanupJob
 	PruningVolumeHelper    *scheduler.PruningVolumeHelperJob
 	ExpiredSessionsCleanup *scheduler.ExpiredSessionsCleanupJob
-	ScheduledPrune         *scheduler.ScheduledPruneJob
 	FilesystemWatcher      *scheduler.FilesystemWatcherJob
 	VulnerabilityScan      *scheduler.VulnerabilityScanJob
 	AutoHeal               *scheduler.AutoHealJob
@@ -64,6 +64,10 @@ func provideContainerRegistryServiceInternal(db *database.DB, docker *services.D
 	}, kv)
 }
 
+func provideNetworkServiceInternal(db *database.DB, docker *services.DockerClientService, event *services.EventService, swarm *services.SwarmService) *services.NetworkService {
+	return services.NewNetworkService(db, docker, event).WithSwarmService(swarm)
+}
+
 func provideProjectServiceInternal(db *database.DB, settings *services.SettingsService, event *services.EventService, image *services.ImageService, docker *services.DockerClientService, build *services.BuildService, lifecycle *services.LifecycleService, kv *services.KVService, registry *services.ContainerRegistryService, environment *services.EnvironmentService, cfg *config.Config) *services.ProjectService {
 	return services.NewProjectService(db, settings, event, image, docker, build, lifecycle, registry, cfg).
 		WithKVService(kv).
@@ -53,6 +53,7 @@ func (m *CORSMiddleware) Add() echo.MiddlewareFunc {
 			"X-Total-Count",
 			"X-Page",
 			"X-Per-Page",
+			"X-Truncated",
 		},
 		MaxAge: 300,
 	})
@@ -14,9 +14,11 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/edge"
 	wsutil "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/ws"
+	pkgutils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	httputils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils/httpx"
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v5"
@@ -29,6 +31,9 @@ const (
 	// proxyTimeout is intentionally generous because some proxied operations
 	// (e.g., image pulls with progress streaming) can take multiple minutes.
 	proxyTimeout = 30 * time.Minute
+
+	// revealQueryParam asks for masked container environment values unmasked.
+	revealQueryParam = "reveal"
 )
 
 // managementEndpointSet contains paths handled locally and never proxied to remote environments.
@@ -39,6 +44,7 @@ var managementEndpointSet = map[string]struct{}{
 	"/sync":            {},
 	"/deployment":      {},
 	"/agent/pair":      {},
+	"/agent/repair":    {},
 	"/version":         {},
 	"/settings":        {},
 	"/job-schedules":   {},
@@ -56,6 +62,14 @@ type EnvResolver func(ctx context.Context, id string) (string, *string, bool, er
 // agent proxies) bypass authorization.
 type AuthValidator func(ctx context.Context, c *echo.Context) (*authz.PermissionSet, bool)
 
+// ProxyCircuitBreaker is the per-environment circuit breaker proxied HTTP
+// requests go through. Every request AllowEnvironmentRequest lets through is
+// reported back with RecordEnvironmentRequestOutcome.
+type ProxyCircuitBreaker interface {
+	AllowEnvironmentRequest(envID string) error
+	RecordEnvironmentRequestOutcome(envID, path string, statusCode int, err error)
+}
+
 // EnvironmentMiddleware proxies requests for remote environments to their respective agents.
 type EnvironmentMiddleware struct {
 	localID       string
@@ -65,11 +79,12 @@ type EnvironmentMiddleware struct {
 	httpClient    *http.Client
 	registry      *edge.TunnelRegistry
 	matcher       *authz.PermissionMatcher
+	breaker       ProxyCircuitBreaker
 }
 
 // NewEnvProxyMiddlewareWithParam creates middleware that proxies requests to remote environments.
-func NewEnvProxyMiddlewareWithParam(localID, paramName string, resolver EnvResolver, authValidator AuthValidator, matcher *authz.PermissionMatcher) echo.MiddlewareFunc {
-	return NewEnvProxyMiddlewareWithParamAndRegistry(localID, paramName, resolver, authValidator, matcher, edge.GetRegistry())
+func NewEnvProxyMiddlewareWithParam(localID, paramName string, resolver EnvResolver, authValidator AuthValidator, matcher *authz.PermissionMatcher, breaker ProxyCircuitBreaker) echo.MiddlewareFunc {
+	return NewEnvProxyMiddlewareWithParamAndRegistry(localID, paramName, resolver, authValidator, matcher, breaker, edge.GetRegistry())
 }
 
 // NewEnvProxyMiddlewareWithParamAndRegistry creates middleware with an injected tunnel registry.
@@ -79,6 +94,7 @@ func NewEnvProxyMiddlewareWithParamAndRegistry(
 	resolver EnvResolver,
 	authValidator AuthValidator,
 	matcher *authz.PermissionMatcher,
+	breaker ProxyCircuitBreaker,
 	registry *edge.TunnelRegistry,
 ) echo.MiddlewareFunc {
 	if registry == nil {
@@ -93,6 +109,7 @@ func NewEnvProxyMiddlewareWithParamAndRegistry(
 		httpClient:    &http.Client{Timeout: proxyTimeout},
 		registry:      registry,
 		matcher:       matcher,
+		breaker:       breaker,
 	}
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
@@ -153,6 +170,13 @@ func (m *EnvironmentMiddleware) Handle(c *echo.Context, next echo.HandlerFunc) e
 		})
 	}
 
+	// Agents run every request with the manager's sudo permission set, so only a
+	// global admin's maintenance override or secret reveal may be forwarded to them.
+	if !perms.IsGlobalAdmin() {
+		c.Request().Header.Del(pkgutils.HeaderMaintenanceOverride)
+		stripQueryParamInternal(c.Request(), revealQueryParam)
+	}
+
 	isEdgeEnvironment := isEdgeEnvironmentURLInternal(apiURL)
 
 	if handled, err := m.proxyActiveEdgeTunnelInternal(c, envID, accessToken); handled {
@@ -173,7 +197,17 @@ func (m *EnvironmentMiddleware) Handle(c *echo.Context, next echo.HandlerFunc) e
 	if m.isWebSocketUpgrade(c) {
 		return m.proxyWebSocket(c, target, accessToken, envID)
 	}
-	return m.proxyHTTP(c, target, accessToken)
+	return m.proxyHTTP(c, target, accessToken, envID)
+}
+
+// stripQueryParamInternal removes key from the request's query string.
+func stripQueryParamInternal(req *http.Request, key string) {
+	query := req.URL.Query()
+	if !query.Has(key) {
+		return
+	}
+	query.Del(key)
+	req.URL.RawQuery = query.Encode()
 }
 
 // proxyPermissionDenied reports whether the caller lacks permission to perform
@@ -255,7 +289,24 @@ func (m *EnvironmentMiddleware) proxyThroughTunnelInternal(c *echo.Context, tunn
 	if m.isWebSocketUpgrade(c) {
 		return edge.ProxyWebSocketRequest(c, tunnel, proxyPath)
 	}
-	return edge.ProxyHTTPRequest(c, tunnel, proxyPath)
+	if m.breaker == nil {
+		return edge.ProxyHTTPRequest(c, tunnel, proxyPath)
+	}
+
+	if err := m.breaker.AllowEnvironmentRequest(envID); err != nil {
+		return m.abortCircuitOpenInternal(c, err)
+	}
+	recorded := false
+	err := edge.ProxyHTTPRequestWithOutcome(c, tunnel, proxyPath, func(statusCode int, proxyErr error) {
+		recorded = true
+		m.breaker.RecordEnvironmentRequestOutcome(envID, proxyPath, statusCode, proxyErr)
+	})
+	if !recorded {
+		// The request never reached the agent, so it says nothing about it; a
+		// canceled outcome only frees the half-open trial slot.
+		m.breaker.RecordEnvironmentRequestOutcome(envID, proxyPath, 0, context.Canceled)
+	}
+	return err
 }
 
 // hasResourcePath reports whether the request targets a proxiable resource path.
@@ -412,6 +463,15 @@ func (m *EnvironmentMiddleware) waitForActiveEdgeTunnelInternal(ctx context.Cont
 	}
 }
 
+// abortCircuitOpenInternal fast-fails a proxied request while the environment's
+// circuit breaker is open.
+func (m *EnvironmentMiddleware) abortCircuitOpenInternal(c *echo.Context, err error) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]any{
+		"success": false,
+		"data":    map[string]any{"error": err.Error()},
+	})
+}
+
 func (m *EnvironmentMiddleware) abortEdgeTunnelUnavailable(c *echo.Context) error {
 	return c.JSON(http.StatusBadGateway, map[string]any{
 		"success": false,
@@ -438,7 +498,7 @@ func (m *EnvironmentMiddleware) proxyWebSocket(c *echo.Context, target string, a
 }
 
 // proxyHTTP handles standard HTTP proxy requests.
-func (m *EnvironmentMiddleware) proxyHTTP(c *echo.Context, target string, accessToken *string) error {
+func (m *EnvironmentMiddleware) proxyHTTP(c *echo.Context, target string, accessToken *string, envID string) error {
 	if isEdgeEnvironmentURLInternal(target) {
 		slog.WarnContext(c.Request().Context(), "Refusing direct HTTP proxy to edge environment without active tunnel", "target", target)
 		return m.abortEdgeTunnelUnavailable(c)
@@ -456,7 +516,13 @@ func (m *EnvironmentMiddleware) proxyHTTP(c *echo.Context, target string, access
 		})
 	}
 
-	resp, err := m.httpClient.Do(req)
+	if m.breaker != nil {
+		if err := m.breaker.AllowEnvironmentRequest(envID); err != nil {
+			return m.abortCircuitOpenInternal(c, err)
+		}
+	}
+
+	resp, err := m.doProxyRequestInternal(req, envID)
 	if err != nil {
 		return c.JSON(http.StatusBadGateway, map[string]any{
 			"success": false,
@@ -469,6 +535,40 @@ func (m *EnvironmentMiddleware) proxyHTTP(c *echo.Context, target string, access
 	return nil
 }
 
+// doProxyRequestInternal sends req to the agent and records the outcome on the
+// environment's circuit breaker. Idempotent methods retry on any transient
+// failure; others only when the connection was never established, since the
+// agent may already have acted on a request that failed mid-flight.
+func (m *EnvironmentMiddleware) doProxyRequestInternal(req *http.Request, envID string) (*http.Response, error) {
+	var resp *http.Response
+	attempt := func() (int, error) {
+		if resp != nil {
+			_ = resp.Body.Close()
+			resp = nil
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return 0, err
+			}
+			req.Body = body
+		}
+
+		var err error
+		resp, err = m.httpClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		return resp.StatusCode, nil
+	}
+
+	statusCode, err := services.RetryEnvironmentRequest(req.Context(), req.Method, attempt)
+	if m.breaker != nil {
+		m.breaker.RecordEnvironmentRequestOutcome(envID, req.URL.Path, statusCode, err)
+	}
+	return resp, err
+}
+
 // createProxyRequest builds the HTTP request to forward to the remote environment.
 func (m *EnvironmentMiddleware) createProxyRequest(c *echo.Context, target string, accessToken *string) (*http.Request, error) {
 	srcReq := c.Request()
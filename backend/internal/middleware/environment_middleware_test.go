@@ -4,8 +4,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"emperror.dev/errors"
+
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/authz"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/edge"
 	"github.com/labstack/echo/v5"
@@ -289,12 +292,62 @@ func TestEnvironmentMiddleware_ProxyHTTPRejectsEdgeTargetsWithoutTunnel(t *testi
 	req := httptest.NewRequest(http.MethodGet, "/api/environments/env-edge/containers", nil)
 	c := e.NewContext(req, recorder)
 
-	_ = middleware.proxyHTTP(c, "edge://oracle-1/api/environments/0/containers", nil)
+	_ = middleware.proxyHTTP(c, "edge://oracle-1/api/environments/0/containers", nil, "env-edge")
 
 	assert.Equal(t, http.StatusBadGateway, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "Edge agent is not connected")
 }
 
+type testProxyCircuitBreaker struct {
+	allowErr error
+	outcomes []int
+}
+
+func (b *testProxyCircuitBreaker) AllowEnvironmentRequest(string) error { return b.allowErr }
+
+func (b *testProxyCircuitBreaker) RecordEnvironmentRequestOutcome(_, _ string, statusCode int, _ error) {
+	b.outcomes = append(b.outcomes, statusCode)
+}
+
+func TestEnvironmentMiddleware_ProxyHTTPUsesCircuitBreaker(t *testing.T) {
+	var attempts atomic.Int32
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer agent.Close()
+
+	breaker := &testProxyCircuitBreaker{}
+	middleware := newTestEnvironmentMiddleware()
+	middleware.breaker = breaker
+	e := echo.New()
+
+	proxy := func(method string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(method, "/api/environments/env-1/containers", nil), recorder)
+		_ = middleware.proxyHTTP(c, agent.URL+"/api/environments/0/containers", nil, "env-1")
+		return recorder
+	}
+
+	// Idempotent requests retry; the final outcome is recorded once.
+	assert.Equal(t, http.StatusServiceUnavailable, proxy(http.MethodGet).Code)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, []int{http.StatusServiceUnavailable}, breaker.outcomes)
+
+	// Other methods are sent once.
+	attempts.Store(0)
+	assert.Equal(t, http.StatusServiceUnavailable, proxy(http.MethodPost).Code)
+	assert.Equal(t, int32(1), attempts.Load())
+
+	// An open breaker fails fast without reaching the agent.
+	attempts.Store(0)
+	breaker.allowErr = errors.New("environment env-1 is unavailable")
+	recorder := proxy(http.MethodGet)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "environment env-1 is unavailable")
+	assert.Zero(t, attempts.Load())
+}
+
 func TestIsWebSocketUpgrade(t *testing.T) {
 	middleware := newTestEnvironmentMiddleware()
 
@@ -397,3 +450,13 @@ func TestIsCentralSwarmManagementPathInternal_IsMethodAware(t *testing.T) {
 	assert.True(t, isCentralSwarmManagementPathInternal(http.MethodPut, "/swarm/nodes/node-1/agent/binding"))
 	assert.True(t, isCentralSwarmManagementPathInternal(http.MethodDelete, "/swarm/nodes/node-1/agent/binding"))
 }
+
+func TestStripQueryParamInternal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/environments/env-1/containers/abc/env?reveal=true&foo=bar", nil)
+	stripQueryParamInternal(req, revealQueryParam)
+	assert.Equal(t, "foo=bar", req.URL.RawQuery)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/environments/env-1/containers/abc/env?b=2&a=1", nil)
+	stripQueryParamInternal(req, revealQueryParam)
+	assert.Equal(t, "b=2&a=1", req.URL.RawQuery)
+}
@@ -11,9 +11,12 @@ type Environment struct {
 	Enabled             bool       `json:"enabled" sortable:"true"`
 	IsEdge              bool       `json:"isEdge" gorm:"column:is_edge;default:false"`
 	Hidden              bool       `json:"hidden" gorm:"column:hidden;default:false"`
+	Pinned              bool       `json:"pinned" gorm:"column:pinned;default:false" sortable:"true"`
+	DisplayOrder        int        `json:"displayOrder" gorm:"column:display_order;default:0" sortable:"true"`
 	LastSeen            *time.Time `json:"lastSeen" gorm:"column:last_seen"`
 	LastEdgeTransport   *string    `json:"lastEdgeTransport" gorm:"column:last_edge_transport"`
 	AccessToken         *string    `json:"-" gorm:"column:access_token"`
+	BootstrapToken      *string    `json:"-" gorm:"column:bootstrap_token"`
 	ApiKeyID            *string    `json:"-" gorm:"column:api_key_id"`
 	ParentEnvironmentID *string    `json:"-" gorm:"column:parent_environment_id"`
 	SwarmNodeID         *string    `json:"-" gorm:"column:swarm_node_id"`
@@ -29,4 +32,7 @@ const (
 	EnvironmentStatusOffline EnvironmentStatus = "offline"
 	EnvironmentStatusError   EnvironmentStatus = "error"
 	EnvironmentStatusPending EnvironmentStatus = "pending"
+	// EnvironmentStatusAuthFailed marks an agent that is reachable but rejects
+	// the manager's access token.
+	EnvironmentStatusAuthFailed EnvironmentStatus = "auth_failed"
 )
@@ -23,6 +23,8 @@ const (
 	EventTypeContainerPause   EventType = "container.pause"
 	EventTypeContainerUnpause EventType = "container.unpause"
 	EventTypeContainerError   EventType = "container.error"
+	EventTypeContainerBatch   EventType = "container.batch"
+	EventTypeContainerExec    EventType = "container.exec"
 
 	EventTypeImagePull              EventType = "image.pull"
 	EventTypeImageLoad              EventType = "image.load"
@@ -17,6 +17,7 @@ const (
 	NotificationProviderGotify   NotificationProvider = "gotify"
 	NotificationProviderMatrix   NotificationProvider = "matrix"
 	NotificationProviderGeneric  NotificationProvider = "generic"
+	NotificationProviderWebhook  NotificationProvider = "webhook"
 )
 
 var validNotificationProviders = map[NotificationProvider]struct{}{
@@ -30,6 +31,7 @@ var validNotificationProviders = map[NotificationProvider]struct{}{
 	NotificationProviderGotify:   {},
 	NotificationProviderMatrix:   {},
 	NotificationProviderGeneric:  {},
+	NotificationProviderWebhook:  {},
 }
 
 func IsValidNotificationProvider(provider NotificationProvider) bool {
@@ -45,8 +47,29 @@ const (
 	NotificationEventVulnerabilityFound NotificationEventType = "vulnerability_found"
 	NotificationEventPruneReport        NotificationEventType = "prune_report"
 	NotificationEventAutoHeal           NotificationEventType = "auto_heal"
+
+	NotificationEventContainerDie         NotificationEventType = "container_die"
+	NotificationEventContainerOOM         NotificationEventType = "container_oom"
+	NotificationEventContainerUnhealthy   NotificationEventType = "container_unhealthy"
+	NotificationEventContainerRestartLoop NotificationEventType = "container_restart_loop"
 )
 
+// containerLifecycleNotificationEvents are reported from the Docker event
+// stream rather than from an Arcane action.
+var containerLifecycleNotificationEvents = map[NotificationEventType]struct{}{
+	NotificationEventContainerDie:         {},
+	NotificationEventContainerOOM:         {},
+	NotificationEventContainerUnhealthy:   {},
+	NotificationEventContainerRestartLoop: {},
+}
+
+// IsContainerLifecycleNotificationEvent reports whether eventType is one of the
+// container lifecycle events. These are opt-in per provider.
+func IsContainerLifecycleNotificationEvent(eventType NotificationEventType) bool {
+	_, ok := containerLifecycleNotificationEvents[eventType]
+	return ok
+}
+
 type EmailTLSMode string
 
 const (
@@ -195,3 +218,14 @@ type GenericConfig struct {
 	// checked (existing behaviour).
 	SuccessBodyContains string `json:"successBodyContains,omitempty"`
 }
+
+// WebhookConfig configures the event webhook provider, which POSTs a
+// structured JSON event instead of a rendered message.
+type WebhookConfig struct {
+	URL           string                         `json:"url"`
+	CustomHeaders map[string]string              `json:"customHeaders,omitempty"`
+	Events        map[NotificationEventType]bool `json:"events,omitempty"`
+	// Secret, when set, signs each payload with HMAC-SHA256. The hex digest
+	// is sent in the X-Arcane-Signature header as "sha256=<digest>".
+	Secret string `json:"secret,omitempty"`
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"database/sql/driver"
+	json "encoding/json/v2"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/getarcaneapp/arcane/types/v2/system"
+)
+
+// PrunePolicy is a prune request stored as JSON in a single TEXT column.
+//
+//nolint:recvcheck
+type PrunePolicy system.PruneAllRequest
+
+func (p PrunePolicy) Value() (driver.Value, error) {
+	return json.Marshal(system.PruneAllRequest(p))
+}
+
+func (p *PrunePolicy) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*p = PrunePolicy{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.Errorf("unsupported scan type for PrunePolicy: %T", value)
+	}
+
+	var req system.PruneAllRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	*p = PrunePolicy(req)
+	return nil
+}
+
+// PruneSchedule is a prune policy run on a cron schedule in one environment,
+// together with the outcome of its most recent run.
+type PruneSchedule struct {
+	BaseModel
+
+	EnvironmentID       string      `json:"environmentId" gorm:"column:environment_id;index"`
+	Name                string      `json:"name" gorm:"column:name"`
+	Schedule            string      `json:"schedule" gorm:"column:schedule"`
+	Enabled             bool        `json:"enabled" gorm:"column:enabled"`
+	Policy              PrunePolicy `json:"policy" gorm:"column:policy;type:text"`
+	LastRunAt           *time.Time  `json:"lastRunAt,omitempty" gorm:"column:last_run_at"`
+	LastRunStatus       *string     `json:"lastRunStatus,omitempty" gorm:"column:last_run_status"`
+	LastSpaceReclaimed  int64       `json:"lastSpaceReclaimed" gorm:"column:last_space_reclaimed"`
+	TotalSpaceReclaimed int64       `json:"totalSpaceReclaimed" gorm:"column:total_space_reclaimed"`
+}
+
+func (PruneSchedule) TableName() string {
+	return "prune_schedules"
+}
+
+// ToDTO converts the schedule to its API shape. nextRunAt is computed by the
+// caller from the installed cron entry.
+func (s *PruneSchedule) ToDTO(nextRunAt *time.Time) system.PruneSchedule {
+	dto := system.PruneSchedule{
+		ID:                  s.ID,
+		EnvironmentID:       s.EnvironmentID,
+		Name:                s.Name,
+		Schedule:            s.Schedule,
+		Enabled:             s.Enabled,
+		Policy:              system.PruneAllRequest(s.Policy),
+		NextRunAt:           nextRunAt,
+		LastRunAt:           s.LastRunAt,
+		LastSpaceReclaimed:  uint64(max(s.LastSpaceReclaimed, 0)),
+		TotalSpaceReclaimed: uint64(max(s.TotalSpaceReclaimed, 0)),
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+	if s.LastRunStatus != nil {
+		dto.LastRunStatus = system.PruneScheduleRunStatus(*s.LastRunStatus)
+	}
+	return dto
+}
+
+// PruneScheduleRunErrors is a JSON-serialized list of run errors, stored in a
+// single TEXT column.
+//
+//nolint:recvcheck
+type PruneScheduleRunErrors []string
+
+func (e PruneScheduleRunErrors) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	return json.Marshal([]string(e))
+}
+
+func (e *PruneScheduleRunErrors) Scan(value any) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(e))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(e))
+	default:
+		return errors.Errorf("unsupported scan type for PruneScheduleRunErrors: %T", value)
+	}
+}
+
+// PruneScheduleRun is the auditable record of one scheduled prune run.
+type PruneScheduleRun struct {
+	BaseModel
+
+	ScheduleID       string                 `json:"scheduleId" gorm:"column:schedule_id;index"`
+	Status           string                 `json:"status" gorm:"column:status"`
+	StartedAt        time.Time              `json:"startedAt" gorm:"column:started_at"`
+	FinishedAt       *time.Time             `json:"finishedAt,omitempty" gorm:"column:finished_at"`
+	SpaceReclaimed   int64                  `json:"spaceReclaimed" gorm:"column:space_reclaimed"`
+	ContainersPruned int                    `json:"containersPruned" gorm:"column:containers_pruned"`
+	ImagesDeleted    int                    `json:"imagesDeleted" gorm:"column:images_deleted"`
+	VolumesDeleted   int                    `json:"volumesDeleted" gorm:"column:volumes_deleted"`
+	NetworksDeleted  int                    `json:"networksDeleted" gorm:"column:networks_deleted"`
+	Errors           PruneScheduleRunErrors `json:"errors,omitempty" gorm:"column:errors;type:text"`
+	ActivityID       *string                `json:"activityId,omitempty" gorm:"column:activity_id"`
+}
+
+func (PruneScheduleRun) TableName() string {
+	return "prune_schedule_runs"
+}
+
+func (r *PruneScheduleRun) ToDTO() system.PruneScheduleRun {
+	return system.PruneScheduleRun{
+		ID:               r.ID,
+		ScheduleID:       r.ScheduleID,
+		Status:           system.PruneScheduleRunStatus(r.Status),
+		StartedAt:        r.StartedAt,
+		FinishedAt:       r.FinishedAt,
+		SpaceReclaimed:   uint64(max(r.SpaceReclaimed, 0)),
+		ContainersPruned: r.ContainersPruned,
+		ImagesDeleted:    r.ImagesDeleted,
+		VolumesDeleted:   r.VolumesDeleted,
+		NetworksDeleted:  r.NetworksDeleted,
+		Errors:           []string(r.Errors),
+		ActivityID:       r.ActivityID,
+	}
+}
@@ -66,16 +66,22 @@ func (s SettingVariable) AsDurationSeconds() time.Duration {
 
 type Settings struct {
 	// General category
-	ProjectsDirectory          SettingVariable `key:"projectsDirectory,envOverride" meta:"label=Projects Directory;type=text;keywords=projects,directory,path,folder,location,storage,files,compose,docker-compose;category=internal;description=Configure where project files are stored"`
-	TemplatesDirectory         SettingVariable `key:"templatesDirectory,envOverride" meta:"label=Templates Directory;type=text;keywords=templates,directory,path,folder,location,storage,compose,docker-compose;category=internal;description=Configure where local compose template folders are discovered"`
-	FollowProjectSymlinks      SettingVariable `key:"followProjectSymlinks,envOverride" meta:"label=Follow Project Symlinks;type=boolean;keywords=projects,symlink,symlinks,symbolic links,compose,directory,discovery;category=general;description=Treat symlinked child directories inside the projects directory as Docker Compose projects"`
-	SwarmStackSourcesDirectory SettingVariable `key:"swarmStackSourcesDirectory,envOverride" meta:"label=Swarm Stack Sources Directory;type=text;keywords=swarm,stacks,stack,source,sources,directory,path,folder,location,storage,compose,env;category=internal;description=Configure where swarm stack source files are stored"`
-	DiskUsagePath              SettingVariable `key:"diskUsagePath" meta:"label=Disk Usage Path;type=text;keywords=disk,usage,path,storage,folder,files;category=general;description=Path used for disk usage calculations"`
-	BaseServerURL              SettingVariable `key:"baseServerUrl" meta:"label=Base Server URL;type=text;keywords=base,url,server,domain,host,endpoint,address,link;category=general;description=Set the base URL for the application"`
-	EnableGravatar             SettingVariable `key:"enableGravatar,authrequired" meta:"label=Enable Gravatar;type=boolean;keywords=gravatar,avatar,profile,picture,image,user,photo;category=users;description=Enable Gravatar profile pictures for users"`
-	AvatarMaxUploadSizeMb      SettingVariable `key:"avatarMaxUploadSizeMb,authrequired" meta:"label=Avatar Max Upload Size (MB);type=number;keywords=avatar,profile,picture,upload,size,limit,maximum,image,user,photo,mb;category=users;description=Maximum size in MB for profile picture uploads (default: 2)"`
-	DefaultShell               SettingVariable `key:"defaultShell" meta:"label=Default Shell;type=text;keywords=shell,default,shellpath,path,login;category=general;description=Default shell to use for commands"`
-	EnvironmentHealthInterval  SettingVariable `key:"environmentHealthInterval" meta:"label=Environment Health Check Interval;type=cron;keywords=environment,health,check,interval,frequency,heartbeat,status,monitoring,uptime,jobs,schedule;description=How often to check environment connectivity (cron expression)" catmeta:"id=jobschedule;title=Automations;icon=jobs;url=/settings/jobs;description=Configure how often Arcane background jobs run"`
+	ProjectsDirectory            SettingVariable `key:"projectsDirectory,envOverride" meta:"label=Projects Directory;type=text;keywords=projects,directory,path,folder,location,storage,files,compose,docker-compose;category=internal;description=Configure where project files are stored"`
+	TemplatesDirectory           SettingVariable `key:"templatesDirectory,envOverride" meta:"label=Templates Directory;type=text;keywords=templates,directory,path,folder,location,storage,compose,docker-compose;category=internal;description=Configure where local compose template folders are discovered"`
+	FollowProjectSymlinks        SettingVariable `key:"followProjectSymlinks,envOverride" meta:"label=Follow Project Symlinks;type=boolean;keywords=projects,symlink,symlinks,symbolic links,compose,directory,discovery;category=general;description=Treat symlinked child directories inside the projects directory as Docker Compose projects"`
+	SwarmStackSourcesDirectory   SettingVariable `key:"swarmStackSourcesDirectory,envOverride" meta:"label=Swarm Stack Sources Directory;type=text;keywords=swarm,stacks,stack,source,sources,directory,path,folder,location,storage,compose,env;category=internal;description=Configure where swarm stack source files are stored"`
+	SwarmStackSourceHistoryLimit SettingVariable `key:"swarmStackSourceHistoryLimit" meta:"label=Swarm Stack Source History Limit;type=number;keywords=swarm,stacks,stack,source,history,versions,snapshots,retention,limit,diff;category=internal;description=Number of previous swarm stack source versions to keep for history and diffs. Set 0 to disable history."`
+	DiskUsagePath                SettingVariable `key:"diskUsagePath" meta:"label=Disk Usage Path;type=text;keywords=disk,usage,path,storage,folder,files;category=general;description=Path used for disk usage calculations"`
+	SystemGpuVendor              SettingVariable `key:"systemGpuVendor" meta:"label=GPU Vendor;type=select;keywords=gpu,vendor,nvidia,amd,intel,jetson,graphics,monitoring,detection,none,disable;category=general;description=Force the GPU vendor used for monitoring instead of auto-detecting it. Set none to disable GPU polling."`
+	SystemNvidiaSmiPath          SettingVariable `key:"systemNvidiaSmiPath" meta:"label=nvidia-smi Path;type=text;keywords=gpu,nvidia,nvidia-smi,path,binary,tool,monitoring,detection;category=general;description=Absolute path to nvidia-smi when it is not on PATH, for example with custom GPU runtime mounts"`
+	SystemNvidiaSmiExtraFields   SettingVariable `key:"systemNvidiaSmiExtraFields" meta:"label=nvidia-smi Extra Query Fields;type=text;keywords=gpu,nvidia,nvidia-smi,query,fields,power,clocks,fan,metrics,monitoring;category=general;description=Comma-separated nvidia-smi --query-gpu fields to report alongside GPU stats, for example power.draw,fan.speed"`
+	SystemIntelGpuTopPath        SettingVariable `key:"systemIntelGpuTopPath" meta:"label=intel_gpu_top Path;type=text;keywords=gpu,intel,intel_gpu_top,path,binary,tool,monitoring,detection;category=general;description=Absolute path to intel_gpu_top when it is not on PATH"`
+	SystemTegrastatsPath         SettingVariable `key:"systemTegrastatsPath" meta:"label=tegrastats Path;type=text;keywords=gpu,jetson,tegra,tegrastats,path,binary,tool,monitoring,detection;category=general;description=Absolute path to tegrastats on NVIDIA Jetson boards when it is not on PATH"`
+	BaseServerURL                SettingVariable `key:"baseServerUrl" meta:"label=Base Server URL;type=text;keywords=base,url,server,domain,host,endpoint,address,link;category=general;description=Set the base URL for the application"`
+	EnableGravatar               SettingVariable `key:"enableGravatar,authrequired" meta:"label=Enable Gravatar;type=boolean;keywords=gravatar,avatar,profile,picture,image,user,photo;category=users;description=Enable Gravatar profile pictures for users"`
+	AvatarMaxUploadSizeMb        SettingVariable `key:"avatarMaxUploadSizeMb,authrequired" meta:"label=Avatar Max Upload Size (MB);type=number;keywords=avatar,profile,picture,upload,size,limit,maximum,image,user,photo,mb;category=users;description=Maximum size in MB for profile picture uploads (default: 2)"`
+	DefaultShell                 SettingVariable `key:"defaultShell" meta:"label=Default Shell;type=text;keywords=shell,default,shellpath,path,login;category=general;description=Default shell to use for commands"`
+	EnvironmentHealthInterval    SettingVariable `key:"environmentHealthInterval" meta:"label=Environment Health Check Interval;type=cron;keywords=environment,health,check,interval,frequency,heartbeat,status,monitoring,uptime,jobs,schedule;description=How often to check environment connectivity (cron expression)" catmeta:"id=jobschedule;title=Automations;icon=jobs;url=/settings/jobs;description=Configure how often Arcane background jobs run"`
 
 	// Docker category
 	AutoUpdate                     SettingVariable `key:"autoUpdate" meta:"label=Auto Update;type=boolean;keywords=auto,update,automatic,upgrade,refresh,restart,deploy;category=internal;description=Automatically update containers when new images are available"`
@@ -85,23 +91,27 @@ type Settings struct {
 	PollingInterval                SettingVariable `key:"pollingInterval" meta:"label=Polling Interval;type=cron;keywords=interval,frequency,schedule,time,minutes,period,delay;category=internal;description=How often to check for image updates (cron expression)"`
 	DockerClientRefreshInterval    SettingVariable `key:"dockerClientRefreshInterval" meta:"label=Docker Client Refresh Interval;type=cron;keywords=docker,client,refresh,daemon,api,version,reconnect,renegotiate,schedule;category=internal;description=How often to refresh the cached Docker client API version (cron expression)"`
 	EventCleanupInterval           SettingVariable `key:"eventCleanupInterval" meta:"label=Event Cleanup Interval;type=cron;keywords=events,cleanup,retention,interval,frequency,schedule,history,logs,jobs;description=How often to delete old events (cron expression)"`
+	EventRetentionHours            SettingVariable `key:"eventRetentionHours" meta:"label=Event Retention;type=number;keywords=events,audit,log,retention,hours,cleanup,history,compliance;category=activity;description=Delete events older than this many hours when the event cleanup job runs. Set 0 to keep events indefinitely."`
+	NotificationLogRetentionDays   SettingVariable `key:"notificationLogRetentionDays" meta:"label=Notification Log Retention;type=number;keywords=notifications,alerts,delivery,log,history,retention,days,cleanup;category=activity;description=Delete notification delivery logs older than this many days when the event cleanup job runs. Set 0 to keep them indefinitely."`
 	ExpiredSessionsCleanupInterval SettingVariable `key:"expiredSessionsCleanupInterval" meta:"label=Expired Sessions Cleanup Interval;type=cron;keywords=sessions,cleanup,retention,expired,revoked,interval,frequency,schedule,auth,jobs;description=How often to delete expired and old revoked sessions (cron expression)"`
 	ActivityHistoryRetentionDays   SettingVariable `key:"activityHistoryRetentionDays" meta:"label=Activity History Retention;type=number;keywords=activity,history,retention,days,cleanup,background,tasks;category=activity;description=Delete completed Activity Center entries older than this many days. Set 0 to disable age-based cleanup." catmeta:"id=activity;title=Activity;icon=activity;url=/settings/activity;description=Configure Activity Center history and cleanup"`
 	ActivityHistoryMaxEntries      SettingVariable `key:"activityHistoryMaxEntries" meta:"label=Activity History Limit;type=number;keywords=activity,history,limit,entries,count,cleanup,background,tasks;category=activity;description=Maximum completed Activity Center entries to keep per environment. Set 0 to disable count-based cleanup."`
 	MaxConcurrentActivities        SettingVariable `key:"maxConcurrentActivities" meta:"label=Concurrent Activity Limit;type=number;keywords=activity,concurrency,limit,queue,parallel,bulk,background,tasks;category=activity;description=Maximum long-running activities per environment before new ones queue. Set 0 for unlimited."`
 	AutoInjectEnv                  SettingVariable `key:"autoInjectEnv" meta:"label=Auto Inject Env Variables;type=boolean;keywords=auto,inject,env,environment,variables,interpolation;category=internal;description=Automatically inject project .env variables into all containers (default: false)"`
 	DefaultDeployPullPolicy        SettingVariable `key:"defaultDeployPullPolicy" meta:"label=Default Deploy Pull Policy;type=select;keywords=deploy,pull,policy,compose,up,missing,always;category=internal;description=Default image pull policy when deploying projects"`
-	ScheduledPruneEnabled          SettingVariable `key:"scheduledPruneEnabled" meta:"label=Scheduled Prune Enabled;type=boolean;keywords=prune,cleanup,maintenance,schedule,automatic;category=internal;description=Enable scheduled pruning of unused Docker resources"`
-	ScheduledPruneInterval         SettingVariable `key:"scheduledPruneInterval" meta:"label=Scheduled Prune Interval;type=cron;keywords=prune,cleanup,interval,minutes,schedule;category=internal;description=How often to run scheduled prunes (cron expression)"`
-	PruneContainerMode             SettingVariable `key:"pruneContainerMode" meta:"label=Prune Containers;type=select;keywords=prune,containers,cleanup,maintenance,mode,older,stopped;category=internal;description=Select how containers should be pruned when the scheduled prune job runs"`
-	PruneContainerUntil            SettingVariable `key:"pruneContainerUntil" meta:"label=Container Age Filter;type=text;keywords=prune,containers,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for scheduled container prune when mode is olderThan"`
-	PruneImageMode                 SettingVariable `key:"pruneImageMode" meta:"label=Prune Images;type=select;keywords=prune,images,cleanup,maintenance,mode,dangling,all,older;category=internal;description=Select how images should be pruned when the scheduled prune job runs"`
-	PruneImageUntil                SettingVariable `key:"pruneImageUntil" meta:"label=Image Age Filter;type=text;keywords=prune,images,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for scheduled image prune when mode is olderThan"`
-	PruneVolumeMode                SettingVariable `key:"pruneVolumeMode" meta:"label=Prune Volumes;type=select;keywords=prune,volumes,cleanup,maintenance,mode,anonymous,named;category=internal;description=Select how volumes should be pruned when the scheduled prune job runs"`
-	PruneNetworkMode               SettingVariable `key:"pruneNetworkMode" meta:"label=Prune Networks;type=select;keywords=prune,networks,cleanup,maintenance,mode,unused,older;category=internal;description=Select how networks should be pruned when the scheduled prune job runs"`
-	PruneNetworkUntil              SettingVariable `key:"pruneNetworkUntil" meta:"label=Network Age Filter;type=text;keywords=prune,networks,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for scheduled network prune when mode is olderThan"`
-	PruneBuildCacheMode            SettingVariable `key:"pruneBuildCacheMode" meta:"label=Prune Build Cache;type=select;keywords=prune,build cache,cleanup,maintenance,mode,unused,all,older;category=internal;description=Select how build cache should be pruned when the scheduled prune job runs"`
-	PruneBuildCacheUntil           SettingVariable `key:"pruneBuildCacheUntil" meta:"label=Build Cache Age Filter;type=text;keywords=prune,build cache,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for scheduled build cache prune when mode is olderThan"`
+	DefaultContainerCpuLimit       SettingVariable `key:"defaultContainerCpuLimit" meta:"label=Default Container CPU Limit (cores);type=number;keywords=container,cpu,cores,limit,default,resources,create,guardrail;category=internal;description=CPU limit applied to new containers that do not set one (supports decimals, e.g. 1.5). Set 0 for no default"`
+	DefaultContainerMemoryLimitMb  SettingVariable `key:"defaultContainerMemoryLimitMb" meta:"label=Default Container Memory Limit (MB);type=number;keywords=container,memory,ram,mb,limit,default,resources,create,guardrail;category=internal;description=Memory limit in MB applied to new containers that do not set one. Set 0 for no default"`
+	DefaultContainerRestartPolicy  SettingVariable `key:"defaultContainerRestartPolicy" meta:"label=Default Container Restart Policy;type=select;keywords=container,restart,policy,default,create,always,unless-stopped,on-failure;category=internal;description=Restart policy applied to new containers that do not set one. Leave empty for Docker's default"`
+	RegistryMirrors                SettingVariable `key:"registryMirrors" meta:"label=Registry Mirrors;type=textarea;keywords=registry,mirror,mirrors,pull,through,cache,proxy,docker hub,rate limit,air-gapped,offline;category=internal;description=Pull images through a mirror or pull-through cache. Use commas or new lines to separate registry=mirror entries (for example: docker.io=mirror.example.com/dockerhub)"`
+	PruneContainerMode             SettingVariable `key:"pruneContainerMode" meta:"label=Prune Containers;type=select;keywords=prune,containers,cleanup,maintenance,mode,older,stopped;category=internal;description=Select how containers should be pruned by default"`
+	PruneContainerUntil            SettingVariable `key:"pruneContainerUntil" meta:"label=Container Age Filter;type=text;keywords=prune,containers,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for container prune when mode is olderThan"`
+	PruneImageMode                 SettingVariable `key:"pruneImageMode" meta:"label=Prune Images;type=select;keywords=prune,images,cleanup,maintenance,mode,dangling,all,older;category=internal;description=Select how images should be pruned by default"`
+	PruneImageUntil                SettingVariable `key:"pruneImageUntil" meta:"label=Image Age Filter;type=text;keywords=prune,images,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for image prune when mode is olderThan"`
+	PruneVolumeMode                SettingVariable `key:"pruneVolumeMode" meta:"label=Prune Volumes;type=select;keywords=prune,volumes,cleanup,maintenance,mode,anonymous,named;category=internal;description=Select how volumes should be pruned by default"`
+	PruneNetworkMode               SettingVariable `key:"pruneNetworkMode" meta:"label=Prune Networks;type=select;keywords=prune,networks,cleanup,maintenance,mode,unused,older;category=internal;description=Select how networks should be pruned by default"`
+	PruneNetworkUntil              SettingVariable `key:"pruneNetworkUntil" meta:"label=Network Age Filter;type=text;keywords=prune,networks,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for network prune when mode is olderThan"`
+	PruneBuildCacheMode            SettingVariable `key:"pruneBuildCacheMode" meta:"label=Prune Build Cache;type=select;keywords=prune,build cache,cleanup,maintenance,mode,unused,all,older;category=internal;description=Select how build cache should be pruned by default"`
+	PruneBuildCacheUntil           SettingVariable `key:"pruneBuildCacheUntil" meta:"label=Build Cache Age Filter;type=text;keywords=prune,build cache,cleanup,maintenance,until,older,duration;category=internal;description=Duration threshold for build cache prune when mode is olderThan"`
 	AutoHealEnabled                SettingVariable `key:"autoHealEnabled" meta:"label=Auto Heal;type=boolean;keywords=auto,heal,health,restart,unhealthy,recovery,container,healthcheck;category=internal;description=Automatically restart containers that become unhealthy"`
 	AutoHealInterval               SettingVariable `key:"autoHealInterval" meta:"label=Auto Heal Interval;type=cron;keywords=auto,heal,interval,frequency,schedule,health,jobs;description=How often to check container health (cron expression)" catmeta:"id=jobschedule"`
 	AutoHealExcludedContainers     SettingVariable `key:"autoHealExcludedContainers" meta:"label=Auto Heal Excluded Containers;type=text;keywords=auto,heal,exclude,containers,ignore,skip,health;category=internal;description=Comma-separated list of containers to exclude from auto-heal"`
@@ -163,10 +173,20 @@ type Settings struct {
 
 	// Notifications category (placeholder for category metadata only - actual settings managed via notification service)
 	NotificationsCategoryPlaceholder SettingVariable `key:"notificationsCategory,internal" meta:"label=Notifications;type=internal;keywords=notifications,alerts,email,discord,webhooks,events,messages;category=notifications;description=Configure notification providers and alerts" catmeta:"id=notifications;title=Notifications;icon=bell;url=/settings/notifications;description=Configure email and Discord notifications for container and image updates"`
+	ImageUpdateNotificationCooldown  SettingVariable `key:"imageUpdateNotificationCooldown" meta:"label=Image Update Notification Cooldown;type=number;keywords=notifications,image,update,duplicate,dedupe,cooldown,throttle,repeat,hours;category=notifications;description=Hours during which the same image update is not notified again through the same provider. Set 0 to disable (default: 24)"`
 
 	AgentToken SettingVariable `key:"agentToken,internal,sensitive"`
 	InstanceID SettingVariable `key:"instanceId,internal"`
 
+	// Agent pairing details, reported by GET /environments/0/agent/info.
+	AgentTokenCreatedAt      SettingVariable `key:"agentTokenCreatedAt,internal"`
+	AgentPairedAt            SettingVariable `key:"agentPairedAt,internal"`
+	AgentPairedControllerURL SettingVariable `key:"agentPairedControllerUrl,internal"`
+
+	// Maintenance mode is toggled through POST /environments/{id}/maintenance only.
+	MaintenanceMode   SettingVariable `key:"maintenanceMode,internal"`
+	MaintenanceReason SettingVariable `key:"maintenanceReason,internal"`
+
 	// Users category (admin management page - no actual settings)
 	UsersCategoryPlaceholder SettingVariable `key:"usersCategory,internal" meta:"label=Users;type=internal;keywords=users,accounts,management,admin,access,permissions,roles;category=users;description=Manage user accounts and permissions" catmeta:"id=users;title=Users;icon=user;url=/settings/users;description=Manage user accounts and access control"`
 
@@ -1,5 +1,11 @@
 package models
 
+const (
+	TemplateRegistryAuthNone   = "none"
+	TemplateRegistryAuthBearer = "bearer"
+	TemplateRegistryAuthBasic  = "basic"
+)
+
 type TemplateRegistry struct {
 	BaseModel
 
@@ -7,6 +13,9 @@ type TemplateRegistry struct {
 	URL         string `json:"url"`
 	Enabled     bool   `json:"enabled"`
 	Description string `json:"description"`
+	AuthType    string `json:"authType" gorm:"default:none"` // none, bearer, basic
+	Username    string `json:"username"`
+	Credential  string `json:"-"` // encrypted bearer token or basic auth password
 }
 
 type ComposeTemplate struct {
@@ -14,6 +23,7 @@ type ComposeTemplate struct {
 
 	Name        string                   `json:"name"`
 	Description string                   `json:"description"`
+	Category    string                   `json:"category"`
 	Content     string                   `json:"content" gorm:"type:text"`
 	EnvContent  *string                  `json:"envContent,omitempty" gorm:"type:text"`
 	IsCustom    bool                     `json:"isCustom"`
@@ -21,6 +31,12 @@ type ComposeTemplate struct {
 	RegistryID  *string                  `json:"registryId,omitempty"`
 	Registry    *TemplateRegistry        `json:"registry,omitempty" gorm:"foreignKey:RegistryID;references:ID"`
 	Metadata    *ComposeTemplateMetadata `json:"metadata,omitempty" gorm:"embedded;embeddedPrefix:meta_"`
+
+	// Source* track where a downloaded template came from. SourceDigest hashes
+	// the upstream content as of the last download or refresh.
+	SourceURL        *string `json:"sourceUrl,omitempty"`
+	SourceRegistryID *string `json:"sourceRegistryId,omitempty"`
+	SourceDigest     *string `json:"sourceDigest,omitempty"`
 }
 
 type ComposeTemplateMetadata struct {
@@ -33,5 +49,9 @@ type ComposeTemplateMetadata struct {
 	IconURL          *string  `json:"iconUrl,omitempty"`
 }
 
+// HasCredential reports whether a secret is stored. It is exposed in API
+// responses in place of the secret itself.
+func (r TemplateRegistry) HasCredential() bool { return r.Credential != "" }
+
 func (TemplateRegistry) TableName() string { return "template_registries" }
 func (ComposeTemplate) TableName() string  { return "compose_templates" }
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -113,6 +114,9 @@ func (s *ApiKeyService) markApiKeyUsedInternal(ctx context.Context, keyID string
 }
 
 func (s *ApiKeyService) CreateApiKey(ctx context.Context, userID string, callerPerms *authz.PermissionSet, req apikey.CreateApiKey) (*apikey.ApiKeyCreatedDto, error) {
+	if err := s.validateGrantShapeInternal(ctx, req.Permissions); err != nil {
+		return nil, err
+	}
 	if err := validateGrantsAgainstPermissionSetInternal(callerPerms, req.Permissions); err != nil {
 		return nil, err
 	}
@@ -149,6 +153,51 @@ const ErrApiKeyPermissionEscalation = errors.Sentinel("cannot grant a permission
 // the owner's role permissions at authentication time.
 const ErrApiKeyPersonalNoGrants = errors.Sentinel("personal API keys inherit the owner's permissions and cannot carry grants")
 
+// ErrApiKeyInvalidGrant is returned when a grant names an unknown permission,
+// scopes an org-level permission to an environment, or references an
+// environment that does not exist.
+const ErrApiKeyInvalidGrant = errors.Sentinel("invalid API key permission grant")
+
+// validateGrantShapeInternal rejects grants that could never take effect before
+// they are checked against the caller and owner. Org-level permissions are only
+// resolved globally, so scoping one to an environment would silently grant
+// nothing; an unknown environment ID would do the same until an environment
+// with that ID appeared.
+func (s *ApiKeyService) validateGrantShapeInternal(ctx context.Context, grants []apikey.PermissionGrant) error {
+	envIDs := make([]string, 0, len(grants))
+	for _, g := range grants {
+		if !authz.IsKnownPermission(g.Permission) {
+			return errors.WrapIff(ErrApiKeyInvalidGrant, "unknown permission %q", g.Permission)
+		}
+		if g.EnvironmentID == nil {
+			continue
+		}
+		if *g.EnvironmentID == "" {
+			return errors.WrapIff(ErrApiKeyInvalidGrant, "%s: environment ID must not be empty", g.Permission)
+		}
+		if authz.IsOrgLevel(g.Permission) {
+			return errors.WrapIff(ErrApiKeyInvalidGrant, "%s is org-level and cannot be scoped to an environment", g.Permission)
+		}
+		if !slices.Contains(envIDs, *g.EnvironmentID) {
+			envIDs = append(envIDs, *g.EnvironmentID)
+		}
+	}
+	if len(envIDs) == 0 {
+		return nil
+	}
+
+	var found []string
+	if err := s.db.WithContext(ctx).Model(&models.Environment{}).Where("id IN ?", envIDs).Pluck("id", &found).Error; err != nil {
+		return errors.WrapIf(err, "failed to look up grant environments")
+	}
+	for _, id := range envIDs {
+		if !slices.Contains(found, id) {
+			return errors.WrapIff(ErrApiKeyInvalidGrant, "environment %q not found", id)
+		}
+	}
+	return nil
+}
+
 // validateGrantsAgainstOwnerInternal caps grants at the key owner's role
 // permissions, so no holder of apikeys:create/update — sudo included — can
 // push a key above what its owner is allowed to do. Complements the caller-set
@@ -579,6 +628,9 @@ func (s *ApiKeyService) UpdateApiKey(ctx context.Context, callerPerms *authz.Per
 		if ak.Kind == models.ApiKeyKindPersonal {
 			return nil, ErrApiKeyPersonalNoGrants
 		}
+		if err := s.validateGrantShapeInternal(ctx, req.Permissions); err != nil {
+			return nil, err
+		}
 		// Grants are capped twice: by the calling credential's effective
 		// permissions (a holder of apikeys:update cannot grant anything the
 		// credential itself cannot do right now) AND by the key owner's role
@@ -295,6 +295,42 @@ func TestCreateApiKeyRejectsGrantsBeyondCallerPermissions(t *testing.T) {
 	require.Equal(t, models.ApiKeyKindScoped, created.Kind)
 }
 
+func TestCreateApiKeyValidatesGrantScope(t *testing.T) {
+	ctx := context.Background()
+	db := setupAuthServiceTestDB(t)
+	userSvc := NewUserService(db)
+	service := NewApiKeyService(db, userSvc)
+	owner := createTestUser(t, userSvc, "scoped-owner", "scoped-owner")
+	createNamedTestEnvironmentInternal(t, db, "env-staging", "staging", "http://staging:3552", nil)
+
+	for name, grant := range map[string]apikey.PermissionGrant{
+		"unknown permission":   {Permission: "containers:teleport"},
+		"org-level env grant":  {Permission: authz.PermUsersList, EnvironmentID: new("env-staging")},
+		"missing environment":  {Permission: authz.PermProjectsDeploy, EnvironmentID: new("env-missing")},
+		"empty environment ID": {Permission: authz.PermProjectsDeploy, EnvironmentID: new("")},
+	} {
+		_, err := service.CreateApiKey(ctx, owner.ID, authz.SudoPermissionSet(), apikey.CreateApiKey{
+			Name:        name,
+			Permissions: []apikey.PermissionGrant{grant},
+		})
+		require.ErrorIs(t, err, ErrApiKeyInvalidGrant, name)
+	}
+
+	created, err := service.CreateApiKey(ctx, owner.ID, authz.SudoPermissionSet(), apikey.CreateApiKey{
+		Name: "ci-staging",
+		Permissions: []apikey.PermissionGrant{
+			{Permission: authz.PermProjectsDeploy, EnvironmentID: new("env-staging")},
+			{Permission: authz.PermEnvironmentsList},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = service.UpdateApiKey(ctx, authz.SudoPermissionSet(), created.ID, apikey.UpdateApiKey{
+		Permissions: []apikey.PermissionGrant{{Permission: authz.PermProjectsDeploy, EnvironmentID: new("env-missing")}},
+	})
+	require.ErrorIs(t, err, ErrApiKeyInvalidGrant)
+}
+
 func TestApiKeyGrantsAreCappedByOwnerRoles(t *testing.T) {
 	ctx := context.Background()
 	db := setupAuthServiceTestDB(t)
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
 	json "encoding/json/v2"
 	stderrors "errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -763,6 +767,239 @@ func (s *ContainerRegistryService) TestECRRegistry(ctx context.Context, reg *mod
 	return nil
 }
 
+// ProbeRegistryAuth performs a token handshake against the registry's /v2/
+// endpoint with the registry's stored credentials and reports the scheme,
+// token service and scope the registry granted. Unlike TestRegistry it talks to
+// the registry directly instead of going through the Docker daemon.
+func (s *ContainerRegistryService) ProbeRegistryAuth(ctx context.Context, reg *models.ContainerRegistry) (*containerregistry.TestResult, error) {
+	var username, token string
+	if reg.RegistryType == registryTypeECR {
+		ecrUser, ecrPass, err := s.GetOrRefreshECRToken(ctx, reg)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to obtain ECR token")
+		}
+		username, token = ecrUser, ecrPass
+	} else if reg.Token != "" {
+		decrypted, err := crypto.Decrypt(reg.Token)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to decrypt registry token")
+		}
+		username, token = strings.TrimSpace(reg.Username), strings.TrimSpace(decrypted)
+	}
+
+	host := normalizeRegistryServerAddressInternal(reg.URL)
+	if host == "" {
+		return nil, errors.New("registry URL is empty")
+	}
+	if utilsregistry.NormalizeRegistryForComparison(host) == utilsregistry.DefaultRegistryDomain {
+		host = utilsregistry.DefaultRegistry
+	}
+	scheme := "https"
+	if reg.Insecure {
+		scheme = "http"
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, timeouts.DefaultRegistry)
+	defer cancel()
+	return s.probeRegistryAuthInternal(probeCtx, scheme+"://"+host, username, token)
+}
+
+// probeRegistryAuthInternal follows the registry's /v2/ auth challenge: a basic
+// challenge is answered by retrying /v2/ with the credentials, a bearer challenge
+// by fetching a token from the advertised realm.
+func (s *ContainerRegistryService) probeRegistryAuthInternal(ctx context.Context, baseURL, username, token string) (*containerregistry.TestResult, error) {
+	hasCredentials := username != "" || token != ""
+
+	resp, err := s.doRegistryProbeRequestInternal(ctx, baseURL+"/v2/", "", "")
+	if err != nil {
+		return nil, err
+	}
+	challenge := resp.Header.Get(utilsregistry.ChallengeHeader)
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &containerregistry.TestResult{AuthType: "none"}, nil
+	case http.StatusUnauthorized:
+	default:
+		return nil, errors.Errorf("registry returned %s for /v2/", resp.Status)
+	}
+
+	scheme, params := parseRegistryChallengeInternal(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCredentials {
+			return nil, errors.New("registry requires credentials but none are configured")
+		}
+		resp, err := s.doRegistryProbeRequestInternal(ctx, baseURL+"/v2/", username, token)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("registry rejected the credentials: %s", resp.Status)
+		}
+		return &containerregistry.TestResult{AuthType: "basic"}, nil
+	case "bearer":
+		return s.fetchRegistryProbeTokenInternal(ctx, baseURL, params, username, token)
+	default:
+		return nil, errors.Errorf("registry returned an unsupported auth challenge %q", challenge)
+	}
+}
+
+// fetchRegistryProbeTokenInternal requests a token from the bearer challenge's
+// realm. The realm is chosen by the registry, so the credentials are only sent
+// when it is served over https or by the registry itself; any other realm is
+// asked for an anonymous token instead.
+func (s *ContainerRegistryService) fetchRegistryProbeTokenInternal(ctx context.Context, baseURL string, params map[string]string, username, token string) (*containerregistry.TestResult, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return nil, errors.Errorf("registry bearer challenge has an invalid realm %q", params["realm"])
+	}
+	if !registryRealmTrustedInternal(realm, baseURL) {
+		slog.WarnContext(ctx, "registry token realm is neither https nor the registry host; requesting an anonymous token", "realm", realm.Redacted())
+		username, token = "", ""
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	if username != "" {
+		query.Set("account", username)
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := s.doRegistryProbeRequestInternal(ctx, realm.String(), username, token)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, errors.Errorf("registry rejected the credentials: %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("registry token endpoint returned %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Scope       string `json:"scope"`
+	}
+	if err := json.UnmarshalRead(io.LimitReader(resp.Body, 1<<20), &body); err != nil {
+		return nil, errors.WrapIf(err, "failed to decode registry token response")
+	}
+	issued := cmp.Or(body.Token, body.AccessToken)
+	if issued == "" {
+		return nil, errors.New("registry token response did not include a token")
+	}
+
+	scope := strings.Fields(body.Scope)
+	if len(scope) == 0 {
+		scope = registryTokenAccessInternal(issued)
+	}
+	if len(scope) == 0 {
+		scope = strings.Fields(params["scope"])
+	}
+
+	return &containerregistry.TestResult{
+		AuthType:  "bearer",
+		Service:   params["service"],
+		Scope:     scope,
+		ExpiresIn: body.ExpiresIn,
+	}, nil
+}
+
+// registryRealmTrustedInternal reports whether realm may receive the
+// registry's credentials: it is served over https, or by the same host as
+// baseURL.
+func registryRealmTrustedInternal(realm *url.URL, baseURL string) bool {
+	if strings.EqualFold(realm.Scheme, "https") {
+		return true
+	}
+	base, err := url.Parse(baseURL)
+	return err == nil && base.Host != "" && strings.EqualFold(realm.Host, base.Host)
+}
+
+func (s *ContainerRegistryService) doRegistryProbeRequestInternal(ctx context.Context, target, username, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to build registry request for %s", target)
+	}
+	if username != "" || token != "" {
+		req.SetBasicAuth(username, token)
+	}
+
+	resp, err := s.distributionHTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.WrapIff(err, "registry request to %s failed", req.URL.Host)
+	}
+	return resp, nil
+}
+
+// parseRegistryChallengeInternal splits a WWW-Authenticate header into its
+// lowercased scheme and parameters. Quoted values may contain commas, as
+// multi-action scopes do.
+func parseRegistryChallengeInternal(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if quoted, ok := strings.CutPrefix(value, `"`); ok {
+			value, rest, _ = strings.Cut(quoted, `"`)
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+		}
+		params[key] = strings.TrimSpace(value)
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ","))
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+// registryTokenAccessInternal reads the access claim of a distribution JWT as
+// type:name:actions entries. The signature is not verified; the claim is only
+// used to report what the registry granted.
+func registryTokenAccessInternal(token string) []string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+
+	var claims struct {
+		Access []struct {
+			Type    string   `json:"type"`
+			Name    string   `json:"name"`
+			Actions []string `json:"actions"`
+		} `json:"access"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+
+	scope := make([]string, 0, len(claims.Access))
+	for _, access := range claims.Access {
+		scope = append(scope, access.Type+":"+access.Name+":"+strings.Join(access.Actions, ","))
+	}
+	return scope
+}
+
 // GetImageDigest fetches the current digest for an image:tag from the registry
 // This is used for digest-based update detection for non-semver tags
 func (s *ContainerRegistryService) GetImageDigest(ctx context.Context, imageRef string) (string, error) {
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -1001,3 +1002,102 @@ func TestContainerRegistryService_InspectImageDigest_PreservesAnonymousUnauthori
 	assert.Contains(t, err.Error(), "status: 401")
 	assert.Contains(t, err.Error(), "failed to load enabled registries")
 }
+
+func TestContainerRegistryService_ProbeRegistryAuth_BearerReportsGrantedScope(t *testing.T) {
+	claims, err := json.Marshal(map[string]any{
+		"access": []map[string]any{{"type": "repository", "name": "team/app", "actions": []string{"pull", "push"}}},
+	})
+	require.NoError(t, err)
+	issued := "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry.test",scope="repository:team/app:pull,push"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/token":
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "user" || password != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "registry.test", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:team/app:pull,push", r.URL.Query().Get("scope"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"token": issued, "expires_in": 300})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewContainerRegistryService(nil, nil, nil)
+	svc.distributionHTTPClient = server.Client()
+
+	result, err := svc.probeRegistryAuthInternal(context.Background(), server.URL, "user", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "bearer", result.AuthType)
+	assert.Equal(t, "registry.test", result.Service)
+	assert.Equal(t, []string{"repository:team/app:pull,push"}, result.Scope)
+	assert.Equal(t, 300, result.ExpiresIn)
+
+	_, err = svc.probeRegistryAuthInternal(context.Background(), server.URL, "user", "expired")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected the credentials")
+}
+
+func TestContainerRegistryService_ProbeRegistryAuth_PlainHTTPRealmOnOtherHostGetsNoCredentials(t *testing.T) {
+	realmServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, ok := r.BasicAuth()
+		assert.False(t, ok, "credentials must not be sent to a plain-http realm on another host")
+		assert.Empty(t, r.URL.Query().Get("account"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"token": "anonymous-token"})
+	}))
+	defer realmServer.Close()
+
+	registryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+realmServer.URL+`/token",service="registry.test",scope="repository:team/app:pull"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer registryServer.Close()
+
+	svc := NewContainerRegistryService(nil, nil, nil)
+	svc.distributionHTTPClient = registryServer.Client()
+
+	result, err := svc.probeRegistryAuthInternal(context.Background(), registryServer.URL, "user", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "bearer", result.AuthType)
+	assert.Equal(t, []string{"repository:team/app:pull"}, result.Scope)
+}
+
+func TestContainerRegistryService_ProbeRegistryAuth_BasicChallenge(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	svc := NewContainerRegistryService(nil, nil, nil)
+	svc.distributionHTTPClient = server.Client()
+
+	result, err := svc.probeRegistryAuthInternal(context.Background(), server.URL, "user", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "basic", result.AuthType)
+
+	_, err = svc.probeRegistryAuthInternal(context.Background(), server.URL, "", "")
+	require.Error(t, err)
+}
+
+func TestParseRegistryChallengeInternal(t *testing.T) {
+	scheme, params := parseRegistryChallengeInternal(`Bearer realm="https://auth.example.com/token", service="registry.example.com",scope="repository:a/b:pull,push"`)
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull,push",
+	}, params)
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json/jsontext"
 	json "encoding/json/v2"
@@ -8,18 +12,27 @@ import (
 	"io"
 	"log/slog"
 	"maps"
+	"net/netip"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
 
 	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/events"
 	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/client"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	dockerutils "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
@@ -27,15 +40,18 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/timeouts"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/iconcatalog"
 	containertypes "github.com/getarcaneapp/arcane/types/v2/container"
 	"github.com/getarcaneapp/arcane/types/v2/containerregistry"
 	imagetypes "github.com/getarcaneapp/arcane/types/v2/image"
+	volumetypes "github.com/getarcaneapp/arcane/types/v2/volume"
 	"github.com/samber/hot"
 	"go.getarcane.app/streams/bus"
 	containerstats "go.getarcane.app/streams/stats"
 	"go.getarcane.app/sys/cgroup"
 	libupdater "go.getarcane.app/updater/pkg/labels"
+	"golang.org/x/sync/errgroup"
 )
 
 type ContainerService struct {
@@ -48,14 +64,76 @@ type ContainerService struct {
 	statsHistory    containerstats.Store
 	updateInfoCache *hot.HotCache[string, *imagetypes.UpdateInfo]
 	iconMetaCache   *hot.HotCache[string, projects.ArcaneComposeMetadata]
+	// operationLocks serializes lifecycle operations per container ID while
+	// letting different containers proceed in parallel. Entries are dropped
+	// once no operation holds or waits for them.
+	operationLocksMu sync.Mutex
+	operationLocks   map[string]*containerOperationLockInternal
+}
+
+// containerOperationLockInternal is one container's operation semaphore.
+// refs counts the holder and waiters and is guarded by operationLocksMu.
+type containerOperationLockInternal struct {
+	sem  chan struct{}
+	refs int
 }
 
 const (
 	containerGroupByProject  = "project"
 	containerNoProjectGroup  = "No Project"
 	containerIconMetadataTTL = 5 * time.Second
+
+	// containerSummaryInspectConcurrency bounds parallel inspects for summary
+	// enrichment when CONTAINER_LIST_INSPECT_CONCURRENCY is not set.
+	containerSummaryInspectConcurrency = 5
+	// containerBatchActionConcurrency bounds parallel Docker calls for a batch action.
+	containerBatchActionConcurrency = 4
+	// containerOperationLockWait is how long a lifecycle operation waits for another
+	// operation on the same container before giving up.
+	containerOperationLockWait = 2 * time.Second
 )
 
+// ErrContainerFileTooLarge is returned when a container file write exceeds the configured cap.
+var ErrContainerFileTooLarge = errors.Sentinel("file exceeds the maximum allowed size")
+
+// ContainerSummaryEnrichment selects the optional per-container details
+// ListContainersPaginated fills in for the containers it returns. The enabled
+// details share a single inspect per container.
+type ContainerSummaryEnrichment struct {
+	// GPUs fills Summary.GPUs from the container's device requests.
+	GPUs bool
+	// Health fills Summary.Health with the healthcheck status, without its log.
+	Health bool
+}
+
+// containerSummaryInspectEnricher copies details from a container inspect onto its summary.
+type containerSummaryInspectEnricher func(summary *containertypes.Summary, inspect container.InspectResponse)
+
+// inspectEnrichersInternal returns the enrichers for the enabled details, or
+// nil when none of them needs an inspect.
+func (e ContainerSummaryEnrichment) inspectEnrichersInternal() []containerSummaryInspectEnricher {
+	var enrichers []containerSummaryInspectEnricher
+	if e.GPUs {
+		enrichers = append(enrichers, func(summary *containertypes.Summary, inspect container.InspectResponse) {
+			if inspect.HostConfig != nil {
+				summary.GPUs = containertypes.NewGPUAssignment(inspect.HostConfig.DeviceRequests)
+			}
+		})
+	}
+	if e.Health {
+		enrichers = append(enrichers, func(summary *containertypes.Summary, inspect container.InspectResponse) {
+			if inspect.State == nil {
+				return
+			}
+			if health := containertypes.NewHealth(inspect.State.Health); health != nil {
+				health.Log = nil
+				summary.Health = health
+			}
+		})
+	}
+	return enrichers
+}
+
 type ContainerListResult struct {
 	Items      []containertypes.Summary
 	Groups     []containertypes.SummaryGroup
@@ -155,33 +233,16 @@ func shouldStartRedeployedContainerInternal(containerInfo container.InspectRespo
 	return shouldStart
 }
 
-func (s *ContainerService) pullRedeployImageInternal(ctx context.Context, dockerClient *client.Client, imageName, containerID, containerName string, user models.User) error {
+func (s *ContainerService) pullRedeployImageInternal(ctx context.Context, dockerClient *client.Client, imageName, containerID, containerName, action string, user models.User) error {
 	settings := s.settingsService.GetSettingsConfig()
 	pullCtx, pullCancel := timeouts.WithTimeout(ctx, settings.DockerImagePullTimeout.AsInt(), timeouts.DefaultDockerImagePull)
 	defer pullCancel()
 
-	pullOptions, authErr := s.imageService.getPullOptionsWithAuth(ctx, imageName, nil)
-	if authErr != nil {
-		slog.WarnContext(ctx, "failed to get registry authentication for container redeploy pull; proceeding without auth",
-			"image", imageName,
-			"error", authErr.Error(),
-		)
-		pullOptions = client.ImagePullOptions{}
-	}
-
-	reader, pullErr := dockerClient.ImagePull(pullCtx, imageName, pullOptions)
-	if pullErr != nil && shouldRetryAnonymousPullInternal(pullOptions, pullErr) {
-		slog.WarnContext(ctx, "container redeploy image pull failed with registry auth; retrying anonymously",
-			"image", imageName,
-			"error", pullErr.Error(),
-		)
-		pullOptions = client.ImagePullOptions{}
-		reader, pullErr = dockerClient.ImagePull(pullCtx, imageName, pullOptions)
-	}
+	reader, pulledRef, pullErr := s.imageService.openImagePullInternal(pullCtx, dockerClient, imageName, nil)
 	if pullErr != nil {
 		if errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
 			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", pullErr, models.JSON{
-				"action": "redeploy",
+				"action": action,
 				"step":   "pull_image_timeout",
 				"image":  imageName,
 			})
@@ -189,7 +250,7 @@ func (s *ContainerService) pullRedeployImageInternal(ctx context.Context, docker
 		}
 
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", pullErr, models.JSON{
-			"action": "redeploy",
+			"action": action,
 			"step":   "pull_image",
 			"image":  imageName,
 		})
@@ -204,21 +265,21 @@ func (s *ContainerService) pullRedeployImageInternal(ctx context.Context, docker
 	streamErr := dockerutils.RenderJSONMessageStream(reader, logWriter)
 	if streamErr != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", streamErr, models.JSON{
-			"action": "redeploy",
+			"action": action,
 			"step":   "complete_pull",
 			"image":  imageName,
 		})
 		return errors.WrapIf(streamErr, "failed to complete image pull")
 	}
 
-	return nil
+	return retagMirroredPullInternal(ctx, dockerClient, pulledRef, imageName)
 }
 
-func (s *ContainerService) prepareContainerForRedeployInternal(ctx context.Context, dockerClient *client.Client, containerID, containerName, backupName string, wasRunning bool, user models.User) error {
+func (s *ContainerService) prepareContainerForRedeployInternal(ctx context.Context, dockerClient *client.Client, containerID, containerName, backupName, action string, wasRunning bool, user models.User) error {
 	if containerName != "" {
 		if _, err := dockerClient.ContainerRename(ctx, containerID, client.ContainerRenameOptions{NewName: backupName}); err != nil {
 			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", err, models.JSON{
-				"action":     "redeploy",
+				"action":     action,
 				"step":       "rename_old",
 				"backupName": backupName,
 			})
@@ -238,24 +299,24 @@ func (s *ContainerService) prepareContainerForRedeployInternal(ctx context.Conte
 	if containerName != "" {
 		if _, renameErr := dockerClient.ContainerRename(ctx, containerID, client.ContainerRenameOptions{NewName: containerName}); renameErr != nil {
 			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", renameErr, models.JSON{
-				"action": "redeploy",
+				"action": action,
 				"step":   "restore_name_after_stop_failure",
 			})
 		}
 	}
 
 	s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", err, models.JSON{
-		"action": "redeploy",
+		"action": action,
 		"step":   "stop",
 	})
 	return errors.WrapIf(err, "failed to stop container")
 }
 
-func (s *ContainerService) restoreContainerAfterRedeployFailureInternal(ctx context.Context, dockerClient *client.Client, containerID, containerName, backupName, failedStep string, wasRunning bool, user models.User) {
+func (s *ContainerService) restoreContainerAfterRedeployFailureInternal(ctx context.Context, dockerClient *client.Client, containerID, containerName, backupName, failedStep, action string, wasRunning bool, user models.User) {
 	if wasRunning {
 		if _, startErr := dockerClient.ContainerStart(ctx, containerID, client.ContainerStartOptions{}); startErr != nil {
 			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", startErr, models.JSON{
-				"action":     "redeploy",
+				"action":     action,
 				"step":       "restore_start_original",
 				"failedStep": failedStep,
 			})
@@ -268,7 +329,7 @@ func (s *ContainerService) restoreContainerAfterRedeployFailureInternal(ctx cont
 
 	if _, renameErr := dockerClient.ContainerRename(ctx, containerID, client.ContainerRenameOptions{NewName: containerName}); renameErr != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, backupName, user.ID, user.Username, "0", renameErr, models.JSON{
-			"action":     "redeploy",
+			"action":     action,
 			"step":       "restore_name",
 			"failedStep": failedStep,
 		})
@@ -283,7 +344,88 @@ type containerLifecycleActionInternal struct {
 	runContainerAction func(*client.Client) error
 }
 
+// lockContainerOperationInternal waits up to containerOperationLockWait for
+// exclusive use of containerID and returns the matching unlock function. It
+// fails with common.ErrContainerOperationInProgress when another operation on
+// the same container still holds the lock.
+//
+// The lock is keyed by the container's full ID, so a name, a short ID and the
+// full ID all contend for the same lock.
+func (s *ContainerService) lockContainerOperationInternal(ctx context.Context, containerID string) (func(), error) {
+	key := s.containerOperationLockKeyInternal(ctx, containerID)
+	lock := s.retainContainerOperationLockInternal(key)
+
+	timer := time.NewTimer(containerOperationLockWait)
+	defer timer.Stop()
+	select {
+	case lock.sem <- struct{}{}:
+		return func() {
+			<-lock.sem
+			s.releaseContainerOperationLockInternal(key)
+		}, nil
+	case <-timer.C:
+		s.releaseContainerOperationLockInternal(key)
+		return nil, common.ErrContainerOperationInProgress
+	case <-ctx.Done():
+		s.releaseContainerOperationLockInternal(key)
+		return nil, errors.WrapIf(ctx.Err(), "waiting for container operation lock")
+	}
+}
+
+// containerOperationLockKeyInternal resolves ref to the container's full ID.
+// A reference that can't be inspected is used as-is; the operation itself
+// then reports why the container is unavailable.
+func (s *ContainerService) containerOperationLockKeyInternal(ctx context.Context, ref string) string {
+	if s.dockerService == nil {
+		return ref
+	}
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return ref
+	}
+	inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, ref, client.ContainerInspectOptions{})
+	if err != nil || inspect.Container.ID == "" {
+		return ref
+	}
+	return inspect.Container.ID
+}
+
+func (s *ContainerService) retainContainerOperationLockInternal(key string) *containerOperationLockInternal {
+	s.operationLocksMu.Lock()
+	defer s.operationLocksMu.Unlock()
+
+	if s.operationLocks == nil {
+		s.operationLocks = make(map[string]*containerOperationLockInternal)
+	}
+	lock, ok := s.operationLocks[key]
+	if !ok {
+		lock = &containerOperationLockInternal{sem: make(chan struct{}, 1)}
+		s.operationLocks[key] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+func (s *ContainerService) releaseContainerOperationLockInternal(key string) {
+	s.operationLocksMu.Lock()
+	defer s.operationLocksMu.Unlock()
+
+	lock, ok := s.operationLocks[key]
+	if !ok {
+		return
+	}
+	if lock.refs--; lock.refs <= 0 {
+		delete(s.operationLocks, key)
+	}
+}
+
 func (s *ContainerService) runContainerLifecycleActionInternal(ctx context.Context, containerID string, user models.User, cfg containerLifecycleActionInternal) error {
+	unlock, err := s.lockContainerOperationInternal(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": cfg.action})
@@ -323,28 +465,108 @@ func (s *ContainerService) StartContainer(ctx context.Context, containerID strin
 	})
 }
 
-func (s *ContainerService) StopContainer(ctx context.Context, containerID string, user models.User) error {
+// ContainerStopOptions overrides how a container is stopped. A nil Timeout
+// keeps the action's default grace period and an empty Signal keeps the
+// container's configured stop signal.
+type ContainerStopOptions struct {
+	// Timeout is the number of seconds to wait before the container is killed.
+	Timeout *int
+	// Signal is sent instead of the container's stop signal, e.g. SIGINT.
+	Signal string
+}
+
+// containerStopDefaultTimeout is the grace period StopContainer uses when no
+// timeout is given.
+const containerStopDefaultTimeout = 30
+
+func (s *ContainerService) StopContainer(ctx context.Context, containerID string, opts ContainerStopOptions, user models.User) error {
+	signal, err := normalizeContainerSignalInternal(opts.Signal)
+	if err != nil {
+		return err
+	}
+	timeout := cmp.Or(opts.Timeout, new(containerStopDefaultTimeout))
+
 	return s.runContainerLifecycleActionInternal(ctx, containerID, user, containerLifecycleActionInternal{
 		action:    "stop",
 		eventType: models.EventTypeContainerStop,
+		metadata:  stopOptionsMetadataInternal(opts.Timeout, signal),
 		runContainerAction: func(dockerClient *client.Client) error {
-			_, err := dockerClient.ContainerStop(ctx, containerID, client.ContainerStopOptions{Timeout: new(30)})
+			_, err := dockerClient.ContainerStop(ctx, containerID, client.ContainerStopOptions{Signal: signal, Timeout: timeout})
 			return err
 		},
 	})
 }
 
-func (s *ContainerService) RestartContainer(ctx context.Context, containerID string, user models.User) error {
+// RestartContainer restarts the container. Without a timeout in opts the
+// daemon's default grace period applies.
+func (s *ContainerService) RestartContainer(ctx context.Context, containerID string, opts ContainerStopOptions, user models.User) error {
+	signal, err := normalizeContainerSignalInternal(opts.Signal)
+	if err != nil {
+		return err
+	}
+
 	return s.runContainerLifecycleActionInternal(ctx, containerID, user, containerLifecycleActionInternal{
 		action:    "restart",
 		eventType: models.EventTypeContainerRestart,
+		metadata:  stopOptionsMetadataInternal(opts.Timeout, signal),
 		runContainerAction: func(dockerClient *client.Client) error {
-			_, err := dockerClient.ContainerRestart(ctx, containerID, client.ContainerRestartOptions{})
+			_, err := dockerClient.ContainerRestart(ctx, containerID, client.ContainerRestartOptions{Signal: signal, Timeout: opts.Timeout})
 			return err
 		},
 	})
 }
 
+func stopOptionsMetadataInternal(timeout *int, signal string) models.JSON {
+	metadata := models.JSON{}
+	if timeout != nil {
+		metadata["timeout"] = *timeout
+	}
+	if signal != "" {
+		metadata["signal"] = signal
+	}
+	return metadata
+}
+
+// containerSignalNames lists the Linux signal names Docker accepts, without
+// the SIG prefix.
+var containerSignalNames = []string{
+	"ABRT", "ALRM", "BUS", "CHLD", "CONT", "FPE", "HUP", "ILL", "INT", "IO",
+	"KILL", "PIPE", "PROF", "PWR", "QUIT", "SEGV", "STKFLT", "STOP", "SYS",
+	"TERM", "TRAP", "TSTP", "TTIN", "TTOU", "URG", "USR1", "USR2", "VTALRM",
+	"WINCH", "XCPU", "XFSZ", "RTMIN", "RTMAX",
+}
+
+// normalizeContainerSignalInternal validates a signal given as a name (TERM or
+// SIGTERM, any case), a real-time offset (SIGRTMIN+3) or a number, and returns
+// it in the form sent to Docker. An empty signal is returned unchanged.
+func normalizeContainerSignalInternal(signal string) (string, error) {
+	signal = strings.TrimSpace(signal)
+	if signal == "" {
+		return "", nil
+	}
+	if n, err := strconv.Atoi(signal); err == nil {
+		if n < 1 || n > 64 {
+			return "", errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid signal %q", signal)
+		}
+		return signal, nil
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	base, offset, hasOffset := strings.Cut(name, "+")
+	if !hasOffset {
+		base, offset, hasOffset = strings.Cut(name, "-")
+	}
+	if hasOffset {
+		n, err := strconv.Atoi(offset)
+		if (base != "RTMIN" && base != "RTMAX") || err != nil || n < 1 || n > 30 {
+			return "", errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid signal %q", signal)
+		}
+	} else if !slices.Contains(containerSignalNames, base) {
+		return "", errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid signal %q", signal)
+	}
+	return "SIG" + name, nil
+}
+
 // KillContainer sends a signal to the container's main process (default SIGKILL
 // when signal is empty) without removing the container.
 func (s *ContainerService) KillContainer(ctx context.Context, containerID, signal string, user models.User) error {
@@ -386,6 +608,225 @@ func (s *ContainerService) UnpauseContainer(ctx context.Context, containerID str
 	})
 }
 
+// containerMinMemoryLimit is the smallest memory limit Docker accepts.
+const containerMinMemoryLimit = 6 * 1024 * 1024
+
+// UpdateContainerResources changes a container's restart policy and CPU/memory
+// limits in place through Docker's container update API. The container keeps
+// its ID and keeps running; a running container is held to the new limits
+// immediately. Docker cannot lift a limit this way, so zero is rejected.
+func (s *ContainerService) UpdateContainerResources(ctx context.Context, containerID string, update containertypes.ResourceUpdate, user models.User) (*containertypes.ResourceUpdateResult, error) {
+	options, metadata, err := buildContainerUpdateOptionsInternal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	var result client.ContainerUpdateResult
+	err = s.runContainerLifecycleActionInternal(ctx, containerID, user, containerLifecycleActionInternal{
+		action:         "update_resources",
+		eventType:      models.EventTypeContainerUpdate,
+		metadata:       metadata,
+		warnOnLogError: true,
+		runContainerAction: func(dockerClient *client.Client) error {
+			var updateErr error
+			result, updateErr = dockerClient.ContainerUpdate(ctx, containerID, options)
+			return updateErr
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &containertypes.ResourceUpdateResult{Warnings: result.Warnings}, nil
+}
+
+// buildContainerUpdateOptionsInternal validates update and converts it into
+// ContainerUpdate options, along with the event metadata describing the change.
+// Invalid values fail with cerrdefs.ErrInvalidArgument.
+func buildContainerUpdateOptionsInternal(update containertypes.ResourceUpdate) (client.ContainerUpdateOptions, models.JSON, error) {
+	var options client.ContainerUpdateOptions
+	metadata := models.JSON{}
+
+	if policy := update.RestartPolicy; policy != nil {
+		mode := container.RestartPolicyMode(cmp.Or(strings.TrimSpace(policy.Name), string(container.RestartPolicyDisabled)))
+		switch mode {
+		case container.RestartPolicyDisabled, container.RestartPolicyAlways, container.RestartPolicyUnlessStopped:
+			if policy.MaximumRetryCount != 0 {
+				return options, nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "maximumRetryCount is only valid with the on-failure restart policy")
+			}
+		case container.RestartPolicyOnFailure:
+			if policy.MaximumRetryCount < 0 {
+				return options, nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "maximumRetryCount must not be negative")
+			}
+		default:
+			return options, nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid restart policy %q", policy.Name)
+		}
+		options.RestartPolicy = &container.RestartPolicy{Name: mode, MaximumRetryCount: policy.MaximumRetryCount}
+		metadata["restartPolicy"] = string(mode)
+		if mode == container.RestartPolicyOnFailure {
+			metadata["maximumRetryCount"] = policy.MaximumRetryCount
+		}
+	}
+
+	var resources container.Resources
+	resourcesSet := false
+	for _, field := range []struct {
+		name  string
+		value *int64
+		min   int64
+		dst   *int64
+	}{
+		{"memory", update.Memory, containerMinMemoryLimit, &resources.Memory},
+		{"memorySwap", update.MemorySwap, -1, &resources.MemorySwap},
+		{"memoryReservation", update.MemoryReservation, containerMinMemoryLimit, &resources.MemoryReservation},
+		{"nanoCpus", update.NanoCPUs, 1, &resources.NanoCPUs},
+		{"cpuShares", update.CPUShares, 2, &resources.CPUShares},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if *field.value == 0 {
+			return options, nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "%s cannot be 0: an existing limit can only be removed by recreating the container", field.name)
+		}
+		if *field.value < field.min {
+			return options, nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "%s must be at least %d", field.name, field.min)
+		}
+		*field.dst = *field.value
+		metadata[field.name] = *field.value
+		resourcesSet = true
+	}
+	if resources.Memory > 0 && resources.MemorySwap > 0 && resources.MemorySwap < resources.Memory {
+		return options, nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "memorySwap must not be smaller than memory")
+	}
+	if resources.Memory > 0 && resources.MemoryReservation > resources.Memory {
+		return options, nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "memoryReservation must not be larger than memory")
+	}
+	if resourcesSet {
+		options.Resources = &resources
+	}
+
+	if options.RestartPolicy == nil && options.Resources == nil {
+		return options, nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "no container settings to update")
+	}
+	return options, metadata, nil
+}
+
+// Container batch actions accepted by BatchAction.
+const (
+	ContainerBatchActionStart   = "start"
+	ContainerBatchActionStop    = "stop"
+	ContainerBatchActionRestart = "restart"
+	ContainerBatchActionRemove  = "remove"
+)
+
+// BatchAction runs action against every container in ids with a bounded worker
+// pool. Each container is handled by the matching single-container method, so
+// per-container events are logged as usual; one aggregate event summarizes the
+// batch. Individual failures are reported in the results rather than aborting.
+// force only applies to remove.
+func (s *ContainerService) BatchAction(ctx context.Context, ids []string, action string, force bool, user models.User) (containertypes.BatchActionResponse, error) {
+	run, err := s.containerBatchActionFuncInternal(action, force)
+	if err != nil {
+		return containertypes.BatchActionResponse{}, err
+	}
+
+	ids = dedupeContainerIDsInternal(ids)
+	if len(ids) == 0 {
+		return containertypes.BatchActionResponse{}, errors.WrapIf(cerrdefs.ErrInvalidArgument, "at least one container ID is required")
+	}
+
+	results := make([]containertypes.BatchActionResult, len(ids))
+	g := new(errgroup.Group)
+	g.SetLimit(containerBatchActionConcurrency)
+	for i, id := range ids {
+		g.Go(func() error {
+			results[i] = containertypes.BatchActionResult{ID: id, Success: true}
+			if err := run(ctx, id, user); err != nil {
+				results[i].Success = false
+				results[i].Error = err.Error()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	resp := containertypes.BatchActionResponse{Action: action, Results: results}
+	for _, result := range results {
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	s.logContainerBatchEventInternal(ctx, resp, ids, user)
+	return resp, nil
+}
+
+func (s *ContainerService) containerBatchActionFuncInternal(action string, force bool) (func(context.Context, string, models.User) error, error) {
+	switch action {
+	case ContainerBatchActionStart:
+		return s.StartContainer, nil
+	case ContainerBatchActionStop:
+		return func(ctx context.Context, containerID string, user models.User) error {
+			return s.StopContainer(ctx, containerID, ContainerStopOptions{}, user)
+		}, nil
+	case ContainerBatchActionRestart:
+		return func(ctx context.Context, containerID string, user models.User) error {
+			return s.RestartContainer(ctx, containerID, ContainerStopOptions{}, user)
+		}, nil
+	case ContainerBatchActionRemove:
+		return func(ctx context.Context, containerID string, user models.User) error {
+			return s.DeleteContainer(ctx, containerID, force, false, user)
+		}, nil
+	default:
+		return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "unsupported batch action %q", action)
+	}
+}
+
+func dedupeContainerIDsInternal(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
+
+func (s *ContainerService) logContainerBatchEventInternal(ctx context.Context, resp containertypes.BatchActionResponse, ids []string, user models.User) {
+	severity := models.EventSeverityInfo
+	if resp.Failed > 0 {
+		severity = models.EventSeverityWarning
+	}
+
+	_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          models.EventTypeContainerBatch,
+		Severity:      severity,
+		Title:         fmt.Sprintf("Container batch %s: %d of %d succeeded", resp.Action, resp.Succeeded, len(ids)),
+		Description:   fmt.Sprintf("Batch %s ran against %d containers (%d failed)", resp.Action, len(ids), resp.Failed),
+		ResourceType:  new("container"),
+		UserID:        new(user.ID),
+		Username:      new(user.Username),
+		EnvironmentID: new("0"),
+		Metadata: models.JSON{
+			"action":       resp.Action,
+			"containerIds": ids,
+			"succeeded":    resp.Succeeded,
+			"failed":       resp.Failed,
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "could not log container batch action", "action", resp.Action, "error", err)
+	}
+}
+
 // CommitContainer creates an image from a container's current filesystem.
 func (s *ContainerService) CommitContainer(ctx context.Context, containerID string, req containertypes.CommitRequest, user models.User) (*containertypes.CommitResult, error) {
 	containerID = strings.TrimSpace(containerID)
@@ -585,8 +1026,6 @@ func (s *ContainerService) RedeployContainer(ctx context.Context, containerID st
 	}
 
 	containerName := strings.TrimPrefix(containerInfo.Name, "/")
-	imageName := containerInfo.Config.Image
-	wasRunning := containerInfo.State != nil && containerInfo.State.Running
 	apiVersion := libarcane.DetectDockerAPIVersion(ctx, dockerClient)
 
 	currentContainerID, currentContainerErr := cgroup.CurrentContainerID()
@@ -611,21 +1050,301 @@ func (s *ContainerService) RedeployContainer(ctx context.Context, containerID st
 		return newID, nil
 	}
 
+	return s.replaceContainerInternal(ctx, dockerClient, containerInfo, containerID, apiVersion, "redeploy", user)
+}
+
+// RecreateContainer replaces a container with a new one built from its existing
+// configuration under the same name, optionally switching the image or setting
+// environment variables. Unlike RedeployContainer it always clones the container
+// through the Docker API, so compose-managed containers keep their labels but do
+// not pick up compose file edits. When the image changes, settings inherited
+// unchanged from the old image, such as variables, command and entrypoint, are
+// dropped so the new image's defaults apply.
+func (s *ContainerService) RecreateContainer(ctx context.Context, containerID string, overrides containertypes.RecreateRequest, user models.User) (string, error) {
+	return s.recreateContainerWithConfigInternal(ctx, containerID, "recreate", user, func(ctx context.Context, dockerClient *client.Client, containerInfo container.InspectResponse) (*container.Config, error) {
+		var imageDefaults *container.Config
+		if image := strings.TrimSpace(overrides.Image); image != "" && image != containerInfo.Config.Image {
+			imageInspect, err := dockerClient.ImageInspect(ctx, containerInfo.Image)
+			if err != nil {
+				return nil, errors.WrapIf(err, "failed to inspect current image")
+			}
+			if imageCfg := imageInspect.Config; imageCfg != nil {
+				imageDefaults = &container.Config{
+					User:         imageCfg.User,
+					ExposedPorts: make(network.PortSet, len(imageCfg.ExposedPorts)),
+					Env:          imageCfg.Env,
+					Cmd:          imageCfg.Cmd,
+					Healthcheck:  imageCfg.Healthcheck,
+					WorkingDir:   imageCfg.WorkingDir,
+					Entrypoint:   imageCfg.Entrypoint,
+					Labels:       imageCfg.Labels,
+				}
+				for port := range imageCfg.ExposedPorts {
+					if parsed, err := network.ParsePort(port); err == nil {
+						imageDefaults.ExposedPorts[parsed] = struct{}{}
+					}
+				}
+			}
+		}
+		return applyRecreateOverridesInternal(*containerInfo.Config, imageDefaults, overrides), nil
+	})
+}
+
+// GetContainerEnv returns a container's environment variables in order. Values
+// of secret-looking names are masked unless reveal is set.
+func (s *ContainerService) GetContainerEnv(ctx context.Context, containerID string, reveal bool) ([]containertypes.EnvVar, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	containerJSON, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect container")
+	}
+	if containerJSON.Container.Config == nil {
+		return []containertypes.EnvVar{}, nil
+	}
+
+	return parseContainerEnvInternal(containerJSON.Container.Config.Env, reveal), nil
+}
+
+// SetContainerEnv recreates a container with env as its complete environment,
+// keeping the rest of its configuration. A masked value sent back unchanged
+// keeps the current value, so a client can edit one variable without revealing
+// the others. Returns the ID of the new container.
+func (s *ContainerService) SetContainerEnv(ctx context.Context, containerID string, env []containertypes.EnvVar, user models.User) (string, error) {
+	return s.recreateContainerWithConfigInternal(ctx, containerID, "update_env", user, func(_ context.Context, _ *client.Client, containerInfo container.InspectResponse) (*container.Config, error) {
+		cfg := *containerInfo.Config
+		merged, err := mergeContainerEnvInternal(cfg.Env, env)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Env = merged
+		return &cfg, nil
+	})
+}
+
+// recreateContainerWithConfigInternal replaces a container with one built from
+// its existing configuration after apply has built the new Config from a copy of
+// the inspected container. action labels the emitted events.
+func (s *ContainerService) recreateContainerWithConfigInternal(ctx context.Context, containerID, action string, user models.User, apply func(context.Context, *client.Client, container.InspectResponse) (*container.Config, error)) (string, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{
+			"action": action,
+			"step":   "get_client",
+		})
+		return "", errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	containerJSON, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{
+			"action": action,
+			"step":   "inspect",
+		})
+		return "", errors.WrapIf(err, "failed to inspect container")
+	}
+
+	containerInfo := containerJSON.Container
+	if containerInfo.Config == nil {
+		return "", errors.New("failed to recreate container: container config is nil")
+	}
+
+	currentContainerID, currentContainerErr := cgroup.CurrentContainerID()
+	if libupdater.ShouldDisableArcaneServerRedeploy(containerInfo.Config.Labels, containerInfo.ID, currentContainerID, currentContainerErr) {
+		return "", errors.New("arcane cannot recreate itself; use the system upgrade flow (Settings -> Updates) instead")
+	}
+
+	cfg, err := apply(ctx, dockerClient, containerInfo)
+	if err != nil {
+		return "", err
+	}
+	containerInfo.Config = cfg
+	apiVersion := libarcane.DetectDockerAPIVersion(ctx, dockerClient)
+	return s.replaceContainerInternal(ctx, dockerClient, containerInfo, containerID, apiVersion, action, user)
+}
+
+// containerEnvMask replaces the values of secret-looking variables.
+const containerEnvMask = "********"
+
+// containerEnvSecretMarkers are name fragments that mark a variable as secret.
+var containerEnvSecretMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL", "PRIVATE"}
+
+// isSecretContainerEnvKeyInternal reports whether a variable name looks like it
+// holds a secret.
+func isSecretContainerEnvKeyInternal(key string) bool {
+	upper := strings.ToUpper(key)
+	return slices.ContainsFunc(containerEnvSecretMarkers, func(marker string) bool {
+		return strings.Contains(upper, marker)
+	})
+}
+
+func parseContainerEnvInternal(entries []string, reveal bool) []containertypes.EnvVar {
+	vars := make([]containertypes.EnvVar, 0, len(entries))
+	for _, entry := range entries {
+		key, value, _ := strings.Cut(entry, "=")
+		envVar := containertypes.EnvVar{Key: key, Value: value}
+		if !reveal && value != "" && isSecretContainerEnvKeyInternal(key) {
+			envVar.Value = containerEnvMask
+			envVar.Masked = true
+		}
+		vars = append(vars, envVar)
+	}
+	return vars
+}
+
+// maskContainerEnvEntriesInternal returns KEY=value entries with the values of
+// secret-looking variables masked.
+func maskContainerEnvEntriesInternal(entries []string) []string {
+	if len(entries) == 0 {
+		return entries
+	}
+	masked := make([]string, len(entries))
+	for i, envVar := range parseContainerEnvInternal(entries, false) {
+		masked[i] = entries[i]
+		if envVar.Masked {
+			masked[i] = envVar.Key + "=" + envVar.Value
+		}
+	}
+	return masked
+}
+
+// mergeContainerEnvInternal builds the Config.Env list for env, restoring the
+// current value of any secret variable whose masked value came back unchanged.
+func mergeContainerEnvInternal(current []string, env []containertypes.EnvVar) ([]string, error) {
+	currentValues := make(map[string]string, len(current))
+	for _, entry := range current {
+		key, value, _ := strings.Cut(entry, "=")
+		currentValues[key] = value
+	}
+
+	merged := make([]string, 0, len(env))
+	seen := make(map[string]struct{}, len(env))
+	for _, envVar := range env {
+		key := strings.TrimSpace(envVar.Key)
+		if key == "" || strings.Contains(key, "=") {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid environment variable name %q", envVar.Key)
+		}
+		if _, dup := seen[key]; dup {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "duplicate environment variable %q", key)
+		}
+		seen[key] = struct{}{}
+
+		value := envVar.Value
+		if existing, ok := currentValues[key]; ok && value == containerEnvMask && isSecretContainerEnvKeyInternal(key) {
+			value = existing
+		}
+		merged = append(merged, key+"="+value)
+	}
+	return merged, nil
+}
+
+// applyRecreateOverridesInternal returns cfg with the requested image and env
+// overrides applied. On an image change, whatever cfg inherited unchanged from
+// imageDefaults, the old image's config, is dropped so the new image's defaults
+// apply: Env entries, exposed ports and labels one by one, and Cmd, Entrypoint,
+// WorkingDir, User and Healthcheck as a whole. Env overrides replace variables
+// of the same name in place and append new ones in key order.
+func applyRecreateOverridesInternal(cfg container.Config, imageDefaults *container.Config, overrides containertypes.RecreateRequest) *container.Config {
+	if image := strings.TrimSpace(overrides.Image); image != "" && image != cfg.Image {
+		cfg.Image = image
+		if imageDefaults != nil {
+			dropImageDefaultsInternal(&cfg, imageDefaults)
+		}
+	}
+	if len(overrides.Env) == 0 {
+		return &cfg
+	}
+
+	env := make([]string, 0, len(cfg.Env)+len(overrides.Env))
+	applied := make(map[string]bool, len(overrides.Env))
+	for _, entry := range cfg.Env {
+		key, _, _ := strings.Cut(entry, "=")
+		if value, ok := overrides.Env[key]; ok {
+			if !applied[key] {
+				env = append(env, key+"="+value)
+				applied[key] = true
+			}
+			continue
+		}
+		env = append(env, entry)
+	}
+	for _, key := range slices.Sorted(maps.Keys(overrides.Env)) {
+		if !applied[key] {
+			env = append(env, key+"="+overrides.Env[key])
+		}
+	}
+	cfg.Env = env
+	return &cfg
+}
+
+// dropImageDefaultsInternal clears the parts of cfg that still equal
+// imageDefaults. Slices and maps are replaced, never modified in place, since
+// cfg is a shallow copy of the inspected config.
+func dropImageDefaultsInternal(cfg *container.Config, imageDefaults *container.Config) {
+	cfg.Env = slices.DeleteFunc(slices.Clone(cfg.Env), func(entry string) bool {
+		return slices.Contains(imageDefaults.Env, entry)
+	})
+	if len(cfg.ExposedPorts) > 0 {
+		ports := maps.Clone(cfg.ExposedPorts)
+		maps.DeleteFunc(ports, func(port network.Port, _ struct{}) bool {
+			_, inherited := imageDefaults.ExposedPorts[port]
+			return inherited
+		})
+		cfg.ExposedPorts = ports
+	}
+	if len(cfg.Labels) > 0 {
+		labels := maps.Clone(cfg.Labels)
+		maps.DeleteFunc(labels, func(key, value string) bool {
+			imageValue, inherited := imageDefaults.Labels[key]
+			return inherited && imageValue == value
+		})
+		cfg.Labels = labels
+	}
+	if slices.Equal(cfg.Cmd, imageDefaults.Cmd) {
+		cfg.Cmd = nil
+	}
+	if slices.Equal(cfg.Entrypoint, imageDefaults.Entrypoint) {
+		cfg.Entrypoint = nil
+	}
+	if cfg.WorkingDir == imageDefaults.WorkingDir {
+		cfg.WorkingDir = ""
+	}
+	if cfg.User == imageDefaults.User {
+		cfg.User = ""
+	}
+	if reflect.DeepEqual(cfg.Healthcheck, imageDefaults.Healthcheck) {
+		cfg.Healthcheck = nil
+	}
+}
+
+// replaceContainerInternal swaps containerInfo for a fresh container built from the
+// same Config/HostConfig/NetworkingConfig under the same name. The image is pulled
+// first and the original is only renamed aside until the replacement starts, so any
+// failure restores the original container. containerInfo.Config is expected to
+// already carry any caller overrides. action labels the emitted events.
+func (s *ContainerService) replaceContainerInternal(ctx context.Context, dockerClient *client.Client, containerInfo container.InspectResponse, containerID, apiVersion, action string, user models.User) (string, error) {
+	containerName := strings.TrimPrefix(containerInfo.Name, "/")
+	imageName := containerInfo.Config.Image
+	wasRunning := containerInfo.State != nil && containerInfo.State.Running
+
 	metadata := models.JSON{
-		"action":        "redeploy",
+		"action":        action,
 		"containerId":   containerID,
 		"containerName": containerName,
 		"image":         imageName,
 	}
 
 	if imageName != "" {
-		if err := s.pullRedeployImageInternal(ctx, dockerClient, imageName, containerID, containerName, user); err != nil {
+		if err := s.pullRedeployImageInternal(ctx, dockerClient, imageName, containerID, containerName, action, user); err != nil {
 			return "", err
 		}
 	}
 
 	backupName := buildRedeployBackupNameInternal(containerName, containerID)
-	if err := s.prepareContainerForRedeployInternal(ctx, dockerClient, containerID, containerName, backupName, wasRunning, user); err != nil {
+	if err := s.prepareContainerForRedeployInternal(ctx, dockerClient, containerID, containerName, backupName, action, wasRunning, user); err != nil {
 		return "", err
 	}
 
@@ -643,9 +1362,9 @@ func (s *ContainerService) RedeployContainer(ctx context.Context, containerID st
 		Name:             containerName,
 	}, apiVersion)
 	if err != nil {
-		s.restoreContainerAfterRedeployFailureInternal(ctx, dockerClient, containerID, containerName, backupName, "create", wasRunning, user)
+		s.restoreContainerAfterRedeployFailureInternal(ctx, dockerClient, containerID, containerName, backupName, "create", action, wasRunning, user)
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, containerName, user.ID, user.Username, "0", err, models.JSON{
-			"action": "redeploy",
+			"action": action,
 			"step":   "create",
 			"image":  imageName,
 		})
@@ -657,13 +1376,13 @@ func (s *ContainerService) RedeployContainer(ctx context.Context, containerID st
 		if err != nil {
 			if _, removeErr := dockerClient.ContainerRemove(ctx, createResp.ID, client.ContainerRemoveOptions{Force: true}); removeErr != nil {
 				s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", createResp.ID, containerName, user.ID, user.Username, "0", removeErr, models.JSON{
-					"action": "redeploy",
+					"action": action,
 					"step":   "cleanup_failed_start",
 				})
 			}
-			s.restoreContainerAfterRedeployFailureInternal(ctx, dockerClient, containerID, containerName, backupName, "start", wasRunning, user)
+			s.restoreContainerAfterRedeployFailureInternal(ctx, dockerClient, containerID, containerName, backupName, "start", action, wasRunning, user)
 			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", createResp.ID, containerName, user.ID, user.Username, "0", err, models.JSON{
-				"action": "redeploy",
+				"action": action,
 				"step":   "start",
 				"image":  imageName,
 			})
@@ -671,7 +1390,8 @@ func (s *ContainerService) RedeployContainer(ctx context.Context, containerID st
 		}
 	}
 
-	slog.InfoContext(ctx, "container redeployed successfully",
+	slog.InfoContext(ctx, "container replaced successfully",
+		"action", action,
 		"oldContainerId", containerID,
 		"newContainerId", createResp.ID,
 		"containerName", containerName,
@@ -683,7 +1403,7 @@ func (s *ContainerService) RedeployContainer(ctx context.Context, containerID st
 		RemoveVolumes: false,
 		RemoveLinks:   false,
 	}); err != nil {
-		slog.WarnContext(ctx, "failed to remove old container after successful redeploy",
+		slog.WarnContext(ctx, "failed to remove old container after successful replacement",
 			"containerId", containerID,
 			"backupName", backupName,
 			"error", err,
@@ -722,6 +1442,8 @@ func (s *ContainerService) GetContainerDetails(ctx context.Context, id string) (
 	}
 
 	details := containertypes.NewDetails(containerInspect)
+	// Secret-looking values are only available, to admins, from GetContainerEnv.
+	details.Config.Env = maskContainerEnvEntriesInternal(details.Config.Env)
 	currentContainerID, currentContainerErr := cgroup.CurrentContainerID()
 	details.RedeployDisabled = libupdater.ShouldDisableArcaneServerRedeploy(details.Labels, details.ID, currentContainerID, currentContainerErr)
 	s.applyContainerDetailsIconInternal(ctx, &details)
@@ -744,6 +1466,12 @@ func (s *ContainerService) GetContainerNameByID(ctx context.Context, id string)
 }
 
 func (s *ContainerService) DeleteContainer(ctx context.Context, containerID string, force bool, removeVolumes bool, user models.User) error {
+	unlock, err := s.lockContainerOperationInternal(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "delete", "force": force, "removeVolumes": removeVolumes})
@@ -797,47 +1525,38 @@ func (s *ContainerService) DeleteContainer(ctx context.Context, containerID stri
 	return nil
 }
 
-func (s *ContainerService) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string, user models.User, credentials []containerregistry.Credential) (*container.InspectResponse, error) {
+// CreateContainer creates and starts a container, pulling its image first when
+// it is missing. Unless skipPortCheck is set, host ports already published by a
+// running container are rejected with common.ErrContainerHostPortInUse before
+// any pull work is done. Pull progress is sent to progressChan, which may be nil.
+func (s *ContainerService) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string, user models.User, credentials []containerregistry.Credential, skipPortCheck bool, progressChan chan<- imagetypes.PullProgress) (*container.InspectResponse, error) {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image})
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	_, err = dockerClient.ImageInspect(ctx, config.Image)
-	if err != nil {
+	if !skipPortCheck && hostConfig != nil && len(hostConfig.PortBindings) > 0 {
+		if err := checkHostPortConflictsInternal(ctx, dockerClient, hostConfig.PortBindings); err != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "port_check"})
+			return nil, err
+		}
+	}
+
+	if _, err := dockerClient.ImageInspect(ctx, config.Image); err != nil {
 		// Image not found locally, need to pull it
-		pullOptions, authErr := s.imageService.getPullOptionsWithAuth(ctx, config.Image, credentials)
-		if authErr != nil {
-			slog.WarnContext(ctx, "Failed to get registry authentication for container image; proceeding without auth",
-				"image", config.Image,
-				"error", authErr.Error())
-			pullOptions = client.ImagePullOptions{}
-		}
-
-		settings := s.settingsService.GetSettingsConfig()
-		pullCtx, pullCancel := timeouts.WithTimeout(ctx, settings.DockerImagePullTimeout.AsInt(), timeouts.DefaultDockerImagePull)
-		defer pullCancel()
-
-		reader, pullErr := dockerClient.ImagePull(pullCtx, config.Image, pullOptions)
-		if pullErr != nil {
-			if errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
-				s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", pullErr, models.JSON{"action": "create", "image": config.Image, "step": "pull_image_timeout"})
-				return nil, errors.Errorf("image pull timed out for %s (increase DOCKER_IMAGE_PULL_TIMEOUT or setting)", config.Image)
+		if pullErr := s.imageService.PullWithProgress(ctx, config.Image, credentials, progressChan); pullErr != nil {
+			step := "pull_image"
+			if errors.Is(pullErr, context.DeadlineExceeded) {
+				step = "pull_image_timeout"
 			}
-			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", pullErr, models.JSON{"action": "create", "image": config.Image, "step": "pull_image"})
-			return nil, errors.WrapIff(pullErr, "failed to pull image %s", config.Image)
+			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", pullErr, models.JSON{"action": "create", "image": config.Image, "step": step})
+			return nil, pullErr
 		}
-		defer func() { _ = reader.Close() }()
+	}
 
-		progressWriter, _ := ctx.Value(dockerutils.ProgressWriterKey{}).(io.Writer)
-		logWriter := dockerutils.NewLogLineWriter(progressWriter)
-		streamErr := dockerutils.RenderJSONMessageStream(reader, logWriter)
-		_ = logWriter.Close()
-		if streamErr != nil {
-			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", streamErr, models.JSON{"action": "create", "image": config.Image, "step": "complete_pull"})
-			return nil, errors.WrapIf(streamErr, "failed to complete image pull")
-		}
+	if s.settingsService != nil {
+		hostConfig = applyContainerCreateDefaultsInternal(hostConfig, s.settingsService.GetSettingsConfig())
 	}
 
 	resp, err := libarcane.ContainerCreateWithCompatibility(ctx, dockerClient, client.ContainerCreateOptions{
@@ -875,6 +1594,127 @@ func (s *ContainerService) CreateContainer(ctx context.Context, config *containe
 	return new(containerJSON.Container), nil
 }
 
+// applyContainerCreateDefaultsInternal fills the environment's default CPU
+// limit, memory limit and restart policy into hostConfig wherever the request
+// left them unset, so containers created without limits cannot exhaust a
+// shared host. Values the request sets, including an explicit "no" restart
+// policy, are kept. No restart policy is added to auto-removed containers,
+// which Docker rejects.
+func applyContainerCreateDefaultsInternal(hostConfig *container.HostConfig, cfg *models.Settings) *container.HostConfig {
+	if cfg == nil {
+		return hostConfig
+	}
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+
+	if cpus, err := strconv.ParseFloat(strings.TrimSpace(cfg.DefaultContainerCpuLimit.Value), 64); err == nil && cpus > 0 &&
+		hostConfig.NanoCPUs == 0 && hostConfig.CPUQuota == 0 {
+		hostConfig.NanoCPUs = int64(cpus * 1e9)
+	}
+
+	if memoryMB, err := strconv.ParseInt(strings.TrimSpace(cfg.DefaultContainerMemoryLimitMb.Value), 10, 64); err == nil && memoryMB > 0 &&
+		hostConfig.Memory == 0 {
+		hostConfig.Memory = memoryMB * 1024 * 1024
+	}
+
+	if policy := strings.TrimSpace(cfg.DefaultContainerRestartPolicy.Value); policy != "" &&
+		hostConfig.RestartPolicy.Name == "" && !hostConfig.AutoRemove {
+		hostConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyMode(policy)}
+	}
+
+	return hostConfig
+}
+
+// checkHostPortConflictsInternal lists running containers and reports the first
+// requested host port one of them already publishes.
+func checkHostPortConflictsInternal(ctx context.Context, dockerClient *client.Client, bindings network.PortMap) error {
+	running, err := dockerClient.ContainerList(ctx, client.ContainerListOptions{})
+	if err != nil {
+		return errors.WrapIf(err, "failed to list containers for port check")
+	}
+
+	summaries := make([]containertypes.Summary, 0, len(running.Items))
+	for _, item := range running.Items {
+		summaries = append(summaries, containertypes.NewSummary(item))
+	}
+	return findHostPortConflictInternal(bindings, summaries)
+}
+
+// findHostPortConflictInternal returns common.ErrContainerHostPortInUse when a
+// binding's host port (or any port of a host port range) with the same protocol
+// is already published by one of containers on an overlapping address. Bindings
+// without a host port get a random one from Docker and never conflict.
+func findHostPortConflictInternal(bindings network.PortMap, containers []containertypes.Summary) error {
+	for port, portBindings := range bindings {
+		proto := string(port.Proto())
+		for _, binding := range portBindings {
+			low, high, ok := parseHostPortRangeInternal(binding.HostPort)
+			if !ok {
+				continue
+			}
+			hostIP := ""
+			if binding.HostIP.IsValid() {
+				hostIP = binding.HostIP.String()
+			}
+			for _, existing := range containers {
+				for _, published := range existing.Ports {
+					if published.PublicPort < low || published.PublicPort > high || !strings.EqualFold(published.Type, proto) {
+						continue
+					}
+					if !hostIPsOverlapInternal(hostIP, published.IP) {
+						continue
+					}
+					return common.Classify(common.ErrContainerHostPortInUse, errors.Errorf(
+						"port %d/%s already in use by %s", published.PublicPort, proto, primaryContainerNameInternal(existing.Names, existing.ID),
+					))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseHostPortRangeInternal parses "8080" or "8080-8090"; ok is false for an
+// empty (randomly assigned) or malformed host port.
+func parseHostPortRangeInternal(hostPort string) (low, high int, ok bool) {
+	hostPort = strings.TrimSpace(hostPort)
+	if hostPort == "" {
+		return 0, 0, false
+	}
+	lowText, highText, isRange := strings.Cut(hostPort, "-")
+	low, err := strconv.Atoi(lowText)
+	if err != nil || low <= 0 {
+		return 0, 0, false
+	}
+	if !isRange {
+		return low, low, true
+	}
+	high, err = strconv.Atoi(highText)
+	if err != nil || high < low {
+		return 0, 0, false
+	}
+	return low, high, true
+}
+
+// hostIPsOverlapInternal reports whether two host bind addresses can collide;
+// an empty or unspecified address binds every interface.
+func hostIPsOverlapInternal(a, b string) bool {
+	isAny := func(ip string) bool {
+		addr, err := netip.ParseAddr(ip)
+		return ip == "" || (err == nil && addr.IsUnspecified())
+	}
+	if isAny(a) || isAny(b) {
+		return true
+	}
+	addrA, errA := netip.ParseAddr(a)
+	addrB, errB := netip.ParseAddr(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return addrA.Unmap() == addrB.Unmap()
+}
+
 func (s *ContainerService) StreamStats(ctx context.Context, containerID string, statsChan chan<- any) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
@@ -931,7 +1771,9 @@ func (s *ContainerService) StreamStats(ctx context.Context, containerID string,
 	}
 }
 
-func (s *ContainerService) StreamLogs(ctx context.Context, containerID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool) error {
+// StreamLogs sends the container's log lines to logsChan. A non-nil filter applies
+// server-side grep, line throttling and, for non-follow reads, a byte cap.
+func (s *ContainerService) StreamLogs(ctx context.Context, containerID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool, filter *dockerutils.LogFilter) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
@@ -958,7 +1800,416 @@ func (s *ContainerService) StreamLogs(ctx context.Context, containerID string, l
 	defer func() { _ = logs.Close() }()
 
 	isTTY := containerInspect.Container.Config != nil && containerInspect.Container.Config.Tty
-	return dockerutils.StreamContainerLogs(ctx, logs, logsChan, follow, isTTY)
+	return dockerutils.StreamContainerLogsFiltered(ctx, logs, logsChan, follow, isTTY, filter)
+}
+
+// GetHealthLog returns the container's health-check status with its most recent
+// results, oldest first, keeping at most limit entries when limit > 0. Containers
+// without a HEALTHCHECK report the "none" status and an empty log.
+func (s *ContainerService) GetHealthLog(ctx context.Context, containerID string, limit int) (containertypes.Health, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return containertypes.Health{}, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return containertypes.Health{}, errors.WrapIf(err, "failed to inspect container")
+	}
+
+	if inspect.Container.State == nil {
+		return containertypes.Health{Status: string(container.NoHealthcheck)}, nil
+	}
+	health := containertypes.NewHealth(inspect.Container.State.Health)
+	if health == nil {
+		return containertypes.Health{Status: string(container.NoHealthcheck)}, nil
+	}
+	if limit > 0 && len(health.Log) > limit {
+		health.Log = health.Log[len(health.Log)-limit:]
+	}
+	return *health, nil
+}
+
+const containerTopMaxPsArgsLength = 64
+
+// containerTopUnixOptions and containerTopBSDOptions are the ps option letters
+// TopContainer accepts with and without a leading dash. BSD "e", which appends
+// each process's environment to its command, is left out on purpose.
+const (
+	containerTopUnixOptions = "AefHjlLw"
+	containerTopBSDOptions  = "afjluvwx"
+)
+
+// containerTopFields are the ps output fields accepted after o, -o or -O.
+var containerTopFields = map[string]struct{}{
+	"pid": {}, "ppid": {}, "pgid": {}, "sid": {}, "tid": {}, "lwp": {}, "nlwp": {},
+	"uid": {}, "euid": {}, "ruid": {}, "user": {}, "euser": {}, "ruser": {},
+	"gid": {}, "egid": {}, "group": {}, "egroup": {},
+	"comm": {}, "args": {}, "cmd": {}, "command": {}, "ucmd": {},
+	"etime": {}, "etimes": {}, "time": {}, "cputime": {}, "stime": {}, "start": {}, "lstart": {},
+	"tty": {}, "tt": {}, "stat": {}, "state": {}, "s": {}, "wchan": {},
+	"pcpu": {}, "c": {}, "pmem": {}, "rss": {}, "rsz": {}, "vsz": {}, "sz": {},
+	"nice": {}, "ni": {}, "pri": {}, "psr": {},
+}
+
+// containerTopHeaderPattern limits a renamed column header (field=HEADER).
+var containerTopHeaderPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+// validateContainerTopPsArgsInternal accepts only the ps options in
+// containerTopUnixOptions and containerTopBSDOptions and the output fields in
+// containerTopFields, so psArgs cannot ask ps for process environments or
+// smuggle paths and shell syntax to it.
+func validateContainerTopPsArgsInternal(psArgs string) error {
+	if len(psArgs) > containerTopMaxPsArgsLength {
+		return errors.Errorf("longer than %d characters", containerTopMaxPsArgsLength)
+	}
+
+	expectFields := false
+	for _, token := range strings.Fields(psArgs) {
+		if expectFields {
+			if err := validateContainerTopFieldsInternal(token); err != nil {
+				return err
+			}
+			expectFields = false
+			continue
+		}
+
+		options, allowed := token, containerTopBSDOptions
+		if rest, ok := strings.CutPrefix(token, "-"); ok {
+			options, allowed = rest, containerTopUnixOptions
+		}
+		if options == "" {
+			return errors.Errorf("unsupported argument %q", token)
+		}
+		for i, option := range options {
+			if option == 'o' || (option == 'O' && allowed == containerTopUnixOptions) {
+				if fields := options[i+1:]; fields != "" {
+					if err := validateContainerTopFieldsInternal(fields); err != nil {
+						return err
+					}
+				} else {
+					expectFields = true
+				}
+				break
+			}
+			if !strings.ContainsRune(allowed, option) {
+				return errors.Errorf("unsupported option %q in %q", option, token)
+			}
+		}
+	}
+	if expectFields {
+		return errors.New("missing output fields")
+	}
+	return nil
+}
+
+func validateContainerTopFieldsInternal(fields string) error {
+	for field := range strings.SplitSeq(fields, ",") {
+		name, header, _ := strings.Cut(field, "=")
+		if _, ok := containerTopFields[name]; !ok {
+			return errors.Errorf("unsupported output field %q", name)
+		}
+		if !containerTopHeaderPattern.MatchString(header) {
+			return errors.Errorf("unsupported column header %q", header)
+		}
+	}
+	return nil
+}
+
+// TopContainer lists the processes running in a container, like docker top.
+// Docker runs ps on the host, so the reported PIDs are host PIDs. psArgs are
+// passed to ps and default to Docker's "-ef".
+//
+// Returns an invalid argument error when psArgs holds options or output fields
+// outside the allowlist, and common.ErrContainerNotRunning when the container
+// is stopped or restarting.
+func (s *ContainerService) TopContainer(ctx context.Context, containerID, psArgs string) (containertypes.Top, error) {
+	psArgs = strings.TrimSpace(psArgs)
+	if err := validateContainerTopPsArgsInternal(psArgs); err != nil {
+		return containertypes.Top{}, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid ps arguments %q: %s", psArgs, err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return containertypes.Top{}, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return containertypes.Top{}, errors.WrapIf(err, "failed to inspect container")
+	}
+	if inspect.Container.State == nil || !inspect.Container.State.Running || inspect.Container.State.Restarting {
+		return containertypes.Top{}, common.ErrContainerNotRunning
+	}
+
+	top, err := dockerClient.ContainerTop(ctx, containerID, client.ContainerTopOptions{Arguments: strings.Fields(psArgs)})
+	if err != nil {
+		return containertypes.Top{}, errors.WrapIf(err, "failed to list container processes")
+	}
+
+	processes := top.Processes
+	if processes == nil {
+		processes = [][]string{}
+	}
+	return containertypes.Top{Titles: top.Titles, Processes: processes}, nil
+}
+
+// GetContainerChanges reports the filesystem changes in a container's
+// writable layer relative to its image, like docker diff, split into added,
+// changed and deleted paths. Each list is sorted. Works on stopped containers
+// too, since the layer outlives the process.
+func (s *ContainerService) GetContainerChanges(ctx context.Context, containerID string) (containertypes.Changes, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return containertypes.Changes{}, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	diff, err := dockerClient.ContainerDiff(ctx, containerID, client.ContainerDiffOptions{})
+	if err != nil {
+		return containertypes.Changes{}, errors.WrapIf(err, "failed to list container changes")
+	}
+
+	changes := containertypes.Changes{Added: []string{}, Changed: []string{}, Deleted: []string{}}
+	for _, change := range diff.Changes {
+		switch change.Kind {
+		case container.ChangeAdd:
+			changes.Added = append(changes.Added, change.Path)
+		case container.ChangeModify:
+			changes.Changed = append(changes.Changed, change.Path)
+		case container.ChangeDelete:
+			changes.Deleted = append(changes.Deleted, change.Path)
+		}
+	}
+	slices.Sort(changes.Added)
+	slices.Sort(changes.Changed)
+	slices.Sort(changes.Deleted)
+	return changes, nil
+}
+
+// ContainerLogExportOptions selects the part of a container's log to export.
+// An empty Tail exports the whole log.
+type ContainerLogExportOptions struct {
+	Tail  string
+	Since string
+	Until string
+}
+
+// ExportLogs returns a reader over the container's timestamp-prefixed log with
+// stdout and stderr demultiplexed, along with the container name. The log is
+// streamed from Docker as the reader is consumed; callers must close it.
+func (s *ContainerService) ExportLogs(ctx context.Context, containerID string, opts ContainerLogExportOptions) (io.ReadCloser, string, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	containerInspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to inspect container for logs")
+	}
+
+	tail := opts.Tail
+	if tail == "" {
+		tail = "all"
+	}
+
+	logs, err := dockerClient.ContainerLogs(ctx, containerID, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       tail,
+		Since:      opts.Since,
+		Until:      opts.Until,
+		Timestamps: true,
+	})
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to get container logs")
+	}
+
+	name := strings.TrimPrefix(containerInspect.Container.Name, "/")
+	if name == "" {
+		name = containerID
+	}
+
+	if containerInspect.Container.Config != nil && containerInspect.Container.Config.Tty {
+		return logs, name, nil
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		_, copyErr := stdcopy.StdCopy(pipeWriter, pipeWriter, logs)
+		_ = logs.Close()
+		_ = pipeWriter.CloseWithError(copyErr)
+	}()
+
+	return &containerLogExportReaderInternal{PipeReader: pipeReader, source: logs}, name, nil
+}
+
+// containerLogExportReaderInternal closes the Docker log stream along with the pipe
+// so an abandoned download stops the demultiplexing goroutine promptly.
+type containerLogExportReaderInternal struct {
+	*io.PipeReader
+	source io.Closer
+}
+
+func (r *containerLogExportReaderInternal) Close() error {
+	_ = r.source.Close()
+	return r.PipeReader.Close()
+}
+
+const (
+	containerLogSearchDefaultMatches = 1000
+	containerLogSearchMaxMatches     = 10000
+	// containerLogSearchMaxLineBytes bounds the text kept for one log line;
+	// the rest of a longer line is discarded but the line is still matched on
+	// its kept prefix.
+	containerLogSearchMaxLineBytes = 64 << 10
+	containerLogSearchTimeout      = 2 * time.Minute
+)
+
+// ContainerLogSearchOptions narrows a log search. A zero MaxMatches or Timeout
+// uses the service default.
+type ContainerLogSearchOptions struct {
+	CaseInsensitive bool
+	Since           string
+	Until           string
+	MaxMatches      int
+	Timeout         time.Duration
+}
+
+// SearchLogs streams the container's full log, or the Since/Until window of
+// it, through the regular expression pattern and returns the matching lines
+// with their line numbers.
+//
+// Lines are read one at a time and the search stops at the match cap, so
+// memory stays bounded regardless of log size. The search also stops when its
+// timeout elapses, returning the matches found so far with TimedOut set;
+// cancelling ctx aborts it with ctx's error.
+//
+// Returns an invalid argument error when pattern is empty or does not compile.
+func (s *ContainerService) SearchLogs(ctx context.Context, containerID, pattern string, opts ContainerLogSearchOptions) (containertypes.LogSearchResult, error) {
+	matcher, err := compileLogSearchPatternInternal(pattern, opts.CaseInsensitive)
+	if err != nil {
+		return containertypes.LogSearchResult{}, err
+	}
+
+	maxMatches := opts.MaxMatches
+	if maxMatches <= 0 {
+		maxMatches = containerLogSearchDefaultMatches
+	}
+	maxMatches = min(maxMatches, containerLogSearchMaxMatches)
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = containerLogSearchTimeout
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// timedOut reports whether the search deadline, rather than the caller,
+	// ended the search.
+	timedOut := func() bool {
+		return ctx.Err() == nil && errors.Is(searchCtx.Err(), context.DeadlineExceeded)
+	}
+
+	logs, _, err := s.ExportLogs(searchCtx, containerID, ContainerLogExportOptions{Since: opts.Since, Until: opts.Until})
+	if err != nil {
+		if timedOut() {
+			return containertypes.LogSearchResult{Matches: []containertypes.LogSearchMatch{}, TimedOut: true}, nil
+		}
+		return containertypes.LogSearchResult{}, err
+	}
+	defer func() { _ = logs.Close() }()
+
+	result, err := searchLogLinesInternal(searchCtx, logs, matcher, maxMatches)
+	if err != nil {
+		if timedOut() {
+			result.TimedOut = true
+			return result, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return containertypes.LogSearchResult{}, ctxErr
+		}
+		return containertypes.LogSearchResult{}, err
+	}
+	return result, nil
+}
+
+func compileLogSearchPatternInternal(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "search pattern is required")
+	}
+	if caseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid search pattern: %v", err)
+	}
+	return matcher, nil
+}
+
+// searchLogLinesInternal matches timestamp-prefixed log lines read from r
+// against matcher, stopping after maxMatches matches. Truncated is set only
+// when a further match exists beyond the cap.
+func searchLogLinesInternal(ctx context.Context, r io.Reader, matcher *regexp.Regexp, maxMatches int) (containertypes.LogSearchResult, error) {
+	result := containertypes.LogSearchResult{Matches: []containertypes.LogSearchMatch{}}
+	reader := bufio.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		line, err := readLogSearchLineInternal(reader)
+		if err == nil || line != "" {
+			result.LinesScanned++
+			timestamp, text := splitLogSearchTimestampInternal(line)
+			if matcher.MatchString(text) {
+				if len(result.Matches) == maxMatches {
+					result.Truncated = true
+					return result, nil
+				}
+				result.Matches = append(result.Matches, containertypes.LogSearchMatch{
+					Line:      result.LinesScanned,
+					Timestamp: timestamp,
+					Text:      text,
+				})
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, errors.WrapIf(err, "failed to read container logs")
+		}
+	}
+}
+
+// readLogSearchLineInternal reads one line without its line ending, keeping at
+// most containerLogSearchMaxLineBytes of it.
+func readLogSearchLineInternal(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		fragment, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return string(line), err
+		}
+		if room := containerLogSearchMaxLineBytes - len(line); room > 0 {
+			line = append(line, fragment[:min(len(fragment), room)]...)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// splitLogSearchTimestampInternal separates the timestamp Docker prepends to
+// each line from the message. Lines without one are returned unchanged.
+func splitLogSearchTimestampInternal(line string) (string, string) {
+	if _, ok := dockerutils.LogLineTimestamp(line); !ok {
+		return "", line
+	}
+	timestamp, text, _ := strings.Cut(line, " ")
+	return timestamp, text
 }
 
 func (s *ContainerService) ListContainersPaginated(
@@ -967,6 +2218,7 @@ func (s *ContainerService) ListContainersPaginated(
 	includeAll bool,
 	includeInternal bool,
 	groupBy string,
+	enrichment ContainerSummaryEnrichment,
 ) (ContainerListResult, error) {
 	var dockerContainers []container.Summary
 	if includeAll {
@@ -994,7 +2246,7 @@ func (s *ContainerService) ListContainersPaginated(
 	currentContainerID, currentContainerErr := cgroup.CurrentContainerID()
 	items := s.buildContainerSummaries(dockerContainers, updateInfoMap, currentContainerID, currentContainerErr)
 
-	config := s.buildContainerPaginationConfig()
+	config := s.ContainerPaginationConfig()
 	counts := s.calculateContainerStatusCounts(items)
 
 	if groupBy == containerGroupByProject {
@@ -1010,6 +2262,7 @@ func (s *ContainerService) ListContainersPaginated(
 		metadataByProject := map[string]projects.ArcaneComposeMetadata{}
 		for gi := range groups {
 			s.applyContainerSummaryIconsInternal(ctx, groups[gi].Items, metadataByProject)
+			s.applyContainerSummaryInspectDetailsInternal(ctx, groups[gi].Items, enrichment)
 		}
 
 		return ContainerListResult{
@@ -1022,6 +2275,7 @@ func (s *ContainerService) ListContainersPaginated(
 
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 	s.applyContainerSummaryIconsInternal(ctx, result.Items, nil)
+	s.applyContainerSummaryInspectDetailsInternal(ctx, result.Items, enrichment)
 	paginationResp := pagination.BuildResponseFromFilterResult(result, params)
 
 	return ContainerListResult{
@@ -1227,6 +2481,60 @@ func (s *ContainerService) applyContainerSummaryIconsInternal(ctx context.Contex
 	}
 }
 
+// applyContainerSummaryInspectDetailsInternal inspects each summary on the current page
+// to fill in the details ContainerList does not return: GPU device requests and
+// health-check status. Inspection failures are logged and leave the summary as is.
+func (s *ContainerService) applyContainerSummaryInspectDetailsInternal(ctx context.Context, summaries []containertypes.Summary, enrichment ContainerSummaryEnrichment) {
+	enrichers := enrichment.inspectEnrichersInternal()
+	if len(summaries) == 0 || len(enrichers) == 0 {
+		return
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to connect to Docker for container details", "error", err)
+		return
+	}
+
+	forEachBoundedInternal(ctx, len(summaries), s.summaryInspectConcurrencyInternal(), func(ctx context.Context, i int) {
+		inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, summaries[i].ID, client.ContainerInspectOptions{})
+		if err != nil {
+			slog.DebugContext(ctx, "Failed to inspect container for summary details", "containerId", summaries[i].ID, "error", err)
+			return
+		}
+		for _, enrich := range enrichers {
+			enrich(&summaries[i], inspect.Container)
+		}
+	})
+}
+
+// summaryInspectConcurrencyInternal returns the configured bound on parallel
+// summary inspects, falling back to containerSummaryInspectConcurrency.
+func (s *ContainerService) summaryInspectConcurrencyInternal() int {
+	if s.dockerService != nil && s.dockerService.config != nil && s.dockerService.config.ContainerListInspectConcurrency > 0 {
+		return s.dockerService.config.ContainerListInspectConcurrency
+	}
+	return containerSummaryInspectConcurrency
+}
+
+// forEachBoundedInternal calls fn for every index in [0, n) on at most limit
+// goroutines at once and returns when every call has finished. Indexes not yet
+// started are skipped once ctx is cancelled.
+func forEachBoundedInternal(ctx context.Context, n, limit int, fn func(ctx context.Context, i int)) {
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(max(limit, 1))
+	for i := range n {
+		if groupCtx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			fn(groupCtx, i)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
 func (s *ContainerService) applyContainerSummaryIconInternal(ctx context.Context, summary *containertypes.Summary, metadataByProject map[string]projects.ArcaneComposeMetadata) {
 	if summary == nil {
 		return
@@ -1300,7 +2608,7 @@ func (s *ContainerService) resolveIconSetInternal(ctx context.Context, iconSet i
 	return iconcatalog.Resolve(iconCatalogForContextInternal(ctx), iconSet)
 }
 
-func (s *ContainerService) buildContainerPaginationConfig() pagination.Config[containertypes.Summary] {
+func (s *ContainerService) ContainerPaginationConfig() pagination.Config[containertypes.Summary] {
 	return pagination.Config[containertypes.Summary]{
 		SearchAccessors: []pagination.SearchAccessor[containertypes.Summary]{
 			func(c containertypes.Summary) (string, error) {
@@ -1313,8 +2621,12 @@ func (s *ContainerService) buildContainerPaginationConfig() pagination.Config[co
 			func(c containertypes.Summary) (string, error) { return c.State, nil },
 			func(c containertypes.Summary) (string, error) { return c.Status, nil },
 		},
+		CSVHeaders:      []string{"Name", "Image", "State", "Status"},
 		SortBindings:    s.buildContainerSortBindings(),
 		FilterAccessors: s.buildContainerFilterAccessors(),
+		RangeBindings: []pagination.RangeBinding[containertypes.Summary]{
+			{Key: "created", Time: func(c containertypes.Summary) time.Time { return time.Unix(c.Created, 0) }},
+		},
 	}
 }
 
@@ -1551,6 +2863,18 @@ func (e *ExecSession) Close(ctx context.Context) error {
 	return closeErr
 }
 
+// Resize sets the exec TTY to rows x cols. Zero dimensions are ignored since
+// Docker rejects them and they only occur while the client is still laying out.
+func (e *ExecSession) Resize(ctx context.Context, rows, cols uint) error {
+	if rows == 0 || cols == 0 {
+		return nil
+	}
+	if _, err := e.dockerClient.ExecResize(ctx, e.execID, client.ExecResizeOptions{Height: rows, Width: cols}); err != nil {
+		return errors.WrapIf(err, "failed to resize exec")
+	}
+	return nil
+}
+
 // AttachExec attaches to an exec instance and returns an ExecSession for lifecycle management.
 func (s *ContainerService) AttachExec(ctx context.Context, containerID, execID string) (*ExecSession, error) {
 	dockerClient, err := s.dockerService.GetClient(ctx)
@@ -1572,3 +2896,467 @@ func (s *ContainerService) AttachExec(ctx context.Context, containerID, execID s
 		dockerClient: dockerClient,
 	}, nil
 }
+
+const (
+	execRunDefaultTimeout = 30 * time.Second
+	execRunMaxTimeout     = 5 * time.Minute
+	// execRunMaxOutputBytes caps what RunExec keeps of each of stdout and stderr.
+	execRunMaxOutputBytes = 1 << 20
+	execRunPollInterval   = 50 * time.Millisecond
+)
+
+// RunExec runs req.Cmd in a running container without a TTY and waits for it
+// to finish, returning its exit code and its stdout and stderr captured
+// separately. Each stream keeps at most 1 MiB; the rest is read and dropped,
+// and Truncated is set.
+//
+// Returns common.ErrContainerNotRunning when the container is stopped or
+// restarting. When the timeout passes first, the wait is abandoned with a
+// context.DeadlineExceeded error; Docker cannot signal an exec, so the command
+// may keep running in the container. A canceled ctx is returned as
+// context.Canceled. Every exec that Docker created is logged as a
+// container.exec event, with the error when it did not complete.
+func (s *ContainerService) RunExec(ctx context.Context, containerID string, req containertypes.ExecRunRequest, user models.User) (containertypes.ExecRunResult, error) {
+	if len(req.Cmd) == 0 || strings.TrimSpace(req.Cmd[0]) == "" {
+		return containertypes.ExecRunResult{}, errors.WrapIf(cerrdefs.ErrInvalidArgument, "a command is required")
+	}
+	timeout := execRunDefaultTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(req.TimeoutSeconds)*time.Second, execRunMaxTimeout)
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return containertypes.ExecRunResult{}, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return containertypes.ExecRunResult{}, errors.WrapIf(err, "failed to inspect container")
+	}
+	if inspect.Container.State == nil || !inspect.Container.State.Running || inspect.Container.State.Restarting {
+		return containertypes.ExecRunResult{}, common.ErrContainerNotRunning
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	execResp, err := dockerClient.ExecCreate(runCtx, containerID, client.ExecCreateOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          req.Cmd,
+		Env:          req.Env,
+		WorkingDir:   req.WorkingDir,
+		User:         req.User,
+	})
+	if err != nil {
+		return containertypes.ExecRunResult{}, errors.WrapIf(err, "failed to create exec")
+	}
+
+	result, runErr := collectExecResultInternal(ctx, runCtx, dockerClient, execResp.ID, timeout)
+
+	// Every exec that reached Docker is audited, including failed and timed-out
+	// ones, and even when the caller has gone away.
+	containerName := strings.TrimPrefix(inspect.Container.Name, "/")
+	metadata := models.JSON{
+		"action": "exec_run",
+		"cmd":    req.Cmd,
+	}
+	if runErr != nil {
+		metadata["error"] = runErr.Error()
+		metadata["timedOut"] = errors.Is(runErr, context.DeadlineExceeded)
+	} else {
+		metadata["exitCode"] = result.ExitCode
+	}
+	if logErr := s.eventService.LogContainerEvent(context.WithoutCancel(ctx), models.EventTypeContainerExec, inspect.Container.ID, containerName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log container exec event", "container", containerID, "error", logErr.Error())
+	}
+
+	return result, runErr
+}
+
+// collectExecResultInternal attaches to the created exec execID, captures its
+// output and waits for its exit code under runCtx. When ctx, the caller's
+// context, is done first the caller's context error is returned rather than a
+// timeout.
+func collectExecResultInternal(ctx, runCtx context.Context, dockerClient *client.Client, execID string, timeout time.Duration) (containertypes.ExecRunResult, error) {
+	runCtxErr := func() error {
+		if ctx.Err() != nil {
+			return errors.WrapIf(ctx.Err(), "request ended before the command finished")
+		}
+		return errors.WrapIff(runCtx.Err(), "command did not finish within %s", timeout)
+	}
+
+	started := time.Now()
+	attach, err := dockerClient.ExecAttach(runCtx, execID, client.ExecAttachOptions{})
+	if err != nil {
+		if runCtx.Err() != nil {
+			return containertypes.ExecRunResult{}, runCtxErr()
+		}
+		return containertypes.ExecRunResult{}, errors.WrapIf(err, "failed to start exec")
+	}
+	defer attach.HijackedResponse.Close()
+
+	// The hijacked connection does not observe runCtx, so close it on timeout
+	// to unblock the copy below.
+	stopWatch := context.AfterFunc(runCtx, attach.HijackedResponse.Close)
+	defer stopWatch()
+
+	stdout := &execOutputBufferInternal{limit: execRunMaxOutputBytes}
+	stderr := &execOutputBufferInternal{limit: execRunMaxOutputBytes}
+	_, copyErr := stdcopy.StdCopy(stdout, stderr, attach.HijackedResponse.Reader)
+	if runCtx.Err() != nil {
+		return containertypes.ExecRunResult{}, runCtxErr()
+	}
+	if copyErr != nil {
+		return containertypes.ExecRunResult{}, errors.WrapIf(copyErr, "failed to read exec output")
+	}
+
+	exitCode, err := waitExecExitInternal(runCtx, dockerClient, execID)
+	if err != nil {
+		if runCtx.Err() != nil {
+			return containertypes.ExecRunResult{}, runCtxErr()
+		}
+		return containertypes.ExecRunResult{}, err
+	}
+
+	return containertypes.ExecRunResult{
+		ExitCode:   exitCode,
+		Stdout:     stdout.buf.String(),
+		Stderr:     stderr.buf.String(),
+		Truncated:  stdout.truncated || stderr.truncated,
+		DurationMs: time.Since(started).Milliseconds(),
+	}, nil
+}
+
+// waitExecExitInternal polls the exec until Docker reports it has stopped and
+// returns its exit code. The output stream can close a moment before the exec
+// is marked finished.
+func waitExecExitInternal(ctx context.Context, dockerClient *client.Client, execID string) (int, error) {
+	for {
+		inspect, err := dockerClient.ExecInspect(ctx, execID, client.ExecInspectOptions{})
+		if err != nil {
+			return 0, errors.WrapIf(err, "failed to inspect exec")
+		}
+		if !inspect.Running {
+			return inspect.ExitCode, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(execRunPollInterval):
+		}
+	}
+}
+
+// execOutputBufferInternal keeps the first limit bytes written to it and
+// discards the rest, so a chatty command cannot grow the response unbounded
+// while its stream is still drained to completion.
+type execOutputBufferInternal struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *execOutputBufferInternal) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(remaining, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// PutContainerFileContent replaces filePath inside the container with content. An
+// existing file keeps its mode and ownership; a new one is created as 0644 owned by
+// root. Parent directories must already exist. Writes larger than maxBytes fail with
+// ErrContainerFileTooLarge.
+func (s *ContainerService) PutContainerFileContent(ctx context.Context, containerID, filePath string, content []byte, maxBytes int64, user models.User) error {
+	dir, name, err := splitContainerFilePathInternal(filePath)
+	if err != nil {
+		return err
+	}
+	if maxBytes > 0 && int64(len(content)) > maxBytes {
+		return errors.WrapIff(ErrContainerFileTooLarge, "limit is %d bytes", maxBytes)
+	}
+	return s.copyFileToContainerInternal(ctx, containerID, dir, name, content, user)
+}
+
+// UploadContainerFile copies an uploaded file named filename into destDir inside the
+// container. Uploads larger than maxBytes fail with ErrContainerFileTooLarge.
+func (s *ContainerService) UploadContainerFile(ctx context.Context, containerID, destDir, filename string, content io.Reader, maxBytes int64, user models.User) error {
+	dir, err := utils.SanitizeBrowsePath(destDir)
+	if err != nil {
+		return errors.WrapIf(cerrdefs.ErrInvalidArgument, err.Error())
+	}
+	name := strings.TrimSpace(filename)
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+		return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid file name %q", filename)
+	}
+
+	reader := content
+	if maxBytes > 0 {
+		reader = io.LimitReader(content, maxBytes+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return errors.WrapIf(err, "failed to read upload")
+	}
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return errors.WrapIff(ErrContainerFileTooLarge, "limit is %d bytes", maxBytes)
+	}
+	return s.copyFileToContainerInternal(ctx, containerID, dir, name, data, user)
+}
+
+// ListContainerDirectory lists dirPath inside a container with directories first,
+// then files, each sorted by name. opts filters by a name glob and pages the sorted
+// result; the total number of matching entries is returned alongside the page.
+//
+// A running container is listed with find and stat over exec. Stopped containers,
+// images without those tools and listings too large for the exec output cap fall
+// back to reading the directory archive.
+func (s *ContainerService) ListContainerDirectory(ctx context.Context, containerID, dirPath string, opts volumetypes.ListDirectoryOptions) ([]volumetypes.FileEntry, int, error) {
+	if _, err := path.Match(opts.Filter, ""); err != nil {
+		return nil, 0, errors.WrapIf(cerrdefs.ErrInvalidArgument, "invalid filter pattern")
+	}
+	dir, err := utils.SanitizeBrowsePath(dirPath)
+	if err != nil {
+		return nil, 0, errors.WrapIf(cerrdefs.ErrInvalidArgument, err.Error())
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, 0, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, dockerClient, containerID, client.ContainerInspectOptions{})
+	if err != nil {
+		return nil, 0, errors.WrapIf(err, "failed to inspect container")
+	}
+
+	var entries []volumetypes.FileEntry
+	if inspect.Container.State != nil && inspect.Container.State.Running && !inspect.Container.State.Restarting {
+		entries, err = listContainerDirectoryExecInternal(ctx, dockerClient, containerID, dir)
+		if err != nil {
+			slog.DebugContext(ctx, "container directory exec listing failed, reading archive", "containerID", containerID, "path", dir, "error", err)
+			entries = nil
+		}
+	}
+	if entries == nil {
+		entries, err = listContainerDirectoryArchiveInternal(ctx, dockerClient, containerID, dir, opts.Filter)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	page, total := pageFileEntriesInternal(entries, opts)
+
+	// The stat listing has no link targets; resolve them for the returned page only.
+	for i := range page {
+		if page[i].IsSymlink && page[i].LinkTarget == "" {
+			if result, err := runContainerCommandInternal(ctx, dockerClient, containerID, []string{"readlink", page[i].Path}); err == nil && result.ExitCode == 0 {
+				page[i].LinkTarget = strings.TrimSpace(result.Stdout)
+			}
+		}
+	}
+
+	return page, total, nil
+}
+
+// listContainerDirectoryExecInternal lists dir with find and stat inside the running
+// container. It fails when the command is missing or its output was truncated, so the
+// caller can fall back to the archive.
+func listContainerDirectoryExecInternal(ctx context.Context, dockerClient *client.Client, containerID, dir string) ([]volumetypes.FileEntry, error) {
+	result, err := runContainerCommandInternal(ctx, dockerClient, containerID, statListingCommandInternal(dir))
+	if err != nil {
+		return nil, err
+	}
+	if result.ExitCode != 0 {
+		return nil, errors.Errorf("listing exited with code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	if result.Truncated {
+		return nil, errors.New("listing output was truncated")
+	}
+	return parseStatListingInternal(result.Stdout, ""), nil
+}
+
+// runContainerCommandInternal runs cmd in a running container and captures its output
+// under the RunExec limits. Unlike RunExec it is not audited; it backs read-only
+// browsing commands chosen by the server, not by the caller.
+func runContainerCommandInternal(ctx context.Context, dockerClient *client.Client, containerID string, cmd []string) (containertypes.ExecRunResult, error) {
+	runCtx, cancel := context.WithTimeout(ctx, execRunDefaultTimeout)
+	defer cancel()
+
+	execResp, err := dockerClient.ExecCreate(runCtx, containerID, client.ExecCreateOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return containertypes.ExecRunResult{}, errors.WrapIf(err, "failed to create exec")
+	}
+	return collectExecResultInternal(ctx, runCtx, dockerClient, execResp.ID, execRunDefaultTimeout)
+}
+
+// listContainerDirectoryArchiveInternal lists dir from the archive Docker builds of it,
+// which works for stopped containers too. Docker archives the whole subtree, so the
+// stream is read to its end to count every match; file bodies and deeper entries are
+// skipped without being kept, and only names matching filter are kept.
+func listContainerDirectoryArchiveInternal(ctx context.Context, dockerClient *client.Client, containerID, dir, filter string) ([]volumetypes.FileEntry, error) {
+	copyResult, err := dockerClient.CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{SourcePath: dir})
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to read %s", dir)
+	}
+	defer func() { _ = copyResult.Content.Close() }()
+
+	tr := tar.NewReader(copyResult.Content)
+	root, err := tr.Next()
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to read %s", dir)
+	}
+	if root.Typeflag != tar.TypeDir {
+		return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "%s is not a directory", dir)
+	}
+	rootName := containerArchiveNameInternal(root.Name)
+
+	entries := make([]volumetypes.FileEntry, 0)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to read %s", dir)
+		}
+
+		rel, ok := containerArchiveNameInternal(header.Name), true
+		if rootName != "" {
+			rel, ok = strings.CutPrefix(rel, rootName+"/")
+		}
+		if !ok || rel == "" || strings.Contains(rel, "/") {
+			continue
+		}
+		if filter != "" {
+			if matched, _ := path.Match(filter, rel); !matched {
+				continue
+			}
+		}
+		entries = append(entries, containerArchiveFileEntryInternal(header, rel, dir))
+	}
+	return entries, nil
+}
+
+// containerArchiveNameInternal normalizes an archive entry name so the root of an
+// archive of "/" ("/", "." or "./") compares as empty.
+func containerArchiveNameInternal(name string) string {
+	name = strings.Trim(strings.TrimPrefix(name, "./"), "/")
+	if name == "." {
+		return ""
+	}
+	return name
+}
+
+// containerArchiveFileEntryInternal converts an archive header for name, a direct
+// child of dir, into a file entry shaped like the stat listing.
+func containerArchiveFileEntryInternal(header *tar.Header, name, dir string) volumetypes.FileEntry {
+	mode := header.FileInfo().Mode().String()
+	if header.Typeflag == tar.TypeSymlink {
+		// fs.FileMode marks symlinks with "L"; stat and ls use "l".
+		mode = "l" + strings.TrimPrefix(mode, "L")
+	}
+
+	entry := volumetypes.FileEntry{
+		Name:        name,
+		Path:        path.Join(dir, name),
+		IsDirectory: header.Typeflag == tar.TypeDir,
+		Size:        header.Size,
+		ModTime:     header.ModTime,
+		Mode:        mode,
+		IsSymlink:   header.Typeflag == tar.TypeSymlink,
+		LinkTarget:  header.Linkname,
+	}
+	applyFileOwnershipInternal(&entry, []string{strconv.Itoa(header.Uid), strconv.Itoa(header.Gid), header.Uname, header.Gname})
+	return entry
+}
+
+// splitContainerFilePathInternal cleans filePath and splits it into its directory and
+// base name, rejecting the root and any path that escapes "/" after cleaning.
+func splitContainerFilePathInternal(filePath string) (string, string, error) {
+	cleaned, err := utils.SanitizeBrowsePath(filePath)
+	if err != nil {
+		return "", "", errors.WrapIf(cerrdefs.ErrInvalidArgument, err.Error())
+	}
+	if cleaned == "/" {
+		return "", "", errors.WrapIf(cerrdefs.ErrInvalidArgument, "a file path is required")
+	}
+	return path.Dir(cleaned), path.Base(cleaned), nil
+}
+
+// existingContainerFileHeaderInternal returns the archive header Docker reports for
+// the regular file at targetPath, which carries the mode and ownership a rewrite
+// has to keep. It returns nil when there is no such file. Only the header is read;
+// the file content is discarded with the closed stream.
+func existingContainerFileHeaderInternal(ctx context.Context, dockerClient *client.Client, containerID, targetPath string) *tar.Header {
+	copyResult, err := dockerClient.CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{SourcePath: targetPath})
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = copyResult.Content.Close() }()
+
+	header, err := tar.NewReader(copyResult.Content).Next()
+	if err != nil || header.Typeflag != tar.TypeReg {
+		return nil
+	}
+	return header
+}
+
+func (s *ContainerService) copyFileToContainerInternal(ctx context.Context, containerID, dir, name string, content []byte, user models.User) error {
+	containerID = strings.TrimSpace(containerID)
+	if containerID == "" {
+		return errors.WrapIf(cerrdefs.ErrInvalidArgument, "container ID is required")
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	targetPath := path.Join(dir, name)
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if existing := existingContainerFileHeaderInternal(ctx, dockerClient, containerID, targetPath); existing != nil {
+		header.Mode = existing.Mode
+		header.Uid, header.Gid = existing.Uid, existing.Gid
+		header.Uname, header.Gname = existing.Uname, existing.Gname
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(header); err != nil {
+		return errors.WrapIf(err, "failed to build archive")
+	}
+	if _, err := tw.Write(content); err != nil {
+		_ = tw.Close()
+		return errors.WrapIf(err, "failed to build archive")
+	}
+	if err := tw.Close(); err != nil {
+		return errors.WrapIf(err, "failed to build archive")
+	}
+
+	if _, err := dockerClient.CopyToContainer(ctx, containerID, client.CopyToContainerOptions{
+		DestinationPath: dir,
+		Content:         &buf,
+	}); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "write_file", "path": targetPath})
+		return errors.WrapIff(err, "failed to write %s", targetPath)
+	}
+
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUpdate, containerID, "", user.ID, user.Username, "0", models.JSON{"action": "write_file", "path": targetPath, "size": len(content)}); logErr != nil {
+		slog.WarnContext(ctx, "could not log container file write event", "containerID", containerID, "error", logErr.Error())
+	}
+	return nil
+}
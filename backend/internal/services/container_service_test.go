@@ -1,18 +1,31 @@
 package services
 
 import (
+	"archive/tar"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
 	containertypes "github.com/getarcaneapp/arcane/types/v2/container"
 	imagetypes "github.com/getarcaneapp/arcane/types/v2/image"
+	volumetypes "github.com/getarcaneapp/arcane/types/v2/volume"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
 	"github.com/stretchr/testify/require"
@@ -317,3 +330,894 @@ func newGroupedContainerSummary(name string, project string) containertypes.Summ
 		State:  "running",
 	}
 }
+
+func TestContainerServiceApplyContainerSummaryInspectDetailsGPUsInternal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hostConfig := map[string]any{}
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/gpu-1/json":
+			hostConfig["DeviceRequests"] = []map[string]any{
+				{"Driver": "nvidia", "Count": 0, "DeviceIDs": []string{"GPU-a", "GPU-b"}, "Capabilities": [][]string{{"gpu", "compute"}}},
+			}
+		case "/containers/gpu-all/json":
+			hostConfig["DeviceRequests"] = []map[string]any{
+				{"Driver": "", "Count": -1, "Capabilities": [][]string{{"gpu"}}},
+			}
+		case "/containers/plain/json":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(dockerTestPathInternal(r.URL.Path), "/containers/"), "/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Id":         id,
+			"Config":     map[string]any{},
+			"HostConfig": hostConfig,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+	summaries := []containertypes.Summary{{ID: "gpu-1"}, {ID: "gpu-all"}, {ID: "plain"}, {ID: "missing"}}
+
+	svc.applyContainerSummaryInspectDetailsInternal(context.Background(), summaries, ContainerSummaryEnrichment{GPUs: true})
+
+	require.NotNil(t, summaries[0].GPUs)
+	require.Equal(t, 2, summaries[0].GPUs.Count)
+	require.Equal(t, []string{"GPU-a", "GPU-b"}, summaries[0].GPUs.DeviceIDs)
+	require.Equal(t, []string{"compute", "gpu"}, summaries[0].GPUs.Capabilities)
+	require.Equal(t, []string{"nvidia"}, summaries[0].GPUs.Drivers)
+
+	require.NotNil(t, summaries[1].GPUs)
+	require.Equal(t, -1, summaries[1].GPUs.Count)
+
+	require.Nil(t, summaries[2].GPUs)
+	require.Nil(t, summaries[3].GPUs)
+}
+
+func TestContainerServicePutContainerFileContentWritesSingleFileTarInternal(t *testing.T) {
+	db := setupProjectTestDB(t)
+	var gotPath, gotName, gotContent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || dockerTestPathInternal(r.URL.Path) != "/containers/container-1/archive" {
+			http.NotFound(w, r)
+			return
+		}
+		gotPath = r.URL.Query().Get("path")
+		tr := tar.NewReader(r.Body)
+		hdr, err := tr.Next()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(tr)
+		gotName, gotContent = hdr.Name, string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewContainerService(
+		context.Background(),
+		db,
+		NewEventService(db, nil, nil),
+		&DockerClientService{client: newTestDockerClient(t, server)},
+		nil,
+		nil,
+		nil,
+	)
+
+	err := svc.PutContainerFileContent(context.Background(), "container-1", "/etc/app/../app/config.yml", []byte("key: value\n"), 1024, systemUser)
+	require.NoError(t, err)
+	require.Equal(t, "/etc/app", gotPath)
+	require.Equal(t, "config.yml", gotName)
+	require.Equal(t, "key: value\n", gotContent)
+
+	err = svc.PutContainerFileContent(context.Background(), "container-1", "/etc/app/config.yml", []byte("too large"), 4, systemUser)
+	require.ErrorIs(t, err, ErrContainerFileTooLarge)
+
+	err = svc.PutContainerFileContent(context.Background(), "container-1", "/", []byte("x"), 1024, systemUser)
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+
+	err = svc.UploadContainerFile(context.Background(), "container-1", "/tmp", "../evil", strings.NewReader("x"), 1024, systemUser)
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+}
+
+func TestContainerServicePutContainerFileContentKeepsExistingModeAndOwnerInternal(t *testing.T) {
+	db := setupProjectTestDB(t)
+	var written *tar.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dockerTestPathInternal(r.URL.Path) != "/containers/container-1/archive" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("path") != "/app/run.sh" {
+				http.NotFound(w, r)
+				return
+			}
+			stat, err := json.Marshal(container.PathStat{Name: "run.sh", Size: 3, Mode: 0o750})
+			require.NoError(t, err)
+			w.Header().Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(stat))
+			tw := tar.NewWriter(w)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: "run.sh", Typeflag: tar.TypeReg, Mode: 0o750, Uid: 1000, Gid: 1001, Size: 3}))
+			_, _ = tw.Write([]byte("old"))
+			_ = tw.Close()
+		case http.MethodPut:
+			hdr, err := tar.NewReader(r.Body).Next()
+			require.NoError(t, err)
+			written = hdr
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewContainerService(
+		context.Background(),
+		db,
+		NewEventService(db, nil, nil),
+		&DockerClientService{client: newTestDockerClient(t, server)},
+		nil,
+		nil,
+		nil,
+	)
+
+	require.NoError(t, svc.PutContainerFileContent(context.Background(), "container-1", "/app/run.sh", []byte("#!/bin/sh\n"), 1024, systemUser))
+	require.NotNil(t, written)
+	require.Equal(t, int64(0o750), written.Mode)
+	require.Equal(t, 1000, written.Uid)
+	require.Equal(t, 1001, written.Gid)
+
+	// A new file falls back to 0644 owned by root.
+	require.NoError(t, svc.PutContainerFileContent(context.Background(), "container-1", "/app/new.sh", []byte("x"), 1024, systemUser))
+	require.Equal(t, int64(0o644), written.Mode)
+	require.Zero(t, written.Uid)
+	require.Zero(t, written.Gid)
+}
+
+func TestContainerServiceListContainerDirectoryReadsArchiveOfStoppedContainerInternal(t *testing.T) {
+	db := setupProjectTestDB(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/stopped/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": "stopped", "Name": "/stopped", "State": map[string]any{"Running": false}})
+		case "/containers/stopped/archive":
+			if r.URL.Query().Get("path") != "/etc" {
+				http.NotFound(w, r)
+				return
+			}
+			stat, err := json.Marshal(container.PathStat{Name: "etc", Mode: 0o755})
+			require.NoError(t, err)
+			w.Header().Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(stat))
+			tw := tar.NewWriter(w)
+			for _, hdr := range []*tar.Header{
+				{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0o755},
+				{Name: "etc/app.conf", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1, Uid: 1000, Uname: "app"},
+				{Name: "etc/nginx/", Typeflag: tar.TypeDir, Mode: 0o755},
+				{Name: "etc/nginx/nginx.conf", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1},
+				{Name: "etc/hosts", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1},
+				{Name: "etc/localtime", Typeflag: tar.TypeSymlink, Mode: 0o777, Linkname: "/usr/share/zoneinfo/UTC"},
+			} {
+				require.NoError(t, tw.WriteHeader(hdr))
+				if hdr.Size > 0 {
+					_, _ = tw.Write([]byte("x"))
+				}
+			}
+			_ = tw.Close()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewContainerService(
+		context.Background(),
+		db,
+		NewEventService(db, nil, nil),
+		&DockerClientService{client: newTestDockerClient(t, server)},
+		nil,
+		nil,
+		nil,
+	)
+
+	entries, total, err := svc.ListContainerDirectory(context.Background(), "stopped", "/etc", volumetypes.ListDirectoryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	require.Equal(t, []string{"nginx", "app.conf", "hosts", "localtime"}, names)
+	require.Equal(t, "/etc/app.conf", entries[1].Path)
+	require.Equal(t, "app", entries[1].Owner)
+	require.True(t, entries[3].IsSymlink)
+	require.Equal(t, "/usr/share/zoneinfo/UTC", entries[3].LinkTarget)
+	require.True(t, strings.HasPrefix(entries[3].Mode, "l"))
+
+	entries, total, err = svc.ListContainerDirectory(context.Background(), "stopped", "/etc", volumetypes.ListDirectoryOptions{Filter: "*.conf", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Len(t, entries, 1)
+	require.Equal(t, "app.conf", entries[0].Name)
+
+	_, _, err = svc.ListContainerDirectory(context.Background(), "stopped", "/etc", volumetypes.ListDirectoryOptions{Filter: "["})
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+}
+
+func TestContainerServiceExportLogsDemultiplexesTimestampedLogInternal(t *testing.T) {
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"Id":     "web",
+				"Name":   "/nginx",
+				"Config": map[string]any{"Tty": false},
+			})
+		case "/containers/web/logs":
+			gotQuery = r.URL.Query()
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+			for _, frame := range []struct {
+				stream  byte
+				payload string
+			}{
+				{1, "2026-01-01T00:00:00Z GET /\n"},
+				{2, "2026-01-01T00:00:01Z upstream timed out\n"},
+			} {
+				header := make([]byte, 8)
+				header[0] = frame.stream
+				binary.BigEndian.PutUint32(header[4:], uint32(len(frame.payload)))
+				_, _ = w.Write(header)
+				_, _ = w.Write([]byte(frame.payload))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	logs, name, err := svc.ExportLogs(context.Background(), "web", ContainerLogExportOptions{Since: "1700000000"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = logs.Close() })
+
+	body, err := io.ReadAll(logs)
+	require.NoError(t, err)
+	require.Equal(t, "nginx", name)
+	require.Equal(t, "2026-01-01T00:00:00Z GET /\n2026-01-01T00:00:01Z upstream timed out\n", string(body))
+	require.Equal(t, "all", gotQuery.Get("tail"))
+	require.Equal(t, "1700000000", gotQuery.Get("since"))
+	require.Equal(t, "1", gotQuery.Get("timestamps"))
+}
+
+func TestContainerServiceTopContainerInternal(t *testing.T) {
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": "web", "Name": "/web", "State": map[string]any{"Running": true}})
+		case "/containers/stopped/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": "stopped", "Name": "/stopped", "State": map[string]any{"Running": false}})
+		case "/containers/web/top":
+			gotQuery = r.URL.Query()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"Titles":    []string{"PID", "COMMAND"},
+				"Processes": [][]string{{"1", "nginx: master process"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	top, err := svc.TopContainer(context.Background(), "web", " aux ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"PID", "COMMAND"}, top.Titles)
+	require.Equal(t, [][]string{{"1", "nginx: master process"}}, top.Processes)
+	require.Equal(t, "aux", gotQuery.Get("ps_args"))
+
+	_, err = svc.TopContainer(context.Background(), "stopped", "")
+	require.ErrorIs(t, err, common.ErrContainerNotRunning)
+
+	_, err = svc.TopContainer(context.Background(), "web", "-ef; rm -rf /")
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+
+	_, err = svc.TopContainer(context.Background(), "web", "auxe")
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+}
+
+func TestValidateContainerTopPsArgsInternal(t *testing.T) {
+	for _, psArgs := range []string{"", "aux", "-ef", "-eo pid,comm", "axo pid=PID,user,args", "-opid,etime"} {
+		require.NoError(t, validateContainerTopPsArgsInternal(psArgs), psArgs)
+	}
+	for _, psArgs := range []string{"auxe", "e", "-o", "-o environ", "-eo pid,args e", "--help", "-o pid=A/B"} {
+		require.Error(t, validateContainerTopPsArgsInternal(psArgs), psArgs)
+	}
+}
+
+func TestContainerServiceGetContainerChangesInternal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/changes":
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				{"Path": "/var/log/nginx/access.log", "Kind": 1},
+				{"Path": "/etc/nginx", "Kind": 0},
+				{"Path": "/tmp/a", "Kind": 1},
+				{"Path": "/usr/share/doc", "Kind": 2},
+			})
+		case "/containers/clean/changes":
+			_, _ = w.Write([]byte("null"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	changes, err := svc.GetContainerChanges(context.Background(), "web")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/tmp/a", "/var/log/nginx/access.log"}, changes.Added)
+	require.Equal(t, []string{"/etc/nginx"}, changes.Changed)
+	require.Equal(t, []string{"/usr/share/doc"}, changes.Deleted)
+
+	changes, err = svc.GetContainerChanges(context.Background(), "clean")
+	require.NoError(t, err)
+	require.Empty(t, changes.Added)
+	require.NotNil(t, changes.Added)
+}
+
+func TestContainerServiceRunExecRejectsInvalidRequestsInternal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/stopped/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": "stopped", "Name": "/stopped", "State": map[string]any{"Running": false}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	_, err := svc.RunExec(context.Background(), "stopped", containertypes.ExecRunRequest{}, models.User{})
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+
+	_, err = svc.RunExec(context.Background(), "stopped", containertypes.ExecRunRequest{Cmd: []string{"true"}}, models.User{})
+	require.ErrorIs(t, err, common.ErrContainerNotRunning)
+}
+
+func TestContainerServiceRunExecAuditsFailedExecInternal(t *testing.T) {
+	db := setupProjectTestDB(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": "web-id", "Name": "/web", "State": map[string]any{"Running": true}})
+		case "/containers/web/exec":
+			_ = json.NewEncoder(w).Encode(map[string]string{"Id": "exec-1"})
+		case "/exec/exec-1/start":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "exec start failed"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewContainerService(
+		context.Background(),
+		db,
+		NewEventService(db, nil, nil),
+		&DockerClientService{client: newTestDockerClient(t, server)},
+		nil,
+		nil,
+		nil,
+	)
+
+	_, err := svc.RunExec(context.Background(), "web", containertypes.ExecRunRequest{Cmd: []string{"true"}}, systemUser)
+	require.Error(t, err)
+
+	var event models.Event
+	require.NoError(t, db.WithContext(context.Background()).Where("type = ?", models.EventTypeContainerExec).First(&event).Error)
+	require.Equal(t, "web-id", *event.ResourceID)
+	require.Contains(t, event.Metadata["error"], "failed to start exec")
+	require.Equal(t, false, event.Metadata["timedOut"])
+}
+
+func TestExecOutputBufferInternalTruncatesAtLimit(t *testing.T) {
+	buf := &execOutputBufferInternal{limit: 5}
+
+	n, err := buf.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.False(t, buf.truncated)
+
+	n, err = buf.Write([]byte("defgh"))
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.True(t, buf.truncated)
+
+	_, err = buf.Write([]byte("more"))
+	require.NoError(t, err)
+	require.Equal(t, "abcde", buf.buf.String())
+}
+
+func TestApplyContainerCreateDefaultsKeepsExplicitValuesInternal(t *testing.T) {
+	cfg := &models.Settings{
+		DefaultContainerCpuLimit:      models.SettingVariable{Value: "1.5"},
+		DefaultContainerMemoryLimitMb: models.SettingVariable{Value: "512"},
+		DefaultContainerRestartPolicy: models.SettingVariable{Value: "unless-stopped"},
+	}
+
+	hostConfig := applyContainerCreateDefaultsInternal(nil, cfg)
+	require.Equal(t, int64(1_500_000_000), hostConfig.NanoCPUs)
+	require.Equal(t, int64(512*1024*1024), hostConfig.Memory)
+	require.Equal(t, container.RestartPolicyUnlessStopped, hostConfig.RestartPolicy.Name)
+
+	hostConfig = applyContainerCreateDefaultsInternal(&container.HostConfig{
+		Resources:     container.Resources{CPUQuota: 50000, Memory: 64 * 1024 * 1024},
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyDisabled},
+	}, cfg)
+	require.Zero(t, hostConfig.NanoCPUs)
+	require.Equal(t, int64(64*1024*1024), hostConfig.Memory)
+	require.Equal(t, container.RestartPolicyDisabled, hostConfig.RestartPolicy.Name)
+
+	hostConfig = applyContainerCreateDefaultsInternal(&container.HostConfig{AutoRemove: true}, cfg)
+	require.Empty(t, hostConfig.RestartPolicy.Name)
+
+	hostConfig = applyContainerCreateDefaultsInternal(&container.HostConfig{}, &models.Settings{
+		DefaultContainerCpuLimit:      models.SettingVariable{Value: "0"},
+		DefaultContainerMemoryLimitMb: models.SettingVariable{Value: ""},
+	})
+	require.Equal(t, &container.HostConfig{}, hostConfig)
+}
+
+func TestContainerServiceSearchLogsMatchesFullLogUpToCapInternal(t *testing.T) {
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"Id":     "web",
+				"Name":   "/nginx",
+				"Config": map[string]any{"Tty": true},
+			})
+		case "/containers/web/logs":
+			gotQuery = r.URL.Query()
+			_, _ = io.WriteString(w, "2026-01-01T00:00:00Z GET /\n"+
+				"2026-01-01T00:00:01Z ERROR upstream timed out\n"+
+				"2026-01-01T00:00:02Z GET /health\n"+
+				"2026-01-01T00:00:03Z error: connection reset\n"+
+				"2026-01-01T00:00:04Z Error again")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	result, err := svc.SearchLogs(context.Background(), "web", "^error", ContainerLogSearchOptions{CaseInsensitive: true, Until: "1800000000"})
+	require.NoError(t, err)
+	require.Equal(t, []containertypes.LogSearchMatch{
+		{Line: 2, Timestamp: "2026-01-01T00:00:01Z", Text: "ERROR upstream timed out"},
+		{Line: 4, Timestamp: "2026-01-01T00:00:03Z", Text: "error: connection reset"},
+		{Line: 5, Timestamp: "2026-01-01T00:00:04Z", Text: "Error again"},
+	}, result.Matches)
+	require.Equal(t, 5, result.LinesScanned)
+	require.False(t, result.Truncated)
+	require.False(t, result.TimedOut)
+	require.Equal(t, "all", gotQuery.Get("tail"))
+	require.Equal(t, "1800000000", gotQuery.Get("until"))
+
+	result, err = svc.SearchLogs(context.Background(), "web", "GET", ContainerLogSearchOptions{MaxMatches: 1})
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+	require.True(t, result.Truncated)
+	require.Equal(t, 3, result.LinesScanned)
+
+	_, err = svc.SearchLogs(context.Background(), "web", "(", ContainerLogSearchOptions{})
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+}
+
+func TestContainerServiceSearchLogsHonoursCancellationInternal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := searchLogLinesInternal(ctx, strings.NewReader("2026-01-01T00:00:00Z line\n"), regexp.MustCompile("line"), 10)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestContainerServiceBatchActionContinuesPastFailuresInternal(t *testing.T) {
+	db := setupProjectTestDB(t)
+	// Workers log events concurrently; keep them on the one in-memory connection.
+	sqlDB, err := db.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/ok-1/start", "/containers/ok-2/start":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"No such container"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewContainerService(
+		context.Background(),
+		db,
+		NewEventService(db, nil, nil),
+		&DockerClientService{client: newTestDockerClient(t, server)},
+		nil,
+		nil,
+		nil,
+	)
+
+	resp, err := svc.BatchAction(context.Background(), []string{"ok-1", "missing", " ok-2 ", "ok-1", ""}, ContainerBatchActionStart, false, systemUser)
+	require.NoError(t, err)
+	require.Equal(t, ContainerBatchActionStart, resp.Action)
+	require.Equal(t, 2, resp.Succeeded)
+	require.Equal(t, 1, resp.Failed)
+	require.Len(t, resp.Results, 3)
+	require.Equal(t, containertypes.BatchActionResult{ID: "ok-1", Success: true}, resp.Results[0])
+	require.Equal(t, "missing", resp.Results[1].ID)
+	require.False(t, resp.Results[1].Success)
+	require.NotEmpty(t, resp.Results[1].Error)
+	require.Equal(t, containertypes.BatchActionResult{ID: "ok-2", Success: true}, resp.Results[2])
+
+	var batchEvents int64
+	require.NoError(t, db.Model(&models.Event{}).Where("type = ?", models.EventTypeContainerBatch).Count(&batchEvents).Error)
+	require.Equal(t, int64(1), batchEvents)
+
+	_, err = svc.BatchAction(context.Background(), []string{"ok-1"}, "explode", false, systemUser)
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+
+	_, err = svc.BatchAction(context.Background(), []string{" "}, ContainerBatchActionStart, false, systemUser)
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+}
+
+func TestContainerServiceLockContainerOperationInternal(t *testing.T) {
+	svc := &ContainerService{}
+
+	unlock, err := svc.lockContainerOperationInternal(context.Background(), "web")
+	require.NoError(t, err)
+
+	// A different container is not blocked by the held lock.
+	unlockOther, err := svc.lockContainerOperationInternal(context.Background(), "db")
+	require.NoError(t, err)
+	unlockOther()
+
+	_, err = svc.lockContainerOperationInternal(context.Background(), "web")
+	require.ErrorIs(t, err, common.ErrContainerOperationInProgress)
+	require.ErrorIs(t, err, common.ErrConflict)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = svc.lockContainerOperationInternal(ctx, "web")
+	require.ErrorIs(t, err, context.Canceled)
+
+	unlock()
+	unlock, err = svc.lockContainerOperationInternal(context.Background(), "web")
+	require.NoError(t, err)
+	unlock()
+
+	// Released locks don't accumulate, including ones that timed out waiting.
+	require.Empty(t, svc.operationLocks)
+}
+
+func TestContainerServiceLockContainerOperationInternalUsesCanonicalID(t *testing.T) {
+	const fullID = "4f1c2a9be0d3c7d1a8e6b5f4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/json", "/containers/4f1c2a9be0d3/json", "/containers/" + fullID + "/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": fullID, "Name": "/web"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	unlock, err := svc.lockContainerOperationInternal(context.Background(), "web")
+	require.NoError(t, err)
+
+	_, err = svc.lockContainerOperationInternal(context.Background(), "4f1c2a9be0d3")
+	require.ErrorIs(t, err, common.ErrContainerOperationInProgress)
+
+	unlock()
+	require.Empty(t, svc.operationLocks)
+}
+
+func TestNormalizeContainerSignalInternal(t *testing.T) {
+	for input, want := range map[string]string{
+		"":           "",
+		"SIGTERM":    "SIGTERM",
+		"term":       "SIGTERM",
+		" sigquit ":  "SIGQUIT",
+		"SIGRTMIN+3": "SIGRTMIN+3",
+		"rtmax-1":    "SIGRTMAX-1",
+		"15":         "15",
+	} {
+		got, err := normalizeContainerSignalInternal(input)
+		require.NoError(t, err, input)
+		require.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"SIGNOPE", "0", "65", "SIGTERM+1", "SIGRTMIN+31", "-9"} {
+		_, err := normalizeContainerSignalInternal(input)
+		require.True(t, cerrdefs.IsInvalidArgument(err), input)
+	}
+}
+
+func TestContainerServiceHealthDetailsInternal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := map[string]any{"Status": "running", "Running": true}
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/web/json":
+			log := make([]map[string]any, 0, 4)
+			for i := range 4 {
+				log = append(log, map[string]any{
+					"Start":    "2026-01-01T00:00:0" + strconv.Itoa(i) + "Z",
+					"End":      "2026-01-01T00:00:0" + strconv.Itoa(i) + "Z",
+					"ExitCode": i % 2,
+					"Output":   "probe " + strconv.Itoa(i),
+				})
+			}
+			state["Health"] = map[string]any{"Status": "unhealthy", "FailingStreak": 1, "Log": log}
+		case "/containers/plain/json":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(dockerTestPathInternal(r.URL.Path), "/containers/"), "/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Id":     id,
+			"Config": map[string]any{},
+			"State":  state,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &ContainerService{dockerService: &DockerClientService{client: newTestDockerClient(t, server)}}
+
+	summaries := []containertypes.Summary{{ID: "web"}, {ID: "plain"}}
+	svc.applyContainerSummaryInspectDetailsInternal(context.Background(), summaries, ContainerSummaryEnrichment{Health: true})
+	require.NotNil(t, summaries[0].Health)
+	require.Equal(t, "unhealthy", summaries[0].Health.Status)
+	require.Equal(t, 1, summaries[0].Health.FailingStreak)
+	require.Empty(t, summaries[0].Health.Log)
+	require.Nil(t, summaries[0].GPUs)
+	require.Nil(t, summaries[1].Health)
+
+	health, err := svc.GetHealthLog(context.Background(), "web", 2)
+	require.NoError(t, err)
+	require.Equal(t, "unhealthy", health.Status)
+	require.Len(t, health.Log, 2)
+	require.Equal(t, "probe 2", health.Log[0].Output)
+	require.Equal(t, 1, health.Log[1].ExitCode)
+
+	health, err = svc.GetHealthLog(context.Background(), "plain", 0)
+	require.NoError(t, err)
+	require.Equal(t, "none", health.Status)
+	require.Empty(t, health.Log)
+}
+
+func TestForEachBoundedInternalRespectsLimit(t *testing.T) {
+	var running, peak, calls atomic.Int32
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		forEachBoundedInternal(context.Background(), 12, 3, func(context.Context, int) {
+			current := running.Add(1)
+			for {
+				seen := peak.Load()
+				if current <= seen || peak.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+			calls.Add(1)
+		})
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return running.Load() == 3 }, time.Second, time.Millisecond)
+	close(release)
+	<-done
+
+	require.Equal(t, int32(12), calls.Load())
+	require.Equal(t, int32(3), peak.Load())
+}
+
+func TestApplyRecreateOverridesInternal(t *testing.T) {
+	original := container.Config{
+		Image: "nginx:1.27",
+		Env:   []string{"PATH=/usr/bin", "MODE=prod", "EMPTY"},
+	}
+
+	cfg := applyRecreateOverridesInternal(original, nil, containertypes.RecreateRequest{
+		Image: " nginx:1.29 ",
+		Env:   map[string]string{"MODE": "debug", "ZETA": "1", "ALPHA": "2"},
+	})
+	require.Equal(t, "nginx:1.29", cfg.Image)
+	require.Equal(t, []string{"PATH=/usr/bin", "MODE=debug", "EMPTY", "ALPHA=2", "ZETA=1"}, cfg.Env)
+	require.Equal(t, []string{"PATH=/usr/bin", "MODE=prod", "EMPTY"}, original.Env, "original config must not be modified")
+
+	cfg = applyRecreateOverridesInternal(original, nil, containertypes.RecreateRequest{})
+	require.Equal(t, original, *cfg)
+
+	// On an image change, variables inherited unchanged from the old image are dropped.
+	cfg = applyRecreateOverridesInternal(original, &container.Config{Env: []string{"PATH=/usr/bin", "MODE=image-default"}}, containertypes.RecreateRequest{Image: "nginx:1.29"})
+	require.Equal(t, []string{"MODE=prod", "EMPTY"}, cfg.Env)
+	require.Equal(t, []string{"PATH=/usr/bin", "MODE=prod", "EMPTY"}, original.Env, "original config must not be modified")
+
+	// The same image keeps every variable.
+	cfg = applyRecreateOverridesInternal(original, &container.Config{Env: []string{"PATH=/usr/bin"}}, containertypes.RecreateRequest{Image: "nginx:1.27"})
+	require.Equal(t, original.Env, cfg.Env)
+}
+
+func TestApplyRecreateOverridesInternal_DropsOldImageDefaults(t *testing.T) {
+	tcp80 := network.MustParsePort("80/tcp")
+	tcp9000 := network.MustParsePort("9000/tcp")
+	oldImage := &container.Config{
+		User:         "www-data",
+		ExposedPorts: network.PortSet{tcp80: {}},
+		Cmd:          []string{"nginx", "-g", "daemon off;"},
+		Healthcheck:  &container.HealthConfig{Test: []string{"CMD", "curl", "-f", "http://localhost"}},
+		WorkingDir:   "/usr/share/nginx",
+		Entrypoint:   []string{"/docker-entrypoint.sh"},
+		Labels:       map[string]string{"maintainer": "nginx"},
+	}
+	original := container.Config{
+		Image:        "nginx:1.27",
+		User:         oldImage.User,
+		ExposedPorts: network.PortSet{tcp80: {}, tcp9000: {}},
+		Cmd:          oldImage.Cmd,
+		Healthcheck:  oldImage.Healthcheck,
+		WorkingDir:   oldImage.WorkingDir,
+		Entrypoint:   oldImage.Entrypoint,
+		Labels:       map[string]string{"maintainer": "nginx", "com.docker.compose.service": "web"},
+	}
+
+	// Switching to an image with a different entrypoint must not keep the old one.
+	cfg := applyRecreateOverridesInternal(original, oldImage, containertypes.RecreateRequest{Image: "caddy:2"})
+	require.Equal(t, "caddy:2", cfg.Image)
+	require.Nil(t, cfg.Entrypoint)
+	require.Nil(t, cfg.Cmd)
+	require.Nil(t, cfg.Healthcheck)
+	require.Empty(t, cfg.WorkingDir)
+	require.Empty(t, cfg.User)
+	require.Equal(t, network.PortSet{tcp9000: {}}, cfg.ExposedPorts)
+	require.Equal(t, map[string]string{"com.docker.compose.service": "web"}, cfg.Labels)
+	require.Len(t, original.ExposedPorts, 2, "original config must not be modified")
+	require.Len(t, original.Labels, 2, "original config must not be modified")
+
+	// Settings the user changed from the old image's defaults carry over.
+	custom := original
+	custom.Entrypoint = []string{"/custom-entrypoint.sh"}
+	custom.User = "1000:1000"
+	cfg = applyRecreateOverridesInternal(custom, oldImage, containertypes.RecreateRequest{Image: "caddy:2"})
+	require.Equal(t, []string{"/custom-entrypoint.sh"}, cfg.Entrypoint)
+	require.Equal(t, "1000:1000", cfg.User)
+	require.Nil(t, cfg.Cmd)
+}
+
+func TestParseContainerEnvInternal(t *testing.T) {
+	entries := []string{"PATH=/usr/bin", "DB_PASSWORD=hunter2", "API_TOKEN=", "URL=a=b"}
+
+	require.Equal(t, []containertypes.EnvVar{
+		{Key: "PATH", Value: "/usr/bin"},
+		{Key: "DB_PASSWORD", Value: containerEnvMask, Masked: true},
+		{Key: "API_TOKEN", Value: ""},
+		{Key: "URL", Value: "a=b"},
+	}, parseContainerEnvInternal(entries, false))
+
+	revealed := parseContainerEnvInternal(entries, true)
+	require.Equal(t, "hunter2", revealed[1].Value)
+	require.False(t, revealed[1].Masked)
+
+	require.Equal(t, []string{"PATH=/usr/bin", "DB_PASSWORD=" + containerEnvMask, "API_TOKEN=", "URL=a=b", "BARE"},
+		maskContainerEnvEntriesInternal(append(entries, "BARE")))
+}
+
+func TestMergeContainerEnvInternal(t *testing.T) {
+	current := []string{"PATH=/usr/bin", "DB_PASSWORD=hunter2", "MODE=prod"}
+
+	merged, err := mergeContainerEnvInternal(current, []containertypes.EnvVar{
+		{Key: "DB_PASSWORD", Value: containerEnvMask},
+		{Key: " MODE ", Value: "debug"},
+		{Key: "NEW", Value: "1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"DB_PASSWORD=hunter2", "MODE=debug", "NEW=1"}, merged, "masked values keep the current secret and omitted keys are removed")
+
+	for name, env := range map[string][]containertypes.EnvVar{
+		"empty key":     {{Key: " ", Value: "x"}},
+		"key with =":    {{Key: "A=B", Value: "x"}},
+		"duplicate key": {{Key: "A", Value: "1"}, {Key: "A", Value: "2"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := mergeContainerEnvInternal(current, env)
+			require.True(t, cerrdefs.IsInvalidArgument(err))
+		})
+	}
+}
+
+func TestBuildContainerUpdateOptionsInternal(t *testing.T) {
+	options, metadata, err := buildContainerUpdateOptionsInternal(containertypes.ResourceUpdate{
+		RestartPolicy: &containertypes.RestartPolicyCreate{Name: "on-failure", MaximumRetryCount: 3},
+		Memory:        new(int64(512 << 20)),
+		NanoCPUs:      new(int64(1_500_000_000)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, &container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3}, options.RestartPolicy)
+	require.Equal(t, &container.Resources{Memory: 512 << 20, NanoCPUs: 1_500_000_000}, options.Resources)
+	require.Equal(t, "on-failure", metadata["restartPolicy"])
+
+	options, _, err = buildContainerUpdateOptionsInternal(containertypes.ResourceUpdate{
+		RestartPolicy: &containertypes.RestartPolicyCreate{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, container.RestartPolicyDisabled, options.RestartPolicy.Name)
+	require.Nil(t, options.Resources, "resources must be left untouched when only the restart policy changes")
+
+	for name, update := range map[string]containertypes.ResourceUpdate{
+		"empty":                {},
+		"unknown policy":       {RestartPolicy: &containertypes.RestartPolicyCreate{Name: "sometimes"}},
+		"retries with always":  {RestartPolicy: &containertypes.RestartPolicyCreate{Name: "always", MaximumRetryCount: 2}},
+		"negative retries":     {RestartPolicy: &containertypes.RestartPolicyCreate{Name: "on-failure", MaximumRetryCount: -1}},
+		"zero memory":          {Memory: new(int64(0))},
+		"tiny memory":          {Memory: new(int64(1024))},
+		"zero cpus":            {NanoCPUs: new(int64(0))},
+		"low cpu shares":       {CPUShares: new(int64(1))},
+		"swap below memory":    {Memory: new(int64(512 << 20)), MemorySwap: new(int64(256 << 20))},
+		"reservation too high": {Memory: new(int64(256 << 20)), MemoryReservation: new(int64(512 << 20))},
+	} {
+		_, _, err := buildContainerUpdateOptionsInternal(update)
+		require.True(t, cerrdefs.IsInvalidArgument(err), name)
+	}
+
+	options, _, err = buildContainerUpdateOptionsInternal(containertypes.ResourceUpdate{
+		Memory:     new(int64(512 << 20)),
+		MemorySwap: new(int64(-1)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), options.Resources.MemorySwap)
+}
+
+func TestFindHostPortConflictInternal(t *testing.T) {
+	running := []containertypes.Summary{
+		{ID: "abc123def456789", Names: []string{"/web"}, Ports: []containertypes.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}}},
+		{ID: "fff000fff000fff", Names: []string{"/dns"}, Ports: []containertypes.Port{{IP: "127.0.0.1", PrivatePort: 53, PublicPort: 5353, Type: "udp"}}},
+	}
+	tcp80 := network.MustParsePort("80/tcp")
+	udp53 := network.MustParsePort("53/udp")
+
+	err := findHostPortConflictInternal(network.PortMap{tcp80: {{HostPort: "8080"}}}, running)
+	require.ErrorIs(t, err, common.ErrContainerHostPortInUse)
+	require.ErrorIs(t, err, common.ErrConflict)
+	require.Equal(t, "port 8080/tcp already in use by web", err.Error())
+
+	err = findHostPortConflictInternal(network.PortMap{tcp80: {{HostPort: "8000-8100"}}}, running)
+	require.ErrorIs(t, err, common.ErrContainerHostPortInUse)
+
+	// Different protocol, different address or a random host port do not conflict.
+	require.NoError(t, findHostPortConflictInternal(network.PortMap{udp53: {{HostPort: "8080"}}}, running))
+	require.NoError(t, findHostPortConflictInternal(network.PortMap{udp53: {{HostIP: netip.MustParseAddr("10.0.0.5"), HostPort: "5353"}}}, running))
+	require.NoError(t, findHostPortConflictInternal(network.PortMap{tcp80: {{HostPort: ""}}}, running))
+
+	err = findHostPortConflictInternal(network.PortMap{udp53: {{HostPort: "5353"}}}, running)
+	require.ErrorIs(t, err, common.ErrContainerHostPortInUse)
+}
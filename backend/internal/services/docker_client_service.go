@@ -11,6 +11,7 @@ import (
 	"emperror.dev/errors"
 
 	"github.com/cenkalti/backoff/v5"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	docker "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
@@ -114,7 +115,8 @@ func closeDockerClientInternal(cli *client.Client, message string) {
 }
 
 // GetClient returns a singleton Docker client instance.
-// It initializes the client on the first call.
+// It initializes the client on the first call. Initialization failures are
+// classified as common.ErrDockerUnavailable and keep the underlying cause.
 func (s *DockerClientService) GetClient(ctx context.Context) (*client.Client, error) {
 	s.mu.Lock()
 	if s.client != nil {
@@ -126,7 +128,7 @@ func (s *DockerClientService) GetClient(ctx context.Context) (*client.Client, er
 
 	cli, err := newDockerClientInternal(ctx, s.config.DockerHost)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to create Docker client")
+		return nil, common.Classify(common.ErrDockerUnavailable, errors.WrapIf(err, "failed to create Docker client"))
 	}
 
 	s.mu.Lock()
@@ -150,7 +152,7 @@ func (s *DockerClientService) GetClient(ctx context.Context) (*client.Client, er
 func (s *DockerClientService) RefreshClient(ctx context.Context) error {
 	apiVersion, err := detectDockerAPIVersionInternal(ctx, s.config.DockerHost)
 	if err != nil {
-		return errors.WrapIf(err, "failed to refresh Docker client")
+		return common.Classify(common.ErrDockerUnavailable, errors.WrapIf(err, "failed to refresh Docker client"))
 	}
 
 	s.mu.Lock()
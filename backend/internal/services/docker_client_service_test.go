@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/events"
@@ -74,6 +75,19 @@ func TestDockerClientService_GetClientReturnsCachedClientUntilRefresh(t *testing
 	assert.Equal(t, "1.41", secondClient.ClientVersion())
 }
 
+func TestDockerClientService_GetClientClassifiesUnreachableDaemon(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	svc := newDockerClientServiceForTestInternal(server.URL)
+
+	_, err := svc.GetClient(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, common.ErrDockerUnavailable)
+	assert.ErrorIs(t, err, common.ErrUnavailable)
+	assert.Contains(t, err.Error(), "failed to create Docker client")
+	assert.Nil(t, svc.client)
+}
+
 func TestDockerClientService_RefreshClientRecreatesCachedClientAfterAPIVersionChange(t *testing.T) {
 	apiVersion := atomic.Value{}
 	apiVersion.Store("1.41")
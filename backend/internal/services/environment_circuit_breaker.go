@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
+)
+
+const (
+	// environmentRetryMaxAttempts is how many times a direct agent request is
+	// tried before a transient failure is returned to the caller.
+	environmentRetryMaxAttempts = 3
+	// environmentRetryBaseDelay is the backoff before the second attempt; it
+	// doubles for each attempt after that.
+	environmentRetryBaseDelay = 250 * time.Millisecond
+
+	// environmentCircuitFailureThreshold is how many consecutive failed requests
+	// open an environment's circuit breaker.
+	environmentCircuitFailureThreshold = 5
+	// environmentCircuitCooldown is how long an open breaker fast-fails requests
+	// before letting a trial request through.
+	environmentCircuitCooldown = 30 * time.Second
+)
+
+const (
+	environmentCircuitClosed   = "closed"
+	environmentCircuitOpen     = "open"
+	environmentCircuitHalfOpen = "half_open"
+)
+
+type environmentCircuitInternal struct {
+	consecutiveFailures int
+	openUntil           time.Time
+	// trialInFlight is set while the single half-open trial request is running.
+	trialInFlight bool
+}
+
+// environmentCircuitBreakerInternal tracks consecutive request failures per
+// environment. After environmentCircuitFailureThreshold failures it opens and
+// fast-fails requests for environmentCircuitCooldown; after the cooldown exactly
+// one request is let through as a trial, which closes the breaker on success or
+// re-opens it on failure. Other requests keep failing fast until that trial's
+// outcome is recorded.
+type environmentCircuitBreakerInternal struct {
+	mu       sync.Mutex
+	circuits map[string]*environmentCircuitInternal
+	now      func() time.Time
+}
+
+func newEnvironmentCircuitBreakerInternal() *environmentCircuitBreakerInternal {
+	return &environmentCircuitBreakerInternal{
+		circuits: make(map[string]*environmentCircuitInternal),
+		now:      time.Now,
+	}
+}
+
+// allow returns an error while the environment's breaker is open or its
+// half-open trial is already running. Every allowed request must report back
+// through recordOutcome.
+func (b *environmentCircuitBreakerInternal) allow(envID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuit, ok := b.circuits[envID]
+	if !ok || circuit.consecutiveFailures < environmentCircuitFailureThreshold {
+		return nil
+	}
+	if b.now().Before(circuit.openUntil) {
+		return common.Classify(common.ErrEnvironmentCircuitOpen, errors.Errorf("environment %s is unavailable after %d consecutive failures; retry after %s", envID, circuit.consecutiveFailures, circuit.openUntil.Format(time.RFC3339)))
+	}
+	if circuit.trialInFlight {
+		return common.Classify(common.ErrEnvironmentCircuitOpen, errors.Errorf("environment %s is unavailable after %d consecutive failures; a trial request is in progress", envID, circuit.consecutiveFailures))
+	}
+	circuit.trialInFlight = true
+	return nil
+}
+
+func (b *environmentCircuitBreakerInternal) recordSuccess(envID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.circuits, envID)
+}
+
+func (b *environmentCircuitBreakerInternal) recordFailure(envID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuit, ok := b.circuits[envID]
+	if !ok {
+		circuit = &environmentCircuitInternal{}
+		b.circuits[envID] = circuit
+	}
+	circuit.consecutiveFailures++
+	circuit.trialInFlight = false
+	if circuit.consecutiveFailures >= environmentCircuitFailureThreshold {
+		circuit.openUntil = b.now().Add(environmentCircuitCooldown)
+	}
+}
+
+func (b *environmentCircuitBreakerInternal) releaseTrialInternal(envID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if circuit, ok := b.circuits[envID]; ok {
+		circuit.trialInFlight = false
+	}
+}
+
+// snapshot returns the breaker state for envID, or nil when no failures are recorded.
+func (b *environmentCircuitBreakerInternal) snapshot(envID string) *environment.CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuit, ok := b.circuits[envID]
+	if !ok {
+		return nil
+	}
+
+	out := &environment.CircuitBreaker{
+		State:               environmentCircuitClosed,
+		ConsecutiveFailures: circuit.consecutiveFailures,
+	}
+	switch {
+	case b.now().Before(circuit.openUntil):
+		out.State = environmentCircuitOpen
+		out.OpenUntil = new(circuit.openUntil)
+	case circuit.consecutiveFailures >= environmentCircuitFailureThreshold:
+		out.State = environmentCircuitHalfOpen
+	}
+	return out
+}
+
+// recordOutcome feeds the outcome of a request to path into the breaker.
+// Requests cancelled by the caller say nothing about the agent; they only free
+// the half-open trial slot so another request can take it. A 503 from a health
+// route is the agent answering that it is not ready, so it counts as reachable.
+func (b *environmentCircuitBreakerInternal) recordOutcome(envID, path string, statusCode int, err error) {
+	if errors.Is(err, context.Canceled) {
+		b.releaseTrialInternal(envID)
+		return
+	}
+	if err == nil && statusCode == http.StatusServiceUnavailable && isEnvironmentHealthPathInternal(path) {
+		b.recordSuccess(envID)
+		return
+	}
+	if isTransientEnvironmentFailureInternal(statusCode, err) {
+		b.recordFailure(envID)
+		return
+	}
+	b.recordSuccess(envID)
+}
+
+// isTransientEnvironmentFailureInternal reports whether a request outcome
+// suggests the agent is down or restarting rather than answering.
+func isTransientEnvironmentFailureInternal(statusCode int, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable
+}
+
+// isEnvironmentHealthPathInternal reports whether path is an agent health or
+// readiness route, which answer 503 by design while a subsystem is down.
+// Agents served under a base path keep the same suffixes.
+func isEnvironmentHealthPathInternal(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	if strings.HasSuffix(path, "/api/health") {
+		return true
+	}
+	if !strings.Contains(path, "/api/environments/") {
+		return false
+	}
+	return strings.HasSuffix(path, "/system/health") || strings.HasSuffix(path, "/system/ready")
+}
+
+// isRetryableEnvironmentFailureInternal reports whether a failed attempt may be
+// repeated. Idempotent requests retry on any transient failure; other methods
+// only retry when the connection was never established, so the agent cannot
+// have acted on the request.
+func isRetryableEnvironmentFailureInternal(method string, statusCode int, err error) bool {
+	if !isTransientEnvironmentFailureInternal(statusCode, err) {
+		return false
+	}
+	// A timed-out attempt already used the caller's time budget.
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	var opErr *net.OpError
+	return err != nil && errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// RetryEnvironmentRequest runs attempt until it returns an outcome
+// isRetryableEnvironmentFailureInternal rejects or environmentRetryMaxAttempts
+// is reached, backing off between attempts. attempt reports the response status
+// code or a transport error.
+func RetryEnvironmentRequest(ctx context.Context, method string, attempt func() (int, error)) (int, error) {
+	delay := environmentRetryBaseDelay
+	for try := 1; ; try++ {
+		statusCode, err := attempt()
+		if try >= environmentRetryMaxAttempts || !isRetryableEnvironmentFailureInternal(method, statusCode, err) {
+			return statusCode, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statusCode, err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
@@ -3,18 +3,24 @@ package services
 import (
 	"github.com/samber/mo"
 
+	"bytes"
+	"cmp"
 	"context"
 	json "encoding/json/v2"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"emperror.dev/errors"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/edge"
@@ -24,14 +30,18 @@ import (
 	pkgutils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	httputils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils/httpx"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/mapper"
+	"github.com/getarcaneapp/arcane/types/v2"
+	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/containerregistry"
 	"github.com/getarcaneapp/arcane/types/v2/environment"
 	"github.com/getarcaneapp/arcane/types/v2/gitops"
 	schedulertypes "github.com/getarcaneapp/arcane/types/v2/scheduler"
+	"github.com/getarcaneapp/arcane/types/v2/system"
 	"github.com/google/uuid"
 	"github.com/moby/moby/client"
 	"github.com/samber/hot"
 	"go.getarcane.app/sys/crypto"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
 
@@ -43,6 +53,7 @@ type EnvironmentService struct {
 	settingsService *SettingsService
 	apiKeyService   *ApiKeyService
 	remoteClient    *remenv.Client
+	streamClient    *remenv.Client
 	tokenCacheMu    sync.RWMutex
 	tokenCache      *hot.HotCache[string, string]
 	tokenByEnvID    map[string]string
@@ -61,6 +72,12 @@ type EnvironmentService struct {
 	// variableSyncer is injected post-construction via SetVariableSyncer
 	// (manager-only) to avoid a wire cycle with VariableService.
 	variableSyncer VariableSyncer
+
+	// connectionStats holds the last successful TestConnection result per
+	// environment ID, surfaced on environment listings.
+	connectionStats sync.Map
+	// circuitBreaker fast-fails requests to environments that keep failing.
+	circuitBreaker *environmentCircuitBreakerInternal
 }
 
 // VariableSyncer pushes the effective global-variable set to one environment.
@@ -76,6 +93,22 @@ const (
 
 const edgeTokenCacheTTL = time.Minute
 
+const (
+	// environmentRefreshConcurrency bounds how many environments
+	// RefreshAllConnections tests at once.
+	environmentRefreshConcurrency = 8
+	// environmentRefreshTimeout bounds each environment's connection test
+	// during RefreshAllConnections.
+	environmentRefreshTimeout = 15 * time.Second
+)
+
+// healthResponseMaxBytes caps how much of an agent's health response is read.
+const healthResponseMaxBytes = 64 << 10
+
+// agentCredentialProbePath is an authenticated, side-effect free agent
+// endpoint used to tell a rejected access token apart from an unreachable agent.
+const agentCredentialProbePath = "/api/environments/0/system/health"
+
 const (
 	ErrEnvironmentAccessTokenRequired = errors.Sentinel("environment access token required")
 	ErrInvalidEnvironmentAccessToken  = errors.Sentinel("invalid environment access token")
@@ -96,12 +129,17 @@ func NewEnvironmentService(db *database.DB, httpClient *http.Client, dockerServi
 			EnsureAvailableFunc: ensureRemoteEnvironmentTunnelAvailableInternal,
 			DoFunc:              doRemoteEnvironmentTunnelRequestInternal,
 		}),
+		streamClient: remenv.NewClient(withoutClientTimeoutInternal(httpClient), remenv.TunnelTransportFuncs{
+			EnsureAvailableFunc: ensureRemoteEnvironmentTunnelAvailableInternal,
+			DoFunc:              doRemoteEnvironmentTunnelRequestInternal,
+		}),
 		tokenCache: hot.NewHotCache[string, string](hot.LRU, 1024).
 			WithTTL(edgeTokenCacheTTL).
 			WithJanitor().
 			Build(),
-		tokenByEnvID: make(map[string]string),
-		remoteEnvs:   make(map[string]models.Environment),
+		tokenByEnvID:   make(map[string]string),
+		remoteEnvs:     make(map[string]models.Environment),
+		circuitBreaker: newEnvironmentCircuitBreakerInternal(),
 	}
 }
 
@@ -245,12 +283,12 @@ func (s *EnvironmentService) runHealthCheckInternal(ctx context.Context, envID s
 	}
 	defer release()
 
-	status, err := s.TestConnection(ctx, envID, nil)
+	result, err := s.TestConnection(ctx, envID, nil)
 	switch {
 	case err != nil:
-		slog.WarnContext(ctx, "environment health check failed", "environment_id", envID, "status", status, "error", err)
+		slog.WarnContext(ctx, "environment health check failed", "environment_id", envID, "status", result.Status, "error", err)
 		return
-	case status != "online":
+	case result.Status != "online":
 		return
 	}
 
@@ -829,6 +867,7 @@ func (s *EnvironmentService) ListEnvironmentsPaginated(ctx context.Context, para
 
 	q = pagination.ApplyFilter(q, "status", params.Filters["status"])
 	q = pagination.ApplyBooleanFilter(q, "enabled", params.Filters["enabled"])
+	q = applyEnvironmentOrderInternal(q, params.Sort)
 
 	paginationResp, err := pagination.PaginateAndSortDB(params, q, &envs)
 	if err != nil {
@@ -839,6 +878,9 @@ func (s *EnvironmentService) ListEnvironmentsPaginated(ctx context.Context, para
 	if mapErr != nil {
 		return nil, pagination.Response{}, errors.WrapIf(mapErr, "failed to map environments")
 	}
+	for i := range out {
+		s.applyConnectionStatsInternal(&out[i])
+	}
 
 	return out, paginationResp, nil
 }
@@ -862,9 +904,9 @@ func filterEnvironmentsByIDInternal(items []environment.Environment, allowedIDs
 
 func (s *EnvironmentService) listEnvironmentsPaginatedWithRuntimeFiltersInternal(ctx context.Context, params pagination.QueryParams, accessibleEnvIDs []string) ([]environment.Environment, pagination.Response, error) {
 	var envs []models.Environment
-	if err := s.db.WithContext(ctx).
+	if err := applyEnvironmentOrderInternal(s.db.WithContext(ctx).
 		Model(&models.Environment{}).
-		Where("hidden = ?", false).
+		Where("hidden = ?", false), "").
 		Find(&envs).Error; err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list environments")
 	}
@@ -881,13 +923,27 @@ func (s *EnvironmentService) listEnvironmentsPaginatedWithRuntimeFiltersInternal
 
 	for i := range items {
 		ApplyEnvironmentRuntimeState(&items[i])
+		s.applyConnectionStatsInternal(&items[i])
 	}
 
-	config := pagination.Config[environment.Environment]{
+	config := s.EnvironmentPaginationConfig()
+	config.SortBindings = pinnedFirstSortBindingsInternal(config.SortBindings)
+
+	result := pagination.SearchOrderAndPaginate(items, params, config)
+	paginationResp := pagination.BuildResponseFromFilterResult(result, params)
+
+	return result.Items, paginationResp, nil
+}
+
+// EnvironmentPaginationConfig returns the in-memory search, sort and filter
+// bindings for environment lists.
+func (s *EnvironmentService) EnvironmentPaginationConfig() pagination.Config[environment.Environment] {
+	return pagination.Config[environment.Environment]{
 		SearchAccessors: []pagination.SearchAccessor[environment.Environment]{
 			func(env environment.Environment) (string, error) { return env.Name, nil },
 			func(env environment.Environment) (string, error) { return env.ApiUrl, nil },
 		},
+		CSVHeaders: []string{"Name", "API URL"},
 		SortBindings: []pagination.SortBinding[environment.Environment]{
 			{
 				Key: "id",
@@ -952,11 +1008,42 @@ func (s *EnvironmentService) listEnvironmentsPaginatedWithRuntimeFiltersInternal
 			},
 		},
 	}
+}
 
-	result := pagination.SearchOrderAndPaginate(items, params, config)
-	paginationResp := pagination.BuildResponseFromFilterResult(result, params)
+// applyEnvironmentOrderInternal sorts pinned environments first. Without an
+// explicit sort the rest follow the curated display order, then creation order.
+func applyEnvironmentOrderInternal(q *gorm.DB, sort string) *gorm.DB {
+	q = q.Order("pinned DESC")
+	if sort == "" {
+		q = q.Order("display_order ASC").Order("created_at ASC").Order("id ASC")
+	}
+	return q
+}
 
-	return result.Items, paginationResp, nil
+// pinnedFirstSortBindingsInternal wraps each binding so pinned environments
+// stay ahead of unpinned ones in both sort directions.
+func pinnedFirstSortBindingsInternal(bindings []pagination.SortBinding[environment.Environment]) []pagination.SortBinding[environment.Environment] {
+	for i, binding := range bindings {
+		asc := binding.Fn
+		bindings[i].Fn = func(a, b environment.Environment) int {
+			return cmp.Or(compareEnvironmentPinnedInternal(a, b), asc(a, b))
+		}
+		bindings[i].DescFn = func(a, b environment.Environment) int {
+			return cmp.Or(compareEnvironmentPinnedInternal(a, b), asc(b, a))
+		}
+	}
+	return bindings
+}
+
+func compareEnvironmentPinnedInternal(a, b environment.Environment) int {
+	switch {
+	case a.Pinned == b.Pinned:
+		return 0
+	case a.Pinned:
+		return -1
+	default:
+		return 1
+	}
 }
 
 func environmentTypeMatchesInternal(env environment.Environment, filterValue string) bool {
@@ -990,7 +1077,7 @@ func (s *EnvironmentService) ListVisibleEnvironments(ctx context.Context) ([]env
 	if err := s.db.WithContext(ctx).
 		Model(&models.Environment{}).
 		Where("hidden = ?", false).
-		Order("created_at asc, id asc").
+		Order("pinned desc, display_order asc, created_at asc, id asc").
 		Find(&envs).Error; err != nil {
 		return nil, errors.WrapIf(err, "failed to list visible environments")
 	}
@@ -1002,6 +1089,7 @@ func (s *EnvironmentService) ListVisibleEnvironments(ctx context.Context) ([]env
 
 	for i := range out {
 		ApplyEnvironmentRuntimeState(&out[i])
+		s.applyConnectionStatsInternal(&out[i])
 	}
 
 	return out, nil
@@ -1111,6 +1199,52 @@ func (s *EnvironmentService) UpdateEnvironment(ctx context.Context, id string, u
 	return updated, nil
 }
 
+// ReorderEnvironments assigns display orders following ids. Visible
+// environments missing from ids keep their relative order after the listed ones.
+func (s *EnvironmentService) ReorderEnvironments(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return common.Classify(common.ErrEnvironmentOrderInvalid, errors.New("at least one environment ID is required"))
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []string
+		if err := applyEnvironmentOrderInternal(tx.Model(&models.Environment{}).Where("hidden = ?", false), "").
+			Pluck("id", &current).Error; err != nil {
+			return errors.WrapIf(err, "failed to list environments")
+		}
+
+		known := make(map[string]bool, len(current))
+		for _, id := range current {
+			known[id] = false
+		}
+
+		ordered := make([]string, 0, len(current))
+		for _, id := range ids {
+			placed, ok := known[id]
+			if !ok {
+				return common.Classify(common.ErrEnvironmentOrderInvalid, errors.Errorf("unknown environment ID %q", id))
+			}
+			if placed {
+				return common.Classify(common.ErrEnvironmentOrderInvalid, errors.Errorf("duplicate environment ID %q", id))
+			}
+			known[id] = true
+			ordered = append(ordered, id)
+		}
+		for _, id := range current {
+			if !known[id] {
+				ordered = append(ordered, id)
+			}
+		}
+
+		for position, id := range ordered {
+			if err := tx.Model(&models.Environment{}).Where("id = ?", id).UpdateColumn("display_order", position).Error; err != nil {
+				return errors.WrapIf(err, "failed to update environment order")
+			}
+		}
+		return nil
+	})
+}
+
 func (s *EnvironmentService) DeleteEnvironment(ctx context.Context, id string, userID, username *string) error {
 	// Get environment details before deletion
 	env, err := s.GetEnvironmentByID(ctx, id)
@@ -1161,6 +1295,8 @@ func (s *EnvironmentService) DeleteEnvironment(ctx context.Context, id string, u
 
 	s.invalidateEnvironmentTokenInternal(id)
 	s.removeRemoteEnvironmentSnapshotInternal(id)
+	s.connectionStats.Delete(id)
+	s.circuitBreaker.recordSuccess(id)
 
 	// Create event in background
 	go s.createEnvironmentEvent(context.WithoutCancel(ctx), id, env.Name, models.EventTypeEnvironmentDelete, "Environment Deleted", fmt.Sprintf("Environment '%s' was deleted", env.Name), models.EventSeverityWarning, userID, username)
@@ -1168,23 +1304,91 @@ func (s *EnvironmentService) DeleteEnvironment(ctx context.Context, id string, u
 	return nil
 }
 
-func (s *EnvironmentService) TestConnection(ctx context.Context, id string, customApiUrl *string) (string, error) {
-	environment, err := s.GetEnvironmentByID(ctx, id)
+// TestConnection checks that an environment is reachable and, unless
+// customApiUrl is set, records the outcome as the environment's status. The
+// result carries the health check's round-trip latency and the version the
+// agent reports; the local environment reports 0 latency and this build's
+// version.
+func (s *EnvironmentService) TestConnection(ctx context.Context, id string, customApiUrl *string) (environment.Test, error) {
+	env, err := s.GetEnvironmentByID(ctx, id)
 	if err != nil {
-		return "error", err
+		return environment.Test{Status: "error"}, err
 	}
 
+	var result environment.Test
+	switch {
 	// Special handling for local Docker environment (ID "0")
-	if id == "0" && customApiUrl == nil {
-		return s.testLocalDockerConnection(ctx, id)
+	case id == "0" && customApiUrl == nil:
+		result, err = s.testLocalDockerConnection(ctx, id)
+	// For edge environments, check if there's an active tunnel and route through it
+	case env.IsEdge && customApiUrl == nil:
+		result, err = s.testEdgeConnection(ctx, env)
+	default:
+		result, err = s.testDirectConnectionInternal(ctx, env, customApiUrl)
 	}
 
-	// For edge environments, check if there's an active tunnel and route through it
-	if environment.IsEdge && customApiUrl == nil {
-		return s.testEdgeConnection(ctx, id)
+	if customApiUrl == nil && id != "0" {
+		switch result.Status {
+		case string(models.EnvironmentStatusOffline), string(models.EnvironmentStatusError):
+			s.circuitBreaker.recordFailure(id)
+		default:
+			s.circuitBreaker.recordSuccess(id)
+		}
+	}
+	if customApiUrl == nil {
+		// A failed test clears the last latency and version so listings do not
+		// show stale figures for an environment that is no longer reachable.
+		if err == nil {
+			s.connectionStats.Store(id, result)
+		} else {
+			s.connectionStats.Delete(id)
+		}
+	}
+	return result, err
+}
+
+// RefreshAllConnections tests every enabled environment concurrently, each with
+// its own timeout, and returns the resulting status keyed by environment ID.
+// Each test updates the environment's status and last-seen heartbeat. When allow
+// is non-nil, environments it rejects are skipped and never tested.
+func (s *EnvironmentService) RefreshAllConnections(ctx context.Context, allow func(environmentID string) bool) (map[string]string, error) {
+	ids, err := s.listEnabledEnvironmentIDsInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if allow != nil {
+		ids = slices.DeleteFunc(ids, func(id string) bool { return !allow(id) })
+	}
+
+	var mu sync.Mutex
+	statuses := make(map[string]string, len(ids))
+
+	g := new(errgroup.Group)
+	g.SetLimit(environmentRefreshConcurrency)
+	for _, id := range ids {
+		g.Go(func() error {
+			testCtx, cancel := context.WithTimeout(ctx, environmentRefreshTimeout)
+			defer cancel()
+
+			result, testErr := s.TestConnection(testCtx, id, nil)
+			if testErr != nil {
+				slog.DebugContext(ctx, "environment connection refresh failed", "environment_id", id, "status", result.Status, "error", testErr)
+			}
+
+			mu.Lock()
+			statuses[id] = result.Status
+			mu.Unlock()
+			return nil
+		})
 	}
+	_ = g.Wait()
 
-	apiUrl := environment.ApiUrl
+	return statuses, nil
+}
+
+func (s *EnvironmentService) testDirectConnectionInternal(ctx context.Context, env *models.Environment, customApiUrl *string) (environment.Test, error) {
+	id := env.ID
+	apiUrl := env.ApiUrl
 	if customApiUrl != nil && *customApiUrl != "" {
 		apiUrl = *customApiUrl
 	}
@@ -1194,7 +1398,7 @@ func (s *EnvironmentService) TestConnection(ctx context.Context, id string, cust
 		if customApiUrl == nil {
 			_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
 		}
-		return "offline", errors.WrapIf(err, "invalid environment API URL")
+		return environment.Test{Status: "offline"}, errors.WrapIf(err, "invalid environment API URL")
 	}
 
 	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -1204,58 +1408,143 @@ func (s *EnvironmentService) TestConnection(ctx context.Context, id string, cust
 		if customApiUrl == nil {
 			_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
 		}
-		return "offline", errors.WrapIf(err, "failed to create request")
-	}
-	resp, err := s.httpClient.Do(req)
+		return environment.Test{Status: "offline"}, errors.WrapIf(err, "failed to create request")
+	}
+	// The agent only reports its version to authenticated callers.
+	edge.SetAgentToken(req, env.AccessToken)
+	if controllerURL := s.controllerURLInternal(reqCtx); controllerURL != "" {
+		req.Header.Set(pkgutils.HeaderControllerURL, controllerURL)
+	}
+	var resp *http.Response
+	var latency time.Duration
+	_, err = RetryEnvironmentRequest(reqCtx, http.MethodGet, func() (int, error) {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		start := time.Now()
+		var doErr error
+		resp, doErr = s.httpClient.Do(req)
+		latency = time.Since(start)
+		if doErr != nil {
+			return 0, doErr
+		}
+		return resp.StatusCode, nil
+	})
 	if err != nil {
 		if customApiUrl == nil {
 			_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
 		}
-		return "offline", errors.WrapIf(err, "connection failed")
+		return environment.Test{Status: "offline"}, errors.WrapIf(err, "connection failed")
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusOK {
+		statusCode, probeErr := s.probeAgentCredentialsInternal(reqCtx, apiUrl, env.AccessToken)
+		if probeErr == nil && agentRejectedCredentialsInternal(statusCode) {
+			if customApiUrl == nil {
+				_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusAuthFailed))
+			}
+			return environment.Test{Status: string(models.EnvironmentStatusAuthFailed)}, agentAuthFailedErrorInternal(statusCode)
+		}
+
 		if customApiUrl == nil {
 			_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOnline))
 		}
-		return "online", nil
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, healthResponseMaxBytes))
+		return onlineConnectionTestInternal(latency, healthResponseVersionInternal(body)), nil
+	}
+
+	if agentRejectedCredentialsInternal(resp.StatusCode) {
+		if customApiUrl == nil {
+			_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusAuthFailed))
+		}
+		return environment.Test{Status: string(models.EnvironmentStatusAuthFailed)}, agentAuthFailedErrorInternal(resp.StatusCode)
 	}
 
 	if customApiUrl == nil {
 		_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusError))
 	}
-	return "error", errors.Errorf("unexpected status code: %d", resp.StatusCode)
+	return environment.Test{Status: "error"}, errors.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// probeAgentCredentialsInternal sends the access token to an authenticated
+// agent endpoint and returns the response status. /api/health is public, so it
+// cannot reveal whether the agent still accepts the token. Environments
+// without an access token are not probed and report 0.
+func (s *EnvironmentService) probeAgentCredentialsInternal(ctx context.Context, apiUrl string, accessToken *string) (int, error) {
+	if accessToken == nil || strings.TrimSpace(*accessToken) == "" {
+		return 0, nil
+	}
+
+	probeURL, err := buildEnvironmentEndpointURLInternal(apiUrl, agentCredentialProbePath)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, probeURL, nil)
+	if err != nil {
+		return 0, errors.WrapIf(err, "failed to create credential probe request")
+	}
+	edge.SetAgentToken(req, accessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.WrapIf(err, "credential probe failed")
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func agentRejectedCredentialsInternal(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
+
+func agentAuthFailedErrorInternal(statusCode int) error {
+	return common.Classify(common.ErrEnvironmentAuthFailed, errors.Errorf("agent rejected credentials (status code %d)", statusCode))
 }
 
 // testEdgeConnection tests connection to an edge agent via its tunnel
-func (s *EnvironmentService) testEdgeConnection(ctx context.Context, id string) (string, error) {
+func (s *EnvironmentService) testEdgeConnection(ctx context.Context, env *models.Environment) (environment.Test, error) {
+	id := env.ID
 	if !edge.HasActiveTunnel(id) {
 		if _, ok := edge.RequestTunnelAndWait(ctx, id, edge.DefaultTunnelDemandTTL, edge.DefaultTunnelAcquireTimeout()).Get(); !ok {
 			_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
-			return "offline", errors.New("edge agent is not connected")
+			return environment.Test{Status: "offline"}, errors.New("edge agent is not connected")
 		}
 	}
 
 	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	statusCode, _, err := edge.DoRequest(reqCtx, id, http.MethodGet, "/api/health", nil)
+	// The agent only reports its version to authenticated callers.
+	headers := map[string]string{}
+	remenv.ApplyAgentTokenHeaderMap(headers, env.AccessToken)
+	if controllerURL := s.controllerURLInternal(reqCtx); controllerURL != "" {
+		headers[pkgutils.HeaderControllerURL] = controllerURL
+	}
+
+	start := time.Now()
+	statusCode, body, err := edge.DoRequestWithHeaders(reqCtx, id, http.MethodGet, "/api/health", headers, nil)
 	if err != nil {
 		_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
-		return "offline", errors.WrapIf(err, "health check via tunnel failed")
+		return environment.Test{Status: "offline"}, errors.WrapIf(err, "health check via tunnel failed")
 	}
+	latency := time.Since(start)
 
 	if statusCode == http.StatusOK {
 		_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOnline))
-		return "online", nil
+		return onlineConnectionTestInternal(latency, healthResponseVersionInternal(body)), nil
+	}
+
+	if agentRejectedCredentialsInternal(statusCode) {
+		_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusAuthFailed))
+		return environment.Test{Status: string(models.EnvironmentStatusAuthFailed)}, agentAuthFailedErrorInternal(statusCode)
 	}
 
 	_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusError))
-	return "error", errors.Errorf("unexpected status code: %d", statusCode)
+	return environment.Test{Status: "error"}, errors.Errorf("unexpected status code: %d", statusCode)
 }
 
-func (s *EnvironmentService) testLocalDockerConnection(ctx context.Context, id string) (string, error) {
+func (s *EnvironmentService) testLocalDockerConnection(ctx context.Context, id string) (environment.Test, error) {
 	// Test local Docker socket by pinging Docker
 	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -1263,17 +1552,49 @@ func (s *EnvironmentService) testLocalDockerConnection(ctx context.Context, id s
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
-		return "offline", errors.WrapIf(err, "failed to connect to Docker")
+		return environment.Test{Status: "offline"}, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
 	_, err = dockerClient.Ping(reqCtx, client.PingOptions{})
 	if err != nil {
 		_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOffline))
-		return "offline", errors.WrapIf(err, "docker ping failed")
+		return environment.Test{Status: "offline"}, errors.WrapIf(err, "docker ping failed")
 	}
 
 	_ = s.updateEnvironmentStatusInternal(ctx, id, string(models.EnvironmentStatusOnline))
-	return "online", nil
+	return onlineConnectionTestInternal(0, config.Version), nil
+}
+
+func onlineConnectionTestInternal(latency time.Duration, agentVersion string) environment.Test {
+	return environment.Test{
+		Status:       "online",
+		LatencyMs:    new(latency.Milliseconds()),
+		AgentVersion: mo.EmptyableToOption(strings.TrimSpace(agentVersion)).ToPointer(),
+	}
+}
+
+// healthResponseVersionInternal reads the version from an agent's /api/health
+// body. Agents that predate the field report no version.
+func healthResponseVersionInternal(body []byte) string {
+	var health system.HealthResponse
+	if err := json.Unmarshal(body, &health); err != nil {
+		return ""
+	}
+	return health.Version
+}
+
+// applyConnectionStatsInternal copies the latency and agent version of the
+// environment's last successful connection test, and its circuit breaker
+// state, onto env.
+func (s *EnvironmentService) applyConnectionStatsInternal(env *environment.Environment) {
+	env.CircuitBreaker = s.circuitBreaker.snapshot(env.ID)
+	value, ok := s.connectionStats.Load(env.ID)
+	if !ok {
+		return
+	}
+	stats, _ := value.(environment.Test)
+	env.LatencyMs = stats.LatencyMs
+	env.AgentVersion = stats.AgentVersion
 }
 
 func (s *EnvironmentService) updateEnvironmentStatusInternal(ctx context.Context, id, status string) error {
@@ -1429,6 +1750,126 @@ func (s *EnvironmentService) RegenerateEnvironmentApiKey(ctx context.Context, en
 	return nil
 }
 
+// SetBootstrapToken stores the agent's bootstrap token encrypted so
+// RepairAgentToken can re-pair the environment later. An empty token clears it.
+func (s *EnvironmentService) SetBootstrapToken(ctx context.Context, id string, token string) error {
+	token = strings.TrimSpace(token)
+
+	var stored *string
+	if token != "" {
+		encrypted, err := crypto.Encrypt(token)
+		if err != nil {
+			return errors.WrapIf(err, "failed to encrypt bootstrap token")
+		}
+		stored = &encrypted
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.Environment{}).Where("id = ?", id).Update("bootstrap_token", stored).Error; err != nil {
+		return errors.WrapIf(err, "failed to store bootstrap token")
+	}
+	return nil
+}
+
+// RepairAgentToken re-pairs a direct environment whose agent rejects its access
+// token. It sends the stored bootstrap token to the agent's pairing endpoint.
+// The agent token it gets back becomes the new access token. It then re-tests
+// the connection and reports a failed re-test in the result message. The agent
+// token is not rotated, so the bootstrap token stays valid.
+func (s *EnvironmentService) RepairAgentToken(ctx context.Context, id string, userID, username *string) (environment.Test, error) {
+	env, err := s.GetEnvironmentByID(ctx, id)
+	if err != nil {
+		return environment.Test{Status: "error"}, err
+	}
+	if id == types.LOCAL_DOCKER_ENVIRONMENT_ID || env.IsEdge {
+		return environment.Test{Status: env.Status}, common.Classify(common.ErrEnvironmentRepairUnsupported, errors.Errorf("environment %s cannot be repaired with a bootstrap token", id))
+	}
+	if env.BootstrapToken == nil || *env.BootstrapToken == "" {
+		return environment.Test{Status: env.Status}, common.Classify(common.ErrEnvironmentBootstrapTokenMissing, errors.Errorf("no bootstrap token stored for environment %s", id))
+	}
+
+	bootstrapToken, err := crypto.Decrypt(*env.BootstrapToken)
+	if err != nil {
+		return environment.Test{Status: env.Status}, errors.WrapIf(err, "failed to decrypt bootstrap token")
+	}
+
+	token, err := s.pairAgentTokenInternal(ctx, env.ApiUrl, bootstrapToken, s.controllerURLInternal(ctx))
+	if err != nil {
+		return environment.Test{Status: env.Status}, err
+	}
+
+	if _, err := s.UpdateEnvironment(ctx, id, map[string]any{"access_token": token}, userID, username); err != nil {
+		return environment.Test{Status: env.Status}, err
+	}
+	slog.InfoContext(ctx, "Re-paired environment agent with bootstrap token", "environment_id", id)
+
+	result, testErr := s.TestConnection(ctx, id, nil)
+	if testErr != nil {
+		result.Message = new(testErr.Error())
+	}
+	return result, nil
+}
+
+// controllerURLInternal returns this controller's own URL, which is the local
+// environment's API URL. Agents record it as the controller they are paired
+// with.
+func (s *EnvironmentService) controllerURLInternal(ctx context.Context) string {
+	local, err := s.GetEnvironmentByID(ctx, types.LOCAL_DOCKER_ENVIRONMENT_ID)
+	if err != nil {
+		return ""
+	}
+	return local.ApiUrl
+}
+
+// pairAgentTokenInternal asks the agent at apiURL for its current agent token,
+// authenticating with the bootstrap header the agent accepts on its pairing path.
+// controllerURL, when set, identifies this controller to the agent.
+func (s *EnvironmentService) pairAgentTokenInternal(ctx context.Context, apiURL, bootstrapToken, controllerURL string) (string, error) {
+	pairURL, err := buildEnvironmentEndpointURLInternal(apiURL, pkgutils.AgentPairingPrefix)
+	if err != nil {
+		return "", errors.WrapIf(err, "invalid environment API URL")
+	}
+
+	body, err := json.Marshal(environment.AgentPairRequest{Rotate: new(false)})
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to encode pairing request")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, pairURL, bytes.NewReader(body))
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to create pairing request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pkgutils.HeaderAgentBootstrap, bootstrapToken)
+	if controllerURL != "" {
+		req.Header.Set(pkgutils.HeaderControllerURL, controllerURL)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", errors.WrapIf(err, "pairing request failed")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if agentRejectedCredentialsInternal(resp.StatusCode) {
+		return "", common.Classify(common.ErrEnvironmentAuthFailed, errors.Errorf("agent rejected bootstrap token (status code %d)", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected pairing status code: %d", resp.StatusCode)
+	}
+
+	var pairResp base.ApiResponse[environment.AgentPairResponse]
+	if err := json.UnmarshalRead(io.LimitReader(resp.Body, healthResponseMaxBytes), &pairResp); err != nil {
+		return "", errors.WrapIf(err, "failed to decode pairing response")
+	}
+	token := strings.TrimSpace(pairResp.Data.Token)
+	if token == "" {
+		return "", errors.New("agent returned an empty token")
+	}
+	return token, nil
+}
+
 func (s *EnvironmentService) GetDB() *database.DB {
 	return s.db
 }
@@ -1811,17 +2252,53 @@ func (s *EnvironmentService) buildRemoteRequestInternal(
 	}, nil
 }
 
+// AllowEnvironmentRequest returns common.ErrEnvironmentCircuitOpen while the
+// environment's circuit breaker fast-fails requests. Callers that proxy to an
+// agent themselves must report every allowed request through
+// RecordEnvironmentRequestOutcome.
+func (s *EnvironmentService) AllowEnvironmentRequest(envID string) error {
+	return s.circuitBreaker.allow(envID)
+}
+
+// RecordEnvironmentRequestOutcome feeds the result of a proxied request to path
+// into the environment's circuit breaker. statusCode is the agent's response
+// status, or 0 with err set when no response arrived.
+func (s *EnvironmentService) RecordEnvironmentRequestOutcome(envID, path string, statusCode int, err error) {
+	s.circuitBreaker.recordOutcome(envID, path, statusCode, err)
+}
+
 func (s *EnvironmentService) ExecuteRemoteRequest(ctx context.Context, envID string, method string, path string, body []byte) (*remenv.Response, error) {
 	target, err := s.resolveRemoteEnvironmentTargetInternal(ctx, envID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.executeRemoteRequestForTargetInternal(ctx, target, method, path, body)
+	return s.executeRemoteRequestForTargetInternal(ctx, s.remoteClient, target, method, path, body)
+}
+
+// ExecuteRemoteStreamRequest is ExecuteRemoteRequest for operations that outlive
+// the shared HTTP client's timeout, such as streamed deploys. The caller must
+// bound ctx.
+func (s *EnvironmentService) ExecuteRemoteStreamRequest(ctx context.Context, envID string, method string, path string, body []byte) (*remenv.Response, error) {
+	target, err := s.resolveRemoteEnvironmentTargetInternal(ctx, envID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executeRemoteRequestForTargetInternal(ctx, s.streamClient, target, method, path, body)
+}
+
+// withoutClientTimeoutInternal copies httpClient with its overall timeout
+// removed so only the request context bounds a call.
+func withoutClientTimeoutInternal(httpClient *http.Client) *http.Client {
+	client := *httpClient
+	client.Timeout = 0
+	return &client
 }
 
 func (s *EnvironmentService) executeRemoteRequestForTargetInternal(
 	ctx context.Context,
+	client *remenv.Client,
 	target *remoteEnvironmentTargetInternal,
 	method string,
 	path string,
@@ -1838,7 +2315,28 @@ func (s *EnvironmentService) executeRemoteRequestForTargetInternal(
 		return nil, err
 	}
 
-	resp, err := s.remoteClient.Do(ctx, request)
+	if err := s.circuitBreaker.allow(target.ID); err != nil {
+		return nil, errors.WrapIff(err, "failed to send request to environment %s", target.Name)
+	}
+
+	var resp *remenv.Response
+	doRequest := func() (int, error) {
+		var doErr error
+		resp, doErr = client.Do(ctx, request)
+		if doErr != nil {
+			return 0, doErr
+		}
+		return resp.StatusCode, nil
+	}
+
+	// Edge requests already wait for the tunnel, so only direct requests retry.
+	var statusCode int
+	if target.IsEdge {
+		statusCode, err = doRequest()
+	} else {
+		statusCode, err = RetryEnvironmentRequest(ctx, method, doRequest)
+	}
+	s.circuitBreaker.recordOutcome(target.ID, path, statusCode, err)
 	if err != nil {
 		return nil, errors.WrapIff(err, "failed to send request to environment %s", target.Name)
 	}
@@ -1880,7 +2378,7 @@ func (s *EnvironmentService) proxyJSONRequestForTargetInternal(
 	body []byte,
 	out any,
 ) error {
-	resp, err := s.executeRemoteRequestForTargetInternal(ctx, target, method, path, body)
+	resp, err := s.executeRemoteRequestForTargetInternal(ctx, s.remoteClient, target, method, path, body)
 	if err != nil {
 		return err
 	}
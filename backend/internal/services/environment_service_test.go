@@ -13,10 +13,12 @@ import (
 	"testing"
 	"time"
 
+	"emperror.dev/errors"
 	sqlite "github.com/libtnb/sqlite"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
@@ -890,6 +892,50 @@ func TestEnvironmentService_ListMethods_ExcludeHiddenEnvironments(t *testing.T)
 	require.Equal(t, "env-visible", remoteEnvironments[0].ID)
 }
 
+func TestEnvironmentService_ReorderEnvironments_PinnedSortFirst(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	createNamedTestEnvironmentInternal(t, db, "env-a", "Alpha", "http://a.example", nil)
+	createNamedTestEnvironmentInternal(t, db, "env-b", "Bravo", "http://b.example", nil)
+	createNamedTestEnvironmentInternal(t, db, "env-c", "Charlie", "http://c.example", nil)
+
+	require.ErrorIs(t, svc.ReorderEnvironments(ctx, []string{"env-a", "env-a"}), common.ErrEnvironmentOrderInvalid)
+	require.ErrorIs(t, svc.ReorderEnvironments(ctx, []string{"missing"}), common.ErrEnvironmentOrderInvalid)
+
+	require.NoError(t, svc.ReorderEnvironments(ctx, []string{"env-c", "env-a"}))
+	_, err := svc.UpdateEnvironment(ctx, "env-b", map[string]any{"pinned": true}, nil, nil)
+	require.NoError(t, err)
+
+	listIDs := func(params pagination.QueryParams) []string {
+		t.Helper()
+		items, _, err := svc.ListEnvironmentsPaginated(ctx, params, nil)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	defaultParams := pagination.QueryParams{Params: pagination.Params{Start: 0, Limit: 20}}
+	require.Equal(t, []string{"env-b", "env-c", "env-a"}, listIDs(defaultParams))
+
+	byNameDesc := pagination.QueryParams{
+		Params:     pagination.Params{Start: 0, Limit: 20},
+		SortParams: pagination.SortParams{Sort: "name", Order: pagination.SortDesc},
+	}
+	require.Equal(t, []string{"env-b", "env-c", "env-a"}, listIDs(byNameDesc))
+
+	runtimeFiltered := byNameDesc
+	runtimeFiltered.Filters = map[string]string{"type": "http"}
+	require.Equal(t, []string{"env-b", "env-c", "env-a"}, listIDs(runtimeFiltered))
+
+	runtimeFiltered.Order = pagination.SortAsc
+	require.Equal(t, []string{"env-b", "env-a", "env-c"}, listIDs(runtimeFiltered))
+}
+
 func TestEnvironmentService_ListEnvironmentsPaginated_FiltersByAccessibleEnvIDs(t *testing.T) {
 	ctx := context.Background()
 	db := setupEnvironmentServiceTestDB(t)
@@ -1127,12 +1173,196 @@ func TestEnvironmentService_TestConnection_RejectsInvalidCustomURL(t *testing.T)
 	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
 
 	createTestEnvironment(t, db, "env-1", "http://example.com", nil)
-	status, err := svc.TestConnection(ctx, "env-1", new("ftp://example.com"))
+	result, err := svc.TestConnection(ctx, "env-1", new("ftp://example.com"))
 	require.Error(t, err)
-	require.Equal(t, "offline", status)
+	require.Equal(t, "offline", result.Status)
 	require.Contains(t, err.Error(), "invalid environment API URL")
 }
 
+func TestEnvironmentService_TestConnection_IdentifiesController(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "http://controller.example.com", r.Header.Get("X-Arcane-Controller-Url"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"UP"}`))
+	}))
+	defer server.Close()
+
+	createTestEnvironment(t, db, "0", "http://controller.example.com", nil)
+	createTestEnvironment(t, db, "env-1", server.URL, nil)
+
+	result, err := svc.TestConnection(ctx, "env-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, "online", result.Status)
+}
+
+func TestEnvironmentService_TestConnection_ReportsLatencyAndAgentVersion(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	var unhealthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/health", r.URL.Path)
+		if unhealthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"UP","version":"1.2.3"}`))
+	}))
+	defer server.Close()
+
+	createTestEnvironment(t, db, "env-1", server.URL, nil)
+
+	result, err := svc.TestConnection(ctx, "env-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, "online", result.Status)
+	require.NotNil(t, result.LatencyMs)
+	require.NotNil(t, result.AgentVersion)
+	require.Equal(t, "1.2.3", *result.AgentVersion)
+
+	listed, _, err := svc.ListEnvironmentsPaginated(ctx, pagination.QueryParams{
+		Params: pagination.Params{Start: 0, Limit: 20},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, result.LatencyMs, listed[0].LatencyMs)
+	require.Equal(t, result.AgentVersion, listed[0].AgentVersion)
+
+	// A failed test clears the figures from the last successful one.
+	unhealthy.Store(true)
+	_, err = svc.TestConnection(ctx, "env-1", nil)
+	require.Error(t, err)
+
+	listed, _, err = svc.ListEnvironmentsPaginated(ctx, pagination.QueryParams{
+		Params: pagination.Params{Start: 0, Limit: 20},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Nil(t, listed[0].LatencyMs)
+	require.Nil(t, listed[0].AgentVersion)
+}
+
+func TestEnvironmentService_TestConnection_DetectsRejectedTokenAndRepairs(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	const agentToken = "agent-token"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			w.WriteHeader(http.StatusOK)
+		case agentCredentialProbePath:
+			if r.Header.Get("X-Arcane-Agent-Token") != agentToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/api/environments/0/agent/pair":
+			require.Equal(t, agentToken, r.Header.Get("X-Arcane-Agent-Bootstrap"))
+			require.Equal(t, "http://controller.example.com", r.Header.Get("X-Arcane-Controller-Url"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"success":true,"data":{"token":"` + agentToken + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	createTestEnvironment(t, db, "0", "http://controller.example.com", nil)
+	createTestEnvironment(t, db, "env-1", server.URL, new("stale-token"))
+
+	result, err := svc.TestConnection(ctx, "env-1", nil)
+	require.ErrorIs(t, err, common.ErrEnvironmentAuthFailed)
+	require.Equal(t, string(models.EnvironmentStatusAuthFailed), result.Status)
+
+	env, err := svc.GetEnvironmentByID(ctx, "env-1")
+	require.NoError(t, err)
+	require.Equal(t, string(models.EnvironmentStatusAuthFailed), env.Status)
+
+	_, err = svc.RepairAgentToken(ctx, "env-1", nil, nil)
+	require.ErrorIs(t, err, common.ErrEnvironmentBootstrapTokenMissing)
+
+	require.NoError(t, svc.SetBootstrapToken(ctx, "env-1", agentToken))
+	result, err = svc.RepairAgentToken(ctx, "env-1", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "online", result.Status)
+
+	env, err = svc.GetEnvironmentByID(ctx, "env-1")
+	require.NoError(t, err)
+	require.NotNil(t, env.AccessToken)
+	require.Equal(t, agentToken, *env.AccessToken)
+	require.Equal(t, string(models.EnvironmentStatusOnline), env.Status)
+}
+
+func TestEnvironmentService_RefreshAllConnections_ReportsEachEnabledEnvironment(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	onlineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"UP"}`))
+	}))
+	defer onlineServer.Close()
+
+	offlineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	offlineURL := offlineServer.URL
+	offlineServer.Close()
+
+	createTestEnvironment(t, db, "env-online", onlineServer.URL, nil)
+	createTestEnvironment(t, db, "env-offline", offlineURL, nil)
+	createTestEnvironment(t, db, "env-disabled", onlineServer.URL, nil)
+	require.NoError(t, db.WithContext(ctx).Model(&models.Environment{}).Where("id = ?", "env-disabled").Update("enabled", false).Error)
+
+	statuses, err := svc.RefreshAllConnections(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"env-online":  "online",
+		"env-offline": "offline",
+	}, statuses)
+
+	var offline models.Environment
+	require.NoError(t, db.WithContext(ctx).Where("id = ?", "env-offline").First(&offline).Error)
+	require.Equal(t, string(models.EnvironmentStatusOffline), offline.Status)
+}
+
+func TestEnvironmentService_RefreshAllConnections_SkipsDisallowedEnvironments(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	var hits atomic.Int32
+	onlineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"UP"}`))
+	}))
+	defer onlineServer.Close()
+
+	offlineServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	offlineURL := offlineServer.URL
+	offlineServer.Close()
+
+	createTestEnvironment(t, db, "env-allowed", onlineServer.URL, nil)
+	createTestEnvironment(t, db, "env-denied", offlineURL, nil)
+
+	statuses, err := svc.RefreshAllConnections(ctx, func(id string) bool { return id == "env-allowed" })
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"env-allowed": "online"}, statuses)
+	require.Positive(t, hits.Load())
+
+	// The denied environment was never tested, so its stored status is untouched.
+	var denied models.Environment
+	require.NoError(t, db.WithContext(ctx).Where("id = ?", "env-denied").First(&denied).Error)
+	require.Equal(t, string(models.EnvironmentStatusOnline), denied.Status)
+}
+
 func TestEnvironmentService_ExecuteRemoteRequest_RejectsInvalidEnvironmentURL(t *testing.T) {
 	ctx := context.Background()
 	db := setupEnvironmentServiceTestDB(t)
@@ -1144,3 +1374,86 @@ func TestEnvironmentService_ExecuteRemoteRequest_RejectsInvalidEnvironmentURL(t
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid environment API URL")
 }
+
+func TestEnvironmentService_ExecuteRemoteRequest_RetriesAndOpensCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	db := setupEnvironmentServiceTestDB(t)
+	svc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	createTestEnvironment(t, db, "env-flaky", server.URL, new("agent-token"))
+
+	resp, err := svc.ExecuteRemoteRequest(ctx, "env-flaky", http.MethodGet, "/api/health", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(environmentRetryMaxAttempts), attempts.Load())
+
+	// Non-idempotent requests are not retried on a 503.
+	attempts.Store(0)
+	for range environmentCircuitFailureThreshold - 1 {
+		_, err = svc.ExecuteRemoteRequest(ctx, "env-flaky", http.MethodPost, "/api/environments/0/containers", nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(environmentCircuitFailureThreshold-1), attempts.Load())
+
+	_, err = svc.ExecuteRemoteRequest(ctx, "env-flaky", http.MethodGet, "/api/health", nil)
+	require.ErrorIs(t, err, common.ErrEnvironmentCircuitOpen)
+	require.Equal(t, int32(environmentCircuitFailureThreshold-1), attempts.Load())
+
+	breaker := svc.circuitBreaker.snapshot("env-flaky")
+	require.NotNil(t, breaker)
+	require.Equal(t, environmentCircuitOpen, breaker.State)
+	require.Equal(t, environmentCircuitFailureThreshold, breaker.ConsecutiveFailures)
+}
+
+func TestEnvironmentCircuitBreaker_HalfOpenAllowsOneTrial(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := newEnvironmentCircuitBreakerInternal()
+	breaker.now = func() time.Time { return now }
+
+	for range environmentCircuitFailureThreshold {
+		require.NoError(t, breaker.allow("env-1"))
+		breaker.recordOutcome("env-1", "/api/environments/0/containers", http.StatusServiceUnavailable, nil)
+	}
+	require.ErrorIs(t, breaker.allow("env-1"), common.ErrEnvironmentCircuitOpen)
+
+	now = now.Add(environmentCircuitCooldown)
+	require.NoError(t, breaker.allow("env-1"), "the first request after the cooldown is the trial")
+	require.ErrorIs(t, breaker.allow("env-1"), common.ErrEnvironmentCircuitOpen, "only one trial may run")
+
+	// A canceled trial frees the slot without changing the failure count.
+	breaker.recordOutcome("env-1", "/api/environments/0/containers", 0, context.Canceled)
+	require.NoError(t, breaker.allow("env-1"))
+
+	// A failed trial re-opens the breaker.
+	breaker.recordOutcome("env-1", "/api/environments/0/containers", 0, errors.New("connection refused"))
+	require.ErrorIs(t, breaker.allow("env-1"), common.ErrEnvironmentCircuitOpen)
+
+	now = now.Add(environmentCircuitCooldown)
+	require.NoError(t, breaker.allow("env-1"))
+	breaker.recordOutcome("env-1", "/api/environments/0/containers", http.StatusOK, nil)
+	require.Nil(t, breaker.snapshot("env-1"))
+	require.NoError(t, breaker.allow("env-1"))
+	require.NoError(t, breaker.allow("env-1"))
+}
+
+func TestEnvironmentCircuitBreaker_IgnoresHealthRouteUnavailable(t *testing.T) {
+	breaker := newEnvironmentCircuitBreakerInternal()
+
+	for range environmentCircuitFailureThreshold {
+		breaker.recordOutcome("env-1", "/api/environments/0/system/ready", http.StatusServiceUnavailable, nil)
+		breaker.recordOutcome("env-1", "/api/health", http.StatusServiceUnavailable, nil)
+	}
+	require.Nil(t, breaker.snapshot("env-1"))
+	require.NoError(t, breaker.allow("env-1"))
+
+	breaker.recordOutcome("env-1", "/api/environments/0/system/ready", http.StatusBadGateway, nil)
+	breaker.recordOutcome("env-1", "/api/environments/0/system/ready", 0, errors.New("connection refused"))
+	require.Equal(t, 2, breaker.snapshot("env-1").ConsecutiveFailures)
+}
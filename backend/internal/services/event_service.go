@@ -285,7 +285,24 @@ func copyOptionalStringPtr(value *string) *string {
 	return new(*value)
 }
 
-func (s *EventService) ListEventsPaginated(ctx context.Context, params pagination.QueryParams) ([]event.Event, pagination.Response, error) {
+// EventQueryFilter narrows an event query. Empty fields are ignored; string
+// fields accept comma-separated values that are OR-ed together. From and To
+// bound the event timestamp inclusively.
+type EventQueryFilter struct {
+	Severity      string
+	Type          string
+	ResourceType  string
+	ResourceID    string
+	UserID        string
+	Username      string
+	EnvironmentID string
+	From          *time.Time
+	To            *time.Time
+}
+
+// QueryEvents returns the events matching filter and the search term in
+// params, sorted and paginated by params. A limit of -1 returns every match.
+func (s *EventService) QueryEvents(ctx context.Context, filter EventQueryFilter, params pagination.QueryParams) ([]event.Event, pagination.Response, error) {
 	var events []models.Event
 	q := s.db.WithContext(ctx).Model(&models.Event{})
 
@@ -297,11 +314,19 @@ func (s *EventService) ListEventsPaginated(ctx context.Context, params paginatio
 		)
 	}
 
-	q = pagination.ApplyFilter(q, "severity", params.Filters["severity"])
-	q = applyEventTypeFilter(q, params.Filters["type"])
-	q = pagination.ApplyFilter(q, "resource_type", params.Filters["resourceType"])
-	q = pagination.ApplyFilter(q, "username", params.Filters["username"])
-	q = pagination.ApplyFilter(q, "environment_id", params.Filters["environmentId"])
+	q = pagination.ApplyFilter(q, "severity", filter.Severity)
+	q = applyEventTypeFilter(q, filter.Type)
+	q = pagination.ApplyFilter(q, "resource_type", filter.ResourceType)
+	q = pagination.ApplyFilter(q, "resource_id", filter.ResourceID)
+	q = pagination.ApplyFilter(q, "user_id", filter.UserID)
+	q = pagination.ApplyFilter(q, "username", filter.Username)
+	q = pagination.ApplyFilter(q, "environment_id", filter.EnvironmentID)
+	if filter.From != nil {
+		q = q.Where("timestamp >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("timestamp <= ?", *filter.To)
+	}
 
 	paginationResp, err := pagination.PaginateAndSortDB(params, q, &events)
 	if err != nil {
@@ -316,34 +341,31 @@ func (s *EventService) ListEventsPaginated(ctx context.Context, params paginatio
 	return eventDtos, paginationResp, nil
 }
 
-func (s *EventService) GetEventsByEnvironmentPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]event.Event, pagination.Response, error) {
-	var events []models.Event
-	q := s.db.WithContext(ctx).Model(&models.Event{}).Where("environment_id = ?", environmentID)
-
-	if term := strings.TrimSpace(params.Search); term != "" {
-		searchPattern := "%" + term + "%"
-		q = q.Where(
-			"title LIKE ? OR description LIKE ? OR COALESCE(resource_name, '') LIKE ? OR COALESCE(username, '') LIKE ?",
-			searchPattern, searchPattern, searchPattern, searchPattern,
-		)
-	}
-
-	q = pagination.ApplyFilter(q, "severity", params.Filters["severity"])
-	q = applyEventTypeFilter(q, params.Filters["type"])
-	q = pagination.ApplyFilter(q, "resource_type", params.Filters["resourceType"])
-	q = pagination.ApplyFilter(q, "username", params.Filters["username"])
-
-	paginationResp, err := pagination.PaginateAndSortDB(params, q, &events)
-	if err != nil {
-		return nil, pagination.Response{}, errors.WrapIf(err, "failed to paginate events")
-	}
-
-	eventDtos, mapErr := mapper.MapSlice[models.Event, event.Event](events)
-	if mapErr != nil {
-		return nil, pagination.Response{}, errors.WrapIf(mapErr, "failed to map events")
+// EventCSVConfig describes the columns of an event CSV export: one row per
+// event with the actor, resource and environment spelled out.
+func EventCSVConfig() pagination.Config[event.Event] {
+	deref := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	return pagination.Config[event.Event]{
+		SearchAccessors: []pagination.SearchAccessor[event.Event]{
+			func(e event.Event) (string, error) { return e.Timestamp.UTC().Format(time.RFC3339), nil },
+			func(e event.Event) (string, error) { return e.Type, nil },
+			func(e event.Event) (string, error) { return e.Severity, nil },
+			func(e event.Event) (string, error) { return e.Title, nil },
+			func(e event.Event) (string, error) { return e.Description, nil },
+			func(e event.Event) (string, error) { return deref(e.ResourceType), nil },
+			func(e event.Event) (string, error) { return deref(e.ResourceID), nil },
+			func(e event.Event) (string, error) { return deref(e.ResourceName), nil },
+			func(e event.Event) (string, error) { return deref(e.UserID), nil },
+			func(e event.Event) (string, error) { return deref(e.Username), nil },
+			func(e event.Event) (string, error) { return deref(e.EnvironmentID), nil },
+		},
+		CSVHeaders: []string{"Timestamp", "Type", "Severity", "Title", "Description", "Resource Type", "Resource ID", "Resource Name", "User ID", "Username", "Environment ID"},
 	}
-
-	return eventDtos, paginationResp, nil
 }
 
 // applyEventTypeFilter filters by event type. Values containing a '.' are
@@ -435,10 +457,16 @@ func (s *EventService) DeleteEvent(ctx context.Context, eventID string) error {
 	})
 }
 
-func (s *EventService) DeleteOldEvents(ctx context.Context, olderThan time.Duration) error {
+// DeleteOldEvents deletes events older than olderThan. Events of excludeTypes
+// are kept because they follow their own retention.
+func (s *EventService) DeleteOldEvents(ctx context.Context, olderThan time.Duration, excludeTypes ...models.EventType) error {
 	cutoff := time.Now().Add(-olderThan)
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		result := tx.Where("timestamp < ?", cutoff).Delete(&models.Event{})
+		q := tx.Where("timestamp < ?", cutoff)
+		if len(excludeTypes) > 0 {
+			q = q.Where("type NOT IN ?", excludeTypes)
+		}
+		result := q.Delete(&models.Event{})
 		if result.Error != nil {
 			return errors.WrapIf(result.Error, "failed to delete old events")
 		}
@@ -650,6 +678,7 @@ var eventDefinitions = map[models.EventType]eventDefinition{
 	models.EventTypeContainerScan:    {"Container scanned: %s", "Security scan completed for container '%s'", models.EventSeverityInfo},
 	models.EventTypeContainerUpdate:  {"Container updated: %s", "Container '%s' has been updated", models.EventSeverityInfo},
 	models.EventTypeContainerError:   {"Container error: %s", "An error occurred with container '%s'", models.EventSeverityError},
+	models.EventTypeContainerExec:    {"Command run in container: %s", "A command was run in container '%s'", models.EventSeverityInfo},
 
 	models.EventTypeImagePull:   {"Image pulled: %s", "Image '%s' has been pulled", models.EventSeveritySuccess},
 	models.EventTypeImageLoad:   {"Image loaded: %s", "Image '%s' has been loaded from archive", models.EventSeveritySuccess},
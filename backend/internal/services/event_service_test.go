@@ -304,7 +304,7 @@ func TestEventService_GetEventSeverityCounts(t *testing.T) {
 	require.Equal(t, EventSeverityCounts{Total: 4, Info: 2, Success: 1, Warning: 0, Error: 1}, counts)
 }
 
-func TestEventService_ListEventsPaginated_TypeCategoryFilter(t *testing.T) {
+func TestEventService_QueryEvents_TypeCategoryFilter(t *testing.T) {
 	ctx := context.Background()
 	db := setupEventServiceTestDB(t)
 	svc := NewEventService(db, nil, nil)
@@ -319,9 +319,8 @@ func TestEventService_ListEventsPaginated_TypeCategoryFilter(t *testing.T) {
 	}
 
 	listWithTypeFilter := func(value string) []string {
-		events, _, err := svc.ListEventsPaginated(ctx, pagination.QueryParams{
-			Params:  pagination.Params{Limit: 10},
-			Filters: map[string]string{"type": value},
+		events, _, err := svc.QueryEvents(ctx, EventQueryFilter{Type: value}, pagination.QueryParams{
+			Params: pagination.Params{Limit: 10},
 		})
 		require.NoError(t, err)
 		types := make([]string, 0, len(events))
@@ -335,3 +334,34 @@ func TestEventService_ListEventsPaginated_TypeCategoryFilter(t *testing.T) {
 	require.ElementsMatch(t, []string{"image.pull"}, listWithTypeFilter("image.pull"))
 	require.ElementsMatch(t, []string{"container.start", "container.stop", "image.pull"}, listWithTypeFilter("container,image.pull"))
 }
+
+func TestEventService_QueryEvents_AuditFilters(t *testing.T) {
+	ctx := context.Background()
+	db := setupEventServiceTestDB(t)
+	svc := NewEventService(db, nil, nil)
+
+	now := time.Now()
+	for _, e := range []models.Event{
+		{Type: models.EventTypeProjectDelete, Title: "old delete", ResourceType: new("project"), ResourceID: new("stack-1"), Username: new("alice"), EnvironmentID: new("env-a"), Timestamp: now.Add(-48 * time.Hour)},
+		{Type: models.EventTypeProjectDelete, Title: "recent delete", ResourceType: new("project"), ResourceID: new("stack-1"), Username: new("bob"), EnvironmentID: new("env-a"), Timestamp: now.Add(-time.Hour)},
+		{Type: models.EventTypeContainerStop, Title: "stop", ResourceType: new("container"), ResourceID: new("c-1"), Username: new("bob"), EnvironmentID: new("env-b"), Timestamp: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, db.WithContext(ctx).Create(&e).Error)
+	}
+
+	titles := func(filter EventQueryFilter) []string {
+		events, _, err := svc.QueryEvents(ctx, filter, pagination.QueryParams{Params: pagination.Params{Limit: -1}})
+		require.NoError(t, err)
+		out := make([]string, 0, len(events))
+		for _, e := range events {
+			out = append(out, e.Title)
+		}
+		return out
+	}
+
+	require.ElementsMatch(t, []string{"old delete", "recent delete"}, titles(EventQueryFilter{ResourceType: "project", ResourceID: "stack-1"}))
+	require.ElementsMatch(t, []string{"recent delete", "stop"}, titles(EventQueryFilter{Username: "bob"}))
+	require.ElementsMatch(t, []string{"stop"}, titles(EventQueryFilter{Username: "bob", EnvironmentID: "env-b"}))
+	require.ElementsMatch(t, []string{"recent delete", "stop"}, titles(EventQueryFilter{From: new(now.Add(-24 * time.Hour))}))
+	require.ElementsMatch(t, []string{"old delete"}, titles(EventQueryFilter{To: new(now.Add(-24 * time.Hour))}))
+}
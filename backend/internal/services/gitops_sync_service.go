@@ -427,6 +427,10 @@ func (s *GitOpsSyncService) runScheduledSyncInternal(ctx context.Context, enviro
 	if !sync.AutoSync {
 		return
 	}
+	if err := s.settingsService.CheckMaintenance(ctx, false); err != nil {
+		slog.InfoContext(ctx, "gitops auto-sync skipped; environment is in maintenance mode", "syncId", syncID, "reason", err)
+		return
+	}
 	if _, err := s.PerformSync(ctx, environmentID, syncID, systemUser); err != nil {
 		slog.ErrorContext(ctx, "gitops auto-sync run failed", "syncId", syncID, "error", err)
 	}
@@ -456,6 +460,10 @@ func (s *GitOpsSyncService) unregisterSyncJobInternal(ctx context.Context, syncI
 func (s *GitOpsSyncService) kickSyncInternal(ctx context.Context, syncID, environmentID string) {
 	ctx = s.schedulerCtxInternal(ctx)
 	go func() {
+		if err := s.settingsService.CheckMaintenance(ctx, false); err != nil {
+			slog.InfoContext(ctx, "gitops immediate sync kick skipped; environment is in maintenance mode", "syncId", syncID, "reason", err)
+			return
+		}
 		if _, err := s.PerformSync(ctx, environmentID, syncID, systemUser); err != nil {
 			slog.ErrorContext(ctx, "gitops immediate sync kick failed", "syncId", syncID, "error", err)
 		}
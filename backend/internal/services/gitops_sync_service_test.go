@@ -15,6 +15,7 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	git "github.com/getarcaneapp/arcane/backend/v2/pkg/gitutil"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
 	"github.com/getarcaneapp/arcane/types/v2/gitops"
 	schedulertypes "github.com/getarcaneapp/arcane/types/v2/scheduler"
 	"github.com/stretchr/testify/assert"
@@ -280,6 +281,31 @@ func TestGitOpsSyncService_RunScheduledSync_UnregistersMissingSync(t *testing.T)
 	assert.Contains(t, scheduler.removed, gitOpsSyncJobNameInternal("ghost-sync"))
 }
 
+// TestGitOpsSyncService_RunScheduledSync_SkipsInMaintenance verifies a scheduled
+// fire leaves an auto-sync untouched while the environment is in maintenance mode.
+func TestGitOpsSyncService_RunScheduledSync_SkipsInMaintenance(t *testing.T) {
+	ctx := context.Background()
+	svc, db, _ := setupGitOpsSyncDirectoryTestService(t)
+	require.NoError(t, db.Create(&models.GitOpsSync{
+		BaseModel:     models.BaseModel{ID: "sync-maintenance"},
+		Name:          "maintenance",
+		EnvironmentID: "0",
+		RepositoryID:  "repo-1",
+		ComposePath:   "compose.yml",
+		ProjectName:   "maintenance",
+		SyncInterval:  15,
+		AutoSync:      true,
+	}).Error)
+	_, err := svc.settingsService.SetMaintenance(ctx, environment.MaintenanceUpdate{Enabled: true})
+	require.NoError(t, err)
+
+	svc.runScheduledSyncInternal(ctx, "0", "sync-maintenance")
+
+	var got models.GitOpsSync
+	require.NoError(t, db.Where("id = ?", "sync-maintenance").First(&got).Error)
+	assert.Nil(t, got.LastSyncStatus)
+}
+
 // TestGitOpsSyncService_CleanupLeakedScratchDirsOnStartup_RemovesOrphans verifies the
 // startup sweep removes leaked gitops scratch dirs (hidden and legacy name-embedded
 // forms) while leaving real project directories untouched.
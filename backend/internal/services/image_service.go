@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"encoding/json/jsontext"
+	json "encoding/json/v2"
 	"fmt"
 	"io"
 	"log/slog"
@@ -15,6 +17,7 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	dockerutils "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
 	utilsregistry "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/registryauth"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/timeouts"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/types/v2/containerregistry"
 	imagetypes "github.com/getarcaneapp/arcane/types/v2/image"
@@ -23,6 +26,7 @@ import (
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/jsonmessage"
 	"github.com/samber/hot"
 	"github.com/samber/mo"
 	"golang.org/x/sync/errgroup"
@@ -36,11 +40,12 @@ type ImageService struct {
 	registryService      *ContainerRegistryService
 	vulnerabilityService *VulnerabilityService
 	eventService         *EventService
+	settingsService      *SettingsService
 
 	projectIDCache *hot.HotCache[struct{}, map[string]string]
 }
 
-func NewImageService(db *database.DB, dockerService *DockerClientService, registryService *ContainerRegistryService, imageUpdateService *ImageUpdateService, vulnerabilityService *VulnerabilityService, eventService *EventService) *ImageService {
+func NewImageService(db *database.DB, dockerService *DockerClientService, registryService *ContainerRegistryService, imageUpdateService *ImageUpdateService, vulnerabilityService *VulnerabilityService, eventService *EventService, settingsService *SettingsService) *ImageService {
 	return &ImageService{
 		db:                   db,
 		dockerService:        dockerService,
@@ -48,6 +53,7 @@ func NewImageService(db *database.DB, dockerService *DockerClientService, regist
 		imageUpdateService:   imageUpdateService,
 		vulnerabilityService: vulnerabilityService,
 		eventService:         eventService,
+		settingsService:      settingsService,
 		projectIDCache: hot.NewHotCache[struct{}, map[string]string](hot.LRU, 1).
 			WithTTL(projectIDCacheTTL).
 			Build(),
@@ -174,24 +180,8 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string, progress
 
 	slog.DebugContext(ctx, "Attempting to pull image", "image", imageName, "externalCredCount", len(externalCreds))
 
-	pullOptions, err := s.getPullOptionsWithAuth(ctx, imageName, externalCreds)
+	reader, pulledRef, err := s.openImagePullInternal(ctx, dockerClient, imageName, externalCreds)
 	if err != nil {
-		slog.WarnContext(ctx, "Failed to get registry authentication for image; proceeding without auth", "image", imageName, "error", err.Error())
-		pullOptions = client.ImagePullOptions{}
-	}
-
-	initialHasAuth := pullOptions.RegistryAuth != ""
-	retriedWithoutAuth := false
-
-	reader, err := dockerClient.ImagePull(ctx, imageName, pullOptions)
-	if err != nil && shouldRetryAnonymousPullInternal(pullOptions, err) {
-		retriedWithoutAuth = true
-		slog.WarnContext(ctx, "Docker ImagePull failed with registry auth; retrying anonymously", "image", imageName, "error", err.Error())
-		pullOptions = client.ImagePullOptions{}
-		reader, err = dockerClient.ImagePull(ctx, imageName, pullOptions)
-	}
-	if err != nil {
-		slog.ErrorContext(ctx, "Docker ImagePull failed", "image", imageName, "hasAuth", pullOptions.RegistryAuth != "", "initialHasAuth", initialHasAuth, "retriedWithoutAuth", retriedWithoutAuth, "error", err.Error())
 		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "pull"})
 		return errors.WrapIff(err, "failed to initiate image pull for %s", imageName)
 	}
@@ -212,6 +202,11 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string, progress
 
 	slog.Debug("image pull stream completed", "image", imageName)
 
+	if err := retagMirroredPullInternal(ctx, dockerClient, pulledRef, imageName); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "pull", "step": "retag_mirror", "mirror": pulledRef})
+		return err
+	}
+
 	metadata := models.JSON{
 		"action":    "pull",
 		"imageName": imageName,
@@ -228,6 +223,162 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string, progress
 	return nil
 }
 
+// PullWithProgress pulls imageName and sends one PullProgress per Docker
+// progress message to progressChan, which may be nil. The pull is bounded by
+// the DockerImagePullTimeout setting, and a pull rejected with registry
+// credentials is retried anonymously, as in PullImage.
+func (s *ImageService) PullWithProgress(ctx context.Context, imageName string, credentials []containerregistry.Credential, progressChan chan<- imagetypes.PullProgress) error {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	pullTimeout := 0
+	if s.settingsService != nil {
+		pullTimeout = s.settingsService.GetSettingsConfig().DockerImagePullTimeout.AsInt()
+	}
+	pullCtx, pullCancel := timeouts.WithTimeout(ctx, pullTimeout, timeouts.DefaultDockerImagePull)
+	defer pullCancel()
+
+	reader, pulledRef, err := s.openImagePullInternal(pullCtx, dockerClient, imageName, credentials)
+	if err == nil {
+		defer func() { _ = reader.Close() }()
+		err = decodePullProgressInternal(pullCtx, reader, progressChan)
+	}
+	if err == nil {
+		err = retagMirroredPullInternal(pullCtx, dockerClient, pulledRef, imageName)
+	}
+	if err != nil {
+		if errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
+			return errors.WrapIff(pullCtx.Err(), "image pull timed out for %s (increase DOCKER_IMAGE_PULL_TIMEOUT or setting)", imageName)
+		}
+		return errors.WrapIff(err, "failed to pull image %s", imageName)
+	}
+
+	if s.registryService != nil {
+		if err := s.registryService.RecordImagePull(ctx, imageName); err != nil {
+			slog.WarnContext(ctx, "failed to record registry pull count", "image", imageName, "error", err)
+		}
+	}
+	return nil
+}
+
+// openImagePullInternal starts an image pull and returns the reference actually
+// being pulled. When the registryMirrors setting has a mirror for the image's
+// registry the pull goes through it, falling back to the source registry if the
+// mirror cannot be reached or refuses the pull; callers then pass the returned
+// reference to retagMirroredPullInternal once the stream completes.
+func (s *ImageService) openImagePullInternal(ctx context.Context, dockerClient *client.Client, imageName string, credentials []containerregistry.Credential) (io.ReadCloser, string, error) {
+	if mirrorRef, ok := s.mirrorReferenceInternal(ctx, imageName); ok {
+		reader, err := s.openImagePullFromInternal(ctx, dockerClient, mirrorRef, credentials)
+		if err == nil {
+			slog.DebugContext(ctx, "Pulling image through registry mirror", "image", imageName, "mirror", mirrorRef)
+			return reader, mirrorRef, nil
+		}
+		slog.WarnContext(ctx, "Registry mirror pull failed; pulling from the source registry", "image", imageName, "mirror", mirrorRef, "error", err.Error())
+	}
+
+	reader, err := s.openImagePullFromInternal(ctx, dockerClient, imageName, credentials)
+	return reader, imageName, err
+}
+
+// mirrorReferenceInternal returns the mirror reference for imageName from the
+// registryMirrors setting, if one is configured.
+func (s *ImageService) mirrorReferenceInternal(ctx context.Context, imageName string) (string, bool) {
+	if s.settingsService == nil {
+		return "", false
+	}
+	mirrors, err := utilsregistry.ParseMirrors(s.settingsService.GetSettingsConfig().RegistryMirrors.Value)
+	if err != nil {
+		slog.WarnContext(ctx, "Ignoring invalid registryMirrors setting", "error", err.Error())
+		return "", false
+	}
+	return utilsregistry.MirrorReference(imageName, mirrors)
+}
+
+// retagMirroredPullInternal gives an image pulled through a mirror its original
+// name and drops the mirror tag, so it is listed and run under the name it was
+// requested by. It does nothing when the image was pulled directly.
+func retagMirroredPullInternal(ctx context.Context, dockerClient *client.Client, pulledRef, imageName string) error {
+	if pulledRef == imageName {
+		return nil
+	}
+	target := imageName
+	if named, err := ref.ParseNormalizedNamed(imageName); err == nil {
+		target = ref.FamiliarString(ref.TagNameOnly(named))
+	}
+
+	if _, err := dockerClient.ImageTag(ctx, client.ImageTagOptions{Source: pulledRef, Target: target}); err != nil {
+		return errors.WrapIff(err, "failed to tag mirrored image %s as %s", pulledRef, target)
+	}
+	if _, err := dockerClient.ImageRemove(ctx, pulledRef, client.ImageRemoveOptions{}); err != nil {
+		slog.WarnContext(ctx, "Failed to remove registry mirror tag after pull", "mirror", pulledRef, "image", target, "error", err.Error())
+	}
+	return nil
+}
+
+// openImagePullFromInternal starts pulling imageName with the best registry
+// credentials for its host, falling back to an anonymous pull when the registry
+// rejects them.
+func (s *ImageService) openImagePullFromInternal(ctx context.Context, dockerClient *client.Client, imageName string, credentials []containerregistry.Credential) (io.ReadCloser, error) {
+	pullOptions, err := s.getPullOptionsWithAuth(ctx, imageName, credentials)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to get registry authentication for image; proceeding without auth", "image", imageName, "error", err.Error())
+		pullOptions = client.ImagePullOptions{}
+	}
+
+	initialHasAuth := pullOptions.RegistryAuth != ""
+	retriedWithoutAuth := false
+
+	reader, err := dockerClient.ImagePull(ctx, imageName, pullOptions)
+	if err != nil && shouldRetryAnonymousPullInternal(pullOptions, err) {
+		retriedWithoutAuth = true
+		slog.WarnContext(ctx, "Docker ImagePull failed with registry auth; retrying anonymously", "image", imageName, "error", err.Error())
+		pullOptions = client.ImagePullOptions{}
+		reader, err = dockerClient.ImagePull(ctx, imageName, pullOptions)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Docker ImagePull failed", "image", imageName, "hasAuth", pullOptions.RegistryAuth != "", "initialHasAuth", initialHasAuth, "retriedWithoutAuth", retriedWithoutAuth, "error", err.Error())
+		return nil, err
+	}
+	return reader, nil
+}
+
+// decodePullProgressInternal reads a Docker JSON message stream, forwarding
+// each status message to progressChan and returning the first error the
+// daemon reports.
+func decodePullProgressInternal(ctx context.Context, stream io.Reader, progressChan chan<- imagetypes.PullProgress) error {
+	decoder := jsontext.NewDecoder(stream)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := json.UnmarshalDecode(decoder, &msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return errors.WrapIf(err, "failed to decode image pull stream")
+		}
+		if msg.Error != nil {
+			return errors.New(msg.Error.Message)
+		}
+		if msg.Status == "" || progressChan == nil {
+			continue
+		}
+
+		progress := imagetypes.PullProgress{ID: msg.ID, Status: msg.Status}
+		if msg.Progress != nil {
+			progress.Current, progress.Total = msg.Progress.Current, msg.Progress.Total
+		}
+		select {
+		case progressChan <- progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func (s *ImageService) ReconcilePulledImageUpdate(ctx context.Context, imageName string) error {
 	if s.imageUpdateService == nil {
 		return nil
@@ -716,7 +867,7 @@ func (s *ImageService) ListImagesPaginated(ctx context.Context, params paginatio
 
 	items := mapDockerImagesToDTOs(dockerImages, usageMap, updateMap, nil)
 
-	config := s.getImagePaginationConfig()
+	config := s.ImagePaginationConfig()
 
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 
@@ -1106,7 +1257,7 @@ func mapDockerImagesToDTOs(dockerImages []image.Summary, usageMap map[string][]i
 	return items
 }
 
-func (s *ImageService) getImagePaginationConfig() pagination.Config[imagetypes.Summary] {
+func (s *ImageService) ImagePaginationConfig() pagination.Config[imagetypes.Summary] {
 	return pagination.Config[imagetypes.Summary]{
 		SearchAccessors: []pagination.SearchAccessor[imagetypes.Summary]{
 			func(i imagetypes.Summary) (string, error) { return i.Repo, nil },
@@ -1205,5 +1356,9 @@ func (s *ImageService) getImagePaginationConfig() pagination.Config[imagetypes.S
 				},
 			},
 		},
+		RangeBindings: []pagination.RangeBinding[imagetypes.Summary]{
+			{Key: "size", Number: func(i imagetypes.Summary) float64 { return float64(i.Size) }},
+			{Key: "created", Time: func(i imagetypes.Summary) time.Time { return time.Unix(i.Created, 0) }},
+		},
 	}
 }
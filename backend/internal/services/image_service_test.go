@@ -41,6 +41,29 @@ func TestGetImageIDsFromSummariesInternal(t *testing.T) {
 	assert.Equal(t, []string{"img1", "img2"}, got)
 }
 
+func TestDecodePullProgressInternal(t *testing.T) {
+	stream := strings.NewReader(`{"status":"Pulling from library/nginx","id":"latest"}
+{"status":"Downloading","id":"abc123","progressDetail":{"current":512,"total":2048}}
+{"status":"Pull complete","id":"abc123"}
+`)
+	progressChan := make(chan imagetypes.PullProgress, 8)
+	require.NoError(t, decodePullProgressInternal(context.Background(), stream, progressChan))
+	close(progressChan)
+
+	var got []imagetypes.PullProgress
+	for progress := range progressChan {
+		got = append(got, progress)
+	}
+	require.Equal(t, []imagetypes.PullProgress{
+		{ID: "latest", Status: "Pulling from library/nginx"},
+		{ID: "abc123", Status: "Downloading", Current: 512, Total: 2048},
+		{ID: "abc123", Status: "Pull complete"},
+	}, got)
+
+	err := decodePullProgressInternal(context.Background(), strings.NewReader(`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`), nil)
+	require.ErrorContains(t, err, "manifest unknown")
+}
+
 func TestApplyVulnerabilitySummariesToItemsInternal(t *testing.T) {
 	items := []imagetypes.Summary{
 		{ID: "img1"},
@@ -219,7 +242,7 @@ func TestImageServicePullImageRetriesAnonymouslyAfterAuthRejectedInternal(t *tes
 	t.Cleanup(server.Close)
 
 	dockerService := &DockerClientService{client: newTestDockerClient(t, server)}
-	imageSvc := NewImageService(db, dockerService, nil, nil, nil, NewEventService(db, nil, nil))
+	imageSvc := NewImageService(db, dockerService, nil, nil, nil, NewEventService(db, nil, nil), nil)
 
 	err := imageSvc.PullImage(context.Background(), "registry.example.com/team/app:latest", io.Discard, systemUser, []containerregistry.Credential{
 		{URL: "https://registry.example.com", Username: "external-user", Token: "external-token", Enabled: true},
@@ -244,7 +267,7 @@ func TestImageServiceTagImageCallsDockerAPIInternal(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil))
+	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil), nil)
 
 	err := imageSvc.TagImage(context.Background(), "source:latest", imagetypes.TagRequest{Repository: "registry.example.com/team/app", Tag: "v2"}, systemUser)
 	require.NoError(t, err)
@@ -265,7 +288,7 @@ func TestImageServiceGetImageHistoryCallsDockerAPIInternal(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil))
+	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil), nil)
 
 	history, err := imageSvc.GetImageHistory(context.Background(), "source:latest")
 	require.NoError(t, err)
@@ -275,7 +298,7 @@ func TestImageServiceGetImageHistoryCallsDockerAPIInternal(t *testing.T) {
 }
 
 func TestImageServiceSearchImagesRequiresTermInternal(t *testing.T) {
-	imageSvc := NewImageService(nil, &DockerClientService{}, nil, nil, nil, nil)
+	imageSvc := NewImageService(nil, &DockerClientService{}, nil, nil, nil, nil, nil)
 
 	_, err := imageSvc.SearchImages(context.Background(), " ")
 	require.Error(t, err)
@@ -295,7 +318,7 @@ func TestImageServiceExportImageReturnsTarStreamInternal(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil))
+	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil), nil)
 
 	reader, err := imageSvc.ExportImage(context.Background(), "source:latest")
 	require.NoError(t, err)
@@ -320,7 +343,7 @@ func TestImageServiceSearchImagesCallsDockerAPIInternal(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil))
+	imageSvc := NewImageService(db, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, NewEventService(db, nil, nil), nil)
 
 	results, err := imageSvc.SearchImages(context.Background(), "nginx")
 	require.NoError(t, err)
@@ -1522,7 +1522,7 @@ func TestImageUpdateService_SendBatchNotifications_DetachesCanceledContext(t *te
 		},
 	}).Error)
 
-	notif := NewNotificationService(db, nil, nil, nil)
+	notif := NewNotificationService(db, nil, nil, nil, nil)
 	svc := NewImageUpdateService(db, nil, nil, nil, nil, notif, nil)
 
 	rec := models.ImageUpdateRecord{
@@ -1561,7 +1561,7 @@ func TestImageUpdateService_SendBatchNotifications_NoEligibleProviders_LeavesUnn
 	db := setupImageUpdateTestDB(t)
 	require.NoError(t, db.AutoMigrate(&models.NotificationSettings{}))
 
-	notif := NewNotificationService(db, nil, nil, nil)
+	notif := NewNotificationService(db, nil, nil, nil, nil)
 	svc := NewImageUpdateService(db, nil, nil, nil, nil, notif, nil)
 
 	rec := models.ImageUpdateRecord{
@@ -1613,7 +1613,7 @@ func TestImageUpdateService_SendBatchNotifications_PartialFailureStillMarksNotif
 		}).Error)
 	}
 
-	notif := NewNotificationService(db, nil, nil, nil)
+	notif := NewNotificationService(db, nil, nil, nil, nil)
 	svc := NewImageUpdateService(db, nil, nil, nil, nil, notif, nil)
 
 	rec := models.ImageUpdateRecord{
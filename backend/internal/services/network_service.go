@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/netip"
 	"sort"
 	"strings"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	dockerutil "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
@@ -16,6 +19,7 @@ import (
 	networktypes "github.com/getarcaneapp/arcane/types/v2/network"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/client"
 	"golang.org/x/sync/errgroup"
 )
@@ -24,6 +28,7 @@ type NetworkService struct {
 	db            *database.DB
 	dockerService *DockerClientService
 	eventService  *EventService
+	swarmService  *SwarmService
 }
 
 func NewNetworkService(db *database.DB, dockerService *DockerClientService, eventService *EventService) *NetworkService {
@@ -34,6 +39,21 @@ func NewNetworkService(db *database.DB, dockerService *DockerClientService, even
 	}
 }
 
+// WithSwarmService lets network mutations drop the swarm service's cached
+// network list, so swarm views see new and removed networks immediately.
+func (s *NetworkService) WithSwarmService(swarmService *SwarmService) *NetworkService {
+	s.swarmService = swarmService
+	return s
+}
+
+// invalidateSwarmMetadataInternal drops the swarm service's cached network
+// list for the local environment after a network mutation.
+func (s *NetworkService) invalidateSwarmMetadataInternal() {
+	if s.swarmService != nil {
+		s.swarmService.InvalidateSwarmMetadata(localEnvironmentID)
+	}
+}
+
 func (s *NetworkService) GetNetworkByID(ctx context.Context, id string) (*network.Inspect, error) {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
@@ -48,6 +68,87 @@ func (s *NetworkService) GetNetworkByID(ctx context.Context, id string) (*networ
 	return new(networkInspect.Network), nil
 }
 
+// GetNetworkUsage lists the containers attached to a network and, for
+// swarm-scoped networks, the swarm services that reference it by ID or name.
+// Services are skipped when this node cannot list them because it is not a
+// swarm manager.
+func (s *NetworkService) GetNetworkUsage(ctx context.Context, id string) (*networktypes.Usage, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	inspected, err := libarcane.NetworkInspectWithCompatibility(ctx, dockerClient, id, client.NetworkInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect network")
+	}
+	networkInfo := inspected.Network
+
+	usage := &networktypes.Usage{
+		NetworkID:   networkInfo.ID,
+		NetworkName: networkInfo.Name,
+		Scope:       networkInfo.Scope,
+		Containers:  networkContainerEndpointsInternal(networkInfo.Containers),
+		Services:    []networktypes.ServiceAttachment{},
+	}
+
+	if networkInfo.Scope == "swarm" {
+		servicesResult, err := dockerClient.ServiceList(ctx, client.ServiceListOptions{})
+		switch {
+		case err == nil:
+			usage.Services = networkServiceAttachmentsInternal(servicesResult.Items, networkInfo.ID, networkInfo.Name)
+		case cerrdefs.IsUnavailable(err):
+			slog.DebugContext(ctx, "Skipping swarm services in network usage; node is not a swarm manager", "network", networkInfo.Name)
+		default:
+			return nil, errors.WrapIf(err, "failed to list swarm services")
+		}
+	}
+
+	usage.InUse = len(usage.Containers) > 0 || len(usage.Services) > 0
+	return usage, nil
+}
+
+func networkContainerEndpointsInternal(endpoints map[string]network.EndpointResource) []networktypes.ContainerEndpoint {
+	out := make([]networktypes.ContainerEndpoint, 0, len(endpoints))
+	for containerID, endpoint := range endpoints {
+		out = append(out, networktypes.NewContainerEndpoint(containerID, endpoint))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// networkServiceAttachmentsInternal returns the services whose task template
+// attaches the network, or whose endpoint holds a virtual IP on it, as with
+// the ingress network for published ports.
+func networkServiceAttachmentsInternal(services []swarm.Service, networkID, networkName string) []networktypes.ServiceAttachment {
+	out := []networktypes.ServiceAttachment{}
+	for _, service := range services {
+		var attachment *networktypes.ServiceAttachment
+		for _, cfg := range service.Spec.TaskTemplate.Networks {
+			if cfg.Target != networkID && cfg.Target != networkName {
+				continue
+			}
+			if attachment == nil {
+				attachment = &networktypes.ServiceAttachment{ServiceID: service.ID, ServiceName: service.Spec.Name}
+			}
+			attachment.Aliases = append(attachment.Aliases, cfg.Aliases...)
+		}
+		if attachment == nil {
+			for _, vip := range service.Endpoint.VirtualIPs {
+				if vip.NetworkID == networkID {
+					attachment = &networktypes.ServiceAttachment{ServiceID: service.ID, ServiceName: service.Spec.Name}
+					break
+				}
+			}
+		}
+		if attachment != nil {
+			out = append(out, *attachment)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ServiceName < out[j].ServiceName })
+	return out
+}
+
 func (s *NetworkService) GetNetworkTopology(ctx context.Context) (*networktypes.Topology, error) {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
@@ -171,13 +272,31 @@ func (s *NetworkService) GetNetworkTopology(ctx context.Context) (*networktypes.
 	return topology, nil
 }
 
-func (s *NetworkService) CreateNetwork(ctx context.Context, name string, options client.NetworkCreateOptions, user models.User) (*network.CreateResponse, error) {
+// CreateNetwork validates the requested IPAM configuration against itself and
+// the networks that already exist, then creates the network. Malformed or
+// conflicting subnets are rejected with a field-level validation error before
+// Docker is called.
+func (s *NetworkService) CreateNetwork(ctx context.Context, req networktypes.CreateRequest, user models.User) (*network.CreateResponse, error) {
+	defer s.invalidateSwarmMetadataInternal()
+
+	name := req.Name
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeNetworkError, "network", "", name, user.ID, user.Username, "0", err, models.JSON{"action": "create", "driver": options.Driver})
+		s.eventService.LogErrorEvent(ctx, models.EventTypeNetworkError, "network", "", name, user.ID, user.Username, "0", err, models.JSON{"action": "create", "driver": req.Options.Driver})
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
+	if req.Options.IPAM != nil && len(req.Options.IPAM.Config) > 0 {
+		networkList, err := libarcane.NetworkListWithCompatibility(ctx, dockerClient, client.NetworkListOptions{})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list Docker networks")
+		}
+		if err := validateNetworkIPAMInternal(req.Options, networkList.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	options := req.Options.ToDockerCreateOptions()
 	response, err := dockerClient.NetworkCreate(ctx, name, options)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeNetworkError, "network", "", name, user.ID, user.Username, "0", err, models.JSON{"action": "create", "driver": options.Driver})
@@ -206,7 +325,99 @@ func (s *NetworkService) CreateNetwork(ctx context.Context, name string, options
 	return &out, nil
 }
 
+// validateNetworkIPAMInternal parses every IPAM pool of a create request and
+// checks that it is a canonical CIDR, that its gateway, IP range and auxiliary
+// addresses fall inside it, and that it overlaps neither another requested pool
+// nor a subnet of an existing network.
+func validateNetworkIPAMInternal(options networktypes.CreateOptions, existing []network.Summary) error {
+	if options.IPAM == nil {
+		return nil
+	}
+
+	requested := make([]netip.Prefix, 0, len(options.IPAM.Config))
+	for i, cfg := range options.IPAM.Config {
+		field := fmt.Sprintf("options.ipam.config[%d]", i)
+
+		rawSubnet := strings.TrimSpace(cfg.Subnet)
+		if rawSubnet == "" {
+			if strings.TrimSpace(cfg.Gateway) != "" || strings.TrimSpace(cfg.IPRange) != "" || len(cfg.AuxAddress) > 0 {
+				return networkValidationErrorInternal(field+".subnet", "A subnet is required when a gateway, IP range or auxiliary address is set.")
+			}
+			continue
+		}
+
+		subnet, err := netip.ParsePrefix(rawSubnet)
+		if err != nil {
+			return networkValidationErrorInternal(field+".subnet", fmt.Sprintf("Subnet %q is not a valid CIDR.", rawSubnet))
+		}
+		if subnet != subnet.Masked() {
+			return networkValidationErrorInternal(field+".subnet", fmt.Sprintf("Subnet %q is not a network address; use %q.", rawSubnet, subnet.Masked().String()))
+		}
+		if subnet.Addr().Is6() && !options.EnableIPv6 {
+			return networkValidationErrorInternal("options.enableIPv6", fmt.Sprintf("IPv6 must be enabled to use subnet %s.", subnet))
+		}
+
+		if rawGateway := strings.TrimSpace(cfg.Gateway); rawGateway != "" {
+			gateway, err := netip.ParseAddr(rawGateway)
+			if err != nil {
+				return networkValidationErrorInternal(field+".gateway", fmt.Sprintf("Gateway %q is not a valid IP address.", rawGateway))
+			}
+			if !subnet.Contains(gateway) {
+				return networkValidationErrorInternal(field+".gateway", fmt.Sprintf("Gateway %s is outside subnet %s.", gateway, subnet))
+			}
+		}
+
+		if rawRange := strings.TrimSpace(cfg.IPRange); rawRange != "" {
+			ipRange, err := netip.ParsePrefix(rawRange)
+			if err != nil {
+				return networkValidationErrorInternal(field+".ipRange", fmt.Sprintf("IP range %q is not a valid CIDR.", rawRange))
+			}
+			if ipRange.Bits() < subnet.Bits() || !subnet.Contains(ipRange.Addr()) {
+				return networkValidationErrorInternal(field+".ipRange", fmt.Sprintf("IP range %s is outside subnet %s.", ipRange, subnet))
+			}
+		}
+
+		auxNames := make([]string, 0, len(cfg.AuxAddress))
+		for auxName := range cfg.AuxAddress {
+			auxNames = append(auxNames, auxName)
+		}
+		sort.Strings(auxNames)
+		for _, auxName := range auxNames {
+			rawAux := strings.TrimSpace(cfg.AuxAddress[auxName])
+			aux, err := netip.ParseAddr(rawAux)
+			if err != nil {
+				return networkValidationErrorInternal(field+".auxAddress."+auxName, fmt.Sprintf("Auxiliary address %q is not a valid IP address.", rawAux))
+			}
+			if !subnet.Contains(aux) {
+				return networkValidationErrorInternal(field+".auxAddress."+auxName, fmt.Sprintf("Auxiliary address %s is outside subnet %s.", aux, subnet))
+			}
+		}
+
+		for _, other := range requested {
+			if subnet.Overlaps(other) {
+				return networkValidationErrorInternal(field+".subnet", fmt.Sprintf("Subnet %s overlaps requested subnet %s.", subnet, other))
+			}
+		}
+		for _, existingNet := range existing {
+			for _, existingCfg := range existingNet.IPAM.Config {
+				if existingCfg.Subnet.IsValid() && subnet.Overlaps(existingCfg.Subnet.Masked()) {
+					return networkValidationErrorInternal(field+".subnet", fmt.Sprintf("Subnet %s overlaps subnet %s of network %q.", subnet, existingCfg.Subnet.Masked(), existingNet.Name))
+				}
+			}
+		}
+		requested = append(requested, subnet)
+	}
+
+	return nil
+}
+
+func networkValidationErrorInternal(field, message string) error {
+	return common.Classify(common.ErrValidation, errors.WithDetails(errors.New(message), "field", field))
+}
+
 func (s *NetworkService) RemoveNetwork(ctx context.Context, id string, user models.User) error {
+	defer s.invalidateSwarmMetadataInternal()
+
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeNetworkError, "network", id, "", user.ID, user.Username, "0", err, models.JSON{"action": "delete"})
@@ -238,6 +449,8 @@ func (s *NetworkService) RemoveNetwork(ctx context.Context, id string, user mode
 }
 
 func (s *NetworkService) PruneNetworks(ctx context.Context) (*network.PruneReport, error) {
+	defer s.invalidateSwarmMetadataInternal()
+
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/netip"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	networktypes "github.com/getarcaneapp/arcane/types/v2/network"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkServiceAttachmentsInternal(t *testing.T) {
+	services := []swarm.Service{
+		{
+			ID:   "svc-web",
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web"}, TaskTemplate: swarm.TaskSpec{Networks: []swarm.NetworkAttachmentConfig{{Target: "edge", Aliases: []string{"www"}}}}},
+		},
+		{
+			ID:   "svc-api",
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "api"}, TaskTemplate: swarm.TaskSpec{Networks: []swarm.NetworkAttachmentConfig{{Target: "net-edge"}}}},
+		},
+		{
+			ID:       "svc-proxy",
+			Spec:     swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "proxy"}},
+			Endpoint: swarm.Endpoint{VirtualIPs: []swarm.EndpointVirtualIP{{NetworkID: "net-edge"}}},
+		},
+		{
+			ID:   "svc-db",
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "db"}, TaskTemplate: swarm.TaskSpec{Networks: []swarm.NetworkAttachmentConfig{{Target: "backend"}}}},
+		},
+	}
+
+	require.Equal(t, []networktypes.ServiceAttachment{
+		{ServiceID: "svc-api", ServiceName: "api"},
+		{ServiceID: "svc-proxy", ServiceName: "proxy"},
+		{ServiceID: "svc-web", ServiceName: "web", Aliases: []string{"www"}},
+	}, networkServiceAttachmentsInternal(services, "net-edge", "edge"))
+
+	require.Empty(t, networkServiceAttachmentsInternal(services, "net-unused", "unused"))
+}
+
+func TestValidateNetworkIPAMInternal(t *testing.T) {
+	existing := []network.Summary{{Network: network.Network{
+		Name: "bridge",
+		IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: netip.MustParsePrefix("172.17.0.0/16")}}},
+	}}}
+	ipam := func(configs ...networktypes.IPAMConfig) *networktypes.IPAM {
+		return &networktypes.IPAM{Config: configs}
+	}
+
+	tests := []struct {
+		name      string
+		options   networktypes.CreateOptions
+		wantField string
+	}{
+		{name: "no ipam", options: networktypes.CreateOptions{}},
+		{name: "valid ipv4 pool", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.0/24", Gateway: "10.20.0.1", IPRange: "10.20.0.128/25", AuxAddress: map[string]string{"host": "10.20.0.2"}})}},
+		{name: "valid dual stack", options: networktypes.CreateOptions{EnableIPv6: true, IPAM: ipam(
+			networktypes.IPAMConfig{Subnet: "10.30.0.0/24"},
+			networktypes.IPAMConfig{Subnet: "fd00:dead:beef::/64", Gateway: "fd00:dead:beef::1"},
+		)}},
+		{name: "invalid cidr", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.0/33"})}, wantField: "options.ipam.config[0].subnet"},
+		{name: "host bits set", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.1/24"})}, wantField: "options.ipam.config[0].subnet"},
+		{name: "gateway without subnet", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Gateway: "10.20.0.1"})}, wantField: "options.ipam.config[0].subnet"},
+		{name: "gateway outside subnet", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.0/24", Gateway: "10.21.0.1"})}, wantField: "options.ipam.config[0].gateway"},
+		{name: "gateway family mismatch", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.0/24", Gateway: "fd00::1"})}, wantField: "options.ipam.config[0].gateway"},
+		{name: "ip range wider than subnet", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.0/24", IPRange: "10.20.0.0/16"})}, wantField: "options.ipam.config[0].ipRange"},
+		{name: "aux address outside subnet", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "10.20.0.0/24", AuxAddress: map[string]string{"router": "10.99.0.1"}})}, wantField: "options.ipam.config[0].auxAddress.router"},
+		{name: "ipv6 not enabled", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "fd00::/64"})}, wantField: "options.enableIPv6"},
+		{name: "overlapping requested pools", options: networktypes.CreateOptions{IPAM: ipam(
+			networktypes.IPAMConfig{Subnet: "10.20.0.0/16"},
+			networktypes.IPAMConfig{Subnet: "10.20.5.0/24"},
+		)}, wantField: "options.ipam.config[1].subnet"},
+		{name: "overlaps existing network", options: networktypes.CreateOptions{IPAM: ipam(networktypes.IPAMConfig{Subnet: "172.17.4.0/24"})}, wantField: "options.ipam.config[0].subnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNetworkIPAMInternal(tt.options, existing)
+			if tt.wantField == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, common.ErrValidation)
+			require.Equal(t, []any{"field", tt.wantField}, errors.GetDetails(err))
+		})
+	}
+}
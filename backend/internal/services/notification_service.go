@@ -12,16 +12,20 @@ import (
 	"maps"
 	"net/http"
 	"net/mail"
+	"slices"
 	"strings"
 	"time"
 
 	"emperror.dev/errors"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/notifications"
 	"github.com/getarcaneapp/arcane/backend/v2/resources"
+	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/imageupdate"
 	notificationdto "github.com/getarcaneapp/arcane/types/v2/notification"
 	"github.com/getarcaneapp/arcane/types/v2/system"
@@ -38,17 +42,19 @@ var notificationCredentialFieldsByProviderInternal = map[models.NotificationProv
 	models.NotificationProviderPushover: {"token"},
 	models.NotificationProviderGotify:   {"token"},
 	models.NotificationProviderMatrix:   {"password"},
+	models.NotificationProviderWebhook:  {"secret"},
 }
 
 const ErrUnauthorizedNotificationDispatch = errors.Sentinel("unauthorized notification dispatch")
 const ErrUnsupportedDispatchKind = errors.Sentinel("unsupported notification dispatch kind")
 
 type NotificationService struct {
-	db             *database.DB
-	config         *config.Config
-	environmentSvc *EnvironmentService
-	eventSvc       *EventService
-	httpClient     *http.Client
+	db              *database.DB
+	config          *config.Config
+	environmentSvc  *EnvironmentService
+	eventSvc        *EventService
+	settingsService *SettingsService
+	httpClient      *http.Client
 }
 
 type NotificationTarget struct {
@@ -69,13 +75,14 @@ func (s *NotificationService) ResolveNotificationTarget(ctx context.Context, env
 	return s.resolveNotificationTargetInternal(ctx, environmentID)
 }
 
-func NewNotificationService(db *database.DB, cfg *config.Config, environmentSvc *EnvironmentService, eventSvc *EventService) *NotificationService {
+func NewNotificationService(db *database.DB, cfg *config.Config, environmentSvc *EnvironmentService, eventSvc *EventService, settingsService *SettingsService) *NotificationService {
 	return &NotificationService{
-		db:             db,
-		config:         cfg,
-		environmentSvc: environmentSvc,
-		eventSvc:       eventSvc,
-		httpClient:     &http.Client{Timeout: 15 * time.Second},
+		db:              db,
+		config:          cfg,
+		environmentSvc:  environmentSvc,
+		eventSvc:        eventSvc,
+		settingsService: settingsService,
+		httpClient:      &http.Client{Timeout: 15 * time.Second},
 	}
 }
 
@@ -234,6 +241,16 @@ func (s *NotificationService) DispatchNotification(ctx context.Context, accessTo
 		}
 		logManagerDispatchNotificationInternal(ctx, target, payload.Kind)
 		return dispatchResponse, s.sendAutoHealNotificationForTargetInternal(ctx, target, payload.AutoHeal.ContainerName, payload.AutoHeal.ContainerID)
+	case notificationdto.DispatchKindContainerEvent:
+		if payload.ContainerEvent == nil {
+			return notificationdto.DispatchResponse{}, errors.New("container event payload is required")
+		}
+		eventType := models.NotificationEventType(payload.ContainerEvent.EventType)
+		if !models.IsContainerLifecycleNotificationEvent(eventType) {
+			return notificationdto.DispatchResponse{}, errors.Errorf("unsupported container event type: %s", payload.ContainerEvent.EventType)
+		}
+		logManagerDispatchNotificationInternal(ctx, target, payload.Kind)
+		return dispatchResponse, s.sendContainerEventNotificationForTargetInternal(ctx, target, eventType, payload.ContainerEvent.ContainerName, payload.ContainerEvent.ContainerID, payload.ContainerEvent.Detail)
 	default:
 		return notificationdto.DispatchResponse{}, errors.WrapIff(ErrUnsupportedDispatchKind, "%s", payload.Kind)
 	}
@@ -258,6 +275,10 @@ func (s *NotificationService) GetSettingsByProvider(ctx context.Context, provide
 func (s *NotificationService) CreateOrUpdateSettings(ctx context.Context, provider models.NotificationProvider, enabled bool, config models.JSON) (*models.NotificationSettings, error) {
 	var setting models.NotificationSettings
 
+	if err := notifications.ValidateMessageTemplate(config); err != nil {
+		return nil, err
+	}
+
 	err := s.db.WithContext(ctx).Where("provider = ?", provider).First(&setting).Error
 	existingConfig := models.JSON(nil)
 	if err == nil {
@@ -387,14 +408,18 @@ func (s *NotificationService) DeleteSettings(ctx context.Context, provider model
 // SendImageUpdateNotification dispatches a single-image update notification and
 
 func (s *NotificationService) isEventEnabled(config models.JSON, eventType models.NotificationEventType) bool {
+	// Container lifecycle events are opt-in so existing providers are not
+	// paged for every container exit after an upgrade.
+	defaultEnabled := !models.IsContainerLifecycleNotificationEvent(eventType)
+
 	events, ok := config["events"].(map[string]any)
 	if !ok {
-		return true // If no events config, default to enabled
+		return defaultEnabled // If no events config, fall back to the event type's default
 	}
 
 	enabled, ok := events[string(eventType)].(bool)
 	if !ok {
-		return true // If event type not specified, default to enabled
+		return defaultEnabled // If event type not specified, fall back to the event type's default
 	}
 
 	return enabled
@@ -410,6 +435,10 @@ func (s *NotificationService) logNotification(ctx context.Context, environmentID
 	severity := models.EventSeveritySuccess
 	title := fmt.Sprintf("Notification sent via %s", provider)
 	description := subject
+	if status == notificationStatusSkippedDuplicate {
+		severity = models.EventSeverityInfo
+		title = fmt.Sprintf("Duplicate notification skipped for %s", provider)
+	}
 	if errMsg != nil {
 		severity = models.EventSeverityError
 		title = fmt.Sprintf("Notification failed via %s", provider)
@@ -419,6 +448,9 @@ func (s *NotificationService) logNotification(ctx context.Context, environmentID
 	eventMetadata := cloneNotificationConfigInternal(metadata)
 	eventMetadata["provider"] = string(provider)
 	eventMetadata["status"] = status
+	if errMsg != nil {
+		eventMetadata[notificationErrorMetadataKey] = *errMsg
+	}
 
 	resourceType := "notification"
 	providerName := string(provider)
@@ -436,6 +468,135 @@ func (s *NotificationService) logNotification(ctx context.Context, environmentID
 	}
 }
 
+// NotificationLogFilter narrows a notification log query. Provider, Status and
+// EnvironmentID accept comma-separated values that are OR-ed together, where
+// Status is one of success, failed or skipped. From and To bound the send time
+// inclusively.
+type NotificationLogFilter struct {
+	Provider      string
+	Status        string
+	EnvironmentID string
+	From          *time.Time
+	To            *time.Time
+}
+
+// notificationLogStatusSeveritiesInternal maps the log status filter values to
+// the event severity logNotification records for them.
+var notificationLogStatusSeveritiesInternal = map[string]models.EventSeverity{
+	"success": models.EventSeveritySuccess,
+	"failed":  models.EventSeverityError,
+	"skipped": models.EventSeverityInfo,
+}
+
+// ListNotificationLogs returns the delivery attempts recorded by
+// logNotification that match filter, sorted and paginated by params. The search
+// term in params matches the rendered title and subject.
+func (s *NotificationService) ListNotificationLogs(ctx context.Context, filter NotificationLogFilter, params pagination.QueryParams) ([]notificationdto.LogEntry, pagination.Response, error) {
+	severities, err := notificationLogSeveritiesInternal(filter.Status)
+	if err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	q := s.db.WithContext(ctx).Model(&models.Event{}).Where("type = ?", models.EventTypeNotificationSend)
+	q = pagination.ApplyLikeSearch(q, params.Search, "title LIKE ? OR description LIKE ?")
+	q = pagination.ApplyFilter(q, "resource_name", filter.Provider)
+	q = pagination.ApplyFilter(q, "environment_id", filter.EnvironmentID)
+	if len(severities) > 0 {
+		q = q.Where("severity IN ?", severities)
+	}
+	if filter.From != nil {
+		q = q.Where("timestamp >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("timestamp <= ?", *filter.To)
+	}
+
+	var events []models.Event
+	paginationResp, err := pagination.PaginateAndSortDB(params, q, &events)
+	if err != nil {
+		return nil, pagination.Response{}, errors.WrapIf(err, "failed to paginate notification logs")
+	}
+
+	entries := make([]notificationdto.LogEntry, len(events))
+	for i := range events {
+		entries[i] = newNotificationLogEntryInternal(events[i])
+	}
+	return entries, paginationResp, nil
+}
+
+// DeleteOldNotificationLogs removes the delivery attempts recorded more than
+// olderThan ago and returns how many were deleted.
+func (s *NotificationService) DeleteOldNotificationLogs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-olderThan)
+	result := s.db.WithContext(ctx).
+		Where("type = ? AND timestamp < ?", models.EventTypeNotificationSend, cutoff).
+		Delete(&models.Event{})
+	if result.Error != nil {
+		return 0, errors.WrapIf(result.Error, "failed to delete old notification logs")
+	}
+	return result.RowsAffected, nil
+}
+
+func notificationLogSeveritiesInternal(status string) ([]models.EventSeverity, error) {
+	var severities []models.EventSeverity
+	for part := range strings.SplitSeq(status, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		severity, ok := notificationLogStatusSeveritiesInternal[part]
+		if !ok {
+			return nil, common.Classify(common.ErrValidation, errors.Errorf("Unknown notification status %q; use success, failed or skipped.", part))
+		}
+		severities = append(severities, severity)
+	}
+	return severities, nil
+}
+
+// newNotificationLogEntryInternal renders a notification event as a log entry.
+// Failed attempts logged before the error was kept in the metadata only carry
+// it in their "subject: error" description, so it is split back out there.
+func newNotificationLogEntryInternal(event models.Event) notificationdto.LogEntry {
+	entry := notificationdto.LogEntry{
+		ID:      event.ID,
+		Title:   event.Title,
+		Subject: event.Description,
+		SentAt:  event.Timestamp,
+	}
+	if event.ResourceName != nil {
+		entry.Provider = notificationdto.Provider(*event.ResourceName)
+	}
+	if event.EnvironmentID != nil {
+		entry.EnvironmentID = *event.EnvironmentID
+	}
+
+	metadata := cloneNotificationConfigInternal(event.Metadata)
+	entry.Status, _ = metadata["status"].(string)
+	if event.Severity == models.EventSeverityError {
+		if errMsg, ok := metadata[notificationErrorMetadataKey].(string); ok {
+			entry.Error = errMsg
+			entry.Subject = strings.TrimSuffix(event.Description, ": "+errMsg)
+		} else if subject, errMsg, found := strings.Cut(event.Description, ": "); found {
+			entry.Subject, entry.Error = subject, errMsg
+		}
+	}
+	if entry.Status == "" {
+		for status, severity := range notificationLogStatusSeveritiesInternal {
+			if severity == event.Severity {
+				entry.Status = status
+			}
+		}
+	}
+
+	delete(metadata, "provider")
+	delete(metadata, "status")
+	delete(metadata, notificationErrorMetadataKey)
+	if len(metadata) > 0 {
+		entry.Metadata = base.JsonObject(metadata)
+	}
+	return entry
+}
+
 // SendBatchImageUpdateNotification dispatches a batched image-update notification
 // and returns the number of eligible providers it was delivered to (0 means no
 
@@ -473,6 +634,13 @@ func (s *NotificationService) notifyEnabledProvidersInternal(
 			continue
 		}
 
+		// The provider already has this notification, so it counts as delivered.
+		if errors.Is(sendErr, errDuplicateNotificationInternal) {
+			delivered++
+			s.logNotification(ctx, target.EnvironmentID, setting.Provider, logRef, notificationStatusSkippedDuplicate, nil, metadata)
+			continue
+		}
+
 		if sendErr == nil {
 			delivered++
 		}
@@ -501,6 +669,90 @@ func unknownNotificationProviderErrorInternal(provider models.NotificationProvid
 	return errors.Errorf("unknown provider: %s", provider)
 }
 
+// --- Image update deduplication ---
+
+const notificationStatusSkippedDuplicate = "skipped (duplicate)"
+
+// errDuplicateNotificationInternal is returned by a dispatch func when the
+// provider was already sent the same notification within the cooldown.
+const errDuplicateNotificationInternal = errors.Sentinel("duplicate notification")
+
+// notificationErrorMetadataKey holds the provider error of a failed delivery
+// attempt in its event metadata.
+const notificationErrorMetadataKey = "error"
+
+// notificationImagesMetadataKey holds the imageRef -> latestDigest pairs of an
+// image update notification in its event metadata, used for deduplication.
+const notificationImagesMetadataKey = "images"
+
+type notifiedImageDigestInternal struct {
+	imageRef string
+	digest   string
+}
+
+// imageUpdateNotificationCooldownInternal returns how long an identical image
+// update notification is suppressed per provider; 0 disables deduplication.
+func (s *NotificationService) imageUpdateNotificationCooldownInternal(ctx context.Context) time.Duration {
+	hours := 24
+	if s.settingsService != nil {
+		hours = s.settingsService.GetIntSetting(ctx, "imageUpdateNotificationCooldown", hours)
+	}
+	return time.Duration(max(hours, 0)) * time.Hour
+}
+
+// recentlyNotifiedImageDigestsInternal returns the image digests successfully
+// sent to provider for environmentID within the cooldown, read from the
+// notification event log.
+func (s *NotificationService) recentlyNotifiedImageDigestsInternal(ctx context.Context, environmentID string, provider models.NotificationProvider, cooldown time.Duration) map[notifiedImageDigestInternal]struct{} {
+	if cooldown <= 0 || s.eventSvc == nil || s.db == nil {
+		return nil
+	}
+
+	var events []models.Event
+	if err := s.db.WithContext(ctx).
+		Where("type = ? AND severity = ? AND resource_name = ? AND environment_id = ? AND timestamp >= ?",
+			models.EventTypeNotificationSend, models.EventSeveritySuccess, string(provider), environmentID, time.Now().Add(-cooldown)).
+		Find(&events).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to load notification history for deduplication", "provider", provider, "error", err.Error())
+		return nil
+	}
+
+	sent := make(map[notifiedImageDigestInternal]struct{})
+	for _, event := range events {
+		images, _ := event.Metadata[notificationImagesMetadataKey].(map[string]any)
+		for imageRef, digest := range images {
+			if digest, ok := digest.(string); ok && digest != "" {
+				sent[notifiedImageDigestInternal{imageRef: imageRef, digest: digest}] = struct{}{}
+			}
+		}
+	}
+	return sent
+}
+
+// unnotifiedImageUpdatesInternal drops the updates whose latest digest was
+// already sent, keeping updates without a digest since they cannot be matched.
+func unnotifiedImageUpdatesInternal(updates map[string]*imageupdate.Response, sent map[notifiedImageDigestInternal]struct{}) map[string]*imageupdate.Response {
+	if len(sent) == 0 {
+		return updates
+	}
+	remaining := make(map[string]*imageupdate.Response, len(updates))
+	for imageRef, update := range updates {
+		if _, ok := sent[notifiedImageDigestInternal{imageRef: imageRef, digest: update.LatestDigest}]; ok && update.LatestDigest != "" {
+			continue
+		}
+		remaining[imageRef] = update
+	}
+	return remaining
+}
+
+func notificationImagesMetadataInternal(updates map[string]*imageupdate.Response) map[string]any {
+	images := make(map[string]any, len(updates))
+	for imageRef, update := range updates {
+		images[imageRef] = update.LatestDigest
+	}
+	return images
+}
+
 const (
 	notificationTestTypeSimple           = "simple"
 	notificationTestTypeImageUpdate      = "image-update"
@@ -531,6 +783,40 @@ type VulnerabilityNotificationPayload struct {
 	InstalledVersion string // optional
 }
 
+// notificationEventTypeTest is the webhook event type reported for test sends.
+const notificationEventTypeTest models.NotificationEventType = "test"
+
+// notificationEventInternal describes a notification for the webhook provider.
+func notificationEventInternal(target NotificationTarget, eventType models.NotificationEventType, resource string, before, after any) notifications.Event {
+	return notifications.Event{
+		EventType: string(eventType),
+		Resource:  resource,
+		Before:    before,
+		After:     after,
+		Timestamp: time.Now().UTC(),
+		Environment: notifications.EventEnvironment{
+			ID:   target.EnvironmentID,
+			Name: target.EnvironmentName,
+		},
+	}
+}
+
+func imageStateInternal(digest, version string) map[string]any {
+	return map[string]any{"digest": digest, "version": version}
+}
+
+// batchImageUpdateNotificationEventInternal reports the current and latest
+// image state per image reference.
+func batchImageUpdateNotificationEventInternal(target NotificationTarget, updates map[string]*imageupdate.Response) notifications.Event {
+	before := make(map[string]any, len(updates))
+	after := make(map[string]any, len(updates))
+	for imageRef, update := range updates {
+		before[imageRef] = imageStateInternal(update.CurrentDigest, update.CurrentVersion)
+		after[imageRef] = imageStateInternal(update.LatestDigest, update.LatestVersion)
+	}
+	return notificationEventInternal(target, models.NotificationEventImageUpdate, strings.Join(slices.Sorted(maps.Keys(updates)), ", "), before, after)
+}
+
 // --- Per-event notification content ---
 
 func (s *NotificationService) imageUpdateNotificationContentInternal(environmentName, imageRef string, updateInfo *imageupdate.Response) notifications.Content {
@@ -641,6 +927,38 @@ func (s *NotificationService) pruneReportNotificationContentInternal(environment
 	}
 }
 
+// containerEventNotificationTitles are the headlines for each container lifecycle event.
+var containerEventNotificationTitles = map[models.NotificationEventType]string{
+	models.NotificationEventContainerDie:         "Container Exited",
+	models.NotificationEventContainerOOM:         "Container Out of Memory",
+	models.NotificationEventContainerUnhealthy:   "Container Unhealthy",
+	models.NotificationEventContainerRestartLoop: "Container Restart Loop",
+}
+
+func (s *NotificationService) containerEventNotificationContentInternal(environmentName string, eventType models.NotificationEventType, containerName, detail string) notifications.Content {
+	title := containerEventNotificationTitles[eventType]
+	defaultTitle := notifications.BuildEmailSubject(environmentName, title)
+	return notifications.Content{
+		Text: notifications.TextByFormat(func(format notifications.MessageFormat) string {
+			return notifications.BuildContainerEventNotificationMessage(format, environmentName, title, containerName, detail)
+		}),
+		Title:        defaultTitle,
+		DefaultTitle: defaultTitle,
+		RenderEmail: func() (string, string, error) {
+			subject := notifications.BuildEmailSubject(environmentName, fmt.Sprintf("%s: '%s'", title, containerName))
+			body := fmt.Sprintf(
+				"<p><strong>Environment:</strong> %s</p><p><strong>Container:</strong> %s</p>",
+				html.EscapeString(environmentName),
+				html.EscapeString(containerName),
+			)
+			if detail != "" {
+				body += fmt.Sprintf("<p>%s</p>", html.EscapeString(detail))
+			}
+			return subject, body, nil
+		},
+	}
+}
+
 func (s *NotificationService) autoHealNotificationContentInternal(environmentName, containerName string) notifications.Content {
 	defaultTitle := notifications.BuildEmailSubject(environmentName, "Auto Heal")
 	return notifications.Content{
@@ -694,19 +1012,45 @@ func (s *NotificationService) SendImageUpdateNotification(ctx context.Context, i
 }
 
 func (s *NotificationService) sendImageUpdateNotificationForTargetInternal(ctx context.Context, target NotificationTarget, imageRef string, updateInfo *imageupdate.Response, eventType models.NotificationEventType) (int, error) {
+	updates := map[string]*imageupdate.Response{imageRef: updateInfo}
 	metadata := models.JSON{
-		"hasUpdate":     updateInfo.HasUpdate,
-		"currentDigest": updateInfo.CurrentDigest,
-		"latestDigest":  updateInfo.LatestDigest,
-		"updateType":    updateInfo.UpdateType,
-		"eventType":     string(eventType),
-	}
+		"hasUpdate":                   updateInfo.HasUpdate,
+		"currentDigest":               updateInfo.CurrentDigest,
+		"latestDigest":                updateInfo.LatestDigest,
+		"updateType":                  updateInfo.UpdateType,
+		"eventType":                   string(eventType),
+		notificationImagesMetadataKey: notificationImagesMetadataInternal(updates),
+	}
+	cooldown := s.imageUpdateNotificationCooldownInternal(ctx)
 	content := s.imageUpdateNotificationContentInternal(target.EnvironmentName, imageRef, updateInfo)
+	content.Event = notificationEventInternal(target, eventType, imageRef,
+		imageStateInternal(updateInfo.CurrentDigest, updateInfo.CurrentVersion),
+		imageStateInternal(updateInfo.LatestDigest, updateInfo.LatestVersion),
+	)
 	return s.notifyEnabledProvidersInternal(ctx, target, eventType, imageRef, metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
-		return notifications.Deliver(ctx, provider, config, content)
+		sent := s.recentlyNotifiedImageDigestsInternal(ctx, target.EnvironmentID, provider, cooldown)
+		if len(unnotifiedImageUpdatesInternal(updates, sent)) == 0 {
+			return true, errDuplicateNotificationInternal
+		}
+		templated, err := notifications.ApplyMessageTemplate(config, content, imageUpdateTemplateDataInternal(target.EnvironmentName, updates))
+		if err != nil {
+			return true, err
+		}
+		return notifications.Deliver(ctx, provider, config, templated)
 	})
 }
 
+// imageUpdateTemplateDataInternal returns message template variables for each
+// update, ordered by image reference.
+func imageUpdateTemplateDataInternal(environmentName string, updates map[string]*imageupdate.Response) []map[string]string {
+	imageRefs := slices.Sorted(maps.Keys(updates))
+	data := make([]map[string]string, 0, len(imageRefs))
+	for _, imageRef := range imageRefs {
+		data = append(data, notifications.ImageUpdateTemplateData(environmentName, imageRef, updates[imageRef]))
+	}
+	return data
+}
+
 func (s *NotificationService) SendContainerUpdateNotification(ctx context.Context, containerName, imageRef, oldDigest, newDigest string) error {
 	if s.config != nil && s.config.AgentMode {
 		_, err := s.dispatchNotificationToManagerInternal(ctx, notificationdto.DispatchRequest{
@@ -737,6 +1081,10 @@ func (s *NotificationService) sendContainerUpdateNotificationForTargetInternal(c
 		"eventType":     string(models.NotificationEventContainerUpdate),
 	}
 	content := s.containerUpdateNotificationContentInternal(target.EnvironmentName, containerName, imageRef, oldDigest, newDigest)
+	content.Event = notificationEventInternal(target, models.NotificationEventContainerUpdate, containerName,
+		map[string]any{"imageRef": imageRef, "digest": oldDigest},
+		map[string]any{"imageRef": imageRef, "digest": newDigest},
+	)
 	_, err := s.notifyEnabledProvidersInternal(ctx, target, models.NotificationEventContainerUpdate, imageRef, metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
 		return notifications.Deliver(ctx, provider, config, content)
 	})
@@ -787,6 +1135,14 @@ func (s *NotificationService) sendVulnerabilityNotificationForTargetInternal(ctx
 		"eventType":    string(models.NotificationEventVulnerabilityFound),
 	}
 	content := s.vulnerabilityNotificationContentInternal(target.EnvironmentName, payload)
+	content.Event = notificationEventInternal(target, models.NotificationEventVulnerabilityFound, payload.ImageName, nil, map[string]any{
+		"cveId":            payload.CVEID,
+		"cveLink":          payload.CVELink,
+		"severity":         payload.Severity,
+		"fixedVersion":     payload.FixedVersion,
+		"pkgName":          payload.PkgName,
+		"installedVersion": payload.InstalledVersion,
+	})
 	_, err := s.notifyEnabledProvidersInternal(ctx, target, models.NotificationEventVulnerabilityFound, payload.ImageName, metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
 		return notifications.Deliver(ctx, provider, config, content)
 	})
@@ -846,13 +1202,37 @@ func (s *NotificationService) sendBatchImageUpdateNotificationForTargetInternal(
 	}
 
 	metadata := models.JSON{
-		"updateCount": len(updatesWithChanges),
-		"eventType":   string(models.NotificationEventImageUpdate),
-		"batch":       true,
+		"updateCount":                 len(updatesWithChanges),
+		"eventType":                   string(models.NotificationEventImageUpdate),
+		"batch":                       true,
+		notificationImagesMetadataKey: notificationImagesMetadataInternal(updatesWithChanges),
 	}
+	cooldown := s.imageUpdateNotificationCooldownInternal(ctx)
 	content := s.batchImageUpdateNotificationContentInternal(target.EnvironmentName, updatesWithChanges)
+	content.Event = batchImageUpdateNotificationEventInternal(target, updatesWithChanges)
 	return s.notifyEnabledProvidersInternal(ctx, target, models.NotificationEventImageUpdate, strings.Join(imageRefs, ", "), metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
-		return notifications.Deliver(ctx, provider, config, content)
+		sent := s.recentlyNotifiedImageDigestsInternal(ctx, target.EnvironmentID, provider, cooldown)
+		remaining := unnotifiedImageUpdatesInternal(updatesWithChanges, sent)
+		// Providers are dispatched and logged one at a time, so the metadata
+		// logged for this provider records only the images it is sent; images
+		// cut from the batch keep their earlier cooldown.
+		metadata["updateCount"] = len(updatesWithChanges)
+		metadata[notificationImagesMetadataKey] = notificationImagesMetadataInternal(updatesWithChanges)
+		providerContent := content
+		switch {
+		case len(remaining) == 0:
+			return true, errDuplicateNotificationInternal
+		case len(remaining) < len(updatesWithChanges):
+			metadata["updateCount"] = len(remaining)
+			metadata[notificationImagesMetadataKey] = notificationImagesMetadataInternal(remaining)
+			providerContent = s.batchImageUpdateNotificationContentInternal(target.EnvironmentName, remaining)
+			providerContent.Event = batchImageUpdateNotificationEventInternal(target, remaining)
+		}
+		templated, err := notifications.ApplyMessageTemplate(config, providerContent, imageUpdateTemplateDataInternal(target.EnvironmentName, remaining))
+		if err != nil {
+			return true, err
+		}
+		return notifications.Deliver(ctx, provider, config, templated)
 	})
 }
 
@@ -891,6 +1271,7 @@ func (s *NotificationService) sendPruneReportNotificationForTargetInternal(ctx c
 		"eventType":      string(models.NotificationEventPruneReport),
 	}
 	content := s.pruneReportNotificationContentInternal(target.EnvironmentName, result)
+	content.Event = notificationEventInternal(target, models.NotificationEventPruneReport, "system", nil, result)
 	_, err := s.notifyEnabledProvidersInternal(ctx, target, models.NotificationEventPruneReport, "System Prune Report", metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
 		return notifications.Deliver(ctx, provider, config, content)
 	})
@@ -946,12 +1327,57 @@ func (s *NotificationService) sendAutoHealNotificationForTargetInternal(ctx cont
 		"eventType":   string(models.NotificationEventAutoHeal),
 	}
 	content := s.autoHealNotificationContentInternal(target.EnvironmentName, containerName)
+	content.Event = notificationEventInternal(target, models.NotificationEventAutoHeal, containerName,
+		map[string]any{"containerId": containerID, "health": "unhealthy"},
+		map[string]any{"containerId": containerID, "action": "restarted"},
+	)
 	_, err := s.notifyEnabledProvidersInternal(ctx, target, models.NotificationEventAutoHeal, containerName, metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
 		return notifications.Deliver(ctx, provider, config, content)
 	})
 	return err
 }
 
+// SendContainerEventNotification sends a notification for a container lifecycle
+// event observed on the Docker event stream, such as a crash or OOM kill.
+func (s *NotificationService) SendContainerEventNotification(ctx context.Context, eventType models.NotificationEventType, containerName, containerID, detail string) error {
+	if !models.IsContainerLifecycleNotificationEvent(eventType) {
+		return errors.Errorf("unsupported container event type: %s", eventType)
+	}
+
+	if s.config != nil && s.config.AgentMode {
+		_, err := s.dispatchNotificationToManagerInternal(ctx, notificationdto.DispatchRequest{
+			Kind: notificationdto.DispatchKindContainerEvent,
+			ContainerEvent: &notificationdto.DispatchContainerEvent{
+				EventType:     string(eventType),
+				ContainerName: containerName,
+				ContainerID:   containerID,
+				Detail:        detail,
+			},
+		})
+		return err
+	}
+
+	target, err := s.resolveNotificationTargetInternal(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	return s.sendContainerEventNotificationForTargetInternal(ctx, target, eventType, containerName, containerID, detail)
+}
+
+func (s *NotificationService) sendContainerEventNotificationForTargetInternal(ctx context.Context, target NotificationTarget, eventType models.NotificationEventType, containerName, containerID, detail string) error {
+	metadata := models.JSON{
+		"containerID": containerID,
+		"eventType":   string(eventType),
+	}
+	content := s.containerEventNotificationContentInternal(target.EnvironmentName, eventType, containerName, detail)
+	content.Event = notificationEventInternal(target, eventType, containerName, nil, map[string]any{"containerId": containerID, "detail": detail})
+	_, err := s.notifyEnabledProvidersInternal(ctx, target, eventType, containerName, metadata, func(ctx context.Context, provider models.NotificationProvider, config models.JSON) (bool, error) {
+		return notifications.Deliver(ctx, provider, config, content)
+	})
+	return err
+}
+
 // --- Test notifications ---
 
 // notificationEventTypeForTestTypeInternal maps a test type to the event type a
@@ -1076,10 +1502,15 @@ func (s *NotificationService) TestNotification(ctx context.Context, environmentI
 	}
 
 	if provider == models.NotificationProviderEmail && testType == notificationTestTypeSimple {
-		return warning, s.sendTestEmail(ctx, target.EnvironmentName, setting.Config)
+		htmlBody, _, err := s.renderTestEmailTemplate(target.EnvironmentName)
+		if err != nil {
+			return "", errors.WrapIf(err, "failed to render test email template")
+		}
+		return warning, s.sendTestEmail(ctx, setting.Config, notifications.BuildEmailSubject(target.EnvironmentName, testEmailSubject), htmlBody)
 	}
 
 	content := s.testNotificationContentInternal(target.EnvironmentName, testType)
+	content.Event = notificationEventInternal(target, notificationEventTypeTest, testType, nil, nil)
 	handled, sendErr := notifications.Deliver(ctx, provider, setting.Config, content)
 	if !handled {
 		return "", unknownNotificationProviderErrorInternal(provider)
@@ -1087,8 +1518,59 @@ func (s *NotificationService) TestNotification(ctx context.Context, environmentI
 	return warning, sendErr
 }
 
+// TestProvider sends a sample notification through one provider's saved
+// configuration and returns the title and body it rendered. A failed send is
+// reported on the result rather than as an error so the preview is still
+// returned; errors are reserved for missing settings and unknown providers.
+func (s *NotificationService) TestProvider(ctx context.Context, provider models.NotificationProvider) (notificationdto.ProviderTestResult, error) {
+	setting, err := s.GetSettingsByProvider(ctx, provider)
+	if err != nil {
+		return notificationdto.ProviderTestResult{}, errors.Errorf("please save your %s settings before testing", provider)
+	}
+
+	target, err := s.resolveNotificationTargetInternal(ctx, "0")
+	if err != nil {
+		return notificationdto.ProviderTestResult{}, err
+	}
+
+	result := notificationdto.ProviderTestResult{
+		Provider: notificationdto.Provider(provider),
+		Warning:  s.testNotificationWarningInternal(setting, notificationTestTypeSimple),
+	}
+
+	var sendErr error
+	if provider == models.NotificationProviderEmail {
+		htmlBody, textBody, err := s.renderTestEmailTemplate(target.EnvironmentName)
+		if err != nil {
+			return notificationdto.ProviderTestResult{}, errors.WrapIf(err, "failed to render test email template")
+		}
+		result.Title = notifications.BuildEmailSubject(target.EnvironmentName, testEmailSubject)
+		result.Body = textBody
+		result.HTML = htmlBody
+		sendErr = s.sendTestEmail(ctx, setting.Config, result.Title, htmlBody)
+	} else {
+		format, ok := notifications.ProviderMessageFormat(provider)
+		if !ok {
+			return notificationdto.ProviderTestResult{}, unknownNotificationProviderErrorInternal(provider)
+		}
+		content := s.testNotificationContentInternal(target.EnvironmentName, notificationTestTypeSimple)
+		content.Event = notificationEventInternal(target, notificationEventTypeTest, notificationTestTypeSimple, nil, nil)
+		result.Title = content.Title
+		result.Body = content.Text[format]
+		_, sendErr = notifications.Deliver(ctx, provider, setting.Config, content)
+	}
+
+	result.Success = sendErr == nil
+	if sendErr != nil {
+		result.Error = sendErr.Error()
+	}
+	return result, nil
+}
+
 const logoURLPath = "/api/app-images/logo-email"
 
+const testEmailSubject = "Test Email from Arcane"
+
 func (s *NotificationService) renderEmailTemplate(environmentName, imageRef string, updateInfo *imageupdate.Response) (string, string, error) {
 	appURL := s.config.GetAppURL()
 	logoURL := appURL + logoURLPath
@@ -1184,7 +1666,7 @@ func (s *NotificationService) renderContainerUpdateEmailTemplate(environmentName
 	return htmlBuf.String(), textBuf.String(), nil
 }
 
-func (s *NotificationService) sendTestEmail(ctx context.Context, environmentName string, config models.JSON) error {
+func (s *NotificationService) sendTestEmail(ctx context.Context, config models.JSON, subject, htmlBody string) error {
 	var emailConfig models.EmailConfig
 	configBytes, err := json.Marshal(config)
 	if err != nil {
@@ -1214,12 +1696,6 @@ func (s *NotificationService) sendTestEmail(ctx context.Context, environmentName
 		return err
 	}
 
-	htmlBody, _, err := s.renderTestEmailTemplate(environmentName)
-	if err != nil {
-		return errors.WrapIf(err, "failed to render test email template")
-	}
-
-	subject := notifications.BuildEmailSubject(environmentName, "Test Email from Arcane")
 	if err := notifications.SendEmail(ctx, emailConfig, subject, htmlBody); err != nil {
 		return errors.WrapIf(err, "failed to send email")
 	}
@@ -18,9 +18,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/notifications"
 	"github.com/getarcaneapp/arcane/types/v2/imageupdate"
 	notificationdto "github.com/getarcaneapp/arcane/types/v2/notification"
@@ -58,7 +60,7 @@ func setupNotificationTestServiceInternal(t *testing.T) (*database.DB, *Environm
 		AppUrl: "http://localhost:3552",
 	}
 
-	return db, envSvc, NewNotificationService(db, cfg, envSvc, NewEventService(db, cfg, nil))
+	return db, envSvc, NewNotificationService(db, cfg, envSvc, NewEventService(db, cfg, nil), nil)
 }
 
 func newNotificationTestUpdateInfoInternal() *imageupdate.Response {
@@ -234,7 +236,7 @@ func TestNotificationService_SendImageUpdateNotification_AgentModeDispatchesToMa
 		AgentMode:     true,
 		AgentToken:    "agent-token",
 		ManagerApiUrl: server.URL,
-	}, envSvc, nil)
+	}, envSvc, nil, nil)
 
 	delivered, err := svc.SendImageUpdateNotification(ctx, "nginx:latest", newNotificationTestUpdateInfoInternal(), models.NotificationEventImageUpdate)
 	require.NoError(t, err)
@@ -271,7 +273,7 @@ func TestNotificationService_SendBatchImageUpdateNotification_AgentModeUsesManag
 		AgentMode:     true,
 		AgentToken:    "agent-token",
 		ManagerApiUrl: server.URL,
-	}, envSvc, nil)
+	}, envSvc, nil, nil)
 
 	delivered, err := svc.SendBatchImageUpdateNotification(ctx, map[string]*imageupdate.Response{
 		"nginx:latest": newNotificationTestUpdateInfoInternal(),
@@ -291,7 +293,7 @@ func TestNotificationService_SendImageUpdateNotification_AgentModeRequiresUpdate
 	svc := NewNotificationService(db, &config.Config{
 		AppUrl:    "http://localhost:3552",
 		AgentMode: true,
-	}, envSvc, nil)
+	}, envSvc, nil, nil)
 
 	_, err := svc.SendImageUpdateNotification(ctx, "nginx:latest", nil, models.NotificationEventImageUpdate)
 	require.Error(t, err)
@@ -315,7 +317,7 @@ func TestNotificationService_SendBatchImageUpdateNotification_AgentModeSkipsNoOp
 		AgentMode:     true,
 		AgentToken:    "agent-token",
 		ManagerApiUrl: server.URL,
-	}, envSvc, nil)
+	}, envSvc, nil, nil)
 
 	t.Run("empty updates", func(t *testing.T) {
 		delivered, err := svc.SendBatchImageUpdateNotification(ctx, map[string]*imageupdate.Response{})
@@ -486,6 +488,15 @@ func TestBuildAutoHealNotificationMessageInternal_IncludesEnvironment(t *testing
 	require.Equal(t, 1, strings.Count(message, "Environment"))
 }
 
+func TestNotificationService_IsEventEnabled_ContainerLifecycleEventsAreOptIn(t *testing.T) {
+	svc := &NotificationService{}
+
+	require.True(t, svc.isEventEnabled(models.JSON{}, models.NotificationEventImageUpdate))
+	require.False(t, svc.isEventEnabled(models.JSON{}, models.NotificationEventContainerDie))
+	require.False(t, svc.isEventEnabled(models.JSON{"events": map[string]any{"image_update": true}}, models.NotificationEventContainerOOM))
+	require.True(t, svc.isEventEnabled(models.JSON{"events": map[string]any{"container_restart_loop": true}}, models.NotificationEventContainerRestartLoop))
+}
+
 func TestNotificationCredentialInternal_KeepsPlaintextLegacyValues(t *testing.T) {
 	setupNotificationTestDB(t)
 
@@ -529,7 +540,7 @@ func TestNotificationCredentialInternal_LeavesEmptyValuesEmpty(t *testing.T) {
 func TestNotificationService_CreateOrUpdateSettingsEncryptsCredentialFieldsInternal(t *testing.T) {
 	ctx := context.Background()
 	db := setupNotificationTestDB(t)
-	svc := NewNotificationService(db, &config.Config{}, nil, nil)
+	svc := NewNotificationService(db, &config.Config{}, nil, nil, nil)
 
 	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderDiscord, true, models.JSON{
 		"webhookId": "123456789",
@@ -552,7 +563,7 @@ func TestNotificationService_CreateOrUpdateSettingsEncryptsCredentialFieldsInter
 func TestNotificationService_CreateOrUpdateSettingsPreservesStoredCredentialWhenEmptyInternal(t *testing.T) {
 	ctx := context.Background()
 	db := setupNotificationTestDB(t)
-	svc := NewNotificationService(db, &config.Config{}, nil, nil)
+	svc := NewNotificationService(db, &config.Config{}, nil, nil, nil)
 
 	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderGotify, true, models.JSON{
 		"host":  "gotify.example",
@@ -580,7 +591,7 @@ func TestNotificationService_CreateOrUpdateSettingsPreservesStoredCredentialWhen
 func TestNotificationService_CreateOrUpdateSettingsClearsEmailPasswordWhenAuthModeNoneInternal(t *testing.T) {
 	ctx := context.Background()
 	db := setupNotificationTestDB(t)
-	svc := NewNotificationService(db, &config.Config{}, nil, nil)
+	svc := NewNotificationService(db, &config.Config{}, nil, nil, nil)
 
 	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderEmail, true, models.JSON{
 		"smtpHost":     "smtp.example",
@@ -604,7 +615,7 @@ func TestNotificationService_CreateOrUpdateSettingsClearsEmailPasswordWhenAuthMo
 func TestNotificationService_CreateOrUpdateSettingsPreservesCredentialAcrossDisableInternal(t *testing.T) {
 	ctx := context.Background()
 	db := setupNotificationTestDB(t)
-	svc := NewNotificationService(db, &config.Config{}, nil, nil)
+	svc := NewNotificationService(db, &config.Config{}, nil, nil, nil)
 
 	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderGotify, true, models.JSON{
 		"host":  "gotify.example",
@@ -635,7 +646,7 @@ func TestNotificationService_CreateOrUpdateSettingsPreservesCredentialAcrossDisa
 func TestNotificationService_CreateOrUpdateSettingsKeepsConfigWhenDisabledInternal(t *testing.T) {
 	ctx := context.Background()
 	db := setupNotificationTestDB(t)
-	svc := NewNotificationService(db, &config.Config{}, nil, nil)
+	svc := NewNotificationService(db, &config.Config{}, nil, nil, nil)
 
 	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderNtfy, true, models.JSON{
 		"host":  "ntfy.example",
@@ -671,7 +682,7 @@ func TestNotificationService_CreateOrUpdateSettingsKeepsConfigWhenDisabledIntern
 func TestNotificationService_NotifyEnabledProvidersInternal_SkipsFiltersAndAggregatesInternal(t *testing.T) {
 	ctx := context.Background()
 	db := setupNotificationTestDB(t)
-	svc := NewNotificationService(db, &config.Config{}, nil, NewEventService(db, nil, nil))
+	svc := NewNotificationService(db, &config.Config{}, nil, NewEventService(db, nil, nil), nil)
 
 	rows := []models.NotificationSettings{
 		{Provider: models.NotificationProviderDiscord, Enabled: false, Config: models.JSON{}},
@@ -714,6 +725,68 @@ func TestNotificationService_NotifyEnabledProvidersInternal_SkipsFiltersAndAggre
 	require.Contains(t, events[1].Description, "boom")
 }
 
+func TestNotificationService_ListNotificationLogs(t *testing.T) {
+	ctx := context.Background()
+	db, _, svc := setupNotificationTestServiceInternal(t)
+
+	errMsg := "boom"
+	svc.logNotification(ctx, "0", models.NotificationProviderGotify, "nginx:latest", "success", nil, models.JSON{"eventType": "image_update"})
+	svc.logNotification(ctx, "0", models.NotificationProviderNtfy, "nginx:latest", "failed", &errMsg, nil)
+	svc.logNotification(ctx, "env-2", models.NotificationProviderGotify, "redis:7", notificationStatusSkippedDuplicate, nil, nil)
+
+	// Attempts logged before the error was stored in the metadata.
+	legacy := "ntfy"
+	require.NoError(t, db.WithContext(ctx).Create(&models.Event{
+		Type:         models.EventTypeNotificationSend,
+		Severity:     models.EventSeverityError,
+		Title:        "Notification failed via ntfy",
+		Description:  "redis:7: connection refused",
+		ResourceName: &legacy,
+		Metadata:     models.JSON{"provider": "ntfy", "status": "failed"},
+		Timestamp:    time.Now().Add(-10 * 24 * time.Hour),
+	}).Error)
+
+	params := pagination.QueryParams{
+		SortParams: pagination.SortParams{Sort: "timestamp", Order: pagination.SortDesc},
+		Params:     pagination.Params{Limit: 20},
+	}
+
+	logs, resp, err := svc.ListNotificationLogs(ctx, NotificationLogFilter{}, params)
+	require.NoError(t, err)
+	require.Len(t, logs, 4)
+	require.Equal(t, int64(4), resp.TotalItems)
+
+	logs, _, err = svc.ListNotificationLogs(ctx, NotificationLogFilter{Status: "failed"}, params)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, notificationdto.NotificationProviderNtfy, logs[0].Provider)
+	require.Equal(t, "failed", logs[0].Status)
+	require.Equal(t, "nginx:latest", logs[0].Subject)
+	require.Equal(t, "boom", logs[0].Error)
+	require.Nil(t, logs[0].Metadata)
+	require.Equal(t, "redis:7", logs[1].Subject)
+	require.Equal(t, "connection refused", logs[1].Error)
+
+	logs, _, err = svc.ListNotificationLogs(ctx, NotificationLogFilter{Provider: "gotify", Status: "success,skipped"}, params)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, notificationStatusSkippedDuplicate, logs[0].Status)
+	require.Equal(t, "env-2", logs[0].EnvironmentID)
+	require.Equal(t, "image_update", logs[1].Metadata["eventType"])
+
+	from := time.Now().Add(-time.Hour)
+	logs, _, err = svc.ListNotificationLogs(ctx, NotificationLogFilter{EnvironmentID: "0", From: &from}, params)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+
+	_, _, err = svc.ListNotificationLogs(ctx, NotificationLogFilter{Status: "sent"}, params)
+	require.ErrorIs(t, err, common.ErrValidation)
+
+	deleted, err := svc.DeleteOldNotificationLogs(ctx, 7*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
+
 func TestSupportedNotificationTestTypes_IncludesAutoHeal(t *testing.T) {
 	expected := []string{
 		notificationTestTypeSimple,
@@ -732,3 +805,135 @@ func TestSupportedNotificationTestTypes_IncludesAutoHeal(t *testing.T) {
 	require.Equal(t, len(expected), len(supportedNotificationTestTypes),
 		"supportedNotificationTestTypes has unexpected entries")
 }
+
+func TestNotificationService_TestProvider_ReturnsRenderedPreview(t *testing.T) {
+	ctx := context.Background()
+	db, _, svc := setupNotificationTestServiceInternal(t)
+
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		received.Store(payload)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	require.NoError(t, db.WithContext(ctx).Create(&models.NotificationSettings{
+		Provider: models.NotificationProviderGeneric,
+		Enabled:  false,
+		Config:   models.JSON{"webhookUrl": server.URL, "successBodyContains": "ok"},
+	}).Error)
+	require.NoError(t, db.WithContext(ctx).Create(&models.NotificationSettings{
+		Provider: models.NotificationProviderEmail,
+		Enabled:  true,
+		Config:   models.JSON{},
+	}).Error)
+
+	result, err := svc.TestProvider(ctx, models.NotificationProviderGeneric)
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Empty(t, result.Error)
+	require.Equal(t, notificationdto.NotificationProviderGeneric, result.Provider)
+	require.Equal(t, "Container Image Update", result.Title)
+	require.Contains(t, result.Body, "Local Docker")
+	require.Contains(t, result.Warning, "disabled")
+	require.NotNil(t, received.Load())
+
+	// A failed send still returns the rendered email for preview.
+	result, err = svc.TestProvider(ctx, models.NotificationProviderEmail)
+	require.NoError(t, err)
+	require.False(t, result.Success)
+	require.Contains(t, result.Error, "SMTP host or port not configured")
+	require.Contains(t, result.Title, "Test Email from Arcane")
+	require.Contains(t, result.HTML, "Local Docker")
+
+	_, err = svc.TestProvider(ctx, models.NotificationProviderSlack)
+	require.Error(t, err)
+}
+
+func TestNotificationService_SendImageUpdateNotification_SkipsDuplicateWithinCooldown(t *testing.T) {
+	ctx := context.Background()
+	db, _, svc := setupNotificationTestServiceInternal(t)
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	require.NoError(t, db.WithContext(ctx).Create(&models.NotificationSettings{
+		Provider: models.NotificationProviderGeneric,
+		Enabled:  true,
+		Config:   models.JSON{"webhookUrl": server.URL, "successBodyContains": "ok"},
+	}).Error)
+
+	updateInfo := newNotificationTestUpdateInfoInternal()
+	for range 2 {
+		delivered, err := svc.SendImageUpdateNotification(ctx, "nginx:latest", updateInfo, models.NotificationEventImageUpdate)
+		require.NoError(t, err)
+		require.Equal(t, 1, delivered)
+	}
+	require.Equal(t, int32(1), hits.Load())
+
+	var skipped []models.Event
+	require.NoError(t, db.WithContext(ctx).Where("type = ? AND severity = ?", models.EventTypeNotificationSend, models.EventSeverityInfo).Find(&skipped).Error)
+	require.Len(t, skipped, 1)
+	require.Equal(t, notificationStatusSkippedDuplicate, skipped[0].Metadata["status"])
+
+	// A new digest for the same image is a new notification.
+	newer := newNotificationTestUpdateInfoInternal()
+	newer.LatestDigest = "sha256:newer"
+	delivered, err := svc.SendBatchImageUpdateNotification(ctx, map[string]*imageupdate.Response{
+		"nginx:latest": newer,
+		"redis:7":      updateInfo,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+	require.Equal(t, int32(2), hits.Load())
+
+	_, err = svc.SendBatchImageUpdateNotification(ctx, map[string]*imageupdate.Response{"nginx:latest": newer})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), hits.Load())
+}
+
+func TestNotificationService_SendBatchImageUpdateNotification_RecordsOnlySentImages(t *testing.T) {
+	ctx := context.Background()
+	db, _, svc := setupNotificationTestServiceInternal(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	require.NoError(t, db.WithContext(ctx).Create(&models.NotificationSettings{
+		Provider: models.NotificationProviderGeneric,
+		Enabled:  true,
+		Config:   models.JSON{"webhookUrl": server.URL, "successBodyContains": "ok"},
+	}).Error)
+
+	updateInfo := newNotificationTestUpdateInfoInternal()
+	_, err := svc.SendImageUpdateNotification(ctx, "nginx:latest", updateInfo, models.NotificationEventImageUpdate)
+	require.NoError(t, err)
+
+	redis := newNotificationTestUpdateInfoInternal()
+	redis.LatestDigest = "sha256:redis"
+	delivered, err := svc.SendBatchImageUpdateNotification(ctx, map[string]*imageupdate.Response{
+		"nginx:latest": updateInfo,
+		"redis:7":      redis,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+
+	var sent []models.Event
+	require.NoError(t, db.WithContext(ctx).
+		Where("type = ? AND severity = ?", models.EventTypeNotificationSend, models.EventSeveritySuccess).
+		Find(&sent).Error)
+	require.Len(t, sent, 2)
+	batch := sent[0]
+	if batch.Metadata["batch"] != true {
+		batch = sent[1]
+	}
+	require.Equal(t, map[string]any{"redis:7": "sha256:redis"}, batch.Metadata[notificationImagesMetadataKey])
+}
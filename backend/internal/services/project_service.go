@@ -2,7 +2,9 @@ package services
 
 import (
 	"bufio"
+	"cmp"
 	"context"
+	"encoding/json/jsontext"
 	json "encoding/json/v2"
 	stderrors "errors"
 	"fmt"
@@ -23,6 +25,7 @@ import (
 	"github.com/compose-spec/compose-go/v2/dotenv"
 	"github.com/compose-spec/compose-go/v2/loader"
 	composetypes "github.com/compose-spec/compose-go/v2/types"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
@@ -41,6 +44,7 @@ import (
 	imagetypes "github.com/getarcaneapp/arcane/types/v2/image"
 	"github.com/getarcaneapp/arcane/types/v2/project"
 	"github.com/moby/moby/api/types/container"
+	networktypes "github.com/moby/moby/api/types/network"
 	dockerregistry "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
 	"github.com/samber/hot"
@@ -2122,13 +2126,14 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 	// `sops -d secrets.enc.env > .env.runtime` for an `env_file: .env.runtime`
 	// service). A failed hook aborts the deploy.
 	if s.lifecycleService != nil {
+		projects.ReportDeployStage(ctx, project.DeployProgressStageHooks)
 		if lerr := s.lifecycleService.RunPreDeploy(ctx, projectFromDb, user); lerr != nil {
 			s.restoreProjectStatusAfterFailedDeployInternal(ctx, projectID)
 			return errors.WrapIf(lerr, "pre-deploy lifecycle hook failed")
 		}
 	}
 
-	project, _, derr := s.loadComposeProjectForProjectInternal(ctx, projectFromDb, nil)
+	composeProject, _, derr := s.loadComposeProjectForProjectInternal(ctx, projectFromDb, nil)
 	if derr != nil {
 		s.restoreProjectStatusAfterFailedDeployInternal(ctx, projectID)
 		return errors.WrapIff(derr, "failed to load compose project in %s", projectFromDb.Path)
@@ -2140,8 +2145,9 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 		return errors.WrapIf(cerr, "resolve registry credentials")
 	}
 
+	projects.ReportDeployStage(ctx, project.DeployProgressStageImages)
 	progressWriter, _ := ctx.Value(dockerutil.ProgressWriterKey{}).(io.Writer)
-	if perr := s.prepareProjectImagesForDeploy(ctx, projectID, project, progressWriter, credentials, &user, resolvedPullPolicy); perr != nil {
+	if perr := s.prepareProjectImagesForDeploy(ctx, projectID, composeProject, progressWriter, credentials, &user, resolvedPullPolicy); perr != nil {
 		s.restoreProjectStatusAfterFailedDeployInternal(ctx, projectID)
 		return errors.WrapIf(perr, "failed to prepare project images for deploy")
 	}
@@ -2149,10 +2155,11 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 	gitOpsManaged := projectFromDb.GitOpsManagedBy != nil && *projectFromDb.GitOpsManagedBy != ""
 	removeOrphans := resolveRemoveOrphansInternal(gitOpsManaged, options)
 
-	slog.Info("starting compose up with health check support", "projectID", projectID, "projectName", project.Name, "services", len(project.Services), "removeOrphans", removeOrphans)
+	slog.Info("starting compose up with health check support", "projectID", projectID, "projectName", composeProject.Name, "services", len(composeProject.Services), "removeOrphans", removeOrphans)
 	// Health/progress streaming (if any) is handled inside projects.ComposeUp via ctx.
-	if err := projects.ComposeUp(ctx, project, nil, removeOrphans, forceRecreate, s.composeRegistryAuthConfigsInternal(ctx)); err != nil {
-		slog.Error("compose up failed", "projectName", project.Name, "projectID", projectID, "error", err)
+	projects.ReportDeployStage(ctx, project.DeployProgressStageUp)
+	if err := projects.ComposeUp(ctx, composeProject, nil, removeOrphans, forceRecreate, s.composeRegistryAuthConfigsInternal(ctx)); err != nil {
+		slog.Error("compose up failed", "projectName", composeProject.Name, "projectID", projectID, "error", err)
 		if containers, psErr := s.GetProjectServices(ctx, projectID); psErr == nil {
 			slog.Info("containers after failed deploy", "projectID", projectID, "containers", containers)
 		}
@@ -2165,10 +2172,13 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 		}
 		return errors.WrapIf(err, "failed to deploy project")
 	}
-	slog.Info("compose up completed successfully", "projectID", projectID, "projectName", project.Name)
+	slog.Info("compose up completed successfully", "projectID", projectID, "projectName", composeProject.Name)
+	if !removeOrphans {
+		s.warnProjectOrphansAfterDeployInternal(ctx, projectID, composeProject, progressWriter)
+	}
 
-	metadata := models.JSON{"action": "deploy", "projectID": projectID, "projectName": project.Name}
-	s.logProjectEventInternal(ctx, models.EventTypeProjectDeploy, projectID, project.Name, user, metadata, "could not log project deployment action")
+	metadata := models.JSON{"action": "deploy", "projectID": projectID, "projectName": composeProject.Name}
+	s.logProjectEventInternal(ctx, models.EventTypeProjectDeploy, projectID, composeProject.Name, user, metadata, "could not log project deployment action")
 
 	err = s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusRunning)
 	if err != nil {
@@ -2208,6 +2218,31 @@ func (s *ProjectService) DownProject(ctx context.Context, projectID string, user
 	return s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusStopped)
 }
 
+// projectNameTakenInternal reports whether a project named name already exists,
+// either as a database row or as a directory CreateProject would collide with.
+func (s *ProjectService) projectNameTakenInternal(ctx context.Context, name string) (bool, error) {
+	sanitized := projects.SanitizeProjectName(name)
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Project{}).Where("name = ? OR dir_name = ?", name, sanitized).Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check existing projects")
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	projectsDirectory, err := s.getProjectsDirectoryInternal(ctx)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to get projects directory")
+	}
+	if _, err := os.Stat(filepath.Join(projectsDirectory, sanitized)); err == nil {
+		return true, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, errors.WrapIf(err, "failed to check project directory")
+	}
+	return false, nil
+}
+
 func (s *ProjectService) CreateProject(ctx context.Context, name, composeContent string, envContent *string, projectFiles []project.ProjectFileDraft, user models.User) (*models.Project, error) {
 	return s.createProjectInternal(ctx, name, composeContent, envContent, projectFiles, user, true)
 }
@@ -4169,6 +4204,377 @@ func (s *ProjectService) StreamProjectLogs(ctx context.Context, projectID string
 	return nil
 }
 
+const (
+	// projectStatsInterval is how often StreamProjectStats emits a combined snapshot.
+	projectStatsInterval = 2 * time.Second
+	// projectStatsResolveInterval is how often StreamProjectStats re-lists the
+	// project's containers to pick up ones that started or stopped.
+	projectStatsResolveInterval = 10 * time.Second
+)
+
+// projectStatsStream is the stats stream of one project container.
+type projectStatsStream struct {
+	cancel context.CancelFunc
+}
+
+// StreamProjectStats sends a combined resource snapshot of the project's
+// running containers to statsChan every projectStatsInterval. Each container's
+// Docker stats stream is followed individually, and membership is re-resolved
+// from the compose project label every projectStatsResolveInterval so
+// containers that start or stop mid-stream are added or dropped.
+func (s *ProjectService) StreamProjectStats(ctx context.Context, projectID string, statsChan chan<- project.Stats) error {
+	proj, err := s.GetProjectFromDatabaseByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	projectName := projects.NormalizeProjectName(proj.Name)
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		streams = map[string]*projectStatsStream{}
+		latest  = map[string]project.ContainerStats{}
+	)
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resolve := func() error {
+		filter := make(client.Filters).Add("label", dockerutil.ComposeProjectLabelKey+"="+projectName)
+		list, err := dockerClient.ContainerList(ctx, client.ContainerListOptions{Filters: filter})
+		if err != nil {
+			return errors.WrapIf(err, "failed to list project containers")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		running := make(map[string]struct{}, len(list.Items))
+		for _, c := range list.Items {
+			running[c.ID] = struct{}{}
+			if _, ok := streams[c.ID]; ok {
+				continue
+			}
+			streamCtx, stop := context.WithCancel(ctx)
+			stream := &projectStatsStream{cancel: stop}
+			streams[c.ID] = stream
+			base := project.ContainerStats{
+				ID:      c.ID,
+				Name:    dockerutil.ContainerNameFromNames(c.Names),
+				Service: dockerutil.ComposeServiceLabel(c.Labels),
+			}
+			wg.Go(func() {
+				err := followProjectContainerStatsInternal(streamCtx, dockerClient, base, func(sample project.ContainerStats) {
+					mu.Lock()
+					if streams[base.ID] == stream {
+						latest[base.ID] = sample
+					}
+					mu.Unlock()
+				})
+				if err != nil && streamCtx.Err() == nil {
+					slog.DebugContext(ctx, "Project container stats stream stopped", "projectId", projectID, "containerId", base.ID, "error", err)
+				}
+				mu.Lock()
+				if streams[base.ID] == stream {
+					delete(streams, base.ID)
+					delete(latest, base.ID)
+				}
+				mu.Unlock()
+				stop()
+			})
+		}
+		for id, stream := range streams {
+			if _, ok := running[id]; !ok {
+				stream.cancel()
+				delete(streams, id)
+				delete(latest, id)
+			}
+		}
+		return nil
+	}
+
+	if err := resolve(); err != nil {
+		return err
+	}
+
+	emitTicker := time.NewTicker(projectStatsInterval)
+	defer emitTicker.Stop()
+	resolveTicker := time.NewTicker(projectStatsResolveInterval)
+	defer resolveTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resolveTicker.C:
+			if err := resolve(); err != nil {
+				slog.DebugContext(ctx, "Failed to re-resolve project containers for stats", "projectId", projectID, "error", err)
+			}
+		case now := <-emitTicker.C:
+			mu.Lock()
+			snapshot := summarizeProjectStatsInternal(projectID, projectName, now, slices.Collect(maps.Values(latest)))
+			mu.Unlock()
+			select {
+			case statsChan <- snapshot:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// followProjectContainerStatsInternal decodes the container's Docker stats
+// stream and reports each sample until the stream or ctx ends.
+func followProjectContainerStatsInternal(ctx context.Context, dockerClient *client.Client, base project.ContainerStats, onSample func(project.ContainerStats)) error {
+	stats, err := dockerClient.ContainerStats(ctx, base.ID, client.ContainerStatsOptions{Stream: true})
+	if err != nil {
+		return errors.WrapIf(err, "failed to start stats stream")
+	}
+	defer func() { _ = stats.Body.Close() }()
+
+	decoder := jsontext.NewDecoder(stats.Body)
+	for {
+		var statsData container.StatsResponse
+		if err := json.UnmarshalDecode(decoder, &statsData); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.WrapIf(err, "failed to decode stats")
+		}
+
+		sample := base
+		sample.CPUPercent = containerCPUPercentInternal(statsData)
+		sample.MemoryUsage = containerMemoryUsageInternal(statsData)
+		sample.MemoryLimit = statsData.MemoryStats.Limit
+		onSample(sample)
+	}
+}
+
+// summarizeProjectStatsInternal sums the container samples into one snapshot,
+// listing the containers by name.
+func summarizeProjectStatsInternal(projectID, projectName string, now time.Time, samples []project.ContainerStats) project.Stats {
+	if samples == nil {
+		samples = []project.ContainerStats{}
+	}
+	slices.SortFunc(samples, func(a, b project.ContainerStats) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.ID, b.ID))
+	})
+
+	out := project.Stats{
+		ProjectID:   projectID,
+		ProjectName: projectName,
+		Timestamp:   now,
+		Containers:  samples,
+	}
+	for _, sample := range samples {
+		out.CPUPercent += sample.CPUPercent
+		out.MemoryUsage += sample.MemoryUsage
+	}
+	return out
+}
+
+// containerCPUPercentInternal returns the container's share of host CPU between
+// the two readings of a stats sample, matching the container stats view.
+func containerCPUPercentInternal(stats container.StatsResponse) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	return min(cpuDelta/systemDelta*100, 100)
+}
+
+// containerMemoryUsageInternal returns memory usage without the inactive page
+// cache, which the kernel can reclaim at any time.
+func containerMemoryUsageInternal(stats container.StatsResponse) uint64 {
+	usage := stats.MemoryStats.Usage
+	inactive := stats.MemoryStats.Stats["inactive_file"]
+	if inactive > usage {
+		return 0
+	}
+	return usage - inactive
+}
+
+// GetProjectOrphans compares the project's rendered compose file with the
+// containers and networks labelled with its compose project name and returns
+// the ones the file no longer defines, such as the containers of a service
+// that was removed before the last redeploy. A deploy only cleans these up when
+// remove-orphans is enabled, so they can otherwise keep running unnoticed.
+func (s *ProjectService) GetProjectOrphans(ctx context.Context, projectID string) (*project.Orphans, error) {
+	proj, err := s.GetProjectFromDatabaseByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get project")
+	}
+	composeProject, _, err := s.loadComposeProjectForProjectInternal(ctx, proj, nil)
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to load compose project in %s", proj.Path)
+	}
+
+	orphans, err := s.listProjectOrphansInternal(ctx, projectID, composeProject)
+	if err != nil {
+		return nil, err
+	}
+	return &orphans, nil
+}
+
+func (s *ProjectService) listProjectOrphansInternal(ctx context.Context, projectID string, composeProject *composetypes.Project) (project.Orphans, error) {
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return project.Orphans{}, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	filter := make(client.Filters).Add("label", api.ProjectLabel+"="+composeProject.Name)
+	containers, err := dockerClient.ContainerList(ctx, client.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return project.Orphans{}, errors.WrapIf(err, "failed to list project containers")
+	}
+	networks, err := dockerClient.NetworkList(ctx, client.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return project.Orphans{}, errors.WrapIf(err, "failed to list project networks")
+	}
+
+	return buildProjectOrphansInternal(projectID, composeProject, containers.Items, networks.Items), nil
+}
+
+// warnProjectOrphansAfterDeployInternal logs, and writes to the deploy's
+// progress stream, any resources a deploy without remove-orphans left behind.
+// Lookup failures only skip the warning.
+func (s *ProjectService) warnProjectOrphansAfterDeployInternal(ctx context.Context, projectID string, composeProject *composetypes.Project, progressWriter io.Writer) {
+	orphans, err := s.listProjectOrphansInternal(ctx, projectID, composeProject)
+	if err != nil {
+		slog.DebugContext(ctx, "skipping orphan check after deploy", "projectID", projectID, "error", err)
+		return
+	}
+	if len(orphans.Containers) == 0 && len(orphans.Networks) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(orphans.Containers)+len(orphans.Networks))
+	for _, c := range orphans.Containers {
+		names = append(names, c.Name)
+	}
+	for _, n := range orphans.Networks {
+		names = append(names, n.Name)
+	}
+	slog.WarnContext(ctx, "project has orphaned resources from an earlier compose revision", "projectID", projectID, "projectName", composeProject.Name, "resources", names)
+
+	if progressWriter == nil {
+		return
+	}
+	logWriter := dockerutil.NewLogLineWriter(progressWriter)
+	_, _ = fmt.Fprintf(logWriter, "Warning: found orphaned resources no longer defined in the compose file: %s. Prune them or deploy with remove orphans enabled.\n", strings.Join(names, ", "))
+	_ = logWriter.Close()
+}
+
+// PruneProjectOrphans removes the project's orphaned containers, then its
+// orphaned networks. Removal keeps going past individual failures, which are
+// reported in the result; a network still attached to a container outside the
+// project is one such failure.
+func (s *ProjectService) PruneProjectOrphans(ctx context.Context, projectID string, user models.User) (*project.PruneOrphansResult, error) {
+	proj, err := s.getMutableProjectInternal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	orphans, err := s.GetProjectOrphans(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	result := &project.PruneOrphansResult{ContainersRemoved: []string{}, NetworksRemoved: []string{}}
+	for _, orphan := range orphans.Containers {
+		if _, err := dockerClient.ContainerRemove(ctx, orphan.ID, client.ContainerRemoveOptions{Force: true}); err != nil && !cerrdefs.IsNotFound(err) {
+			result.Errors = append(result.Errors, fmt.Sprintf("container %s: %v", orphan.Name, err))
+			continue
+		}
+		result.ContainersRemoved = append(result.ContainersRemoved, orphan.Name)
+	}
+	for _, orphan := range orphans.Networks {
+		if _, err := dockerClient.NetworkRemove(ctx, orphan.ID, client.NetworkRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
+			result.Errors = append(result.Errors, fmt.Sprintf("network %s: %v", orphan.Name, err))
+			continue
+		}
+		result.NetworksRemoved = append(result.NetworksRemoved, orphan.Name)
+	}
+
+	metadata := models.JSON{
+		"action":            "prune_orphans",
+		"projectID":         projectID,
+		"projectName":       proj.Name,
+		"containersRemoved": result.ContainersRemoved,
+		"networksRemoved":   result.NetworksRemoved,
+	}
+	s.logProjectEventInternal(ctx, models.EventTypeProjectUpdate, projectID, proj.Name, user, metadata, "could not log project orphan prune action")
+
+	if len(result.ContainersRemoved) > 0 {
+		if err := s.updateProjectStatusandCountsInternal(ctx, projectID, proj.Status); err != nil {
+			slog.WarnContext(ctx, "failed to refresh project counts after pruning orphans", "projectID", projectID, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// buildProjectOrphansInternal picks the labelled containers and networks that
+// composeProject does not define. Services disabled by an inactive profile still
+// count as defined, and one-off `compose run` containers are never orphans,
+// matching what compose itself removes with --remove-orphans.
+func buildProjectOrphansInternal(projectID string, composeProject *composetypes.Project, containers []container.Summary, networks []networktypes.Summary) project.Orphans {
+	orphans := project.Orphans{
+		ProjectID:   projectID,
+		ProjectName: composeProject.Name,
+		Containers:  []project.OrphanContainer{},
+		Networks:    []project.OrphanNetwork{},
+	}
+
+	for _, c := range containers {
+		if c.Labels[api.OneoffLabel] == "True" {
+			continue
+		}
+		service := dockerutil.ComposeServiceLabel(c.Labels)
+		if _, ok := composeProject.Services[service]; ok {
+			continue
+		}
+		if _, ok := composeProject.DisabledServices[service]; ok {
+			continue
+		}
+		orphans.Containers = append(orphans.Containers, project.OrphanContainer{
+			ID:      c.ID,
+			Name:    dockerutil.ContainerNameFromNames(c.Names),
+			Service: service,
+			Image:   c.Image,
+			State:   string(c.State),
+		})
+	}
+
+	for _, n := range networks {
+		key := n.Labels[api.NetworkLabel]
+		if _, ok := composeProject.Networks[key]; ok {
+			continue
+		}
+		orphans.Networks = append(orphans.Networks, project.OrphanNetwork{
+			ID:      n.ID,
+			Name:    n.Name,
+			Network: key,
+		})
+	}
+
+	slices.SortFunc(orphans.Containers, func(a, b project.OrphanContainer) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(orphans.Networks, func(a, b project.OrphanNetwork) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return orphans
+}
+
 // End Project Actions
 
 // Table Functions
@@ -32,6 +32,7 @@ import (
 	sqlite "github.com/libtnb/sqlite"
 	"github.com/moby/moby/api/types/container"
 	dockertypesimage "github.com/moby/moby/api/types/image"
+	networktypes "github.com/moby/moby/api/types/network"
 	dockerregistry "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/api/types/volume"
 	"github.com/opencontainers/go-digest"
@@ -634,7 +635,7 @@ func TestProjectService_PullProjectImages_UpdatesCurrentImageRecordAfterPull(t *
 	dockerService := &DockerClientService{client: newTestDockerClient(t, server)}
 	eventService := NewEventService(db, nil, nil)
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService)
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService, nil)
 	svc := NewProjectService(db, settingsService, nil, imageService, dockerService, nil, nil, nil, config.Load())
 
 	projectPath := createComposeProjectDir(t, projectsDir, "compose-pull")
@@ -730,7 +731,7 @@ func TestProjectService_EnsureImagesPresent_UpdatesCurrentImageRecordAfterPull(t
 	dockerService := &DockerClientService{client: newTestDockerClient(t, server)}
 	eventService := NewEventService(db, nil, nil)
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService)
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService, nil)
 	svc := NewProjectService(db, settingsService, nil, imageService, dockerService, nil, nil, nil, config.Load())
 
 	require.NoError(t, db.Create(&models.ImageUpdateRecord{
@@ -782,7 +783,7 @@ func TestProjectService_PullImageForService_UpdatesCurrentImageRecordAfterPull(t
 	dockerService := &DockerClientService{client: newTestDockerClient(t, server)}
 	eventService := NewEventService(db, nil, nil)
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService)
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService, nil)
 	svc := NewProjectService(db, settingsService, nil, imageService, dockerService, nil, nil, nil, config.Load())
 
 	require.NoError(t, db.Create(&models.ImageUpdateRecord{
@@ -845,7 +846,7 @@ func TestProjectService_ComposePullSelectedServicesInternal_ReconcilesOnlyOnSucc
 	dockerService := &DockerClientService{client: newTestDockerClient(t, server)}
 	eventService := NewEventService(db, nil, nil)
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService)
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, eventService, nil)
 	svc := NewProjectService(db, settingsService, nil, imageService, dockerService, nil, nil, nil, config.Load())
 
 	projectDef := &composetypes.Project{
@@ -949,7 +950,7 @@ func TestProjectService_ComposePullSelectedServicesInternal_LeavesRecordsWhenPul
 
 	dockerService := &DockerClientService{client: newTestDockerClient(t, failingServer)}
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, NewEventService(db, nil, nil))
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, NewEventService(db, nil, nil), nil)
 	svc := NewProjectService(db, settingsService, nil, imageService, dockerService, nil, nil, nil, config.Load())
 
 	projectDef := &composetypes.Project{
@@ -1007,7 +1008,7 @@ func TestProjectService_UpdateProjectServicesHardFailsWhenPullFailsInternal(t *t
 
 	dockerService := &DockerClientService{client: newTestDockerClient(t, failingServer)}
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, NewEventService(db, nil, nil))
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, NewEventService(db, nil, nil), nil)
 
 	projectPath := createComposeProjectDir(t, projectsDir, "compose-update-pull-fail")
 	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "compose.yaml"), []byte("services:\n  app:\n    image: "+imageRef+"\n"), 0o644))
@@ -1080,7 +1081,7 @@ func TestProjectService_UpdateProjectServicesForcesRecreateInternal(t *testing.T
 
 	dockerService := &DockerClientService{client: newTestDockerClient(t, server)}
 	imageUpdateService := NewImageUpdateService(db, nil, nil, dockerService, nil, nil, nil)
-	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, NewEventService(db, nil, nil))
+	imageService := NewImageService(db, dockerService, nil, imageUpdateService, nil, NewEventService(db, nil, nil), nil)
 
 	projectPath := createComposeProjectDir(t, projectsDir, "compose-update-force")
 	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "compose.yaml"), []byte("services:\n  app:\n    image: "+imageRef+"\n"), 0o644))
@@ -7560,3 +7561,76 @@ func TestProjectUpdateBackup_RenamedDirDemotedToCopyWhenBatchTouchesIt(t *testin
 	require.FileExists(t, filepath.Join(projectPath, "a", "keep.txt"))
 	require.NoDirExists(t, filepath.Join(projectPath, "b"))
 }
+
+func TestSummarizeProjectStatsInternal(t *testing.T) {
+	sample := func(total, preTotal, system, preSystem, usage, inactive uint64) container.StatsResponse {
+		var stats container.StatsResponse
+		stats.CPUStats.CPUUsage.TotalUsage = total
+		stats.CPUStats.SystemUsage = system
+		stats.PreCPUStats.CPUUsage.TotalUsage = preTotal
+		stats.PreCPUStats.SystemUsage = preSystem
+		stats.MemoryStats.Usage = usage
+		stats.MemoryStats.Stats = map[string]uint64{"inactive_file": inactive}
+		return stats
+	}
+
+	web := sample(300, 100, 2000, 1000, 512, 128)
+	require.InDelta(t, 20.0, containerCPUPercentInternal(web), 0.001)
+	require.Equal(t, uint64(384), containerMemoryUsageInternal(web))
+	// A sample without a previous reading, or with more inactive cache than
+	// usage, reports zero rather than a bogus value.
+	require.Zero(t, containerCPUPercentInternal(sample(300, 0, 2000, 2000, 0, 0)))
+	require.Zero(t, containerMemoryUsageInternal(sample(0, 0, 0, 0, 64, 128)))
+
+	now := time.Now()
+	stats := summarizeProjectStatsInternal("proj-1", "media", now, []projecttypes.ContainerStats{
+		{ID: "b", Name: "media-worker-1", Service: "worker", CPUPercent: 5, MemoryUsage: 100},
+		{ID: "a", Name: "media-api-1", Service: "api", CPUPercent: 12.5, MemoryUsage: 300},
+	})
+	require.Equal(t, "proj-1", stats.ProjectID)
+	require.Equal(t, "media", stats.ProjectName)
+	require.Equal(t, now, stats.Timestamp)
+	require.InDelta(t, 17.5, stats.CPUPercent, 0.001)
+	require.Equal(t, uint64(400), stats.MemoryUsage)
+	require.Equal(t, []string{"media-api-1", "media-worker-1"}, []string{stats.Containers[0].Name, stats.Containers[1].Name})
+
+	empty := summarizeProjectStatsInternal("proj-1", "media", now, nil)
+	require.NotNil(t, empty.Containers)
+	require.Empty(t, empty.Containers)
+}
+
+func TestBuildProjectOrphansInternal(t *testing.T) {
+	composeProject := &composetypes.Project{
+		Name:             "shop",
+		Services:         composetypes.Services{"web": {Name: "web"}},
+		DisabledServices: composetypes.Services{"debug": {Name: "debug"}},
+		Networks:         composetypes.Networks{"default": {Name: "shop_default"}},
+	}
+	labels := func(service string) map[string]string {
+		return map[string]string{composeapi.ProjectLabel: "shop", composeapi.ServiceLabel: service, composeapi.OneoffLabel: "False"}
+	}
+
+	oneOff := labels("migrate")
+	oneOff[composeapi.OneoffLabel] = "True"
+	containers := []container.Summary{
+		{ID: "c1", Names: []string{"/shop-web-1"}, Labels: labels("web"), State: container.StateRunning},
+		{ID: "c2", Names: []string{"/shop-worker-1"}, Labels: labels("worker"), Image: "shop/worker", State: container.StateRunning},
+		{ID: "c3", Names: []string{"/shop-cache-1"}, Labels: labels("cache"), Image: "redis:7", State: container.StateExited},
+		{ID: "c4", Names: []string{"/shop-debug-1"}, Labels: labels("debug"), State: container.StateExited},
+		{ID: "c5", Names: []string{"/shop-migrate-run-1"}, Labels: oneOff, State: container.StateExited},
+	}
+	networks := []networktypes.Summary{
+		{Network: networktypes.Network{ID: "n1", Name: "shop_default", Labels: map[string]string{composeapi.NetworkLabel: "default"}}},
+		{Network: networktypes.Network{ID: "n2", Name: "shop_backend", Labels: map[string]string{composeapi.NetworkLabel: "backend"}}},
+	}
+
+	orphans := buildProjectOrphansInternal("proj-1", composeProject, containers, networks)
+
+	require.Equal(t, "proj-1", orphans.ProjectID)
+	require.Equal(t, "shop", orphans.ProjectName)
+	require.Equal(t, []projecttypes.OrphanContainer{
+		{ID: "c3", Name: "shop-cache-1", Service: "cache", Image: "redis:7", State: "exited"},
+		{ID: "c2", Name: "shop-worker-1", Service: "worker", Image: "shop/worker", State: "running"},
+	}, orphans.Containers)
+	require.Equal(t, []projecttypes.OrphanNetwork{{ID: "n2", Name: "shop_backend", Network: "backend"}}, orphans.Networks)
+}
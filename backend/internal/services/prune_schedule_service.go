@@ -0,0 +1,611 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/types/v2"
+	schedulertypes "github.com/getarcaneapp/arcane/types/v2/scheduler"
+	"github.com/getarcaneapp/arcane/types/v2/system"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const pruneScheduleJobPrefix = "prune-schedule:"
+
+// pruneScheduleRunHistoryLimit is how many runs are kept per schedule. Older
+// runs are dropped as new ones are recorded.
+const pruneScheduleRunHistoryLimit = 100
+
+// The global scheduled prune that prune schedules replaced kept its on/off
+// switch and cron expression in these settings. ImportLegacyScheduledPrune reads
+// them once and deletes them.
+const (
+	legacyScheduledPruneEnabledKey      = "scheduledPruneEnabled"
+	legacyScheduledPruneIntervalKey     = "scheduledPruneInterval"
+	legacyScheduledPruneDefaultInterval = "0 0 0 * * *"
+	legacyScheduledPruneName            = "Scheduled prune"
+
+	// Deployments that configure settings through the environment (agents,
+	// UI_CONFIGURATION_DISABLED) set the legacy prune with these variables
+	// instead of settings rows.
+	legacyScheduledPruneEnabledEnv  = "SCHEDULED_PRUNE_ENABLED"
+	legacyScheduledPruneIntervalEnv = "SCHEDULED_PRUNE_INTERVAL"
+)
+
+// pruneScheduleParser accepts the same expressions as the job scheduler: six
+// fields with seconds, plus @-descriptors such as @daily.
+var pruneScheduleParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+func pruneScheduleJobNameInternal(scheduleID string) string {
+	return pruneScheduleJobPrefix + scheduleID
+}
+
+// PruneScheduleService manages per-environment prune policies that run on a
+// cron schedule, and records what each run removed.
+type PruneScheduleService struct {
+	db                  *database.DB
+	systemService       *SystemService
+	settingsService     *SettingsService
+	notificationService *NotificationService
+	location            *time.Location
+
+	// scheduler and lifecycleCtx are injected post-construction via SetScheduler.
+	scheduler    DynamicScheduler
+	lifecycleCtx context.Context
+}
+
+func NewPruneScheduleService(db *database.DB, systemService *SystemService, settingsService *SettingsService, notificationService *NotificationService, cfg *config.Config) *PruneScheduleService {
+	return &PruneScheduleService{
+		db:                  db,
+		systemService:       systemService,
+		settingsService:     settingsService,
+		notificationService: notificationService,
+		location:            cfg.GetLocation(),
+	}
+}
+
+// SetScheduler injects the job scheduler and the app lifecycle context. It must be
+// called during bootstrap before RegisterSchedulesOnStartup.
+func (s *PruneScheduleService) SetScheduler(ctx context.Context, scheduler DynamicScheduler) { //nolint:contextcheck // scheduled prunes must capture the app lifecycle context, not request contexts
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s.lifecycleCtx = ctx
+	s.scheduler = scheduler
+}
+
+func (s *PruneScheduleService) schedulerCtxInternal(ctx context.Context) context.Context {
+	if s.lifecycleCtx != nil {
+		return s.lifecycleCtx
+	}
+	if ctx != nil {
+		return context.WithoutCancel(ctx)
+	}
+	return context.Background()
+}
+
+// RegisterSchedulesOnStartup installs a dynamic job for every enabled schedule.
+func (s *PruneScheduleService) RegisterSchedulesOnStartup(ctx context.Context) {
+	if s.scheduler == nil {
+		return
+	}
+
+	var schedules []models.PruneSchedule
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to load prune schedules", "error", err)
+		return
+	}
+	for i := range schedules {
+		s.registerScheduleJobInternal(ctx, &schedules[i])
+	}
+	if len(schedules) > 0 {
+		slog.InfoContext(ctx, "Registered prune schedules", "count", len(schedules))
+	}
+}
+
+// ImportLegacyScheduledPrune turns the old global scheduled prune into a prune
+// schedule for the local environment, so only one prune scheduler exists. When
+// it was enabled, its cron expression and the prune mode settings it used to
+// read on every run become an enabled schedule; either way the legacy settings
+// are deleted so the import runs once.
+//
+// The legacy environment variables override the settings rows, as they did for
+// the old job. They cannot be deleted, so they are only imported while no
+// schedule of the legacy name exists, and a deprecation warning is logged on
+// every startup until they are removed.
+func (s *PruneScheduleService) ImportLegacyScheduledPrune(ctx context.Context) error {
+	var legacy []models.SettingVariable
+	if err := s.db.WithContext(ctx).Where("key IN ?", []string{legacyScheduledPruneEnabledKey, legacyScheduledPruneIntervalKey}).Find(&legacy).Error; err != nil {
+		return errors.WrapIf(err, "failed to load legacy scheduled prune settings")
+	}
+
+	values := make(map[string]string, len(legacy)+2)
+	for _, setting := range legacy {
+		values[setting.Key] = strings.TrimSpace(setting.Value)
+	}
+	fromEnv := false
+	for key, env := range map[string]string{
+		legacyScheduledPruneEnabledKey:  legacyScheduledPruneEnabledEnv,
+		legacyScheduledPruneIntervalKey: legacyScheduledPruneIntervalEnv,
+	} {
+		if value, ok := os.LookupEnv(env); ok {
+			values[key] = strings.TrimSpace(value)
+			fromEnv = true
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if fromEnv {
+		slog.WarnContext(ctx, "SCHEDULED_PRUNE_ENABLED and SCHEDULED_PRUNE_INTERVAL are deprecated: scheduled prunes are now prune schedules. The legacy prune is imported once; remove these variables and manage the schedule instead")
+	}
+
+	var schedule *models.PruneSchedule
+	if enabled, _ := strconv.ParseBool(values[legacyScheduledPruneEnabledKey]); enabled {
+		expression := values[legacyScheduledPruneIntervalKey]
+		if _, err := pruneScheduleParser.Parse(expression); err != nil {
+			expression = legacyScheduledPruneDefaultInterval
+		}
+		schedule = &models.PruneSchedule{
+			EnvironmentID: types.LOCAL_DOCKER_ENVIRONMENT_ID,
+			Name:          legacyScheduledPruneName,
+			Schedule:      expression,
+			Enabled:       true,
+			Policy:        models.PrunePolicy(s.legacyPrunePolicyInternal(ctx)),
+		}
+		if err := validatePruneScheduleInternal(schedule); err != nil {
+			slog.WarnContext(ctx, "Legacy scheduled prune not imported", "error", err)
+			schedule = nil
+		}
+	}
+	if schedule != nil && fromEnv {
+		var existing int64
+		if err := s.db.WithContext(ctx).Model(&models.PruneSchedule{}).
+			Where("environment_id = ? AND name = ?", types.LOCAL_DOCKER_ENVIRONMENT_ID, legacyScheduledPruneName).
+			Count(&existing).Error; err != nil {
+			return errors.WrapIf(err, "failed to check for an imported legacy scheduled prune")
+		}
+		if existing > 0 {
+			schedule = nil
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if schedule != nil {
+			if err := tx.Create(schedule).Error; err != nil {
+				return errors.WrapIf(err, "failed to import legacy scheduled prune")
+			}
+		}
+		if err := tx.Where("key IN ?", []string{legacyScheduledPruneEnabledKey, legacyScheduledPruneIntervalKey}).Delete(&models.SettingVariable{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete legacy scheduled prune settings")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if schedule != nil {
+		slog.InfoContext(ctx, "Imported legacy scheduled prune as a prune schedule", "scheduleId", schedule.ID, "schedule", schedule.Schedule)
+	}
+	return nil
+}
+
+// legacyPrunePolicyInternal builds the policy the global scheduled prune ran
+// with from the default prune mode settings; a mode of none skips that type.
+func (s *PruneScheduleService) legacyPrunePolicyInternal(ctx context.Context) system.PruneAllRequest {
+	setting := func(key, fallback string) string {
+		return s.settingsService.GetStringSetting(ctx, key, fallback)
+	}
+
+	var policy system.PruneAllRequest
+	if mode := setting("pruneContainerMode", "stopped"); mode != "" && mode != string(system.PruneContainerModeNone) {
+		policy.Containers = &system.PruneContainersOptions{Mode: system.PruneContainerMode(mode), Until: setting("pruneContainerUntil", "")}
+	}
+	if mode := setting("pruneImageMode", "dangling"); mode != "" && mode != string(system.PruneImageModeNone) {
+		policy.Images = &system.PruneImagesOptions{Mode: system.PruneImageMode(mode), Until: setting("pruneImageUntil", "")}
+	}
+	if mode := setting("pruneVolumeMode", "none"); mode != "" && mode != string(system.PruneVolumeModeNone) {
+		policy.Volumes = &system.PruneVolumesOptions{Mode: system.PruneVolumeMode(mode)}
+	}
+	if mode := setting("pruneNetworkMode", "unused"); mode != "" && mode != string(system.PruneNetworkModeNone) {
+		policy.Networks = &system.PruneNetworksOptions{Mode: system.PruneNetworkMode(mode), Until: setting("pruneNetworkUntil", "")}
+	}
+	if mode := setting("pruneBuildCacheMode", "none"); mode != "" && mode != string(system.PruneBuildCacheModeNone) {
+		policy.BuildCache = &system.PruneBuildCacheOptions{Mode: system.PruneBuildCacheMode(mode), Until: setting("pruneBuildCacheUntil", "")}
+	}
+	return policy
+}
+
+// ListSchedules returns every prune schedule in environmentID, ordered by name.
+func (s *PruneScheduleService) ListSchedules(ctx context.Context, environmentID string) ([]system.PruneSchedule, error) {
+	var schedules []models.PruneSchedule
+	if err := s.db.WithContext(ctx).Where("environment_id = ?", environmentID).Order("name ASC").Find(&schedules).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to list prune schedules")
+	}
+
+	result := make([]system.PruneSchedule, 0, len(schedules))
+	for i := range schedules {
+		result = append(result, s.toDTOInternal(&schedules[i]))
+	}
+	return result, nil
+}
+
+func (s *PruneScheduleService) GetSchedule(ctx context.Context, environmentID, scheduleID string) (*system.PruneSchedule, error) {
+	schedule, err := s.getScheduleRecordInternal(ctx, environmentID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	dto := s.toDTOInternal(schedule)
+	return &dto, nil
+}
+
+// CreateSchedule stores a new prune schedule and, unless it is created
+// disabled, installs it in the scheduler.
+func (s *PruneScheduleService) CreateSchedule(ctx context.Context, environmentID string, req system.CreatePruneScheduleRequest) (*system.PruneSchedule, error) {
+	schedule := &models.PruneSchedule{
+		EnvironmentID: environmentID,
+		Name:          strings.TrimSpace(req.Name),
+		Schedule:      strings.TrimSpace(req.Schedule),
+		Enabled:       req.Enabled == nil || *req.Enabled,
+		Policy:        models.PrunePolicy(req.Policy),
+	}
+	if err := validatePruneScheduleInternal(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(schedule).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to create prune schedule")
+	}
+	s.syncScheduleJobInternal(ctx, schedule)
+
+	dto := s.toDTOInternal(schedule)
+	return &dto, nil
+}
+
+// UpdateSchedule applies the fields set in req and reinstalls the schedule's job.
+func (s *PruneScheduleService) UpdateSchedule(ctx context.Context, environmentID, scheduleID string, req system.UpdatePruneScheduleRequest) (*system.PruneSchedule, error) {
+	schedule, err := s.getScheduleRecordInternal(ctx, environmentID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		schedule.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Schedule != nil {
+		schedule.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	if req.Policy != nil {
+		schedule.Policy = models.PrunePolicy(*req.Policy)
+	}
+	if err := validatePruneScheduleInternal(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Model(schedule).Select("name", "schedule", "enabled", "policy", "updated_at").Updates(schedule).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to update prune schedule")
+	}
+	s.syncScheduleJobInternal(ctx, schedule)
+
+	dto := s.toDTOInternal(schedule)
+	return &dto, nil
+}
+
+// DeleteSchedule removes the schedule, its job and its run history.
+func (s *PruneScheduleService) DeleteSchedule(ctx context.Context, environmentID, scheduleID string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("environment_id = ? AND id = ?", environmentID, scheduleID).Delete(&models.PruneSchedule{})
+		if result.Error != nil {
+			return errors.WrapIf(result.Error, "failed to delete prune schedule")
+		}
+		if result.RowsAffected == 0 {
+			return common.ErrPruneScheduleNotFound
+		}
+		return tx.Where("schedule_id = ?", scheduleID).Delete(&models.PruneScheduleRun{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.unregisterScheduleJobInternal(ctx, scheduleID)
+	return nil
+}
+
+// RunScheduleNow runs the schedule's policy immediately, recording the run as
+// a scheduled fire would. Disabled schedules can be run this way too.
+func (s *PruneScheduleService) RunScheduleNow(ctx context.Context, environmentID, scheduleID string) (*system.PruneScheduleRun, error) {
+	schedule, err := s.getScheduleRecordInternal(ctx, environmentID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.runScheduleInternal(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	dto := run.ToDTO()
+	return &dto, nil
+}
+
+// ListScheduleRuns returns the schedule's recorded runs, newest first. limit
+// caps the number returned; zero returns every retained run.
+func (s *PruneScheduleService) ListScheduleRuns(ctx context.Context, environmentID, scheduleID string, limit int) ([]system.PruneScheduleRun, error) {
+	if _, err := s.getScheduleRecordInternal(ctx, environmentID, scheduleID); err != nil {
+		return nil, err
+	}
+
+	q := s.db.WithContext(ctx).Where("schedule_id = ?", scheduleID).Order("started_at DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var runs []models.PruneScheduleRun
+	if err := q.Find(&runs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to list prune schedule runs")
+	}
+
+	result := make([]system.PruneScheduleRun, 0, len(runs))
+	for i := range runs {
+		result = append(result, runs[i].ToDTO())
+	}
+	return result, nil
+}
+
+func (s *PruneScheduleService) getScheduleRecordInternal(ctx context.Context, environmentID, scheduleID string) (*models.PruneSchedule, error) {
+	var schedule models.PruneSchedule
+	err := s.db.WithContext(ctx).Where("environment_id = ? AND id = ?", environmentID, scheduleID).First(&schedule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, common.ErrPruneScheduleNotFound
+	}
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to load prune schedule")
+	}
+	return &schedule, nil
+}
+
+// validatePruneScheduleInternal rejects a schedule without a name, with an
+// unparsable cron expression, or whose policy selects nothing to prune or
+// fails ValidatePruneRequest.
+func validatePruneScheduleInternal(schedule *models.PruneSchedule) error {
+	if schedule.Name == "" {
+		return common.Classify(common.ErrValidation, errors.WithDetails(errors.New("Prune schedule name is required."), "field", "name"))
+	}
+	if _, err := pruneScheduleParser.Parse(schedule.Schedule); err != nil {
+		return common.Classify(common.ErrValidation, errors.WithDetails(errors.Errorf("Invalid cron expression %q: %v", schedule.Schedule, err), "field", "schedule"))
+	}
+
+	policy := system.PruneAllRequest(schedule.Policy)
+	if !hasScheduledPruneTargetsInternal(policy) {
+		return common.Classify(common.ErrValidation, errors.WithDetails(errors.New("Prune schedule policy must select at least one resource type."), "field", "policy"))
+	}
+	if err := ValidatePruneRequest(policy); err != nil {
+		return common.Classify(common.ErrValidation, errors.WithDetails(err, "field", "policy"))
+	}
+	return nil
+}
+
+// hasScheduledPruneTargetsInternal reports whether req selects at least one
+// resource type with a mode other than none.
+func hasScheduledPruneTargetsInternal(req system.PruneAllRequest) bool {
+	return (req.Containers != nil && req.Containers.Mode != system.PruneContainerModeNone) ||
+		(req.Images != nil && req.Images.Mode != system.PruneImageModeNone) ||
+		(req.Volumes != nil && req.Volumes.Mode != system.PruneVolumeModeNone) ||
+		(req.Networks != nil && req.Networks.Mode != system.PruneNetworkModeNone) ||
+		(req.BuildCache != nil && req.BuildCache.Mode != system.PruneBuildCacheModeNone)
+}
+
+// toDTOInternal converts schedule, computing its next fire time from the cron
+// expression in the scheduler's timezone while it is enabled.
+func (s *PruneScheduleService) toDTOInternal(schedule *models.PruneSchedule) system.PruneSchedule {
+	var nextRunAt *time.Time
+	if schedule.Enabled {
+		if sched, err := pruneScheduleParser.Parse(schedule.Schedule); err == nil {
+			location := s.location
+			if location == nil {
+				location = time.UTC
+			}
+			nextRunAt = new(sched.Next(time.Now().In(location)))
+		}
+	}
+	return schedule.ToDTO(nextRunAt)
+}
+
+// buildScheduleJobInternal returns the dynamic job for a single schedule. The
+// run body re-reads the schedule each fire, so an edit is picked up and a row
+// deleted out-of-band unregisters its job instead of firing forever.
+func (s *PruneScheduleService) buildScheduleJobInternal(scheduleID, environmentID, expression string) *schedulertypes.GenericJob {
+	return &schedulertypes.GenericJob{
+		JobName: pruneScheduleJobNameInternal(scheduleID),
+		ScheduleFn: func(_ context.Context) string {
+			return expression
+		},
+		RunFn: func(ctx context.Context) {
+			s.runScheduledFireInternal(ctx, environmentID, scheduleID)
+		},
+	}
+}
+
+func (s *PruneScheduleService) runScheduledFireInternal(ctx context.Context, environmentID, scheduleID string) {
+	schedule, err := s.getScheduleRecordInternal(ctx, environmentID, scheduleID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			slog.InfoContext(ctx, "prune schedule job unregistering; schedule no longer exists", "scheduleId", scheduleID)
+			s.unregisterScheduleJobInternal(ctx, scheduleID)
+			return
+		}
+		slog.WarnContext(ctx, "scheduled prune skipped; failed to load schedule", "scheduleId", scheduleID, "error", err)
+		return
+	}
+	if !schedule.Enabled {
+		return
+	}
+
+	if err := s.settingsService.CheckMaintenance(ctx, false); err != nil {
+		slog.InfoContext(ctx, "scheduled prune skipped; environment is in maintenance mode", "scheduleId", scheduleID, "reason", err)
+		run := &models.PruneScheduleRun{
+			ScheduleID: schedule.ID,
+			StartedAt:  time.Now(),
+			Status:     string(system.PruneScheduleRunStatusSkipped),
+			Errors:     models.PruneScheduleRunErrors{err.Error()},
+		}
+		run.FinishedAt = new(run.StartedAt)
+		if err := s.recordRunInternal(ctx, schedule, run); err != nil {
+			slog.ErrorContext(ctx, "failed to record skipped prune schedule run", "scheduleId", scheduleID, "error", err)
+		}
+		return
+	}
+
+	if _, err := s.runScheduleInternal(ctx, schedule); err != nil {
+		slog.ErrorContext(ctx, "scheduled prune run failed", "scheduleId", scheduleID, "error", err)
+	}
+}
+
+// runScheduleInternal prunes with the schedule's policy, records the run and
+// its reclaimed space, and sends the prune report notification. A run that
+// finds another prune in progress is recorded as skipped.
+func (s *PruneScheduleService) runScheduleInternal(ctx context.Context, schedule *models.PruneSchedule) (*models.PruneScheduleRun, error) {
+	slog.InfoContext(ctx, "scheduled prune run started", "scheduleId", schedule.ID, "name", schedule.Name, "environmentId", schedule.EnvironmentID)
+
+	run := &models.PruneScheduleRun{
+		ScheduleID: schedule.ID,
+		StartedAt:  time.Now(),
+	}
+	result, started, pruneErr := s.systemService.PruneAll(ctx, schedule.EnvironmentID, system.PruneAllRequest(schedule.Policy))
+	run.FinishedAt = new(time.Now())
+	applyPruneResultToRunInternal(run, result, started, pruneErr)
+
+	if err := s.recordRunInternal(ctx, schedule, run); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "scheduled prune run completed",
+		"scheduleId", schedule.ID,
+		"status", run.Status,
+		"space_reclaimed_bytes", run.SpaceReclaimed,
+		"containers_pruned", run.ContainersPruned,
+		"images_deleted", run.ImagesDeleted,
+		"volumes_deleted", run.VolumesDeleted,
+		"networks_deleted", run.NetworksDeleted,
+		"errors", len(run.Errors),
+	)
+
+	if started && pruneErr == nil && s.notificationService != nil {
+		if err := s.notificationService.SendPruneReportNotification(ctx, result); err != nil {
+			slog.WarnContext(ctx, "failed to send prune report notification", "scheduleId", schedule.ID, "error", err)
+		}
+	}
+	return run, nil
+}
+
+// applyPruneResultToRunInternal fills run's status and counters from the
+// outcome of SystemService.PruneAll.
+func applyPruneResultToRunInternal(run *models.PruneScheduleRun, result *system.PruneAllResult, started bool, pruneErr error) {
+	switch {
+	case pruneErr != nil:
+		run.Status = string(system.PruneScheduleRunStatusFailed)
+		run.Errors = models.PruneScheduleRunErrors{pruneErr.Error()}
+		return
+	case !started:
+		run.Status = string(system.PruneScheduleRunStatusSkipped)
+		run.Errors = models.PruneScheduleRunErrors{"another prune was already running in this environment"}
+		if result != nil {
+			run.ActivityID = result.ActivityID
+		}
+		return
+	}
+
+	run.ActivityID = result.ActivityID
+	run.SpaceReclaimed = int64(min(result.SpaceReclaimed, uint64(1<<63-1)))
+	run.ContainersPruned = len(result.ContainersPruned)
+	run.ImagesDeleted = len(result.ImagesDeleted)
+	run.VolumesDeleted = len(result.VolumesDeleted)
+	run.NetworksDeleted = len(result.NetworksDeleted)
+	run.Errors = models.PruneScheduleRunErrors(result.Errors)
+	run.Status = string(system.PruneScheduleRunStatusSuccess)
+	if len(result.Errors) > 0 {
+		run.Status = string(system.PruneScheduleRunStatusPartial)
+	}
+}
+
+// recordRunInternal stores run, updates the schedule's last-run summary and
+// drops runs beyond pruneScheduleRunHistoryLimit.
+func (s *PruneScheduleService) recordRunInternal(ctx context.Context, schedule *models.PruneSchedule, run *models.PruneScheduleRun) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return errors.WrapIf(err, "failed to record prune schedule run")
+		}
+
+		if err := tx.Model(&models.PruneSchedule{}).Where("id = ?", schedule.ID).Updates(map[string]any{
+			"last_run_at":           run.StartedAt,
+			"last_run_status":       run.Status,
+			"last_space_reclaimed":  run.SpaceReclaimed,
+			"total_space_reclaimed": gorm.Expr("total_space_reclaimed + ?", run.SpaceReclaimed),
+		}).Error; err != nil {
+			return errors.WrapIf(err, "failed to update prune schedule")
+		}
+
+		var staleIDs []string
+		if err := tx.Model(&models.PruneScheduleRun{}).
+			Where("schedule_id = ?", schedule.ID).
+			Order("started_at DESC").
+			Offset(pruneScheduleRunHistoryLimit).
+			Pluck("id", &staleIDs).Error; err != nil {
+			return errors.WrapIf(err, "failed to trim prune schedule history")
+		}
+		if len(staleIDs) > 0 {
+			if err := tx.Where("id IN ?", staleIDs).Delete(&models.PruneScheduleRun{}).Error; err != nil {
+				return errors.WrapIf(err, "failed to trim prune schedule history")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	schedule.LastRunAt = &run.StartedAt
+	schedule.LastRunStatus = &run.Status
+	schedule.LastSpaceReclaimed = run.SpaceReclaimed
+	schedule.TotalSpaceReclaimed += run.SpaceReclaimed
+	return nil
+}
+
+// syncScheduleJobInternal installs the schedule's job while it is enabled and
+// removes it otherwise.
+func (s *PruneScheduleService) syncScheduleJobInternal(ctx context.Context, schedule *models.PruneSchedule) {
+	if !schedule.Enabled {
+		s.unregisterScheduleJobInternal(ctx, schedule.ID)
+		return
+	}
+	s.registerScheduleJobInternal(ctx, schedule)
+}
+
+func (s *PruneScheduleService) registerScheduleJobInternal(ctx context.Context, schedule *models.PruneSchedule) {
+	if s.scheduler == nil {
+		return
+	}
+	job := s.buildScheduleJobInternal(schedule.ID, schedule.EnvironmentID, schedule.Schedule)
+	schedulerCtx := s.schedulerCtxInternal(ctx)
+	if err := s.scheduler.AddJob(schedulerCtx, job); err != nil {
+		slog.ErrorContext(schedulerCtx, "Failed to register prune schedule job", "scheduleId", schedule.ID, "error", err)
+	}
+}
+
+func (s *PruneScheduleService) unregisterScheduleJobInternal(ctx context.Context, scheduleID string) {
+	if s.scheduler == nil {
+		return
+	}
+	s.scheduler.RemoveJob(s.schedulerCtxInternal(ctx), pruneScheduleJobNameInternal(scheduleID))
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
+	"github.com/getarcaneapp/arcane/types/v2/system"
+	sqlite "github.com/libtnb/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupPruneScheduleTestService(t *testing.T) (*PruneScheduleService, *database.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.PruneSchedule{}, &models.PruneScheduleRun{}, &models.SettingVariable{}))
+
+	dbWrapper := &database.DB{DB: db}
+	settingsService, err := NewSettingsService(context.Background(), dbWrapper)
+	require.NoError(t, err)
+	return NewPruneScheduleService(dbWrapper, nil, settingsService, nil, config.Load()), dbWrapper
+}
+
+func danglingImagesPolicyInternal() system.PruneAllRequest {
+	return system.PruneAllRequest{Images: &system.PruneImagesOptions{Mode: system.PruneImageModeDangling}}
+}
+
+func TestValidatePruneScheduleInternal(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule models.PruneSchedule
+		wantErr  bool
+	}{
+		{
+			name:     "valid six-field cron",
+			schedule: models.PruneSchedule{Name: "nightly", Schedule: "0 0 3 * * *", Policy: models.PrunePolicy(danglingImagesPolicyInternal())},
+		},
+		{
+			name:     "valid descriptor",
+			schedule: models.PruneSchedule{Name: "weekly", Schedule: "@weekly", Policy: models.PrunePolicy(danglingImagesPolicyInternal())},
+		},
+		{
+			name:     "missing name",
+			schedule: models.PruneSchedule{Schedule: "@daily", Policy: models.PrunePolicy(danglingImagesPolicyInternal())},
+			wantErr:  true,
+		},
+		{
+			name:     "five-field cron is rejected",
+			schedule: models.PruneSchedule{Name: "nightly", Schedule: "0 3 * * *", Policy: models.PrunePolicy(danglingImagesPolicyInternal())},
+			wantErr:  true,
+		},
+		{
+			name:     "empty policy",
+			schedule: models.PruneSchedule{Name: "nothing", Schedule: "@daily"},
+			wantErr:  true,
+		},
+		{
+			name: "only none modes",
+			schedule: models.PruneSchedule{Name: "nothing", Schedule: "@daily", Policy: models.PrunePolicy{
+				Containers: &system.PruneContainersOptions{Mode: system.PruneContainerModeNone},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "until filter with volumes",
+			schedule: models.PruneSchedule{Name: "old", Schedule: "@daily", Policy: models.PrunePolicy{
+				Until:   "168h",
+				Volumes: &system.PruneVolumesOptions{Mode: system.PruneVolumeModeAll},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePruneScheduleInternal(&tt.schedule)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorIs(t, err, common.ErrValidation)
+		})
+	}
+}
+
+func TestPruneScheduleService_CreateAndUpdate_SyncsSchedulerJob(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupPruneScheduleTestService(t)
+	scheduler := &gitOpsSyncTestSchedulerInternal{}
+	svc.SetScheduler(ctx, scheduler)
+
+	created, err := svc.CreateSchedule(ctx, "0", system.CreatePruneScheduleRequest{
+		Name:     "  nightly images  ",
+		Schedule: "@daily",
+		Policy:   danglingImagesPolicyInternal(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "nightly images", created.Name)
+	assert.True(t, created.Enabled)
+	require.NotNil(t, created.NextRunAt)
+	assert.True(t, created.NextRunAt.After(time.Now()))
+	assert.Equal(t, []string{pruneScheduleJobNameInternal(created.ID)}, scheduler.added)
+
+	updated, err := svc.UpdateSchedule(ctx, "0", created.ID, system.UpdatePruneScheduleRequest{Enabled: new(false)})
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Nil(t, updated.NextRunAt)
+	assert.Equal(t, []string{pruneScheduleJobNameInternal(created.ID)}, scheduler.removed)
+
+	_, err = svc.GetSchedule(ctx, "other-env", created.ID)
+	assert.ErrorIs(t, err, common.ErrPruneScheduleNotFound)
+}
+
+func TestPruneScheduleService_CreateDisabled_DoesNotRegisterJob(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupPruneScheduleTestService(t)
+	scheduler := &gitOpsSyncTestSchedulerInternal{}
+	svc.SetScheduler(ctx, scheduler)
+
+	_, err := svc.CreateSchedule(ctx, "0", system.CreatePruneScheduleRequest{
+		Name:     "weekly",
+		Schedule: "@weekly",
+		Enabled:  new(false),
+		Policy:   danglingImagesPolicyInternal(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, scheduler.added)
+
+	svc.RegisterSchedulesOnStartup(ctx)
+	assert.Empty(t, scheduler.added)
+}
+
+func TestPruneScheduleService_DeleteSchedule_RemovesRunsAndJob(t *testing.T) {
+	ctx := context.Background()
+	svc, db := setupPruneScheduleTestService(t)
+	scheduler := &gitOpsSyncTestSchedulerInternal{}
+	svc.SetScheduler(ctx, scheduler)
+
+	created, err := svc.CreateSchedule(ctx, "0", system.CreatePruneScheduleRequest{
+		Name:     "nightly",
+		Schedule: "@daily",
+		Policy:   danglingImagesPolicyInternal(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.PruneScheduleRun{ScheduleID: created.ID, Status: "success", StartedAt: time.Now()}).Error)
+
+	require.NoError(t, svc.DeleteSchedule(ctx, "0", created.ID))
+	assert.Contains(t, scheduler.removed, pruneScheduleJobNameInternal(created.ID))
+
+	var count int64
+	require.NoError(t, db.Model(&models.PruneScheduleRun{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	assert.ErrorIs(t, svc.DeleteSchedule(ctx, "0", created.ID), common.ErrPruneScheduleNotFound)
+}
+
+func TestPruneScheduleService_RunScheduledFire_UnregistersMissingSchedule(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupPruneScheduleTestService(t)
+	scheduler := &gitOpsSyncTestSchedulerInternal{}
+	svc.SetScheduler(ctx, scheduler)
+
+	svc.runScheduledFireInternal(ctx, "0", "ghost-schedule")
+
+	assert.Contains(t, scheduler.removed, pruneScheduleJobNameInternal("ghost-schedule"))
+}
+
+func TestApplyPruneResultToRunInternal(t *testing.T) {
+	result := &system.PruneAllResult{
+		ContainersPruned: []string{"a", "b"},
+		ImagesDeleted:    []string{"sha256:1"},
+		SpaceReclaimed:   2048,
+	}
+
+	run := &models.PruneScheduleRun{}
+	applyPruneResultToRunInternal(run, result, true, nil)
+	assert.Equal(t, string(system.PruneScheduleRunStatusSuccess), run.Status)
+	assert.Equal(t, int64(2048), run.SpaceReclaimed)
+	assert.Equal(t, 2, run.ContainersPruned)
+	assert.Equal(t, 1, run.ImagesDeleted)
+
+	result.Errors = []string{"volume in use"}
+	run = &models.PruneScheduleRun{}
+	applyPruneResultToRunInternal(run, result, true, nil)
+	assert.Equal(t, string(system.PruneScheduleRunStatusPartial), run.Status)
+
+	run = &models.PruneScheduleRun{}
+	applyPruneResultToRunInternal(run, nil, true, errors.New("docker unavailable"))
+	assert.Equal(t, string(system.PruneScheduleRunStatusFailed), run.Status)
+	assert.Equal(t, models.PruneScheduleRunErrors{"docker unavailable"}, run.Errors)
+
+	run = &models.PruneScheduleRun{}
+	applyPruneResultToRunInternal(run, &system.PruneAllResult{}, false, nil)
+	assert.Equal(t, string(system.PruneScheduleRunStatusSkipped), run.Status)
+}
+
+func TestPruneScheduleService_RecordRun_UpdatesTotalsAndTrimsHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, db := setupPruneScheduleTestService(t)
+
+	created, err := svc.CreateSchedule(ctx, "0", system.CreatePruneScheduleRequest{
+		Name:     "nightly",
+		Schedule: "@daily",
+		Policy:   danglingImagesPolicyInternal(),
+	})
+	require.NoError(t, err)
+
+	var schedule models.PruneSchedule
+	require.NoError(t, db.First(&schedule, "id = ?", created.ID).Error)
+
+	start := time.Now().Add(-time.Hour)
+	for i := range pruneScheduleRunHistoryLimit + 5 {
+		run := &models.PruneScheduleRun{
+			ScheduleID:     schedule.ID,
+			Status:         string(system.PruneScheduleRunStatusSuccess),
+			StartedAt:      start.Add(time.Duration(i) * time.Second),
+			SpaceReclaimed: 10,
+		}
+		require.NoError(t, svc.recordRunInternal(ctx, &schedule, run))
+	}
+
+	got, err := svc.GetSchedule(ctx, "0", schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), got.LastSpaceReclaimed)
+	assert.Equal(t, uint64(10*(pruneScheduleRunHistoryLimit+5)), got.TotalSpaceReclaimed)
+	assert.Equal(t, system.PruneScheduleRunStatusSuccess, got.LastRunStatus)
+
+	runs, err := svc.ListScheduleRuns(ctx, "0", schedule.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, pruneScheduleRunHistoryLimit)
+	assert.True(t, runs[0].StartedAt.After(runs[len(runs)-1].StartedAt))
+}
+
+func TestPruneScheduleService_ImportLegacyScheduledPrune(t *testing.T) {
+	ctx := context.Background()
+	svc, db := setupPruneScheduleTestService(t)
+
+	for key, value := range map[string]string{
+		legacyScheduledPruneEnabledKey:  "true",
+		legacyScheduledPruneIntervalKey: "0 0 4 * * *",
+	} {
+		require.NoError(t, db.Create(&models.SettingVariable{Key: key, Value: value}).Error)
+	}
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "pruneContainerMode", "olderThan"))
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "pruneContainerUntil", "24h"))
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "pruneImageMode", "all"))
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "pruneNetworkMode", "none"))
+
+	require.NoError(t, svc.ImportLegacyScheduledPrune(ctx))
+
+	schedules, err := svc.ListSchedules(ctx, "0")
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, legacyScheduledPruneName, schedules[0].Name)
+	assert.Equal(t, "0 0 4 * * *", schedules[0].Schedule)
+	assert.True(t, schedules[0].Enabled)
+	assert.Equal(t, &system.PruneContainersOptions{Mode: system.PruneContainerModeOlderThan, Until: "24h"}, schedules[0].Policy.Containers)
+	assert.Equal(t, &system.PruneImagesOptions{Mode: system.PruneImageModeAll}, schedules[0].Policy.Images)
+	assert.Nil(t, schedules[0].Policy.Volumes)
+	assert.Nil(t, schedules[0].Policy.Networks)
+
+	var remaining int64
+	require.NoError(t, db.Model(&models.SettingVariable{}).Where("key IN ?", []string{legacyScheduledPruneEnabledKey, legacyScheduledPruneIntervalKey}).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+
+	// The legacy settings are gone, so a second startup imports nothing.
+	require.NoError(t, svc.ImportLegacyScheduledPrune(ctx))
+	schedules, err = svc.ListSchedules(ctx, "0")
+	require.NoError(t, err)
+	assert.Len(t, schedules, 1)
+}
+
+func TestPruneScheduleService_ImportLegacyScheduledPrune_DisabledOnlyDropsSettings(t *testing.T) {
+	ctx := context.Background()
+	svc, db := setupPruneScheduleTestService(t)
+	require.NoError(t, db.Create(&models.SettingVariable{Key: legacyScheduledPruneEnabledKey, Value: "false"}).Error)
+
+	require.NoError(t, svc.ImportLegacyScheduledPrune(ctx))
+
+	schedules, err := svc.ListSchedules(ctx, "0")
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	var remaining int64
+	require.NoError(t, db.Model(&models.SettingVariable{}).Where("key = ?", legacyScheduledPruneEnabledKey).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
+
+func TestPruneScheduleService_ImportLegacyScheduledPrune_FromEnvironment(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupPruneScheduleTestService(t)
+	t.Setenv(legacyScheduledPruneEnabledEnv, "true")
+	t.Setenv(legacyScheduledPruneIntervalEnv, "0 0 3 * * *")
+
+	require.NoError(t, svc.ImportLegacyScheduledPrune(ctx))
+
+	schedules, err := svc.ListSchedules(ctx, "0")
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, legacyScheduledPruneName, schedules[0].Name)
+	assert.Equal(t, "0 0 3 * * *", schedules[0].Schedule)
+
+	// The variables are still set on the next startup, but the schedule already exists.
+	require.NoError(t, svc.ImportLegacyScheduledPrune(ctx))
+	schedules, err = svc.ListSchedules(ctx, "0")
+	require.NoError(t, err)
+	assert.Len(t, schedules, 1)
+}
+
+func TestPruneScheduleService_ScheduledFireSkipsInMaintenance(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupPruneScheduleTestService(t)
+
+	schedule, err := svc.CreateSchedule(ctx, "0", system.CreatePruneScheduleRequest{
+		Name:     "nightly",
+		Schedule: "@daily",
+		Policy:   danglingImagesPolicyInternal(),
+	})
+	require.NoError(t, err)
+	_, err = svc.settingsService.SetMaintenance(ctx, environment.MaintenanceUpdate{Enabled: true, Reason: "storage migration"})
+	require.NoError(t, err)
+
+	svc.runScheduledFireInternal(ctx, "0", schedule.ID)
+
+	runs, err := svc.ListScheduleRuns(ctx, "0", schedule.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, system.PruneScheduleRunStatusSkipped, runs[0].Status)
+	require.Len(t, runs[0].Errors, 1)
+	assert.Contains(t, runs[0].Errors[0], "storage migration")
+}
@@ -21,12 +21,16 @@ import (
 	"github.com/samber/mo"
 	"gorm.io/gorm"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane"
+	utilsregistry "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/registryauth"
+	systemlib "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/system"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
 	"github.com/getarcaneapp/arcane/types/v2/settings"
 )
 
@@ -35,14 +39,14 @@ type SettingsService struct {
 	config       atomic.Pointer[models.Settings]
 	envOverrides []settingsEnvOverride
 
-	OnImagePollingSettingsChanged      func(ctx context.Context)
-	OnAutoUpdateSettingsChanged        func(ctx context.Context)
-	OnProjectsDirectoryChanged         func(ctx context.Context)
-	OnTemplatesDirectoryChanged        func(ctx context.Context)
-	OnScheduledPruneSettingsChanged    func(ctx context.Context)
-	OnVulnerabilityScanSettingsChanged func(ctx context.Context)
-	OnAutoHealSettingsChanged          func(ctx context.Context)
-	OnTimeoutSettingsChanged           func(ctx context.Context, timeoutSettings []libarcane.SettingUpdate)
+	OnImagePollingSettingsChanged       func(ctx context.Context)
+	OnAutoUpdateSettingsChanged         func(ctx context.Context)
+	OnProjectsDirectoryChanged          func(ctx context.Context)
+	OnTemplatesDirectoryChanged         func(ctx context.Context)
+	OnSwarmStackSourcesDirectoryChanged func(ctx context.Context, oldDir, newDir string)
+	OnVulnerabilityScanSettingsChanged  func(ctx context.Context)
+	OnAutoHealSettingsChanged           func(ctx context.Context)
+	OnTimeoutSettingsChanged            func(ctx context.Context, timeoutSettings []libarcane.SettingUpdate)
 }
 
 type settingsEnvOverride struct {
@@ -113,7 +117,13 @@ func DefaultSettingsConfig() *models.Settings {
 		TemplatesDirectory:              models.SettingVariable{Value: "/app/data/templates"},
 		FollowProjectSymlinks:           models.SettingVariable{Value: "false"},
 		SwarmStackSourcesDirectory:      models.SettingVariable{Value: "/app/data/swarm/sources"},
+		SwarmStackSourceHistoryLimit:    models.SettingVariable{Value: "10"},
 		DiskUsagePath:                   models.SettingVariable{Value: "/app/data/projects"},
+		SystemGpuVendor:                 models.SettingVariable{Value: "auto"},
+		SystemNvidiaSmiPath:             models.SettingVariable{Value: ""},
+		SystemNvidiaSmiExtraFields:      models.SettingVariable{Value: ""},
+		SystemIntelGpuTopPath:           models.SettingVariable{Value: ""},
+		SystemTegrastatsPath:            models.SettingVariable{Value: ""},
 		AutoUpdate:                      models.SettingVariable{Value: "false"},
 		AutoUpdateInterval:              models.SettingVariable{Value: "0 0 0 * * *"},
 		AutoUpdateExcludedContainers:    models.SettingVariable{Value: ""},
@@ -121,14 +131,18 @@ func DefaultSettingsConfig() *models.Settings {
 		PollingInterval:                 models.SettingVariable{Value: "0 0 * * * *"},
 		DockerClientRefreshInterval:     models.SettingVariable{Value: "*/30 * * * * *"},
 		EventCleanupInterval:            models.SettingVariable{Value: "0 0 */6 * * *"},
+		EventRetentionHours:             models.SettingVariable{Value: "36"},
+		NotificationLogRetentionDays:    models.SettingVariable{Value: "7"},
 		ExpiredSessionsCleanupInterval:  models.SettingVariable{Value: "0 0 0 * * *"},
 		ActivityHistoryRetentionDays:    models.SettingVariable{Value: "30"},
 		ActivityHistoryMaxEntries:       models.SettingVariable{Value: "1000"},
 		MaxConcurrentActivities:         models.SettingVariable{Value: "5"},
 		AutoInjectEnv:                   models.SettingVariable{Value: "false"},
 		DefaultDeployPullPolicy:         models.SettingVariable{Value: "missing"},
-		ScheduledPruneEnabled:           models.SettingVariable{Value: "false"},
-		ScheduledPruneInterval:          models.SettingVariable{Value: "0 0 0 * * *"},
+		DefaultContainerCpuLimit:        models.SettingVariable{Value: "0"},
+		DefaultContainerMemoryLimitMb:   models.SettingVariable{Value: "0"},
+		DefaultContainerRestartPolicy:   models.SettingVariable{Value: ""},
+		RegistryMirrors:                 models.SettingVariable{Value: ""},
 		PruneContainerMode:              models.SettingVariable{Value: "stopped"},
 		PruneContainerUntil:             models.SettingVariable{Value: ""},
 		PruneImageMode:                  models.SettingVariable{Value: "dangling"},
@@ -144,6 +158,7 @@ func DefaultSettingsConfig() *models.Settings {
 		AutoHealMaxRestarts:             models.SettingVariable{Value: "5"},
 		AutoHealRestartWindow:           models.SettingVariable{Value: "30"},
 		VolumeBrowserHelperIdleTimeout:  models.SettingVariable{Value: "10"},
+		ImageUpdateNotificationCooldown: models.SettingVariable{Value: "24"},
 		BaseServerURL:                   models.SettingVariable{Value: "http://localhost"},
 		EnableGravatar:                  models.SettingVariable{Value: "true"},
 		AvatarMaxUploadSizeMb:           models.SettingVariable{Value: "2"},
@@ -205,7 +220,9 @@ func DefaultSettingsConfig() *models.Settings {
 		DepotProjectId:         models.SettingVariable{Value: ""},
 		DepotToken:             models.SettingVariable{Value: ""},
 
-		InstanceID: models.SettingVariable{Value: ""},
+		InstanceID:        models.SettingVariable{Value: ""},
+		MaintenanceMode:   models.SettingVariable{Value: "false"},
+		MaintenanceReason: models.SettingVariable{Value: ""},
 	}
 }
 
@@ -406,8 +423,9 @@ func (s *SettingsService) updateSettingValueNoRefreshInternal(ctx context.Contex
 func (s *SettingsService) UpdateSettings(ctx context.Context, updates settings.Update) ([]models.SettingVariable, error) {
 	defaultCfg := s.getDefaultSettings()
 	cfg := s.GetSettingsConfig().Clone()
+	previousSwarmStackSourcesDir := cfg.SwarmStackSourcesDirectory.Value
 
-	valuesToUpdate, changedPolling, changedAutoUpdate, changedScheduledPrune, changedVulnerabilityScan, changedAutoHeal, changedTimeouts, err := s.prepareUpdateValues(updates, cfg, defaultCfg)
+	valuesToUpdate, changedPolling, changedAutoUpdate, changedVulnerabilityScan, changedAutoHeal, changedTimeouts, err := s.prepareUpdateValues(updates, cfg, defaultCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -432,9 +450,6 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates settings.U
 	if changedAutoUpdate && s.OnAutoUpdateSettingsChanged != nil {
 		s.OnAutoUpdateSettingsChanged(ctx)
 	}
-	if changedScheduledPrune && s.OnScheduledPruneSettingsChanged != nil {
-		s.OnScheduledPruneSettingsChanged(ctx)
-	}
 	if changedVulnerabilityScan && s.OnVulnerabilityScanSettingsChanged != nil {
 		s.OnVulnerabilityScanSettingsChanged(ctx)
 	}
@@ -451,6 +466,9 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates settings.U
 	}) && s.OnTemplatesDirectoryChanged != nil {
 		s.OnTemplatesDirectoryChanged(ctx)
 	}
+	if settings.SwarmStackSourcesDirectory.Value != previousSwarmStackSourcesDir && s.OnSwarmStackSourcesDirectoryChanged != nil {
+		s.OnSwarmStackSourcesDirectoryChanged(ctx, previousSwarmStackSourcesDir, settings.SwarmStackSourcesDirectory.Value)
+	}
 	if len(changedTimeouts) > 0 && s.OnTimeoutSettingsChanged != nil {
 		s.OnTimeoutSettingsChanged(ctx, changedTimeouts)
 	}
@@ -458,14 +476,13 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates settings.U
 	return settings.ToSettingVariableSlice(models.SettingVisibilityNonAdmin, false), nil
 }
 
-func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defaultCfg *models.Settings) ([]models.SettingVariable, bool, bool, bool, bool, bool, []libarcane.SettingUpdate, error) {
+func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defaultCfg *models.Settings) ([]models.SettingVariable, bool, bool, bool, bool, []libarcane.SettingUpdate, error) {
 	rt := reflect.TypeFor[settings.Update]()
 	rv := reflect.ValueOf(updates)
 	valuesToUpdate := make([]models.SettingVariable, 0)
 
 	changedPolling := false
 	changedAutoUpdate := false
-	changedScheduledPrune := false
 	changedVulnerabilityScan := false
 	changedAutoHeal := false
 	changedTimeouts := make([]libarcane.SettingUpdate, 0)
@@ -487,7 +504,7 @@ func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defa
 			}
 
 			if err := cfg.UpdateField(key, value, false); err != nil {
-				return nil, false, false, false, false, false, nil, errors.WrapIff(err, "failed to update in-memory config for key '%s'", key)
+				return nil, false, false, false, false, nil, errors.WrapIff(err, "failed to update in-memory config for key '%s'", key)
 			}
 
 			valuesToUpdate = append(valuesToUpdate, models.SettingVariable{Key: key, Value: value})
@@ -499,7 +516,24 @@ func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defa
 		}
 
 		if err := libarcane.ValidateCronSetting(key, value); err != nil {
-			return nil, false, false, false, false, false, nil, errors.WrapIff(err, "invalid cron expression for %s", key)
+			return nil, false, false, false, false, nil, errors.WrapIff(err, "invalid cron expression for %s", key)
+		}
+		if key == "registryMirrors" {
+			if _, err := utilsregistry.ParseMirrors(value); err != nil {
+				return nil, false, false, false, false, nil, errors.WrapIf(err, "invalid registry mirrors")
+			}
+		}
+		switch key {
+		case "systemNvidiaSmiPath", "systemIntelGpuTopPath", "systemTegrastatsPath":
+			if strings.TrimSpace(value) != "" {
+				if err := systemlib.ValidateGPUToolPath(strings.TrimSpace(value)); err != nil {
+					return nil, false, false, false, false, nil, errors.WrapIff(err, "invalid %s", key)
+				}
+			}
+		case "systemNvidiaSmiExtraFields":
+			if _, err := systemlib.ParseNvidiaQueryFields(value); err != nil {
+				return nil, false, false, false, false, nil, errors.WrapIf(err, "invalid nvidia-smi extra query fields")
+			}
 		}
 
 		var valueToSave string
@@ -518,7 +552,7 @@ func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defa
 			continue
 		}
 		if err != nil {
-			return nil, false, false, false, false, false, nil, errors.WrapIff(err, "failed to update in-memory config for key '%s'", key)
+			return nil, false, false, false, false, nil, errors.WrapIff(err, "failed to update in-memory config for key '%s'", key)
 		}
 
 		valuesToUpdate = append(valuesToUpdate, models.SettingVariable{Key: key, Value: valueToSave})
@@ -528,9 +562,6 @@ func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defa
 			changedPolling = true
 		case "autoUpdate", "autoUpdateInterval":
 			changedAutoUpdate = true
-		case "scheduledPruneEnabled",
-			"scheduledPruneInterval":
-			changedScheduledPrune = true
 		case "vulnerabilityScanEnabled", "vulnerabilityScanInterval", "trivyNetwork", "trivySecurityOpts", "trivyPrivileged", "trivyResourceLimitsEnabled", "trivyCpuLimit", "trivyMemoryLimitMb", "trivyConcurrentScanContainers":
 			changedVulnerabilityScan = true
 		case "autoHealEnabled", "autoHealInterval", "autoHealExcludedContainers", "autoHealMaxRestarts", "autoHealRestartWindow":
@@ -542,7 +573,7 @@ func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defa
 		}
 	}
 
-	return valuesToUpdate, changedPolling, changedAutoUpdate, changedScheduledPrune, changedVulnerabilityScan, changedAutoHeal, changedTimeouts, nil
+	return valuesToUpdate, changedPolling, changedAutoUpdate, changedVulnerabilityScan, changedAutoHeal, changedTimeouts, nil
 }
 
 func extractUpdateValue(field reflect.StructField, fieldValue reflect.Value) (string, string, bool) {
@@ -818,6 +849,93 @@ func (s *SettingsService) GetStringSetting(ctx context.Context, key, defaultValu
 	return settingValueInternal(ctx, s, key, func(value string) (string, error) { return value, nil }).OrElse(defaultValue)
 }
 
+// GetMaintenance returns this environment's maintenance-mode state.
+func (s *SettingsService) GetMaintenance(ctx context.Context) environment.Maintenance {
+	maintenance := environment.Maintenance{Enabled: s.GetBoolSetting(ctx, "maintenanceMode", false)}
+	if maintenance.Enabled {
+		maintenance.Reason = s.GetStringSetting(ctx, "maintenanceReason", "")
+	}
+	return maintenance
+}
+
+// SetMaintenance turns maintenance mode on or off. The reason is cleared when disabling.
+func (s *SettingsService) SetMaintenance(ctx context.Context, update environment.MaintenanceUpdate) (environment.Maintenance, error) {
+	reason := ""
+	if update.Enabled {
+		reason = strings.TrimSpace(update.Reason)
+	}
+	if err := s.updateSettingValueNoRefreshInternal(ctx, "maintenanceReason", reason); err != nil {
+		return environment.Maintenance{}, errors.WrapIf(err, "failed to save maintenance reason")
+	}
+	if err := s.UpdateSetting(ctx, "maintenanceMode", strconv.FormatBool(update.Enabled)); err != nil {
+		return environment.Maintenance{}, errors.WrapIf(err, "failed to save maintenance mode")
+	}
+	return s.GetMaintenance(ctx), nil
+}
+
+// CheckMaintenance returns common.ErrEnvironmentMaintenance, with the configured
+// reason appended, when maintenance mode is on and the caller has not been granted
+// an override.
+func (s *SettingsService) CheckMaintenance(ctx context.Context, override bool) error {
+	maintenance := s.GetMaintenance(ctx)
+	if !maintenance.Enabled || override {
+		return nil
+	}
+	if maintenance.Reason != "" {
+		return fmt.Errorf("%w: %s", common.ErrEnvironmentMaintenance, maintenance.Reason)
+	}
+	return common.ErrEnvironmentMaintenance
+}
+
+// GetAgentInfo returns the local agent's pairing state. tokenConfigured reports
+// whether the running agent has a token, which may come from AGENT_TOKEN rather
+// than the database.
+func (s *SettingsService) GetAgentInfo(ctx context.Context, tokenConfigured bool) environment.AgentInfo {
+	info := environment.AgentInfo{
+		Paired:        tokenConfigured,
+		ControllerURL: s.GetStringSetting(ctx, "agentPairedControllerUrl", ""),
+	}
+	if createdAt, ok := settingValueInternal(ctx, s, "agentTokenCreatedAt", parseRFC3339Internal).Get(); ok && tokenConfigured {
+		info.TokenCreatedAt = &createdAt
+		info.TokenAgeSeconds = new(int64(time.Since(createdAt).Seconds()))
+	}
+	if pairedAt, ok := settingValueInternal(ctx, s, "agentPairedAt", parseRFC3339Internal).Get(); ok {
+		info.LastPairedAt = &pairedAt
+	}
+	return info
+}
+
+// RecordAgentTokenIssued stores when the local agent token was generated or rotated.
+func (s *SettingsService) RecordAgentTokenIssued(ctx context.Context, issuedAt time.Time) error {
+	return s.SetStringSetting(ctx, "agentTokenCreatedAt", issuedAt.UTC().Format(time.RFC3339))
+}
+
+// RecordAgentPairing stores the controller that just paired with the local agent.
+func (s *SettingsService) RecordAgentPairing(ctx context.Context, controllerURL string, pairedAt time.Time) error {
+	if err := s.updateSettingValueNoRefreshInternal(ctx, "agentPairedControllerUrl", strings.TrimSpace(controllerURL)); err != nil {
+		return errors.WrapIf(err, "failed to save paired controller URL")
+	}
+	if err := s.UpdateSetting(ctx, "agentPairedAt", pairedAt.UTC().Format(time.RFC3339)); err != nil {
+		return errors.WrapIf(err, "failed to save pairing time")
+	}
+	return nil
+}
+
+// RecordAgentController stores the controller behind an agent-token request
+// when it differs from the recorded one, so agents configured with a static
+// AGENT_TOKEN still report who they are paired with.
+func (s *SettingsService) RecordAgentController(ctx context.Context, controllerURL string) error {
+	controllerURL = strings.TrimSpace(controllerURL)
+	if controllerURL == "" || controllerURL == s.GetStringSetting(ctx, "agentPairedControllerUrl", "") {
+		return nil
+	}
+	return s.RecordAgentPairing(ctx, controllerURL, time.Now())
+}
+
+func parseRFC3339Internal(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
+
 func (s *SettingsService) SetBoolSetting(ctx context.Context, key string, value bool) error {
 	return s.UpdateSetting(ctx, key, strconv.FormatBool(value))
 }
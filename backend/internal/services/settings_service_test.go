@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	sqlite "github.com/libtnb/sqlite"
 	"github.com/stretchr/testify/require"
@@ -320,6 +321,33 @@ func TestSettingsService_GetSetHelpers(t *testing.T) {
 	require.Equal(t, "http://localhost", svc.GetStringSetting(ctx, "baseServerUrl", ""))
 }
 
+func TestSettingsService_AgentInfo(t *testing.T) {
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	svc, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+
+	info := svc.GetAgentInfo(ctx, false)
+	require.False(t, info.Paired)
+	require.Nil(t, info.TokenCreatedAt)
+	require.Nil(t, info.LastPairedAt)
+
+	issuedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	pairedAt := issuedAt.Add(time.Minute)
+	require.NoError(t, svc.RecordAgentTokenIssued(ctx, issuedAt))
+	require.NoError(t, svc.RecordAgentPairing(ctx, " https://arcane.example.com ", pairedAt))
+
+	info = svc.GetAgentInfo(ctx, true)
+	require.True(t, info.Paired)
+	require.NotNil(t, info.TokenCreatedAt)
+	require.True(t, issuedAt.Equal(*info.TokenCreatedAt))
+	require.NotNil(t, info.TokenAgeSeconds)
+	require.GreaterOrEqual(t, *info.TokenAgeSeconds, int64(3600))
+	require.Equal(t, "https://arcane.example.com", info.ControllerURL)
+	require.NotNil(t, info.LastPairedAt)
+	require.True(t, pairedAt.Equal(*info.LastPairedAt))
+}
+
 func TestSettingsService_UpdateSetting(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
@@ -349,43 +377,6 @@ func TestSettingsService_UpdateSetting_RefreshesCachedSnapshot(t *testing.T) {
 	require.Equal(t, "https://arcane.test", settings.BaseServerURL.Value)
 }
 
-func TestSettingsService_UpdateSettings_PruneModesDoNotTriggerScheduledPruneCallback(t *testing.T) {
-	ctx := context.Background()
-	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
-	require.NoError(t, err)
-
-	callbackCalls := 0
-	svc.OnScheduledPruneSettingsChanged = func(context.Context) {
-		callbackCalls++
-	}
-
-	_, err = svc.UpdateSettings(ctx, settings.Update{
-		PruneImageMode:      new("all"),
-		PruneContainerUntil: new("24h"),
-	})
-	require.NoError(t, err)
-	require.Equal(t, 0, callbackCalls)
-}
-
-func TestSettingsService_UpdateSettings_ScheduledPruneScheduleTriggersCallback(t *testing.T) {
-	ctx := context.Background()
-	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
-	require.NoError(t, err)
-
-	callbackCalls := 0
-	svc.OnScheduledPruneSettingsChanged = func(context.Context) {
-		callbackCalls++
-	}
-
-	_, err = svc.UpdateSettings(ctx, settings.Update{
-		ScheduledPruneEnabled: new("true"),
-	})
-	require.NoError(t, err)
-	require.Equal(t, 1, callbackCalls)
-}
-
 func BenchmarkSettingsService_GetSettings(b *testing.B) {
 	ctx := context.Background()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
@@ -839,3 +830,26 @@ func TestSettingsService_NormalizeProjectsDirectory_UpdatesCacheAfterNormalizati
 	require.Equal(t, expectedPath, cfg2.ProjectsDirectory.Value)
 	require.True(t, filepath.IsAbs(cfg2.ProjectsDirectory.Value), "path should be absolute")
 }
+
+func TestSettingsService_RecordAgentControllerOnlyWritesChanges(t *testing.T) {
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	svc, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RecordAgentController(ctx, "  "))
+	require.Empty(t, svc.GetAgentInfo(ctx, true).ControllerURL)
+
+	require.NoError(t, svc.RecordAgentController(ctx, "https://arcane.example.com"))
+	first := svc.GetAgentInfo(ctx, true)
+	require.Equal(t, "https://arcane.example.com", first.ControllerURL)
+	require.NotNil(t, first.LastPairedAt)
+
+	pinned := first.LastPairedAt.Add(-time.Hour)
+	require.NoError(t, svc.RecordAgentPairing(ctx, "https://arcane.example.com", pinned))
+	require.NoError(t, svc.RecordAgentController(ctx, "https://arcane.example.com"))
+	require.True(t, pinned.Equal(*svc.GetAgentInfo(ctx, true).LastPairedAt))
+
+	require.NoError(t, svc.RecordAgentController(ctx, "https://other.example.com"))
+	require.Equal(t, "https://other.example.com", svc.GetAgentInfo(ctx, true).ControllerURL)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	networktypes "github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/swarm"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/samber/hot"
+	"golang.org/x/sync/singleflight"
+)
+
+// swarmMetadataCacheDefaultTTL applies when SWARM_METADATA_CACHE_TTL is not
+// available, e.g. for services built without config.
+const swarmMetadataCacheDefaultTTL = 2 * time.Second
+
+// swarmMetadataCache keeps NodeList and NetworkList results per environment
+// for a few seconds. Every swarm list endpoint resolves node and network names
+// and the dashboard polls several of them at once, so sharing the lookups
+// removes most of the repeated Docker round-trips. Cached slices are shared
+// between callers and must be treated as read-only.
+type swarmMetadataCache struct {
+	nodes    *hot.HotCache[string, []swarm.Node]
+	networks *hot.HotCache[string, []networktypes.Summary]
+	group    singleflight.Group
+
+	mu          sync.Mutex
+	generations map[string]uint64
+}
+
+// newSwarmMetadataCacheInternal returns nil when ttl is not positive, which
+// disables caching.
+func newSwarmMetadataCacheInternal(ttl time.Duration) *swarmMetadataCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &swarmMetadataCache{
+		nodes:       hot.NewHotCache[string, []swarm.Node](hot.LRU, 64).WithTTL(ttl).Build(),
+		networks:    hot.NewHotCache[string, []networktypes.Summary](hot.LRU, 64).WithTTL(ttl).Build(),
+		generations: make(map[string]uint64),
+	}
+}
+
+// swarmMetadataCacheTTLInternal reads SWARM_METADATA_CACHE_TTL in seconds from
+// the Docker service config, where 0 disables the cache.
+func swarmMetadataCacheTTLInternal(dockerService *DockerClientService) time.Duration {
+	if dockerService == nil || dockerService.config == nil {
+		return swarmMetadataCacheDefaultTTL
+	}
+	return time.Duration(dockerService.config.SwarmMetadataCacheTTL) * time.Second
+}
+
+func (c *swarmMetadataCache) generationInternal(environmentID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[environmentID]
+}
+
+// invalidateInternal drops the cached lists of an environment. Bumping the
+// generation also keeps a lookup that was already in flight from storing the
+// pre-mutation result.
+func (c *swarmMetadataCache) invalidateInternal(environmentID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.generations[environmentID]++
+	c.mu.Unlock()
+
+	c.nodes.Delete(environmentID)
+	c.networks.Delete(environmentID)
+}
+
+// loadSwarmMetadataInternal returns the cached list for environmentID or
+// fetches it once for all concurrent callers. The fetch is detached from the
+// first caller's cancellation so it cannot fail the callers sharing it.
+func loadSwarmMetadataInternal[T any](ctx context.Context, c *swarmMetadataCache, store *hot.HotCache[string, []T], kind, environmentID string, fetch func(ctx context.Context) ([]T, error)) ([]T, error) {
+	if cached, ok, _ := store.Get(environmentID); ok {
+		return cached, nil
+	}
+
+	generation := c.generationInternal(environmentID)
+	key := kind + ":" + environmentID + ":" + strconv.FormatUint(generation, 10)
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		items, err := fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if c.generationInternal(environmentID) == generation {
+			store.Set(environmentID, items)
+		}
+		return items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, _ := v.([]T)
+	return items, nil
+}
+
+// listSwarmNodesCachedInternal returns the swarm nodes of environmentID,
+// reusing a recent NodeList result when one is cached.
+func (s *SwarmService) listSwarmNodesCachedInternal(ctx context.Context, dockerClient *dockerclient.Client, environmentID string) ([]swarm.Node, error) {
+	fetch := func(ctx context.Context) ([]swarm.Node, error) {
+		nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list swarm nodes")
+		}
+		return nodesResult.Items, nil
+	}
+	if s.metadataCache == nil {
+		return fetch(ctx)
+	}
+	return loadSwarmMetadataInternal(ctx, s.metadataCache, s.metadataCache.nodes, "nodes", swarmMetadataCacheKeyInternal(environmentID), fetch)
+}
+
+// listNetworksCachedInternal returns the networks of environmentID, reusing a
+// recent NetworkList result when one is cached.
+func (s *SwarmService) listNetworksCachedInternal(ctx context.Context, dockerClient *dockerclient.Client, environmentID string) ([]networktypes.Summary, error) {
+	fetch := func(ctx context.Context) ([]networktypes.Summary, error) {
+		networksResult, err := dockerClient.NetworkList(ctx, dockerclient.NetworkListOptions{})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list networks")
+		}
+		return networksResult.Items, nil
+	}
+	if s.metadataCache == nil {
+		return fetch(ctx)
+	}
+	return loadSwarmMetadataInternal(ctx, s.metadataCache, s.metadataCache.networks, "networks", swarmMetadataCacheKeyInternal(environmentID), fetch)
+}
+
+// invalidateSwarmMetadataInternal drops the cached node and network lists of
+// environmentID after a mutation that changes them.
+func (s *SwarmService) invalidateSwarmMetadataInternal(environmentID string) {
+	s.metadataCache.invalidateInternal(swarmMetadataCacheKeyInternal(environmentID))
+}
+
+// InvalidateSwarmMetadata drops the cached node and network lists of
+// environmentID. Services that change networks outside SwarmService call it
+// so swarm views do not serve a stale list until the cache expires.
+func (s *SwarmService) InvalidateSwarmMetadata(environmentID string) {
+	s.invalidateSwarmMetadataInternal(environmentID)
+}
+
+// swarmMetadataCacheKeyInternal folds the empty environment ID into the local
+// one so both share a cache entry.
+func swarmMetadataCacheKeyInternal(environmentID string) string {
+	if isLocalSwarmEnvironmentInternal(environmentID) {
+		return localEnvironmentID
+	}
+	return environmentID
+}
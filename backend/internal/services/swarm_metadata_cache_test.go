@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
+	networktypes "github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwarmMetadataCacheInternal_ReusesUntilInvalidated(t *testing.T) {
+	ctx := context.Background()
+	cache := newSwarmMetadataCacheInternal(time.Minute)
+	require.NotNil(t, cache)
+
+	calls := 0
+	fetch := func(context.Context) ([]swarm.Node, error) {
+		calls++
+		return []swarm.Node{{ID: "node-1"}}, nil
+	}
+
+	for range 3 {
+		nodes, err := loadSwarmMetadataInternal(ctx, cache, cache.nodes, "nodes", "0", fetch)
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+	}
+	require.Equal(t, 1, calls)
+
+	_, err := loadSwarmMetadataInternal(ctx, cache, cache.nodes, "nodes", "env-2", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls, "environments are cached separately")
+
+	cache.invalidateInternal("0")
+	_, err = loadSwarmMetadataInternal(ctx, cache, cache.nodes, "nodes", "0", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+}
+
+func TestSwarmMetadataCacheInternal_InvalidationDuringFetchIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	cache := newSwarmMetadataCacheInternal(time.Minute)
+
+	calls := 0
+	fetch := func(context.Context) ([]swarm.Node, error) {
+		calls++
+		if calls == 1 {
+			// A mutation lands while the first lookup is still in flight.
+			cache.invalidateInternal("0")
+		}
+		return []swarm.Node{{ID: "node-1"}}, nil
+	}
+
+	_, err := loadSwarmMetadataInternal(ctx, cache, cache.nodes, "nodes", "0", fetch)
+	require.NoError(t, err)
+	_, err = loadSwarmMetadataInternal(ctx, cache, cache.nodes, "nodes", "0", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestSwarmMetadataCacheTTLInternal(t *testing.T) {
+	require.Equal(t, swarmMetadataCacheDefaultTTL, swarmMetadataCacheTTLInternal(nil))
+	require.Equal(t, 3*time.Second, swarmMetadataCacheTTLInternal(&DockerClientService{config: &config.Config{SwarmMetadataCacheTTL: 3}}))
+
+	disabled := swarmMetadataCacheTTLInternal(&DockerClientService{config: &config.Config{}})
+	require.Zero(t, disabled)
+	require.Nil(t, newSwarmMetadataCacheInternal(disabled))
+
+	var nilCache *swarmMetadataCache
+	require.NotPanics(t, func() { nilCache.invalidateInternal("0") })
+}
+
+func TestNetworkServiceMutationsInvalidateSwarmMetadataInternal(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"prune failed"}`, http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	swarmSvc := &SwarmService{metadataCache: newSwarmMetadataCacheInternal(time.Minute)}
+	networkSvc := NewNetworkService(nil, &DockerClientService{client: newTestDockerClient(t, server)}, nil).WithSwarmService(swarmSvc)
+
+	calls := 0
+	fetch := func(context.Context) ([]networktypes.Summary, error) {
+		calls++
+		return []networktypes.Summary{{}}, nil
+	}
+	cache := swarmSvc.metadataCache
+	_, err := loadSwarmMetadataInternal(ctx, cache, cache.networks, "networks", localEnvironmentID, fetch)
+	require.NoError(t, err)
+
+	// Even a failed prune may have removed some networks.
+	_, err = networkSvc.PruneNetworks(ctx)
+	require.Error(t, err)
+
+	_, err = loadSwarmMetadataInternal(ctx, cache, cache.networks, "networks", localEnvironmentID, fetch)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
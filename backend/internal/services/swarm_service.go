@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	stdjson "encoding/json"
 	json "encoding/json/v2"
@@ -9,11 +10,15 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -21,6 +26,7 @@ import (
 
 	"emperror.dev/errors"
 	cerrdefs "github.com/containerd/errdefs"
+	"github.com/distribution/reference"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	dockerutil "github.com/getarcaneapp/arcane/backend/v2/pkg/dockerutil"
@@ -29,11 +35,14 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	appfs "github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
 	swarmtypes "github.com/getarcaneapp/arcane/types/v2/swarm"
+	"github.com/moby/moby/api/types/events"
 	networktypes "github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/api/types/system"
 	dockerclient "github.com/moby/moby/client"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/samber/hot"
+	"go.getarcane.app/streams/bus"
 	"go.getarcane.app/sys/atomic"
 	"golang.org/x/sync/errgroup"
 )
@@ -43,6 +52,14 @@ const (
 	swarmNodeIdentityCacheTTL         = 30 * time.Second
 	KVKeySwarmEnabled                 = "swarm.enabled"
 	defaultSwarmListenAddr            = "0.0.0.0:2377"
+	secretRotationRemoveTimeout       = 30 * time.Second
+	// nodeLabelPatchAttempts bounds how often PatchNodeLabels re-inspects a node
+	// that keeps changing underneath it.
+	nodeLabelPatchAttempts = 5
+	// swarmMinNanoCPUs and swarmMinMemoryBytes are the smallest non-zero task
+	// resource values swarmkit accepts.
+	swarmMinNanoCPUs    = 1_000_000
+	swarmMinMemoryBytes = 4 * 1024 * 1024
 )
 
 // SwarmService provides Docker Swarm related operations.
@@ -53,6 +70,7 @@ type SwarmService struct {
 	registryService    *ContainerRegistryService
 	environmentService *EnvironmentService
 	identityCache      *hot.HotCache[string, SwarmNodeIdentity]
+	metadataCache      *swarmMetadataCache
 }
 
 func NewSwarmService(
@@ -72,6 +90,7 @@ func NewSwarmService(
 			WithTTL(swarmNodeIdentityCacheTTL).
 			WithJanitor().
 			Build(),
+		metadataCache: newSwarmMetadataCacheInternal(swarmMetadataCacheTTLInternal(dockerService)),
 	}
 }
 
@@ -110,12 +129,12 @@ func (s *SwarmService) IsEnabled(ctx context.Context) (bool, error) {
 	return enabled, nil
 }
 
-func (s *SwarmService) ListServicesPaginated(ctx context.Context, params pagination.QueryParams) ([]swarmtypes.ServiceSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) ListServicesPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]swarmtypes.ServiceSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -127,22 +146,20 @@ func (s *SwarmService) ListServicesPaginated(ctx context.Context, params paginat
 	services := servicesResult.Items
 
 	// Fetch nodes to resolve node IDs to hostnames
-	nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, environmentID)
 	if err != nil {
-		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list swarm nodes")
+		return nil, pagination.Response{}, err
 	}
-	nodes := nodesResult.Items
 	nodeNameByID := make(map[string]string, len(nodes))
 	for _, node := range nodes {
 		nodeNameByID[node.ID] = node.Description.Hostname
 	}
 
 	// Fetch networks to resolve network IDs to names
-	networksResult, err := dockerClient.NetworkList(ctx, dockerclient.NetworkListOptions{})
+	networks, err := s.listNetworksCachedInternal(ctx, dockerClient, environmentID)
 	if err != nil {
-		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list networks")
+		return nil, pagination.Response{}, err
 	}
-	networks := networksResult.Items
 	networkNameByID := make(map[string]string, len(networks))
 	for _, n := range networks {
 		networkNameByID[n.ID] = n.Name
@@ -180,19 +197,19 @@ func (s *SwarmService) ListServicesPaginated(ctx context.Context, params paginat
 		items = append(items, swarmtypes.NewServiceSummary(service, nodeNames, networkNameByID))
 	}
 
-	config := s.buildServicePaginationConfigInternal()
+	config := s.ServicePaginationConfig()
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 	paginationResp := buildPaginationResponseInternal(result, params)
 
 	return result.Items, paginationResp, nil
 }
 
-func (s *SwarmService) GetService(ctx context.Context, serviceID string) (*swarmtypes.ServiceInspect, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) GetService(ctx context.Context, environmentID, serviceID string) (*swarmtypes.ServiceInspect, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -211,14 +228,85 @@ func (s *SwarmService) GetService(ctx context.Context, serviceID string) (*swarm
 	return &inspect, nil
 }
 
+// GetServiceUpdateStatus reports the rollout state of a service together with its running and
+// desired replica counts so callers can poll for convergence after an update or scale.
+func (s *SwarmService) GetServiceUpdateStatus(ctx context.Context, environmentID, serviceID string) (*swarmtypes.ServiceUpdateStatus, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	serviceResult, err := dockerClient.ServiceInspect(ctx, serviceID, dockerclient.ServiceInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect swarm service")
+	}
+
+	taskFilters := make(dockerclient.Filters).Add("service", serviceResult.Service.ID)
+	tasksResult, err := dockerClient.TaskList(ctx, dockerclient.TaskListOptions{Filters: taskFilters})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list swarm tasks")
+	}
+
+	status := buildSwarmServiceUpdateStatusInternal(serviceResult.Service, tasksResult.Items)
+	return &status, nil
+}
+
+func buildSwarmServiceUpdateStatusInternal(service swarm.Service, tasks []swarm.Task) swarmtypes.ServiceUpdateStatus {
+	status := swarmtypes.ServiceUpdateStatus{ServiceID: service.ID}
+
+	desiredFromTasks := uint64(0)
+	for _, task := range tasks {
+		if task.DesiredState != swarm.TaskStateRunning {
+			// During a rolling update the replaced task keeps running until it
+			// stops, but it no longer counts towards the service's replicas.
+			continue
+		}
+		desiredFromTasks++
+		if task.Status.State == swarm.TaskStateRunning {
+			status.RunningReplicas++
+		}
+	}
+
+	if replicated := service.Spec.Mode.Replicated; replicated != nil && replicated.Replicas != nil {
+		status.DesiredReplicas = *replicated.Replicas
+	} else {
+		// Global services have no replica count in the spec; the orchestrator's desired
+		// state on each task is the only source of truth for how many should be running.
+		status.DesiredReplicas = desiredFromTasks
+	}
+
+	inProgress := false
+	if update := service.UpdateStatus; update != nil {
+		status.State = string(update.State)
+		status.StartedAt = update.StartedAt
+		status.CompletedAt = update.CompletedAt
+		status.Message = update.Message
+
+		switch update.State {
+		case swarm.UpdateStatePaused, swarm.UpdateStateRollbackPaused:
+			status.Paused = true
+			inProgress = true
+		case swarm.UpdateStateUpdating, swarm.UpdateStateRollbackStarted:
+			inProgress = true
+		}
+	}
+
+	status.Converged = !inProgress && status.RunningReplicas == status.DesiredReplicas
+	return status
+}
+
 func (s *SwarmService) resolveServiceNodeNamesInternal(ctx context.Context, dockerClient *dockerclient.Client, serviceID string) []string {
-	nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, localEnvironmentID)
 	if err != nil {
 		return nil
 	}
 
-	nodeNameByID := make(map[string]string, len(nodesResult.Items))
-	for _, node := range nodesResult.Items {
+	nodeNameByID := make(map[string]string, len(nodes))
+	for _, node := range nodes {
 		nodeNameByID[node.ID] = node.Description.Hostname
 	}
 
@@ -346,12 +434,14 @@ func (s *SwarmService) enrichServiceMountsInternal(
 	return mounts
 }
 
-func (s *SwarmService) CreateService(ctx context.Context, req swarmtypes.ServiceCreateRequest) (*swarmtypes.ServiceCreateResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) CreateService(ctx context.Context, environmentID string, req swarmtypes.ServiceCreateRequest) (*swarmtypes.ServiceCreateResponse, error) {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -385,12 +475,66 @@ func (s *SwarmService) CreateService(ctx context.Context, req swarmtypes.Service
 	}, nil
 }
 
-func (s *SwarmService) UpdateService(ctx context.Context, serviceID string, req swarmtypes.ServiceUpdateRequest) (*swarmtypes.ServiceUpdateResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+// CreateServiceFromCompose creates a standalone swarm service from a compose
+// fragment declaring exactly one service, converting it with the same rules a
+// stack deploy uses. The service is not labelled as part of a stack, and
+// registry credentials for its image are sent when one is configured.
+func (s *SwarmService) CreateServiceFromCompose(ctx context.Context, environmentID, composeSnippet, name string) (*swarmtypes.ServiceCreateResponse, error) {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	spec, err := libswarm.BuildServiceSpecFromCompose(ctx, dockerClient, libswarm.ServiceFromComposeOptions{
+		Name:           name,
+		ComposeContent: composeSnippet,
+		PathMapper:     s.getPathMapperInternal(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sanitizeServiceSpecInternal(&spec)
+
+	options := dockerclient.ServiceCreateOptions{Spec: spec}
+	if s.registryService != nil {
+		encodedAuth, err := s.registryService.GetRegistryAuthForImage(ctx, spec.TaskTemplate.ContainerSpec.Image)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to resolve registry credentials")
+		}
+		options.EncodedRegistryAuth = encodedAuth
+		options.QueryRegistry = encodedAuth != ""
+	}
+
+	resp, err := dockerClient.ServiceCreate(ctx, options)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create swarm service")
+	}
+
+	return &swarmtypes.ServiceCreateResponse{
+		ID:       resp.ID,
+		Warnings: resp.Warnings,
+	}, nil
+}
+
+func (s *SwarmService) UpdateService(ctx context.Context, environmentID, serviceID string, req swarmtypes.ServiceUpdateRequest) (*swarmtypes.ServiceUpdateResponse, error) {
+	if err := applySwarmRolloutConfigInternal(&req.Spec.UpdateConfig, req.UpdateConfig, false); err != nil {
+		return nil, errors.WrapIf(err, "invalid update config")
+	}
+	if err := applySwarmRolloutConfigInternal(&req.Spec.RollbackConfig, req.RollbackConfig, true); err != nil {
+		return nil, errors.WrapIf(err, "invalid rollback config")
+	}
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -424,17 +568,111 @@ func (s *SwarmService) UpdateService(ctx context.Context, serviceID string, req
 		return nil, errors.WrapIf(err, "failed to update swarm service")
 	}
 
+	warnings := resp.Warnings
+	if warning, ok := swarmStartFirstHealthWarningInternal(req.Spec); ok {
+		warnings = append(warnings, warning)
+	}
+
 	return &swarmtypes.ServiceUpdateResponse{
-		Warnings: resp.Warnings,
+		Warnings: warnings,
 	}, nil
 }
 
-func (s *SwarmService) RemoveService(ctx context.Context, serviceID string) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+// swarmStartFirstHealthWarningInternal warns when spec updates start-first
+// without a health check of its own. Swarm only stops an old task once its
+// replacement is running, which is healthy only when a health check exists,
+// so without one the image must define a HEALTHCHECK for the update to be
+// health-gated.
+func swarmStartFirstHealthWarningInternal(spec swarm.ServiceSpec) (string, bool) {
+	if swarmtypes.EffectiveUpdateOrder(spec.UpdateConfig) != string(swarm.UpdateOrderStartFirst) {
+		return "", false
+	}
+	if containerSpec := spec.TaskTemplate.ContainerSpec; containerSpec != nil && containerSpec.Healthcheck != nil {
+		if test := containerSpec.Healthcheck.Test; len(test) > 0 && test[0] != "NONE" {
+			return "", false
+		}
+	}
+	return "start-first updates are only health-gated when the service or its image defines a health check; otherwise old tasks stop as soon as new ones start", true
+}
+
+// applySwarmRolloutConfigInternal merges the set fields of override into *target,
+// creating the config when the spec has none. Rollback configs cannot use a
+// "rollback" failure action, matching the Docker CLI.
+func applySwarmRolloutConfigInternal(target **swarm.UpdateConfig, override *swarmtypes.ServiceRolloutConfig, rollback bool) error {
+	if override == nil {
+		return nil
+	}
+
+	cfg := swarm.UpdateConfig{}
+	if *target != nil {
+		cfg = **target
+	}
+
+	if override.Parallelism != nil {
+		if *override.Parallelism < 0 {
+			return errors.WrapIf(cerrdefs.ErrInvalidArgument, "parallelism must not be negative")
+		}
+		cfg.Parallelism = uint64(*override.Parallelism) //nolint:gosec // checked non-negative above
+	}
+	if override.Delay != nil {
+		delay, err := parseSwarmRolloutDurationInternal("delay", *override.Delay)
+		if err != nil {
+			return err
+		}
+		cfg.Delay = delay
+	}
+	if override.Monitor != nil {
+		monitor, err := parseSwarmRolloutDurationInternal("monitor", *override.Monitor)
+		if err != nil {
+			return err
+		}
+		cfg.Monitor = monitor
+	}
+	if override.FailureAction != nil {
+		action := swarm.FailureAction(strings.TrimSpace(*override.FailureAction))
+		switch action {
+		case swarm.UpdateFailureActionPause, swarm.UpdateFailureActionContinue:
+		case swarm.UpdateFailureActionRollback:
+			if rollback {
+				return errors.WrapIf(cerrdefs.ErrInvalidArgument, "failure action \"rollback\" is not allowed for a rollback")
+			}
+		default:
+			return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid failure action %q: must be pause, continue or rollback", action)
+		}
+		cfg.FailureAction = action
+	}
+	if override.MaxFailureRatio != nil {
+		if ratio := *override.MaxFailureRatio; ratio < 0 || ratio > 1 {
+			return errors.WrapIff(cerrdefs.ErrInvalidArgument, "max failure ratio %v must be between 0 and 1", ratio)
+		}
+		cfg.MaxFailureRatio = *override.MaxFailureRatio
+	}
+	if override.Order != nil {
+		order := swarm.UpdateOrder(strings.TrimSpace(*override.Order))
+		if order != swarm.UpdateOrderStopFirst && order != swarm.UpdateOrderStartFirst {
+			return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid order %q: must be stop-first or start-first", order)
+		}
+		cfg.Order = order
+	}
+
+	*target = &cfg
+	return nil
+}
+
+func parseSwarmRolloutDurationInternal(field, value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || duration < 0 {
+		return 0, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid %s %q: must be a non-negative duration such as 10s", field, value)
+	}
+	return duration, nil
+}
+
+func (s *SwarmService) RemoveService(ctx context.Context, environmentID, serviceID string) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -446,12 +684,19 @@ func (s *SwarmService) RemoveService(ctx context.Context, serviceID string) erro
 	return nil
 }
 
-func (s *SwarmService) StreamServiceLogs(ctx context.Context, serviceID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+// StreamServiceLogs streams the logs of a swarm service into logsChan. When details is true each
+// line is labeled with the task ID and node name parsed from the attributes Docker attaches to
+// service log lines; otherwise lines are forwarded unchanged.
+//
+// When timestamps is set and mergeWindow is positive, lines from all tasks are re-emitted in
+// timestamp order: buffered for mergeWindow at a time while following, or sorted as a whole
+// for a non-follow tail so replicas interleave chronologically.
+func (s *SwarmService) StreamServiceLogs(ctx context.Context, environmentID, serviceID string, logsChan chan<- string, follow bool, tail, since string, timestamps, details bool, mergeWindow time.Duration) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -472,11 +717,290 @@ func (s *SwarmService) StreamServiceLogs(ctx context.Context, serviceID string,
 	}
 	defer func() { _ = logs.Close() }()
 
+	target := logsChan
+	if details {
+		nodeNames := s.resolveSwarmNodeNamesInternal(ctx, dockerClient)
+		labeled := make(chan string, 256)
+		forwardDone := make(chan struct{})
+		go func() {
+			defer close(forwardDone)
+			for line := range labeled {
+				select {
+				case logsChan <- labelSwarmServiceLogLineInternal(line, nodeNames):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		defer func() {
+			close(labeled)
+			<-forwardDone
+		}()
+		target = labeled
+	}
+
+	if timestamps && mergeWindow > 0 {
+		merged := make(chan string, 256)
+		mergeDone := make(chan struct{})
+		window := mergeWindow
+		if !follow {
+			window = 0
+		}
+		go func(out chan<- string) {
+			defer close(mergeDone)
+			_ = dockerutil.MergeLogsByTimestamp(ctx, merged, out, window)
+		}(target)
+		defer func() {
+			close(merged)
+			<-mergeDone
+		}()
+		target = merged
+	}
+
 	if follow {
-		return dockerutil.StreamMultiplexedLogs(ctx, logs, logsChan)
+		return dockerutil.StreamMultiplexedLogs(ctx, logs, target)
+	}
+
+	return dockerutil.ReadAllLogs(ctx, logs, target)
+}
+
+// StreamTaskLogs streams the logs of a single swarm task into logsChan. Logs are
+// read through the swarm task log API so tasks on any node are covered; when that
+// fails and the task's container lives on this node, its container logs are read
+// instead. Following is turned off for tasks that have already exited, so a
+// failed task returns its last lines rather than waiting for output that never
+// comes.
+func (s *SwarmService) StreamTaskLogs(ctx context.Context, environmentID, taskID string, logsChan chan<- string, follow bool, tail, since string, timestamps bool) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	taskResult, err := dockerClient.TaskInspect(ctx, taskID, dockerclient.TaskInspectOptions{})
+	if err != nil {
+		return errors.WrapIf(err, "failed to inspect swarm task")
+	}
+	task := taskResult.Task
+
+	follow = follow && !isTaskTerminalInternal(task.Status.State)
+	isTTY := task.Spec.ContainerSpec != nil && task.Spec.ContainerSpec.TTY
+
+	logs, err := dockerClient.TaskLogs(ctx, task.ID, dockerclient.TaskLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+		Tail:       tail,
+		Since:      since,
+		Timestamps: timestamps,
+	})
+	if err != nil {
+		containerID := ""
+		if task.Status.ContainerStatus != nil {
+			containerID = task.Status.ContainerStatus.ContainerID
+		}
+		if containerID == "" {
+			return errors.WrapIf(err, "failed to get task logs")
+		}
+		slog.DebugContext(ctx, "Task log API failed; reading container logs", "taskID", task.ID, "containerID", containerID, "error", err)
+		containerLogs, containerErr := dockerClient.ContainerLogs(ctx, containerID, dockerclient.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Follow:     follow,
+			Tail:       tail,
+			Since:      since,
+			Timestamps: timestamps,
+		})
+		if containerErr != nil {
+			return errors.WrapIff(containerErr, "failed to get task logs (task log API: %v)", err)
+		}
+		return dockerutil.StreamContainerLogs(ctx, containerLogs, logsChan, follow, isTTY)
+	}
+
+	return dockerutil.StreamContainerLogs(ctx, logs, logsChan, follow, isTTY)
+}
+
+// StreamServiceEvents forwards Docker events about a swarm service and the
+// containers of its tasks into eventsChan until ctx is canceled. Task events
+// are only visible for containers running on the node Arcane is connected to.
+func (s *SwarmService) StreamServiceEvents(ctx context.Context, environmentID, serviceID string, eventsChan chan<- swarmtypes.ServiceEvent) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	serviceResult, err := dockerClient.ServiceInspect(ctx, serviceID, dockerclient.ServiceInspectOptions{})
+	if err != nil {
+		return errors.WrapIf(err, "failed to inspect swarm service")
+	}
+	service := serviceResult.Service
+
+	eventBus := s.dockerService.EventBus()
+	serviceCh, unsubscribeService := eventBus.Subscribe(events.ServiceEventType, bus.WithSubscriberBuffer(64))
+	defer unsubscribeService()
+	containerCh, unsubscribeContainer := eventBus.Subscribe(events.ContainerEventType, bus.WithSubscriberBuffer(64))
+	defer unsubscribeContainer()
+
+	for {
+		var msg events.Message
+		select {
+		case <-ctx.Done():
+			return nil
+		case m, ok := <-serviceCh:
+			if !ok {
+				return nil
+			}
+			msg = m
+		case m, ok := <-containerCh:
+			if !ok {
+				return nil
+			}
+			msg = m
+		}
+
+		event, ok := newServiceEventInternal(msg, service.ID, service.Spec.Name)
+		if !ok {
+			continue
+		}
+		select {
+		case eventsChan <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// newServiceEventInternal normalizes msg into a ServiceEvent when it concerns
+// the service itself or a container started for one of its tasks.
+func newServiceEventInternal(msg events.Message, serviceID, serviceName string) (swarmtypes.ServiceEvent, bool) {
+	eventTime := time.Unix(msg.Time, 0)
+	if msg.TimeNano != 0 {
+		eventTime = time.Unix(0, msg.TimeNano)
+	}
+	attrs := msg.Actor.Attributes
+
+	switch msg.Type {
+	case events.ServiceEventType:
+		if msg.Actor.ID != serviceID {
+			return swarmtypes.ServiceEvent{}, false
+		}
+		extra := maps.Clone(attrs)
+		delete(extra, "name")
+		return swarmtypes.ServiceEvent{
+			Time:        eventTime,
+			Scope:       swarmtypes.ServiceEventScopeService,
+			Action:      string(msg.Action),
+			ServiceID:   serviceID,
+			ServiceName: cmp.Or(attrs["name"], serviceName),
+			Attributes:  extra,
+		}, true
+	case events.ContainerEventType:
+		if attrs["com.docker.swarm.service.id"] != serviceID {
+			return swarmtypes.ServiceEvent{}, false
+		}
+		return swarmtypes.ServiceEvent{
+			Time:        eventTime,
+			Scope:       swarmtypes.ServiceEventScopeTask,
+			Action:      string(msg.Action),
+			ServiceID:   serviceID,
+			ServiceName: serviceName,
+			TaskID:      attrs["com.docker.swarm.task.id"],
+			TaskName:    attrs["com.docker.swarm.task.name"],
+			NodeID:      attrs["com.docker.swarm.node.id"],
+			ContainerID: msg.Actor.ID,
+			ExitCode:    attrs["exitCode"],
+		}, true
+	default:
+		return swarmtypes.ServiceEvent{}, false
+	}
+}
+
+// resolveSwarmNodeNamesInternal maps node IDs to hostnames for log labeling. Lookup failures are
+// not fatal; lines fall back to the node ID.
+func (s *SwarmService) resolveSwarmNodeNamesInternal(ctx context.Context, dockerClient *dockerclient.Client) map[string]string {
+	names := map[string]string{}
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, localEnvironmentID)
+	if err != nil {
+		slog.DebugContext(ctx, "failed to list swarm nodes for log labels", "error", err)
+		return names
+	}
+
+	for _, node := range nodes {
+		if node.Description.Hostname != "" {
+			names[node.ID] = node.Description.Hostname
+		}
+	}
+
+	return names
+}
+
+// labelSwarmServiceLogLineInternal replaces the attribute block Docker emits for service logs
+// ("com.docker.swarm.node.id=...,com.docker.swarm.task.id=... message") with a compact
+// "[task=<id> node=<name>]" label. The stderr marker and timestamp keep their leading position so
+// downstream parsing is unaffected. Lines without an attribute block are returned unchanged.
+func labelSwarmServiceLogLineInternal(line string, nodeNames map[string]string) string {
+	rest := line
+	var head strings.Builder
+
+	if after, ok := strings.CutPrefix(rest, "[STDERR] "); ok {
+		head.WriteString("[STDERR] ")
+		rest = after
+	}
+
+	if token, after, ok := strings.Cut(rest, " "); ok {
+		if _, err := time.Parse(time.RFC3339Nano, token); err == nil {
+			head.WriteString(token)
+			head.WriteByte(' ')
+			rest = after
+		}
+	}
+
+	attrsToken, message, _ := strings.Cut(rest, " ")
+	if !strings.Contains(attrsToken, "com.docker.swarm.") {
+		return line
+	}
+
+	attrs := make(map[string]string)
+	for pair := range strings.SplitSeq(attrsToken, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return line
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		attrs[key] = value
+	}
+
+	taskID := attrs["com.docker.swarm.task.id"]
+	nodeID := attrs["com.docker.swarm.node.id"]
+	if taskID == "" && nodeID == "" {
+		return line
+	}
+
+	nodeName := nodeNames[nodeID]
+	if nodeName == "" {
+		nodeName = nodeID
+	}
+
+	head.WriteString("[task=")
+	if len(taskID) > 12 {
+		taskID = taskID[:12]
 	}
+	head.WriteString(taskID)
+	head.WriteString(" node=")
+	head.WriteString(nodeName)
+	head.WriteString("] ")
+	head.WriteString(message)
 
-	return dockerutil.ReadAllLogs(ctx, logs, logsChan)
+	return head.String()
 }
 
 func (s *SwarmService) ListNodesPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]swarmtypes.NodeSummary, pagination.Response, error) {
@@ -493,24 +1017,23 @@ func (s *SwarmService) ListNodesPaginated(ctx context.Context, environmentID str
 		}
 
 		s.enrichNodeAgentStatusesInternal(ctx, environmentID, remote.Data)
-		result := pagination.SearchOrderAndPaginate(remote.Data, params, s.buildNodePaginationConfigInternal())
+		result := pagination.SearchOrderAndPaginate(remote.Data, params, s.NodePaginationConfig())
 		return result.Items, buildPaginationResponseInternal(result, params), nil
 	}
 
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, environmentID)
 	if err != nil {
-		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list swarm nodes")
+		return nil, pagination.Response{}, err
 	}
-	nodes := nodesResult.Items
 
 	items := make([]swarmtypes.NodeSummary, 0, len(nodes))
 	for _, node := range nodes {
@@ -519,7 +1042,7 @@ func (s *SwarmService) ListNodesPaginated(ctx context.Context, environmentID str
 
 	s.enrichNodeAgentStatusesInternal(ctx, environmentID, items)
 
-	config := s.buildNodePaginationConfigInternal()
+	config := s.NodePaginationConfig()
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 	paginationResp := buildPaginationResponseInternal(result, params)
 
@@ -544,11 +1067,11 @@ func (s *SwarmService) GetNode(ctx context.Context, environmentID, nodeID string
 		return &items[0], nil
 	}
 
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -925,7 +1448,7 @@ func (s *SwarmService) JoinEnvironments(ctx context.Context, managerEnvironmentI
 
 func (s *SwarmService) getSwarmJoinTokensForEnvironmentInternal(ctx context.Context, environmentID string) (*swarmtypes.SwarmJoinTokensResponse, error) {
 	if environmentID == "0" {
-		return s.GetSwarmJoinTokens(ctx)
+		return s.GetSwarmJoinTokens(ctx, environmentID)
 	}
 
 	var response struct {
@@ -1125,12 +1648,12 @@ func (s *SwarmService) buildNodeAgentStatusInternal(nodeID string, env *models.E
 	return status
 }
 
-func (s *SwarmService) ListTasksPaginated(ctx context.Context, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) ListTasksPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1146,11 +1669,10 @@ func (s *SwarmService) ListTasksPaginated(ctx context.Context, params pagination
 		serviceNameByID[service.ID] = service.Spec.Name
 	}
 
-	nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, environmentID)
 	if err != nil {
-		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list swarm nodes")
+		return nil, pagination.Response{}, err
 	}
-	nodes := nodesResult.Items
 
 	nodeNameByID := make(map[string]string, len(nodes))
 	for _, node := range nodes {
@@ -1168,7 +1690,7 @@ func (s *SwarmService) ListTasksPaginated(ctx context.Context, params pagination
 		items = append(items, swarmtypes.NewTaskSummary(task, serviceNameByID[task.ServiceID], nodeNameByID[task.NodeID]))
 	}
 
-	config := s.buildTaskPaginationConfigInternal()
+	config := s.TaskPaginationConfig()
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 	paginationResp := buildPaginationResponseInternal(result, params)
 
@@ -1176,11 +1698,11 @@ func (s *SwarmService) ListTasksPaginated(ctx context.Context, params pagination
 }
 
 func (s *SwarmService) ListStacksPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]swarmtypes.StackSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1244,7 +1766,9 @@ func (s *SwarmService) ListStacksPaginated(ctx context.Context, environmentID st
 }
 
 func (s *SwarmService) DeployStack(ctx context.Context, environmentID string, req swarmtypes.StackDeployRequest) (*swarmtypes.StackDeployResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
@@ -1253,7 +1777,7 @@ func (s *SwarmService) DeployStack(ctx context.Context, environmentID string, re
 		return nil, errors.New("stack name is required")
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1263,10 +1787,6 @@ func (s *SwarmService) DeployStack(ctx context.Context, environmentID string, re
 		return nil, err
 	}
 
-	if err := s.upsertStackSourceInternal(ctx, environmentID, stackName, req.ComposeContent, req.OverrideContent, req.EnvContent, req.Files); err != nil {
-		slog.WarnContext(ctx, "failed to persist swarm stack source", "environmentID", normalizeSwarmEnvironmentIDInternal(environmentID), "stackName", stackName, "error", err)
-	}
-
 	workingDir := req.WorkingDir
 	if workingDir == "" || len(req.Files) > 0 {
 		workingDir = stackSourceDir
@@ -1274,6 +1794,36 @@ func (s *SwarmService) DeployStack(ctx context.Context, environmentID string, re
 
 	pm := s.getPathMapperInternal(ctx)
 
+	// A dry run only reads the running stack; the saved source and Docker state stay untouched.
+	if req.DryRun {
+		if len(req.Files) > 0 {
+			stageDir, err := stageStackSourceForPlanInternal(stackSourceDir, req.Files)
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = os.RemoveAll(stageDir) }()
+			workingDir = stageDir
+		}
+
+		plan, err := libswarm.PlanStack(ctx, dockerClient, libswarm.StackDeployOptions{
+			Name:            stackName,
+			ComposeContent:  req.ComposeContent,
+			OverrideContent: req.OverrideContent,
+			EnvContent:      req.EnvContent,
+			Prune:           req.Prune,
+			WorkingDir:      workingDir,
+			PathMapper:      pm,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &swarmtypes.StackDeployResponse{Name: stackName, Plan: plan}, nil
+	}
+
+	if err := s.upsertStackSourceInternal(ctx, environmentID, stackName, req.ComposeContent, req.OverrideContent, req.EnvContent, req.Files); err != nil {
+		slog.WarnContext(ctx, "failed to persist swarm stack source", "environmentID", normalizeSwarmEnvironmentIDInternal(environmentID), "stackName", stackName, "error", err)
+	}
+
 	if err := libswarm.DeployStack(ctx, dockerClient, libswarm.StackDeployOptions{
 		Name:             stackName,
 		ComposeContent:   req.ComposeContent,
@@ -1294,15 +1844,21 @@ func (s *SwarmService) DeployStack(ctx context.Context, environmentID string, re
 		return nil, err
 	}
 
-	return &swarmtypes.StackDeployResponse{Name: stackName}, nil
+	// The deploy already succeeded; an image lookup failure only drops the report.
+	images, err := libswarm.ListStackServiceImages(ctx, dockerClient, stackName)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list resolved swarm stack images", "stackName", stackName, "error", err)
+	}
+
+	return &swarmtypes.StackDeployResponse{Name: stackName, Images: images}, nil
 }
 
-func (s *SwarmService) GetSwarmInfo(ctx context.Context) (*swarmtypes.SwarmInfo, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) GetSwarmInfo(ctx context.Context, environmentID string) (*swarmtypes.SwarmInfo, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1315,30 +1871,125 @@ func (s *SwarmService) GetSwarmInfo(ctx context.Context) (*swarmtypes.SwarmInfo,
 	return new(swarmtypes.NewSwarmInfo(infoResult.Swarm)), nil
 }
 
-func (s *SwarmService) InitSwarm(ctx context.Context, req swarmtypes.SwarmInitRequest) (*swarmtypes.SwarmInitResponse, error) {
-	dockerClient, err := s.dockerService.GetClient(ctx)
+// GetClusterResources sums the CPU and memory of the swarm's nodes and
+// subtracts the reservations of running tasks, giving the capacity left for
+// new tasks overall and per node. Only ready, active nodes count toward the
+// cluster totals; drained, paused or down nodes are still listed. The cluster's
+// Available is the sum of each node's Available, so a node reserved beyond its
+// capacity does not hide the headroom left on the others.
+func (s *SwarmService) GetClusterResources(ctx context.Context, environmentID string) (*swarmtypes.ClusterResources, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	spec, err := decodeSwarmSpecInternal(req.Spec)
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, environmentID)
 	if err != nil {
 		return nil, err
 	}
 
-	defaultAddrPool := make([]netip.Prefix, 0, len(req.DefaultAddrPool))
-	for _, raw := range req.DefaultAddrPool {
-		prefix, err := netip.ParsePrefix(raw)
-		if err != nil {
-			return nil, errors.WrapIff(err, "failed to parse default address pool %q", raw)
-		}
-		defaultAddrPool = append(defaultAddrPool, prefix.Masked())
+	taskFilters := make(dockerclient.Filters).Add("desired-state", string(swarm.TaskStateRunning))
+	tasksResult, err := dockerClient.TaskList(ctx, dockerclient.TaskListOptions{Filters: taskFilters})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list swarm tasks")
 	}
 
-	initResult, err := dockerClient.SwarmInit(ctx, dockerclient.SwarmInitOptions{
-		ListenAddr:       defaultSwarmListenAddrInternal(req.ListenAddr),
-		AdvertiseAddr:    req.AdvertiseAddr,
-		DataPathAddr:     req.DataPathAddr,
+	return new(summarizeClusterResourcesInternal(nodes, tasksResult.Items)), nil
+}
+
+func summarizeClusterResourcesInternal(nodes []swarm.Node, tasks []swarm.Task) swarmtypes.ClusterResources {
+	type reservation struct {
+		nanoCPUs, memoryBytes int64
+		tasks                 int
+	}
+	reservedByNode := make(map[string]reservation, len(nodes))
+	for _, task := range tasks {
+		if task.NodeID == "" || isTaskTerminalInternal(task.Status.State) {
+			continue
+		}
+		reserved := reservedByNode[task.NodeID]
+		reserved.tasks++
+		if task.Spec.Resources != nil && task.Spec.Resources.Reservations != nil {
+			reserved.nanoCPUs += task.Spec.Resources.Reservations.NanoCPUs
+			reserved.memoryBytes += task.Spec.Resources.Reservations.MemoryBytes
+		}
+		reservedByNode[task.NodeID] = reserved
+	}
+
+	capacity := func(total, allocated int64) swarmtypes.ResourceCapacity {
+		return swarmtypes.ResourceCapacity{Total: total, Allocated: allocated, Available: max(total-allocated, 0)}
+	}
+
+	add := func(sum *swarmtypes.ResourceCapacity, node swarmtypes.ResourceCapacity) {
+		sum.Total += node.Total
+		sum.Allocated += node.Allocated
+		sum.Available += node.Available
+	}
+
+	var clusterCPUs, clusterMemory swarmtypes.ResourceCapacity
+	nodeResources := make([]swarmtypes.NodeResources, 0, len(nodes))
+	for _, node := range nodes {
+		resources := node.Description.Resources
+		reserved := reservedByNode[node.ID]
+		schedulable := node.Status.State == swarm.NodeStateReady && node.Spec.Availability == swarm.NodeAvailabilityActive
+
+		nodeCPUs := capacity(resources.NanoCPUs, reserved.nanoCPUs)
+		nodeMemory := capacity(resources.MemoryBytes, reserved.memoryBytes)
+		nodeResources = append(nodeResources, swarmtypes.NodeResources{
+			NodeID:       node.ID,
+			Hostname:     node.Description.Hostname,
+			Schedulable:  schedulable,
+			NanoCPUs:     nodeCPUs,
+			MemoryBytes:  nodeMemory,
+			RunningTasks: reserved.tasks,
+		})
+		if schedulable {
+			add(&clusterCPUs, nodeCPUs)
+			add(&clusterMemory, nodeMemory)
+		}
+	}
+
+	sort.SliceStable(nodeResources, func(i, j int) bool {
+		return nodeResources[i].Hostname < nodeResources[j].Hostname
+	})
+
+	return swarmtypes.ClusterResources{
+		NanoCPUs:    clusterCPUs,
+		MemoryBytes: clusterMemory,
+		Nodes:       nodeResources,
+	}
+}
+
+func (s *SwarmService) InitSwarm(ctx context.Context, environmentID string, req swarmtypes.SwarmInitRequest) (*swarmtypes.SwarmInitResponse, error) {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	spec, err := decodeSwarmSpecInternal(req.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultAddrPool := make([]netip.Prefix, 0, len(req.DefaultAddrPool))
+	for _, raw := range req.DefaultAddrPool {
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to parse default address pool %q", raw)
+		}
+		defaultAddrPool = append(defaultAddrPool, prefix.Masked())
+	}
+
+	initResult, err := dockerClient.SwarmInit(ctx, dockerclient.SwarmInitOptions{
+		ListenAddr:       defaultSwarmListenAddrInternal(req.ListenAddr),
+		AdvertiseAddr:    req.AdvertiseAddr,
+		DataPathAddr:     req.DataPathAddr,
 		DataPathPort:     req.DataPathPort,
 		ForceNewCluster:  req.ForceNewCluster,
 		Spec:             spec,
@@ -1356,8 +2007,10 @@ func (s *SwarmService) InitSwarm(ctx context.Context, req swarmtypes.SwarmInitRe
 	return &swarmtypes.SwarmInitResponse{NodeID: initResult.NodeID}, nil
 }
 
-func (s *SwarmService) JoinSwarm(ctx context.Context, req swarmtypes.SwarmJoinRequest) error {
-	dockerClient, err := s.dockerService.GetClient(ctx)
+func (s *SwarmService) JoinSwarm(ctx context.Context, environmentID string, req swarmtypes.SwarmJoinRequest) error {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1378,12 +2031,14 @@ func (s *SwarmService) JoinSwarm(ctx context.Context, req swarmtypes.SwarmJoinRe
 	return nil
 }
 
-func (s *SwarmService) LeaveSwarm(ctx context.Context, req swarmtypes.SwarmLeaveRequest) error {
-	if err := s.ensureSwarmActiveInternal(ctx); err != nil {
+func (s *SwarmService) LeaveSwarm(ctx context.Context, environmentID string, req swarmtypes.SwarmLeaveRequest) error {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmActiveInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1397,12 +2052,12 @@ func (s *SwarmService) LeaveSwarm(ctx context.Context, req swarmtypes.SwarmLeave
 	return nil
 }
 
-func (s *SwarmService) UnlockSwarm(ctx context.Context, req swarmtypes.SwarmUnlockRequest) error {
-	if err := s.ensureSwarmActiveInternal(ctx); err != nil {
+func (s *SwarmService) UnlockSwarm(ctx context.Context, environmentID string, req swarmtypes.SwarmUnlockRequest) error {
+	if err := s.ensureSwarmActiveInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1414,12 +2069,12 @@ func (s *SwarmService) UnlockSwarm(ctx context.Context, req swarmtypes.SwarmUnlo
 	return nil
 }
 
-func (s *SwarmService) GetSwarmUnlockKey(ctx context.Context) (*swarmtypes.SwarmUnlockKeyResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) GetSwarmUnlockKey(ctx context.Context, environmentID string) (*swarmtypes.SwarmUnlockKeyResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1432,12 +2087,12 @@ func (s *SwarmService) GetSwarmUnlockKey(ctx context.Context) (*swarmtypes.Swarm
 	return &swarmtypes.SwarmUnlockKeyResponse{UnlockKey: unlockResult.Key}, nil
 }
 
-func (s *SwarmService) GetSwarmJoinTokens(ctx context.Context) (*swarmtypes.SwarmJoinTokensResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) GetSwarmJoinTokens(ctx context.Context, environmentID string) (*swarmtypes.SwarmJoinTokensResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1453,12 +2108,12 @@ func (s *SwarmService) GetSwarmJoinTokens(ctx context.Context) (*swarmtypes.Swar
 	}, nil
 }
 
-func (s *SwarmService) RotateSwarmJoinTokens(ctx context.Context, req swarmtypes.SwarmRotateJoinTokensRequest) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) RotateSwarmJoinTokens(ctx context.Context, environmentID string, req swarmtypes.SwarmRotateJoinTokensRequest) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1487,12 +2142,12 @@ func (s *SwarmService) RotateSwarmJoinTokens(ctx context.Context, req swarmtypes
 	return nil
 }
 
-func (s *SwarmService) UpdateSwarmSpec(ctx context.Context, req swarmtypes.SwarmUpdateRequest) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) UpdateSwarmSpec(ctx context.Context, environmentID string, req swarmtypes.SwarmUpdateRequest) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1524,22 +2179,22 @@ func (s *SwarmService) UpdateSwarmSpec(ctx context.Context, req swarmtypes.Swarm
 	return nil
 }
 
-func (s *SwarmService) ListServiceTasksPaginated(ctx context.Context, serviceID string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) ListServiceTasksPaginated(ctx context.Context, environmentID, serviceID string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
 	filters := make(dockerclient.Filters)
 	filters.Add("service", serviceID)
-	return s.listTasksPaginatedWithFiltersInternal(ctx, filters, params)
+	return s.listTasksPaginatedWithFiltersInternal(ctx, environmentID, filters, params)
 }
 
-func (s *SwarmService) RollbackService(ctx context.Context, serviceID string) (*swarmtypes.ServiceUpdateResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) RollbackService(ctx context.Context, environmentID, serviceID string) (*swarmtypes.ServiceUpdateResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1561,12 +2216,107 @@ func (s *SwarmService) RollbackService(ctx context.Context, serviceID string) (*
 	return &swarmtypes.ServiceUpdateResponse{Warnings: updateResult.Warnings}, nil
 }
 
-func (s *SwarmService) ScaleService(ctx context.Context, serviceID string, replicas uint64) (*swarmtypes.ServiceUpdateResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+// ForceUpdateService restarts every task of a service without changing its
+// spec, matching `docker service update --force`.
+func (s *SwarmService) ForceUpdateService(ctx context.Context, environmentID, serviceID string) (*swarmtypes.ServiceUpdateResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	serviceResult, err := dockerClient.ServiceInspect(ctx, serviceID, dockerclient.ServiceInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect swarm service")
+	}
+	service := serviceResult.Service
+	service.Spec.TaskTemplate.ForceUpdate++
+
+	updateResult, err := dockerClient.ServiceUpdate(ctx, serviceID, dockerclient.ServiceUpdateOptions{
+		Version: service.Version,
+		Spec:    service.Spec,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to force update swarm service")
+	}
+
+	return &swarmtypes.ServiceUpdateResponse{Warnings: updateResult.Warnings}, nil
+}
+
+// PinServiceImage pins a service to the image digest it currently runs. The
+// spec image is rewritten to the digest-qualified reference and the digest is
+// recorded in swarmtypes.ServiceImagePinnedLabel, so later stack deploys keep
+// the service on it instead of resolving the tag again.
+//
+// Returns an invalid argument error when the service has no container spec or
+// its image was never resolved to a digest.
+func (s *SwarmService) PinServiceImage(ctx context.Context, environmentID, serviceID string) (*swarmtypes.ServiceImagePinResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	serviceResult, err := dockerClient.ServiceInspect(ctx, serviceID, dockerclient.ServiceInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect swarm service")
+	}
+	service := serviceResult.Service
+	if service.Spec.TaskTemplate.ContainerSpec == nil {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "swarm service has no container spec")
+	}
+
+	pinnedImage, digest, err := pinnedServiceImageInternal(service.Spec.TaskTemplate.ContainerSpec.Image)
+	if err != nil {
+		return nil, err
+	}
+	service.Spec.TaskTemplate.ContainerSpec.Image = pinnedImage
+	if service.Spec.Labels == nil {
+		service.Spec.Labels = map[string]string{}
+	}
+	service.Spec.Labels[swarmtypes.ServiceImagePinnedLabel] = digest
+
+	updateResult, err := dockerClient.ServiceUpdate(ctx, serviceID, dockerclient.ServiceUpdateOptions{
+		Version: service.Version,
+		Spec:    service.Spec,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to pin swarm service image")
+	}
+
+	return &swarmtypes.ServiceImagePinResponse{Image: pinnedImage, Digest: digest, Warnings: updateResult.Warnings}, nil
+}
+
+// pinnedServiceImageInternal turns a resolved service image such as
+// "nginx:latest@sha256:…" into its tagless "nginx@sha256:…" form and digest.
+func pinnedServiceImageInternal(image string) (string, string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", "", errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid service image %q", image)
+	}
+	canonical, ok := named.(reference.Canonical)
+	if !ok {
+		return "", "", errors.WrapIff(cerrdefs.ErrInvalidArgument, "service image %s is not resolved to a digest; redeploy it with image resolution enabled first", image)
+	}
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), canonical.Digest())
+	if err != nil {
+		return "", "", errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid service image %q", image)
+	}
+	return reference.FamiliarString(pinned), canonical.Digest().String(), nil
+}
+
+func (s *SwarmService) ScaleService(ctx context.Context, environmentID, serviceID string, replicas uint64) (*swarmtypes.ServiceUpdateResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1605,173 +2355,650 @@ func applySwarmServiceScaleInternal(mode *swarm.ServiceMode, replicas uint64) er
 	return nil
 }
 
-func (s *SwarmService) UpdateNode(ctx context.Context, nodeID string, req swarmtypes.NodeUpdateRequest) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return err
+// ScaleStack scales several services of a stack in one call. Every requested service is
+// resolved and checked for a scalable mode before any update is sent, so an invalid request
+// leaves the stack untouched. Per-service update failures are reported in the results.
+func (s *SwarmService) ScaleStack(ctx context.Context, environmentID, stackName string, replicas map[string]uint64) ([]swarmtypes.StackScaleResult, error) {
+	if len(replicas) == 0 {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "at least one service is required")
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
-	if err != nil {
-		return errors.WrapIf(err, "failed to connect to Docker")
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
 	}
 
-	nodeResult, err := dockerClient.NodeInspect(ctx, nodeID, dockerclient.NodeInspectOptions{})
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
-		return errors.WrapIf(err, "failed to inspect swarm node")
-	}
-
-	version := req.Version
-	if version == 0 {
-		version = nodeResult.Node.Version.Index
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	spec := nodeResult.Node.Spec
-	if req.Name != nil {
-		spec.Name = *req.Name
-	}
-	if req.Labels != nil {
-		spec.Labels = req.Labels
-	}
-	if req.Role != nil {
-		spec.Role = *req.Role
+	stackServices, err := s.listStackServicesRawInternal(ctx, dockerClient, stackName)
+	if err != nil {
+		return nil, err
 	}
-	if req.Availability != nil {
-		spec.Availability = *req.Availability
+	if len(stackServices) == 0 {
+		return nil, errors.WrapIff(cerrdefs.ErrNotFound, "stack %s not found", stackName)
 	}
 
-	if _, err := dockerClient.NodeUpdate(ctx, nodeID, dockerclient.NodeUpdateOptions{
-		Version: swarm.Version{Index: version},
-		Spec:    spec,
-	}); err != nil {
-		return errors.WrapIf(err, "failed to update swarm node")
+	names := make([]string, 0, len(replicas))
+	for name := range replicas {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	return nil
-}
-
-func (s *SwarmService) RemoveNode(ctx context.Context, nodeID string, force bool) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return err
+	targets := make([]swarm.Service, 0, len(names))
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		service, ok := findSwarmStackServiceInternal(stackServices, stackName, name)
+		if !ok {
+			return nil, errors.WrapIff(cerrdefs.ErrNotFound, "service %s not found in stack %s", name, stackName)
+		}
+		if previous, dup := seen[service.ID]; dup {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "services %s and %s refer to the same service", previous, name)
+		}
+		seen[service.ID] = name
+		if err := applySwarmServiceScaleInternal(&service.Spec.Mode, replicas[name]); err != nil {
+			return nil, errors.WrapIff(err, "service %s", name)
+		}
+		targets = append(targets, service)
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
-	if err != nil {
-		return errors.WrapIf(err, "failed to connect to Docker")
-	}
+	results := make([]swarmtypes.StackScaleResult, 0, len(targets))
+	for i, service := range targets {
+		result := swarmtypes.StackScaleResult{
+			Service:   names[i],
+			ServiceID: service.ID,
+			Replicas:  replicas[names[i]],
+		}
 
-	if _, err := dockerClient.NodeRemove(ctx, nodeID, dockerclient.NodeRemoveOptions{Force: force}); err != nil {
-		return errors.WrapIf(err, "failed to remove swarm node")
+		updateResult, err := dockerClient.ServiceUpdate(ctx, service.ID, dockerclient.ServiceUpdateOptions{
+			Version: service.Version,
+			Spec:    service.Spec,
+		})
+		if err != nil {
+			result.Error = errors.WrapIf(err, "failed to scale swarm service").Error()
+		} else {
+			result.Success = true
+			result.Warnings = updateResult.Warnings
+		}
+
+		results = append(results, result)
 	}
 
-	return nil
+	return results, nil
 }
 
-func (s *SwarmService) PromoteNode(ctx context.Context, nodeID string) error {
-	return s.UpdateNode(ctx, nodeID, swarmtypes.NodeUpdateRequest{Role: new(swarm.NodeRoleManager)})
-}
+// findSwarmStackServiceInternal matches a service by ID, full name, or name without the
+// "<stack>_" namespace prefix.
+func findSwarmStackServiceInternal(services []swarm.Service, stackName, name string) (swarm.Service, bool) {
+	name = strings.TrimSpace(name)
+	prefixed := strings.TrimSpace(stackName) + "_" + name
+	for _, service := range services {
+		if service.ID == name || service.Spec.Name == name || service.Spec.Name == prefixed {
+			return service, true
+		}
+	}
 
-func (s *SwarmService) DemoteNode(ctx context.Context, nodeID string) error {
-	return s.UpdateNode(ctx, nodeID, swarmtypes.NodeUpdateRequest{Role: new(swarm.NodeRoleWorker)})
+	return swarm.Service{}, false
 }
 
-func (s *SwarmService) ListNodeTasksPaginated(ctx context.Context, nodeID string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return nil, pagination.Response{}, err
+// UpdateServicePlacement replaces the placement constraints and/or preferences
+// of a service at its current version. Nil fields keep the existing placement.
+func (s *SwarmService) UpdateServicePlacement(ctx context.Context, environmentID, serviceID string, req swarmtypes.ServicePlacementRequest) (*swarmtypes.ServiceUpdateResponse, error) {
+	if err := validateSwarmPlacementRequestInternal(req); err != nil {
+		return nil, err
 	}
 
-	filters := make(dockerclient.Filters)
-	filters.Add("node", nodeID)
-	return s.listTasksPaginatedWithFiltersInternal(ctx, filters, params)
-}
-
-func (s *SwarmService) GetStack(ctx context.Context, environmentID, stackName string) (*swarmtypes.StackInspect, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	services, err := s.listStackServicesRawInternal(ctx, dockerClient, stackName)
+	serviceResult, err := dockerClient.ServiceInspect(ctx, serviceID, dockerclient.ServiceInspectOptions{})
 	if err != nil {
-		return nil, err
+		return nil, errors.WrapIf(err, "failed to inspect swarm service")
 	}
-	if len(services) == 0 {
-		persisted, err := s.getPersistedStackSourceSummaryInternal(ctx, environmentID, stackName)
-		if err != nil {
-			if cerrdefs.IsNotFound(err) {
-				return nil, cerrdefs.ErrNotFound
-			}
-			return nil, err
-		}
+	service := serviceResult.Service
 
-		return &swarmtypes.StackInspect{
-			Name:      persisted.Name,
-			Namespace: persisted.Namespace,
-			Services:  persisted.Services,
-			CreatedAt: persisted.CreatedAt,
-			UpdatedAt: persisted.UpdatedAt,
-		}, nil
-	}
+	applySwarmServicePlacementInternal(&service.Spec.TaskTemplate, req)
 
-	createdAt := services[0].CreatedAt
-	updatedAt := services[0].UpdatedAt
-	for _, service := range services[1:] {
-		if service.CreatedAt.Before(createdAt) {
-			createdAt = service.CreatedAt
-		}
-		if service.UpdatedAt.After(updatedAt) {
-			updatedAt = service.UpdatedAt
-		}
+	updateResult, err := dockerClient.ServiceUpdate(ctx, serviceID, dockerclient.ServiceUpdateOptions{
+		Version: service.Version,
+		Spec:    service.Spec,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to update swarm service placement")
 	}
 
-	return &swarmtypes.StackInspect{
-		Name:      stackName,
-		Namespace: stackName,
-		Services:  len(services),
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-	}, nil
+	return &swarmtypes.ServiceUpdateResponse{Warnings: updateResult.Warnings}, nil
 }
 
-func (s *SwarmService) GetStackSource(ctx context.Context, environmentID, stackName string) (*swarmtypes.StackSource, error) {
-	stackName = strings.TrimSpace(stackName)
-	if stackName == "" {
-		return nil, errors.New("stack name is required")
+func applySwarmServicePlacementInternal(taskTemplate *swarm.TaskSpec, req swarmtypes.ServicePlacementRequest) {
+	if req.Constraints == nil && req.Preferences == nil {
+		return
 	}
-
-	_, stackSourceDir, err := s.resolveSwarmStackSourceDirInternal(ctx, environmentID, stackName)
-	if err != nil {
-		return nil, err
+	if taskTemplate.Placement == nil {
+		taskTemplate.Placement = &swarm.Placement{}
 	}
 
-	composeContent, err := os.ReadFile(filepath.Join(stackSourceDir, swarmStackComposeFilename))
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, cerrdefs.ErrNotFound
+	if req.Constraints != nil {
+		constraints := make([]string, 0, len(*req.Constraints))
+		for _, constraint := range *req.Constraints {
+			constraints = append(constraints, strings.TrimSpace(constraint))
 		}
-		return nil, errors.WrapIf(err, "failed to read swarm stack compose source")
+		taskTemplate.Placement.Constraints = constraints
 	}
 
-	overrideContent := ""
-	overrideBytes, err := os.ReadFile(filepath.Join(stackSourceDir, swarmStackOverrideFilename))
-	if err == nil {
-		overrideContent = string(overrideBytes)
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return nil, errors.WrapIf(err, "failed to read swarm stack override source")
+	if req.Preferences != nil {
+		preferences := make([]swarm.PlacementPreference, 0, len(*req.Preferences))
+		for _, preference := range *req.Preferences {
+			preferences = append(preferences, swarm.PlacementPreference{
+				Spread: &swarm.SpreadOver{SpreadDescriptor: strings.TrimSpace(preference.Spread)},
+			})
+		}
+		taskTemplate.Placement.Preferences = preferences
 	}
+}
 
-	envContent := ""
-	envBytes, err := os.ReadFile(filepath.Join(stackSourceDir, swarmStackEnvFilename))
-	if err == nil {
-		envContent = string(envBytes)
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return nil, errors.WrapIf(err, "failed to read swarm stack env source")
+func validateSwarmPlacementRequestInternal(req swarmtypes.ServicePlacementRequest) error {
+	if req.Constraints != nil {
+		for _, constraint := range *req.Constraints {
+			if err := validateSwarmPlacementConstraintInternal(constraint); err != nil {
+				return err
+			}
+		}
 	}
 
-	var files []swarmtypes.SyncFile
+	if req.Preferences != nil {
+		for _, preference := range *req.Preferences {
+			descriptor := strings.TrimSpace(preference.Spread)
+			if !strings.HasPrefix(descriptor, "node.labels.") && !strings.HasPrefix(descriptor, "engine.labels.") {
+				return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid placement preference %q: spread must reference node.labels.<name> or engine.labels.<name>", preference.Spread)
+			}
+			if strings.TrimPrefix(strings.TrimPrefix(descriptor, "node.labels."), "engine.labels.") == "" {
+				return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid placement preference %q: label name is required", preference.Spread)
+			}
+		}
+	}
+
+	return nil
+}
+
+var swarmPlacementConstraintKeys = map[string]struct{}{
+	"node.id":            {},
+	"node.hostname":      {},
+	"node.role":          {},
+	"node.platform.os":   {},
+	"node.platform.arch": {},
+}
+
+func validateSwarmPlacementConstraintInternal(constraint string) error {
+	trimmed := strings.TrimSpace(constraint)
+	operatorIndex := strings.Index(trimmed, "==")
+	if notEqualIndex := strings.Index(trimmed, "!="); notEqualIndex >= 0 && (operatorIndex < 0 || notEqualIndex < operatorIndex) {
+		operatorIndex = notEqualIndex
+	}
+	if operatorIndex < 0 {
+		return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid placement constraint %q: expected <key>==<value> or <key>!=<value>", constraint)
+	}
+
+	key := strings.TrimSpace(trimmed[:operatorIndex])
+	value := strings.TrimSpace(trimmed[operatorIndex+2:])
+	if key == "" || value == "" {
+		return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid placement constraint %q: key and value are required", constraint)
+	}
+	if strings.ContainsAny(value, "=!") {
+		return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid placement constraint %q: value must not contain an operator", constraint)
+	}
+
+	if _, ok := swarmPlacementConstraintKeys[key]; ok {
+		return nil
+	}
+	for _, prefix := range []string{"node.labels.", "engine.labels."} {
+		if strings.HasPrefix(key, prefix) && strings.TrimPrefix(key, prefix) != "" {
+			return nil
+		}
+	}
+
+	return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid placement constraint %q: unsupported key %q", constraint, key)
+}
+
+// UpdateServiceResources merges CPU and memory limits and reservations into a
+// service's task resources at its current version, leaving fields the request
+// omits and the other resource settings (PIDs, generic resources) untouched.
+// The response warns when no schedulable node could fit a task's reservation,
+// since such tasks stay pending.
+func (s *SwarmService) UpdateServiceResources(ctx context.Context, environmentID, serviceID string, limits, reservations *swarmtypes.ServiceResourceValues) (*swarmtypes.ServiceUpdateResponse, error) {
+	if limits == nil && reservations == nil {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "limits or reservations are required")
+	}
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	serviceResult, err := dockerClient.ServiceInspect(ctx, serviceID, dockerclient.ServiceInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect swarm service")
+	}
+	service := serviceResult.Service
+
+	if err := applySwarmServiceResourcesInternal(&service.Spec.TaskTemplate, limits, reservations); err != nil {
+		return nil, err
+	}
+
+	updateResult, err := dockerClient.ServiceUpdate(ctx, serviceID, dockerclient.ServiceUpdateOptions{
+		Version: service.Version,
+		Spec:    service.Spec,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to update swarm service resources")
+	}
+
+	warnings := updateResult.Warnings
+	if resources := service.Spec.TaskTemplate.Resources; resources != nil && resources.Reservations != nil {
+		if nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, environmentID); err == nil {
+			warnings = append(warnings, swarmReservationWarningsInternal(*resources.Reservations, nodes)...)
+		} else {
+			slog.DebugContext(ctx, "Failed to list swarm nodes for reservation check", "serviceID", serviceID, "error", err)
+		}
+	}
+
+	return &swarmtypes.ServiceUpdateResponse{Warnings: warnings}, nil
+}
+
+// applySwarmServiceResourcesInternal merges limits and reservations into
+// taskTemplate.Resources, then checks the merged values: each must be zero
+// (unset) or above the swarm minimum, and a reservation may not exceed the
+// matching limit.
+func applySwarmServiceResourcesInternal(taskTemplate *swarm.TaskSpec, limits, reservations *swarmtypes.ServiceResourceValues) error {
+	for _, values := range []*swarmtypes.ServiceResourceValues{limits, reservations} {
+		if err := validateSwarmResourceValuesInternal(values); err != nil {
+			return err
+		}
+	}
+
+	// Work on copies so a rejected merge leaves the task template untouched.
+	var resources swarm.ResourceRequirements
+	if taskTemplate.Resources != nil {
+		resources = *taskTemplate.Resources
+	}
+	if limits != nil {
+		var limit swarm.Limit
+		if resources.Limits != nil {
+			limit = *resources.Limits
+		}
+		if limits.NanoCPUs != nil {
+			limit.NanoCPUs = *limits.NanoCPUs
+		}
+		if limits.MemoryBytes != nil {
+			limit.MemoryBytes = *limits.MemoryBytes
+		}
+		resources.Limits = &limit
+	}
+	if reservations != nil {
+		var reservation swarm.Resources
+		if resources.Reservations != nil {
+			reservation = *resources.Reservations
+		}
+		if reservations.NanoCPUs != nil {
+			reservation.NanoCPUs = *reservations.NanoCPUs
+		}
+		if reservations.MemoryBytes != nil {
+			reservation.MemoryBytes = *reservations.MemoryBytes
+		}
+		resources.Reservations = &reservation
+	}
+
+	if resources.Limits != nil && resources.Reservations != nil {
+		if resources.Limits.NanoCPUs > 0 && resources.Reservations.NanoCPUs > resources.Limits.NanoCPUs {
+			return errors.WrapIf(cerrdefs.ErrInvalidArgument, "CPU reservation must not exceed the CPU limit")
+		}
+		if resources.Limits.MemoryBytes > 0 && resources.Reservations.MemoryBytes > resources.Limits.MemoryBytes {
+			return errors.WrapIf(cerrdefs.ErrInvalidArgument, "memory reservation must not exceed the memory limit")
+		}
+	}
+
+	taskTemplate.Resources = &resources
+	return nil
+}
+
+// validateSwarmResourceValuesInternal applies the same minimums swarmkit
+// enforces, so a bad value is rejected before the update is sent.
+func validateSwarmResourceValuesInternal(values *swarmtypes.ServiceResourceValues) error {
+	if values == nil {
+		return nil
+	}
+	if values.NanoCPUs != nil && *values.NanoCPUs != 0 && *values.NanoCPUs < swarmMinNanoCPUs {
+		return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid CPU value %d: must be 0 or at least %d nano CPUs", *values.NanoCPUs, swarmMinNanoCPUs)
+	}
+	if values.MemoryBytes != nil && *values.MemoryBytes != 0 && *values.MemoryBytes < swarmMinMemoryBytes {
+		return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid memory value %d: must be 0 or at least 4 MiB", *values.MemoryBytes)
+	}
+	return nil
+}
+
+// swarmReservationWarningsInternal reports reservations that no schedulable
+// node has the total capacity for.
+func swarmReservationWarningsInternal(reservation swarm.Resources, nodes []swarm.Node) []string {
+	var largestCPUs, largestMemory int64
+	for _, node := range nodes {
+		if node.Status.State != swarm.NodeStateReady || node.Spec.Availability != swarm.NodeAvailabilityActive {
+			continue
+		}
+		largestCPUs = max(largestCPUs, node.Description.Resources.NanoCPUs)
+		largestMemory = max(largestMemory, node.Description.Resources.MemoryBytes)
+	}
+
+	var warnings []string
+	if reservation.NanoCPUs > largestCPUs {
+		warnings = append(warnings, fmt.Sprintf("No schedulable node has %d nano CPUs to reserve; tasks will stay pending", reservation.NanoCPUs))
+	}
+	if reservation.MemoryBytes > largestMemory {
+		warnings = append(warnings, fmt.Sprintf("No schedulable node has %d bytes of memory to reserve; tasks will stay pending", reservation.MemoryBytes))
+	}
+	return warnings
+}
+
+func (s *SwarmService) UpdateNode(ctx context.Context, environmentID, nodeID string, req swarmtypes.NodeUpdateRequest) error {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	nodeResult, err := dockerClient.NodeInspect(ctx, nodeID, dockerclient.NodeInspectOptions{})
+	if err != nil {
+		return errors.WrapIf(err, "failed to inspect swarm node")
+	}
+
+	version := req.Version
+	if version == 0 {
+		version = nodeResult.Node.Version.Index
+	}
+
+	spec := nodeResult.Node.Spec
+	if req.Name != nil {
+		spec.Name = *req.Name
+	}
+	if req.Labels != nil {
+		spec.Labels = req.Labels
+	}
+	if req.Role != nil {
+		spec.Role = *req.Role
+	}
+	if req.Availability != nil {
+		spec.Availability = *req.Availability
+	}
+
+	if _, err := dockerClient.NodeUpdate(ctx, nodeID, dockerclient.NodeUpdateOptions{
+		Version: swarm.Version{Index: version},
+		Spec:    spec,
+	}); err != nil {
+		return errors.WrapIf(err, "failed to update swarm node")
+	}
+
+	return nil
+}
+
+// PatchNodeLabels sets and removes individual labels on a node without replacing
+// the others. The labels are merged into the spec at the version just inspected,
+// and the merge is redone against a fresh inspect when another update lands first,
+// so concurrent edits to different labels are not lost.
+func (s *SwarmService) PatchNodeLabels(ctx context.Context, environmentID, nodeID string, set map[string]string, remove []string) (*swarmtypes.NodeLabelsResponse, error) {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if len(set) == 0 && len(remove) == 0 {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "at least one label to set or remove is required")
+	}
+	for key := range set {
+		if strings.TrimSpace(key) == "" {
+			return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "label key must not be empty")
+		}
+		if slices.Contains(remove, key) {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "label %q cannot be both set and removed", key)
+		}
+	}
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	for attempt := 1; ; attempt++ {
+		nodeResult, err := dockerClient.NodeInspect(ctx, nodeID, dockerclient.NodeInspectOptions{})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to inspect swarm node")
+		}
+		node := nodeResult.Node
+
+		spec := node.Spec
+		spec.Labels = mergeNodeLabelsInternal(node.Spec.Labels, set, remove)
+		_, err = dockerClient.NodeUpdate(ctx, node.ID, dockerclient.NodeUpdateOptions{
+			Version: node.Version,
+			Spec:    spec,
+		})
+		if err == nil {
+			return &swarmtypes.NodeLabelsResponse{NodeID: node.ID, Labels: spec.Labels}, nil
+		}
+		if attempt >= nodeLabelPatchAttempts || !isSwarmVersionConflictInternal(err) {
+			return nil, errors.WrapIf(err, "failed to update swarm node labels")
+		}
+		slog.DebugContext(ctx, "Swarm node changed while patching labels; retrying", "nodeID", nodeID, "attempt", attempt)
+	}
+}
+
+// mergeNodeLabelsInternal returns a copy of current with set applied and the
+// remove keys deleted. The result is never nil so it serializes as an object.
+func mergeNodeLabelsInternal(current, set map[string]string, remove []string) map[string]string {
+	merged := make(map[string]string, len(current)+len(set))
+	maps.Copy(merged, current)
+	maps.Copy(merged, set)
+	for _, key := range remove {
+		delete(merged, key)
+	}
+	return merged
+}
+
+// isSwarmVersionConflictInternal reports whether a swarm update was rejected
+// because the object's version index moved on since it was inspected.
+func isSwarmVersionConflictInternal(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "update out of sequence")
+}
+
+// DrainNode sets a node's availability to drain and waits up to timeout for its tasks to be
+// rescheduled. A timeout is not an error: the node stays drained and the response reports
+// how many tasks are still active.
+func (s *SwarmService) DrainNode(ctx context.Context, environmentID, nodeID string, timeout time.Duration) (*swarmtypes.NodeDrainResponse, error) {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	nodeResult, err := dockerClient.NodeInspect(ctx, nodeID, dockerclient.NodeInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect swarm node")
+	}
+	node := nodeResult.Node
+
+	activeBefore, err := countActiveNodeTasksInternal(ctx, dockerClient, node.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Spec.Availability != swarm.NodeAvailabilityDrain {
+		spec := node.Spec
+		spec.Availability = swarm.NodeAvailabilityDrain
+		if _, err := dockerClient.NodeUpdate(ctx, node.ID, dockerclient.NodeUpdateOptions{
+			Version: node.Version,
+			Spec:    spec,
+		}); err != nil {
+			return nil, errors.WrapIf(err, "failed to drain swarm node")
+		}
+	}
+
+	remaining, err := s.waitForNodeTasksDrainedInternal(ctx, dockerClient, node.ID, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &swarmtypes.NodeDrainResponse{
+		NodeID:         node.ID,
+		TasksMoved:     max(activeBefore-remaining, 0),
+		RemainingTasks: remaining,
+		Drained:        remaining == 0,
+	}, nil
+}
+
+func (s *SwarmService) RemoveNode(ctx context.Context, environmentID, nodeID string, force bool) error {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	if _, err := dockerClient.NodeRemove(ctx, nodeID, dockerclient.NodeRemoveOptions{Force: force}); err != nil {
+		return errors.WrapIf(err, "failed to remove swarm node")
+	}
+
+	return nil
+}
+
+func (s *SwarmService) PromoteNode(ctx context.Context, environmentID, nodeID string) error {
+	return s.UpdateNode(ctx, environmentID, nodeID, swarmtypes.NodeUpdateRequest{Role: new(swarm.NodeRoleManager)})
+}
+
+func (s *SwarmService) DemoteNode(ctx context.Context, environmentID, nodeID string) error {
+	return s.UpdateNode(ctx, environmentID, nodeID, swarmtypes.NodeUpdateRequest{Role: new(swarm.NodeRoleWorker)})
+}
+
+func (s *SwarmService) ListNodeTasksPaginated(ctx context.Context, environmentID, nodeID string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	filters := make(dockerclient.Filters)
+	filters.Add("node", nodeID)
+	return s.listTasksPaginatedWithFiltersInternal(ctx, environmentID, filters, params)
+}
+
+func (s *SwarmService) GetStack(ctx context.Context, environmentID, stackName string) (*swarmtypes.StackInspect, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	services, err := s.listStackServicesRawInternal(ctx, dockerClient, stackName)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		persisted, err := s.getPersistedStackSourceSummaryInternal(ctx, environmentID, stackName)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return nil, cerrdefs.ErrNotFound
+			}
+			return nil, err
+		}
+
+		return &swarmtypes.StackInspect{
+			Name:      persisted.Name,
+			Namespace: persisted.Namespace,
+			Services:  persisted.Services,
+			CreatedAt: persisted.CreatedAt,
+			UpdatedAt: persisted.UpdatedAt,
+		}, nil
+	}
+
+	createdAt := services[0].CreatedAt
+	updatedAt := services[0].UpdatedAt
+	for _, service := range services[1:] {
+		if service.CreatedAt.Before(createdAt) {
+			createdAt = service.CreatedAt
+		}
+		if service.UpdatedAt.After(updatedAt) {
+			updatedAt = service.UpdatedAt
+		}
+	}
+
+	return &swarmtypes.StackInspect{
+		Name:      stackName,
+		Namespace: stackName,
+		Services:  len(services),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func (s *SwarmService) GetStackSource(ctx context.Context, environmentID, stackName string) (*swarmtypes.StackSource, error) {
+	stackName = strings.TrimSpace(stackName)
+	if stackName == "" {
+		return nil, errors.New("stack name is required")
+	}
+
+	_, stackSourceDir, err := s.resolveSwarmStackSourceDirInternal(ctx, environmentID, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	composeContent, err := os.ReadFile(filepath.Join(stackSourceDir, swarmStackComposeFilename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, cerrdefs.ErrNotFound
+		}
+		return nil, errors.WrapIf(err, "failed to read swarm stack compose source")
+	}
+
+	overrideContent := ""
+	overrideBytes, err := os.ReadFile(filepath.Join(stackSourceDir, swarmStackOverrideFilename))
+	if err == nil {
+		overrideContent = string(overrideBytes)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, errors.WrapIf(err, "failed to read swarm stack override source")
+	}
+
+	envContent := ""
+	envBytes, err := os.ReadFile(filepath.Join(stackSourceDir, swarmStackEnvFilename))
+	if err == nil {
+		envContent = string(envBytes)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, errors.WrapIf(err, "failed to read swarm stack env source")
+	}
+
+	var files []swarmtypes.SyncFile
 	root, err := os.OpenRoot(stackSourceDir)
 	if err == nil {
 		defer func() { _ = root.Close() }()
@@ -1779,6 +3006,9 @@ func (s *SwarmService) GetStackSource(ctx context.Context, environmentID, stackN
 			if walkErr != nil {
 				return walkErr
 			}
+			if d.IsDir() && path == swarmStackHistoryDirname {
+				return fs.SkipDir
+			}
 			if path == "." || d.IsDir() {
 				return nil
 			}
@@ -1825,47 +3055,245 @@ func (s *SwarmService) UpdateStackSource(ctx context.Context, environmentID, sta
 		return nil, err
 	}
 
-	return &swarmtypes.StackSource{
-		Name:            stackName,
-		ComposeContent:  req.ComposeContent,
-		OverrideContent: req.OverrideContent,
-		EnvContent:      req.EnvContent,
-		Files:           req.Files,
-	}, nil
+	return &swarmtypes.StackSource{
+		Name:            stackName,
+		ComposeContent:  req.ComposeContent,
+		OverrideContent: req.OverrideContent,
+		EnvContent:      req.EnvContent,
+		Files:           req.Files,
+	}, nil
+}
+
+// GetStackSourceHistory lists the saved source snapshots of a stack, newest first.
+func (s *SwarmService) GetStackSourceHistory(ctx context.Context, environmentID, stackName string) ([]swarmtypes.StackSourceVersion, error) {
+	stackName = strings.TrimSpace(stackName)
+	if stackName == "" {
+		return nil, errors.New("stack name is required")
+	}
+
+	_, stackSourceDir, err := s.resolveSwarmStackSourceDirInternal(ctx, environmentID, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	return listStackSourceSnapshotsInternal(stackSourceDir)
+}
+
+// GetStackSourceDiff returns a unified diff of the stack source between two versions.
+// Either version may be "current" to compare against the live source.
+func (s *SwarmService) GetStackSourceDiff(ctx context.Context, environmentID, stackName, fromVersion, toVersion string) (*swarmtypes.StackSourceDiff, error) {
+	stackName = strings.TrimSpace(stackName)
+	if stackName == "" {
+		return nil, errors.New("stack name is required")
+	}
+
+	fromVersion = strings.TrimSpace(fromVersion)
+	toVersion = strings.TrimSpace(toVersion)
+	if toVersion == "" {
+		toVersion = swarmStackCurrentVersion
+	}
+	if fromVersion == "" {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "from version is required")
+	}
+
+	_, stackSourceDir, err := s.resolveSwarmStackSourceDirInternal(ctx, environmentID, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	fromFiles, err := readStackSourceVersionInternal(stackSourceDir, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := readStackSourceVersionInternal(stackSourceDir, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff strings.Builder
+	for _, filename := range swarmStackSourceFilenames {
+		fileDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(fromFiles[filename]),
+			B:        difflib.SplitLines(toFiles[filename]),
+			FromFile: filename + "@" + fromVersion,
+			ToFile:   filename + "@" + toVersion,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to diff %s", filename)
+		}
+		diff.WriteString(fileDiff)
+	}
+
+	return &swarmtypes.StackSourceDiff{From: fromVersion, To: toVersion, Diff: diff.String()}, nil
+}
+
+func (s *SwarmService) listPersistedStackSourcesInternal(ctx context.Context, environmentID string) (map[string]swarmtypes.StackSummary, error) {
+	_, environmentDir, err := s.resolveSwarmStackSourceEnvironmentDirInternal(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(environmentDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]swarmtypes.StackSummary{}, nil
+		}
+		return nil, errors.WrapIf(err, "failed to list swarm stack source directories")
+	}
+
+	stacks := make(map[string]swarmtypes.StackSummary, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		summary, err := s.buildPersistedStackSourceSummaryInternal(filepath.Join(environmentDir, entry.Name()), entry.Name())
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		stacks[summary.Name] = *summary
+	}
+
+	return stacks, nil
+}
+
+// ListOrphanedStackSources returns the saved stack sources of an environment
+// that have no stack deployed under the same name, for example after a stack
+// was removed outside Arcane.
+func (s *SwarmService) ListOrphanedStackSources(ctx context.Context, environmentID string) ([]swarmtypes.OrphanedStackSource, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	servicesResult, err := dockerClient.ServiceList(ctx, dockerclient.ServiceListOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list swarm services")
+	}
+	deployed := make(map[string]struct{})
+	for _, service := range servicesResult.Items {
+		if stackName := service.Spec.Labels[swarmtypes.StackNamespaceLabel]; stackName != "" {
+			// Source directories are stored under the sanitized stack name.
+			deployed[appfs.SanitizeProjectName(stackName)] = struct{}{}
+		}
+	}
+
+	_, environmentDir, err := s.resolveSwarmStackSourceEnvironmentDirInternal(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	persisted, err := s.listPersistedStackSourcesInternal(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	orphaned := make([]swarmtypes.OrphanedStackSource, 0)
+	for name, summary := range persisted {
+		if _, ok := deployed[name]; ok {
+			continue
+		}
+		orphaned = append(orphaned, swarmtypes.OrphanedStackSource{
+			Name:      name,
+			Path:      filepath.Join(environmentDir, name),
+			UpdatedAt: summary.UpdatedAt,
+		})
+	}
+	slices.SortFunc(orphaned, func(a, b swarmtypes.OrphanedStackSource) int { return strings.Compare(a.Name, b.Name) })
+
+	return orphaned, nil
 }
 
-func (s *SwarmService) listPersistedStackSourcesInternal(ctx context.Context, environmentID string) (map[string]swarmtypes.StackSummary, error) {
-	_, environmentDir, err := s.resolveSwarmStackSourceEnvironmentDirInternal(ctx, environmentID)
-	if err != nil {
-		return nil, err
+// MigrateStackSources moves saved stack sources from oldRoot to newRoot after
+// the swarmStackSourcesDirectory setting changes, so existing stacks keep their
+// sources. Both roots are setting values and are resolved like the live setting.
+// Every environment/stack directory is moved on its own; a stack that already
+// exists under newRoot is left in place rather than overwritten. It returns the
+// number of stack sources moved.
+func (s *SwarmService) MigrateStackSources(ctx context.Context, oldRoot, newRoot string) (int, error) {
+	fromRoot := appfs.ResolveConfiguredContainerDirectory(oldRoot, defaultSwarmStackSourceRootDir)
+	toRoot := appfs.ResolveConfiguredContainerDirectory(newRoot, defaultSwarmStackSourceRootDir)
+	if filepath.Clean(fromRoot) == filepath.Clean(toRoot) {
+		return 0, nil
+	}
+	if appfs.IsSafeSubdirectory(fromRoot, toRoot) || appfs.IsSafeSubdirectory(toRoot, fromRoot) {
+		return 0, errors.Errorf("cannot migrate swarm stack sources between nested directories %s and %s", fromRoot, toRoot)
 	}
 
-	entries, err := os.ReadDir(environmentDir)
+	environmentEntries, err := os.ReadDir(fromRoot)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return map[string]swarmtypes.StackSummary{}, nil
+			return 0, nil
 		}
-		return nil, errors.WrapIf(err, "failed to list swarm stack source directories")
+		return 0, errors.WrapIf(err, "failed to list swarm stack source environments")
 	}
 
-	stacks := make(map[string]swarmtypes.StackSummary, len(entries))
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	moved := 0
+	for _, environmentEntry := range environmentEntries {
+		if !environmentEntry.IsDir() {
 			continue
 		}
-
-		summary, err := s.buildPersistedStackSourceSummaryInternal(filepath.Join(environmentDir, entry.Name()), entry.Name())
+		stackEntries, err := os.ReadDir(filepath.Join(fromRoot, environmentEntry.Name()))
 		if err != nil {
-			if cerrdefs.IsNotFound(err) {
+			return moved, errors.WrapIff(err, "failed to list swarm stack sources for environment %s", environmentEntry.Name())
+		}
+
+		for _, stackEntry := range stackEntries {
+			if !stackEntry.IsDir() {
 				continue
 			}
-			return nil, err
+			source := filepath.Join(fromRoot, environmentEntry.Name(), stackEntry.Name())
+			destination := filepath.Join(toRoot, environmentEntry.Name(), stackEntry.Name())
+			if !appfs.IsSafeSubdirectory(fromRoot, source) || !appfs.IsSafeSubdirectory(toRoot, destination) {
+				slog.WarnContext(ctx, "skipping swarm stack source outside storage root", "source", source)
+				continue
+			}
+
+			if _, err := os.Lstat(destination); err == nil {
+				slog.WarnContext(ctx, "swarm stack source already exists in new directory, keeping old copy", "source", source, "destination", destination)
+				continue
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return moved, errors.WrapIff(err, "failed to check swarm stack source destination %s", destination)
+			}
+
+			if err := moveStackSourceDirInternal(source, destination); err != nil {
+				return moved, errors.WrapIff(err, "failed to move swarm stack source %s", source)
+			}
+			moved++
 		}
+	}
 
-		stacks[summary.Name] = *summary
+	return moved, nil
+}
+
+// moveStackSourceDirInternal renames source to destination, falling back to a
+// copy and delete when the two roots are on different filesystems.
+func moveStackSourceDirInternal(source, destination string) error {
+	if err := os.MkdirAll(filepath.Dir(destination), common.DirPerm); err != nil {
+		return err
 	}
 
-	return stacks, nil
+	err := os.Rename(source, destination)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := os.MkdirAll(destination, common.DirPerm); err != nil {
+		return err
+	}
+	if err := appfs.CopyDirectoryContents(source, destination); err != nil {
+		_ = os.RemoveAll(destination)
+		return err
+	}
+	return os.RemoveAll(source)
 }
 
 func (s *SwarmService) getPersistedStackSourceSummaryInternal(ctx context.Context, environmentID, stackName string) (*swarmtypes.StackSummary, error) {
@@ -1911,8 +3339,47 @@ func (s *SwarmService) buildPersistedStackSourceSummaryInternal(stackSourceDir,
 	}, nil
 }
 
+// PlanStackRemoval reports what RemoveStack would delete for stackName without
+// changing anything. Stack-labeled networks that services outside the stack are
+// attached to, and the ingress network, are listed as kept rather than removed.
+func (s *SwarmService) PlanStackRemoval(ctx context.Context, environmentID, stackName string) (*swarmtypes.StackRemovalPlan, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	stackName = strings.TrimSpace(stackName)
+	if stackName == "" {
+		return nil, errors.New("stack name is required")
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	services, err := s.listStackServicesRawInternal(ctx, dockerClient, stackName)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		// Removing a stack without services only deletes its saved source.
+		if _, err := s.getPersistedStackSourceSummaryInternal(ctx, environmentID, stackName); err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return nil, cerrdefs.ErrNotFound
+			}
+			return nil, err
+		}
+		plan := buildStackRemovalPlanInternal(stackName, nil, nil, nil, nil, nil)
+		return &plan, nil
+	}
+
+	return s.planStackRemovalInternal(ctx, dockerClient, stackName, services)
+}
+
 func (s *SwarmService) RemoveStack(ctx context.Context, environmentID, stackName string) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+	defer s.invalidateSwarmMetadataInternal(environmentID)
+
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
@@ -1921,7 +3388,7 @@ func (s *SwarmService) RemoveStack(ctx context.Context, environmentID, stackName
 		return errors.New("stack name is required")
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -1934,19 +3401,35 @@ func (s *SwarmService) RemoveStack(ctx context.Context, environmentID, stackName
 		return s.removeSourceOnlyStackInternal(ctx, environmentID, stackName)
 	}
 
-	if err := s.removeStackServicesInternal(ctx, dockerClient, services); err != nil {
+	// The plan has to be built before the services go away, since shared
+	// networks are detected from the attachments of services outside the stack.
+	plan, err := s.planStackRemovalInternal(ctx, dockerClient, stackName, services)
+	if err != nil {
 		return err
 	}
+	for _, kept := range plan.KeptNetworks {
+		if kept.Reason == swarmtypes.StackNetworkKeptShared {
+			slog.InfoContext(ctx, "keeping swarm stack network shared with other services", "stackName", stackName, "network", kept.Name, "usedBy", kept.UsedBy)
+		}
+	}
 
-	stackLabel := fmt.Sprintf("%s=%s", swarmtypes.StackNamespaceLabel, stackName)
-	if err := s.removeStackConfigsInternal(ctx, dockerClient, stackLabel); err != nil {
+	if err := s.removeStackServicesInternal(ctx, dockerClient, services); err != nil {
 		return err
 	}
-	if err := s.removeStackSecretsInternal(ctx, dockerClient, stackLabel); err != nil {
-		return err
+	for _, cfg := range plan.Configs {
+		if _, err := dockerClient.ConfigRemove(ctx, cfg.ID, dockerclient.ConfigRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
+			return errors.WrapIff(err, "failed to remove stack config %s", cfg.Name)
+		}
 	}
-	if err := s.removeStackNetworksInternal(ctx, dockerClient, stackLabel); err != nil {
-		return err
+	for _, secret := range plan.Secrets {
+		if _, err := dockerClient.SecretRemove(ctx, secret.ID, dockerclient.SecretRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
+			return errors.WrapIff(err, "failed to remove stack secret %s", secret.Name)
+		}
+	}
+	for _, network := range plan.Networks {
+		if _, err := dockerClient.NetworkRemove(ctx, network.ID, dockerclient.NetworkRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
+			return errors.WrapIff(err, "failed to remove stack network %s", network.Name)
+		}
 	}
 
 	if err := s.deleteStackSourceInternal(ctx, environmentID, stackName); err != nil {
@@ -1983,60 +3466,105 @@ func (s *SwarmService) removeStackServicesInternal(ctx context.Context, dockerCl
 	return nil
 }
 
-func (s *SwarmService) removeStackConfigsInternal(ctx context.Context, dockerClient *dockerclient.Client, stackLabel string) error {
-	configFilter := make(dockerclient.Filters).Add("label", stackLabel)
-	configsResult, err := dockerClient.ConfigList(ctx, dockerclient.ConfigListOptions{Filters: configFilter})
+// planStackRemovalInternal lists the stack-labeled configs, secrets and networks
+// plus every service in the swarm, and builds the removal plan from them.
+func (s *SwarmService) planStackRemovalInternal(ctx context.Context, dockerClient *dockerclient.Client, stackName string, services []swarm.Service) (*swarmtypes.StackRemovalPlan, error) {
+	stackFilter := make(dockerclient.Filters).Add("label", fmt.Sprintf("%s=%s", swarmtypes.StackNamespaceLabel, stackName))
+
+	configsResult, err := dockerClient.ConfigList(ctx, dockerclient.ConfigListOptions{Filters: stackFilter})
 	if err != nil {
-		return errors.WrapIf(err, "failed to list stack configs")
+		return nil, errors.WrapIf(err, "failed to list stack configs")
 	}
-	for _, cfg := range configsResult.Items {
-		if _, err := dockerClient.ConfigRemove(ctx, cfg.ID, dockerclient.ConfigRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
-			return errors.WrapIff(err, "failed to remove stack config %s", cfg.Spec.Name)
-		}
+	secretsResult, err := dockerClient.SecretList(ctx, dockerclient.SecretListOptions{Filters: stackFilter})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list stack secrets")
 	}
-
-	return nil
-}
-
-func (s *SwarmService) removeStackSecretsInternal(ctx context.Context, dockerClient *dockerclient.Client, stackLabel string) error {
-	secretFilter := make(dockerclient.Filters).Add("label", stackLabel)
-	secretsResult, err := dockerClient.SecretList(ctx, dockerclient.SecretListOptions{Filters: secretFilter})
+	networksResult, err := dockerClient.NetworkList(ctx, dockerclient.NetworkListOptions{Filters: stackFilter})
 	if err != nil {
-		return errors.WrapIf(err, "failed to list stack secrets")
+		return nil, errors.WrapIf(err, "failed to list stack networks")
 	}
-	for _, secret := range secretsResult.Items {
-		if _, err := dockerClient.SecretRemove(ctx, secret.ID, dockerclient.SecretRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
-			return errors.WrapIff(err, "failed to remove stack secret %s", secret.Spec.Name)
-		}
+	allServicesResult, err := dockerClient.ServiceList(ctx, dockerclient.ServiceListOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list swarm services")
 	}
 
-	return nil
+	plan := buildStackRemovalPlanInternal(stackName, services, allServicesResult.Items, configsResult.Items, secretsResult.Items, networksResult.Items)
+	return &plan, nil
 }
 
-func (s *SwarmService) removeStackNetworksInternal(ctx context.Context, dockerClient *dockerclient.Client, stackLabel string) error {
-	networkFilter := make(dockerclient.Filters).Add("label", stackLabel)
-	networksResult, err := dockerClient.NetworkList(ctx, dockerclient.NetworkListOptions{Filters: networkFilter})
-	if err != nil {
-		return errors.WrapIf(err, "failed to list stack networks")
+// buildStackRemovalPlanInternal sorts the stack's resources into what will be
+// removed and which networks are kept. A network is shared when a service that
+// is not part of the stack attaches to it by ID or name.
+func buildStackRemovalPlanInternal(stackName string, stackServices, allServices []swarm.Service, configs []swarm.Config, secrets []swarm.Secret, networks []networktypes.Summary) swarmtypes.StackRemovalPlan {
+	plan := swarmtypes.StackRemovalPlan{
+		StackName:    stackName,
+		Services:     make([]swarmtypes.StackRemovalResource, 0, len(stackServices)),
+		Configs:      make([]swarmtypes.StackRemovalResource, 0, len(configs)),
+		Secrets:      make([]swarmtypes.StackRemovalResource, 0, len(secrets)),
+		Networks:     make([]swarmtypes.StackRemovalResource, 0, len(networks)),
+		KeptNetworks: []swarmtypes.StackKeptNetwork{},
+	}
+
+	inStack := make(map[string]struct{}, len(stackServices))
+	for _, service := range stackServices {
+		inStack[service.ID] = struct{}{}
+		plan.Services = append(plan.Services, swarmtypes.StackRemovalResource{ID: service.ID, Name: service.Spec.Name})
+	}
+	for _, cfg := range configs {
+		plan.Configs = append(plan.Configs, swarmtypes.StackRemovalResource{ID: cfg.ID, Name: cfg.Spec.Name})
+	}
+	for _, secret := range secrets {
+		plan.Secrets = append(plan.Secrets, swarmtypes.StackRemovalResource{ID: secret.ID, Name: secret.Spec.Name})
+	}
+
+	// Attachment targets of services outside the stack, mapped to their names.
+	externalUsers := make(map[string][]string)
+	for _, service := range allServices {
+		if _, ok := inStack[service.ID]; ok {
+			continue
+		}
+		targets := make(map[string]struct{})
+		for _, attachment := range service.Spec.TaskTemplate.Networks {
+			targets[attachment.Target] = struct{}{}
+		}
+		for _, vip := range service.Endpoint.VirtualIPs {
+			targets[vip.NetworkID] = struct{}{}
+		}
+		for target := range targets {
+			if target != "" {
+				externalUsers[target] = append(externalUsers[target], service.Spec.Name)
+			}
+		}
 	}
-	for _, network := range networksResult.Items {
+
+	for _, network := range networks {
+		resource := swarmtypes.StackRemovalResource{ID: network.ID, Name: network.Name}
 		if network.Ingress {
+			plan.KeptNetworks = append(plan.KeptNetworks, swarmtypes.StackKeptNetwork{StackRemovalResource: resource, Reason: swarmtypes.StackNetworkKeptIngress})
 			continue
 		}
-		if _, err := dockerClient.NetworkRemove(ctx, network.ID, dockerclient.NetworkRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
-			return errors.WrapIff(err, "failed to remove stack network %s", network.Name)
+		usedBy := append(slices.Clone(externalUsers[network.ID]), externalUsers[network.Name]...)
+		if len(usedBy) > 0 {
+			slices.Sort(usedBy)
+			plan.KeptNetworks = append(plan.KeptNetworks, swarmtypes.StackKeptNetwork{
+				StackRemovalResource: resource,
+				Reason:               swarmtypes.StackNetworkKeptShared,
+				UsedBy:               slices.Compact(usedBy),
+			})
+			continue
 		}
+		plan.Networks = append(plan.Networks, resource)
 	}
 
-	return nil
+	return plan
 }
 
-func (s *SwarmService) ListStackServicesPaginated(ctx context.Context, stackName string, params pagination.QueryParams) ([]swarmtypes.ServiceSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) ListStackServicesPaginated(ctx context.Context, environmentID, stackName string, params pagination.QueryParams) ([]swarmtypes.ServiceSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -2054,18 +3582,18 @@ func (s *SwarmService) ListStackServicesPaginated(ctx context.Context, stackName
 		return nil, pagination.Response{}, err
 	}
 
-	config := s.buildServicePaginationConfigInternal()
+	config := s.ServicePaginationConfig()
 	result := pagination.SearchOrderAndPaginate(summaries, params, config)
 	paginationResp := buildPaginationResponseInternal(result, params)
 	return result.Items, paginationResp, nil
 }
 
-func (s *SwarmService) ListStackTasksPaginated(ctx context.Context, stackName string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) ListStackTasksPaginated(ctx context.Context, environmentID, stackName string, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -2083,11 +3611,11 @@ func (s *SwarmService) ListStackTasksPaginated(ctx context.Context, stackName st
 		filters.Add("service", service.ID)
 	}
 
-	return s.listTasksPaginatedWithFiltersInternal(ctx, filters, params)
+	return s.listTasksPaginatedWithFiltersInternal(ctx, environmentID, filters, params)
 }
 
-func (s *SwarmService) RenderStackConfig(ctx context.Context, req swarmtypes.StackRenderConfigRequest) (*swarmtypes.StackRenderConfigResponse, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) RenderStackConfig(ctx context.Context, environmentID string, req swarmtypes.StackRenderConfigRequest) (*swarmtypes.StackRenderConfigResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
@@ -2115,34 +3643,39 @@ func (s *SwarmService) RenderStackConfig(ctx context.Context, req swarmtypes.Sta
 	}, nil
 }
 
-func (s *SwarmService) ListConfigs(ctx context.Context) ([]swarmtypes.ConfigSummary, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return nil, err
+func (s *SwarmService) ListConfigsPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]swarmtypes.ConfigSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, pagination.Response{}, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to connect to Docker")
+		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
 	configsResult, err := dockerClient.ConfigList(ctx, dockerclient.ConfigListOptions{})
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to list swarm configs")
+		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list swarm configs")
 	}
 
 	items := make([]swarmtypes.ConfigSummary, 0, len(configsResult.Items))
 	for _, cfg := range configsResult.Items {
 		items = append(items, swarmtypes.NewConfigSummary(cfg))
 	}
-	return items, nil
+
+	config := s.ConfigPaginationConfig()
+	result := pagination.SearchOrderAndPaginate(items, params, config)
+	paginationResp := buildPaginationResponseInternal(result, params)
+
+	return result.Items, paginationResp, nil
 }
 
-func (s *SwarmService) GetConfig(ctx context.Context, configID string) (*swarmtypes.ConfigSummary, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) GetConfig(ctx context.Context, environmentID, configID string) (*swarmtypes.ConfigSummary, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -2155,8 +3688,20 @@ func (s *SwarmService) GetConfig(ctx context.Context, configID string) (*swarmty
 	return new(swarmtypes.NewConfigSummary(cfgResult.Config)), nil
 }
 
-func (s *SwarmService) CreateConfig(ctx context.Context, req swarmtypes.ConfigCreateRequest) (*swarmtypes.ConfigSummary, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+// GetConfigUsage lists the services whose task template mounts configID, with
+// the target file and mode of each mount.
+func (s *SwarmService) GetConfigUsage(ctx context.Context, environmentID, configID string) ([]swarmtypes.ServiceMountUsage, error) {
+	return s.mountUsageInternal(ctx, environmentID, func(ctx context.Context, dockerClient *dockerclient.Client) (string, error) {
+		cfgResult, err := dockerClient.ConfigInspect(ctx, configID, dockerclient.ConfigInspectOptions{})
+		if err != nil {
+			return "", errors.WrapIf(err, "failed to inspect swarm config")
+		}
+		return cfgResult.Config.ID, nil
+	}, configMountRefsInternal)
+}
+
+func (s *SwarmService) CreateConfig(ctx context.Context, environmentID string, req swarmtypes.ConfigCreateRequest) (*swarmtypes.ConfigSummary, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
 
@@ -2165,7 +3710,7 @@ func (s *SwarmService) CreateConfig(ctx context.Context, req swarmtypes.ConfigCr
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -2175,120 +3720,418 @@ func (s *SwarmService) CreateConfig(ctx context.Context, req swarmtypes.ConfigCr
 		return nil, errors.WrapIf(err, "failed to create swarm config")
 	}
 
-	return s.GetConfig(ctx, createResult.ID)
+	return s.GetConfig(ctx, environmentID, createResult.ID)
 }
 
 func (s *SwarmService) UpdateConfig(ctx context.Context, configID string, req swarmtypes.ConfigUpdateRequest) error {
 	_ = configID
 	_ = req
-	return common.Classify(common.ErrSwarmConfigImmutable, errors.New("Swarm configs are immutable; create a new config and update services to use it"))
+	return common.Classify(common.ErrSwarmConfigImmutable, errors.New("Swarm configs are immutable; create a new config and update services to use it"))
+}
+
+func (s *SwarmService) RemoveConfig(ctx context.Context, environmentID, configID string) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	if _, err := dockerClient.ConfigRemove(ctx, configID, dockerclient.ConfigRemoveOptions{}); err != nil {
+		return errors.WrapIf(err, "failed to remove swarm config")
+	}
+
+	return nil
+}
+
+func (s *SwarmService) ListSecretsPaginated(ctx context.Context, environmentID string, params pagination.QueryParams) ([]swarmtypes.SecretSummary, pagination.Response, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	secretsResult, err := dockerClient.SecretList(ctx, dockerclient.SecretListOptions{})
+	if err != nil {
+		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list swarm secrets")
+	}
+
+	items := make([]swarmtypes.SecretSummary, 0, len(secretsResult.Items))
+	for _, secret := range secretsResult.Items {
+		items = append(items, swarmtypes.NewSecretSummary(secret))
+	}
+
+	config := s.SecretPaginationConfig()
+	result := pagination.SearchOrderAndPaginate(items, params, config)
+	paginationResp := buildPaginationResponseInternal(result, params)
+
+	return result.Items, paginationResp, nil
+}
+
+func (s *SwarmService) GetSecret(ctx context.Context, environmentID, secretID string) (*swarmtypes.SecretSummary, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	secretResult, err := dockerClient.SecretInspect(ctx, secretID, dockerclient.SecretInspectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to inspect swarm secret")
+	}
+
+	return new(swarmtypes.NewSecretSummary(secretResult.Secret)), nil
+}
+
+// GetSecretUsage lists the services whose task template mounts secretID, with
+// the target file and mode of each mount.
+func (s *SwarmService) GetSecretUsage(ctx context.Context, environmentID, secretID string) ([]swarmtypes.ServiceMountUsage, error) {
+	return s.mountUsageInternal(ctx, environmentID, func(ctx context.Context, dockerClient *dockerclient.Client) (string, error) {
+		secretResult, err := dockerClient.SecretInspect(ctx, secretID, dockerclient.SecretInspectOptions{})
+		if err != nil {
+			return "", errors.WrapIf(err, "failed to inspect swarm secret")
+		}
+		return secretResult.Secret.ID, nil
+	}, secretMountRefsInternal)
+}
+
+// mountUsageInternal resolves the canonical object ID with inspect and then
+// reports the services whose task templates mount it, reading each template's
+// references with refs.
+func (s *SwarmService) mountUsageInternal(ctx context.Context, environmentID string, inspect func(context.Context, *dockerclient.Client) (string, error), refs func(*swarm.ContainerSpec) []swarmMountRefInternal) ([]swarmtypes.ServiceMountUsage, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	objectID, err := inspect(ctx, dockerClient)
+	if err != nil {
+		return nil, err
+	}
+
+	servicesResult, err := dockerClient.ServiceList(ctx, dockerclient.ServiceListOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list swarm services")
+	}
+
+	return serviceMountUsageInternal(servicesResult.Items, objectID, refs), nil
+}
+
+// swarmMountRefInternal is the part of a config or secret reference that the
+// usage view reports. Both reference kinds share the same file target shape.
+type swarmMountRefInternal struct {
+	id   string
+	file *swarm.ConfigReferenceFileTarget
+}
+
+func configMountRefsInternal(spec *swarm.ContainerSpec) []swarmMountRefInternal {
+	refs := make([]swarmMountRefInternal, 0, len(spec.Configs))
+	for _, ref := range spec.Configs {
+		if ref != nil {
+			refs = append(refs, swarmMountRefInternal{id: ref.ConfigID, file: ref.File})
+		}
+	}
+	return refs
+}
+
+func secretMountRefsInternal(spec *swarm.ContainerSpec) []swarmMountRefInternal {
+	refs := make([]swarmMountRefInternal, 0, len(spec.Secrets))
+	for _, ref := range spec.Secrets {
+		if ref != nil {
+			refs = append(refs, swarmMountRefInternal{id: ref.SecretID, file: (*swarm.ConfigReferenceFileTarget)(ref.File)})
+		}
+	}
+	return refs
+}
+
+func serviceMountUsageInternal(services []swarm.Service, objectID string, refs func(*swarm.ContainerSpec) []swarmMountRefInternal) []swarmtypes.ServiceMountUsage {
+	usage := []swarmtypes.ServiceMountUsage{}
+	for _, service := range services {
+		if service.Spec.TaskTemplate.ContainerSpec == nil {
+			continue
+		}
+		for _, ref := range refs(service.Spec.TaskTemplate.ContainerSpec) {
+			if ref.id != objectID {
+				continue
+			}
+			entry := swarmtypes.ServiceMountUsage{ServiceID: service.ID, ServiceName: service.Spec.Name}
+			if ref.file != nil {
+				entry.Target, entry.UID, entry.GID, entry.Mode = ref.file.Name, ref.file.UID, ref.file.GID, uint32(ref.file.Mode)
+			}
+			usage = append(usage, entry)
+		}
+	}
+	return usage
+}
+
+func (s *SwarmService) CreateSecret(ctx context.Context, environmentID string, req swarmtypes.SecretCreateRequest) (*swarmtypes.SecretSummary, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	spec, err := decodeSecretSpecInternal(req.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	createResult, err := dockerClient.SecretCreate(ctx, dockerclient.SecretCreateOptions{Spec: spec})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create swarm secret")
+	}
+
+	return s.GetSecret(ctx, environmentID, createResult.ID)
+}
+
+func (s *SwarmService) UpdateSecret(ctx context.Context, secretID string, req swarmtypes.SecretUpdateRequest) error {
+	_ = secretID
+	_ = req
+	return common.Classify(common.ErrSwarmSecretImmutable, errors.New("Swarm secrets are immutable; create a new secret and update services to use it"))
 }
 
-func (s *SwarmService) RemoveConfig(ctx context.Context, configID string) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+func (s *SwarmService) RemoveSecret(ctx context.Context, environmentID, secretID string) error {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	if _, err := dockerClient.ConfigRemove(ctx, configID, dockerclient.ConfigRemoveOptions{}); err != nil {
-		return errors.WrapIf(err, "failed to remove swarm config")
+	if _, err := dockerClient.SecretRemove(ctx, secretID, dockerclient.SecretRemoveOptions{}); err != nil {
+		return errors.WrapIf(err, "failed to remove swarm secret")
 	}
 
 	return nil
 }
 
-func (s *SwarmService) ListSecrets(ctx context.Context) ([]swarmtypes.SecretSummary, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
+// RotateSecret replaces a swarm secret with a new version holding newData.
+//
+// Secrets are immutable, so this creates a copy of the secret spec under a
+// versioned name (db-password -> db-password-v2 -> db-password-v3), re-points
+// every service referencing the old secret at the new one while keeping each
+// reference's target file, ownership and mode, and then removes the old secret
+// once no live task still mounts it. If tasks are still running against the old
+// secret after secretRotationRemoveTimeout, it is left in place and reported
+// via PreviousSecretRemoved.
+//
+// If any service update fails, the services already re-pointed are moved back
+// to the old secret and the new secret is removed, so a failed rotation leaves
+// the swarm as it found it.
+func (s *SwarmService) RotateSecret(ctx context.Context, environmentID, secretID string, newData []byte) (*swarmtypes.SecretRotateResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
 		return nil, err
 	}
+	if len(newData) == 0 {
+		return nil, errors.New("secret data is required")
+	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
 	}
 
-	secretsResult, err := dockerClient.SecretList(ctx, dockerclient.SecretListOptions{})
+	oldResult, err := dockerClient.SecretInspect(ctx, secretID, dockerclient.SecretInspectOptions{})
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to list swarm secrets")
-	}
-
-	items := make([]swarmtypes.SecretSummary, 0, len(secretsResult.Items))
-	for _, secret := range secretsResult.Items {
-		items = append(items, swarmtypes.NewSecretSummary(secret))
+		return nil, errors.WrapIf(err, "failed to inspect swarm secret")
 	}
-	return items, nil
-}
+	oldSecret := oldResult.Secret
 
-func (s *SwarmService) GetSecret(ctx context.Context, secretID string) (*swarmtypes.SecretSummary, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return nil, err
-	}
+	spec := oldSecret.Spec
+	spec.Name = nextSecretVersionNameInternal(oldSecret.Spec.Name)
+	spec.Labels = maps.Clone(oldSecret.Spec.Labels)
+	spec.Data = newData
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
+	createResult, err := dockerClient.SecretCreate(ctx, dockerclient.SecretCreateOptions{Spec: spec})
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to connect to Docker")
+		return nil, errors.WrapIf(err, "failed to create rotated swarm secret")
 	}
 
-	secretResult, err := dockerClient.SecretInspect(ctx, secretID, dockerclient.SecretInspectOptions{})
+	servicesResult, err := dockerClient.ServiceList(ctx, dockerclient.ServiceListOptions{})
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to inspect swarm secret")
+		s.rollbackSecretRotationInternal(ctx, dockerClient, oldSecret, createResult.ID, nil)
+		return nil, errors.WrapIf(err, "failed to list swarm services")
 	}
 
-	return new(swarmtypes.NewSecretSummary(secretResult.Secret)), nil
-}
+	updated := []swarmtypes.SecretRotationService{}
+	updatedIDs := map[string]struct{}{}
+	for _, service := range servicesResult.Items {
+		if !repointSecretReferencesInternal(&service.Spec, oldSecret.ID, createResult.ID, spec.Name) {
+			continue
+		}
+		if _, err := dockerClient.ServiceUpdate(ctx, service.ID, dockerclient.ServiceUpdateOptions{
+			Version: service.Version,
+			Spec:    service.Spec,
+		}); err != nil {
+			s.rollbackSecretRotationInternal(ctx, dockerClient, oldSecret, createResult.ID, updated)
+			return nil, errors.WrapIff(err, "failed to update swarm service %s", service.Spec.Name)
+		}
+		updated = append(updated, swarmtypes.SecretRotationService{ID: service.ID, Name: service.Spec.Name})
+		updatedIDs[service.ID] = struct{}{}
+	}
 
-func (s *SwarmService) CreateSecret(ctx context.Context, req swarmtypes.SecretCreateRequest) (*swarmtypes.SecretSummary, error) {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return nil, err
+	removed := false
+	if err := s.waitForSecretReleasedInternal(ctx, dockerClient, oldSecret.ID, updatedIDs, secretRotationRemoveTimeout); err != nil {
+		slog.WarnContext(ctx, "leaving previous swarm secret in place after rotation", "secretId", oldSecret.ID, "error", err)
+	} else if _, err := dockerClient.SecretRemove(ctx, oldSecret.ID, dockerclient.SecretRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
+		slog.WarnContext(ctx, "failed to remove previous swarm secret after rotation", "secretId", oldSecret.ID, "error", err)
+	} else {
+		removed = true
 	}
 
-	spec, err := decodeSecretSpecInternal(req.Spec)
+	secret, err := s.GetSecret(ctx, environmentID, createResult.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
-	if err != nil {
-		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	return &swarmtypes.SecretRotateResponse{
+		Secret:                *secret,
+		PreviousSecretID:      oldSecret.ID,
+		PreviousSecretRemoved: removed,
+		UpdatedServices:       updated,
+	}, nil
+}
+
+// rollbackSecretRotationInternal re-points updated back at oldSecret and then
+// removes the rotated secret newID. Failures are logged rather than returned so
+// the caller can report the error that triggered the rollback.
+func (s *SwarmService) rollbackSecretRotationInternal(ctx context.Context, dockerClient *dockerclient.Client, oldSecret swarm.Secret, newID string, updated []swarmtypes.SecretRotationService) {
+	ctx = context.WithoutCancel(ctx)
+
+	restored := true
+	for _, service := range updated {
+		serviceResult, err := dockerClient.ServiceInspect(ctx, service.ID, dockerclient.ServiceInspectOptions{})
+		if err != nil {
+			restored = false
+			slog.ErrorContext(ctx, "failed to inspect swarm service while rolling back secret rotation", "serviceId", service.ID, "secretId", oldSecret.ID, "error", err)
+			continue
+		}
+		current := serviceResult.Service
+		if !repointSecretReferencesInternal(&current.Spec, newID, oldSecret.ID, oldSecret.Spec.Name) {
+			continue
+		}
+		if _, err := dockerClient.ServiceUpdate(ctx, current.ID, dockerclient.ServiceUpdateOptions{
+			Version: current.Version,
+			Spec:    current.Spec,
+		}); err != nil {
+			restored = false
+			slog.ErrorContext(ctx, "failed to restore swarm service after secret rotation failure", "serviceId", service.ID, "secretId", oldSecret.ID, "error", err)
+		}
 	}
 
-	createResult, err := dockerClient.SecretCreate(ctx, dockerclient.SecretCreateOptions{Spec: spec})
-	if err != nil {
-		return nil, errors.WrapIf(err, "failed to create swarm secret")
+	// A service still mounting the rotated secret would block its removal, so
+	// keep it when any service could not be restored.
+	if !restored {
+		return
+	}
+	if _, err := dockerClient.SecretRemove(ctx, newID, dockerclient.SecretRemoveOptions{}); err != nil && !cerrdefs.IsNotFound(err) {
+		slog.WarnContext(ctx, "failed to remove rotated swarm secret after rollback", "secretId", newID, "error", err)
 	}
+}
 
-	return s.GetSecret(ctx, createResult.ID)
+// nextSecretVersionNameInternal bumps a trailing -vN suffix, or appends -v2 to
+// a name that has none.
+func nextSecretVersionNameInternal(name string) string {
+	if idx := strings.LastIndex(name, "-v"); idx > 0 {
+		if version, err := strconv.Atoi(name[idx+2:]); err == nil && version > 0 {
+			return fmt.Sprintf("%s-v%d", name[:idx], version+1)
+		}
+	}
+	return name + "-v2"
 }
 
-func (s *SwarmService) UpdateSecret(ctx context.Context, secretID string, req swarmtypes.SecretUpdateRequest) error {
-	_ = secretID
-	_ = req
-	return common.Classify(common.ErrSwarmSecretImmutable, errors.New("Swarm secrets are immutable; create a new secret and update services to use it"))
+// repointSecretReferencesInternal swaps references to oldID in spec for newID,
+// reporting whether any reference changed.
+func repointSecretReferencesInternal(spec *swarm.ServiceSpec, oldID, newID, newName string) bool {
+	if spec.TaskTemplate.ContainerSpec == nil {
+		return false
+	}
+
+	changed := false
+	for _, ref := range spec.TaskTemplate.ContainerSpec.Secrets {
+		if ref != nil && ref.SecretID == oldID {
+			ref.SecretID = newID
+			ref.SecretName = newName
+			changed = true
+		}
+	}
+	return changed
 }
 
-func (s *SwarmService) RemoveSecret(ctx context.Context, secretID string) error {
-	if err := s.ensureSwarmManagerInternal(ctx); err != nil {
-		return err
+// waitForSecretReleasedInternal polls the tasks of serviceIDs until none that
+// still mount secretID are active, or timeout elapses.
+func (s *SwarmService) waitForSecretReleasedInternal(ctx context.Context, dockerClient *dockerclient.Client, secretID string, serviceIDs map[string]struct{}, timeout time.Duration) error {
+	if len(serviceIDs) == 0 {
+		return nil
 	}
 
-	dockerClient, err := s.dockerService.GetClient(ctx)
-	if err != nil {
-		return errors.WrapIf(err, "failed to connect to Docker")
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	taskFilters := make(dockerclient.Filters)
+	for serviceID := range serviceIDs {
+		taskFilters.Add("service", serviceID)
 	}
 
-	if _, err := dockerClient.SecretRemove(ctx, secretID, dockerclient.SecretRemoveOptions{}); err != nil {
-		return errors.WrapIf(err, "failed to remove swarm secret")
+	for {
+		tasksResult, err := dockerClient.TaskList(waitCtx, dockerclient.TaskListOptions{Filters: taskFilters})
+		if err != nil {
+			return errors.WrapIf(err, "failed to list tasks while waiting for secret rotation")
+		}
+
+		inUse := false
+		for _, task := range tasksResult.Items {
+			if !isTaskTerminalInternal(task.Status.State) && taskReferencesSecretInternal(task, secretID) {
+				inUse = true
+				break
+			}
+		}
+		if !inUse {
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return errors.WrapIf(waitCtx.Err(), "timed out waiting for tasks to release the previous secret")
+		case <-ticker.C:
+		}
 	}
+}
 
-	return nil
+func taskReferencesSecretInternal(task swarm.Task, secretID string) bool {
+	if task.Spec.ContainerSpec == nil {
+		return false
+	}
+	for _, ref := range task.Spec.ContainerSpec.Secrets {
+		if ref != nil && ref.SecretID == secretID {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *SwarmService) listTasksPaginatedWithFiltersInternal(ctx context.Context, filters dockerclient.Filters, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
-	dockerClient, err := s.dockerService.GetClient(ctx)
+func (s *SwarmService) listTasksPaginatedWithFiltersInternal(ctx context.Context, environmentID string, filters dockerclient.Filters, params pagination.QueryParams) ([]swarmtypes.TaskSummary, pagination.Response, error) {
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return nil, pagination.Response{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -2303,13 +4146,13 @@ func (s *SwarmService) listTasksPaginatedWithFiltersInternal(ctx context.Context
 		serviceNameByID[service.ID] = service.Spec.Name
 	}
 
-	nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, environmentID)
 	if err != nil {
-		return nil, pagination.Response{}, errors.WrapIf(err, "failed to list swarm nodes")
+		return nil, pagination.Response{}, err
 	}
 
-	nodeNameByID := make(map[string]string, len(nodesResult.Items))
-	for _, node := range nodesResult.Items {
+	nodeNameByID := make(map[string]string, len(nodes))
+	for _, node := range nodes {
 		nodeNameByID[node.ID] = node.Description.Hostname
 	}
 
@@ -2326,30 +4169,30 @@ func (s *SwarmService) listTasksPaginatedWithFiltersInternal(ctx context.Context
 		items = append(items, swarmtypes.NewTaskSummary(task, serviceNameByID[task.ServiceID], nodeNameByID[task.NodeID]))
 	}
 
-	config := s.buildTaskPaginationConfigInternal()
+	config := s.TaskPaginationConfig()
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 	paginationResp := buildPaginationResponseInternal(result, params)
 	return result.Items, paginationResp, nil
 }
 
 func (s *SwarmService) summarizeServicesInternal(ctx context.Context, dockerClient *dockerclient.Client, services []swarm.Service) ([]swarmtypes.ServiceSummary, error) {
-	nodesResult, err := dockerClient.NodeList(ctx, dockerclient.NodeListOptions{})
+	nodes, err := s.listSwarmNodesCachedInternal(ctx, dockerClient, localEnvironmentID)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to list swarm nodes")
+		return nil, err
 	}
 
-	nodeNameByID := make(map[string]string, len(nodesResult.Items))
-	for _, node := range nodesResult.Items {
+	nodeNameByID := make(map[string]string, len(nodes))
+	for _, node := range nodes {
 		nodeNameByID[node.ID] = node.Description.Hostname
 	}
 
-	networksResult, err := dockerClient.NetworkList(ctx, dockerclient.NetworkListOptions{})
+	networks, err := s.listNetworksCachedInternal(ctx, dockerClient, localEnvironmentID)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to list networks")
+		return nil, err
 	}
 
-	networkNameByID := make(map[string]string, len(networksResult.Items))
-	for _, network := range networksResult.Items {
+	networkNameByID := make(map[string]string, len(networks))
+	for _, network := range networks {
 		networkNameByID[network.ID] = network.Name
 	}
 
@@ -2454,6 +4297,55 @@ func (s *SwarmService) waitForRemovedServiceTasksInternal(ctx context.Context, d
 	}
 }
 
+// waitForNodeTasksDrainedInternal polls the node's tasks until none are active or timeout
+// elapses, returning the number still active when it stops.
+func (s *SwarmService) waitForNodeTasksDrainedInternal(ctx context.Context, dockerClient *dockerclient.Client, nodeID string, timeout time.Duration) (int, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		active, err := countActiveNodeTasksInternal(waitCtx, dockerClient, nodeID)
+		if err != nil {
+			if waitCtx.Err() != nil && ctx.Err() == nil {
+				return countActiveNodeTasksInternal(ctx, dockerClient, nodeID)
+			}
+			return 0, errors.WrapIf(err, "failed to list tasks while waiting for node drain")
+		}
+		if active == 0 {
+			return 0, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return active, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func countActiveNodeTasksInternal(ctx context.Context, dockerClient *dockerclient.Client, nodeID string) (int, error) {
+	taskFilters := make(dockerclient.Filters).Add("node", nodeID)
+	tasksResult, err := dockerClient.TaskList(ctx, dockerclient.TaskListOptions{Filters: taskFilters})
+	if err != nil {
+		return 0, errors.WrapIf(err, "failed to list swarm node tasks")
+	}
+
+	active := 0
+	for _, task := range tasksResult.Items {
+		if !isTaskTerminalInternal(task.Status.State) {
+			active++
+		}
+	}
+
+	return active, nil
+}
+
 func isTaskTerminalInternal(state swarm.TaskState) bool {
 	switch state {
 	case swarm.TaskStateComplete,
@@ -2580,11 +4472,39 @@ func (s *SwarmService) upsertStackSourceInternal(ctx context.Context, environmen
 		}
 	}
 
-	// Write additional files
+	if err := writeStackSourceFilesInternal(stackSourceDir, files); err != nil {
+		return err
+	}
+
+	// History is best-effort: a failed snapshot must not block a deploy.
+	limit := defaultSwarmStackSourceHistoryLimit
+	if s.settingsService != nil {
+		limit = s.settingsService.GetIntSetting(ctx, "swarmStackSourceHistoryLimit", defaultSwarmStackSourceHistoryLimit)
+	}
+	current := map[string]string{
+		swarmStackComposeFilename:  composeContent,
+		swarmStackOverrideFilename: overrideContent,
+		swarmStackEnvFilename:      envContent,
+	}
+	if err := recordStackSourceSnapshotInternal(stackSourceDir, current, time.Now(), limit); err != nil {
+		slog.WarnContext(ctx, "failed to record swarm stack source history", "stack", stackName, "error", err)
+	}
+
+	return nil
+}
+
+// writeStackSourceFilesInternal writes the additional files of a stack source
+// into stackSourceDir. The compose, override and env files and the history
+// directory are managed separately and are skipped. A path that escapes
+// stackSourceDir is rejected.
+func writeStackSourceFilesInternal(stackSourceDir string, files []swarmtypes.SyncFile) error {
 	for _, f := range files {
 		if f.RelativePath == swarmStackComposeFilename || f.RelativePath == swarmStackOverrideFilename || f.RelativePath == swarmStackEnvFilename {
 			continue
 		}
+		if f.RelativePath == swarmStackHistoryDirname || strings.HasPrefix(filepath.ToSlash(f.RelativePath), swarmStackHistoryDirname+"/") {
+			continue
+		}
 		fPath := filepath.Join(stackSourceDir, f.RelativePath)
 		// Prevent path traversal
 		if !appfs.IsSafeSubdirectory(stackSourceDir, fPath) {
@@ -2601,6 +4521,171 @@ func (s *SwarmService) upsertStackSourceInternal(ctx context.Context, environmen
 	return nil
 }
 
+// stageStackSourceForPlanInternal copies the saved stack source into a new
+// temporary directory and writes files over it, giving a dry run the same
+// working directory a deploy with those files would use. The caller removes
+// the returned directory.
+func stageStackSourceForPlanInternal(stackSourceDir string, files []swarmtypes.SyncFile) (string, error) {
+	stageDir, err := os.MkdirTemp("", "arcane-swarm-plan-*")
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to create swarm stack plan directory")
+	}
+
+	if _, err := os.Stat(stackSourceDir); err == nil {
+		if err := appfs.CopyDirectoryContents(stackSourceDir, stageDir); err != nil {
+			_ = os.RemoveAll(stageDir)
+			return "", errors.WrapIf(err, "failed to stage swarm stack source")
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		_ = os.RemoveAll(stageDir)
+		return "", errors.WrapIf(err, "failed to stage swarm stack source")
+	}
+
+	if err := writeStackSourceFilesInternal(stageDir, files); err != nil {
+		_ = os.RemoveAll(stageDir)
+		return "", err
+	}
+
+	return stageDir, nil
+}
+
+// recordStackSourceSnapshotInternal stores files under the history directory as
+// "<filename>.<timestamp>" and prunes snapshots beyond limit. Content identical to the
+// newest snapshot is not recorded again.
+func recordStackSourceSnapshotInternal(stackSourceDir string, files map[string]string, now time.Time, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	versions, err := listStackSourceSnapshotsInternal(stackSourceDir)
+	if err != nil {
+		return err
+	}
+
+	unchanged := false
+	if len(versions) > 0 {
+		latest, err := readStackSourceVersionInternal(stackSourceDir, versions[0].ID)
+		if err != nil {
+			return err
+		}
+		unchanged = maps.Equal(latest, files)
+	}
+
+	historyDir := filepath.Join(stackSourceDir, swarmStackHistoryDirname)
+	if !unchanged {
+		if err := os.MkdirAll(historyDir, common.DirPerm); err != nil {
+			return errors.WrapIf(err, "failed to create swarm stack history directory")
+		}
+
+		versionID := now.UTC().Format(swarmStackHistoryTimeLayout)
+		for _, filename := range swarmStackSourceFilenames {
+			content := files[filename]
+			if content == "" {
+				continue
+			}
+			if err := atomic.WriteFile(filepath.Join(historyDir, filename+"."+versionID), []byte(content), common.FilePerm); err != nil {
+				return errors.WrapIff(err, "failed to write %s snapshot", filename)
+			}
+		}
+
+		if versions, err = listStackSourceSnapshotsInternal(stackSourceDir); err != nil {
+			return err
+		}
+	}
+
+	for _, stale := range versions[min(limit, len(versions)):] {
+		for _, filename := range stale.Files {
+			if err := os.Remove(filepath.Join(historyDir, filename+"."+stale.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errors.WrapIff(err, "failed to prune %s snapshot", filename)
+			}
+		}
+	}
+
+	return nil
+}
+
+// listStackSourceSnapshotsInternal groups history files by version, newest first.
+func listStackSourceSnapshotsInternal(stackSourceDir string) ([]swarmtypes.StackSourceVersion, error) {
+	entries, err := os.ReadDir(filepath.Join(stackSourceDir, swarmStackHistoryDirname))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []swarmtypes.StackSourceVersion{}, nil
+		}
+		return nil, errors.WrapIf(err, "failed to read swarm stack history directory")
+	}
+
+	byID := make(map[string]*swarmtypes.StackSourceVersion)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, filename := range swarmStackSourceFilenames {
+			versionID, ok := strings.CutPrefix(entry.Name(), filename+".")
+			if !ok {
+				continue
+			}
+			createdAt, err := time.Parse(swarmStackHistoryTimeLayout, versionID)
+			if err != nil {
+				continue
+			}
+			version, exists := byID[versionID]
+			if !exists {
+				version = &swarmtypes.StackSourceVersion{ID: versionID, CreatedAt: createdAt, Files: []string{}}
+				byID[versionID] = version
+			}
+			version.Files = append(version.Files, filename)
+			break
+		}
+	}
+
+	versions := make([]swarmtypes.StackSourceVersion, 0, len(byID))
+	for _, version := range byID {
+		versions = append(versions, *version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CreatedAt.After(versions[j].CreatedAt)
+	})
+
+	return versions, nil
+}
+
+// readStackSourceVersionInternal loads the compose, override, and env content of a snapshot,
+// or of the live source when versionID is "current". Missing files read as empty.
+func readStackSourceVersionInternal(stackSourceDir, versionID string) (map[string]string, error) {
+	pathFor := func(filename string) string {
+		return filepath.Join(stackSourceDir, filename)
+	}
+	if versionID != swarmStackCurrentVersion {
+		// Parsing the ID also guarantees it cannot contain path separators.
+		if _, err := time.Parse(swarmStackHistoryTimeLayout, versionID); err != nil {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid stack source version %q", versionID)
+		}
+		pathFor = func(filename string) string {
+			return filepath.Join(stackSourceDir, swarmStackHistoryDirname, filename+"."+versionID)
+		}
+	}
+
+	files := make(map[string]string, len(swarmStackSourceFilenames))
+	found := false
+	for _, filename := range swarmStackSourceFilenames {
+		content, err := os.ReadFile(pathFor(filename))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				files[filename] = ""
+				continue
+			}
+			return nil, errors.WrapIff(err, "failed to read %s", filename)
+		}
+		files[filename] = string(content)
+		found = true
+	}
+	if !found {
+		return nil, errors.WrapIff(cerrdefs.ErrNotFound, "stack source version %s not found", versionID)
+	}
+
+	return files, nil
+}
+
 func (s *SwarmService) deleteStackSourceInternal(ctx context.Context, environmentID, stackName string) error {
 	if strings.TrimSpace(stackName) == "" {
 		return errors.New("stack name is required")
@@ -2649,8 +4734,15 @@ const (
 	swarmStackComposeFilename      = "compose.yaml"
 	swarmStackOverrideFilename     = "compose.override.yaml"
 	swarmStackEnvFilename          = ".env"
+
+	swarmStackHistoryDirname            = ".history"
+	swarmStackHistoryTimeLayout         = "20060102T150405.000000000Z"
+	swarmStackCurrentVersion            = "current"
+	defaultSwarmStackSourceHistoryLimit = 10
 )
 
+var swarmStackSourceFilenames = []string{swarmStackComposeFilename, swarmStackOverrideFilename, swarmStackEnvFilename}
+
 func (s *SwarmService) resolveSwarmStackSourceDirInternal(ctx context.Context, environmentID, stackName string) (string, string, error) {
 	normalizedStackName := appfs.SanitizeProjectName(strings.TrimSpace(stackName))
 	if normalizedStackName == "" || strings.Trim(normalizedStackName, "_") == "" {
@@ -2742,8 +4834,8 @@ func (s *SwarmService) getPathMapperInternal(ctx context.Context) *appfs.PathMap
 	return nil
 }
 
-func (s *SwarmService) ensureSwarmManagerInternal(ctx context.Context) error {
-	info, err := s.getDockerInfoInternal(ctx)
+func (s *SwarmService) ensureSwarmManagerInternal(ctx context.Context, environmentID string) error {
+	info, err := s.getDockerInfoInternal(ctx, environmentID)
 	if err != nil {
 		return err
 	}
@@ -2758,8 +4850,8 @@ func (s *SwarmService) ensureSwarmManagerInternal(ctx context.Context) error {
 	return nil
 }
 
-func (s *SwarmService) ensureSwarmActiveInternal(ctx context.Context) error {
-	info, err := s.getDockerInfoInternal(ctx)
+func (s *SwarmService) ensureSwarmActiveInternal(ctx context.Context, environmentID string) error {
+	info, err := s.getDockerInfoInternal(ctx, environmentID)
 	if err != nil {
 		return err
 	}
@@ -2771,8 +4863,27 @@ func (s *SwarmService) ensureSwarmActiveInternal(ctx context.Context) error {
 	return nil
 }
 
-func (s *SwarmService) getDockerInfoInternal(ctx context.Context) (system.Info, error) {
-	dockerClient, err := s.dockerService.GetClient(ctx)
+// dockerClientForEnvironmentInternal returns the Docker client that serves
+// environmentID. Swarm requests for remote environments are forwarded to their
+// agent by the environment middleware, which rewrites the path to the local
+// environment, so only the local daemon is ever reachable from here. A remote
+// ID reaching this point means the proxy was bypassed and is rejected rather
+// than silently answered with the local swarm.
+func (s *SwarmService) dockerClientForEnvironmentInternal(ctx context.Context, environmentID string) (*dockerclient.Client, error) {
+	if !isLocalSwarmEnvironmentInternal(environmentID) {
+		return nil, errors.WithDetails(common.ErrSwarmRemoteEnvironment, "environmentId", environmentID)
+	}
+	return s.dockerService.GetClient(ctx)
+}
+
+// isLocalSwarmEnvironmentInternal reports whether environmentID refers to the
+// Docker daemon Arcane runs against. An empty ID is treated as local.
+func isLocalSwarmEnvironmentInternal(environmentID string) bool {
+	return environmentID == "" || environmentID == localEnvironmentID
+}
+
+func (s *SwarmService) getDockerInfoInternal(ctx context.Context, environmentID string) (system.Info, error) {
+	dockerClient, err := s.dockerClientForEnvironmentInternal(ctx, environmentID)
 	if err != nil {
 		return system.Info{}, errors.WrapIf(err, "failed to connect to Docker")
 	}
@@ -2786,7 +4897,7 @@ func (s *SwarmService) getDockerInfoInternal(ctx context.Context) (system.Info,
 }
 
 func (s *SwarmService) SyncSwarmEnabledState(ctx context.Context) error {
-	info, err := s.getDockerInfoInternal(ctx)
+	info, err := s.getDockerInfoInternal(ctx, localEnvironmentID)
 	if err != nil {
 		return err
 	}
@@ -2813,7 +4924,7 @@ func (s *SwarmService) persistSwarmEnabledStateInternal(ctx context.Context, ena
 	}
 }
 
-func (s *SwarmService) buildServicePaginationConfigInternal() pagination.Config[swarmtypes.ServiceSummary] {
+func (s *SwarmService) ServicePaginationConfig() pagination.Config[swarmtypes.ServiceSummary] {
 	return pagination.Config[swarmtypes.ServiceSummary]{
 		SearchAccessors: []pagination.SearchAccessor[swarmtypes.ServiceSummary]{
 			func(svc swarmtypes.ServiceSummary) (string, error) { return svc.Name, nil },
@@ -2828,6 +4939,7 @@ func (s *SwarmService) buildServicePaginationConfigInternal() pagination.Config[
 				return strings.Join(svc.Nodes, " "), nil
 			},
 		},
+		CSVHeaders: []string{"Name", "Image", "ID", "Stack", "Mode", "Networks", "Nodes"},
 		SortBindings: []pagination.SortBinding[swarmtypes.ServiceSummary]{
 			{Key: "name", Fn: func(a, b swarmtypes.ServiceSummary) int { return strings.Compare(a.Name, b.Name) }},
 			{Key: "image", Fn: func(a, b swarmtypes.ServiceSummary) int { return strings.Compare(a.Image, b.Image) }},
@@ -2841,10 +4953,16 @@ func (s *SwarmService) buildServicePaginationConfigInternal() pagination.Config[
 			{Key: "created", Fn: func(a, b swarmtypes.ServiceSummary) int { return compareTimeInternal(a.CreatedAt, b.CreatedAt) }},
 			{Key: "updated", Fn: func(a, b swarmtypes.ServiceSummary) int { return compareTimeInternal(a.UpdatedAt, b.UpdatedAt) }},
 		},
+		RangeBindings: []pagination.RangeBinding[swarmtypes.ServiceSummary]{
+			{Key: "replicas", Number: func(svc swarmtypes.ServiceSummary) float64 { return float64(svc.Replicas) }},
+			{Key: "runningReplicas", Number: func(svc swarmtypes.ServiceSummary) float64 { return float64(svc.RunningReplicas) }},
+			{Key: "created", Time: func(svc swarmtypes.ServiceSummary) time.Time { return svc.CreatedAt }},
+			{Key: "updated", Time: func(svc swarmtypes.ServiceSummary) time.Time { return svc.UpdatedAt }},
+		},
 	}
 }
 
-func (s *SwarmService) buildNodePaginationConfigInternal() pagination.Config[swarmtypes.NodeSummary] {
+func (s *SwarmService) NodePaginationConfig() pagination.Config[swarmtypes.NodeSummary] {
 	return pagination.Config[swarmtypes.NodeSummary]{
 		SearchAccessors: []pagination.SearchAccessor[swarmtypes.NodeSummary]{
 			func(node swarmtypes.NodeSummary) (string, error) { return node.Hostname, nil },
@@ -2853,6 +4971,7 @@ func (s *SwarmService) buildNodePaginationConfigInternal() pagination.Config[swa
 			func(node swarmtypes.NodeSummary) (string, error) { return node.Status, nil },
 			func(node swarmtypes.NodeSummary) (string, error) { return node.Availability, nil },
 		},
+		CSVHeaders: []string{"Hostname", "ID", "Role", "Status", "Availability"},
 		SortBindings: []pagination.SortBinding[swarmtypes.NodeSummary]{
 			{Key: "hostname", Fn: func(a, b swarmtypes.NodeSummary) int { return strings.Compare(a.Hostname, b.Hostname) }},
 			{Key: "role", Fn: func(a, b swarmtypes.NodeSummary) int { return strings.Compare(a.Role, b.Role) }},
@@ -2864,7 +4983,7 @@ func (s *SwarmService) buildNodePaginationConfigInternal() pagination.Config[swa
 	}
 }
 
-func (s *SwarmService) buildTaskPaginationConfigInternal() pagination.Config[swarmtypes.TaskSummary] {
+func (s *SwarmService) TaskPaginationConfig() pagination.Config[swarmtypes.TaskSummary] {
 	return pagination.Config[swarmtypes.TaskSummary]{
 		SearchAccessors: []pagination.SearchAccessor[swarmtypes.TaskSummary]{
 			func(task swarmtypes.TaskSummary) (string, error) { return task.Name, nil },
@@ -2873,6 +4992,7 @@ func (s *SwarmService) buildTaskPaginationConfigInternal() pagination.Config[swa
 			func(task swarmtypes.TaskSummary) (string, error) { return task.ID, nil },
 			func(task swarmtypes.TaskSummary) (string, error) { return task.CurrentState, nil },
 		},
+		CSVHeaders: []string{"Name", "Service", "Node", "ID", "State"},
 		SortBindings: []pagination.SortBinding[swarmtypes.TaskSummary]{
 			{Key: "service", Fn: func(a, b swarmtypes.TaskSummary) int { return strings.Compare(a.ServiceName, b.ServiceName) }},
 			{Key: "node", Fn: func(a, b swarmtypes.TaskSummary) int { return strings.Compare(a.NodeName, b.NodeName) }},
@@ -2883,6 +5003,51 @@ func (s *SwarmService) buildTaskPaginationConfigInternal() pagination.Config[swa
 	}
 }
 
+func (s *SwarmService) ConfigPaginationConfig() pagination.Config[swarmtypes.ConfigSummary] {
+	return pagination.Config[swarmtypes.ConfigSummary]{
+		SearchAccessors: []pagination.SearchAccessor[swarmtypes.ConfigSummary]{
+			func(cfg swarmtypes.ConfigSummary) (string, error) { return cfg.Spec.Name, nil },
+			func(cfg swarmtypes.ConfigSummary) (string, error) { return cfg.ID, nil },
+			func(cfg swarmtypes.ConfigSummary) (string, error) {
+				return swarmLabelsSearchTextInternal(cfg.Spec.Labels), nil
+			},
+		},
+		SortBindings: []pagination.SortBinding[swarmtypes.ConfigSummary]{
+			{Key: "name", Fn: func(a, b swarmtypes.ConfigSummary) int { return strings.Compare(a.Spec.Name, b.Spec.Name) }},
+			{Key: "created", Fn: func(a, b swarmtypes.ConfigSummary) int { return compareTimeInternal(a.CreatedAt, b.CreatedAt) }},
+			{Key: "updated", Fn: func(a, b swarmtypes.ConfigSummary) int { return compareTimeInternal(a.UpdatedAt, b.UpdatedAt) }},
+		},
+	}
+}
+
+func (s *SwarmService) SecretPaginationConfig() pagination.Config[swarmtypes.SecretSummary] {
+	return pagination.Config[swarmtypes.SecretSummary]{
+		SearchAccessors: []pagination.SearchAccessor[swarmtypes.SecretSummary]{
+			func(secret swarmtypes.SecretSummary) (string, error) { return secret.Spec.Name, nil },
+			func(secret swarmtypes.SecretSummary) (string, error) { return secret.ID, nil },
+			func(secret swarmtypes.SecretSummary) (string, error) {
+				return swarmLabelsSearchTextInternal(secret.Spec.Labels), nil
+			},
+		},
+		SortBindings: []pagination.SortBinding[swarmtypes.SecretSummary]{
+			{Key: "name", Fn: func(a, b swarmtypes.SecretSummary) int { return strings.Compare(a.Spec.Name, b.Spec.Name) }},
+			{Key: "created", Fn: func(a, b swarmtypes.SecretSummary) int { return compareTimeInternal(a.CreatedAt, b.CreatedAt) }},
+			{Key: "updated", Fn: func(a, b swarmtypes.SecretSummary) int { return compareTimeInternal(a.UpdatedAt, b.UpdatedAt) }},
+		},
+	}
+}
+
+// swarmLabelsSearchTextInternal renders labels as sorted "key=value" pairs so
+// searches match either a label key or its value.
+func swarmLabelsSearchTextInternal(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, " ")
+}
+
 func (s *SwarmService) buildStackPaginationConfigInternal() pagination.Config[swarmtypes.StackSummary] {
 	return pagination.Config[swarmtypes.StackSummary]{
 		SearchAccessors: []pagination.SearchAccessor[swarmtypes.StackSummary]{
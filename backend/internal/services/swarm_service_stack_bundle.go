@@ -0,0 +1,223 @@
+package services
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	swarmtypes "github.com/getarcaneapp/arcane/types/v2/swarm"
+)
+
+// swarmStackBundleMaxBytes caps the uncompressed size of an imported stack
+// bundle so a crafted archive cannot exhaust memory.
+const swarmStackBundleMaxBytes = 64 << 20
+
+// ExportStacks writes the saved source of every stack in environmentID to w as
+// a gzip-compressed tar archive.
+//
+// Each stack becomes a top-level directory named after the stack holding its
+// compose file, optional override and env files, and any additional synced
+// files. Source history is not exported, and stacks deployed without a saved
+// source cannot be included.
+//
+// Returns the number of stacks written.
+func (s *SwarmService) ExportStacks(ctx context.Context, environmentID string, w io.Writer) (int, error) {
+	stacks, err := s.listPersistedStackSourcesInternal(ctx, environmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	modTime := time.Now()
+
+	written := 0
+	for _, name := range slices.Sorted(maps.Keys(stacks)) {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		source, err := s.GetStackSource(ctx, environmentID, name)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue
+			}
+			return written, errors.WrapIff(err, "failed to read source of stack %s", name)
+		}
+		if err := writeStackBundleEntriesInternal(tarWriter, source, modTime); err != nil {
+			return written, errors.WrapIff(err, "failed to write stack %s to bundle", name)
+		}
+		written++
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return written, errors.WrapIf(err, "failed to finalize stack bundle")
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return written, errors.WrapIf(err, "failed to finalize stack bundle")
+	}
+
+	return written, nil
+}
+
+// ImportStacks reads a bundle produced by ExportStacks and deploys each stack
+// in it to environmentID, saving its source as DeployStack does.
+//
+// With dryRun set, every stack is planned instead and nothing changes. A stack
+// that fails to plan or deploy is reported in its result and does not stop the
+// remaining stacks.
+//
+// Returns an invalid argument error when the bundle cannot be read, holds no
+// stacks, or contains a stack without a compose file.
+func (s *SwarmService) ImportStacks(ctx context.Context, environmentID string, bundle io.Reader, dryRun bool) (*swarmtypes.StackImportResponse, error) {
+	if err := s.ensureSwarmManagerInternal(ctx, environmentID); err != nil {
+		return nil, err
+	}
+
+	sources, err := readStackBundleInternal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &swarmtypes.StackImportResponse{
+		DryRun: dryRun,
+		Stacks: make([]swarmtypes.StackImportResult, 0, len(sources)),
+	}
+	for _, source := range sources {
+		result := swarmtypes.StackImportResult{Name: source.Name}
+		deployResp, err := s.DeployStack(ctx, environmentID, swarmtypes.StackDeployRequest{
+			Name:            source.Name,
+			ComposeContent:  source.ComposeContent,
+			OverrideContent: source.OverrideContent,
+			EnvContent:      source.EnvContent,
+			Files:           source.Files,
+			DryRun:          dryRun,
+		})
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case dryRun:
+			result.Plan = deployResp.Plan
+		default:
+			result.Deployed = true
+		}
+		response.Stacks = append(response.Stacks, result)
+	}
+
+	return response, nil
+}
+
+func writeStackBundleEntriesInternal(tarWriter *tar.Writer, source *swarmtypes.StackSource, modTime time.Time) error {
+	entries := []swarmtypes.SyncFile{{RelativePath: swarmStackComposeFilename, Content: []byte(source.ComposeContent)}}
+	if source.OverrideContent != "" {
+		entries = append(entries, swarmtypes.SyncFile{RelativePath: swarmStackOverrideFilename, Content: []byte(source.OverrideContent)})
+	}
+	if source.EnvContent != "" {
+		entries = append(entries, swarmtypes.SyncFile{RelativePath: swarmStackEnvFilename, Content: []byte(source.EnvContent)})
+	}
+	entries = append(entries, source.Files...)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     path.Join(source.Name, entry.RelativePath),
+			Mode:     int64(common.FilePerm),
+			Size:     int64(len(entry.Content)),
+			ModTime:  modTime,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tarWriter.Write(entry.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readStackBundleInternal parses a stack bundle into one source per top-level
+// directory, ordered by stack name. Directories, links and history snapshots
+// are skipped; entries outside a stack directory are rejected.
+func readStackBundleInternal(bundle io.Reader) ([]swarmtypes.StackSource, error) {
+	gzipReader, err := gzip.NewReader(bundle)
+	if err != nil {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "stack bundle is not a gzip archive")
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	sources := map[string]*swarmtypes.StackSource{}
+	var total int64
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "stack bundle is not a valid tar archive")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "stack bundle entry %q escapes the bundle", header.Name)
+		}
+		stackName, relativePath, ok := strings.Cut(name, "/")
+		if !ok {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "stack bundle entry %q is not inside a stack directory", header.Name)
+		}
+		if relativePath == swarmStackHistoryDirname || strings.HasPrefix(relativePath, swarmStackHistoryDirname+"/") {
+			continue
+		}
+
+		total += header.Size
+		if total > swarmStackBundleMaxBytes {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "stack bundle exceeds %d MiB", swarmStackBundleMaxBytes>>20)
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "failed to read stack bundle entry %q", header.Name)
+		}
+
+		source, exists := sources[stackName]
+		if !exists {
+			source = &swarmtypes.StackSource{Name: stackName}
+			sources[stackName] = source
+		}
+		switch relativePath {
+		case swarmStackComposeFilename:
+			source.ComposeContent = string(content)
+		case swarmStackOverrideFilename:
+			source.OverrideContent = string(content)
+		case swarmStackEnvFilename:
+			source.EnvContent = string(content)
+		default:
+			source.Files = append(source.Files, swarmtypes.SyncFile{RelativePath: relativePath, Content: content})
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "stack bundle contains no stacks")
+	}
+
+	result := make([]swarmtypes.StackSource, 0, len(sources))
+	for _, name := range slices.Sorted(maps.Keys(sources)) {
+		source := sources[name]
+		if strings.TrimSpace(source.ComposeContent) == "" {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "stack %s in bundle has no %s", name, swarmStackComposeFilename)
+		}
+		result = append(result, *source)
+	}
+	return result, nil
+}
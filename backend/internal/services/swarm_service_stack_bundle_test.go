@@ -0,0 +1,98 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	swarmtypes "github.com/getarcaneapp/arcane/types/v2/swarm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwarmService_ExportStacks_RoundTripsSavedSources(t *testing.T) {
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	t.Setenv("SWARM_STACK_SOURCES_DIRECTORY", t.TempDir())
+
+	settingsSvc, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+
+	svc := NewSwarmService(nil, settingsSvc, nil, nil, nil)
+
+	_, err = svc.UpdateStackSource(ctx, "0", "web", swarmtypes.StackSourceUpdateRequest{
+		ComposeContent: "services:\n  web:\n    image: nginx:alpine\n",
+		EnvContent:     "PORT=8080\n",
+		Files:          []swarmtypes.SyncFile{{RelativePath: "config/nginx.conf", Content: []byte("worker_processes 1;")}},
+	})
+	require.NoError(t, err)
+	_, err = svc.UpdateStackSource(ctx, "0", "api", swarmtypes.StackSourceUpdateRequest{
+		ComposeContent:  "services:\n  api:\n    image: api:1\n",
+		OverrideContent: "services:\n  api:\n    deploy:\n      replicas: 2\n",
+	})
+	require.NoError(t, err)
+	// A second save records history, which must stay out of the bundle.
+	_, err = svc.UpdateStackSource(ctx, "0", "api", swarmtypes.StackSourceUpdateRequest{
+		ComposeContent:  "services:\n  api:\n    image: api:2\n",
+		OverrideContent: "services:\n  api:\n    deploy:\n      replicas: 2\n",
+	})
+	require.NoError(t, err)
+
+	var bundle bytes.Buffer
+	count, err := svc.ExportStacks(ctx, "0", &bundle)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	sources, err := readStackBundleInternal(&bundle)
+	require.NoError(t, err)
+	require.Equal(t, []swarmtypes.StackSource{
+		{
+			Name:            "api",
+			ComposeContent:  "services:\n  api:\n    image: api:2\n",
+			OverrideContent: "services:\n  api:\n    deploy:\n      replicas: 2\n",
+		},
+		{
+			Name:           "web",
+			ComposeContent: "services:\n  web:\n    image: nginx:alpine\n",
+			EnvContent:     "PORT=8080\n",
+			Files:          []swarmtypes.SyncFile{{RelativePath: "config/nginx.conf", Content: []byte("worker_processes 1;")}},
+		},
+	}, sources)
+}
+
+func TestReadStackBundleInternal_RejectsInvalidBundles(t *testing.T) {
+	tests := map[string][]tar.Header{
+		"empty":             {},
+		"path traversal":    {{Name: "../web/compose.yaml"}},
+		"absolute path":     {{Name: "/web/compose.yaml"}},
+		"top-level file":    {{Name: "compose.yaml"}},
+		"missing compose":   {{Name: "web/.env"}},
+		"only history kept": {{Name: "web/.history/compose.yaml.20240101T000000.000000000Z"}},
+	}
+	for name, headers := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gzipWriter := gzip.NewWriter(&buf)
+			tarWriter := tar.NewWriter(gzipWriter)
+			for _, header := range headers {
+				content := []byte("services: {}\n")
+				header.Typeflag = tar.TypeReg
+				header.Mode = 0o644
+				header.Size = int64(len(content))
+				require.NoError(t, tarWriter.WriteHeader(&header))
+				_, err := tarWriter.Write(content)
+				require.NoError(t, err)
+			}
+			require.NoError(t, tarWriter.Close())
+			require.NoError(t, gzipWriter.Close())
+
+			_, err := readStackBundleInternal(&buf)
+			require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+		})
+	}
+
+	_, err := readStackBundleInternal(bytes.NewReader([]byte("not a bundle")))
+	require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+}
@@ -3,15 +3,24 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
 	cerrdefs "github.com/containerd/errdefs"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	swarmtypes "github.com/getarcaneapp/arcane/types/v2/swarm"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	networktypes "github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/api/types/system"
 	"github.com/stretchr/testify/require"
@@ -35,6 +44,177 @@ func TestDefaultSwarmListenAddrInternal(t *testing.T) {
 	require.Equal(t, "eth0:2377", defaultSwarmListenAddrInternal(" eth0:2377 "))
 }
 
+func TestNewServiceEventInternal(t *testing.T) {
+	event, ok := newServiceEventInternal(events.Message{
+		Type:     events.ServiceEventType,
+		Action:   events.ActionUpdate,
+		Actor:    events.Actor{ID: "svc1", Attributes: map[string]string{"name": "web", "updatestate.new": "rollback_started"}},
+		TimeNano: time.Unix(100, 0).UnixNano(),
+	}, "svc1", "web")
+	require.True(t, ok)
+	require.Equal(t, swarmtypes.ServiceEventScopeService, event.Scope)
+	require.Equal(t, "update", event.Action)
+	require.Equal(t, "web", event.ServiceName)
+	require.Equal(t, map[string]string{"updatestate.new": "rollback_started"}, event.Attributes)
+	require.True(t, event.Time.Equal(time.Unix(100, 0)))
+
+	event, ok = newServiceEventInternal(events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDie,
+		Actor: events.Actor{ID: "ctr1", Attributes: map[string]string{
+			"com.docker.swarm.service.id": "svc1",
+			"com.docker.swarm.task.id":    "task1",
+			"com.docker.swarm.task.name":  "web.1.task1",
+			"com.docker.swarm.node.id":    "node1",
+			"exitCode":                    "137",
+		}},
+	}, "svc1", "web")
+	require.True(t, ok)
+	require.Equal(t, swarmtypes.ServiceEventScopeTask, event.Scope)
+	require.Equal(t, "task1", event.TaskID)
+	require.Equal(t, "node1", event.NodeID)
+	require.Equal(t, "ctr1", event.ContainerID)
+	require.Equal(t, "137", event.ExitCode)
+
+	_, ok = newServiceEventInternal(events.Message{Type: events.ServiceEventType, Actor: events.Actor{ID: "svc2"}}, "svc1", "web")
+	require.False(t, ok)
+	_, ok = newServiceEventInternal(events.Message{
+		Type:  events.ContainerEventType,
+		Actor: events.Actor{ID: "ctr2", Attributes: map[string]string{"com.docker.swarm.service.id": "svc2"}},
+	}, "svc1", "web")
+	require.False(t, ok)
+}
+
+func TestNextSecretVersionNameInternal(t *testing.T) {
+	require.Equal(t, "db-password-v2", nextSecretVersionNameInternal("db-password"))
+	require.Equal(t, "db-password-v3", nextSecretVersionNameInternal("db-password-v2"))
+	require.Equal(t, "db-password-v10", nextSecretVersionNameInternal("db-password-v9"))
+	require.Equal(t, "dev-vault-v2", nextSecretVersionNameInternal("dev-vault"))
+}
+
+func TestRepointSecretReferencesInternal(t *testing.T) {
+	spec := swarm.ServiceSpec{TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Secrets: []*swarm.SecretReference{
+		{SecretID: "old", SecretName: "db", File: &swarm.SecretReferenceFileTarget{Name: "password", Mode: 0o400}},
+		{SecretID: "other", SecretName: "tls"},
+	}}}}
+
+	require.True(t, repointSecretReferencesInternal(&spec, "old", "new", "db-v2"))
+	refs := spec.TaskTemplate.ContainerSpec.Secrets
+	require.Equal(t, "new", refs[0].SecretID)
+	require.Equal(t, "db-v2", refs[0].SecretName)
+	require.Equal(t, "password", refs[0].File.Name)
+	require.Equal(t, "other", refs[1].SecretID)
+
+	require.False(t, repointSecretReferencesInternal(&spec, "old", "new", "db-v2"))
+	require.False(t, repointSecretReferencesInternal(&swarm.ServiceSpec{}, "old", "new", "db-v2"))
+}
+
+func TestSecretAndConfigUsageInternal(t *testing.T) {
+	services := []swarm.Service{
+		{ID: "svc1", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web"}, TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+			Secrets: []*swarm.SecretReference{{SecretID: "sec1", File: &swarm.SecretReferenceFileTarget{Name: "db_password", UID: "0", GID: "0", Mode: 0o400}}},
+			Configs: []*swarm.ConfigReference{{ConfigID: "cfg1", File: &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx.conf", Mode: 0o444}}, {ConfigID: "cfg1", Runtime: &swarm.ConfigReferenceRuntimeTarget{}}},
+		}}}},
+		{ID: "svc2", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "worker"}, TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+			Secrets: []*swarm.SecretReference{{SecretID: "sec2"}},
+		}}}},
+		{ID: "svc3", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "plugin"}}},
+	}
+
+	require.Equal(t, []swarmtypes.ServiceMountUsage{
+		{ServiceID: "svc1", ServiceName: "web", Target: "db_password", UID: "0", GID: "0", Mode: 0o400},
+	}, serviceMountUsageInternal(services, "sec1", secretMountRefsInternal))
+	require.Equal(t, []swarmtypes.ServiceMountUsage{
+		{ServiceID: "svc1", ServiceName: "web", Target: "/etc/nginx.conf", Mode: 0o444},
+		{ServiceID: "svc1", ServiceName: "web"},
+	}, serviceMountUsageInternal(services, "cfg1", configMountRefsInternal))
+	require.Empty(t, serviceMountUsageInternal(services, "missing", secretMountRefsInternal))
+	require.NotNil(t, serviceMountUsageInternal(services, "missing", secretMountRefsInternal))
+}
+
+func TestBuildStackRemovalPlanInternal(t *testing.T) {
+	stackServices := []swarm.Service{
+		{ID: "svc-web", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "shop_web"}}},
+		{ID: "svc-db", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "shop_db"}}},
+	}
+	allServices := append(slices.Clone(stackServices),
+		swarm.Service{ID: "svc-proxy", Spec: swarm.ServiceSpec{
+			Annotations:  swarm.Annotations{Name: "edge_proxy"},
+			TaskTemplate: swarm.TaskSpec{Networks: []swarm.NetworkAttachmentConfig{{Target: "net-public"}}},
+		}},
+		swarm.Service{ID: "svc-metrics", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "metrics"}},
+			Endpoint: swarm.Endpoint{VirtualIPs: []swarm.EndpointVirtualIP{{NetworkID: "net-public"}}}},
+	)
+	networks := []networktypes.Summary{
+		{Network: networktypes.Network{ID: "net-default", Name: "shop_default"}},
+		{Network: networktypes.Network{ID: "net-public", Name: "shop_public"}},
+		{Network: networktypes.Network{ID: "net-ingress", Name: "ingress", Ingress: true}},
+	}
+
+	plan := buildStackRemovalPlanInternal("shop", stackServices, allServices,
+		[]swarm.Config{{ID: "cfg1", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "shop_nginx"}}}},
+		[]swarm.Secret{{ID: "sec1", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "shop_db_password"}}}},
+		networks,
+	)
+
+	require.Equal(t, "shop", plan.StackName)
+	require.Equal(t, []swarmtypes.StackRemovalResource{{ID: "svc-web", Name: "shop_web"}, {ID: "svc-db", Name: "shop_db"}}, plan.Services)
+	require.Equal(t, []swarmtypes.StackRemovalResource{{ID: "cfg1", Name: "shop_nginx"}}, plan.Configs)
+	require.Equal(t, []swarmtypes.StackRemovalResource{{ID: "sec1", Name: "shop_db_password"}}, plan.Secrets)
+	require.Equal(t, []swarmtypes.StackRemovalResource{{ID: "net-default", Name: "shop_default"}}, plan.Networks)
+	require.Equal(t, []swarmtypes.StackKeptNetwork{
+		{StackRemovalResource: swarmtypes.StackRemovalResource{ID: "net-public", Name: "shop_public"}, Reason: swarmtypes.StackNetworkKeptShared, UsedBy: []string{"edge_proxy", "metrics"}},
+		{StackRemovalResource: swarmtypes.StackRemovalResource{ID: "net-ingress", Name: "ingress"}, Reason: swarmtypes.StackNetworkKeptIngress},
+	}, plan.KeptNetworks)
+}
+
+func TestSummarizeClusterResourcesInternal(t *testing.T) {
+	node := func(id, hostname string, availability swarm.NodeAvailability, state swarm.NodeState) swarm.Node {
+		return swarm.Node{
+			ID:          id,
+			Spec:        swarm.NodeSpec{Availability: availability},
+			Status:      swarm.NodeStatus{State: state},
+			Description: swarm.NodeDescription{Hostname: hostname, Resources: swarm.Resources{NanoCPUs: 4e9, MemoryBytes: 8 << 30}},
+		}
+	}
+	task := func(nodeID string, state swarm.TaskState, cpus, memory int64) swarm.Task {
+		return swarm.Task{
+			NodeID: nodeID,
+			Status: swarm.TaskStatus{State: state},
+			Spec:   swarm.TaskSpec{Resources: &swarm.ResourceRequirements{Reservations: &swarm.Resources{NanoCPUs: cpus, MemoryBytes: memory}}},
+		}
+	}
+
+	resources := summarizeClusterResourcesInternal(
+		[]swarm.Node{
+			node("n2", "worker", swarm.NodeAvailabilityActive, swarm.NodeStateReady),
+			node("n1", "manager", swarm.NodeAvailabilityActive, swarm.NodeStateReady),
+			node("n3", "drained", swarm.NodeAvailabilityDrain, swarm.NodeStateReady),
+		},
+		[]swarm.Task{
+			task("n1", swarm.TaskStateRunning, 1e9, 2<<30),
+			task("n1", swarm.TaskStateRunning, 5e8, 1<<30),
+			task("n2", swarm.TaskStateRunning, 6e9, 1<<30),
+			task("n2", swarm.TaskStateFailed, 1e9, 1<<30),
+			{NodeID: "n2", Status: swarm.TaskStatus{State: swarm.TaskStateRunning}},
+		},
+	)
+
+	// worker is reserved past its 4 CPUs, but manager's 2.5 CPUs of headroom
+	// are still available to the cluster.
+	require.Equal(t, swarmtypes.ResourceCapacity{Total: 8e9, Allocated: 7.5e9, Available: 2.5e9}, resources.NanoCPUs)
+	require.Equal(t, swarmtypes.ResourceCapacity{Total: 16 << 30, Allocated: 4 << 30, Available: 12 << 30}, resources.MemoryBytes)
+
+	require.Len(t, resources.Nodes, 3)
+	require.Equal(t, "drained", resources.Nodes[0].Hostname)
+	require.False(t, resources.Nodes[0].Schedulable)
+	require.Equal(t, "manager", resources.Nodes[1].Hostname)
+	require.Equal(t, 2, resources.Nodes[1].RunningTasks)
+	require.Equal(t, "worker", resources.Nodes[2].Hostname)
+	require.Equal(t, 2, resources.Nodes[2].RunningTasks)
+	require.Equal(t, swarmtypes.ResourceCapacity{Total: 4e9, Allocated: 6e9, Available: 0}, resources.Nodes[2].NanoCPUs)
+}
+
 func TestSwarmService_FetchSwarmNodeIdentityViaEdgeInternal_UsesEnvironmentAccessToken(t *testing.T) {
 	ctx := context.Background()
 	db := setupEnvironmentServiceTestDB(t)
@@ -121,6 +301,149 @@ func TestSwarmService_UpdateAndGetStackSource_UsesStoredFilesWithoutSwarmManager
 	require.Len(t, source.Files, 2)
 }
 
+func TestSwarmService_StackSourceHistory_RecordsChangedVersionsAndDiffs(t *testing.T) {
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	rootDir := t.TempDir()
+	t.Setenv("SWARM_STACK_SOURCES_DIRECTORY", rootDir)
+
+	settingsSvc, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+
+	svc := NewSwarmService(nil, settingsSvc, nil, nil, nil)
+
+	for _, compose := range []string{
+		"services:\n  web:\n    image: nginx:alpine\n",
+		"services:\n  web:\n    image: nginx:alpine\n",
+		"services:\n  web:\n    image: nginx:1.27\n",
+	} {
+		_, err := svc.UpdateStackSource(ctx, "0", "demo-stack", swarmtypes.StackSourceUpdateRequest{ComposeContent: compose})
+		require.NoError(t, err)
+	}
+
+	history, err := svc.GetStackSourceHistory(ctx, "0", "demo-stack")
+	require.NoError(t, err)
+	require.Len(t, history, 2, "unchanged saves must not add a snapshot")
+	require.Equal(t, []string{"compose.yaml"}, history[0].Files)
+
+	diff, err := svc.GetStackSourceDiff(ctx, "0", "demo-stack", history[1].ID, "current")
+	require.NoError(t, err)
+	require.Contains(t, diff.Diff, "-    image: nginx:alpine")
+	require.Contains(t, diff.Diff, "+    image: nginx:1.27")
+
+	source, err := svc.GetStackSource(ctx, "0", "demo-stack")
+	require.NoError(t, err)
+	require.Empty(t, source.Files, "history snapshots must not surface as extra files")
+
+	_, err = svc.GetStackSourceDiff(ctx, "0", "demo-stack", "../../etc/passwd", "current")
+	require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+}
+
+func TestSwarmService_MigrateStackSources(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+	writeSource := func(root, env, stack, content string) {
+		dir := filepath.Join(root, env, stack)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, swarmStackHistoryDirname), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, swarmStackComposeFilename), []byte(content), 0o600))
+	}
+	writeSource(oldRoot, "0", "web", "services: {web: {}}")
+	writeSource(oldRoot, "env-2", "api", "services: {api: {}}")
+	writeSource(oldRoot, "0", "taken", "old")
+	writeSource(newRoot, "0", "taken", "new")
+
+	svc := NewSwarmService(nil, nil, nil, nil, nil)
+	moved, err := svc.MigrateStackSources(context.Background(), oldRoot, newRoot)
+	require.NoError(t, err)
+	require.Equal(t, 2, moved)
+
+	content, err := os.ReadFile(filepath.Join(newRoot, "env-2", "api", swarmStackComposeFilename))
+	require.NoError(t, err)
+	require.Equal(t, "services: {api: {}}", string(content))
+	require.DirExists(t, filepath.Join(newRoot, "0", "web", swarmStackHistoryDirname))
+	require.NoDirExists(t, filepath.Join(oldRoot, "0", "web"))
+
+	// An existing destination is never overwritten; the old copy stays put.
+	content, err = os.ReadFile(filepath.Join(newRoot, "0", "taken", swarmStackComposeFilename))
+	require.NoError(t, err)
+	require.Equal(t, "new", string(content))
+	require.DirExists(t, filepath.Join(oldRoot, "0", "taken"))
+
+	_, err = svc.MigrateStackSources(context.Background(), oldRoot, filepath.Join(oldRoot, "nested"))
+	require.Error(t, err)
+
+	moved, err = svc.MigrateStackSources(context.Background(), filepath.Join(oldRoot, "missing"), newRoot)
+	require.NoError(t, err)
+	require.Zero(t, moved)
+}
+
+func TestRecordStackSourceSnapshotInternal_PrunesBeyondLimit(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := range 3 {
+		files := map[string]string{
+			swarmStackComposeFilename:  "services: {}\n# rev " + string(rune('a'+i)) + "\n",
+			swarmStackOverrideFilename: "",
+			swarmStackEnvFilename:      "REV=" + string(rune('a'+i)) + "\n",
+		}
+		require.NoError(t, recordStackSourceSnapshotInternal(dir, files, base.Add(time.Duration(i)*time.Minute), 2))
+	}
+
+	history, err := listStackSourceSnapshotsInternal(dir)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, base.Add(2*time.Minute), history[0].CreatedAt)
+	require.Equal(t, base.Add(time.Minute), history[1].CreatedAt)
+	require.ElementsMatch(t, []string{swarmStackComposeFilename, swarmStackEnvFilename}, history[0].Files)
+
+	entries, err := os.ReadDir(filepath.Join(dir, swarmStackHistoryDirname))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+}
+
+func TestStageStackSourceForPlanInternal_OverlaysFilesOnSavedSource(t *testing.T) {
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "config", "nginx.conf"), []byte("stale"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "config", "kept.conf"), []byte("kept"), 0o600))
+
+	stageDir, err := stageStackSourceForPlanInternal(sourceDir, []swarmtypes.SyncFile{
+		{RelativePath: "config/nginx.conf", Content: []byte("fresh")},
+		{RelativePath: "scripts/setup.sh", Content: []byte("#!/bin/sh")},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(stageDir) })
+
+	for path, want := range map[string]string{
+		"config/nginx.conf": "fresh",
+		"config/kept.conf":  "kept",
+		"scripts/setup.sh":  "#!/bin/sh",
+	} {
+		got, err := os.ReadFile(filepath.Join(stageDir, path))
+		require.NoError(t, err)
+		require.Equal(t, want, string(got), path)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(sourceDir, "config", "nginx.conf"))
+	require.NoError(t, err)
+	require.Equal(t, "stale", string(saved), "staging must not touch the saved source")
+	require.NoFileExists(t, filepath.Join(sourceDir, "scripts", "setup.sh"))
+
+	// A stack without a saved source stages only the given files.
+	freshStage, err := stageStackSourceForPlanInternal(filepath.Join(t.TempDir(), "missing"), []swarmtypes.SyncFile{
+		{RelativePath: "config/app.yaml", Content: []byte("a: 1")},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(freshStage) })
+	require.FileExists(t, filepath.Join(freshStage, "config", "app.yaml"))
+
+	_, err = stageStackSourceForPlanInternal(sourceDir, []swarmtypes.SyncFile{
+		{RelativePath: "../escape.txt", Content: []byte("x")},
+	})
+	require.Error(t, err)
+}
+
 func TestSwarmService_UpdateAndGetStackSource_RoundTripsOverride(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
@@ -279,7 +602,7 @@ func TestSwarmService_ScaleService_HandlesServiceModesInternal(t *testing.T) {
 
 			svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
 
-			resp, err := svc.ScaleService(ctx, "service-1", replicas)
+			resp, err := svc.ScaleService(ctx, "0", "service-1", replicas)
 			if tt.wantErr {
 				require.Error(t, err)
 				require.True(t, cerrdefs.IsInvalidArgument(err), "expected invalid argument, got %v", err)
@@ -350,3 +673,654 @@ func TestSwarmService_BuildNodeAgentStatusInternal(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSwarmPlacementConstraintInternal(t *testing.T) {
+	valid := []string{
+		"node.labels.zone==eu",
+		"node.role != manager",
+		"engine.labels.storage==ssd",
+		"node.hostname==worker-1",
+		"node.platform.arch==x86_64",
+	}
+	for _, constraint := range valid {
+		require.NoError(t, validateSwarmPlacementConstraintInternal(constraint), constraint)
+	}
+
+	invalid := []string{
+		"",
+		"node.labels.zone=eu",
+		"node.labels.==eu",
+		"node.labels.zone==",
+		"node.zone==eu",
+		"node.labels.zone===eu",
+	}
+	for _, constraint := range invalid {
+		err := validateSwarmPlacementConstraintInternal(constraint)
+		require.Error(t, err, constraint)
+		require.True(t, cerrdefs.IsInvalidArgument(err), "expected invalid argument for %q, got %v", constraint, err)
+	}
+}
+
+func TestApplySwarmServicePlacementInternal(t *testing.T) {
+	taskTemplate := swarm.TaskSpec{
+		Placement: &swarm.Placement{
+			Constraints: []string{"node.role==manager"},
+			Preferences: []swarm.PlacementPreference{{Spread: &swarm.SpreadOver{SpreadDescriptor: "node.labels.rack"}}},
+		},
+	}
+
+	applySwarmServicePlacementInternal(&taskTemplate, swarmtypes.ServicePlacementRequest{
+		Constraints: &[]string{" node.labels.zone==eu "},
+	})
+	require.Equal(t, []string{"node.labels.zone==eu"}, taskTemplate.Placement.Constraints)
+	require.Len(t, taskTemplate.Placement.Preferences, 1)
+
+	applySwarmServicePlacementInternal(&taskTemplate, swarmtypes.ServicePlacementRequest{
+		Constraints: &[]string{},
+		Preferences: &[]swarmtypes.ServicePlacementPreference{},
+	})
+	require.NotNil(t, taskTemplate.Placement.Constraints)
+	require.Empty(t, taskTemplate.Placement.Constraints)
+	require.Empty(t, taskTemplate.Placement.Preferences)
+}
+
+func TestApplySwarmServiceResourcesInternal(t *testing.T) {
+	taskTemplate := swarm.TaskSpec{
+		Resources: &swarm.ResourceRequirements{
+			Limits: &swarm.Limit{NanoCPUs: 2_000_000_000, MemoryBytes: 512 << 20, Pids: 100},
+		},
+	}
+
+	err := applySwarmServiceResourcesInternal(&taskTemplate, &swarmtypes.ServiceResourceValues{
+		MemoryBytes: new(int64(1 << 30)),
+	}, &swarmtypes.ServiceResourceValues{
+		NanoCPUs: new(int64(500_000_000)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2_000_000_000), taskTemplate.Resources.Limits.NanoCPUs, "unset fields keep the spec value")
+	require.Equal(t, int64(1<<30), taskTemplate.Resources.Limits.MemoryBytes)
+	require.Equal(t, int64(100), taskTemplate.Resources.Limits.Pids)
+	require.Equal(t, int64(500_000_000), taskTemplate.Resources.Reservations.NanoCPUs)
+
+	require.NoError(t, applySwarmServiceResourcesInternal(&taskTemplate, &swarmtypes.ServiceResourceValues{
+		NanoCPUs: new(int64(0)),
+	}, nil))
+	require.Zero(t, taskTemplate.Resources.Limits.NanoCPUs, "zero removes the limit")
+}
+
+func TestApplySwarmServiceResourcesInternal_RejectsInvalidValues(t *testing.T) {
+	cases := map[string]struct {
+		limits       *swarmtypes.ServiceResourceValues
+		reservations *swarmtypes.ServiceResourceValues
+	}{
+		"negative cpu":             {limits: &swarmtypes.ServiceResourceValues{NanoCPUs: new(int64(-1))}},
+		"cpu below minimum":        {reservations: &swarmtypes.ServiceResourceValues{NanoCPUs: new(int64(1000))}},
+		"memory below minimum":     {limits: &swarmtypes.ServiceResourceValues{MemoryBytes: new(int64(1 << 20))}},
+		"reservation above cpu":    {reservations: &swarmtypes.ServiceResourceValues{NanoCPUs: new(int64(3_000_000_000))}},
+		"reservation above memory": {limits: &swarmtypes.ServiceResourceValues{MemoryBytes: new(int64(64 << 20))}, reservations: &swarmtypes.ServiceResourceValues{MemoryBytes: new(int64(128 << 20))}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			taskTemplate := swarm.TaskSpec{
+				Resources: &swarm.ResourceRequirements{Limits: &swarm.Limit{NanoCPUs: 2_000_000_000}},
+			}
+			err := applySwarmServiceResourcesInternal(&taskTemplate, tc.limits, tc.reservations)
+			require.Error(t, err)
+			require.True(t, cerrdefs.IsInvalidArgument(err))
+			require.Equal(t, int64(2_000_000_000), taskTemplate.Resources.Limits.NanoCPUs)
+			require.Nil(t, taskTemplate.Resources.Reservations)
+		})
+	}
+}
+
+func TestSwarmReservationWarningsInternal(t *testing.T) {
+	nodes := []swarm.Node{
+		{
+			Spec:        swarm.NodeSpec{Availability: swarm.NodeAvailabilityActive},
+			Status:      swarm.NodeStatus{State: swarm.NodeStateReady},
+			Description: swarm.NodeDescription{Resources: swarm.Resources{NanoCPUs: 2_000_000_000, MemoryBytes: 4 << 30}},
+		},
+		{
+			Spec:        swarm.NodeSpec{Availability: swarm.NodeAvailabilityDrain},
+			Status:      swarm.NodeStatus{State: swarm.NodeStateReady},
+			Description: swarm.NodeDescription{Resources: swarm.Resources{NanoCPUs: 16_000_000_000, MemoryBytes: 64 << 30}},
+		},
+	}
+
+	require.Empty(t, swarmReservationWarningsInternal(swarm.Resources{NanoCPUs: 1_000_000_000, MemoryBytes: 1 << 30}, nodes))
+	require.Len(t, swarmReservationWarningsInternal(swarm.Resources{NanoCPUs: 4_000_000_000}, nodes), 1, "drained nodes do not count")
+}
+
+func TestSwarmService_ForceUpdateService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("bumps force update at current version", func(t *testing.T) {
+		var updatedSpec swarm.ServiceSpec
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/v1.41/info":
+				require.NoError(t, json.NewEncoder(w).Encode(system.Info{
+					Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: true},
+				}))
+			case r.Method == http.MethodGet && r.URL.Path == "/v1.41/services/service-1":
+				require.NoError(t, json.NewEncoder(w).Encode(swarm.Service{
+					ID:   "service-1",
+					Meta: swarm.Meta{Version: swarm.Version{Index: 11}},
+					Spec: swarm.ServiceSpec{
+						Annotations:  swarm.Annotations{Name: "service-1"},
+						TaskTemplate: swarm.TaskSpec{ForceUpdate: 2},
+					},
+				}))
+			case r.Method == http.MethodPost && r.URL.Path == "/v1.41/services/service-1/update":
+				require.Equal(t, "11", r.URL.Query().Get("version"))
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&updatedSpec))
+				require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"Warnings": []string{"restarted"}}))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
+
+		resp, err := svc.ForceUpdateService(ctx, "0", "service-1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"restarted"}, resp.Warnings)
+		require.Equal(t, uint64(3), updatedSpec.TaskTemplate.ForceUpdate)
+	})
+
+	t.Run("requires a manager node", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodGet && r.URL.Path == "/v1.41/info" {
+				require.NoError(t, json.NewEncoder(w).Encode(system.Info{
+					Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: false},
+				}))
+				return
+			}
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}))
+		t.Cleanup(server.Close)
+
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
+
+		_, err := svc.ForceUpdateService(ctx, "0", "service-1")
+		require.ErrorIs(t, err, common.ErrSwarmManagerRequired)
+	})
+}
+
+func TestSwarmService_StreamTaskLogsFallsBackToContainerLogs(t *testing.T) {
+	ctx := context.Background()
+
+	newServer := func(t *testing.T, containerLogsOK bool) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch dockerTestPathInternal(r.URL.Path) {
+			case "/info":
+				w.Header().Set("Content-Type", "application/json")
+				require.NoError(t, json.NewEncoder(w).Encode(system.Info{
+					Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: true},
+				}))
+			case "/tasks/task-1":
+				w.Header().Set("Content-Type", "application/json")
+				require.NoError(t, json.NewEncoder(w).Encode(swarm.Task{
+					ID:     "task-1",
+					Spec:   swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{TTY: true}},
+					Status: swarm.TaskStatus{State: swarm.TaskStateRunning, ContainerStatus: &swarm.ContainerStatus{ContainerID: "container-1"}},
+				}))
+			case "/tasks/task-1/logs":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"message":"task logs are not available"}`))
+			case "/containers/container-1/logs":
+				if !containerLogsOK {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"no such container: container-1"}`))
+					return
+				}
+				_, _ = w.Write([]byte("hello from the container\n"))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("streams container logs when the task log API fails", func(t *testing.T) {
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, newServer(t, true))}, nil, nil, nil, nil)
+
+		logsChan := make(chan string, 4)
+		require.NoError(t, svc.StreamTaskLogs(ctx, "0", "task-1", logsChan, false, "100", "", false))
+		require.Equal(t, "hello from the container", <-logsChan)
+	})
+
+	t.Run("reports both errors when the fallback also fails", func(t *testing.T) {
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, newServer(t, false))}, nil, nil, nil, nil)
+
+		err := svc.StreamTaskLogs(ctx, "0", "task-1", make(chan string, 1), false, "100", "", false)
+		require.Error(t, err)
+		require.True(t, cerrdefs.IsNotFound(err), "the container error should be the cause: %v", err)
+		require.ErrorContains(t, err, "no such container: container-1")
+		require.ErrorContains(t, err, "task logs are not available")
+	})
+}
+
+func TestSwarmService_PinServiceImage(t *testing.T) {
+	ctx := context.Background()
+	const digest = "sha256:0f24d43d5f1e8a5a3dcd64cb2b3e1f8f0b6c2e07f8a4e5c6d7b8a9f0e1d2c3b4"
+
+	newServer := func(t *testing.T, image string, updatedSpec *swarm.ServiceSpec) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/v1.41/info":
+				require.NoError(t, json.NewEncoder(w).Encode(system.Info{
+					Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: true},
+				}))
+			case r.Method == http.MethodGet && r.URL.Path == "/v1.41/services/service-1":
+				require.NoError(t, json.NewEncoder(w).Encode(swarm.Service{
+					ID:   "service-1",
+					Meta: swarm.Meta{Version: swarm.Version{Index: 4}},
+					Spec: swarm.ServiceSpec{
+						Annotations:  swarm.Annotations{Name: "shop_web", Labels: map[string]string{"com.docker.stack.image": "nginx:latest"}},
+						TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: image}},
+					},
+				}))
+			case r.Method == http.MethodPost && r.URL.Path == "/v1.41/services/service-1/update":
+				require.Equal(t, "4", r.URL.Query().Get("version"))
+				require.NoError(t, json.NewDecoder(r.Body).Decode(updatedSpec))
+				require.NoError(t, json.NewEncoder(w).Encode(map[string]any{}))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("rewrites the image to its digest", func(t *testing.T) {
+		var updatedSpec swarm.ServiceSpec
+		server := newServer(t, "nginx:latest@"+digest, &updatedSpec)
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
+
+		resp, err := svc.PinServiceImage(ctx, "0", "service-1")
+		require.NoError(t, err)
+		require.Equal(t, "nginx@"+digest, resp.Image)
+		require.Equal(t, digest, resp.Digest)
+		require.Equal(t, "nginx@"+digest, updatedSpec.TaskTemplate.ContainerSpec.Image)
+		require.Equal(t, digest, updatedSpec.Labels[swarmtypes.ServiceImagePinnedLabel])
+		require.Equal(t, "nginx:latest", updatedSpec.Labels["com.docker.stack.image"])
+	})
+
+	t.Run("rejects an unresolved image", func(t *testing.T) {
+		var updatedSpec swarm.ServiceSpec
+		server := newServer(t, "nginx:latest", &updatedSpec)
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
+
+		_, err := svc.PinServiceImage(ctx, "0", "service-1")
+		require.True(t, cerrdefs.IsInvalidArgument(err))
+		require.Nil(t, updatedSpec.TaskTemplate.ContainerSpec)
+	})
+}
+
+func TestLabelSwarmServiceLogLineInternal(t *testing.T) {
+	nodeNames := map[string]string{"node-abc": "worker-1"}
+	attrs := "com.docker.swarm.node.id=node-abc,com.docker.swarm.service.id=svc1,com.docker.swarm.task.id=task0123456789abcdef"
+
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "plain attributes",
+			line: attrs + " hello world",
+			want: "[task=task01234567 node=worker-1] hello world",
+		},
+		{
+			name: "stderr and timestamp keep leading position",
+			line: "[STDERR] 2026-01-02T03:04:05.123456789Z " + attrs + " boom",
+			want: "[STDERR] 2026-01-02T03:04:05.123456789Z [task=task01234567 node=worker-1] boom",
+		},
+		{
+			name: "unknown node falls back to id",
+			line: "com.docker.swarm.node.id=node-zzz,com.docker.swarm.task.id=t1 msg",
+			want: "[task=t1 node=node-zzz] msg",
+		},
+		{
+			name: "line without attributes is unchanged",
+			line: "just a message",
+			want: "just a message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, labelSwarmServiceLogLineInternal(tt.line, nodeNames))
+		})
+	}
+}
+
+func TestBuildSwarmServiceUpdateStatusInternal(t *testing.T) {
+	replicas := uint64(3)
+	replicated := swarm.Service{
+		ID: "svc-1",
+		Spec: swarm.ServiceSpec{
+			Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+		},
+	}
+	runningTask := swarm.Task{DesiredState: swarm.TaskStateRunning, Status: swarm.TaskStatus{State: swarm.TaskStateRunning}}
+	startingTask := swarm.Task{DesiredState: swarm.TaskStateRunning, Status: swarm.TaskStatus{State: swarm.TaskStatePreparing}}
+	shutdownTask := swarm.Task{DesiredState: swarm.TaskStateShutdown, Status: swarm.TaskStatus{State: swarm.TaskStateShutdown}}
+	replacedTask := swarm.Task{DesiredState: swarm.TaskStateShutdown, Status: swarm.TaskStatus{State: swarm.TaskStateRunning}}
+
+	t.Run("converged when all replicas run and no update is active", func(t *testing.T) {
+		service := replicated
+		service.UpdateStatus = &swarm.UpdateStatus{State: swarm.UpdateStateCompleted, Message: "update completed"}
+
+		status := buildSwarmServiceUpdateStatusInternal(service, []swarm.Task{runningTask, runningTask, runningTask, shutdownTask})
+		require.Equal(t, uint64(3), status.DesiredReplicas)
+		require.Equal(t, uint64(3), status.RunningReplicas)
+		require.Equal(t, "completed", status.State)
+		require.True(t, status.Converged)
+		require.False(t, status.Paused)
+	})
+
+	t.Run("paused update is not converged", func(t *testing.T) {
+		service := replicated
+		service.UpdateStatus = &swarm.UpdateStatus{State: swarm.UpdateStatePaused, Message: "update paused due to failure"}
+
+		status := buildSwarmServiceUpdateStatusInternal(service, []swarm.Task{runningTask, runningTask, startingTask})
+		require.Equal(t, uint64(2), status.RunningReplicas)
+		require.True(t, status.Paused)
+		require.False(t, status.Converged)
+		require.Equal(t, "update paused due to failure", status.Message)
+	})
+
+	t.Run("replaced tasks still running during an update are not counted", func(t *testing.T) {
+		service := replicated
+		service.UpdateStatus = &swarm.UpdateStatus{State: swarm.UpdateStateUpdating}
+
+		status := buildSwarmServiceUpdateStatusInternal(service, []swarm.Task{runningTask, runningTask, startingTask, replacedTask})
+		require.Equal(t, uint64(2), status.RunningReplicas)
+		require.False(t, status.Converged)
+	})
+
+	t.Run("global service desired count comes from tasks", func(t *testing.T) {
+		service := swarm.Service{ID: "svc-2", Spec: swarm.ServiceSpec{Mode: swarm.ServiceMode{Global: &swarm.GlobalService{}}}}
+
+		status := buildSwarmServiceUpdateStatusInternal(service, []swarm.Task{runningTask, startingTask, shutdownTask})
+		require.Equal(t, uint64(2), status.DesiredReplicas)
+		require.Equal(t, uint64(1), status.RunningReplicas)
+		require.False(t, status.Converged)
+	})
+}
+
+func TestSwarmService_ScaleStack(t *testing.T) {
+	ctx := context.Background()
+	replicas := uint64(1)
+	stackServices := []swarm.Service{
+		{
+			ID:   "svc-web",
+			Meta: swarm.Meta{Version: swarm.Version{Index: 4}},
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "demo_web"},
+				Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+			},
+		},
+		{
+			ID:   "svc-agent",
+			Meta: swarm.Meta{Version: swarm.Version{Index: 7}},
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "demo_agent"},
+				Mode:        swarm.ServiceMode{Global: &swarm.GlobalService{}},
+			},
+		},
+	}
+
+	newServer := func(t *testing.T, updates map[string]uint64) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/v1.41/info":
+				require.NoError(t, json.NewEncoder(w).Encode(system.Info{
+					Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: true},
+				}))
+			case r.Method == http.MethodGet && r.URL.Path == "/v1.41/services":
+				require.NoError(t, json.NewEncoder(w).Encode(stackServices))
+			case r.Method == http.MethodPost && r.URL.Path == "/v1.41/services/svc-web/update":
+				require.Equal(t, "4", r.URL.Query().Get("version"))
+				var spec swarm.ServiceSpec
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&spec))
+				updates["svc-web"] = *spec.Mode.Replicated.Replicas
+				require.NoError(t, json.NewEncoder(w).Encode(map[string]any{}))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				http.NotFound(w, r)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("scales services by short name", func(t *testing.T) {
+		updates := map[string]uint64{}
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, newServer(t, updates))}, nil, nil, nil, nil)
+
+		results, err := svc.ScaleStack(ctx, "0", "demo", map[string]uint64{"web": 5})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.True(t, results[0].Success)
+		require.Equal(t, "svc-web", results[0].ServiceID)
+		require.Equal(t, map[string]uint64{"svc-web": 5}, updates)
+	})
+
+	t.Run("rejects global services before mutating anything", func(t *testing.T) {
+		updates := map[string]uint64{}
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, newServer(t, updates))}, nil, nil, nil, nil)
+
+		_, err := svc.ScaleStack(ctx, "0", "demo", map[string]uint64{"web": 5, "agent": 2})
+		require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+		require.Empty(t, updates)
+	})
+
+	t.Run("rejects unknown services before mutating anything", func(t *testing.T) {
+		updates := map[string]uint64{}
+		svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, newServer(t, updates))}, nil, nil, nil, nil)
+
+		_, err := svc.ScaleStack(ctx, "0", "demo", map[string]uint64{"web": 5, "missing": 2})
+		require.ErrorIs(t, err, cerrdefs.ErrNotFound)
+		require.Empty(t, updates)
+	})
+}
+
+func TestSwarmService_DrainNode(t *testing.T) {
+	ctx := context.Background()
+
+	var drainedSpec swarm.NodeSpec
+	var mu sync.Mutex
+	taskCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.41/info":
+			require.NoError(t, json.NewEncoder(w).Encode(system.Info{
+				Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive, ControlAvailable: true},
+			}))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.41/nodes/node-1":
+			require.NoError(t, json.NewEncoder(w).Encode(swarm.Node{
+				ID:   "node-1",
+				Meta: swarm.Meta{Version: swarm.Version{Index: 5}},
+				Spec: swarm.NodeSpec{Availability: swarm.NodeAvailabilityActive},
+			}))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1.41/nodes/node-1/update":
+			require.Equal(t, "5", r.URL.Query().Get("version"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&drainedSpec))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.41/tasks":
+			require.Contains(t, r.URL.Query().Get("filters"), "node-1")
+			mu.Lock()
+			taskCalls++
+			state := swarm.TaskStateRunning
+			// Initial count plus the first poll see running tasks; afterwards they have moved.
+			if taskCalls > 2 {
+				state = swarm.TaskStateShutdown
+			}
+			mu.Unlock()
+			require.NoError(t, json.NewEncoder(w).Encode([]swarm.Task{
+				{ID: "task-1", NodeID: "node-1", Status: swarm.TaskStatus{State: state}},
+				{ID: "task-2", NodeID: "node-1", Status: swarm.TaskStatus{State: state}},
+				{ID: "task-3", NodeID: "node-1", Status: swarm.TaskStatus{State: swarm.TaskStateFailed}},
+			}))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
+
+	resp, err := svc.DrainNode(ctx, "0", "node-1", 10*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, swarm.NodeAvailabilityDrain, drainedSpec.Availability)
+	require.Equal(t, 2, resp.TasksMoved)
+	require.Zero(t, resp.RemainingTasks)
+	require.True(t, resp.Drained)
+}
+
+func TestSwarmService_RejectsRemoteEnvironmentWithoutCallingDocker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewSwarmService(&DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil)
+
+	_, err := svc.GetSwarmInfo(context.Background(), "env-remote")
+	require.ErrorIs(t, err, common.ErrSwarmRemoteEnvironment)
+	require.ErrorIs(t, err, common.ErrBadRequest)
+
+	_, err = svc.ForceUpdateService(context.Background(), "env-remote", "service-1")
+	require.ErrorIs(t, err, common.ErrSwarmRemoteEnvironment)
+}
+
+func TestMergeNodeLabelsInternal(t *testing.T) {
+	current := map[string]string{"zone": "a", "disk": "hdd", "gpu": "false"}
+
+	merged := mergeNodeLabelsInternal(current, map[string]string{"disk": "ssd", "rack": "r4"}, []string{"gpu", "missing"})
+
+	require.Equal(t, map[string]string{"zone": "a", "disk": "ssd", "rack": "r4"}, merged)
+	require.Equal(t, "hdd", current["disk"], "current labels must not be modified")
+
+	require.NotNil(t, mergeNodeLabelsInternal(nil, nil, []string{"zone"}))
+}
+
+func TestIsSwarmVersionConflictInternal(t *testing.T) {
+	require.True(t, isSwarmVersionConflictInternal(errors.New("Error response from daemon: rpc error: code = Unknown desc = update out of sequence")))
+	require.False(t, isSwarmVersionConflictInternal(errors.New("node not found")))
+	require.False(t, isSwarmVersionConflictInternal(nil))
+}
+
+func TestApplySwarmRolloutConfigInternal(t *testing.T) {
+	spec := swarm.ServiceSpec{
+		UpdateConfig: &swarm.UpdateConfig{Parallelism: 1, Delay: 5 * time.Second, FailureAction: swarm.UpdateFailureActionPause},
+	}
+
+	err := applySwarmRolloutConfigInternal(&spec.UpdateConfig, &swarmtypes.ServiceRolloutConfig{
+		Parallelism: new(int64(2)),
+		Order:       new(string(swarm.UpdateOrderStartFirst)),
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), spec.UpdateConfig.Parallelism)
+	require.Equal(t, 5*time.Second, spec.UpdateConfig.Delay, "unset fields keep the spec value")
+	require.Equal(t, swarm.UpdateOrderStartFirst, spec.UpdateConfig.Order)
+
+	require.NoError(t, applySwarmRolloutConfigInternal(&spec.RollbackConfig, &swarmtypes.ServiceRolloutConfig{
+		Delay:           new("1m"),
+		MaxFailureRatio: new(float32(0.2)),
+	}, true))
+	require.NotNil(t, spec.RollbackConfig)
+	require.Equal(t, time.Minute, spec.RollbackConfig.Delay)
+
+	require.NoError(t, applySwarmRolloutConfigInternal(&spec.UpdateConfig, nil, false))
+	require.Equal(t, uint64(2), spec.UpdateConfig.Parallelism)
+}
+
+func TestApplySwarmRolloutConfigInternal_RejectsInvalidValues(t *testing.T) {
+	cases := map[string]struct {
+		override swarmtypes.ServiceRolloutConfig
+		rollback bool
+	}{
+		"negative parallelism":        {override: swarmtypes.ServiceRolloutConfig{Parallelism: new(int64(-1))}},
+		"unknown failure action":      {override: swarmtypes.ServiceRolloutConfig{FailureAction: new("retry")}},
+		"rollback action on rollback": {override: swarmtypes.ServiceRolloutConfig{FailureAction: new(string(swarm.UpdateFailureActionRollback))}, rollback: true},
+		"bad delay":                   {override: swarmtypes.ServiceRolloutConfig{Delay: new("soon")}},
+		"negative monitor":            {override: swarmtypes.ServiceRolloutConfig{Monitor: new("-5s")}},
+		"ratio above one":             {override: swarmtypes.ServiceRolloutConfig{MaxFailureRatio: new(float32(1.5))}},
+		"unknown order":               {override: swarmtypes.ServiceRolloutConfig{Order: new("random")}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var cfg *swarm.UpdateConfig
+			err := applySwarmRolloutConfigInternal(&cfg, &tc.override, tc.rollback)
+			require.Error(t, err)
+			require.True(t, cerrdefs.IsInvalidArgument(err))
+			require.Nil(t, cfg)
+		})
+	}
+}
+
+func TestSwarmStartFirstHealthWarningInternal(t *testing.T) {
+	startFirst := &swarm.UpdateConfig{Order: swarm.UpdateOrderStartFirst}
+
+	_, warn := swarmStartFirstHealthWarningInternal(swarm.ServiceSpec{})
+	require.False(t, warn, "stop-first is the default and never warns")
+
+	_, warn = swarmStartFirstHealthWarningInternal(swarm.ServiceSpec{
+		UpdateConfig: startFirst,
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}}}},
+	})
+	require.False(t, warn)
+
+	_, warn = swarmStartFirstHealthWarningInternal(swarm.ServiceSpec{
+		UpdateConfig: startFirst,
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Healthcheck: &container.HealthConfig{Test: []string{"NONE"}}}},
+	})
+	require.True(t, warn)
+
+	_, warn = swarmStartFirstHealthWarningInternal(swarm.ServiceSpec{UpdateConfig: startFirst, TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}}})
+	require.True(t, warn)
+}
+
+func TestSwarmService_ConfigPaginationConfigSearchesLabelsAndSortsByName(t *testing.T) {
+	svc := &SwarmService{}
+	newConfig := func(name string, labels map[string]string) swarmtypes.ConfigSummary {
+		return swarmtypes.ConfigSummary{ID: name + "-id", Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: name, Labels: labels}}}
+	}
+	items := []swarmtypes.ConfigSummary{
+		newConfig("web-v2", map[string]string{"app": "web"}),
+		newConfig("db", map[string]string{"app": "postgres"}),
+		newConfig("web-v1", map[string]string{"app": "web"}),
+	}
+
+	params := pagination.QueryParams{
+		SearchQuery: pagination.SearchQuery{Search: "app=web"},
+		SortParams:  pagination.ParseSortParams("name", "asc"),
+		Params:      pagination.Params{Start: 0, Limit: 1},
+	}
+	result := pagination.SearchOrderAndPaginate(items, params, svc.ConfigPaginationConfig())
+
+	require.Equal(t, int64(2), result.TotalCount)
+	require.Len(t, result.Items, 1)
+	require.Equal(t, "web-v1", result.Items[0].Spec.Name)
+}
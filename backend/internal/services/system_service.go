@@ -1,23 +1,36 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/config"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	activitylib "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/activity"
+	systemlib "github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/system"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	containertypes "github.com/getarcaneapp/arcane/types/v2/container"
 	"github.com/getarcaneapp/arcane/types/v2/system"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/swarm"
 	"github.com/moby/moby/client"
+	"github.com/samber/hot"
 	"github.com/samber/mo"
+	"github.com/shirou/gopsutil/v4/disk"
+	"go.getarcane.app/streams/bus"
 	"go.getarcane.app/updater/pkg/labels"
 	"golang.org/x/sync/errgroup"
 )
@@ -31,6 +44,8 @@ type SystemService struct {
 	networkService   *NetworkService
 	settingsService  *SettingsService
 	activityService  *ActivityService
+	gpuMonitor       *systemlib.GPUMonitor
+	diskUsageCache   *hot.HotCache[string, *system.DiskUsage]
 	pruneMu          sync.Mutex
 	runningPrunes    map[string]string
 }
@@ -44,8 +59,9 @@ func NewSystemService(
 	networkService *NetworkService,
 	settingsService *SettingsService,
 	activityService *ActivityService,
+	cfg *config.Config,
 ) *SystemService {
-	return &SystemService{
+	s := &SystemService{
 		db:               db,
 		dockerService:    dockerService,
 		containerService: containerService,
@@ -55,22 +71,87 @@ func NewSystemService(
 		settingsService:  settingsService,
 		activityService:  activityService,
 		runningPrunes:    make(map[string]string),
+		diskUsageCache: hot.NewHotCache[string, *system.DiskUsage](hot.LRU, 1).
+			WithTTL(diskUsageCacheTTL).
+			Build(),
+	}
+	if cfg != nil {
+		s.gpuMonitor = systemlib.NewGPUMonitor(cfg.GPUMonitoringEnabled, cfg.GPUType).
+			WithVendorOverride(s.gpuVendorOverrideInternal).
+			WithToolConfig(s.gpuToolConfigInternal)
+	}
+	return s
+}
+
+// GPUMonitor returns the process-wide GPU monitor so every handler shares one
+// detection cache. Returns nil when the service was built without config.
+func (s *SystemService) GPUMonitor() *systemlib.GPUMonitor {
+	if s == nil {
+		return nil
+	}
+	return s.gpuMonitor
+}
+
+// gpuVendorOverrideInternal reads the systemGpuVendor setting on each poll so changes
+// apply without restarting.
+func (s *SystemService) gpuVendorOverrideInternal(ctx context.Context) string {
+	if s.settingsService == nil {
+		return ""
+	}
+	return s.settingsService.GetStringSetting(ctx, "systemGpuVendor", "auto")
+}
+
+// gpuToolConfigInternal reads the GPU tool path and nvidia-smi field settings on each
+// poll. Invalid extra fields are dropped rather than failing the whole poll.
+func (s *SystemService) gpuToolConfigInternal(ctx context.Context) systemlib.GPUToolConfig {
+	if s.settingsService == nil {
+		return systemlib.GPUToolConfig{}
+	}
+	extraFields, err := systemlib.ParseNvidiaQueryFields(s.settingsService.GetStringSetting(ctx, "systemNvidiaSmiExtraFields", ""))
+	if err != nil {
+		slog.WarnContext(ctx, "Ignoring invalid nvidia-smi extra query fields", "error", err)
+	}
+	return systemlib.GPUToolConfig{
+		NvidiaSmiPath:     s.settingsService.GetStringSetting(ctx, "systemNvidiaSmiPath", ""),
+		IntelGpuTopPath:   s.settingsService.GetStringSetting(ctx, "systemIntelGpuTopPath", ""),
+		TegrastatsPath:    s.settingsService.GetStringSetting(ctx, "systemTegrastatsPath", ""),
+		NvidiaExtraFields: extraFields,
 	}
 }
 
+// diskUsageCacheTTL bounds how often GetDiskUsage asks the daemon to walk every
+// layer and volume, which is slow on hosts with large volumes.
+const diskUsageCacheTTL = 30 * time.Second
+
+const diskUsageCacheKey = "local"
+
 var systemUser = models.User{
 	Username: "System",
 }
 
 func (s *SystemService) PruneAll(ctx context.Context, environmentID string, req system.PruneAllRequest) (*system.PruneAllResult, bool, error) {
+	return s.PruneAllWithProgress(ctx, environmentID, req, nil)
+}
+
+// PruneAllWithProgress runs a prune synchronously like PruneAll and calls onProgress as
+// each stage starts and finishes, ending with a "done" event that carries the result.
+// onProgress calls are serialized, so the callback may write to a single connection.
+// Cancelling ctx stops the remaining Docker prune calls.
+func (s *SystemService) PruneAllWithProgress(ctx context.Context, environmentID string, req system.PruneAllRequest, onProgress func(system.PruneProgressEvent)) (*system.PruneAllResult, bool, error) {
 	slog.InfoContext(ctx, "Starting selective prune operation",
 		"containers", req.Containers,
 		"images", req.Images,
 		"volumes", req.Volumes,
 		"networks", req.Networks,
 		"build_cache", req.BuildCache,
+		"label_filters", req.LabelFilters,
+		"until", req.Until,
 	)
 
+	if err := ValidatePruneRequest(req); err != nil {
+		return nil, false, err
+	}
+
 	prune := s.beginSystemPruneInternal(ctx, environmentID, req)
 	activityID := prune.activityID
 	result := &system.PruneAllResult{Success: true, ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()}
@@ -82,17 +163,23 @@ func (s *SystemService) PruneAll(ctx context.Context, environmentID string, req
 	defer s.finishSystemPruneInternal(environmentID)
 
 	ctx = s.activityService.Track(ctx, activityID)
-	s.runSystemPruneInternal(ctx, req, activityID, result)
+	s.runSystemPruneInternal(ctx, req, activityID, result, newSystemPruneReporterInternal(onProgress))
 
 	return result, true, nil
 }
 
-func (s *SystemService) StartPruneAll(ctx context.Context, environmentID string, req system.PruneAllRequest) *system.PruneAllResult {
+// StartPruneAll validates req and runs the prune in the background, returning immediately
+// with the tracking activity ID.
+func (s *SystemService) StartPruneAll(ctx context.Context, environmentID string, req system.PruneAllRequest) (*system.PruneAllResult, error) {
+	if err := ValidatePruneRequest(req); err != nil {
+		return nil, err
+	}
+
 	prune := s.beginSystemPruneInternal(ctx, environmentID, req)
 	activityID := prune.activityID
 	if prune.started.IsAbsent() {
 		slog.InfoContext(ctx, "System prune already running", "environmentId", environmentID, "activityId", activityID)
-		return &system.PruneAllResult{Success: true, ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()}
+		return &system.PruneAllResult{Success: true, ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()}, nil
 	}
 
 	backgroundCtx := utils.ActivityRuntimeContext(ctx, nil)
@@ -102,10 +189,80 @@ func (s *SystemService) StartPruneAll(ctx context.Context, environmentID string,
 		defer s.finishSystemPruneInternal(environmentID)
 
 		result := &system.PruneAllResult{Success: true, ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()}
-		s.runSystemPruneInternal(backgroundCtx, req, activityID, result)
+		s.runSystemPruneInternal(backgroundCtx, req, activityID, result, nil)
 	}()
 
-	return &system.PruneAllResult{Success: true, ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()}
+	return &system.PruneAllResult{Success: true, ActivityID: mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()}, nil
+}
+
+// pruneUntilLayouts are the absolute timestamp layouts Docker accepts for the until filter.
+var pruneUntilLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// ValidatePruneRequest checks the request-wide label and until filters before any
+// Docker prune call is issued, so a malformed filter never falls back to an
+// unfiltered prune.
+func ValidatePruneRequest(req system.PruneAllRequest) error {
+	for key := range req.LabelFilters {
+		name := strings.TrimPrefix(key, "!")
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, "= \t\n") {
+			return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid label filter key %q", key)
+		}
+	}
+	if len(req.LabelFilters) > 0 && req.BuildCache != nil && req.BuildCache.Mode != system.PruneBuildCacheModeNone {
+		return errors.WrapIf(cerrdefs.ErrInvalidArgument, "label filters are not supported for build cache pruning")
+	}
+
+	if until := strings.TrimSpace(req.Until); until != "" {
+		if !isValidPruneUntilInternal(until) {
+			return errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid until filter %q: expected a duration (e.g. 24h) or timestamp", req.Until)
+		}
+		if req.Volumes != nil && req.Volumes.Mode != system.PruneVolumeModeNone {
+			return errors.WrapIf(cerrdefs.ErrInvalidArgument, "the until filter is not supported for volume pruning")
+		}
+	}
+	return nil
+}
+
+func isValidPruneUntilInternal(value string) bool {
+	if _, err := time.ParseDuration(value); err == nil {
+		return true
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+	for _, layout := range pruneUntilLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPruneLabelFiltersInternal adds label / label! terms for each request label filter.
+func applyPruneLabelFiltersInternal(filterArgs client.Filters, labels map[string]string) client.Filters {
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		term := "label"
+		name := key
+		if rest, ok := strings.CutPrefix(key, "!"); ok {
+			term = "label!"
+			name = rest
+		}
+		value := name
+		if labels[key] != "" {
+			value = name + "=" + labels[key]
+		}
+		filterArgs = filterArgs.Add(term, value)
+	}
+	return filterArgs
+}
+
+// pruneUntilInternal returns the until filter for a category: its own value in olderThan
+// mode, otherwise the request-wide one.
+func pruneUntilInternal(olderThan bool, categoryUntil, requestUntil string) string {
+	if olderThan {
+		return categoryUntil
+	}
+	return strings.TrimSpace(requestUntil)
 }
 
 type systemPruneBeginResult struct {
@@ -133,17 +290,58 @@ func (s *SystemService) finishSystemPruneInternal(environmentID string) {
 	delete(s.runningPrunes, environmentID)
 }
 
-func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.PruneAllRequest, activityID string, result *system.PruneAllResult) {
+// systemPruneReporter serializes progress callbacks from the parallel prune stages.
+// A nil reporter discards events.
+type systemPruneReporter struct {
+	mu         sync.Mutex
+	onProgress func(system.PruneProgressEvent)
+}
+
+func newSystemPruneReporterInternal(onProgress func(system.PruneProgressEvent)) *systemPruneReporter {
+	if onProgress == nil {
+		return nil
+	}
+	return &systemPruneReporter{onProgress: onProgress}
+}
+
+func (r *systemPruneReporter) emitInternal(event system.PruneProgressEvent) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onProgress(event)
+}
+
+// startSystemPruneStageInternal records the stage on the activity and reports it as running.
+func (s *SystemService) startSystemPruneStageInternal(ctx context.Context, activityID string, reporter *systemPruneReporter, stage system.PruneProgressStage, message string, progress int) {
+	s.appendSystemPruneActivityMessageInternal(ctx, activityID, message, progress)
+	reporter.emitInternal(system.PruneProgressEvent{Stage: stage, Status: system.PruneProgressStatusRunning, Message: message, Progress: progress})
+}
+
+// finishSystemPruneStageInternal reports the outcome of a single stage.
+func finishSystemPruneStageInternal(reporter *systemPruneReporter, stage system.PruneProgressStage, progress, itemsRemoved int, spaceReclaimed uint64, err error) {
+	event := system.PruneProgressEvent{Stage: stage, Status: system.PruneProgressStatusCompleted, Progress: progress, ItemsRemoved: itemsRemoved, SpaceReclaimed: spaceReclaimed}
+	if err != nil {
+		event.Status = system.PruneProgressStatusFailed
+		event.Error = err.Error()
+	}
+	reporter.emitInternal(event)
+}
+
+func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.PruneAllRequest, activityID string, result *system.PruneAllResult, reporter *systemPruneReporter) {
 	var mu sync.Mutex
 
 	// 1. Prune Containers first (sequential) as it may free up other resources
 	if req.Containers != nil && req.Containers.Mode != system.PruneContainerModeNone {
-		s.appendSystemPruneActivityMessageInternal(ctx, activityID, "Pruning containers", 15)
+		s.startSystemPruneStageInternal(ctx, activityID, reporter, system.PruneProgressStageContainers, "Pruning containers", 15)
 		slog.InfoContext(ctx, "Pruning containers...", "mode", req.Containers.Mode, "until", req.Containers.Until)
-		if err := s.pruneContainersInternal(ctx, *req.Containers, result); err != nil {
+		err := s.pruneContainersInternal(ctx, *req.Containers, req, result)
+		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("Container pruning failed: %v", err))
 			result.Success = false
 		}
+		finishSystemPruneStageInternal(reporter, system.PruneProgressStageContainers, 30, len(result.ContainersPruned), result.ContainerSpaceReclaimed, err)
 	}
 
 	// 2. Prune other resources in parallel
@@ -151,10 +349,11 @@ func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.P
 
 	if req.Images != nil && req.Images.Mode != system.PruneImageModeNone {
 		g.Go(func() error {
-			s.appendSystemPruneActivityMessageInternal(groupCtx, activityID, "Pruning images", 40)
+			s.startSystemPruneStageInternal(groupCtx, activityID, reporter, system.PruneProgressStageImages, "Pruning images", 40)
 			slog.InfoContext(groupCtx, "Pruning images...", "mode", req.Images.Mode, "until", req.Images.Until)
 			localResult := &system.PruneAllResult{}
-			if err := s.pruneImagesInternal(groupCtx, *req.Images, localResult); err != nil {
+			err := s.pruneImagesInternal(groupCtx, *req.Images, req, localResult)
+			if err != nil {
 				mu.Lock()
 				result.Errors = append(result.Errors, fmt.Sprintf("Image pruning failed: %v", err))
 				result.Success = false
@@ -166,16 +365,18 @@ func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.P
 				result.ImageSpaceReclaimed += localResult.ImageSpaceReclaimed
 				mu.Unlock()
 			}
+			finishSystemPruneStageInternal(reporter, system.PruneProgressStageImages, 75, len(localResult.ImagesDeleted), localResult.ImageSpaceReclaimed, err)
 			return nil
 		})
 	}
 
 	if req.BuildCache != nil && req.BuildCache.Mode != system.PruneBuildCacheModeNone {
 		g.Go(func() error {
-			s.appendSystemPruneActivityMessageInternal(groupCtx, activityID, "Pruning build cache", 45)
+			s.startSystemPruneStageInternal(groupCtx, activityID, reporter, system.PruneProgressStageBuildCache, "Pruning build cache", 45)
 			slog.InfoContext(groupCtx, "Pruning build cache...", "mode", req.BuildCache.Mode, "until", req.BuildCache.Until)
 			localResult := &system.PruneAllResult{}
-			if err := s.pruneBuildCacheInternal(groupCtx, *req.BuildCache, localResult); err != nil {
+			err := s.pruneBuildCacheInternal(groupCtx, *req.BuildCache, req, localResult)
+			if err != nil {
 				slog.WarnContext(groupCtx, "Build cache pruning encountered an error", "error", err.Error())
 				// Surface the failure like every other prune type so a build cache that
 				// could not be reclaimed is reported instead of silently left behind.
@@ -189,16 +390,18 @@ func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.P
 				result.BuildCacheSpaceReclaimed += localResult.BuildCacheSpaceReclaimed
 				mu.Unlock()
 			}
+			finishSystemPruneStageInternal(reporter, system.PruneProgressStageBuildCache, 80, 0, localResult.BuildCacheSpaceReclaimed, err)
 			return nil
 		})
 	}
 
 	if req.Volumes != nil && req.Volumes.Mode != system.PruneVolumeModeNone {
 		g.Go(func() error {
-			s.appendSystemPruneActivityMessageInternal(groupCtx, activityID, "Pruning volumes", 55)
+			s.startSystemPruneStageInternal(groupCtx, activityID, reporter, system.PruneProgressStageVolumes, "Pruning volumes", 55)
 			slog.InfoContext(groupCtx, "Pruning volumes...", "mode", req.Volumes.Mode)
 			localResult := &system.PruneAllResult{}
-			if err := s.pruneVolumesInternal(groupCtx, *req.Volumes, localResult); err != nil {
+			err := s.pruneVolumesInternal(groupCtx, *req.Volumes, req, localResult)
+			if err != nil {
 				mu.Lock()
 				result.Errors = append(result.Errors, fmt.Sprintf("Volume pruning failed: %v", err))
 				result.Success = false
@@ -210,16 +413,18 @@ func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.P
 				result.VolumeSpaceReclaimed += localResult.VolumeSpaceReclaimed
 				mu.Unlock()
 			}
+			finishSystemPruneStageInternal(reporter, system.PruneProgressStageVolumes, 85, len(localResult.VolumesDeleted), localResult.VolumeSpaceReclaimed, err)
 			return nil
 		})
 	}
 
 	if req.Networks != nil && req.Networks.Mode != system.PruneNetworkModeNone {
 		g.Go(func() error {
-			s.appendSystemPruneActivityMessageInternal(groupCtx, activityID, "Pruning networks", 65)
+			s.startSystemPruneStageInternal(groupCtx, activityID, reporter, system.PruneProgressStageNetworks, "Pruning networks", 65)
 			slog.InfoContext(groupCtx, "Pruning networks...", "mode", req.Networks.Mode, "until", req.Networks.Until)
 			localResult := &system.PruneAllResult{}
-			if err := s.pruneNetworksInternal(groupCtx, *req.Networks, localResult); err != nil {
+			err := s.pruneNetworksInternal(groupCtx, *req.Networks, req, localResult)
+			if err != nil {
 				mu.Lock()
 				result.Errors = append(result.Errors, fmt.Sprintf("Network pruning failed: %v", err))
 				result.Success = false
@@ -229,6 +434,7 @@ func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.P
 				result.NetworksDeleted = append(result.NetworksDeleted, localResult.NetworksDeleted...)
 				mu.Unlock()
 			}
+			finishSystemPruneStageInternal(reporter, system.PruneProgressStageNetworks, 90, len(localResult.NetworksDeleted), 0, err)
 			return nil
 		})
 	}
@@ -239,6 +445,14 @@ func (s *SystemService) runSystemPruneInternal(ctx context.Context, req system.P
 
 	slog.InfoContext(ctx, "Selective prune operation completed", "success", result.Success, "containers_pruned", len(result.ContainersPruned), "images_deleted", len(result.ImagesDeleted), "volumes_deleted", len(result.VolumesDeleted), "networks_deleted", len(result.NetworksDeleted), "space_reclaimed", result.SpaceReclaimed, "error_count", len(result.Errors))
 	s.completeSystemPruneActivityInternal(ctx, activityID, result)
+
+	final := system.PruneProgressEvent{Stage: system.PruneProgressStageDone, Status: system.PruneProgressStatusCompleted, Message: "System prune completed", Progress: 100, SpaceReclaimed: result.SpaceReclaimed, Result: result}
+	if !result.Success {
+		final.Status = system.PruneProgressStatusFailed
+		final.Message = "System prune completed with errors"
+		final.Error = strings.Join(result.Errors, "; ")
+	}
+	reporter.emitInternal(final)
 }
 
 func (s *SystemService) startSystemPruneActivityInternal(ctx context.Context, environmentID string, req system.PruneAllRequest) string {
@@ -413,7 +627,7 @@ func (s *SystemService) StopAllContainers(ctx context.Context, environmentID str
 			return !labels.IsArcaneContainer(c.Labels)
 		},
 		func(ctx context.Context, id string) error {
-			return s.containerService.StopContainer(ctx, id, systemUser)
+			return s.containerService.StopContainer(ctx, id, ContainerStopOptions{}, systemUser)
 		})
 	result.ActivityID = mo.EmptyableToOption(strings.TrimSpace(activityID)).ToPointer()
 	s.completeSystemContainerActivityInternal(ctx, activityID, "Stopped all containers", result)
@@ -459,19 +673,20 @@ func (s *SystemService) completeSystemContainerActivityInternal(ctx context.Cont
 	}
 }
 
-func (s *SystemService) pruneContainersInternal(ctx context.Context, options system.PruneContainersOptions, result *system.PruneAllResult) error {
+func (s *SystemService) pruneContainersInternal(ctx context.Context, options system.PruneContainersOptions, req system.PruneAllRequest, result *system.PruneAllResult) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
 
 	filterArgs := make(client.Filters)
-	if options.Mode == system.PruneContainerModeOlderThan {
-		if strings.TrimSpace(options.Until) == "" {
-			return errors.New("container prune mode olderThan requires until")
-		}
-		filterArgs = filterArgs.Add("until", options.Until)
+	if options.Mode == system.PruneContainerModeOlderThan && strings.TrimSpace(options.Until) == "" {
+		return errors.New("container prune mode olderThan requires until")
+	}
+	if until := pruneUntilInternal(options.Mode == system.PruneContainerModeOlderThan, options.Until, req.Until); until != "" {
+		filterArgs = filterArgs.Add("until", until)
 	}
+	filterArgs = applyPruneLabelFiltersInternal(filterArgs, req.LabelFilters)
 
 	report, err := dockerClient.ContainerPrune(ctx, client.ContainerPruneOptions{Filters: filterArgs})
 	if err != nil {
@@ -484,7 +699,7 @@ func (s *SystemService) pruneContainersInternal(ctx context.Context, options sys
 	return nil
 }
 
-func (s *SystemService) pruneImagesInternal(ctx context.Context, options system.PruneImagesOptions, result *system.PruneAllResult) error {
+func (s *SystemService) pruneImagesInternal(ctx context.Context, options system.PruneImagesOptions, req system.PruneAllRequest, result *system.PruneAllResult) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
@@ -503,10 +718,13 @@ func (s *SystemService) pruneImagesInternal(ctx context.Context, options system.
 			return errors.New("image prune mode olderThan requires until")
 		}
 		filterArgs = filterArgs.Add("dangling", "false")
-		filterArgs = filterArgs.Add("until", options.Until)
 	default:
 		return errors.Errorf("unsupported image prune mode: %s", options.Mode)
 	}
+	if until := pruneUntilInternal(options.Mode == system.PruneImageModeOlderThan, options.Until, req.Until); until != "" {
+		filterArgs = filterArgs.Add("until", until)
+	}
+	filterArgs = applyPruneLabelFiltersInternal(filterArgs, req.LabelFilters)
 
 	report, err := dockerClient.ImagePrune(ctx, client.ImagePruneOptions{Filters: filterArgs})
 	if err != nil {
@@ -538,7 +756,7 @@ func (s *SystemService) pruneImagesInternal(ctx context.Context, options system.
 	return nil
 }
 
-func (s *SystemService) pruneBuildCacheInternal(ctx context.Context, options system.PruneBuildCacheOptions, result *system.PruneAllResult) error {
+func (s *SystemService) pruneBuildCacheInternal(ctx context.Context, options system.PruneBuildCacheOptions, req system.PruneAllRequest, result *system.PruneAllResult) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		result.Errors = append(result.Errors, errors.WrapIf(err, "build cache pruning failed (connection)").Error())
@@ -549,12 +767,12 @@ func (s *SystemService) pruneBuildCacheInternal(ctx context.Context, options sys
 	pruneOptions := client.BuildCachePruneOptions{
 		All: options.Mode == system.PruneBuildCacheModeAll,
 	}
-	if options.Mode == system.PruneBuildCacheModeOlderThan {
-		if strings.TrimSpace(options.Until) == "" {
-			return errors.New("build cache prune mode olderThan requires until")
-		}
+	if options.Mode == system.PruneBuildCacheModeOlderThan && strings.TrimSpace(options.Until) == "" {
+		return errors.New("build cache prune mode olderThan requires until")
+	}
+	if until := pruneUntilInternal(options.Mode == system.PruneBuildCacheModeOlderThan, options.Until, req.Until); until != "" {
 		pruneOptions.Filters = make(client.Filters)
-		pruneOptions.Filters = pruneOptions.Filters.Add("until", options.Until)
+		pruneOptions.Filters = pruneOptions.Filters.Add("until", until)
 	}
 
 	slog.DebugContext(ctx, "starting build cache pruning", "mode", options.Mode, "until", options.Until)
@@ -572,9 +790,9 @@ func (s *SystemService) pruneBuildCacheInternal(ctx context.Context, options sys
 	return nil
 }
 
-func (s *SystemService) pruneVolumesInternal(ctx context.Context, options system.PruneVolumesOptions, result *system.PruneAllResult) error {
+func (s *SystemService) pruneVolumesInternal(ctx context.Context, options system.PruneVolumesOptions, req system.PruneAllRequest, result *system.PruneAllResult) error {
 	allVolumes := options.Mode == system.PruneVolumeModeAll
-	report, err := s.volumeService.PruneVolumesWithOptions(ctx, allVolumes)
+	report, err := s.volumeService.PruneVolumesWithFilters(ctx, allVolumes, req.LabelFilters)
 	if err != nil {
 		return err
 	}
@@ -587,19 +805,20 @@ func (s *SystemService) pruneVolumesInternal(ctx context.Context, options system
 	return nil
 }
 
-func (s *SystemService) pruneNetworksInternal(ctx context.Context, options system.PruneNetworksOptions, result *system.PruneAllResult) error {
+func (s *SystemService) pruneNetworksInternal(ctx context.Context, options system.PruneNetworksOptions, req system.PruneAllRequest, result *system.PruneAllResult) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to connect to Docker")
 	}
 
 	filterArgs := make(client.Filters)
-	if options.Mode == system.PruneNetworkModeOlderThan {
-		if strings.TrimSpace(options.Until) == "" {
-			return errors.New("network prune mode olderThan requires until")
-		}
-		filterArgs = filterArgs.Add("until", options.Until)
+	if options.Mode == system.PruneNetworkModeOlderThan && strings.TrimSpace(options.Until) == "" {
+		return errors.New("network prune mode olderThan requires until")
+	}
+	if until := pruneUntilInternal(options.Mode == system.PruneNetworkModeOlderThan, options.Until, req.Until); until != "" {
+		filterArgs = filterArgs.Add("until", until)
 	}
+	filterArgs = applyPruneLabelFiltersInternal(filterArgs, req.LabelFilters)
 
 	report, err := dockerClient.NetworkPrune(ctx, client.NetworkPruneOptions{Filters: filterArgs})
 	if err != nil {
@@ -624,3 +843,420 @@ func (s *SystemService) GetDiskUsagePath(ctx context.Context) string {
 	}
 	return path
 }
+
+// readinessCheckTimeout bounds each readiness probe so a hung subsystem shows up
+// as a failed check instead of stalling the whole report.
+const readinessCheckTimeout = 5 * time.Second
+
+// CheckReadiness probes the subsystems an environment depends on: the Docker
+// daemon, the database, free space on the disk usage path, and, when the
+// node is part of a swarm, the swarm control plane. Docker, database and disk
+// are critical; an unavailable swarm control plane is reported as degraded
+// because worker nodes never have one.
+func (s *SystemService) CheckReadiness(ctx context.Context, minFreeDiskPercent float64) system.ReadinessResponse {
+	checks := []system.ReadinessCheck{
+		s.checkDockerReadinessInternal(ctx),
+		s.checkDatabaseReadinessInternal(ctx),
+		s.checkDiskReadinessInternal(ctx, minFreeDiskPercent),
+		s.checkSwarmReadinessInternal(ctx),
+	}
+
+	response := system.ReadinessResponse{Ready: true, Checks: checks}
+	for _, check := range checks {
+		if check.Critical && check.Status == system.ReadinessStatusFail {
+			response.Ready = false
+			response.Failing = append(response.Failing, check.Name)
+		}
+	}
+	return response
+}
+
+func (s *SystemService) checkDockerReadinessInternal(ctx context.Context) system.ReadinessCheck {
+	check := system.ReadinessCheck{Name: "docker", Status: system.ReadinessStatusOK, Critical: true}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		check.Status, check.Message = system.ReadinessStatusFail, errors.WrapIf(err, "failed to connect to Docker").Error()
+		return check
+	}
+	if _, err := dockerClient.Ping(ctx, client.PingOptions{}); err != nil {
+		check.Status, check.Message = system.ReadinessStatusFail, errors.WrapIf(err, "Docker is not responsive").Error()
+	}
+	return check
+}
+
+func (s *SystemService) checkDatabaseReadinessInternal(ctx context.Context) system.ReadinessCheck {
+	check := system.ReadinessCheck{Name: "database", Status: system.ReadinessStatusOK, Critical: true}
+	if s.db == nil {
+		check.Status = system.ReadinessStatusSkipped
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	sqlDB, err := s.db.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		check.Status, check.Message = system.ReadinessStatusFail, errors.WrapIf(err, "database is not reachable").Error()
+	}
+	return check
+}
+
+func (s *SystemService) checkDiskReadinessInternal(ctx context.Context, minFreeDiskPercent float64) system.ReadinessCheck {
+	check := system.ReadinessCheck{Name: "disk", Status: system.ReadinessStatusOK, Critical: true}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	path := s.GetDiskUsagePath(ctx)
+
+	// statfs ignores the context, and a hung network mount would block it
+	// indefinitely, so run it aside and stop waiting at the deadline.
+	type diskUsageResult struct {
+		usage *disk.UsageStat
+		err   error
+	}
+	done := make(chan diskUsageResult, 1)
+	go func() {
+		usage, err := disk.UsageWithContext(ctx, path)
+		done <- diskUsageResult{usage: usage, err: err}
+	}()
+
+	var usage *disk.UsageStat
+	select {
+	case <-ctx.Done():
+		check.Status, check.Message = system.ReadinessStatusFail, fmt.Sprintf("timed out reading disk usage for %s", path)
+		return check
+	case result := <-done:
+		if result.err != nil || result.usage == nil || result.usage.Total == 0 {
+			check.Status, check.Message = system.ReadinessStatusFail, fmt.Sprintf("failed to read disk usage for %s", path)
+			return check
+		}
+		usage = result.usage
+	}
+
+	freePercent := float64(usage.Free) / float64(usage.Total) * 100
+	if freePercent < minFreeDiskPercent {
+		check.Status = system.ReadinessStatusFail
+		check.Message = fmt.Sprintf("%.1f%% free on %s, below the %.1f%% threshold", freePercent, path, minFreeDiskPercent)
+	}
+	return check
+}
+
+func (s *SystemService) checkSwarmReadinessInternal(ctx context.Context) system.ReadinessCheck {
+	check := system.ReadinessCheck{Name: "swarm", Status: system.ReadinessStatusOK}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		check.Status, check.Message = system.ReadinessStatusSkipped, "Docker is unavailable"
+		return check
+	}
+	infoResult, err := dockerClient.Info(ctx, client.InfoOptions{})
+	if err != nil {
+		check.Status, check.Message = system.ReadinessStatusSkipped, errors.WrapIf(err, "failed to get Docker info").Error()
+		return check
+	}
+
+	switch swarmInfo := infoResult.Info.Swarm; {
+	case swarmInfo.LocalNodeState == swarm.LocalNodeStateInactive || swarmInfo.LocalNodeState == "":
+		check.Status, check.Message = system.ReadinessStatusSkipped, "swarm mode is not enabled"
+	case swarmInfo.LocalNodeState != swarm.LocalNodeStateActive:
+		check.Status = system.ReadinessStatusDegraded
+		check.Message = cmp.Or(swarmInfo.Error, fmt.Sprintf("swarm node state is %s", swarmInfo.LocalNodeState))
+	case !swarmInfo.ControlAvailable:
+		check.Status, check.Message = system.ReadinessStatusDegraded, "swarm control plane is not available on this node"
+	}
+	return check
+}
+
+// GetDiskUsage returns Docker's disk usage per resource kind, the equivalent of
+// `docker system df -v`. Results are cached for diskUsageCacheTTL; refresh bypasses
+// the cache and stores the new snapshot.
+func (s *SystemService) GetDiskUsage(ctx context.Context, refresh bool) (*system.DiskUsage, error) {
+	if !refresh {
+		if cached, ok, _ := s.diskUsageCache.Get(diskUsageCacheKey); ok && cached != nil {
+			return cached, nil
+		}
+	}
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to connect to Docker")
+	}
+
+	du, err := dockerClient.DiskUsage(ctx, client.DiskUsageOptions{
+		Containers: true,
+		Images:     true,
+		BuildCache: true,
+		Volumes:    true,
+		Verbose:    true,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get disk usage")
+	}
+
+	usage := buildDiskUsageInternal(du)
+	s.diskUsageCache.Set(diskUsageCacheKey, usage)
+	return usage, nil
+}
+
+// buildDiskUsageInternal derives the totals from the verbose item lists so the
+// numbers stay consistent with the per-image and per-volume breakdowns. The
+// image total is the exception and comes from the daemon's layer store size.
+func buildDiskUsageInternal(du client.DiskUsageResult) *system.DiskUsage {
+	usage := &system.DiskUsage{
+		ImageItems:  make([]system.ImageDiskUsage, 0, len(du.Images.Items)),
+		VolumeItems: make([]system.VolumeDiskUsage, 0, len(du.Volumes.Items)),
+		CollectedAt: time.Now().UTC(),
+	}
+
+	var sharedMax int64
+	for _, img := range du.Images.Items {
+		unique := max(img.Size-img.SharedSize, 0)
+		usage.Images.TotalCount++
+		// Shared layers are counted once, matching the docker CLI.
+		usage.Images.TotalSize += unique
+		sharedMax = max(sharedMax, img.SharedSize)
+		if img.Containers > 0 {
+			usage.Images.ActiveCount++
+		} else {
+			usage.Images.Reclaimable += unique
+		}
+		usage.ImageItems = append(usage.ImageItems, system.ImageDiskUsage{
+			ID:         img.ID,
+			RepoTags:   img.RepoTags,
+			Size:       img.Size,
+			SharedSize: img.SharedSize,
+			UniqueSize: unique,
+			Containers: img.Containers,
+			Created:    img.Created,
+		})
+	}
+	// The daemon reports the real size of the layer store (LayersSize), which
+	// counts every shared layer once. Adding the largest shared size to the
+	// unique sizes undercounts when images share different layers, so that
+	// is only used when the daemon leaves the total out.
+	if du.Images.TotalSize > 0 {
+		usage.Images.TotalSize = du.Images.TotalSize
+	} else {
+		usage.Images.TotalSize += sharedMax
+	}
+
+	for _, c := range du.Containers.Items {
+		usage.Containers.TotalCount++
+		usage.Containers.TotalSize += c.SizeRw
+		if c.State == "running" {
+			usage.Containers.ActiveCount++
+		} else {
+			usage.Containers.Reclaimable += c.SizeRw
+		}
+	}
+
+	for _, v := range du.Volumes.Items {
+		size, refCount := int64(-1), int64(-1)
+		if v.UsageData != nil {
+			size, refCount = v.UsageData.Size, v.UsageData.RefCount
+		}
+		usage.Volumes.TotalCount++
+		if size > 0 {
+			usage.Volumes.TotalSize += size
+		}
+		if refCount > 0 {
+			usage.Volumes.ActiveCount++
+		} else if refCount == 0 && size > 0 {
+			usage.Volumes.Reclaimable += size
+		}
+		usage.VolumeItems = append(usage.VolumeItems, system.VolumeDiskUsage{
+			Name:     v.Name,
+			Driver:   v.Driver,
+			Size:     size,
+			RefCount: refCount,
+		})
+	}
+
+	for _, record := range du.BuildCache.Items {
+		usage.BuildCache.TotalCount++
+		if record.InUse {
+			usage.BuildCache.ActiveCount++
+		}
+		if record.Shared {
+			continue
+		}
+		usage.BuildCache.TotalSize += record.Size
+		if !record.InUse {
+			usage.BuildCache.Reclaimable += record.Size
+		}
+	}
+
+	slices.SortFunc(usage.ImageItems, func(a, b system.ImageDiskUsage) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+	slices.SortFunc(usage.VolumeItems, func(a, b system.VolumeDiskUsage) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+
+	for _, category := range []system.DiskUsageCategory{usage.Images, usage.Containers, usage.Volumes, usage.BuildCache} {
+		usage.TotalSize += category.TotalSize
+		usage.TotalReclaimable += category.Reclaimable
+	}
+	return usage
+}
+
+// dockerEventTypesInternal are the event types StreamEvents subscribes to when
+// the filter names none.
+var dockerEventTypesInternal = []events.Type{
+	events.ContainerEventType,
+	events.ImageEventType,
+	events.NetworkEventType,
+	events.VolumeEventType,
+	events.DaemonEventType,
+	events.PluginEventType,
+	events.BuilderEventType,
+	events.ServiceEventType,
+	events.NodeEventType,
+	events.SecretEventType,
+	events.ConfigEventType,
+}
+
+// StreamEvents sends the Docker events matching filter to eventsChan until ctx
+// is done. It reads from the Docker client service's event bus, which fans the
+// daemon's single events subscription out to every consumer, so each caller
+// does not open a stream of its own.
+func (s *SystemService) StreamEvents(ctx context.Context, filter system.DockerEventFilter, eventsChan chan<- system.DockerEvent) error {
+	eventTypes, err := resolveDockerEventTypesInternal(filter.Types)
+	if err != nil {
+		return err
+	}
+	labelFilters, err := parseDockerEventLabelFiltersInternal(filter.Labels)
+	if err != nil {
+		return err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	eventBus := s.dockerService.EventBus()
+	merged := make(chan events.Message, 64)
+	unsubscribes := make([]func(), 0, len(eventTypes))
+	defer func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}()
+	for _, eventType := range eventTypes {
+		ch, unsubscribe := eventBus.Subscribe(eventType, bus.WithSubscriberBuffer(64))
+		unsubscribes = append(unsubscribes, unsubscribe)
+		go forwardDockerEventsInternal(streamCtx, ch, merged)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-merged:
+			if msg.Action == dockerImageStateResyncActionInternal || !dockerEventMatchesLabelsInternal(msg, labelFilters) {
+				continue
+			}
+			select {
+			case eventsChan <- newDockerEventInternal(msg):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+func forwardDockerEventsInternal(ctx context.Context, in <-chan events.Message, out chan<- events.Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-in:
+			if !ok {
+				return
+			}
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// resolveDockerEventTypesInternal validates the requested event types, falling
+// back to every type when none are given.
+func resolveDockerEventTypesInternal(requested []string) ([]events.Type, error) {
+	if len(requested) == 0 {
+		return dockerEventTypesInternal, nil
+	}
+	resolved := make([]events.Type, 0, len(requested))
+	for _, name := range requested {
+		eventType := events.Type(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(dockerEventTypesInternal, eventType) {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "unknown event type %q", name)
+		}
+		if !slices.Contains(resolved, eventType) {
+			resolved = append(resolved, eventType)
+		}
+	}
+	return resolved, nil
+}
+
+// parseDockerEventLabelFiltersInternal turns "key" and "key=value" filters into
+// a map where an empty value only requires the key to be present.
+func parseDockerEventLabelFiltersInternal(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		key, value, _ := strings.Cut(strings.TrimSpace(filter), "=")
+		if strings.TrimSpace(key) == "" {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid label filter %q", filter)
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+func dockerEventMatchesLabelsInternal(msg events.Message, labelFilters map[string]string) bool {
+	for key, value := range labelFilters {
+		actual, ok := msg.Actor.Attributes[key]
+		if !ok || (value != "" && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+func newDockerEventInternal(msg events.Message) system.DockerEvent {
+	eventTime := time.Unix(msg.Time, 0)
+	if msg.TimeNano != 0 {
+		eventTime = time.Unix(0, msg.TimeNano)
+	}
+	attrs := maps.Clone(msg.Actor.Attributes)
+	name := attrs["name"]
+	delete(attrs, "name")
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+	return system.DockerEvent{
+		Time:       eventTime,
+		Type:       string(msg.Type),
+		Action:     string(msg.Action),
+		ActorID:    msg.Actor.ID,
+		ActorName:  name,
+		Scope:      msg.Scope,
+		Attributes: attrs,
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/getarcaneapp/arcane/types/v2/system"
+	"github.com/moby/moby/api/types/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemService_PruneAllWithProgress_ReportsStages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/containers/prune":
+			_ = json.NewEncoder(w).Encode(map[string]any{"ContainersDeleted": []string{"c1", "c2"}, "SpaceReclaimed": 2048})
+		case "/networks/prune":
+			_ = json.NewEncoder(w).Encode(map[string]any{"NetworksDeleted": []string{"n1"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewSystemService(nil, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil, nil, nil, nil)
+
+	var events []system.PruneProgressEvent
+	result, started, err := svc.PruneAllWithProgress(context.Background(), "0", system.PruneAllRequest{
+		Containers: &system.PruneContainersOptions{Mode: system.PruneContainerModeStopped},
+		Networks:   &system.PruneNetworksOptions{Mode: system.PruneNetworkModeUnused},
+	}, func(event system.PruneProgressEvent) {
+		events = append(events, event)
+	})
+	require.NoError(t, err)
+	require.True(t, started)
+	require.True(t, result.Success)
+
+	require.Len(t, events, 5)
+	require.Equal(t, system.PruneProgressStageContainers, events[0].Stage)
+	require.Equal(t, system.PruneProgressStatusRunning, events[0].Status)
+	require.Equal(t, system.PruneProgressStageContainers, events[1].Stage)
+	require.Equal(t, system.PruneProgressStatusCompleted, events[1].Status)
+	require.Equal(t, 2, events[1].ItemsRemoved)
+	require.Equal(t, uint64(2048), events[1].SpaceReclaimed)
+	require.Equal(t, system.PruneProgressStageNetworks, events[2].Stage)
+	require.Equal(t, system.PruneProgressStatusRunning, events[2].Status)
+	require.Equal(t, system.PruneProgressStageNetworks, events[3].Stage)
+	require.Equal(t, 1, events[3].ItemsRemoved)
+
+	final := events[4]
+	require.Equal(t, system.PruneProgressStageDone, final.Stage)
+	require.Equal(t, system.PruneProgressStatusCompleted, final.Status)
+	require.Equal(t, 100, final.Progress)
+	require.Equal(t, uint64(2048), final.SpaceReclaimed)
+	require.NotNil(t, final.Result)
+	require.Equal(t, []string{"n1"}, final.Result.NetworksDeleted)
+}
+
+func TestValidatePruneRequest(t *testing.T) {
+	images := &system.PruneImagesOptions{Mode: system.PruneImageModeDangling}
+
+	require.NoError(t, ValidatePruneRequest(system.PruneAllRequest{
+		Images:       images,
+		LabelFilters: map[string]string{"com.docker.stack.namespace": "web", "!keep": ""},
+		Until:        "24h",
+	}))
+	require.NoError(t, ValidatePruneRequest(system.PruneAllRequest{Images: images, Until: "2024-01-02T15:04:05Z"}))
+	require.NoError(t, ValidatePruneRequest(system.PruneAllRequest{Images: images, Until: "1704207845"}))
+
+	for name, req := range map[string]system.PruneAllRequest{
+		"empty label key":       {Images: images, LabelFilters: map[string]string{"!": "x"}},
+		"label key with equals": {Images: images, LabelFilters: map[string]string{"a=b": ""}},
+		"malformed until":       {Images: images, Until: "yesterday"},
+		"labels with build cache": {
+			BuildCache:   &system.PruneBuildCacheOptions{Mode: system.PruneBuildCacheModeAll},
+			LabelFilters: map[string]string{"team": "a"},
+		},
+		"until with volumes": {Volumes: &system.PruneVolumesOptions{Mode: system.PruneVolumeModeAll}, Until: "24h"},
+	} {
+		err := ValidatePruneRequest(req)
+		require.Error(t, err, name)
+		require.True(t, cerrdefs.IsInvalidArgument(err), name)
+	}
+}
+
+func TestSystemService_PruneAll_PassesLabelAndUntilFilters(t *testing.T) {
+	var gotFilters map[string]map[string]bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dockerTestPathInternal(r.URL.Path) != "/images/prune" {
+			http.NotFound(w, r)
+			return
+		}
+		require.NoError(t, json.Unmarshal([]byte(r.URL.Query().Get("filters")), &gotFilters))
+		_ = json.NewEncoder(w).Encode(map[string]any{"ImagesDeleted": []any{}, "SpaceReclaimed": 0})
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewSystemService(nil, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil, nil, nil, nil)
+	result, started, err := svc.PruneAll(context.Background(), "0", system.PruneAllRequest{
+		Images:       &system.PruneImagesOptions{Mode: system.PruneImageModeDangling},
+		LabelFilters: map[string]string{"com.docker.stack.namespace": "web", "!keep": ""},
+		Until:        "48h",
+	})
+	require.NoError(t, err)
+	require.True(t, started)
+	require.True(t, result.Success)
+
+	require.Equal(t, map[string]map[string]bool{
+		"dangling": {"true": true},
+		"until":    {"48h": true},
+		"label":    {"com.docker.stack.namespace=web": true},
+		"label!":   {"keep": true},
+	}, gotFilters)
+}
+
+func TestSystemService_GetDiskUsage_SummarizesAndCaches(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dockerTestPathInternal(r.URL.Path) != "/system/df" {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Images": []map[string]any{
+				{"Id": "sha256:small", "RepoTags": []string{"small:latest"}, "Size": 300, "SharedSize": 100, "Containers": 1},
+				{"Id": "sha256:big", "RepoTags": []string{"big:latest"}, "Size": 1100, "SharedSize": 100, "Containers": 0},
+			},
+			"Containers": []map[string]any{
+				{"Id": "c1", "State": "running", "SizeRw": 50},
+				{"Id": "c2", "State": "exited", "SizeRw": 20},
+			},
+			"Volumes": []map[string]any{
+				{"Name": "used", "Driver": "local", "UsageData": map[string]any{"Size": 10, "RefCount": 1}},
+				{"Name": "orphan", "Driver": "local", "UsageData": map[string]any{"Size": 40, "RefCount": 0}},
+			},
+			"BuildCache": []map[string]any{
+				{"ID": "b1", "Size": 70, "InUse": false, "Shared": false},
+				{"ID": "b2", "Size": 5, "InUse": true, "Shared": false},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewSystemService(nil, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil, nil, nil, nil)
+
+	usage, err := svc.GetDiskUsage(context.Background(), false)
+	require.NoError(t, err)
+
+	require.Equal(t, system.DiskUsageCategory{TotalCount: 2, ActiveCount: 1, TotalSize: 1300, Reclaimable: 1000}, usage.Images)
+	require.Equal(t, system.DiskUsageCategory{TotalCount: 2, ActiveCount: 1, TotalSize: 70, Reclaimable: 20}, usage.Containers)
+	require.Equal(t, system.DiskUsageCategory{TotalCount: 2, ActiveCount: 1, TotalSize: 50, Reclaimable: 40}, usage.Volumes)
+	require.Equal(t, system.DiskUsageCategory{TotalCount: 2, ActiveCount: 1, TotalSize: 75, Reclaimable: 70}, usage.BuildCache)
+	require.Equal(t, int64(1495), usage.TotalSize)
+	require.Equal(t, int64(1130), usage.TotalReclaimable)
+
+	require.Len(t, usage.ImageItems, 2)
+	require.Equal(t, "sha256:big", usage.ImageItems[0].ID)
+	require.Equal(t, int64(1000), usage.ImageItems[0].UniqueSize)
+	require.Equal(t, "orphan", usage.VolumeItems[0].Name)
+
+	_, err = svc.GetDiskUsage(context.Background(), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	_, err = svc.GetDiskUsage(context.Background(), true)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestSystemService_GetDiskUsage_UsesDaemonLayersSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dockerTestPathInternal(r.URL.Path) != "/system/df" {
+			http.NotFound(w, r)
+			return
+		}
+		// Each pair of images shares a different base layer, so the largest
+		// shared size alone would undercount the layer store.
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"LayersSize": 1000,
+			"Images": []map[string]any{
+				{"Id": "sha256:a1", "Size": 300, "SharedSize": 200},
+				{"Id": "sha256:a2", "Size": 300, "SharedSize": 200},
+				{"Id": "sha256:b1", "Size": 350, "SharedSize": 250},
+				{"Id": "sha256:b2", "Size": 350, "SharedSize": 250},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewSystemService(nil, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil, nil, nil, nil)
+
+	usage, err := svc.GetDiskUsage(context.Background(), true)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), usage.Images.TotalSize)
+}
+
+func TestSystemService_CheckReadiness_ReportsFailingCriticalChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch dockerTestPathInternal(r.URL.Path) {
+		case "/_ping":
+			w.WriteHeader(http.StatusOK)
+		case "/info":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Swarm": map[string]any{"LocalNodeState": "active", "ControlAvailable": false}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	settingsService := &SettingsService{}
+	cfg := DefaultSettingsConfig()
+	cfg.DiskUsagePath.Value = t.TempDir()
+	settingsService.config.Store(cfg)
+
+	svc := NewSystemService(nil, &DockerClientService{client: newTestDockerClient(t, server)}, nil, nil, nil, nil, settingsService, nil, nil)
+
+	statuses := func(report system.ReadinessResponse) map[string]string {
+		out := make(map[string]string, len(report.Checks))
+		for _, check := range report.Checks {
+			out[check.Name] = check.Status
+		}
+		return out
+	}
+
+	report := svc.CheckReadiness(context.Background(), 0)
+	require.True(t, report.Ready)
+	require.Empty(t, report.Failing)
+	require.Equal(t, map[string]string{
+		"docker":   system.ReadinessStatusOK,
+		"database": system.ReadinessStatusSkipped,
+		"disk":     system.ReadinessStatusOK,
+		"swarm":    system.ReadinessStatusDegraded,
+	}, statuses(report))
+
+	report = svc.CheckReadiness(context.Background(), 100)
+	require.False(t, report.Ready)
+	require.Equal(t, []string{"disk"}, report.Failing)
+}
+
+func TestSystemService_StreamEvents_FiltersByTypeAndLabel(t *testing.T) {
+	dockerService := &DockerClientService{}
+	svc := NewSystemService(nil, dockerService, nil, nil, nil, nil, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan system.DockerEvent, 8)
+	go func() {
+		_ = svc.StreamEvents(ctx, system.DockerEventFilter{Types: []string{"container"}, Labels: []string{"com.docker.compose.project=shop"}}, out)
+	}()
+
+	var received system.DockerEvent
+	require.Eventually(t, func() bool {
+		eventBus := dockerService.EventBus()
+		eventBus.Publish(events.Message{Type: events.ImageEventType, Action: events.ActionPull, Actor: events.Actor{ID: "nginx:alpine"}})
+		eventBus.Publish(events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Actor: events.Actor{
+			ID:         "c0",
+			Attributes: map[string]string{"name": "blog-web-1", "com.docker.compose.project": "blog"},
+		}})
+		eventBus.Publish(events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Scope: "local", TimeNano: 1700000000000000000, Actor: events.Actor{
+			ID:         "c1",
+			Attributes: map[string]string{"name": "shop-web-1", "com.docker.compose.project": "shop"},
+		}})
+		select {
+		case received = <-out:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, "container", received.Type)
+	require.Equal(t, "start", received.Action)
+	require.Equal(t, "c1", received.ActorID)
+	require.Equal(t, "shop-web-1", received.ActorName)
+	require.Equal(t, "local", received.Scope)
+	require.Equal(t, time.Unix(0, 1700000000000000000), received.Time)
+	require.Equal(t, map[string]string{"com.docker.compose.project": "shop"}, received.Attributes)
+}
+
+func TestSystemService_StreamEvents_RejectsInvalidFilters(t *testing.T) {
+	svc := NewSystemService(nil, &DockerClientService{}, nil, nil, nil, nil, nil, nil, nil)
+
+	err := svc.StreamEvents(context.Background(), system.DockerEventFilter{Types: []string{"kubernetes"}}, make(chan system.DockerEvent))
+	require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+
+	err = svc.StreamEvents(context.Background(), system.DockerEventFilter{Labels: []string{"=shop"}}, make(chan system.DockerEvent))
+	require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+}
@@ -1,15 +1,22 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	json "encoding/json/v2"
 	"fmt"
 	"io"
 	"log/slog"
 	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,16 +28,21 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/timeouts"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils"
 	httputils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils/httpx"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/mapper"
+	"github.com/getarcaneapp/arcane/types/v2"
+	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/env"
+	"github.com/getarcaneapp/arcane/types/v2/project"
 	tmpl "github.com/getarcaneapp/arcane/types/v2/template"
 	"github.com/google/uuid"
 	"github.com/samber/hot"
 	"github.com/samber/mo"
+	"go.getarcane.app/sys/crypto"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
@@ -46,6 +58,10 @@ type TemplateService struct {
 	safeHTTPClient  *http.Client
 	lookupIP        httputils.LookupIPFunc
 	settingsService *SettingsService
+	// projectService and environmentService back DeployTemplate; either may be
+	// nil in tests that never deploy.
+	projectService     *ProjectService
+	environmentService *EnvironmentService
 
 	remoteCache *hot.HotCache[struct{}, []models.ComposeTemplate]
 
@@ -61,6 +77,7 @@ const (
 	remoteCacheDuration         = 5 * time.Minute
 	fsSyncInterval              = 1 * time.Minute
 	remoteIconResolveLimit      = 4
+	templateUpdateCheckLimit    = 4
 	templateArcaneBlockKey      = "x-arcane"
 	templateArcaneIconKey       = "icon"
 	templateArcaneIconsAliasKey = "icons"
@@ -74,18 +91,20 @@ func makeRemoteID(registryID, slug string) string {
 	return fmt.Sprintf("%s:%s:%s", remoteIDPrefix, registryID, slug)
 }
 
-func NewTemplateService(ctx context.Context, db *database.DB, httpClient *http.Client, settingsService *SettingsService) *TemplateService {
+func NewTemplateService(ctx context.Context, db *database.DB, httpClient *http.Client, settingsService *SettingsService, projectService *ProjectService, environmentService *EnvironmentService) *TemplateService {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
 	service := &TemplateService{
-		db:                db,
-		httpClient:        httpClient,
-		lookupIP:          httputils.DefaultLookupIP,
-		settingsService:   settingsService,
-		registryFetchMeta: make(map[string]*registryFetchMeta),
-		registryErrors:    make(map[string]string),
+		db:                 db,
+		httpClient:         httpClient,
+		lookupIP:           httputils.DefaultLookupIP,
+		settingsService:    settingsService,
+		projectService:     projectService,
+		environmentService: environmentService,
+		registryFetchMeta:  make(map[string]*registryFetchMeta),
+		registryErrors:     make(map[string]string),
 	}
 	service.safeHTTPClient = service.newSafeHTTPClientInternal()
 	revalidationCtx := context.WithoutCancel(ctx)
@@ -186,6 +205,7 @@ func (s *TemplateService) GetAllTemplatesPaginated(ctx context.Context, params p
 		SearchAccessors: []pagination.SearchAccessor[tmpl.Template]{
 			func(t tmpl.Template) (string, error) { return t.Name, nil },
 			func(t tmpl.Template) (string, error) { return t.Description, nil },
+			func(t tmpl.Template) (string, error) { return t.Category, nil },
 			func(t tmpl.Template) (string, error) {
 				if t.Metadata != nil && len(t.Metadata.Tags) > 0 {
 					return strings.Join(t.Metadata.Tags, " "), nil
@@ -206,6 +226,12 @@ func (s *TemplateService) GetAllTemplatesPaginated(ctx context.Context, params p
 					return strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
 				},
 			},
+			{
+				Key: "category",
+				Fn: func(a, b tmpl.Template) int {
+					return strings.Compare(strings.ToLower(a.Category), strings.ToLower(b.Category))
+				},
+			},
 			{
 				Key: "isRemote",
 				Fn: func(a, b tmpl.Template) int {
@@ -232,6 +258,23 @@ func (s *TemplateService) GetAllTemplatesPaginated(ctx context.Context, params p
 					return true
 				},
 			},
+			{
+				Key: "category",
+				Fn: func(item tmpl.Template, filterValue string) bool {
+					return strings.EqualFold(item.Category, strings.TrimSpace(filterValue))
+				},
+			},
+			{
+				Key: "tags",
+				Fn: func(item tmpl.Template, filterValue string) bool {
+					if item.Metadata == nil {
+						return false
+					}
+					return slices.ContainsFunc(item.Metadata.Tags, func(tag string) bool {
+						return strings.EqualFold(tag, strings.TrimSpace(filterValue))
+					})
+				},
+			},
 		},
 	}
 
@@ -241,6 +284,32 @@ func (s *TemplateService) GetAllTemplatesPaginated(ctx context.Context, params p
 	return result.Items, paginationResp, nil
 }
 
+// GetTemplateCategories returns the distinct categories across local and
+// remote templates, sorted case-insensitively.
+func (s *TemplateService) GetTemplateCategories(ctx context.Context) ([]string, error) {
+	templates, err := s.getMergedTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]string)
+	for _, t := range templates {
+		category := strings.TrimSpace(t.Category)
+		if category == "" {
+			continue
+		}
+		if _, ok := seen[strings.ToLower(category)]; !ok {
+			seen[strings.ToLower(category)] = category
+		}
+	}
+
+	categories := slices.Collect(maps.Values(seen))
+	slices.SortFunc(categories, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return categories, nil
+}
+
 func (s *TemplateService) GetTemplate(ctx context.Context, id string) (*models.ComposeTemplate, error) {
 	if err := s.syncFilesystemTemplatesInternal(ctx); err != nil {
 		slog.WarnContext(ctx, "failed to sync filesystem templates", "error", err)
@@ -309,6 +378,10 @@ func (s *TemplateService) CreateTemplate(ctx context.Context, template *models.C
 	}
 	template.IsCustom = true
 	template.IsRemote = false
+	template.Category = strings.TrimSpace(template.Category)
+	if template.Metadata != nil {
+		setTemplateTagsInternal(template, template.Metadata.Tags)
+	}
 	setTemplateIconURL(template, s.resolveTemplateIconURL(ctx, template.Content, mo.PointerToOption(template.EnvContent).OrEmpty()))
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(template).Error; err != nil {
@@ -336,6 +409,11 @@ func (s *TemplateService) UpdateTemplate(ctx context.Context, id string, updates
 		existing.Description = updates.Description
 		existing.Content = updates.Content
 		existing.EnvContent = updates.EnvContent
+		existing.Category = strings.TrimSpace(updates.Category)
+		// Nil tags leave the current tags alone; an empty list clears them.
+		if updates.Metadata != nil && updates.Metadata.Tags != nil {
+			setTemplateTagsInternal(&existing, updates.Metadata.Tags)
+		}
 		setTemplateIconURL(&existing, s.resolveTemplateIconURL(ctx, existing.Content, mo.PointerToOption(existing.EnvContent).OrEmpty()))
 
 		if err := tx.Save(&existing).Error; err != nil {
@@ -452,13 +530,20 @@ func (s *TemplateService) GetRegistryFetchErrors() map[string]string {
 	return out
 }
 
+// CreateRegistry stores a new template registry. registry.Credential carries
+// the plaintext token or password and is encrypted before it is persisted.
 func (s *TemplateService) CreateRegistry(ctx context.Context, registry *models.TemplateRegistry) error {
+	auth, err := resolveRegistryAuthInternal(registry.AuthType, registry.Username, registry.Credential, nil)
+	if err != nil {
+		return err
+	}
+
 	// Hydrate metadata if needed
 	if registry.Name == "" || registry.Description == "" {
 		if registry.URL == "" {
 			return errors.New("registry URL is required")
 		}
-		if manifest, err := s.fetchRegistryManifest(ctx, registry.URL); err == nil {
+		if manifest, err := s.fetchRegistryManifest(ctx, registry.URL, auth); err == nil {
 			if registry.Name == "" {
 				registry.Name = manifest.Name
 			}
@@ -473,8 +558,11 @@ func (s *TemplateService) CreateRegistry(ctx context.Context, registry *models.T
 	if registry.ID == "" {
 		registry.ID = uuid.NewString()
 	}
+	if err := storeRegistryAuthInternal(registry, auth); err != nil {
+		return err
+	}
 
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(registry).Error; err != nil {
 			return errors.WrapIf(err, "failed to create registry")
 		}
@@ -488,6 +576,10 @@ func (s *TemplateService) CreateRegistry(ctx context.Context, registry *models.T
 	return nil
 }
 
+// UpdateRegistry updates a template registry. An empty updates.AuthType keeps
+// the stored authentication; an empty updates.Credential keeps the stored secret.
+// Moving the registry to a different host drops the stored secret, so it is never
+// sent to the new host unless the caller supplies it again.
 func (s *TemplateService) UpdateRegistry(ctx context.Context, id string, updates *models.TemplateRegistry) error {
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var existing models.TemplateRegistry
@@ -498,12 +590,24 @@ func (s *TemplateService) UpdateRegistry(ctx context.Context, id string, updates
 			return errors.WrapIf(err, "failed to find registry")
 		}
 
-		if err := s.hydrateRegistryUpdates(ctx, updates, &existing); err != nil {
+		stored := existing
+		if updates.URL != "" && !registryHostsMatchInternal(updates.URL, existing.URL) {
+			stored.Credential = ""
+		}
+		auth, err := resolveRegistryAuthInternal(updates.AuthType, updates.Username, updates.Credential, &stored)
+		if err != nil {
+			return err
+		}
+
+		if err := s.hydrateRegistryUpdates(ctx, updates, &existing, auth); err != nil {
+			return err
+		}
+		if err := storeRegistryAuthInternal(updates, auth); err != nil {
 			return err
 		}
 
 		if err := tx.Model(&models.TemplateRegistry{}).Where("id = ?", id).
-			Select("Name", "URL", "Description", "Enabled").
+			Select("Name", "URL", "Description", "Enabled", "AuthType", "Username", "Credential").
 			Updates(updates).Error; err != nil {
 			return err
 		}
@@ -517,7 +621,7 @@ func (s *TemplateService) UpdateRegistry(ctx context.Context, id string, updates
 	return nil
 }
 
-func (s *TemplateService) hydrateRegistryUpdates(ctx context.Context, updates, existing *models.TemplateRegistry) error {
+func (s *TemplateService) hydrateRegistryUpdates(ctx context.Context, updates, existing *models.TemplateRegistry, auth *RegistryAuth) error {
 	urlChanged := updates.URL != "" && updates.URL != existing.URL
 	needsHydration := updates.Name == "" || updates.Description == ""
 
@@ -526,7 +630,7 @@ func (s *TemplateService) hydrateRegistryUpdates(ctx context.Context, updates, e
 		if manifestURL == "" {
 			manifestURL = existing.URL
 		}
-		if manifest, err := s.fetchRegistryManifest(ctx, manifestURL); err == nil {
+		if manifest, err := s.fetchRegistryManifest(ctx, manifestURL, auth); err == nil {
 			if updates.Name == "" {
 				updates.Name = manifest.Name
 			}
@@ -610,15 +714,17 @@ func (s *TemplateService) loadRemoteTemplates(ctx context.Context) ([]models.Com
 	return templates, nil
 }
 
-func (s *TemplateService) FetchRaw(ctx context.Context, url string) ([]byte, error) {
-	return s.doGET(ctx, url)
+// FetchRaw fetches url, authenticating with auth when it is non-nil.
+func (s *TemplateService) FetchRaw(ctx context.Context, url string, auth *RegistryAuth) ([]byte, error) {
+	return s.doGET(ctx, url, auth)
 }
 
-func (s *TemplateService) doGET(ctx context.Context, url string) ([]byte, error) {
+func (s *TemplateService) doGET(ctx context.Context, url string, auth *RegistryAuth) ([]byte, error) {
 	client, req, err := s.newSafeRequestInternal(ctx, http.MethodGet, url)
 	if err != nil {
 		return nil, err
 	}
+	auth.applyInternal(req)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, errors.WrapIff(err, "failed to fetch %s", url)
@@ -643,10 +749,16 @@ func (s *TemplateService) fetchRegistryTemplates(ctx context.Context, reg *model
 	fetchMeta := s.registryFetchMeta[reg.ID]
 	s.registryMu.RUnlock()
 
+	auth, err := registryAuthFromModelInternal(reg)
+	if err != nil {
+		return nil, err
+	}
+
 	client, req, err := s.newSafeRequestInternal(ctx, http.MethodGet, reg.URL)
 	if err != nil {
 		return nil, errors.WrapIf(err, "create request")
 	}
+	auth.applyInternal(req)
 	if fetchMeta != nil && fetchMeta.LastModified != "" {
 		req.Header.Set("If-Modified-Since", fetchMeta.LastModified)
 	}
@@ -695,8 +807,8 @@ func (s *TemplateService) fetchRegistryTemplates(ctx context.Context, reg *model
 	return templates, nil
 }
 
-func (s *TemplateService) fetchRegistryManifest(ctx context.Context, url string) (*tmpl.RemoteRegistry, error) {
-	body, err := s.doGET(ctx, url)
+func (s *TemplateService) fetchRegistryManifest(ctx context.Context, url string, auth *RegistryAuth) (*tmpl.RemoteRegistry, error) {
+	body, err := s.doGET(ctx, url, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -717,6 +829,7 @@ func (s *TemplateService) convertRemoteToLocal(remote tmpl.RemoteTemplate, regis
 		BaseModel:   models.BaseModel{ID: publicID},
 		Name:        remote.Name,
 		Description: remote.Description,
+		Category:    strings.TrimSpace(remote.Category),
 		Content:     "",
 		EnvContent:  nil,
 		IsCustom:    false,
@@ -754,14 +867,14 @@ func (s *TemplateService) fetchRemoteTemplateFiles(ctx context.Context, template
 		return "", "", errors.New("not a remote template or missing remote URL")
 	}
 
-	composeContent, err := s.fetchURL(ctx, *template.Metadata.RemoteURL)
+	composeContent, err := s.fetchURL(ctx, *template.Metadata.RemoteURL, templateFileAuthInternal(ctx, template, *template.Metadata.RemoteURL))
 	if err != nil {
 		return "", "", errors.WrapIff(err, "failed to fetch compose content from %s", *template.Metadata.RemoteURL)
 	}
 
 	var envContent string
 	if template.Metadata.EnvURL != nil && *template.Metadata.EnvURL != "" {
-		envContent, err = s.fetchURL(ctx, *template.Metadata.EnvURL)
+		envContent, err = s.fetchURL(ctx, *template.Metadata.EnvURL, templateFileAuthInternal(ctx, template, *template.Metadata.EnvURL))
 		if err != nil {
 			slog.WarnContext(ctx, "failed to fetch env content", "url", *template.Metadata.EnvURL, "error", err)
 			envContent = ""
@@ -797,14 +910,147 @@ func (s *TemplateService) enrichRemoteTemplateIcons(ctx context.Context, templat
 	_ = group.Wait()
 }
 
-func (s *TemplateService) fetchURL(ctx context.Context, url string) (string, error) {
-	body, err := s.doGET(ctx, url)
+func (s *TemplateService) fetchURL(ctx context.Context, url string, auth *RegistryAuth) (string, error) {
+	body, err := s.doGET(ctx, url, auth)
 	if err != nil {
 		return "", err
 	}
 	return string(body), nil
 }
 
+// RegistryAuth is a decrypted template registry credential.
+type RegistryAuth struct {
+	Type     string
+	Username string
+	Secret   string
+}
+
+func (a *RegistryAuth) applyInternal(req *http.Request) {
+	if a == nil {
+		return
+	}
+	switch a.Type {
+	case models.TemplateRegistryAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+a.Secret)
+	case models.TemplateRegistryAuthBasic:
+		req.SetBasicAuth(a.Username, a.Secret)
+	}
+}
+
+// NewRegistryAuth validates ad-hoc registry credentials, such as those used to
+// preview a registry before it is saved. It returns nil when authType is empty
+// or "none".
+func NewRegistryAuth(authType, username, secret string) (*RegistryAuth, error) {
+	return resolveRegistryAuthInternal(authType, username, secret, nil)
+}
+
+// resolveRegistryAuthInternal validates requested registry authentication and
+// returns the plaintext credential, or nil for unauthenticated registries. An
+// empty authType keeps existing's authentication and an empty secret keeps
+// existing's stored secret.
+func resolveRegistryAuthInternal(authType, username, secret string, existing *models.TemplateRegistry) (*RegistryAuth, error) {
+	authType = strings.TrimSpace(authType)
+	username = strings.TrimSpace(username)
+
+	if authType == "" {
+		return registryAuthFromModelInternal(existing)
+	}
+
+	switch authType {
+	case models.TemplateRegistryAuthNone:
+		return nil, nil
+	case models.TemplateRegistryAuthBearer:
+		username = ""
+	case models.TemplateRegistryAuthBasic:
+		if username == "" {
+			return nil, errors.WrapIf(common.ErrTemplateRegistryAuthInvalid, "username is required for basic auth")
+		}
+	default:
+		return nil, errors.WrapIff(common.ErrTemplateRegistryAuthInvalid, "unsupported auth type %q", authType)
+	}
+
+	if secret == "" && existing != nil && existing.Credential != "" {
+		decrypted, err := crypto.Decrypt(existing.Credential)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to decrypt registry credential")
+		}
+		secret = decrypted
+	}
+	if secret == "" {
+		return nil, errors.WrapIf(common.ErrTemplateRegistryAuthInvalid, "token is required")
+	}
+
+	return &RegistryAuth{Type: authType, Username: username, Secret: secret}, nil
+}
+
+// registryAuthFromModelInternal decrypts a stored registry credential.
+func registryAuthFromModelInternal(registry *models.TemplateRegistry) (*RegistryAuth, error) {
+	if registry == nil || registry.Credential == "" {
+		return nil, nil
+	}
+	if registry.AuthType != models.TemplateRegistryAuthBearer && registry.AuthType != models.TemplateRegistryAuthBasic {
+		return nil, nil
+	}
+
+	secret, err := crypto.Decrypt(registry.Credential)
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to decrypt credential for registry %s", registry.ID)
+	}
+	return &RegistryAuth{Type: registry.AuthType, Username: registry.Username, Secret: secret}, nil
+}
+
+// storeRegistryAuthInternal writes auth onto registry, encrypting the secret.
+func storeRegistryAuthInternal(registry *models.TemplateRegistry, auth *RegistryAuth) error {
+	if auth == nil {
+		registry.AuthType = models.TemplateRegistryAuthNone
+		registry.Username = ""
+		registry.Credential = ""
+		return nil
+	}
+
+	encrypted, err := crypto.Encrypt(auth.Secret)
+	if err != nil {
+		return errors.WrapIf(err, "failed to encrypt registry credential")
+	}
+	registry.AuthType = auth.Type
+	registry.Username = auth.Username
+	registry.Credential = encrypted
+	return nil
+}
+
+// templateFileAuthInternal returns the owning registry's credential for a
+// remote template file, but only when the file is served from the registry's
+// own host so secrets are never sent to third-party URLs in a manifest.
+func templateFileAuthInternal(ctx context.Context, template *models.ComposeTemplate, fileURL string) *RegistryAuth {
+	if template.Registry == nil || template.Registry.Credential == "" {
+		return nil
+	}
+
+	if !registryHostsMatchInternal(fileURL, template.Registry.URL) {
+		return nil
+	}
+
+	auth, err := registryAuthFromModelInternal(template.Registry)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load registry credential for template file", "templateID", template.ID, "error", err)
+		return nil
+	}
+	return auth
+}
+
+// registryHostsMatchInternal reports whether both URLs parse and share a host.
+func registryHostsMatchInternal(a, b string) bool {
+	first, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	second, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(first.Host, second.Host)
+}
+
 func (s *TemplateService) newSafeHTTPClientInternal() *http.Client {
 	client, err := httputils.NewSafeOutboundHTTPClient(s.httpClient, s.lookupIP)
 	if err != nil {
@@ -877,6 +1123,7 @@ func (s *TemplateService) downloadTemplateTransaction(ctx context.Context, remot
 			existing.Content = composeContent
 			existing.EnvContent = envPtr
 			existing.Metadata = cloneTemplateMetadata(remoteTemplate.Metadata)
+			setTemplateSourceInternal(&existing, remoteTemplate, composeContent, envContent)
 
 			if err := tx.Save(&existing).Error; err != nil {
 				return errors.WrapIf(err, "failed to update existing local template")
@@ -908,6 +1155,7 @@ func (s *TemplateService) downloadTemplateTransaction(ctx context.Context, remot
 			Registry:    nil,
 			Metadata:    cloneTemplateMetadata(remoteTemplate.Metadata),
 		}
+		setTemplateSourceInternal(localTemplate, remoteTemplate, composeContent, envContent)
 
 		if err := tx.Create(localTemplate).Error; err != nil {
 			return errors.WrapIf(err, "failed to save local template")
@@ -921,6 +1169,172 @@ func (s *TemplateService) downloadTemplateTransaction(ctx context.Context, remot
 	return resultTemplate, nil
 }
 
+// setTemplateSourceInternal records the upstream a downloaded template came
+// from, along with a digest of the content fetched from it.
+func setTemplateSourceInternal(local, remote *models.ComposeTemplate, composeContent, envContent string) {
+	if remote.Metadata != nil {
+		local.SourceURL = mo.EmptyableToOption(strings.TrimSpace(mo.PointerToOption(remote.Metadata.RemoteURL).OrEmpty())).ToPointer()
+	}
+	if remote.RegistryID != nil {
+		registryID := *remote.RegistryID
+		local.SourceRegistryID = &registryID
+	}
+	digest := templateSourceDigestInternal(composeContent, envContent)
+	local.SourceDigest = &digest
+}
+
+func templateSourceDigestInternal(composeContent, envContent string) string {
+	sum := sha256.Sum256([]byte(composeContent + "\x00" + envContent))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckTemplateUpdates re-fetches the upstream source of every downloaded
+// template and reports which ones changed since they were last downloaded or
+// refreshed. A source that cannot be fetched is reported with Error set rather
+// than failing the whole check.
+func (s *TemplateService) CheckTemplateUpdates(ctx context.Context) ([]tmpl.UpdateStatus, error) {
+	var tracked []models.ComposeTemplate
+	if err := s.db.WithContext(ctx).
+		Omit("Content", "EnvContent").
+		Where("is_remote = ? AND source_url IS NOT NULL AND source_url <> ''", false).
+		Order("name").
+		Find(&tracked).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get downloaded templates")
+	}
+
+	results := make([]tmpl.UpdateStatus, len(tracked))
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(templateUpdateCheckLimit)
+	for i := range tracked {
+		g.Go(func() error {
+			local := &tracked[i]
+			status := tmpl.UpdateStatus{
+				TemplateID: local.ID,
+				Name:       local.Name,
+				SourceURL:  *local.SourceURL,
+			}
+
+			composeContent, envContent, err := s.fetchTemplateSourceInternal(groupCtx, local)
+			if err != nil {
+				slog.WarnContext(groupCtx, "failed to check template source for updates", "templateID", local.ID, "url", *local.SourceURL, "error", err)
+				status.Error = err.Error()
+			} else {
+				status.HasUpdate = templateSourceDigestInternal(composeContent, envContent) != mo.PointerToOption(local.SourceDigest).OrEmpty()
+			}
+			results[i] = status
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results, nil
+}
+
+// RefreshTemplate replaces a downloaded template's content with the current
+// upstream content, overwriting local edits.
+func (s *TemplateService) RefreshTemplate(ctx context.Context, id string) (*models.ComposeTemplate, error) {
+	var local models.ComposeTemplate
+	if err := s.db.WithContext(ctx).Where("id = ? AND is_remote = ?", id, false).First(&local).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.Classify(common.ErrTemplateNotFound, errors.New("Template not found"))
+		}
+		return nil, errors.WrapIf(err, "failed to get template")
+	}
+	if strings.TrimSpace(mo.PointerToOption(local.SourceURL).OrEmpty()) == "" {
+		return nil, common.Classify(common.ErrTemplateNoSource, errors.Errorf("Template %q was not downloaded from a registry", local.Name))
+	}
+
+	composeContent, envContent, err := s.fetchTemplateSourceInternal(ctx, &local)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := s.localTemplateBaseInternal(ctx, &local)
+	if err != nil {
+		return nil, err
+	}
+	_, composePath, envPath, err := projects.EnsureTemplateDir(ctx, s.configuredTemplatesDirSettingInternal(ctx), base)
+	if err != nil {
+		return nil, err
+	}
+	envPtr, err := projects.WriteTemplateFiles(composePath, envPath, composeContent, envContent)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := templateSourceDigestInternal(composeContent, envContent)
+	local.Content = composeContent
+	local.EnvContent = envPtr
+	local.SourceDigest = &digest
+	if err := s.db.WithContext(ctx).Save(&local).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to save refreshed template")
+	}
+
+	return &local, nil
+}
+
+// localTemplateBaseInternal resolves the directory, relative to the templates
+// directory, that a downloaded template was written to. The stored compose path
+// is preferred so a renamed template keeps refreshing into its original
+// directory; older rows fall back to the slug of the name.
+func (s *TemplateService) localTemplateBaseInternal(ctx context.Context, local *models.ComposeTemplate) (string, error) {
+	templatesDir, err := projects.GetTemplatesDirectory(ctx, s.configuredTemplatesDirSettingInternal(ctx))
+	if err != nil {
+		return "", errors.WrapIf(err, "ensure templates dir")
+	}
+
+	dir := ""
+	if composePath, ok := strings.CutPrefix(local.Description, "Imported from "); ok && filepath.Base(composePath) == "compose.yaml" {
+		dir = filepath.Dir(composePath)
+	} else if slug := projects.Slugify(local.Name); slug != "" {
+		dir = filepath.Join(templatesDir, slug)
+	}
+
+	if dir == "" || !projects.IsSafeSubdirectory(templatesDir, dir) {
+		return "", common.Classify(common.ErrTemplatePathInvalid, errors.Errorf("Template %q does not resolve to a directory under %s", local.Name, templatesDir))
+	}
+	absTemplatesDir, err := filepath.Abs(templatesDir)
+	if err != nil {
+		return "", errors.WrapIf(err, "resolve templates dir")
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.WrapIf(err, "resolve template dir")
+	}
+	base, err := filepath.Rel(absTemplatesDir, absDir)
+	if err != nil || base == "." {
+		return "", common.Classify(common.ErrTemplatePathInvalid, errors.Errorf("Template %q does not resolve to a directory under %s", local.Name, templatesDir))
+	}
+	return base, nil
+}
+
+// fetchTemplateSourceInternal fetches a downloaded template's upstream compose
+// and env content, authenticating with its source registry when it still exists.
+func (s *TemplateService) fetchTemplateSourceInternal(ctx context.Context, local *models.ComposeTemplate) (string, string, error) {
+	source := &models.ComposeTemplate{
+		BaseModel: local.BaseModel,
+		IsRemote:  true,
+		Metadata:  &models.ComposeTemplateMetadata{RemoteURL: local.SourceURL},
+	}
+	if local.Metadata != nil {
+		source.Metadata.EnvURL = local.Metadata.EnvURL
+	}
+
+	if local.SourceRegistryID != nil {
+		var registry models.TemplateRegistry
+		err := s.db.WithContext(ctx).Where("id = ?", *local.SourceRegistryID).First(&registry).Error
+		switch {
+		case err == nil:
+			source.RegistryID = local.SourceRegistryID
+			source.Registry = &registry
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return "", "", errors.WrapIf(err, "failed to get template source registry")
+		}
+	}
+
+	return s.fetchRemoteTemplateFiles(ctx, source)
+}
+
 func (s *TemplateService) templateBaseFromRemote(remoteTemplate *models.ComposeTemplate) string {
 	base := projects.Slugify(remoteTemplate.Name)
 	if base != "" {
@@ -1215,6 +1629,30 @@ func setTemplateIconURL(template *models.ComposeTemplate, iconURL *string) {
 	}
 }
 
+// setTemplateTagsInternal replaces a template's tags, trimming blanks and
+// dropping case-insensitive duplicates.
+func setTemplateTagsInternal(template *models.ComposeTemplate, tags []string) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.ContainsFunc(normalized, func(existing string) bool { return strings.EqualFold(existing, tag) }) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+
+	if template.Metadata == nil {
+		if len(normalized) == 0 {
+			return
+		}
+		template.Metadata = &models.ComposeTemplateMetadata{}
+	}
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	template.Metadata.Tags = normalized
+}
+
 // GetTemplateContentWithParsedData returns template content along with parsed metadata
 func (s *TemplateService) GetTemplateContentWithParsedData(ctx context.Context, id string) (*tmpl.TemplateContent, error) {
 	composeTemplate, err := s.GetTemplate(ctx, id)
@@ -1222,17 +1660,9 @@ func (s *TemplateService) GetTemplateContentWithParsedData(ctx context.Context,
 		return nil, err
 	}
 
-	var composeContent, envContent string
-	if composeTemplate.IsRemote {
-		composeContent, envContent, err = s.FetchTemplateContent(ctx, composeTemplate)
-		if err != nil {
-			return nil, errors.WrapIf(err, "failed to fetch template content")
-		}
-	} else {
-		composeContent = composeTemplate.Content
-		if composeTemplate.EnvContent != nil {
-			envContent = *composeTemplate.EnvContent
-		}
+	composeContent, envContent, err := s.templateFilesInternal(ctx, composeTemplate)
+	if err != nil {
+		return nil, err
 	}
 
 	setTemplateIconURL(composeTemplate, s.resolveTemplateIconURL(ctx, composeContent, envContent))
@@ -1261,6 +1691,309 @@ func (s *TemplateService) GetTemplateContentWithParsedData(ctx context.Context,
 	}, nil
 }
 
+// templateFilesInternal returns a template's compose and env content, fetching
+// it from the registry for remote templates.
+func (s *TemplateService) templateFilesInternal(ctx context.Context, composeTemplate *models.ComposeTemplate) (string, string, error) {
+	if composeTemplate.IsRemote {
+		composeContent, envContent, err := s.FetchTemplateContent(ctx, composeTemplate)
+		if err != nil {
+			return "", "", errors.WrapIf(err, "failed to fetch template content")
+		}
+		return composeContent, envContent, nil
+	}
+
+	var envContent string
+	if composeTemplate.EnvContent != nil {
+		envContent = *composeTemplate.EnvContent
+	}
+	return composeTemplate.Content, envContent, nil
+}
+
+// templateVariablePattern matches compose interpolation: an escaped "$$", a
+// braced "${NAME}" with an optional default/required/alternate modifier, or a
+// bare "$NAME".
+var templateVariablePattern = regexp.MustCompile(`\$(?:\$|\{([A-Za-z_][A-Za-z0-9_]*)(:?[-?+][^}]*)?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// ValidateTemplate reports whether every variable a template references
+// resolves before deploy. Values come from the materialized global variables,
+// the template's own env content and providedVars; references with a default
+// or alternate value ("${VAR:-x}", "${VAR+x}") never count as missing.
+func (s *TemplateService) ValidateTemplate(ctx context.Context, id string, providedVars map[string]string) (*tmpl.ValidationResult, error) {
+	composeTemplate, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	composeContent, envContent, err := s.templateFilesInternal(ctx, composeTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.validateTemplateContentInternal(ctx, composeContent, envContent, providedVars)
+}
+
+func (s *TemplateService) validateTemplateContentInternal(ctx context.Context, composeContent, envContent string, providedVars map[string]string) (*tmpl.ValidationResult, error) {
+	globalVars, err := s.loadGlobalVariablesInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]bool)
+	extractTemplateVariablesInternal(composeContent, required)
+
+	envVars := make(map[string]string)
+	for _, v := range projects.ParseEnvContent(envContent) {
+		envVars[v.Key] = v.Value
+		extractTemplateVariablesInternal(v.Value, required)
+	}
+
+	return buildTemplateValidationResultInternal(required, envVars, globalVars, providedVars), nil
+}
+
+// DeployTemplate creates a stack from a template in environmentID and brings it
+// up. Remote templates are downloaded first. vars are validated like
+// ValidateTemplate and then written into the stack's .env on top of the
+// template's env content. stackName defaults to the template name, and a
+// top-level compose `name:` wins over both, as it does for project creation.
+//
+// Missing required variables fail with ErrTemplateVariablesMissing and a stack
+// name that is already taken fails with ErrTemplateStackExists. A local deploy
+// fails with ErrEnvironmentMaintenance while maintenance mode is on unless
+// maintenanceOverride is set; remote agents enforce their own maintenance mode.
+func (s *TemplateService) DeployTemplate(ctx context.Context, id, environmentID, stackName string, vars map[string]string, maintenanceOverride bool, user models.User) (*tmpl.DeployResult, error) {
+	composeTemplate, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if composeTemplate.IsRemote {
+		composeTemplate, err = s.DownloadTemplate(ctx, composeTemplate)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to download template")
+		}
+	}
+
+	composeContent, envContent, err := s.templateFilesInternal(ctx, composeTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	validation, err := s.validateTemplateContentInternal(ctx, composeContent, envContent, vars)
+	if err != nil {
+		return nil, err
+	}
+	if !validation.Valid {
+		return nil, common.Classify(common.ErrTemplateVariablesMissing, errors.Errorf("Template is missing values for: %s", strings.Join(validation.Missing, ", ")))
+	}
+
+	renderedEnv, err := projects.BuildEffectiveEnvContent(envContent, projects.FormatEnvContent(vars))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to render template env")
+	}
+	envPtr := mo.EmptyableToOption(renderedEnv).ToPointer()
+
+	stackName = strings.TrimSpace(stackName)
+	if stackName == "" {
+		stackName = composeTemplate.Name
+	}
+	if yamlName := projects.ComposeContentProjectName(composeContent); yamlName != "" {
+		stackName = yamlName
+	}
+
+	environmentID = strings.TrimSpace(environmentID)
+	if environmentID == "" || environmentID == types.LOCAL_DOCKER_ENVIRONMENT_ID {
+		return s.deployTemplateLocalInternal(ctx, stackName, composeContent, envPtr, maintenanceOverride, user)
+	}
+	return s.deployTemplateRemoteInternal(ctx, environmentID, stackName, composeContent, envPtr)
+}
+
+func (s *TemplateService) deployTemplateLocalInternal(ctx context.Context, stackName, composeContent string, envContent *string, maintenanceOverride bool, user models.User) (*tmpl.DeployResult, error) {
+	if s.projectService == nil {
+		return nil, errors.New("project service not available")
+	}
+	if err := s.settingsService.CheckMaintenance(ctx, maintenanceOverride); err != nil {
+		return nil, err
+	}
+
+	taken, err := s.projectService.projectNameTakenInternal(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, templateStackExistsErrorInternal(stackName, types.LOCAL_DOCKER_ENVIRONMENT_ID)
+	}
+
+	proj, err := s.projectService.CreateProject(ctx, stackName, composeContent, envContent, nil, user)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create stack")
+	}
+
+	if err := s.projectService.DeployProject(ctx, proj.ID, user, nil); err != nil {
+		return nil, errors.WrapIff(err, "stack %q was created but failed to deploy", proj.Name)
+	}
+
+	return &tmpl.DeployResult{
+		ProjectID:     proj.ID,
+		ProjectName:   proj.Name,
+		EnvironmentID: types.LOCAL_DOCKER_ENVIRONMENT_ID,
+	}, nil
+}
+
+// deployTemplateRemoteInternal creates and starts the stack through the
+// agent's own project endpoints so the agent owns the stack's files.
+func (s *TemplateService) deployTemplateRemoteInternal(ctx context.Context, environmentID, stackName, composeContent string, envContent *string) (*tmpl.DeployResult, error) {
+	if s.environmentService == nil {
+		return nil, errors.New("environment service not available")
+	}
+
+	projectsPath := "/api/environments/" + types.LOCAL_DOCKER_ENVIRONMENT_ID + "/projects"
+
+	query := url.Values{"search": {stackName}, "limit": {"-1"}, "archived": {"all"}}
+	var existing base.Paginated[project.Details]
+	if err := s.environmentService.ProxyJSONRequest(ctx, environmentID, http.MethodGet, projectsPath+"?"+query.Encode(), nil, &existing); err != nil {
+		return nil, errors.WrapIff(err, "failed to list stacks in environment %s", environmentID)
+	}
+	sanitized := projects.SanitizeProjectName(stackName)
+	for _, p := range existing.Data {
+		if p.Name == stackName || p.DirName == sanitized {
+			return nil, templateStackExistsErrorInternal(stackName, environmentID)
+		}
+	}
+
+	body, err := json.Marshal(project.CreateProject{
+		Name:           stackName,
+		ComposeContent: composeContent,
+		EnvContent:     envContent,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to marshal stack create request")
+	}
+
+	var created base.ApiResponse[project.CreateReponse]
+	if err := s.environmentService.ProxyJSONRequest(ctx, environmentID, http.MethodPost, projectsPath, body, &created); err != nil {
+		return nil, errors.WrapIff(err, "failed to create stack in environment %s", environmentID)
+	}
+
+	// The up endpoint streams NDJSON progress and can pull or build images,
+	// so it gets a deploy-sized deadline and its lines are checked here.
+	deployCtx, cancel := context.WithTimeout(ctx, timeouts.DefaultProjectDeploy)
+	defer cancel()
+	resp, err := s.environmentService.ExecuteRemoteStreamRequest(deployCtx, environmentID, http.MethodPost, projectsPath+"/"+url.PathEscape(created.Data.ID)+"/up", nil)
+	if err == nil {
+		err = resp.RequireSuccess()
+	}
+	if err == nil {
+		err = checkDeployStreamInternal(resp.Body)
+	}
+	if err != nil {
+		return nil, errors.WrapIff(err, "stack %q was created but failed to deploy", created.Data.Name)
+	}
+
+	return &tmpl.DeployResult{
+		ProjectID:     created.Data.ID,
+		ProjectName:   created.Data.Name,
+		EnvironmentID: environmentID,
+	}, nil
+}
+
+// checkDeployStreamInternal scans a project up NDJSON stream and fails on an
+// error line or when the stream ends without its done line.
+func checkDeployStreamInternal(body []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg struct {
+			Error string `json:"error"`
+			Done  bool   `json:"done"`
+		}
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+		if msg.Error != "" {
+			return errors.New(msg.Error)
+		}
+		if msg.Done {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.WrapIf(err, "failed to read deploy stream")
+	}
+	return errors.New("deploy stream ended before completion")
+}
+
+func templateStackExistsErrorInternal(stackName, environmentID string) error {
+	return common.Classify(common.ErrTemplateStackExists, errors.Errorf("A stack named %q already exists in environment %s", stackName, environmentID))
+}
+
+// loadGlobalVariablesInternal reads the global variables materialized into the
+// projects directory's .env.global, which is what deployments interpolate with.
+func (s *TemplateService) loadGlobalVariablesInternal(ctx context.Context) (map[string]string, error) {
+	if s.settingsService == nil {
+		return nil, nil
+	}
+
+	projectsDirectory, err := projects.GetProjectsDirectory(ctx, s.settingsService.GetStringSetting(ctx, "projectsDirectory", "/app/data/projects"))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get projects directory")
+	}
+
+	globalVars, err := projects.ParseProjectEnvFile(filepath.Join(projectsDirectory, projects.GlobalEnvFileName), nil)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to read global variables")
+	}
+	return globalVars, nil
+}
+
+// extractTemplateVariablesInternal records every variable referenced in content.
+// A variable is marked required unless all of its references carry a default
+// or alternate value.
+func extractTemplateVariablesInternal(content string, required map[string]bool) {
+	for _, match := range templateVariablePattern.FindAllStringSubmatch(content, -1) {
+		name, modifier := match[1], match[2]
+		if name == "" {
+			name = match[3]
+		}
+		if name == "" {
+			continue
+		}
+
+		optional := modifier != "" && !strings.HasPrefix(strings.TrimPrefix(modifier, ":"), "?")
+		required[name] = required[name] || !optional
+	}
+}
+
+func buildTemplateValidationResultInternal(required map[string]bool, envVars, globalVars, providedVars map[string]string) *tmpl.ValidationResult {
+	hasValue := func(key string) bool {
+		return providedVars[key] != "" || envVars[key] != "" || globalVars[key] != ""
+	}
+
+	result := &tmpl.ValidationResult{
+		Variables: slices.Sorted(maps.Keys(required)),
+		Missing:   []string{},
+		Unused:    []string{},
+	}
+	for _, name := range result.Variables {
+		if required[name] && !hasValue(name) {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(providedVars)) {
+		_, referenced := required[key]
+		_, declared := envVars[key]
+		if !referenced && !declared {
+			result.Unused = append(result.Unused, key)
+		}
+	}
+	result.Valid = len(result.Missing) == 0
+
+	return result
+}
+
 func (s *TemplateService) getMergedTemplates(ctx context.Context) ([]models.ComposeTemplate, error) {
 	if err := s.syncFilesystemTemplatesInternal(ctx); err != nil {
 		slog.WarnContext(ctx, "failed to sync filesystem templates", "error", err)
@@ -24,7 +24,10 @@ import (
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/pagination"
 	httputils "github.com/getarcaneapp/arcane/backend/v2/pkg/utils/httpx"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/utils/mapper"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
 	tmpl "github.com/getarcaneapp/arcane/types/v2/template"
+	"go.getarcane.app/sys/crypto"
 )
 
 func setupTemplateServiceTestDB(t *testing.T) *database.DB {
@@ -235,6 +238,120 @@ services:
 	require.EqualValues(t, 1, composeHits.Load())
 }
 
+func TestTemplateRegistry_BearerAuthIsEncryptedAndSentToRegistryHost(t *testing.T) {
+	crypto.InitEncryption(&crypto.Config{
+		EncryptionKey: "test-encryption-key-for-template-registries-32b",
+		Environment:   "test",
+	})
+
+	var composeHits atomic.Int32
+	var composeURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/registry.json":
+			_, _ = w.Write([]byte(`{
+  "name": "Private Registry",
+  "description": "Team templates",
+  "version": "1.0.0",
+  "author": "Arcane",
+  "templates": [
+    {
+      "id": "app",
+      "name": "App",
+      "description": "",
+      "version": "1.0.0",
+      "author": "Arcane",
+      "compose_url": "` + composeURL + `",
+      "env_url": "",
+      "documentation_url": "",
+      "tags": []
+    }
+  ]
+}`))
+		case "/app.yml":
+			composeHits.Add(1)
+			_, _ = w.Write([]byte("services:\n  app:\n    image: nginx:alpine\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, lookupIP, baseURL := makePublicTestClient(t, server)
+	composeURL = baseURL + "/app.yml"
+
+	ctx := context.Background()
+	service := &TemplateService{
+		db:                setupTemplateServiceTestDB(t),
+		httpClient:        client,
+		lookupIP:          lookupIP,
+		registryFetchMeta: make(map[string]*registryFetchMeta),
+	}
+
+	registry := &models.TemplateRegistry{
+		URL:        baseURL + "/registry.json",
+		Enabled:    true,
+		AuthType:   models.TemplateRegistryAuthBearer,
+		Credential: "s3cret",
+	}
+	require.NoError(t, service.CreateRegistry(ctx, registry))
+	require.Equal(t, "Private Registry", registry.Name)
+
+	var stored models.TemplateRegistry
+	require.NoError(t, service.db.WithContext(ctx).First(&stored, "id = ?", registry.ID).Error)
+	require.NotEqual(t, "s3cret", stored.Credential)
+	decrypted, err := crypto.Decrypt(stored.Credential)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", decrypted)
+
+	var out tmpl.TemplateRegistry
+	require.NoError(t, mapper.MapStruct(stored, &out))
+	require.Equal(t, models.TemplateRegistryAuthBearer, out.AuthType)
+	require.True(t, out.HasCredential)
+
+	templates, err := service.fetchRegistryTemplates(ctx, &stored)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.EqualValues(t, 1, composeHits.Load())
+
+	// Updating without auth fields keeps the stored credential.
+	require.NoError(t, service.UpdateRegistry(ctx, registry.ID, &models.TemplateRegistry{
+		Name:        "Renamed",
+		Description: "Team templates",
+		URL:         registry.URL,
+		Enabled:     true,
+	}))
+	require.NoError(t, service.db.WithContext(ctx).First(&stored, "id = ?", registry.ID).Error)
+	require.Equal(t, models.TemplateRegistryAuthBearer, stored.AuthType)
+	decrypted, err = crypto.Decrypt(stored.Credential)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", decrypted)
+
+	err = service.UpdateRegistry(ctx, registry.ID, &models.TemplateRegistry{
+		Name:        "Renamed",
+		Description: "Team templates",
+		URL:         registry.URL,
+		Enabled:     true,
+		AuthType:    models.TemplateRegistryAuthBasic,
+	})
+	require.ErrorIs(t, err, common.ErrTemplateRegistryAuthInvalid)
+
+	// Moving the registry to another host without a new credential drops the stored one.
+	require.NoError(t, service.UpdateRegistry(ctx, registry.ID, &models.TemplateRegistry{
+		Name:        "Renamed",
+		Description: "Team templates",
+		URL:         "https://templates.example.com/registry.json",
+		Enabled:     true,
+	}))
+	require.NoError(t, service.db.WithContext(ctx).First(&stored, "id = ?", registry.ID).Error)
+	require.Equal(t, models.TemplateRegistryAuthNone, stored.AuthType)
+	require.Empty(t, stored.Credential)
+}
+
 func TestDownloadTemplate_PreservesIconURL(t *testing.T) {
 	tempDir := t.TempDir()
 	setTestWorkingDir(t, tempDir)
@@ -298,6 +415,173 @@ services:
 	require.Equal(t, "https://cdn.example/download.png", *stored.Metadata.IconURL)
 }
 
+func TestCheckTemplateUpdates_DetectsAndRefreshesUpstreamChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	setTestWorkingDir(t, tempDir)
+
+	var upstream atomic.Value
+	upstream.Store("services:\n  app:\n    image: nginx:1.27\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compose.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(upstream.Load().(string)))
+	}))
+	defer server.Close()
+
+	client, lookupIP, baseURL := makePublicTestClient(t, server)
+
+	settingsSvc := minimalSettingsServiceForTest(t)
+	require.NoError(t, settingsSvc.UpdateSetting(context.Background(), "templatesDirectory", filepath.Join(tempDir, "templates")))
+
+	service := &TemplateService{
+		db:                setupTemplateServiceTestDB(t),
+		httpClient:        client,
+		lookupIP:          lookupIP,
+		settingsService:   settingsSvc,
+		registryFetchMeta: make(map[string]*registryFetchMeta),
+	}
+
+	ctx := context.Background()
+	downloaded, err := service.DownloadTemplate(ctx, &models.ComposeTemplate{
+		BaseModel:  models.BaseModel{ID: "remote:reg-1:demo"},
+		Name:       "Demo",
+		IsRemote:   true,
+		RegistryID: mo.EmptyableToOption("reg-1").ToPointer(),
+		Metadata: &models.ComposeTemplateMetadata{
+			RemoteURL: mo.EmptyableToOption(baseURL + "/compose.yaml").ToPointer(),
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, downloaded.SourceURL)
+	require.Equal(t, baseURL+"/compose.yaml", *downloaded.SourceURL)
+	require.NotNil(t, downloaded.SourceDigest)
+
+	updates, err := service.CheckTemplateUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.Equal(t, downloaded.ID, updates[0].TemplateID)
+	require.False(t, updates[0].HasUpdate)
+	require.Empty(t, updates[0].Error)
+
+	upstream.Store("services:\n  app:\n    image: nginx:1.28\n")
+
+	updates, err = service.CheckTemplateUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.True(t, updates[0].HasUpdate)
+
+	refreshed, err := service.RefreshTemplate(ctx, downloaded.ID)
+	require.NoError(t, err)
+	require.Contains(t, refreshed.Content, "nginx:1.28")
+
+	updates, err = service.CheckTemplateUpdates(ctx)
+	require.NoError(t, err)
+	require.False(t, updates[0].HasUpdate)
+
+	_, err = service.RefreshTemplate(ctx, "does-not-exist")
+	require.ErrorIs(t, err, common.ErrTemplateNotFound)
+}
+
+func TestRefreshTemplate_UsesStoredDirectoryAfterRename(t *testing.T) {
+	tempDir := t.TempDir()
+	setTestWorkingDir(t, tempDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("services:\n  app:\n    image: nginx:1.28\n"))
+	}))
+	defer server.Close()
+
+	client, lookupIP, baseURL := makePublicTestClient(t, server)
+
+	templatesDir := filepath.Join(tempDir, "templates")
+	settingsSvc := minimalSettingsServiceForTest(t)
+	require.NoError(t, settingsSvc.UpdateSetting(context.Background(), "templatesDirectory", templatesDir))
+
+	service := &TemplateService{
+		db:                setupTemplateServiceTestDB(t),
+		httpClient:        client,
+		lookupIP:          lookupIP,
+		settingsService:   settingsSvc,
+		registryFetchMeta: make(map[string]*registryFetchMeta),
+	}
+
+	ctx := context.Background()
+	downloaded, err := service.DownloadTemplate(ctx, &models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "remote:reg-1:demo"},
+		Name:      "Demo",
+		IsRemote:  true,
+		Metadata: &models.ComposeTemplateMetadata{
+			RemoteURL: mo.EmptyableToOption(baseURL + "/compose.yaml").ToPointer(),
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.db.WithContext(ctx).Model(&models.ComposeTemplate{}).Where("id = ?", downloaded.ID).Update("name", "Renamed").Error)
+
+	_, err = service.RefreshTemplate(ctx, downloaded.ID)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(templatesDir, "demo", "compose.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "nginx:1.28")
+	require.NoDirExists(t, filepath.Join(templatesDir, "renamed"))
+}
+
+func TestRefreshTemplate_RejectsDirectoryOutsideTemplatesDir(t *testing.T) {
+	tempDir := t.TempDir()
+	setTestWorkingDir(t, tempDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("services:\n  app:\n    image: nginx:1.28\n"))
+	}))
+	defer server.Close()
+
+	client, lookupIP, baseURL := makePublicTestClient(t, server)
+
+	templatesDir := filepath.Join(tempDir, "templates")
+	settingsSvc := minimalSettingsServiceForTest(t)
+	require.NoError(t, settingsSvc.UpdateSetting(context.Background(), "templatesDirectory", templatesDir))
+
+	service := &TemplateService{
+		db:                setupTemplateServiceTestDB(t),
+		httpClient:        client,
+		lookupIP:          lookupIP,
+		settingsService:   settingsSvc,
+		registryFetchMeta: make(map[string]*registryFetchMeta),
+	}
+
+	ctx := context.Background()
+	sourceURL := baseURL + "/compose.yaml"
+
+	escaping := &models.ComposeTemplate{
+		BaseModel:   models.BaseModel{ID: "escaping"},
+		Name:        "Escaping",
+		Description: "Imported from " + filepath.Join(templatesDir, "..", "..", "x", "compose.yaml"),
+		Content:     "services: {}",
+		SourceURL:   &sourceURL,
+	}
+	require.NoError(t, service.db.WithContext(ctx).Create(escaping).Error)
+
+	_, err := service.RefreshTemplate(ctx, escaping.ID)
+	require.ErrorIs(t, err, common.ErrTemplatePathInvalid)
+	require.NoFileExists(t, filepath.Join(tempDir, "..", "x", "compose.yaml"))
+
+	traversalName := &models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "traversal-name"},
+		Name:      "../../x",
+		Content:   "services: {}",
+		SourceURL: &sourceURL,
+	}
+	require.NoError(t, service.db.WithContext(ctx).Create(traversalName).Error)
+
+	_, err = service.RefreshTemplate(ctx, traversalName.ID)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(templatesDir, "x", "compose.yaml"))
+	require.NoFileExists(t, filepath.Join(tempDir, "..", "x", "compose.yaml"))
+}
+
 func TestGetAllTemplatesPaginated_FiltersByType(t *testing.T) {
 	tempDir := t.TempDir()
 	setTestWorkingDir(t, tempDir)
@@ -324,7 +608,7 @@ func TestGetAllTemplatesPaginated_FiltersByType(t *testing.T) {
 	}
 	require.NoError(t, db.WithContext(context.Background()).Create(&localTemplates).Error)
 
-	service := NewTemplateService(context.Background(), db, http.DefaultClient, nil)
+	service := NewTemplateService(context.Background(), db, http.DefaultClient, nil, nil, nil)
 	service.remoteCache.Set(struct{}{}, []models.ComposeTemplate{
 		{
 			BaseModel:   models.BaseModel{ID: "remote-one", CreatedAt: now, UpdatedAt: &now},
@@ -358,6 +642,102 @@ func TestGetAllTemplatesPaginated_FiltersByType(t *testing.T) {
 	}
 }
 
+func TestGetAllTemplatesPaginated_FiltersByCategoryAndTags(t *testing.T) {
+	tempDir := t.TempDir()
+	setTestWorkingDir(t, tempDir)
+
+	ctx := context.Background()
+	db := setupTemplateServiceTestDB(t)
+	service := NewTemplateService(ctx, db, http.DefaultClient, nil, nil, nil)
+
+	require.NoError(t, service.CreateTemplate(ctx, &models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "grafana"},
+		Name:      "Grafana",
+		Category:  " Monitoring ",
+		Content:   "services: {}",
+		Metadata:  &models.ComposeTemplateMetadata{Tags: []string{"dashboards", " ", "Dashboards"}},
+	}))
+	require.NoError(t, service.CreateTemplate(ctx, &models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "postgres"},
+		Name:      "Postgres",
+		Category:  "Databases",
+		Content:   "services: {}",
+	}))
+	service.remoteCache.Set(struct{}{}, []models.ComposeTemplate{
+		{
+			BaseModel: models.BaseModel{ID: "remote-prometheus"},
+			Name:      "Prometheus",
+			Category:  "monitoring",
+			IsRemote:  true,
+			Metadata:  &models.ComposeTemplateMetadata{Tags: []string{"metrics"}},
+		},
+	})
+
+	var stored models.ComposeTemplate
+	require.NoError(t, db.WithContext(ctx).First(&stored, "id = ?", "grafana").Error)
+	require.Equal(t, "Monitoring", stored.Category)
+	require.Equal(t, []string{"dashboards"}, stored.Metadata.Tags)
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		wantIDs []string
+	}{
+		{name: "category is case-insensitive", filters: map[string]string{"category": "MONITORING"}, wantIDs: []string{"grafana", "remote-prometheus"}},
+		{name: "any of several categories", filters: map[string]string{"category": "databases,monitoring"}, wantIDs: []string{"grafana", "postgres", "remote-prometheus"}},
+		{name: "tag", filters: map[string]string{"tags": "metrics"}, wantIDs: []string{"remote-prometheus"}},
+		{name: "category and tag", filters: map[string]string{"category": "monitoring", "tags": "dashboards"}, wantIDs: []string{"grafana"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, _, err := service.GetAllTemplatesPaginated(ctx, pagination.QueryParams{
+				Params:  pagination.Params{Start: 0, Limit: 20},
+				Filters: tt.filters,
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.wantIDs, templateIDsInternal(templates))
+		})
+	}
+
+	categories, err := service.GetTemplateCategories(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Databases", "Monitoring"}, categories)
+}
+
+func TestUpdateTemplate_NilTagsKeepAndEmptyTagsClear(t *testing.T) {
+	tempDir := t.TempDir()
+	setTestWorkingDir(t, tempDir)
+
+	ctx := context.Background()
+	db := setupTemplateServiceTestDB(t)
+	service := NewTemplateService(ctx, db, http.DefaultClient, nil, nil, nil)
+
+	require.NoError(t, service.CreateTemplate(ctx, &models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "grafana"},
+		Name:      "Grafana",
+		Content:   "services: {}",
+		Metadata:  &models.ComposeTemplateMetadata{Tags: []string{"dashboards"}},
+	}))
+
+	require.NoError(t, service.UpdateTemplate(ctx, "grafana", &models.ComposeTemplate{Name: "Grafana", Content: "services: {}"}))
+	var stored models.ComposeTemplate
+	require.NoError(t, db.WithContext(ctx).First(&stored, "id = ?", "grafana").Error)
+	require.NotNil(t, stored.Metadata)
+	require.Equal(t, []string{"dashboards"}, stored.Metadata.Tags)
+
+	require.NoError(t, service.UpdateTemplate(ctx, "grafana", &models.ComposeTemplate{
+		Name:     "Grafana",
+		Content:  "services: {}",
+		Metadata: &models.ComposeTemplateMetadata{Tags: []string{}},
+	}))
+	stored = models.ComposeTemplate{}
+	require.NoError(t, db.WithContext(ctx).First(&stored, "id = ?", "grafana").Error)
+	if stored.Metadata != nil {
+		require.Empty(t, stored.Metadata.Tags)
+	}
+}
+
 func templateIDsInternal(templates []tmpl.Template) []string {
 	ids := make([]string, 0, len(templates))
 	for _, template := range templates {
@@ -373,7 +753,7 @@ func TestFetchRaw_BlocksUnsafeRemoteURL(t *testing.T) {
 		registryFetchMeta: make(map[string]*registryFetchMeta),
 	}
 
-	_, err := service.FetchRaw(context.Background(), "http://127.0.0.1:8080/registry.json")
+	_, err := service.FetchRaw(context.Background(), "http://127.0.0.1:8080/registry.json", nil)
 	require.Error(t, err)
 	require.ErrorIs(t, err, common.ErrUnsafeRemoteURL)
 }
@@ -460,7 +840,7 @@ func TestGetTemplate_ForceRefreshesRemoteCacheOnMiss(t *testing.T) {
 	require.NoError(t, settingsSvc.UpdateSetting(context.Background(), "templatesDirectory", filepath.Join(tempDir, "templates")))
 	require.NoError(t, settingsSvc.UpdateSetting(context.Background(), "projectsDirectory", filepath.Join(tempDir, "projects")))
 
-	service := NewTemplateService(context.Background(), db, client, settingsSvc)
+	service := NewTemplateService(context.Background(), db, client, settingsSvc, nil, nil)
 	service.lookupIP = lookupIP
 	service.safeHTTPClient = service.newSafeHTTPClientInternal()
 
@@ -478,6 +858,87 @@ func TestGetTemplate_ForceRefreshesRemoteCacheOnMiss(t *testing.T) {
 	require.Contains(t, err.Error(), "not found")
 }
 
+func TestValidateTemplate_ReportsMissingAndUnusedVariables(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+
+	settingsSvc := minimalSettingsServiceForTest(t)
+	require.NoError(t, settingsSvc.UpdateSetting(ctx, "templatesDirectory", filepath.Join(tempDir, "templates")))
+	require.NoError(t, settingsSvc.UpdateSetting(ctx, "projectsDirectory", filepath.Join(tempDir, "projects")))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "projects"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "projects", ".env.global"), []byte("TZ=UTC\n"), 0o600))
+
+	db := setupTemplateServiceTestDB(t)
+	envContent := "APP_PORT=8080\nDB_PASSWORD=\nPUBLIC_URL=http://${HOST}:${APP_PORT}\n"
+	require.NoError(t, db.WithContext(ctx).Create(&models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "tmpl-1"},
+		Name:      "app",
+		Content: `services:
+  app:
+    image: app:${TAG:-latest}
+    ports:
+      - "${APP_PORT}:80"
+    environment:
+      TZ: $TZ
+      DB_PASSWORD: ${DB_PASSWORD:?set a password}
+      DEBUG: ${DEBUG+1}
+      LITERAL: $${NOT_A_VAR}
+`,
+		EnvContent: &envContent,
+	}).Error)
+
+	service := NewTemplateService(ctx, db, http.DefaultClient, settingsSvc, nil, nil)
+
+	result, err := service.ValidateTemplate(ctx, "tmpl-1", map[string]string{"HOST": "example.com", "EXTRA": "1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"APP_PORT", "DB_PASSWORD", "DEBUG", "HOST", "TAG", "TZ"}, result.Variables)
+	require.Equal(t, []string{"DB_PASSWORD"}, result.Missing)
+	require.Equal(t, []string{"EXTRA"}, result.Unused)
+	require.False(t, result.Valid)
+
+	result, err = service.ValidateTemplate(ctx, "tmpl-1", map[string]string{"HOST": "example.com", "DB_PASSWORD": "secret"})
+	require.NoError(t, err)
+	require.Empty(t, result.Missing)
+	require.Empty(t, result.Unused)
+	require.True(t, result.Valid)
+}
+
+func TestDeployTemplate_RejectsMissingVariablesAndExistingStack(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+
+	settingsSvc := minimalSettingsServiceForTest(t)
+	require.NoError(t, settingsSvc.UpdateSetting(ctx, "templatesDirectory", filepath.Join(tempDir, "templates")))
+	require.NoError(t, settingsSvc.UpdateSetting(ctx, "projectsDirectory", filepath.Join(tempDir, "projects")))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "projects"), 0o755))
+
+	db := setupTemplateServiceTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Project{}))
+	require.NoError(t, db.WithContext(ctx).Create(&models.ComposeTemplate{
+		BaseModel: models.BaseModel{ID: "tmpl-1"},
+		Name:      "app",
+		Content:   "services:\n  app:\n    image: app:latest\n    environment:\n      DB_PASSWORD: ${DB_PASSWORD}\n",
+	}).Error)
+	require.NoError(t, db.WithContext(ctx).Create(&models.Project{Name: "taken", Path: filepath.Join(tempDir, "projects", "taken")}).Error)
+
+	projectSvc := &ProjectService{db: db, settingsService: settingsSvc}
+	service := NewTemplateService(ctx, db, http.DefaultClient, settingsSvc, projectSvc, nil)
+
+	_, err := service.DeployTemplate(ctx, "tmpl-1", "0", "fresh", nil, false, models.User{})
+	require.ErrorIs(t, err, common.ErrTemplateVariablesMissing)
+	require.ErrorIs(t, err, common.ErrValidation)
+	require.Contains(t, err.Error(), "DB_PASSWORD")
+
+	_, err = service.DeployTemplate(ctx, "tmpl-1", "0", "taken", map[string]string{"DB_PASSWORD": "secret"}, false, models.User{})
+	require.ErrorIs(t, err, common.ErrTemplateStackExists)
+	require.ErrorIs(t, err, common.ErrConflict)
+
+	_, err = settingsSvc.SetMaintenance(ctx, environment.MaintenanceUpdate{Enabled: true})
+	require.NoError(t, err)
+	_, err = service.DeployTemplate(ctx, "tmpl-1", "0", "fresh", map[string]string{"DB_PASSWORD": "secret"}, false, models.User{})
+	require.ErrorIs(t, err, common.ErrEnvironmentMaintenance)
+}
+
 func minimalSettingsServiceForTest(t *testing.T) *SettingsService {
 	t.Helper()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
@@ -487,3 +948,17 @@ func minimalSettingsServiceForTest(t *testing.T) *SettingsService {
 	require.NoError(t, err)
 	return svc
 }
+
+func TestCheckDeployStreamInternal(t *testing.T) {
+	started := `{"type":"activity","activityId":"act-1"}` + "\n"
+
+	require.NoError(t, checkDeployStreamInternal([]byte(started+`{"status":"Pulling"}`+"\n"+`{"done":true}`+"\n")))
+
+	err := checkDeployStreamInternal([]byte(started + `{"error":"pull access denied"}` + "\n"))
+	require.ErrorContains(t, err, "pull access denied")
+
+	err = checkDeployStreamInternal([]byte(started + `{"status":"Pulling"}` + "\n"))
+	require.ErrorContains(t, err, "ended before completion")
+
+	require.Error(t, checkDeployStreamInternal(nil))
+}
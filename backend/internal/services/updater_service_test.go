@@ -376,7 +376,7 @@ func TestUpdaterService_PullImageAdapterInternal(t *testing.T) {
 		db := setupProjectTestDB(t)
 		server := newProjectImagePullServer(t, nil)
 		dockerSvc := &DockerClientService{client: newTestDockerClient(t, server)}
-		imageSvc := NewImageService(db, dockerSvc, nil, nil, nil, NewEventService(db, nil, nil))
+		imageSvc := NewImageService(db, dockerSvc, nil, nil, nil, NewEventService(db, nil, nil), nil)
 		svc := NewUpdaterService(db, nil, dockerSvc, nil, nil, nil, nil, imageSvc, nil, nil, nil)
 		var progress bytes.Buffer
 
@@ -404,7 +404,7 @@ func TestUpdaterService_PullImageAdapterInternal(t *testing.T) {
 		})
 		dockerSvc := &DockerClientService{client: newTestDockerClient(t, server)}
 		registrySvc := NewContainerRegistryService(db, nil, NewKVService(db))
-		imageSvc := NewImageService(db, dockerSvc, registrySvc, nil, nil, NewEventService(db, nil, nil))
+		imageSvc := NewImageService(db, dockerSvc, registrySvc, nil, nil, NewEventService(db, nil, nil), nil)
 		envSvc := NewEnvironmentService(db, nil, nil, nil, nil, nil)
 		projectSvc := NewProjectService(db, nil, nil, nil, nil, nil, nil, nil, nil).
 			WithRegistryCredentialsProvider(envSvc.GetEnabledRegistryCredentials)
@@ -494,7 +494,7 @@ func TestUpdaterService_PendingImageUpdatesFlushesPendingNotificationsInternal(t
 		},
 	}).Error)
 
-	notif := NewNotificationService(db, nil, nil, nil)
+	notif := NewNotificationService(db, nil, nil, nil, nil)
 	imageUpdates := NewImageUpdateService(db, nil, nil, nil, nil, notif, nil)
 	svc := NewUpdaterService(db, nil, nil, nil, imageUpdates, nil, nil, nil, notif, nil, nil)
 
@@ -524,7 +524,7 @@ func TestUpdaterService_PendingImageUpdatesNoProvidersLeavesUnnotifiedInternal(t
 	db := setupProjectTestDB(t)
 	require.NoError(t, db.AutoMigrate(&models.ImageUpdateRecord{}, &models.NotificationSettings{}))
 
-	notif := NewNotificationService(db, nil, nil, nil)
+	notif := NewNotificationService(db, nil, nil, nil, nil)
 	imageUpdates := NewImageUpdateService(db, nil, nil, nil, nil, notif, nil)
 	svc := NewUpdaterService(db, nil, nil, nil, imageUpdates, nil, nil, nil, notif, nil, nil)
 
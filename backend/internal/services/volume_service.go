@@ -3,6 +3,7 @@ package services
 import (
 	"archive/tar"
 	"bytes"
+	"cmp"
 	"compress/gzip"
 	"context"
 	"fmt"
@@ -11,12 +12,14 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
 
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
@@ -210,7 +213,13 @@ func (s *VolumeService) PruneVolumes(ctx context.Context) (*volumetypes.PruneRep
 }
 
 func (s *VolumeService) PruneVolumesWithOptions(ctx context.Context, all bool) (*volumetypes.PruneReport, error) {
-	slog.DebugContext(ctx, "volume service: prune volumes with options", "all", all)
+	return s.PruneVolumesWithFilters(ctx, all, nil)
+}
+
+// PruneVolumesWithFilters prunes unused volumes like PruneVolumesWithOptions, limited to
+// volumes matching labelFilters (see system.PruneAllRequest.LabelFilters).
+func (s *VolumeService) PruneVolumesWithFilters(ctx context.Context, all bool, labelFilters map[string]string) (*volumetypes.PruneReport, error) {
+	slog.DebugContext(ctx, "volume service: prune volumes with options", "all", all, "labelFilters", labelFilters)
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to connect to Docker")
@@ -228,6 +237,12 @@ func (s *VolumeService) PruneVolumesWithOptions(ctx context.Context, all bool) (
 	// - With 'all=true' flag: Removes ALL unused volumes (both named and anonymous)
 	// Note: Volumes are considered "in use" if referenced by any container (running or stopped)
 	volumePruneOptions := buildVolumePruneOptionsInternal(all, preserveTrivyCache)
+	if len(labelFilters) > 0 {
+		if volumePruneOptions.Filters == nil {
+			volumePruneOptions.Filters = make(client.Filters)
+		}
+		volumePruneOptions.Filters = applyPruneLabelFiltersInternal(volumePruneOptions.Filters, labelFilters)
+	}
 	volumePruneResult, err := dockerClient.VolumePrune(ctx, volumePruneOptions)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to prune volumes")
@@ -300,64 +315,45 @@ func (s *VolumeService) isBrowsableVolumeInternal(ctx context.Context, volumeNam
 	return nil
 }
 
-func (s *VolumeService) ListDirectory(ctx context.Context, volumeName, dirPath string) ([]volumetypes.FileEntry, error) {
-	slog.DebugContext(ctx, "volume service: list directory", "volume", volumeName, "path", dirPath)
+// ListDirectory lists a volume directory with directories first, then files, each
+// sorted by name. opts filters by a name glob and pages the sorted result; the total
+// number of matching entries is returned alongside the page.
+func (s *VolumeService) ListDirectory(ctx context.Context, volumeName, dirPath string, opts volumetypes.ListDirectoryOptions) ([]volumetypes.FileEntry, int, error) {
+	slog.DebugContext(ctx, "volume service: list directory", "volume", volumeName, "path", dirPath, "filter", opts.Filter, "offset", opts.Offset, "limit", opts.Limit)
+
+	if _, err := path.Match(opts.Filter, ""); err != nil {
+		return nil, 0, errors.WrapIf(cerrdefs.ErrInvalidArgument, "invalid filter pattern")
+	}
 
 	if err := s.isBrowsableVolumeInternal(ctx, volumeName); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	sanitizedPath, err := utils.SanitizeBrowsePath(dirPath)
 	if err != nil {
-		return nil, errors.WrapIf(err, "invalid path")
+		return nil, 0, errors.WrapIf(err, "invalid path")
 	}
 
 	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer cleanup()
 
 	targetPath := path.Join("/volume", sanitizedPath)
-	quotedPath := strconv.Quote(targetPath)
-	cmd := []string{"sh", "-c", fmt.Sprintf("find %s -mindepth 1 -maxdepth 1 | while IFS= read -r f; do out=$(stat -c \"%%s %%Y %%f %%A\" -- \"$f\" 2>/dev/null) || continue; printf \"%%s\\0%%s\\0\" \"$f\" \"$out\"; done", quotedPath)}
-	stdout, _, err := s.execInContainerInternal(ctx, containerID, cmd)
+	stdout, _, err := s.execInContainerInternal(ctx, containerID, statListingCommandInternal(targetPath))
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to list directory")
+		return nil, 0, errors.WrapIf(err, "failed to list directory")
 	}
+	entries := parseStatListingInternal(stdout, "/volume")
 
-	lines := strings.Split(stdout, "\x00")
-	entries := make([]volumetypes.FileEntry, 0)
-	for i := 0; i+1 < len(lines); i += 2 {
-		fullPath := lines[i]
-		meta := strings.Fields(strings.TrimSpace(lines[i+1]))
-		if fullPath == "" || len(meta) < 4 {
-			continue
-		}
-		name := path.Base(fullPath)
-		size, _ := strconv.ParseInt(meta[0], 10, 64)
-		modTimeSec, _ := strconv.ParseInt(meta[1], 10, 64)
-		mode := meta[3]
+	page, total := pageFileEntriesInternal(entries, opts)
 
-		isDir := strings.HasPrefix(mode, "d")
-		isSymlink := strings.HasPrefix(mode, "l")
-
-		relPath := strings.TrimPrefix(fullPath, "/volume")
-		if relPath == "" {
-			relPath = "/"
-		}
-
-		entry := volumetypes.FileEntry{
-			Name:        name,
-			Path:        relPath,
-			IsDirectory: isDir,
-			Size:        size,
-			ModTime:     time.Unix(modTimeSec, 0),
-			Mode:        mode,
-			IsSymlink:   isSymlink,
-		}
-
-		if isSymlink {
+	// Resolve symlinks only for the returned page; each one costs an exec.
+	for i := range page {
+		entry := &page[i]
+		if entry.IsSymlink {
+			fullPath := path.Join("/volume", entry.Path)
 			// Use readlink without -f to get the raw symlink target (not resolved)
 			// This prevents exposing paths outside the volume
 			target, _, _ := s.execInContainerInternal(ctx, containerID, []string{"readlink", fullPath})
@@ -380,11 +376,108 @@ func (s *VolumeService) ListDirectory(ctx context.Context, volumeName, dirPath s
 				}
 			}
 		}
+	}
+
+	return page, total, nil
+}
 
+// statListingCommandInternal lists the direct children of dirPath, printing each
+// path and its "size mtime rawmode mode uid gid user group" stat columns as
+// NUL-separated pairs. It only needs sh, find and stat.
+func statListingCommandInternal(dirPath string) []string {
+	return []string{"sh", "-c", fmt.Sprintf("find %s -mindepth 1 -maxdepth 1 | while IFS= read -r f; do out=$(stat -c \"%%s %%Y %%f %%A %%u %%g %%U %%G\" -- \"$f\" 2>/dev/null) || continue; printf \"%%s\\0%%s\\0\" \"$f\" \"$out\"; done", strconv.Quote(dirPath))}
+}
+
+// parseStatListingInternal parses the output of statListingCommandInternal. Entry
+// paths are reported relative to root, which is stripped from each listed path.
+func parseStatListingInternal(stdout, root string) []volumetypes.FileEntry {
+	lines := strings.Split(stdout, "\x00")
+	entries := make([]volumetypes.FileEntry, 0, len(lines)/2)
+	for i := 0; i+1 < len(lines); i += 2 {
+		fullPath := lines[i]
+		meta := strings.Fields(strings.TrimSpace(lines[i+1]))
+		if fullPath == "" || len(meta) < 4 {
+			continue
+		}
+		size, _ := strconv.ParseInt(meta[0], 10, 64)
+		modTimeSec, _ := strconv.ParseInt(meta[1], 10, 64)
+		mode := meta[3]
+
+		relPath := strings.TrimPrefix(fullPath, root)
+		if relPath == "" {
+			relPath = "/"
+		}
+
+		entry := volumetypes.FileEntry{
+			Name:        path.Base(fullPath),
+			Path:        relPath,
+			IsDirectory: strings.HasPrefix(mode, "d"),
+			Size:        size,
+			ModTime:     time.Unix(modTimeSec, 0),
+			Mode:        mode,
+			IsSymlink:   strings.HasPrefix(mode, "l"),
+		}
+		applyFileOwnershipInternal(&entry, meta[4:])
 		entries = append(entries, entry)
 	}
+	return entries
+}
+
+// applyFileOwnershipInternal fills owner fields from the trailing "%u %g %U %G" stat
+// columns. The helper container rarely shares the volume owner's passwd/group files,
+// so unresolved names ("UNKNOWN" on GNU and busybox) fall back to the numeric IDs.
+func applyFileOwnershipInternal(entry *volumetypes.FileEntry, fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+	uid, uidErr := strconv.Atoi(fields[0])
+	gid, gidErr := strconv.Atoi(fields[1])
+	if uidErr == nil {
+		entry.UID = &uid
+		entry.Owner = fields[0]
+	}
+	if gidErr == nil {
+		entry.GID = &gid
+		entry.Group = fields[1]
+	}
+	if len(fields) >= 4 {
+		if owner := fields[2]; owner != "" && owner != "UNKNOWN" {
+			entry.Owner = owner
+		}
+		if group := fields[3]; group != "" && group != "UNKNOWN" {
+			entry.Group = group
+		}
+	}
+}
+
+// pageFileEntriesInternal filters entries by name glob, sorts directories before
+// files (then by name), and returns the requested page plus the match count.
+// The filter must already be validated with path.Match.
+func pageFileEntriesInternal(entries []volumetypes.FileEntry, opts volumetypes.ListDirectoryOptions) ([]volumetypes.FileEntry, int) {
+	if opts.Filter != "" {
+		entries = slices.DeleteFunc(entries, func(entry volumetypes.FileEntry) bool {
+			matched, _ := path.Match(opts.Filter, entry.Name)
+			return !matched
+		})
+	}
 
-	return entries, nil
+	slices.SortFunc(entries, func(a, b volumetypes.FileEntry) int {
+		if a.IsDirectory != b.IsDirectory {
+			if a.IsDirectory {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), cmp.Compare(a.Name, b.Name))
+	})
+
+	total := len(entries)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, total)
+	}
+	return entries[start:end], total
 }
 
 func (s *VolumeService) GetFileContent(ctx context.Context, volumeName, filePath string, maxBytes int64) ([]byte, string, error) {
@@ -724,6 +817,25 @@ func (s *VolumeService) createTempContainerInternal(ctx context.Context, volumeN
 		}
 	}
 
+	containerID, cleanup, err := s.startVolumeHelperInternal(ctx, dockerClient, volumeName, readOnly)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if readOnly {
+		s.helperMu.Lock()
+		s.helperByVolume[volumeName] = &volumeHelper{id: containerID, lastUsedAt: time.Now()}
+		s.helperMu.Unlock()
+		return containerID, func() {}, nil
+	}
+
+	return containerID, cleanup, nil
+}
+
+// startVolumeHelperInternal starts a helper container with volumeName mounted
+// at /volume. The returned cleanup removes it; callers that keep the helper for
+// reuse register it themselves instead.
+func (s *VolumeService) startVolumeHelperInternal(ctx context.Context, dockerClient *client.Client, volumeName string, readOnly bool) (string, func(), error) {
 	helperImage, err := getVolumeHelperImageInternal(ctx, s.dockerService, s.imageService, dockerClient)
 	if err != nil {
 		return "", nil, err
@@ -758,15 +870,10 @@ func (s *VolumeService) createTempContainerInternal(ctx context.Context, volumeN
 		return "", nil, errors.WrapIf(err, "failed to start temp container")
 	}
 
+	// Removal must outlive a cancelled request, or an aborted stream or failed
+	// restore would leave the helper behind.
 	cleanup := func() {
-		_, _ = dockerClient.ContainerRemove(ctx, resp.ID, volumehelper.RemoveOptions())
-	}
-
-	if readOnly {
-		s.helperMu.Lock()
-		s.helperByVolume[volumeName] = &volumeHelper{id: resp.ID, lastUsedAt: time.Now()}
-		s.helperMu.Unlock()
-		return resp.ID, func() {}, nil
+		_, _ = dockerClient.ContainerRemove(context.WithoutCancel(ctx), resp.ID, volumehelper.RemoveOptions())
 	}
 
 	return resp.ID, cleanup, nil
@@ -1289,27 +1396,25 @@ func (s *VolumeService) ListBackupsPaginated(ctx context.Context, volumeName str
 		return nil, pagination.Response{}, err
 	}
 
-	sortCol := "created_at"
-	sortOrder := "DESC"
-	if params.Sort != "" {
-		switch params.Sort {
-		case "createdAt", "created_at":
-			sortCol = "created_at"
+	keys := params.Keys()
+	if len(keys) == 0 {
+		keys = []pagination.SortKey{{Sort: "createdAt", Order: pagination.SortDesc}}
+	}
+	for _, key := range keys {
+		sortCol := "created_at"
+		switch key.Sort {
 		case "id":
 			sortCol = "id"
 		case "size":
 			sortCol = "size"
-		default:
-			sortCol = "created_at"
 		}
 
-		if params.Order == pagination.SortDesc {
+		sortOrder := "ASC"
+		if key.Order == pagination.SortDesc {
 			sortOrder = "DESC"
-		} else {
-			sortOrder = "ASC"
 		}
+		query = query.Order(fmt.Sprintf("%s %s", sortCol, sortOrder))
 	}
-	query = query.Order(fmt.Sprintf("%s %s", sortCol, sortOrder))
 
 	if params.Limit > 0 {
 		query = query.Offset(params.Start).Limit(params.Limit)
@@ -1842,6 +1947,27 @@ func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string,
 		return errors.WrapIf(err, "failed to create pre-restore backup")
 	}
 
+	if err := s.restoreArchiveIntoVolumeInternal(ctx, volumeName, tmpFile); err != nil {
+		return err
+	}
+
+	metadata := models.JSON{
+		"action":               "backup_upload_restore",
+		"filename":             filename,
+		"pre_restore_backupId": preBackup.ID,
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupRestore, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume backup upload restore event", "volume", volumeName, "error", logErr.Error())
+	}
+
+	return nil
+}
+
+// restoreArchiveIntoVolumeInternal replaces the contents of volumeName with
+// archive, a tar stream that may be compressed in any format Docker accepts.
+// The archive is unpacked into a staging directory on the volume first, so an
+// empty or unreadable archive fails before the existing data is cleared.
+func (s *VolumeService) restoreArchiveIntoVolumeInternal(ctx context.Context, volumeName string, archive io.ReadSeeker) error {
 	dockerClient, err := s.dockerService.GetClient(ctx)
 	if err != nil {
 		return err
@@ -1862,12 +1988,12 @@ func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string,
 		slog.DebugContext(ctx, "volume service: restore temp dir stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
 	}
 
-	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
 		return errors.WrapIf(err, "failed to read buffered upload")
 	}
 	_, err = dockerClient.CopyToContainer(ctx, containerID, client.CopyToContainerOptions{
 		DestinationPath: tmpDir,
-		Content:         tmpFile,
+		Content:         archive,
 	})
 	if err != nil {
 		return errors.WrapIf(err, "failed to restore from uploaded archive")
@@ -1881,7 +2007,8 @@ func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string,
 		slog.DebugContext(ctx, "volume service: restore validate stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
 	}
 
-	_, stderr, err = s.execInContainerInternal(ctx, containerID, []string{"sh", "-c", "rm -rf /volume/* /volume/.[!.]* /volume/..?* 2>/dev/null || true"})
+	clearCmd := fmt.Sprintf("find /volume -mindepth 1 -maxdepth 1 ! -path %s -exec rm -rf -- {} +", tmpDir)
+	_, stderr, err = s.execInContainerInternal(ctx, containerID, []string{"sh", "-c", clearCmd})
 	if err != nil {
 		return errors.WrapIf(err, "failed to clear volume before restore")
 	}
@@ -1898,18 +2025,165 @@ func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string,
 		slog.DebugContext(ctx, "volume service: restore move stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
 	}
 
+	return nil
+}
+
+// BackupVolume streams the current contents of volumeName as an uncompressed
+// tar archive, read through a dedicated read-only helper container. The helper
+// is removed when the returned reader is closed, or straight away when the
+// backup cannot be started.
+//
+// The returned warnings name running containers using the volume, since files
+// they write during the backup may be captured mid-write.
+func (s *VolumeService) BackupVolume(ctx context.Context, volumeName string, user models.User) (io.ReadCloser, []string, error) {
+	slog.DebugContext(ctx, "volume service: backup volume", "volume", volumeName, "user", user.ID)
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := dockerClient.VolumeInspect(ctx, volumeName, client.VolumeInspectOptions{}); err != nil {
+		return nil, nil, errors.WrapIf(err, "failed to inspect volume")
+	}
+
+	warnings, err := s.volumeInUseWarningsInternal(ctx, dockerClient, volumeName, "the backup may capture files mid-write")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	containerID, cleanup, err := s.startVolumeHelperInternal(ctx, dockerClient, volumeName, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	copyResult, err := dockerClient.CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{
+		SourcePath: "/volume/.",
+	})
+	if err != nil {
+		cleanup()
+		return nil, nil, errors.WrapIf(err, "failed to read volume contents")
+	}
+
 	metadata := models.JSON{
-		"action":               "backup_upload_restore",
-		"filename":             filename,
+		"action": "volume_backup_stream",
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupDownload, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume backup stream event", "volume", volumeName, "error", logErr.Error())
+	}
+
+	return &cleanupReadCloser{
+		Reader:  copyResult.Content,
+		Closer:  copyResult.Content,
+		cleanup: cleanup,
+	}, warnings, nil
+}
+
+// RestoreVolume replaces the contents of volumeName with archive, a plain or
+// gzip-compressed tar archive such as one streamed by BackupVolume. Like
+// UploadAndRestore it stores a pre-restore backup of the current contents
+// first, so the restore can be undone.
+//
+// The returned warnings name running containers using the volume; they keep
+// running and may need a restart to pick up the restored data.
+func (s *VolumeService) RestoreVolume(ctx context.Context, volumeName string, archive io.Reader, user models.User) ([]string, error) {
+	slog.DebugContext(ctx, "volume service: restore volume", "volume", volumeName, "user", user.ID)
+
+	dockerClient, err := s.dockerService.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dockerClient.VolumeInspect(ctx, volumeName, client.VolumeInspectOptions{}); err != nil {
+		return nil, errors.WrapIf(err, "volume not found")
+	}
+
+	tmpFile, err := os.CreateTemp("", "arcane-restore-*.tar")
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to buffer upload")
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+	if _, err := io.Copy(tmpFile, archive); err != nil {
+		return nil, errors.WrapIf(err, "failed to buffer upload")
+	}
+	if err := validateVolumeArchiveInternal(tmpFile); err != nil {
+		return nil, err
+	}
+
+	warnings, err := s.volumeInUseWarningsInternal(ctx, dockerClient, volumeName, "restart them to pick up the restored data")
+	if err != nil {
+		return nil, err
+	}
+
+	preBackup, err := s.CreateBackup(ctx, volumeName, user)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create pre-restore backup")
+	}
+
+	if err := s.restoreArchiveIntoVolumeInternal(ctx, volumeName, tmpFile); err != nil {
+		return nil, err
+	}
+
+	metadata := models.JSON{
+		"action":               "volume_restore",
 		"pre_restore_backupId": preBackup.ID,
 	}
 	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupRestore, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
-		slog.WarnContext(ctx, "could not log volume backup upload restore event", "volume", volumeName, "error", logErr.Error())
+		slog.WarnContext(ctx, "could not log volume restore event", "volume", volumeName, "error", logErr.Error())
 	}
 
+	return warnings, nil
+}
+
+// validateVolumeArchiveInternal checks that archive starts with a readable tar
+// header, either plain or gzip-compressed.
+func validateVolumeArchiveInternal(archive io.ReadSeeker) error {
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return errors.WrapIf(err, "failed to read buffered upload")
+	}
+	var reader io.Reader = archive
+	if gzr, err := gzip.NewReader(archive); err == nil {
+		defer func() { _ = gzr.Close() }()
+		reader = gzr
+	} else if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return errors.WrapIf(err, "failed to read buffered upload")
+	}
+	if _, err := tar.NewReader(reader).Next(); err != nil {
+		return errors.WrapIf(cerrdefs.ErrInvalidArgument, "upload is not a valid tar archive")
+	}
 	return nil
 }
 
+// volumeInUseWarningsInternal returns a warning naming the running containers,
+// other than Arcane's own helpers, that mount volumeName, or nil when there are
+// none. advice is appended to say what that means for the operation.
+func (s *VolumeService) volumeInUseWarningsInternal(ctx context.Context, dockerClient *client.Client, volumeName, advice string) ([]string, error) {
+	list, err := dockerClient.ContainerList(ctx, client.ContainerListOptions{
+		Filters: make(client.Filters).Add("volume", volumeName),
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list containers using volume")
+	}
+
+	var names []string
+	for _, c := range list.Items {
+		if libarcane.IsInternalContainer(c.Labels) {
+			continue
+		}
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	slices.Sort(names)
+	return []string{fmt.Sprintf("volume %s is in use by running containers %s; %s", volumeName, strings.Join(names, ", "), advice)}, nil
+}
+
 func (s *VolumeService) GetVolumeUsage(ctx context.Context, name string) (bool, []string, error) {
 	slog.DebugContext(ctx, "volume service: get volume usage", "volume", name)
 	dockerClient, err := s.dockerService.GetClient(ctx)
@@ -1,10 +1,18 @@
 package services
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane"
 	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane/volumehelper"
 	volumetypes "github.com/getarcaneapp/arcane/types/v2/volume"
@@ -464,3 +472,101 @@ func TestTouchHelperInternal(t *testing.T) {
 	s.touchHelperInternal("missing")
 	require.NotContains(t, s.helperByVolume, "missing")
 }
+
+func TestPageFileEntriesInternal(t *testing.T) {
+	entries := []volumetypes.FileEntry{
+		{Name: "b.conf"},
+		{Name: "lib", IsDirectory: true},
+		{Name: "A.conf"},
+		{Name: "notes.txt"},
+		{Name: "etc", IsDirectory: true},
+	}
+
+	page, total := pageFileEntriesInternal(slices.Clone(entries), volumetypes.ListDirectoryOptions{})
+	require.Equal(t, 5, total)
+	names := make([]string, 0, len(page))
+	for _, entry := range page {
+		names = append(names, entry.Name)
+	}
+	require.Equal(t, []string{"etc", "lib", "A.conf", "b.conf", "notes.txt"}, names)
+
+	page, total = pageFileEntriesInternal(slices.Clone(entries), volumetypes.ListDirectoryOptions{Filter: "*.conf", Offset: 1, Limit: 5})
+	require.Equal(t, 2, total)
+	require.Len(t, page, 1)
+	require.Equal(t, "b.conf", page[0].Name)
+
+	page, total = pageFileEntriesInternal(slices.Clone(entries), volumetypes.ListDirectoryOptions{Offset: 10, Limit: 2})
+	require.Equal(t, 5, total)
+	require.Empty(t, page)
+}
+
+func TestApplyFileOwnershipInternal(t *testing.T) {
+	var named volumetypes.FileEntry
+	applyFileOwnershipInternal(&named, []string{"0", "0", "root", "root"})
+	require.Equal(t, "root", named.Owner)
+	require.Equal(t, "root", named.Group)
+	require.Equal(t, 0, *named.UID)
+	require.Equal(t, 0, *named.GID)
+
+	var unresolved volumetypes.FileEntry
+	applyFileOwnershipInternal(&unresolved, []string{"1000", "1001", "UNKNOWN", "UNKNOWN"})
+	require.Equal(t, "1000", unresolved.Owner)
+	require.Equal(t, "1001", unresolved.Group)
+	require.Equal(t, 1000, *unresolved.UID)
+
+	var missing volumetypes.FileEntry
+	applyFileOwnershipInternal(&missing, nil)
+	require.Empty(t, missing.Owner)
+	require.Nil(t, missing.UID)
+}
+
+func TestValidateVolumeArchiveInternal(t *testing.T) {
+	var plain bytes.Buffer
+	tw := tar.NewWriter(&plain)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "./data.txt", Mode: 0o644, Size: 2}))
+	_, err := tw.Write([]byte("hi"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	var compressed bytes.Buffer
+	gzw := gzip.NewWriter(&compressed)
+	_, err = gzw.Write(plain.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gzw.Close())
+
+	require.NoError(t, validateVolumeArchiveInternal(bytes.NewReader(plain.Bytes())))
+	require.NoError(t, validateVolumeArchiveInternal(bytes.NewReader(compressed.Bytes())))
+
+	err = validateVolumeArchiveInternal(bytes.NewReader([]byte("not an archive")))
+	require.True(t, cerrdefs.IsInvalidArgument(err))
+}
+
+func TestVolumeInUseWarningsInternal(t *testing.T) {
+	var gotFilters string
+	containers := []container.Summary{
+		{ID: "b", Names: []string{"/web"}},
+		{ID: "a", Names: []string{"/db"}},
+		{ID: "c", Names: []string{"/arcane-volume-helper"}, Labels: map[string]string{libarcane.InternalResourceLabel: "true"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dockerTestPathInternal(r.URL.Path) != "/containers/json" {
+			http.NotFound(w, r)
+			return
+		}
+		gotFilters = r.URL.Query().Get("filters")
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(containers))
+	}))
+	t.Cleanup(server.Close)
+
+	svc := &VolumeService{}
+	warnings, err := svc.volumeInUseWarningsInternal(context.Background(), newTestDockerClient(t, server), "data", "restart them")
+	require.NoError(t, err)
+	require.Equal(t, []string{"volume data is in use by running containers db, web; restart them"}, warnings)
+	require.Contains(t, gotFilters, `"volume":{"data":true}`)
+
+	containers = containers[2:]
+	warnings, err = svc.volumeInUseWarningsInternal(context.Background(), newTestDockerClient(t, server), "data", "restart them")
+	require.NoError(t, err)
+	require.Nil(t, warnings)
+}
@@ -3868,23 +3868,26 @@ func (s *VulnerabilityService) UnignoreVulnerability(ctx context.Context, envID
 	return nil
 }
 
-func applyIgnoredVulnerabilitiesSort(query *gorm.DB, sort string) *gorm.DB {
-	if sort == "" {
+// applyIgnoredVulnerabilitiesSort orders by each sort key in turn. A key's
+// direction comes from its order, or from a "-"/"+" prefix on the column.
+func applyIgnoredVulnerabilitiesSort(query *gorm.DB, keys []pagination.SortKey) *gorm.DB {
+	if len(keys) == 0 {
 		return query.Order("created_at DESC")
 	}
 
-	for part := range strings.SplitSeq(sort, ",") {
-		part = strings.TrimSpace(part)
+	for _, key := range keys {
+		part := strings.TrimSpace(key.Sort)
 		if part == "" {
 			continue
 		}
 
-		desc := false
+		desc := key.Order == pagination.SortDesc
 		switch {
 		case strings.HasPrefix(part, "-"):
 			desc = true
 			part = strings.TrimPrefix(part, "-")
 		case strings.HasPrefix(part, "+"):
+			desc = false
 			part = strings.TrimPrefix(part, "+")
 		}
 
@@ -3955,7 +3958,7 @@ func (s *VulnerabilityService) ListIgnoredVulnerabilities(ctx context.Context, e
 
 	var ignores []models.VulnerabilityIgnore
 	query := s.db.WithContext(ctx).Where("environment_id = ?", envID)
-	query = applyIgnoredVulnerabilitiesSort(query, params.Sort)
+	query = applyIgnoredVulnerabilitiesSort(query, params.Keys())
 
 	// Count total
 	var total int64
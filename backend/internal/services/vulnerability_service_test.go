@@ -940,3 +940,38 @@ func TestVulnerabilityService_ListAllVulnerabilityImageOptions_InvalidSeverityAn
 	require.NoError(t, err)
 	require.Equal(t, []string{"alpha:latest", "beta:latest", "sha256:two"}, all)
 }
+
+func TestVulnerabilityService_ListIgnoredVulnerabilities_AppliesTieBreakers(t *testing.T) {
+	ctx := context.Background()
+	db := setupVulnerabilityScanTestDB(t)
+	svc := &VulnerabilityService{db: db}
+
+	created := time.Now().Add(-time.Hour)
+	for _, entry := range []struct {
+		id        string
+		createdAt time.Time
+	}{
+		{id: "CVE-2026-0003", createdAt: created},
+		{id: "CVE-2026-0001", createdAt: created},
+		{id: "CVE-2026-0002", createdAt: created.Add(time.Minute)},
+	} {
+		require.NoError(t, db.Create(&models.VulnerabilityIgnore{
+			EnvironmentID:   "env-1",
+			ImageID:         "sha256:img1",
+			VulnerabilityID: entry.id,
+			CreatedAt:       entry.createdAt,
+		}).Error)
+	}
+
+	items, _, err := svc.ListIgnoredVulnerabilities(ctx, "env-1", pagination.QueryParams{
+		SortParams: pagination.ParseSortParams("createdAt,vulnerabilityId", "desc,asc"),
+		Params:     pagination.Params{Start: 0, Limit: 20},
+	})
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.VulnerabilityID)
+	}
+	require.Equal(t, []string{"CVE-2026-0002", "CVE-2026-0001", "CVE-2026-0003"}, ids)
+}
@@ -47,9 +47,10 @@ type WebhookService struct {
 	gitOpsSyncService  *GitOpsSyncService
 	eventService       *EventService
 	environmentService *EnvironmentService
+	settingsService    *SettingsService
 }
 
-func NewWebhookService(db *database.DB, containerService *ContainerService, updaterService *UpdaterService, projectService *ProjectService, gitOpsSyncService *GitOpsSyncService, eventService *EventService, environmentService *EnvironmentService) *WebhookService {
+func NewWebhookService(db *database.DB, containerService *ContainerService, updaterService *UpdaterService, projectService *ProjectService, gitOpsSyncService *GitOpsSyncService, eventService *EventService, environmentService *EnvironmentService, settingsService *SettingsService) *WebhookService {
 	return &WebhookService{
 		db:                 db,
 		containerService:   containerService,
@@ -58,6 +59,7 @@ func NewWebhookService(db *database.DB, containerService *ContainerService, upda
 		gitOpsSyncService:  gitOpsSyncService,
 		eventService:       eventService,
 		environmentService: environmentService,
+		settingsService:    settingsService,
 	}
 }
 
@@ -466,6 +468,14 @@ func (s *WebhookService) executeWebhookActionInternal(ctx context.Context, wh *m
 		return s.executeRemoteWebhookActionInternal(ctx, wh, actionType)
 	}
 
+	// Local actions bypass the REST routes and their maintenance guard, so
+	// check maintenance mode here. Remote environments enforce their own.
+	if s.settingsService != nil {
+		if err := s.settingsService.CheckMaintenance(ctx, false); err != nil {
+			return nil, err
+		}
+	}
+
 	switch wh.TargetType {
 	case models.WebhookTargetTypeContainer:
 		return s.executeContainerWebhookActionInternal(ctx, wh, actionType)
@@ -574,12 +584,12 @@ func (s *WebhookService) executeContainerWebhookActionInternal(ctx context.Conte
 		}
 		return nil, nil
 	case models.WebhookActionTypeStop:
-		if err := s.containerService.StopContainer(ctx, containerID, systemUser); err != nil {
+		if err := s.containerService.StopContainer(ctx, containerID, ContainerStopOptions{}, systemUser); err != nil {
 			return nil, s.wrapWebhookActionErrorInternal(ctx, wh, "container", actionType, err)
 		}
 		return nil, nil
 	case models.WebhookActionTypeRestart:
-		if err := s.containerService.RestartContainer(ctx, containerID, systemUser); err != nil {
+		if err := s.containerService.RestartContainer(ctx, containerID, ContainerStopOptions{}, systemUser); err != nil {
 			return nil, s.wrapWebhookActionErrorInternal(ctx, wh, "container", actionType, err)
 		}
 		return nil, nil
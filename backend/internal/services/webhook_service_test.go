@@ -10,12 +10,14 @@ import (
 	"testing"
 
 	"github.com/getarcaneapp/arcane/types/v2"
+	"github.com/getarcaneapp/arcane/types/v2/environment"
 	sqlite "github.com/libtnb/sqlite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	libcrypto "go.getarcane.app/sys/crypto"
 	"gorm.io/gorm"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/database"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 )
@@ -626,6 +628,26 @@ func TestTriggerByToken_GitOpsType_NilServiceReturnsError(t *testing.T) {
 	})
 }
 
+func TestTriggerByToken_LocalActionBlockedInMaintenance(t *testing.T) {
+	ctx := context.Background()
+	db := setupWebhookServiceTestDB(t)
+	settingsSvc := minimalSettingsServiceForTest(t)
+	svc := &WebhookService{db: db, settingsService: settingsSvc} // project service is nil
+
+	_, err := settingsSvc.SetMaintenance(ctx, environment.MaintenanceUpdate{Enabled: true, Reason: "upgrading"})
+	require.NoError(t, err)
+
+	rawToken := "arc_wh_5566778801020304aabbccdd0102030405060708090a0b0c0d0e0f1011121314"
+	wh := insertWebhookDirect(t, ctx, db, rawToken, models.WebhookTargetTypeProject, models.WebhookActionTypeRedeploy, "project-id", types.LOCAL_DOCKER_ENVIRONMENT_ID)
+
+	_, err = svc.TriggerByToken(ctx, rawToken)
+	require.ErrorIs(t, err, common.ErrEnvironmentMaintenance)
+	assert.Contains(t, err.Error(), "upgrading")
+
+	stored := fetchWebhook(t, db, wh.ID)
+	assert.Nil(t, stored.LastTriggeredAt)
+}
+
 func TestTriggerByToken_DoesNotUpdateLastTriggeredAtOnError(t *testing.T) {
 	ctx := context.Background()
 	db := setupWebhookServiceTestDB(t)
@@ -0,0 +1,152 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+// LogFilterOptions configures server-side filtering of container log lines.
+// The zero value forwards every line unchanged.
+type LogFilterOptions struct {
+	// Grep keeps only lines containing this substring. When GrepRegex is set
+	// it is compiled as a regular expression instead.
+	Grep      string
+	GrepRegex bool
+	// MaxLinesPerSecond caps how many matching lines are forwarded per second.
+	// Excess lines are dropped and replaced by a single suppression marker.
+	MaxLinesPerSecond int
+	// BytesLimit stops non-follow reads after this many bytes of log output.
+	// The output ends at the last complete line within the limit.
+	BytesLimit int64
+}
+
+// LogFilter applies LogFilterOptions to log lines. It is safe for concurrent
+// use so the stdout and stderr readers of one stream share a single budget.
+type LogFilter struct {
+	match             func(string) bool
+	maxLinesPerSecond int
+	bytesLimit        int64
+	now               func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+	suppressed  int
+}
+
+// NewLogFilter validates opts and returns a filter, or nil when opts does not
+// filter anything.
+func NewLogFilter(opts LogFilterOptions) (*LogFilter, error) {
+	if opts.MaxLinesPerSecond < 0 {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "maxLinesPerSecond must not be negative")
+	}
+	if opts.BytesLimit < 0 {
+		return nil, errors.WrapIf(cerrdefs.ErrInvalidArgument, "bytesLimit must not be negative")
+	}
+	if opts.Grep == "" && opts.MaxLinesPerSecond == 0 && opts.BytesLimit == 0 {
+		return nil, nil
+	}
+
+	filter := &LogFilter{
+		maxLinesPerSecond: opts.MaxLinesPerSecond,
+		bytesLimit:        opts.BytesLimit,
+		now:               time.Now,
+	}
+
+	switch {
+	case opts.Grep == "":
+	case opts.GrepRegex:
+		re, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "invalid grep pattern: %v", err)
+		}
+		filter.match = re.MatchString
+	default:
+		grep := opts.Grep
+		filter.match = func(line string) bool { return strings.Contains(line, grep) }
+	}
+
+	return filter, nil
+}
+
+// BytesLimit returns the configured byte cap for non-follow reads, or 0 when unlimited.
+func (f *LogFilter) BytesLimit() int64 {
+	if f == nil {
+		return 0
+	}
+	return f.bytesLimit
+}
+
+// throttlesInternal reports whether f can drop lines and so hold a pending
+// suppression marker.
+func (f *LogFilter) throttlesInternal() bool {
+	return f != nil && f.maxLinesPerSecond > 0
+}
+
+// Admit reports which lines to forward for an incoming line: nothing when the
+// line does not match or is throttled, otherwise the line itself, preceded by
+// a suppression marker when lines were dropped in an earlier window.
+func (f *LogFilter) Admit(line string) []string {
+	return f.admitInternal(line, "")
+}
+
+// admitInternal matches the raw line and, when it is forwarded, adds prefix
+// to it. Suppression markers are never prefixed.
+func (f *LogFilter) admitInternal(line, prefix string) []string {
+	out := prefix + line
+	if f == nil {
+		return []string{out}
+	}
+	if f.match != nil && !f.match(line) {
+		return nil
+	}
+	if f.maxLinesPerSecond == 0 {
+		return []string{out}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	if f.windowStart.IsZero() || now.Sub(f.windowStart) >= time.Second {
+		f.windowStart = now
+		f.windowCount = 0
+	}
+	if f.windowCount >= f.maxLinesPerSecond {
+		f.suppressed++
+		return nil
+	}
+	f.windowCount++
+
+	if marker, ok := f.takeSuppressedMarkerInternal(); ok {
+		return []string{marker, out}
+	}
+	return []string{out}
+}
+
+// Flush returns the pending suppression marker, if any lines were dropped
+// since the last forwarded line.
+func (f *LogFilter) Flush() (string, bool) {
+	if f == nil {
+		return "", false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.takeSuppressedMarkerInternal()
+}
+
+func (f *LogFilter) takeSuppressedMarkerInternal() (string, bool) {
+	if f.suppressed == 0 {
+		return "", false
+	}
+	marker := fmt.Sprintf("[%d lines suppressed]", f.suppressed)
+	f.suppressed = 0
+	return marker, true
+}
@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogFilterRejectsInvalidOptions(t *testing.T) {
+	_, err := NewLogFilter(LogFilterOptions{Grep: "(", GrepRegex: true})
+	require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+
+	_, err = NewLogFilter(LogFilterOptions{MaxLinesPerSecond: -1})
+	require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+
+	filter, err := NewLogFilter(LogFilterOptions{})
+	require.NoError(t, err)
+	require.Nil(t, filter)
+}
+
+func TestLogFilterThrottlesAndReportsSuppressedLines(t *testing.T) {
+	filter, err := NewLogFilter(LogFilterOptions{Grep: "GET", MaxLinesPerSecond: 2})
+	require.NoError(t, err)
+
+	now := time.Unix(0, 0)
+	filter.now = func() time.Time { return now }
+
+	var got []string
+	for _, line := range []string{"GET /a", "POST /b", "GET /c", "GET /d", "GET /e"} {
+		got = append(got, filter.Admit(line)...)
+	}
+	require.Equal(t, []string{"GET /a", "GET /c"}, got)
+
+	now = now.Add(time.Second)
+	require.Equal(t, []string{"[2 lines suppressed]", "GET /f"}, filter.Admit("GET /f"))
+
+	_, ok := filter.Flush()
+	require.False(t, ok)
+}
+
+func TestStreamContainerLogsFilteredGrepRegexFlushesPendingMarker(t *testing.T) {
+	filter, err := NewLogFilter(LogFilterOptions{Grep: `^ERROR`, GrepRegex: true, MaxLinesPerSecond: 1})
+	require.NoError(t, err)
+	filter.now = func() time.Time { return time.Unix(0, 0) }
+
+	logsChan := make(chan string, 8)
+	input := "ERROR one\nINFO two\nERROR three\nERROR four\n"
+
+	err = StreamContainerLogsFiltered(t.Context(), io.NopCloser(strings.NewReader(input)), logsChan, true, true, filter)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"ERROR one", "[2 lines suppressed]"}, drainLogLinesInternal(logsChan))
+}
+
+func TestStreamContainerLogsFilteredGrepMatchesStderrBeforePrefix(t *testing.T) {
+	var stream bytes.Buffer
+	writeDockerLogFrameInternal(t, &stream, 1, "ERROR out\n")
+	writeDockerLogFrameInternal(t, &stream, 2, "ERROR err\n")
+	writeDockerLogFrameInternal(t, &stream, 2, "INFO err\n")
+
+	filter, err := NewLogFilter(LogFilterOptions{Grep: `^ERROR`, GrepRegex: true})
+	require.NoError(t, err)
+
+	logsChan := make(chan string, 4)
+	err = StreamContainerLogsFiltered(t.Context(), io.NopCloser(bytes.NewReader(stream.Bytes())), logsChan, false, false, filter)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"ERROR out", "[STDERR] ERROR err"}, drainLogLinesInternal(logsChan))
+}
+
+func TestStreamContainerLogsFilteredSnapshotStopsAtBytesLimit(t *testing.T) {
+	var stream bytes.Buffer
+	writeDockerLogFrameInternal(t, &stream, 1, "first\n")
+	writeDockerLogFrameInternal(t, &stream, 2, "second\n")
+	writeDockerLogFrameInternal(t, &stream, 1, "third\n")
+
+	filter, err := NewLogFilter(LogFilterOptions{BytesLimit: int64(len("first\nsecond\n"))})
+	require.NoError(t, err)
+
+	logsChan := make(chan string, 4)
+	err = StreamContainerLogsFiltered(t.Context(), io.NopCloser(bytes.NewReader(stream.Bytes())), logsChan, false, false, filter)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"first", "[STDERR] second"}, drainLogLinesInternal(logsChan))
+}
+
+func TestStreamContainerLogsFilteredBytesLimitKeepsWholeLines(t *testing.T) {
+	limit := int64(len("first\nsec"))
+
+	var stream bytes.Buffer
+	writeDockerLogFrameInternal(t, &stream, 1, "first\n")
+	writeDockerLogFrameInternal(t, &stream, 2, "second\n")
+
+	filter, err := NewLogFilter(LogFilterOptions{BytesLimit: limit})
+	require.NoError(t, err)
+
+	logsChan := make(chan string, 4)
+	err = StreamContainerLogsFiltered(t.Context(), io.NopCloser(bytes.NewReader(stream.Bytes())), logsChan, false, false, filter)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first"}, drainLogLinesInternal(logsChan))
+
+	err = StreamContainerLogsFiltered(t.Context(), io.NopCloser(strings.NewReader("first\nsecond\n")), logsChan, false, true, filter)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first"}, drainLogLinesInternal(logsChan))
+}
+
+func TestStreamContainerLogsFilteredFlushesMarkerWhenStreamGoesIdle(t *testing.T) {
+	interval := logSuppressionFlushInterval
+	logSuppressionFlushInterval = 10 * time.Millisecond
+	t.Cleanup(func() { logSuppressionFlushInterval = interval })
+
+	filter, err := NewLogFilter(LogFilterOptions{MaxLinesPerSecond: 1})
+	require.NoError(t, err)
+	filter.now = func() time.Time { return time.Unix(0, 0) }
+
+	reader, writer := io.Pipe()
+	logsChan := make(chan string, 8)
+	done := make(chan error, 1)
+	go func() {
+		done <- StreamContainerLogsFiltered(t.Context(), reader, logsChan, true, true, filter)
+	}()
+
+	_, err = io.WriteString(writer, "one\ntwo\nthree\n")
+	require.NoError(t, err)
+
+	require.Equal(t, "one", <-logsChan)
+	select {
+	case marker := <-logsChan:
+		require.Equal(t, "[2 lines suppressed]", marker)
+	case <-time.After(5 * time.Second):
+		t.Fatal("suppression marker was not flushed while the stream was idle")
+	}
+
+	require.NoError(t, writer.Close())
+	require.NoError(t, <-done)
+}
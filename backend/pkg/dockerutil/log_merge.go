@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// timestampedLogLineInternal is a buffered log line with the timestamp it is sorted by.
+type timestampedLogLineInternal struct {
+	ts   time.Time
+	line string
+}
+
+// LogLineTimestamp returns the RFC3339Nano timestamp Docker prepends to a log
+// line when timestamps are requested, skipping the "[STDERR] " marker added by
+// the demultiplexer.
+func LogLineTimestamp(line string) (time.Time, bool) {
+	line = strings.TrimPrefix(line, "[STDERR] ")
+	token, _, _ := strings.Cut(line, " ")
+	ts, err := time.Parse(time.RFC3339Nano, token)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
+
+// MergeLogsByTimestamp reads timestamped log lines from in and forwards them to
+// out sorted by timestamp. Lines are buffered for window before each batch is
+// sorted and emitted; a window of zero or less buffers until in is closed, which
+// fully orders a finite tail. Lines without a timestamp keep the position of the
+// line before them. It returns once in is closed and drained, or when ctx is done.
+func MergeLogsByTimestamp(ctx context.Context, in <-chan string, out chan<- string, window time.Duration) error {
+	var pending []timestampedLogLineInternal
+	var last time.Time
+
+	flush := func() error {
+		slices.SortStableFunc(pending, func(a, b timestampedLogLineInternal) int {
+			return a.ts.Compare(b.ts)
+		})
+		for _, entry := range pending {
+			select {
+			case out <- entry.line:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		pending = pending[:0]
+		return nil
+	}
+
+	var tick <-chan time.Time
+	if window > 0 {
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-in:
+			if !ok {
+				return flush()
+			}
+			if ts, ok := LogLineTimestamp(line); ok {
+				last = ts
+			}
+			pending = append(pending, timestampedLogLineInternal{ts: last, line: line})
+		case <-tick:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
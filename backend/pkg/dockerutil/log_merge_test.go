@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeLogsByTimestampOrdersTailAcrossReplicas(t *testing.T) {
+	in := make(chan string, 8)
+	in <- "2024-05-01T10:00:00.000000001Z task=a first"
+	in <- "2024-05-01T10:00:03.000000000Z task=a third"
+	in <- "[STDERR] 2024-05-01T10:00:02.000000000Z task=b second"
+	in <- "    continuation of second"
+	in <- "2024-05-01T10:00:04.000000000Z task=b fourth"
+	close(in)
+
+	out := make(chan string, 8)
+	require.NoError(t, MergeLogsByTimestamp(t.Context(), in, out, 0))
+
+	require.Equal(t, []string{
+		"2024-05-01T10:00:00.000000001Z task=a first",
+		"[STDERR] 2024-05-01T10:00:02.000000000Z task=b second",
+		"    continuation of second",
+		"2024-05-01T10:00:03.000000000Z task=a third",
+		"2024-05-01T10:00:04.000000000Z task=b fourth",
+	}, drainLogLinesInternal(out))
+}
+
+func TestMergeLogsByTimestampFlushesEachWindow(t *testing.T) {
+	in := make(chan string)
+	out := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() { done <- MergeLogsByTimestamp(t.Context(), in, out, 50*time.Millisecond) }()
+
+	in <- "2024-05-01T10:00:02Z later"
+	in <- "2024-05-01T10:00:01Z earlier"
+
+	require.Equal(t, "2024-05-01T10:00:01Z earlier", <-out)
+	require.Equal(t, "2024-05-01T10:00:02Z later", <-out)
+
+	close(in)
+	require.NoError(t, <-done)
+}
+
+func TestLogLineTimestamp(t *testing.T) {
+	ts, ok := LogLineTimestamp("[STDERR] 2024-05-01T10:00:00.5Z boom")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 500_000_000, time.UTC), ts)
+
+	_, ok = LogLineTimestamp("no timestamp here")
+	require.False(t, ok)
+}
@@ -2,11 +2,13 @@ package docker
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"io"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"emperror.dev/errors"
 
@@ -16,18 +18,73 @@ import (
 // StreamContainerLogs streams Docker container logs, handling TTY raw streams
 // and non-TTY multiplexed stdout/stderr streams.
 func StreamContainerLogs(ctx context.Context, logs io.ReadCloser, logsChan chan<- string, follow bool, isTTY bool) error {
+	return StreamContainerLogsFiltered(ctx, logs, logsChan, follow, isTTY, nil)
+}
+
+// StreamContainerLogsFiltered behaves like StreamContainerLogs but passes every
+// line through filter. A nil filter forwards all lines.
+func StreamContainerLogsFiltered(ctx context.Context, logs io.ReadCloser, logsChan chan<- string, follow bool, isTTY bool, filter *LogFilter) error {
+	if follow && filter.throttlesInternal() {
+		flushCtx, cancel := context.WithCancel(ctx)
+		flushDone := make(chan struct{})
+		go func() {
+			defer close(flushDone)
+			flushSuppressedLogsInternal(flushCtx, filter, logsChan)
+		}()
+		// Wait for the flusher so nothing is sent after the caller closes logsChan.
+		defer func() {
+			cancel()
+			<-flushDone
+		}()
+	}
+
 	if isTTY {
-		return readLogLinesInternal(ctx, logs, logsChan, "")
+		if limit := filter.BytesLimit(); !follow && limit > 0 {
+			return readLimitedLogLinesInternal(ctx, logs, logsChan, limit, filter)
+		}
+		return readLogLinesInternal(ctx, logs, logsChan, "", filter)
 	}
 	if follow {
-		return StreamMultiplexedLogs(ctx, logs, logsChan)
+		return streamMultiplexedLogsInternal(ctx, logs, logsChan, filter)
+	}
+	return readAllLogsInternal(ctx, logs, logsChan, filter)
+}
+
+// logSuppressionFlushInterval is how often a followed stream reports lines the
+// filter dropped, so a burst that ends in silence is not held back until the
+// next forwarded line.
+var logSuppressionFlushInterval = time.Second
+
+func flushSuppressedLogsInternal(ctx context.Context, filter *LogFilter, logsChan chan<- string) {
+	ticker := time.NewTicker(logSuppressionFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		marker, ok := filter.Flush()
+		if !ok {
+			continue
+		}
+		select {
+		case logsChan <- marker:
+		case <-ctx.Done():
+			return
+		}
 	}
-	return ReadAllLogs(ctx, logs, logsChan)
 }
 
 // StreamMultiplexedLogs demultiplexes a Docker stdout/stderr log stream and
 // sends non-empty lines to logsChan.
 func StreamMultiplexedLogs(ctx context.Context, logs io.Reader, logsChan chan<- string) error {
+	return streamMultiplexedLogsInternal(ctx, logs, logsChan, nil)
+}
+
+func streamMultiplexedLogsInternal(ctx context.Context, logs io.Reader, logsChan chan<- string, filter *LogFilter) error {
 	stdoutReader, stdoutWriter := io.Pipe()
 	stderrReader, stderrWriter := io.Pipe()
 
@@ -68,11 +125,11 @@ func StreamMultiplexedLogs(ctx context.Context, logs io.Reader, logsChan chan<-
 	done := make(chan error, 2)
 
 	go func() {
-		done <- readLogLinesInternal(ctx, stdoutReader, logsChan, "")
+		done <- readLogLinesInternal(ctx, stdoutReader, logsChan, "", filter)
 	}()
 
 	go func() {
-		done <- readLogLinesInternal(ctx, stderrReader, logsChan, "[STDERR] ")
+		done <- readLogLinesInternal(ctx, stderrReader, logsChan, "[STDERR] ", filter)
 	}()
 
 	select {
@@ -97,8 +154,12 @@ func StreamMultiplexedLogs(ctx context.Context, logs io.Reader, logsChan chan<-
 // ReadAllLogs reads a non-follow Docker multiplexed log stream and sends
 // non-empty stdout/stderr lines to logsChan.
 func ReadAllLogs(ctx context.Context, logs io.ReadCloser, logsChan chan<- string) error {
-	stdoutBuf := &strings.Builder{}
-	stderrBuf := &strings.Builder{}
+	return readAllLogsInternal(ctx, logs, logsChan, nil)
+}
+
+func readAllLogsInternal(ctx context.Context, logs io.ReadCloser, logsChan chan<- string, filter *LogFilter) error {
+	stdoutBuf := &bytes.Buffer{}
+	stderrBuf := &bytes.Buffer{}
 	stdCopyDone := make(chan struct{})
 	defer close(stdCopyDone)
 
@@ -110,8 +171,16 @@ func ReadAllLogs(ctx context.Context, logs io.ReadCloser, logsChan chan<- string
 		}
 	}()
 
-	_, err := stdcopy.StdCopy(stdoutBuf, stderrBuf, logs)
-	if err != nil && !errors.Is(err, io.EOF) {
+	var stdout, stderr io.Writer = stdoutBuf, stderrBuf
+	if limit := filter.BytesLimit(); limit > 0 {
+		// Both writers draw from one budget so the cap covers the combined output.
+		remaining := limit
+		stdout = &budgetedLogWriterInternal{w: stdoutBuf, remaining: &remaining}
+		stderr = &budgetedLogWriterInternal{w: stderrBuf, remaining: &remaining}
+	}
+
+	_, err := stdcopy.StdCopy(stdout, stderr, logs)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, errLogBytesLimitReached) {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return ctxErr
 		}
@@ -120,15 +189,19 @@ func ReadAllLogs(ctx context.Context, logs io.ReadCloser, logsChan chan<- string
 	if ctxErr := ctx.Err(); ctxErr != nil {
 		return ctxErr
 	}
+	if errors.Is(err, errLogBytesLimitReached) {
+		trimPartialLogLineInternal(stdoutBuf)
+		trimPartialLogLineInternal(stderrBuf)
+	}
 
 	if stdoutBuf.Len() > 0 {
-		if err := readLogLinesInternal(ctx, strings.NewReader(stdoutBuf.String()), logsChan, ""); err != nil {
+		if err := readLogLinesInternal(ctx, stdoutBuf, logsChan, "", filter); err != nil {
 			return err
 		}
 	}
 
 	if stderrBuf.Len() > 0 {
-		if err := readLogLinesInternal(ctx, strings.NewReader(stderrBuf.String()), logsChan, "[STDERR] "); err != nil {
+		if err := readLogLinesInternal(ctx, stderrBuf, logsChan, "[STDERR] ", filter); err != nil {
 			return err
 		}
 	}
@@ -136,9 +209,83 @@ func ReadAllLogs(ctx context.Context, logs io.ReadCloser, logsChan chan<- string
 	return nil
 }
 
-func readLogLinesInternal(ctx context.Context, reader io.Reader, logsChan chan<- string, prefix string) error {
+// readLimitedLogLinesInternal reads a non-follow TTY log stream up to limit
+// bytes and sends its complete lines to logsChan.
+func readLimitedLogLinesInternal(ctx context.Context, logs io.ReadCloser, logsChan chan<- string, limit int64, filter *LogFilter) error {
+	copyDone := make(chan struct{})
+	defer close(copyDone)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = logs.Close()
+		case <-copyDone:
+		}
+	}()
+
+	buf := &bytes.Buffer{}
+	remaining := limit
+	_, err := io.Copy(&budgetedLogWriterInternal{w: buf, remaining: &remaining}, logs)
+	if err != nil && !errors.Is(err, errLogBytesLimitReached) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return errors.WrapIf(err, "failed to read logs")
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if errors.Is(err, errLogBytesLimitReached) {
+		trimPartialLogLineInternal(buf)
+	}
+
+	return readLogLinesInternal(ctx, buf, logsChan, "", filter)
+}
+
+// errLogBytesLimitReached stops stdcopy once a non-follow read has consumed its byte budget.
+var errLogBytesLimitReached = errors.New("log bytes limit reached")
+
+type budgetedLogWriterInternal struct {
+	w         io.Writer
+	remaining *int64
+}
+
+func (b *budgetedLogWriterInternal) Write(p []byte) (int, error) {
+	if *b.remaining <= 0 {
+		return 0, errLogBytesLimitReached
+	}
+	if int64(len(p)) <= *b.remaining {
+		n, err := b.w.Write(p)
+		*b.remaining -= int64(n)
+		return n, err
+	}
+
+	n, err := b.w.Write(p[:*b.remaining])
+	*b.remaining -= int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, errLogBytesLimitReached
+}
+
+// trimPartialLogLineInternal drops the unfinished line a byte cap left at the
+// end of buf, so the cap never cuts a line in half.
+func trimPartialLogLineInternal(buf *bytes.Buffer) {
+	buf.Truncate(bytes.LastIndexByte(buf.Bytes(), '\n') + 1)
+}
+
+func readLogLinesInternal(ctx context.Context, reader io.Reader, logsChan chan<- string, prefix string, filter *LogFilter) error {
 	bufferedReader := bufio.NewReader(reader)
 
+	send := func(line string) error {
+		select {
+		case logsChan <- line:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -148,20 +295,20 @@ func readLogLinesInternal(ctx context.Context, reader io.Reader, logsChan chan<-
 		if len(line) > 0 {
 			trimmed := strings.TrimRight(line, "\r\n")
 			if trimmed != "" {
-				if prefix != "" {
-					trimmed = prefix + trimmed
-				}
-
-				select {
-				case logsChan <- trimmed:
-				case <-ctx.Done():
-					return ctx.Err()
+				// Match against the raw line so anchored patterns also apply to stderr.
+				for _, out := range filter.admitInternal(trimmed, prefix) {
+					if sendErr := send(out); sendErr != nil {
+						return sendErr
+					}
 				}
 			}
 		}
 
 		if err != nil {
 			if errors.Is(err, io.EOF) {
+				if marker, ok := filter.Flush(); ok {
+					return send(marker)
+				}
 				return nil
 			}
 			return err
@@ -17,15 +17,34 @@ var commandRoutes = []commandRoute{
 
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers", CommandName: "container.list"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/counts", CommandName: "container.counts"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/export", CommandName: "container.export"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers", CommandName: "container.create"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/create/stream", CommandName: "container.create.stream"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}", CommandName: "container.inspect"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}/health", CommandName: "container.health"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}/top", CommandName: "container.top"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}/changes", CommandName: "container.changes"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/exec/run", CommandName: "container.exec.run"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/start", CommandName: "container.start"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/stop", CommandName: "container.stop"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/restart", CommandName: "container.restart"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/redeploy", CommandName: "container.redeploy"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/recreate", CommandName: "container.recreate"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}/env", CommandName: "container.env.get"},
+	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/containers/{containerId}/env", CommandName: "container.env.set"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/containers/{containerId}", CommandName: "container.delete"},
+	{Method: http.MethodPatch, PathPattern: "/api/environments/{id}/containers/{containerId}", CommandName: "container.resources.update"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/update", CommandName: "container.update"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/containers/{containerId}/auto-update", CommandName: "container.auto_update.set"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}/files", CommandName: "container.files.list"},
+	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/containers/{containerId}/files", CommandName: "container.files.write"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/files/upload", CommandName: "container.files.upload"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/containers/{containerId}/logs/export", CommandName: "container.logs.export"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/{containerId}/logs/search", CommandName: "container.logs.search"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/batch/start", CommandName: "container.batch.start"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/batch/stop", CommandName: "container.batch.stop"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/batch/restart", CommandName: "container.batch.restart"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/containers/batch/remove", CommandName: "container.batch.remove"},
 
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/ports", CommandName: "port.list"},
 
@@ -77,6 +96,8 @@ var commandRoutes = []commandRoute{
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/projects/{projectId}/runtime", CommandName: "project.runtime"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/projects/{projectId}/updates", CommandName: "project.updates"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/projects/{projectId}/file", CommandName: "project.file"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/projects/{projectId}/orphans", CommandName: "project.orphans"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/projects/{projectId}/orphans/prune", CommandName: "project.orphans.prune"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/projects/{projectId}/redeploy", CommandName: "project.redeploy"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/projects/{projectId}/destroy", CommandName: "project.destroy"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/projects/{projectId}", CommandName: "project.update"},
@@ -121,6 +142,8 @@ var commandRoutes = []commandRoute{
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/volumes/backups/{backupId}/has-path", CommandName: "volume.backup.has_path"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/volumes/backups/{backupId}/files", CommandName: "volume.backup.files"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/volumes/{volumeName}/backups/upload", CommandName: "volume.backup.upload"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/volumes/{volumeName}/backup", CommandName: "volume.backup.stream"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/volumes/{volumeName}/restore", CommandName: "volume.restore"},
 
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/builds/browse", CommandName: "build_workspace.browse.list"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/builds/browse/content", CommandName: "build_workspace.browse.read_file"},
@@ -133,6 +156,7 @@ var commandRoutes = []commandRoute{
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/networks/counts", CommandName: "network.counts"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/networks", CommandName: "network.create"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/networks/{networkId}", CommandName: "network.inspect"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/networks/{networkId}/usage", CommandName: "network.usage"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/networks/{networkId}", CommandName: "network.delete"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/networks/prune", CommandName: "network.prune"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/networks/topology", CommandName: "network.topology"},
@@ -143,6 +167,9 @@ var commandRoutes = []commandRoute{
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/settings/public", CommandName: "settings.public.get"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/settings", CommandName: "settings.get"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/settings", CommandName: "settings.update"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/maintenance", CommandName: "maintenance.get"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/maintenance", CommandName: "maintenance.set"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/agent/info", CommandName: "agent.info"},
 
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/job-schedules", CommandName: "job_schedule.list"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/job-schedules", CommandName: "job_schedule.upsert"},
@@ -163,8 +190,17 @@ var commandRoutes = []commandRoute{
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/updater/history", CommandName: "updater.history"},
 
 	{Method: http.MethodHead, PathPattern: "/api/environments/{id}/system/health", CommandName: "system.health"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/system/ready", CommandName: "system.ready"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/system/docker/info", CommandName: "system.docker_info"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/system/df", CommandName: "system.disk_usage"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/system/prune", CommandName: "system.prune"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/system/prune/schedules", CommandName: "system.prune_schedule.list"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/system/prune/schedules", CommandName: "system.prune_schedule.create"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/system/prune/schedules/{scheduleId}", CommandName: "system.prune_schedule.get"},
+	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/system/prune/schedules/{scheduleId}", CommandName: "system.prune_schedule.update"},
+	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/system/prune/schedules/{scheduleId}", CommandName: "system.prune_schedule.delete"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/system/prune/schedules/{scheduleId}/run", CommandName: "system.prune_schedule.run"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/system/prune/schedules/{scheduleId}/runs", CommandName: "system.prune_schedule.runs"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/system/containers/start-all", CommandName: "system.containers.start_all"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/system/containers/start-stopped", CommandName: "system.containers.start_stopped"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/system/containers/stop-all", CommandName: "system.containers.stop_all"},
@@ -174,30 +210,50 @@ var commandRoutes = []commandRoute{
 
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/services", CommandName: "swarm.service.list"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services", CommandName: "swarm.service.create"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services/from-compose", CommandName: "swarm.service.create_from_compose"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/services/export", CommandName: "swarm.service.export"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}", CommandName: "swarm.service.inspect"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/status", CommandName: "swarm.service.status"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}", CommandName: "swarm.service.update"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}", CommandName: "swarm.service.delete"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/tasks", CommandName: "swarm.service.tasks"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/rollback", CommandName: "swarm.service.rollback"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/restart", CommandName: "swarm.service.restart"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/pin", CommandName: "swarm.service.pin_image"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/scale", CommandName: "swarm.service.scale"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/placement", CommandName: "swarm.service.placement"},
+	{Method: http.MethodPatch, PathPattern: "/api/environments/{id}/swarm/services/{serviceId}/resources", CommandName: "swarm.service.resources.update"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/nodes", CommandName: "swarm.node.list"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/nodes/export", CommandName: "swarm.node.export"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}", CommandName: "swarm.node.inspect"},
 	{Method: http.MethodPatch, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}", CommandName: "swarm.node.update"},
+	{Method: http.MethodPatch, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}/labels", CommandName: "swarm.node.labels.patch"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}", CommandName: "swarm.node.delete"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}/agent/deployment", CommandName: "swarm.node.agent_deployment"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}/promote", CommandName: "swarm.node.promote"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}/demote", CommandName: "swarm.node.demote"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}/drain", CommandName: "swarm.node.drain"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/nodes/{nodeId}/tasks", CommandName: "swarm.node.tasks"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/tasks", CommandName: "swarm.task.list"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/tasks/export", CommandName: "swarm.task.export"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/resources", CommandName: "swarm.resources"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/tasks/{taskId}/logs", CommandName: "swarm.task.logs"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks", CommandName: "swarm.stack.list"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/stacks", CommandName: "swarm.stack.deploy"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/export", CommandName: "swarm.stack.export"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/stacks/import", CommandName: "swarm.stack.import"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}", CommandName: "swarm.stack.inspect"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/swarm/stacks/{name}", CommandName: "swarm.stack.delete"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/removal-plan", CommandName: "swarm.stack.removal_plan"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/source", CommandName: "swarm.stack.source.get"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/source", CommandName: "swarm.stack.source.update"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/history", CommandName: "swarm.stack.history"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/history/diff", CommandName: "swarm.stack.history.diff"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/scale", CommandName: "swarm.stack.scale"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/services", CommandName: "swarm.stack.services"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stacks/{name}/tasks", CommandName: "swarm.stack.tasks"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/stacks/config/render", CommandName: "swarm.stack.config.render"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/stack-sources/orphaned", CommandName: "swarm.stack_source.orphaned"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/status", CommandName: "swarm.status"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/info", CommandName: "swarm.info"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/init", CommandName: "swarm.init"},
@@ -211,19 +267,27 @@ var commandRoutes = []commandRoute{
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/configs", CommandName: "swarm.config.list"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/configs", CommandName: "swarm.config.create"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/configs/{configId}", CommandName: "swarm.config.inspect"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/configs/{configId}/usage", CommandName: "swarm.config.usage"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/swarm/configs/{configId}", CommandName: "swarm.config.update"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/swarm/configs/{configId}", CommandName: "swarm.config.delete"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/secrets", CommandName: "swarm.secret.list"},
 	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/secrets", CommandName: "swarm.secret.create"},
 	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/secrets/{secretId}", CommandName: "swarm.secret.inspect"},
+	{Method: http.MethodGet, PathPattern: "/api/environments/{id}/swarm/secrets/{secretId}/usage", CommandName: "swarm.secret.usage"},
 	{Method: http.MethodPut, PathPattern: "/api/environments/{id}/swarm/secrets/{secretId}", CommandName: "swarm.secret.update"},
+	{Method: http.MethodPost, PathPattern: "/api/environments/{id}/swarm/secrets/{secretId}/rotate", CommandName: "swarm.secret.rotate"},
 	{Method: http.MethodDelete, PathPattern: "/api/environments/{id}/swarm/secrets/{secretId}", CommandName: "swarm.secret.delete"},
 
 	{PathPattern: "/api/environments/{id}/ws/projects/{projectId}/logs", CommandName: "project.logs.stream", Stream: true},
+	{PathPattern: "/api/environments/{id}/ws/projects/{projectId}/stats", CommandName: "project.stats.stream", Stream: true},
+	{PathPattern: "/api/environments/{id}/ws/projects/{projectId}/up", CommandName: "project.deploy.stream", Stream: true},
 	{PathPattern: "/api/environments/{id}/ws/containers/{containerId}/logs", CommandName: "container.logs.stream", Stream: true},
 	{PathPattern: "/api/environments/{id}/ws/containers/{containerId}/stats", CommandName: "container.stats.stream", Stream: true},
 	{PathPattern: "/api/environments/{id}/ws/containers/{containerId}/terminal", CommandName: "container.exec.stream", Stream: true},
+	{PathPattern: "/api/environments/{id}/ws/images/pull", CommandName: "image.pull.stream", Stream: true},
 	{PathPattern: "/api/environments/{id}/ws/system/stats", CommandName: "system.stats.stream", Stream: true},
+	{PathPattern: "/api/environments/{id}/ws/system/events", CommandName: "system.events.stream", Stream: true},
+	{PathPattern: "/api/environments/{id}/ws/system/prune", CommandName: "system.prune.stream", Stream: true},
 }
 
 var commandRoutesIndex = buildCommandRouteIndexInternal(commandRoutes)
@@ -22,7 +22,16 @@ func TestResolveEdgeCommandName(t *testing.T) {
 		{name: "container list", method: "GET", path: "/api/environments/0/containers", command: "container.list", shouldHit: true},
 		{name: "container start", method: "POST", path: "/api/environments/0/containers/abc/start", command: "container.start", shouldHit: true},
 		{name: "volume browse download", method: "GET", path: "/api/environments/0/volumes/data/browse/download", command: "volume.browse.download", shouldHit: true},
+		{name: "volume backup stream", method: "GET", path: "/api/environments/0/volumes/data/backup", command: "volume.backup.stream", shouldHit: true},
+		{name: "volume restore", method: "POST", path: "/api/environments/0/volumes/data/restore", command: "volume.restore", shouldHit: true},
+		{name: "prune schedule list", method: "GET", path: "/api/environments/0/system/prune/schedules", command: "system.prune_schedule.list", shouldHit: true},
+		{name: "prune schedule update", method: "PUT", path: "/api/environments/0/system/prune/schedules/abc", command: "system.prune_schedule.update", shouldHit: true},
+		{name: "prune schedule run", method: "POST", path: "/api/environments/0/system/prune/schedules/abc/run", command: "system.prune_schedule.run", shouldHit: true},
+		{name: "prune schedule runs", method: "GET", path: "/api/environments/0/system/prune/schedules/abc/runs", command: "system.prune_schedule.runs", shouldHit: true},
+		{name: "container create stream", method: "POST", path: "/api/environments/0/containers/create/stream", command: "container.create.stream", shouldHit: true},
+		{name: "image pull stream", method: "GET", path: "/api/environments/0/ws/images/pull?image=nginx", stream: true, command: "image.pull.stream", shouldHit: true},
 		{name: "project logs stream", method: "GET", path: "/api/environments/0/ws/projects/p1/logs", stream: true, command: "project.logs.stream", shouldHit: true},
+		{name: "project deploy stream", method: "GET", path: "/api/environments/0/ws/projects/p1/up", stream: true, command: "project.deploy.stream", shouldHit: true},
 		{name: "project updates", method: "GET", path: "/api/environments/0/projects/p1/updates", command: "project.updates", shouldHit: true},
 		{name: "project archive", method: "POST", path: "/api/environments/0/projects/p1/archive", command: "project.archive", shouldHit: true},
 		{name: "activity list", method: "GET", path: "/api/environments/0/activities?limit=50", command: "activity.list", shouldHit: true},
@@ -30,12 +39,30 @@ func TestResolveEdgeCommandName(t *testing.T) {
 		{name: "activity cancel", method: "POST", path: "/api/environments/0/activities/activity-1/cancel", command: "activity.cancel", shouldHit: true},
 		{name: "activity history clear", method: "DELETE", path: "/api/environments/0/activities/history", command: "activity.history.clear", shouldHit: true},
 		{name: "health", method: "HEAD", path: "/api/environments/0/system/health", command: "system.health", shouldHit: true},
+		{name: "readiness", method: "GET", path: "/api/environments/0/system/ready?minFreeDiskPercent=5", command: "system.ready", shouldHit: true},
 		{name: "swarm node identity", method: "GET", path: "/api/swarm/node-identity", command: "swarm.node_identity", shouldHit: true},
 		{name: "ports list", method: "GET", path: "/api/environments/0/ports?limit=20", command: "port.list", shouldHit: true},
 		{name: "network topology", method: "GET", path: "/api/environments/0/networks/topology", command: "network.topology", shouldHit: true},
+		{name: "network usage", method: "GET", path: "/api/environments/0/networks/net1/usage", command: "network.usage", shouldHit: true},
+		{name: "container top", method: "GET", path: "/api/environments/0/containers/abc/top", command: "container.top", shouldHit: true},
+		{name: "container changes", method: "GET", path: "/api/environments/0/containers/abc/changes", command: "container.changes", shouldHit: true},
+		{name: "containers export", method: "GET", path: "/api/environments/0/containers/export?search=web", command: "container.export", shouldHit: true},
+		{name: "container exec run", method: "POST", path: "/api/environments/0/containers/abc/exec/run", command: "container.exec.run", shouldHit: true},
 		{name: "container auto-update", method: "PUT", path: "/api/environments/0/containers/abc/auto-update", command: "container.auto_update.set", shouldHit: true},
 		{name: "project update services", method: "POST", path: "/api/environments/0/projects/p1/update-services", command: "project.update_services", shouldHit: true},
 		{name: "swarm services list", method: "GET", path: "/api/environments/0/swarm/services", command: "swarm.service.list", shouldHit: true},
+		{name: "swarm services export", method: "GET", path: "/api/environments/0/swarm/services/export", command: "swarm.service.export", shouldHit: true},
+		{name: "swarm nodes export", method: "GET", path: "/api/environments/0/swarm/nodes/export", command: "swarm.node.export", shouldHit: true},
+		{name: "swarm tasks export", method: "GET", path: "/api/environments/0/swarm/tasks/export", command: "swarm.task.export", shouldHit: true},
+		{name: "swarm service pin image", method: "POST", path: "/api/environments/0/swarm/services/abc123/pin", command: "swarm.service.pin_image", shouldHit: true},
+		{name: "swarm config usage", method: "GET", path: "/api/environments/0/swarm/configs/c1/usage", command: "swarm.config.usage", shouldHit: true},
+		{name: "swarm secret usage", method: "GET", path: "/api/environments/0/swarm/secrets/s1/usage", command: "swarm.secret.usage", shouldHit: true},
+		{name: "swarm resources", method: "GET", path: "/api/environments/0/swarm/resources", command: "swarm.resources", shouldHit: true},
+		{name: "swarm secret rotate", method: "POST", path: "/api/environments/0/swarm/secrets/s1/rotate", command: "swarm.secret.rotate", shouldHit: true},
+		{name: "swarm stack removal plan", method: "GET", path: "/api/environments/0/swarm/stacks/web/removal-plan", command: "swarm.stack.removal_plan", shouldHit: true},
+		{name: "swarm orphaned stack sources", method: "GET", path: "/api/environments/0/swarm/stack-sources/orphaned", command: "swarm.stack_source.orphaned", shouldHit: true},
+		{name: "swarm stacks export", method: "GET", path: "/api/environments/0/swarm/stacks/export", command: "swarm.stack.export", shouldHit: true},
+		{name: "container resources update", method: "PATCH", path: "/api/environments/0/containers/abc", command: "container.resources.update", shouldHit: true},
 		{name: "unknown", method: "PATCH", path: "/api/environments/0/containers", shouldHit: false},
 	}
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"time"
 
@@ -194,6 +195,14 @@ func (s *grpcResponseState) handleCommandComplete(incoming *TunnelMessage) (int,
 
 // ProxyHTTPRequest is a helper that proxies an echo context through a tunnel
 func ProxyHTTPRequest(c *echo.Context, tunnel *AgentTunnel, targetPath string) error {
+	return ProxyHTTPRequestWithOutcome(c, tunnel, targetPath, nil)
+}
+
+// ProxyHTTPRequestWithOutcome is ProxyHTTPRequest that reports what the agent
+// answered to onOutcome before the response is written: the status code, or 0
+// and the tunnel error. onOutcome is not called when the request never reached
+// the tunnel, and may be nil.
+func ProxyHTTPRequestWithOutcome(c *echo.Context, tunnel *AgentTunnel, targetPath string, onOutcome func(statusCode int, err error)) error {
 	req := c.Request()
 	ctx := req.Context()
 
@@ -229,6 +238,9 @@ func ProxyHTTPRequest(c *echo.Context, tunnel *AgentTunnel, targetPath string) e
 	)
 
 	status, respHeaders, respBody, err := ProxyRequest(proxyCtx, tunnel, req.Method, targetPath, req.URL.RawQuery, headers, body)
+	if onOutcome != nil {
+		onOutcome(status, err)
+	}
 	if err != nil {
 		slog.ErrorContext(ctx, "Edge tunnel proxy failed",
 			"environment_id", tunnel.EnvironmentID,
@@ -352,6 +364,12 @@ func RequestTunnelAndWait(ctx context.Context, envID string, demandTTL, timeout
 // This is for service-level calls that need to route through the tunnel.
 // Returns (statusCode, responseBody, error)
 func DoRequest(ctx context.Context, envID, method, path string, body []byte) (int, []byte, error) {
+	return DoRequestWithHeaders(ctx, envID, method, path, nil, body)
+}
+
+// DoRequestWithHeaders is DoRequest with extra request headers, such as the
+// agent token for endpoints that only reveal details to authenticated callers.
+func DoRequestWithHeaders(ctx context.Context, envID, method, path string, extraHeaders map[string]string, body []byte) (int, []byte, error) {
 	if ctx == nil {
 		return 0, nil, errors.New("context is required")
 	}
@@ -369,7 +387,8 @@ func DoRequest(ctx context.Context, envID, method, path string, body []byte) (in
 		return 0, nil, errors.Errorf("tunnel for environment %s is closed", envID)
 	}
 
-	headers := make(map[string]string)
+	headers := make(map[string]string, len(extraHeaders)+1)
+	maps.Copy(headers, extraHeaders)
 	if method != http.MethodGet && len(body) > 0 {
 		headers["Content-Type"] = "application/json"
 	}
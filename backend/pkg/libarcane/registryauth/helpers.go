@@ -1,8 +1,11 @@
 package registryauth
 
 import (
+	"context"
+	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	ref "github.com/distribution/reference"
@@ -116,3 +119,102 @@ func LookupKeys(url string) []string {
 	sort.Strings(out)
 	return out
 }
+
+// ParseMirrors parses a registry mirror setting made of registry=mirror entries
+// separated by commas or new lines, keyed by the registry host normalized for
+// comparison. A mirror may include a path prefix, as pull-through cache
+// projects in Harbor or Nexus need.
+func ParseMirrors(value string) (map[string]string, error) {
+	mirrors := map[string]string{}
+	entries := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		registry, mirror, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, errors.Errorf("registry mirror %q must be in registry=mirror form", entry)
+		}
+
+		key := NormalizeRegistryForComparison(registry)
+		mirror = strings.TrimSpace(mirror)
+		mirror = strings.TrimPrefix(mirror, "https://")
+		mirror = strings.TrimPrefix(mirror, "http://")
+		mirror = strings.Trim(mirror, "/")
+		switch {
+		case key == "" || mirror == "":
+			return nil, errors.Errorf("registry mirror %q needs both a registry and a mirror host", entry)
+		case strings.ContainsAny(mirror, " \t"):
+			return nil, errors.Errorf("registry mirror %q contains whitespace", entry)
+		case IsRegistryMatch(key, mirror):
+			return nil, errors.Errorf("registry mirror %q points at itself", entry)
+		}
+		if _, exists := mirrors[key]; exists {
+			return nil, errors.Errorf("registry %q has more than one mirror", key)
+		}
+		mirrors[key] = mirror
+	}
+
+	return mirrors, nil
+}
+
+// mirrorProbeTimeout bounds each reachability request ProbeMirror makes.
+const mirrorProbeTimeout = 5 * time.Second
+
+// ProbeMirror checks that mirror, a host with an optional path prefix as
+// ParseMirrors returns it, answers on /v2/. HTTPS is tried first and plain HTTP
+// second, since a mirror listed in the daemon's insecure-registries may only
+// speak HTTP. Any HTTP response counts as reachable, as a mirror may require
+// authentication. When neither scheme answers, the HTTPS error is returned.
+func ProbeMirror(ctx context.Context, mirror string) error {
+	host, _, _ := strings.Cut(mirror, "/")
+	probeClient := &http.Client{Timeout: mirrorProbeTimeout}
+
+	var httpsErr error
+	for _, scheme := range []string{"https", "http"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/v2/", nil)
+		if err != nil {
+			return errors.WrapIf(err, "invalid mirror host")
+		}
+		resp, err := probeClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+			return nil
+		}
+		if httpsErr == nil {
+			httpsErr = err
+		}
+	}
+	return httpsErr
+}
+
+// MirrorReference rewrites imageRef to pull through the mirror configured for
+// its registry, adding the implied latest tag. Digest references are never
+// mirrored because the pulled image could not be tagged back to its original
+// name.
+func MirrorReference(imageRef string, mirrors map[string]string) (string, bool) {
+	if len(mirrors) == 0 {
+		return "", false
+	}
+	named, err := ref.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", false
+	}
+	if _, digested := named.(ref.Digested); digested {
+		return "", false
+	}
+
+	mirror, ok := mirrors[NormalizeRegistryForComparison(ref.Domain(named))]
+	if !ok {
+		return "", false
+	}
+	tagged, ok := ref.TagNameOnly(named).(ref.Tagged)
+	if !ok {
+		return "", false
+	}
+	return mirror + "/" + ref.Path(named) + ":" + tagged.Tag(), true
+}
@@ -1,6 +1,11 @@
 package registryauth
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	dockerauthconfig "github.com/moby/moby/api/pkg/authconfig"
@@ -85,3 +90,59 @@ func TestRegistryAuthLookupKeys(t *testing.T) {
 	assert.Equal(t, []string{"docker.io", "index.docker.io", "registry-1.docker.io"}, LookupKeys("https://index.docker.io/v1/"))
 	assert.Nil(t, LookupKeys("   "))
 }
+
+func TestParseMirrors(t *testing.T) {
+	mirrors, err := ParseMirrors("docker.io=https://mirror.example.com/dockerhub/\n ghcr.io = ghcr-cache.example.com:5000, ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"docker.io": "mirror.example.com/dockerhub",
+		"ghcr.io":   "ghcr-cache.example.com:5000",
+	}, mirrors)
+
+	for _, invalid := range []string{"docker.io", "=mirror.example.com", "docker.io=", "ghcr.io=ghcr.io", "docker.io=a.example.com\nindex.docker.io=b.example.com"} {
+		_, err := ParseMirrors(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestProbeMirror(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/", r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	plainHost := strings.TrimPrefix(server.URL, "http://")
+	require.NoError(t, ProbeMirror(context.Background(), plainHost+"/dockerhub"), "a plain HTTP mirror falls back from HTTPS")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedHost := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	assert.Error(t, ProbeMirror(context.Background(), closedHost))
+}
+
+func TestMirrorReference(t *testing.T) {
+	mirrors := map[string]string{
+		"docker.io": "mirror.example.com/dockerhub",
+		"ghcr.io":   "ghcr-cache.example.com:5000",
+	}
+
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "nginx", want: "mirror.example.com/dockerhub/library/nginx:latest", ok: true},
+		{in: "docker.io/grafana/grafana:11.0.0", want: "mirror.example.com/dockerhub/grafana/grafana:11.0.0", ok: true},
+		{in: "ghcr.io/getarcaneapp/arcane:latest", want: "ghcr-cache.example.com:5000/getarcaneapp/arcane:latest", ok: true},
+		{in: "quay.io/prometheus/prometheus:v2", ok: false},
+		{in: "nginx@sha256:" + strings.Repeat("a", 64), ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := MirrorReference(tt.in, mirrors)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
@@ -33,7 +33,6 @@ var timeoutSettingKeys = []string{
 }
 
 var cronSettingKeys = []string{
-	"scheduledPruneInterval",
 	"autoUpdateInterval",
 	"pollingInterval",
 	"environmentHealthInterval",
@@ -0,0 +1,157 @@
+package swarm
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"emperror.dev/errors"
+
+	composegotypes "github.com/compose-spec/compose-go/v2/types"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/projects"
+	"github.com/moby/moby/api/types/swarm"
+	dockerclient "github.com/moby/moby/client"
+)
+
+// composeServiceProjectName is the project name a single-service compose
+// fragment is loaded under. It never reaches Docker: the service is not part
+// of a stack and keeps its own name.
+const composeServiceProjectName = "arcane-service"
+
+// ServiceFromComposeOptions describes a standalone swarm service defined by a
+// compose fragment.
+type ServiceFromComposeOptions struct {
+	// Name overrides the service name; empty uses the compose service key.
+	Name           string
+	ComposeContent string
+	WorkingDir     string
+	PathMapper     *projects.PathMapper
+}
+
+// BuildServiceSpecFromCompose converts a compose fragment declaring exactly one
+// service into a swarm service spec.
+//
+// The fragment is loaded the same way DeployStack loads a stack, but nothing
+// besides the service is created: networks, configs and secrets it references
+// must be declared external (or, for networks, omitted to use the ingress
+// network only), and are looked up rather than reconciled. Named volumes are
+// created by Docker on first use, so a volume that sets a driver must be
+// external too.
+//
+// ctx controls cancellation for Compose loading and Docker API calls.
+// dockerClient resolves external configs and secrets; it is unused when the
+// service references none.
+//
+// Returns the service spec, named after opts.Name when set.
+// Returns an invalid argument error when the compose content cannot be loaded,
+// does not declare exactly one service, or references a resource that is not
+// external; returns the inspect error when an external config or secret cannot
+// be found.
+func BuildServiceSpecFromCompose(ctx context.Context, dockerClient *dockerclient.Client, opts ServiceFromComposeOptions) (swarm.ServiceSpec, error) {
+	project, err := loadComposeProject(ctx, composeServiceProjectName, opts.ComposeContent, "", "", opts.WorkingDir, opts.PathMapper)
+	if err != nil {
+		return swarm.ServiceSpec{}, common.Classify(cerrdefs.ErrInvalidArgument, err)
+	}
+
+	networkNameByKey, err := composeServiceNetworksInternal(project)
+	if err != nil {
+		return swarm.ServiceSpec{}, err
+	}
+
+	for key, cfg := range project.Volumes {
+		if !bool(cfg.External) && (cfg.Driver != "" || len(cfg.DriverOpts) > 0) {
+			return swarm.ServiceSpec{}, errors.WrapIff(cerrdefs.ErrInvalidArgument, "volume %s sets a driver and must be external", key)
+		}
+	}
+
+	configMetaByKey := make(map[string]resourceMeta, len(project.Configs))
+	for key, cfg := range project.Configs {
+		if !bool(cfg.External) {
+			return swarm.ServiceSpec{}, errors.WrapIff(cerrdefs.ErrInvalidArgument, "config %s must be external", key)
+		}
+		meta, err := inspectConfig(ctx, dockerClient, resolveResourceName("", key, cfg.Name, cfg.External))
+		if err != nil {
+			return swarm.ServiceSpec{}, errors.WrapIff(err, "failed to inspect config %s", key)
+		}
+		configMetaByKey[key] = meta
+	}
+
+	secretMetaByKey := make(map[string]resourceMeta, len(project.Secrets))
+	for key, cfg := range project.Secrets {
+		if !bool(cfg.External) {
+			return swarm.ServiceSpec{}, errors.WrapIff(cerrdefs.ErrInvalidArgument, "secret %s must be external", key)
+		}
+		meta, err := inspectSecret(ctx, dockerClient, resolveResourceName("", key, cfg.Name, cfg.External))
+		if err != nil {
+			return swarm.ServiceSpec{}, errors.WrapIff(err, "failed to inspect secret %s", key)
+		}
+		secretMetaByKey[key] = meta
+	}
+
+	return buildComposeServiceSpecInternal(project, opts.Name, networkNameByKey, configMetaByKey, secretMetaByKey)
+}
+
+// composeServiceNetworksInternal maps the fragment's network keys to external
+// network names. The implicit default network is dropped, so a service without
+// networks only joins the ingress network.
+func composeServiceNetworksInternal(project *composegotypes.Project) (map[string]string, error) {
+	result := make(map[string]string, len(project.Networks))
+	for key, cfg := range project.Networks {
+		if bool(cfg.External) {
+			result[key] = resolveResourceName("", key, cfg.Name, cfg.External)
+			continue
+		}
+		if key == "default" {
+			continue
+		}
+		return nil, errors.WrapIff(cerrdefs.ErrInvalidArgument, "network %s must be external", key)
+	}
+	return result, nil
+}
+
+func buildComposeServiceSpecInternal(
+	project *composegotypes.Project,
+	name string,
+	networkNameByKey map[string]string,
+	configMetaByKey map[string]resourceMeta,
+	secretMetaByKey map[string]resourceMeta,
+) (swarm.ServiceSpec, error) {
+	if len(project.Services) != 1 {
+		return swarm.ServiceSpec{}, errors.WrapIff(cerrdefs.ErrInvalidArgument, "compose content must declare exactly one service, found %d", len(project.Services))
+	}
+
+	var service composegotypes.ServiceConfig
+	for key, svc := range project.Services {
+		service = svc
+		if service.Name == "" {
+			service.Name = key
+		}
+	}
+	if strings.TrimSpace(service.Image) == "" {
+		return swarm.ServiceSpec{}, errors.WrapIff(cerrdefs.ErrInvalidArgument, "service %s has no image", service.Name)
+	}
+	// Compose attaches services without networks to the implicit default
+	// network, which is only created for stacks.
+	if _, ok := networkNameByKey["default"]; !ok && service.Networks != nil {
+		service.Networks = maps.Clone(service.Networks)
+		delete(service.Networks, "default")
+	}
+
+	spec := buildServiceSpec(service, "", nil, networkNameByKey, configMetaByKey, secretMetaByKey, nil)
+	spec.Name = service.Name
+	if name = strings.TrimSpace(name); name != "" {
+		spec.Name = name
+	}
+	// The image label exists so stack updates can tell a pinned image from a
+	// resolved digest; a standalone service does not need it.
+	delete(spec.Labels, stackImageLabel)
+	if len(spec.Labels) == 0 {
+		spec.Labels = nil
+	}
+	if len(spec.TaskTemplate.ContainerSpec.Labels) == 0 {
+		spec.TaskTemplate.ContainerSpec.Labels = nil
+	}
+	return spec, nil
+}
@@ -0,0 +1,76 @@
+package swarm
+
+import (
+	"context"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildServiceSpecFromCompose(t *testing.T) {
+	content := `services:
+  web:
+    image: nginx:alpine
+    command: ["nginx", "-g", "daemon off;"]
+    environment:
+      MODE: production
+    ports:
+      - "8080:80"
+    networks:
+      edge:
+        aliases: [www]
+    deploy:
+      replicas: 3
+      labels:
+        team: web
+networks:
+  edge:
+    external: true
+    name: traefik-public
+`
+
+	spec, err := BuildServiceSpecFromCompose(context.Background(), nil, ServiceFromComposeOptions{ComposeContent: content})
+	require.NoError(t, err)
+
+	require.Equal(t, "web", spec.Name)
+	require.Equal(t, map[string]string{"team": "web"}, spec.Labels)
+	require.Equal(t, "nginx:alpine", spec.TaskTemplate.ContainerSpec.Image)
+	require.Equal(t, []string{"nginx", "-g", "daemon off;"}, spec.TaskTemplate.ContainerSpec.Args)
+	require.Contains(t, spec.TaskTemplate.ContainerSpec.Env, "MODE=production")
+	require.Equal(t, []swarm.NetworkAttachmentConfig{{Target: "traefik-public", Aliases: []string{"www"}}}, spec.TaskTemplate.Networks)
+	require.NotNil(t, spec.Mode.Replicated)
+	require.Equal(t, uint64(3), *spec.Mode.Replicated.Replicas)
+	require.NotNil(t, spec.EndpointSpec)
+	require.Len(t, spec.EndpointSpec.Ports, 1)
+	require.Equal(t, uint32(8080), spec.EndpointSpec.Ports[0].PublishedPort)
+}
+
+func TestBuildServiceSpecFromComposeUsesNameOverride(t *testing.T) {
+	spec, err := BuildServiceSpecFromCompose(context.Background(), nil, ServiceFromComposeOptions{
+		Name:           "web-canary",
+		ComposeContent: "services:\n  web:\n    image: nginx:alpine\n",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "web-canary", spec.Name)
+	require.Empty(t, spec.TaskTemplate.Networks, "the implicit default network is not attached")
+}
+
+func TestBuildServiceSpecFromComposeRejectsInvalidFragments(t *testing.T) {
+	tests := map[string]string{
+		"empty":             "",
+		"two services":      "services:\n  web:\n    image: nginx:alpine\n  db:\n    image: postgres:17\n",
+		"no image":          "services:\n  web:\n    build: .\n",
+		"managed network":   "services:\n  web:\n    image: nginx:alpine\n    networks: [backend]\nnetworks:\n  backend: {}\n",
+		"managed secret":    "services:\n  web:\n    image: nginx:alpine\n    secrets: [token]\nsecrets:\n  token:\n    file: ./token.txt\n",
+		"driver volume":     "services:\n  web:\n    image: nginx:alpine\n    volumes: [data:/data]\nvolumes:\n  data:\n    driver: nfs\n",
+		"malformed compose": "services: [web\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := BuildServiceSpecFromCompose(context.Background(), nil, ServiceFromComposeOptions{ComposeContent: content})
+			require.ErrorIs(t, err, cerrdefs.ErrInvalidArgument)
+		})
+	}
+}
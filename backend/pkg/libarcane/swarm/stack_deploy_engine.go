@@ -9,6 +9,7 @@ import (
 	"net/netip"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -279,6 +280,178 @@ func RenderStackConfig(ctx context.Context, opts StackRenderOptions) (*StackRend
 	}, nil
 }
 
+// PlanStack computes the service changes DeployStack would make without
+// modifying Docker.
+//
+// It loads the Compose project the same way DeployStack does, builds the
+// desired service specs, and compares them with the running stack services by
+// name, image, replica mode and count, and attached networks. Services that are
+// no longer declared are planned for removal when opts.Prune is set and are
+// flagged as destructive; otherwise they are reported as orphaned.
+//
+// ctx controls cancellation for Compose loading and Docker API calls.
+// dockerClient must target a swarm manager able to list stack services and networks.
+// opts provides the same stack name, compose content, env content, and prune flag used for deployment.
+//
+// Returns the plan with one entry per desired or running service, sorted by name.
+// Returns an error if the stack name is empty, the compose or env content is
+// invalid, or the running services cannot be listed.
+func PlanStack(ctx context.Context, dockerClient *dockerclient.Client, opts StackDeployOptions) (*swarmtypes.StackDeployPlan, error) {
+	stackName := strings.TrimSpace(opts.Name)
+	if stackName == "" {
+		return nil, errors.New("stack name is required")
+	}
+
+	project, err := loadComposeProject(ctx, stackName, opts.ComposeContent, opts.OverrideContent, opts.EnvContent, opts.WorkingDir, opts.PathMapper)
+	if err != nil {
+		return nil, err
+	}
+
+	stackLabels := map[string]string{swarmtypes.StackNamespaceLabel: stackName}
+	networkNameByKey := make(map[string]string, len(project.Networks))
+	for key, cfg := range project.Networks {
+		networkName := strings.TrimSpace(cfg.Name)
+		if networkName == "" {
+			networkName = key
+		}
+		if bool(cfg.External) {
+			networkNameByKey[key] = networkName
+			continue
+		}
+		networkNameByKey[key] = stackScopedName(stackName, networkName)
+	}
+
+	desiredServices := make(map[string]swarm.ServiceSpec, len(project.Services))
+	for key, service := range project.Services {
+		if service.Name == "" {
+			service.Name = key
+		}
+		spec := buildServiceSpec(service, stackName, stackLabels, networkNameByKey, nil, nil, project.Volumes)
+		desiredServices[spec.Name] = spec
+	}
+
+	existingServices, err := listStackServices(ctx, dockerClient, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	networkNameByID := map[string]string{}
+	if len(existingServices) > 0 {
+		networksResult, err := dockerClient.NetworkList(ctx, dockerclient.NetworkListOptions{})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list networks")
+		}
+		for _, item := range networksResult.Items {
+			networkNameByID[item.ID] = item.Name
+		}
+	}
+
+	return buildStackDeployPlanInternal(desiredServices, existingServices, networkNameByID, opts.Prune), nil
+}
+
+func buildStackDeployPlanInternal(
+	desiredServices map[string]swarm.ServiceSpec,
+	existingServices map[string]swarm.Service,
+	networkNameByID map[string]string,
+	prune bool,
+) *swarmtypes.StackDeployPlan {
+	plan := &swarmtypes.StackDeployPlan{Services: []swarmtypes.StackServicePlan{}}
+
+	for name, spec := range desiredServices {
+		existing, ok := existingServices[name]
+		if !ok {
+			plan.Services = append(plan.Services, swarmtypes.StackServicePlan{Name: name, Action: swarmtypes.StackServicePlanCreate})
+			continue
+		}
+
+		changes := diffStackServiceSpecInternal(existing, spec, networkNameByID)
+		action := swarmtypes.StackServicePlanUnchanged
+		if len(changes) > 0 {
+			action = swarmtypes.StackServicePlanUpdate
+		}
+		plan.Services = append(plan.Services, swarmtypes.StackServicePlan{Name: name, Action: action, Changes: changes})
+	}
+
+	for name := range existingServices {
+		if _, ok := desiredServices[name]; ok {
+			continue
+		}
+		if prune {
+			plan.Services = append(plan.Services, swarmtypes.StackServicePlan{Name: name, Action: swarmtypes.StackServicePlanRemove, Destructive: true})
+			plan.HasDestructiveChanges = true
+			continue
+		}
+		plan.Services = append(plan.Services, swarmtypes.StackServicePlan{Name: name, Action: swarmtypes.StackServicePlanOrphaned})
+	}
+
+	slices.SortFunc(plan.Services, func(a, b swarmtypes.StackServicePlan) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return plan
+}
+
+func diffStackServiceSpecInternal(existing swarm.Service, desired swarm.ServiceSpec, networkNameByID map[string]string) []swarmtypes.StackServiceFieldChange {
+	var changes []swarmtypes.StackServiceFieldChange
+
+	// The stack image label holds the unresolved reference; the container spec
+	// image may carry a pinned digest that would otherwise always look changed.
+	currentImage := existing.Spec.Labels[stackImageLabel]
+	if currentImage == "" {
+		currentImage = resolveServiceImage(existing.Spec)
+	}
+	if desiredImage := resolveServiceImage(desired); desiredImage != currentImage {
+		changes = append(changes, swarmtypes.StackServiceFieldChange{Field: "image", Current: currentImage, Desired: desiredImage})
+	}
+
+	currentMode, currentReplicas := describeServiceModeInternal(existing.Spec.Mode)
+	desiredMode, desiredReplicas := describeServiceModeInternal(desired.Mode)
+	if currentMode != desiredMode {
+		changes = append(changes, swarmtypes.StackServiceFieldChange{Field: "mode", Current: currentMode, Desired: desiredMode})
+	} else if currentReplicas != desiredReplicas {
+		changes = append(changes, swarmtypes.StackServiceFieldChange{Field: "replicas", Current: currentReplicas, Desired: desiredReplicas})
+	}
+
+	currentNetworks := make([]string, 0, len(existing.Spec.TaskTemplate.Networks))
+	for _, attachment := range existing.Spec.TaskTemplate.Networks {
+		name := networkNameByID[attachment.Target]
+		if name == "" {
+			name = attachment.Target
+		}
+		currentNetworks = append(currentNetworks, name)
+	}
+	desiredNetworks := make([]string, 0, len(desired.TaskTemplate.Networks))
+	for _, attachment := range desired.TaskTemplate.Networks {
+		desiredNetworks = append(desiredNetworks, attachment.Target)
+	}
+	slices.Sort(currentNetworks)
+	slices.Sort(desiredNetworks)
+	if !slices.Equal(currentNetworks, desiredNetworks) {
+		changes = append(changes, swarmtypes.StackServiceFieldChange{
+			Field:   "networks",
+			Current: strings.Join(currentNetworks, ","),
+			Desired: strings.Join(desiredNetworks, ","),
+		})
+	}
+
+	return changes
+}
+
+func describeServiceModeInternal(mode swarm.ServiceMode) (string, string) {
+	switch {
+	case mode.Global != nil:
+		return "global", ""
+	case mode.Replicated != nil:
+		return "replicated", strconv.FormatUint(valueOrZero(mode.Replicated.Replicas), 10)
+	case mode.ReplicatedJob != nil:
+		return "replicated-job", strconv.FormatUint(valueOrZero(mode.ReplicatedJob.TotalCompletions), 10)
+	case mode.GlobalJob != nil:
+		return "global-job", ""
+	default:
+		return "unknown", ""
+	}
+}
+
 func loadComposeProject(ctx context.Context, projectName, composeContent, overrideContent, envContent, providedWorkingDir string, pathMapper *projects.PathMapper) (*composegotypes.Project, error) {
 	composeContent = strings.TrimSpace(composeContent)
 	if composeContent == "" {
@@ -718,7 +891,8 @@ func updateSwarmService(
 	if err != nil {
 		return err
 	}
-	queryRegistry := encodedRegistryAuth != "" || shouldQueryRegistryOnUpdate(resolveMode, existing, spec)
+	pinned := keepPinnedServiceImage(&spec, existing)
+	queryRegistry := !pinned && (encodedRegistryAuth != "" || shouldQueryRegistryOnUpdate(resolveMode, existing, spec))
 	if !queryRegistry {
 		preserveExistingResolvedImage(&spec, existing)
 	}
@@ -1308,6 +1482,58 @@ func preserveExistingResolvedImage(spec *swarm.ServiceSpec, existing swarm.Servi
 	}
 }
 
+// keepPinnedServiceImage keeps a service pinned with ServiceImagePinnedLabel
+// on its current image while the compose image it was deployed from is
+// unchanged, carrying the pin over to spec. Reports whether the pin applies.
+func keepPinnedServiceImage(spec *swarm.ServiceSpec, existing swarm.Service) bool {
+	digest := existing.Spec.Labels[swarmtypes.ServiceImagePinnedLabel]
+	if digest == "" || spec.TaskTemplate.ContainerSpec == nil || existing.Spec.TaskTemplate.ContainerSpec == nil {
+		return false
+	}
+	if resolveServiceImage(*spec) != existing.Spec.Labels[stackImageLabel] {
+		return false
+	}
+
+	spec.TaskTemplate.ContainerSpec.Image = existing.Spec.TaskTemplate.ContainerSpec.Image
+	if spec.Labels == nil {
+		spec.Labels = map[string]string{}
+	}
+	spec.Labels[swarmtypes.ServiceImagePinnedLabel] = digest
+	return true
+}
+
+// ListStackServiceImages reports the image every service of stackName runs,
+// sorted by service name: the compose reference it was deployed from and the
+// reference in its spec, with the digest swarm resolved, if any.
+func ListStackServiceImages(ctx context.Context, dockerClient *dockerclient.Client, stackName string) ([]swarmtypes.StackServiceImage, error) {
+	services, err := listStackServices(ctx, dockerClient, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]swarmtypes.StackServiceImage, 0, len(services))
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		images = append(images, stackServiceImageInternal(services[name]))
+	}
+	return images, nil
+}
+
+func stackServiceImageInternal(service swarm.Service) swarmtypes.StackServiceImage {
+	resolved := resolveServiceImage(service.Spec)
+	image := service.Spec.Labels[stackImageLabel]
+	if image == "" {
+		image = resolved
+	}
+	_, digest, _ := strings.Cut(resolved, "@")
+	return swarmtypes.StackServiceImage{
+		Service:       service.Spec.Name,
+		Image:         image,
+		ResolvedImage: resolved,
+		Digest:        digest,
+		Pinned:        service.Spec.Labels[swarmtypes.ServiceImagePinnedLabel] != "",
+	}
+}
+
 func parseEnvContent(envContent string) (map[string]string, error) {
 	env := make(projects.EnvMap)
 	for _, entry := range os.Environ() {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	composegotypes "github.com/compose-spec/compose-go/v2/types"
+	swarmtypes "github.com/getarcaneapp/arcane/types/v2/swarm"
 	"github.com/moby/moby/api/types/mount"
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/stretchr/testify/require"
@@ -100,3 +101,132 @@ func TestApplyDeployConfigConvertsCPUFractionToNanoCPUs(t *testing.T) {
 	require.Equal(t, int64(250_000_000), spec.TaskTemplate.Resources.Reservations.NanoCPUs)
 	require.Equal(t, int64(268435456), spec.TaskTemplate.Resources.Reservations.MemoryBytes)
 }
+
+func TestBuildStackDeployPlanInternal(t *testing.T) {
+	one := uint64(1)
+	three := uint64(3)
+
+	desired := map[string]swarm.ServiceSpec{
+		"demo_web": {
+			Annotations: swarm.Annotations{Name: "demo_web"},
+			Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &three}},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.27"},
+				Networks:      []swarm.NetworkAttachmentConfig{{Target: "demo_default"}},
+			},
+		},
+		"demo_cache": {
+			Annotations: swarm.Annotations{Name: "demo_cache"},
+			Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &one}},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{Image: "redis:7"},
+				Networks:      []swarm.NetworkAttachmentConfig{{Target: "demo_default"}},
+			},
+		},
+		"demo_api": {
+			Annotations:  swarm.Annotations{Name: "demo_api"},
+			TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "api:latest"}},
+		},
+	}
+
+	existing := map[string]swarm.Service{
+		"demo_web": {Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: "demo_web", Labels: map[string]string{stackImageLabel: "nginx:alpine"}},
+			Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &one}},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{Image: "nginx:alpine@sha256:abc"},
+				Networks:      []swarm.NetworkAttachmentConfig{{Target: "net-id"}},
+			},
+		}},
+		"demo_cache": {Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: "demo_cache", Labels: map[string]string{stackImageLabel: "redis:7"}},
+			Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &one}},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{Image: "redis:7@sha256:def"},
+				Networks:      []swarm.NetworkAttachmentConfig{{Target: "net-id"}},
+			},
+		}},
+		"demo_worker": {Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "demo_worker"}}},
+	}
+	networkNameByID := map[string]string{"net-id": "demo_default"}
+
+	t.Run("prune flags removals as destructive", func(t *testing.T) {
+		plan := buildStackDeployPlanInternal(desired, existing, networkNameByID, true)
+		require.True(t, plan.HasDestructiveChanges)
+		require.Len(t, plan.Services, 4)
+
+		byName := make(map[string]swarmtypes.StackServicePlan, len(plan.Services))
+		for _, service := range plan.Services {
+			byName[service.Name] = service
+		}
+
+		require.Equal(t, swarmtypes.StackServicePlanCreate, byName["demo_api"].Action)
+		require.Equal(t, swarmtypes.StackServicePlanUnchanged, byName["demo_cache"].Action)
+		require.Equal(t, swarmtypes.StackServicePlanRemove, byName["demo_worker"].Action)
+		require.True(t, byName["demo_worker"].Destructive)
+
+		web := byName["demo_web"]
+		require.Equal(t, swarmtypes.StackServicePlanUpdate, web.Action)
+		require.Equal(t, []swarmtypes.StackServiceFieldChange{
+			{Field: "image", Current: "nginx:alpine", Desired: "nginx:1.27"},
+			{Field: "replicas", Current: "1", Desired: "3"},
+		}, web.Changes)
+	})
+
+	t.Run("without prune undeclared services are orphaned", func(t *testing.T) {
+		plan := buildStackDeployPlanInternal(desired, existing, networkNameByID, false)
+		require.False(t, plan.HasDestructiveChanges)
+		require.Equal(t, "demo_worker", plan.Services[3].Name)
+		require.Equal(t, swarmtypes.StackServicePlanOrphaned, plan.Services[3].Action)
+		require.False(t, plan.Services[3].Destructive)
+	})
+}
+
+func TestKeepPinnedServiceImage(t *testing.T) {
+	const digest = "sha256:0f24d43d5f1e8a5a3dcd64cb2b3e1f8f0b6c2e07f8a4e5c6d7b8a9f0e1d2c3b4"
+	existing := swarm.Service{Spec: swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Labels: map[string]string{
+			stackImageLabel:                    "nginx:latest",
+			swarmtypes.ServiceImagePinnedLabel: digest,
+		}},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx@" + digest}},
+	}}
+
+	spec := swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Labels: map[string]string{stackImageLabel: "nginx:latest"}},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx:latest"}},
+	}
+	require.True(t, keepPinnedServiceImage(&spec, existing))
+	require.Equal(t, "nginx@"+digest, spec.TaskTemplate.ContainerSpec.Image)
+	require.Equal(t, digest, spec.Labels[swarmtypes.ServiceImagePinnedLabel])
+
+	changed := swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Labels: map[string]string{stackImageLabel: "nginx:1.27"}},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.27"}},
+	}
+	require.False(t, keepPinnedServiceImage(&changed, existing), "a new compose image drops the pin")
+	require.Equal(t, "nginx:1.27", changed.TaskTemplate.ContainerSpec.Image)
+	require.Empty(t, changed.Labels[swarmtypes.ServiceImagePinnedLabel])
+}
+
+func TestStackServiceImageInternal(t *testing.T) {
+	const digest = "sha256:0f24d43d5f1e8a5a3dcd64cb2b3e1f8f0b6c2e07f8a4e5c6d7b8a9f0e1d2c3b4"
+
+	image := stackServiceImageInternal(swarm.Service{Spec: swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Name: "shop_web", Labels: map[string]string{stackImageLabel: "nginx:latest"}},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx:latest@" + digest}},
+	}})
+	require.Equal(t, swarmtypes.StackServiceImage{
+		Service:       "shop_web",
+		Image:         "nginx:latest",
+		ResolvedImage: "nginx:latest@" + digest,
+		Digest:        digest,
+	}, image)
+
+	unresolved := stackServiceImageInternal(swarm.Service{Spec: swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Name: "shop_cache"},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "redis:7"}},
+	}})
+	require.Equal(t, "redis:7", unresolved.Image)
+	require.Empty(t, unresolved.Digest)
+}
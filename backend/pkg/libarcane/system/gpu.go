@@ -1,13 +1,18 @@
 package system
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/csv"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,21 +28,46 @@ import (
 const (
 	AMDGPUSysfsPath = "/sys/class/drm"
 
+	// JetsonReleasePath is present on NVIDIA Jetson (L4T) boards.
+	JetsonReleasePath = "/etc/nv_tegra_release"
+
 	// gpuDetectionTTL bounds how long a successful detection result is reused before re-detecting.
 	gpuDetectionTTL = 30 * time.Second
+
+	// nvidiaBaseQueryFields are always queried; extra fields are appended after them.
+	nvidiaBaseQueryFields = "index,name,memory.used,memory.total,utilization.gpu,temperature.gpu"
 )
 
-// GPUMonitor probes for an attached GPU (NVIDIA / AMD / Intel) and reports VRAM usage.
+// nvidiaQueryFieldPattern matches a single nvidia-smi --query-gpu property such as
+// "power.draw" or "clocks.current.sm".
+var nvidiaQueryFieldPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z0-9_]+)*$`)
+
+// GPUToolConfig points the monitor at vendor tools outside PATH and extends the
+// nvidia-smi query. Empty paths fall back to exec.LookPath.
+type GPUToolConfig struct {
+	NvidiaSmiPath   string
+	IntelGpuTopPath string
+	TegrastatsPath  string
+	// NvidiaExtraFields are appended to the nvidia-smi query and surfaced in GPUStats.Extra.
+	NvidiaExtraFields []string
+}
+
+// GPUMonitor probes for an attached GPU (NVIDIA / Jetson / AMD / Intel) and reports VRAM usage.
 // Detection is cached for gpuDetectionTTL; once a vendor is detected, subsequent Stats
 // calls invoke the vendor-specific tool directly.
 type GPUMonitor struct {
 	enabled        bool
 	configuredType string
+	vendorOverride func(context.Context) string
+	toolConfig     func(context.Context) GPUToolConfig
 
 	detectionMu   sync.Mutex
 	detectionDone bool
 
 	detectionCache *hot.HotCache[struct{}, gpuDetection]
+
+	toolMu sync.Mutex
+	tools  map[string]gpuToolResolution
 }
 
 type gpuDetection struct {
@@ -46,8 +76,19 @@ type gpuDetection struct {
 	toolPath string
 }
 
+// gpuToolResolution is the lookup result for one vendor tool. A found tool is
+// kept until the configured path for that tool changes; a miss is retried once
+// it is gpuDetectionTTL old, so a tool installed later is picked up.
+type gpuToolResolution struct {
+	configured string
+	path       string
+	err        error
+	warned     bool
+	checkedAt  time.Time
+}
+
 // NewGPUMonitor creates a monitor. enabled gates Stats; when false, Stats returns
-// (nil, nil). configuredType is the user-pinned vendor ("nvidia"|"amd"|"intel"|"auto"|"")
+// (nil, nil). configuredType is the user-pinned vendor ("nvidia"|"amd"|"intel"|"jetson"|"auto"|"")
 // — anything else falls back to auto-detection.
 func NewGPUMonitor(enabled bool, configuredType string) *GPUMonitor {
 	return &GPUMonitor{
@@ -59,16 +100,80 @@ func NewGPUMonitor(enabled bool, configuredType string) *GPUMonitor {
 	}
 }
 
+// WithVendorOverride installs a resolver consulted on every Stats call. A known vendor
+// ("nvidia"|"amd"|"intel"|"jetson") skips detection entirely, "none" disables polling,
+// and any other value ("auto"|"") defers to the configured type and auto-detection.
+func (m *GPUMonitor) WithVendorOverride(resolve func(context.Context) string) *GPUMonitor {
+	m.vendorOverride = resolve
+	return m
+}
+
+// WithToolConfig installs a resolver for tool paths and extra nvidia-smi fields,
+// consulted on every detection and Stats call so setting changes apply without a restart.
+// Found tools are only looked up again when their configured path changes.
+func (m *GPUMonitor) WithToolConfig(resolve func(context.Context) GPUToolConfig) *GPUMonitor {
+	m.toolConfig = resolve
+	return m
+}
+
 // Enabled reports whether GPU monitoring is on.
 func (m *GPUMonitor) Enabled() bool { return m.enabled }
 
-// Stats returns per-GPU VRAM stats. Returns (nil, 0, nil) when monitoring is disabled
-// or no GPU is detected; vendor-specific errors are propagated otherwise.
+// DetectedType reports the vendor Stats will query: the override when one is pinned,
+// otherwise the cached (or freshly probed) detection result. Returns "none" when
+// monitoring is disabled and "" when no supported GPU was found.
+func (m *GPUMonitor) DetectedType(ctx context.Context) string {
+	if !m.enabled {
+		return "none"
+	}
+
+	switch vendor := m.resolveVendorOverrideInternal(ctx); vendor {
+	case "none", "nvidia", "amd", "intel", "jetson":
+		return vendor
+	}
+
+	if detection, found, _ := m.detectionCache.Get(struct{}{}); found && detection.detected {
+		return detection.gpuType
+	}
+	if err := m.detectInternal(ctx); err != nil {
+		return ""
+	}
+	if detection, found, _ := m.detectionCache.Get(struct{}{}); found && detection.detected {
+		return detection.gpuType
+	}
+	return ""
+}
+
+// resolveVendorOverrideInternal returns the normalized override value, or "" when unset.
+func (m *GPUMonitor) resolveVendorOverrideInternal(ctx context.Context) string {
+	if m.vendorOverride == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(m.vendorOverride(ctx)))
+}
+
+// resolveToolConfigInternal returns the configured tool overrides, or the zero value when unset.
+func (m *GPUMonitor) resolveToolConfigInternal(ctx context.Context) GPUToolConfig {
+	if m.toolConfig == nil {
+		return GPUToolConfig{}
+	}
+	return m.toolConfig(ctx)
+}
+
+// Stats returns per-GPU VRAM stats. Returns (nil, nil) when monitoring is disabled or
+// the vendor override is "none"; vendor-specific errors are propagated otherwise.
 func (m *GPUMonitor) Stats(ctx context.Context) ([]systemtypes.GPUStats, error) {
 	if !m.enabled {
 		return nil, nil
 	}
 
+	switch vendor := m.resolveVendorOverrideInternal(ctx); vendor {
+	case "none":
+		return nil, nil
+	case "nvidia", "amd", "intel", "jetson":
+		return m.statsForTypeInternal(ctx, vendor)
+	}
+
 	m.detectionMu.Lock()
 	done := m.detectionDone
 	m.detectionMu.Unlock()
@@ -94,18 +199,64 @@ func (m *GPUMonitor) Stats(ctx context.Context) ([]systemtypes.GPUStats, error)
 }
 
 func (m *GPUMonitor) statsForTypeInternal(ctx context.Context, gpuType string) ([]systemtypes.GPUStats, error) {
+	tools := m.resolveToolConfigInternal(ctx)
 	switch gpuType {
 	case "nvidia":
-		return getNvidiaStatsInternal(ctx)
+		path, err := m.resolveToolInternal(ctx, tools.NvidiaSmiPath, "nvidia-smi", true)
+		if err != nil {
+			return nil, err
+		}
+		return getNvidiaStatsInternal(ctx, path, tools.NvidiaExtraFields)
 	case "amd":
 		return getAMDStatsInternal(ctx)
 	case "intel":
 		return getIntelStatsInternal(ctx)
+	case "jetson":
+		path, err := m.resolveToolInternal(ctx, tools.TegrastatsPath, "tegrastats", true)
+		if err != nil {
+			return nil, err
+		}
+		return getJetsonStatsInternal(ctx, path)
 	default:
 		return nil, errors.New("no supported GPU found")
 	}
 }
 
+// resolveToolInternal returns the path of a vendor tool. A successful lookup runs
+// once per configured path rather than on every poll, and a failed one at most
+// once per gpuDetectionTTL. When warnMissing is set, a missing tool is logged
+// once per configured path instead of on each poll; detection probes pass false
+// because a missing tool there only means another vendor is tried.
+func (m *GPUMonitor) resolveToolInternal(ctx context.Context, configured, name string, warnMissing bool) (string, error) {
+	configured = strings.TrimSpace(configured)
+
+	m.toolMu.Lock()
+	defer m.toolMu.Unlock()
+
+	resolution, ok := m.tools[name]
+	samePath := ok && resolution.configured == configured
+	if !samePath || (resolution.err != nil && time.Since(resolution.checkedAt) >= gpuDetectionTTL) {
+		path, err := lookupGPUToolInternal(ctx, configured, name)
+		resolution = gpuToolResolution{
+			configured: configured,
+			path:       path,
+			err:        err,
+			warned:     samePath && resolution.warned,
+			checkedAt:  time.Now(),
+		}
+	}
+	if resolution.err != nil && warnMissing && !resolution.warned {
+		slog.WarnContext(ctx, "GPU tool not found", "tool", name, "error", resolution.err)
+		resolution.warned = true
+	}
+
+	if m.tools == nil {
+		m.tools = make(map[string]gpuToolResolution)
+	}
+	m.tools[name] = resolution
+	return resolution.path, resolution.err
+}
+
 // markDetected records a successful detection.
 func (m *GPUMonitor) markDetectedInternal(gpuType, toolPath string) {
 	m.detectionCache.Set(struct{}{}, gpuDetection{detected: true, gpuType: gpuType, toolPath: toolPath})
@@ -113,15 +264,17 @@ func (m *GPUMonitor) markDetectedInternal(gpuType, toolPath string) {
 }
 
 // detect runs vendor probing under detectionMu. The configuredType pin is honored when set
-// to a known vendor; otherwise vendors are tried in order: nvidia → amd → intel.
+// to a known vendor; otherwise vendors are tried in order: nvidia → jetson → amd → intel.
 func (m *GPUMonitor) detectInternal(ctx context.Context) error {
 	m.detectionMu.Lock()
 	defer m.detectionMu.Unlock()
 
+	tools := m.resolveToolConfigInternal(ctx)
+
 	if t := m.configuredType; t != "" && t != "auto" {
 		switch t {
 		case "nvidia":
-			if path, err := exec.LookPath("nvidia-smi"); err == nil {
+			if path, err := m.resolveToolInternal(ctx, tools.NvidiaSmiPath, "nvidia-smi", false); err == nil {
 				m.markDetectedInternal("nvidia", path)
 				slog.InfoContext(ctx, "Using configured GPU type", "type", "nvidia")
 				return nil
@@ -135,28 +288,40 @@ func (m *GPUMonitor) detectInternal(ctx context.Context) error {
 			}
 			return errors.New("AMD GPU not found in sysfs but GPU_TYPE set to amd")
 		case "intel":
-			if path, err := exec.LookPath("intel_gpu_top"); err == nil {
+			if path, err := m.resolveToolInternal(ctx, tools.IntelGpuTopPath, "intel_gpu_top", false); err == nil {
 				m.markDetectedInternal("intel", path)
 				slog.InfoContext(ctx, "Using configured GPU type", "type", "intel")
 				return nil
 			}
 			return errors.New("intel_gpu_top not found but GPU_TYPE set to intel")
+		case "jetson":
+			if m.hasJetsonGPUInternal(ctx, tools.TegrastatsPath) {
+				m.markDetectedInternal("jetson", "tegrastats")
+				slog.InfoContext(ctx, "Using configured GPU type", "type", "jetson")
+				return nil
+			}
+			return errors.New("tegrastats not found but GPU_TYPE set to jetson")
 		default:
 			slog.WarnContext(ctx, "Invalid GPU_TYPE specified, falling back to auto-detection", "gpu_type", t)
 		}
 	}
 
-	if path, err := exec.LookPath("nvidia-smi"); err == nil {
+	if path, err := m.resolveToolInternal(ctx, tools.NvidiaSmiPath, "nvidia-smi", false); err == nil {
 		m.markDetectedInternal("nvidia", path)
 		slog.InfoContext(ctx, "NVIDIA GPU detected", "tool", "nvidia-smi", "path", path)
 		return nil
 	}
+	if m.hasJetsonGPUInternal(ctx, tools.TegrastatsPath) {
+		m.markDetectedInternal("jetson", "tegrastats")
+		slog.InfoContext(ctx, "NVIDIA Jetson GPU detected", "tool", "tegrastats")
+		return nil
+	}
 	if HasAMDGPU() {
 		m.markDetectedInternal("amd", AMDGPUSysfsPath)
 		slog.InfoContext(ctx, "AMD GPU detected", "method", "sysfs", "path", AMDGPUSysfsPath)
 		return nil
 	}
-	if path, err := exec.LookPath("intel_gpu_top"); err == nil {
+	if path, err := m.resolveToolInternal(ctx, tools.IntelGpuTopPath, "intel_gpu_top", false); err == nil {
 		m.markDetectedInternal("intel", path)
 		slog.InfoContext(ctx, "Intel GPU detected", "tool", "intel_gpu_top", "path", path)
 		return nil
@@ -166,6 +331,60 @@ func (m *GPUMonitor) detectInternal(ctx context.Context) error {
 	return errors.New("no supported GPU found")
 }
 
+// lookupGPUToolInternal resolves a vendor tool, preferring the configured path. A
+// configured path that is not an executable file is logged and PATH is searched instead.
+func lookupGPUToolInternal(ctx context.Context, configured, name string) (string, error) {
+	if configured = strings.TrimSpace(configured); configured != "" {
+		err := ValidateGPUToolPath(configured)
+		if err == nil {
+			return configured, nil
+		}
+		slog.WarnContext(ctx, "Configured GPU tool path is unusable, searching PATH", "tool", name, "path", configured, "error", err)
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", errors.WrapIff(err, "%s not found", name)
+	}
+	return path, nil
+}
+
+// ValidateGPUToolPath checks that path is an absolute path to an executable regular file.
+func ValidateGPUToolPath(path string) error {
+	if !filepath.IsAbs(path) {
+		return errors.Errorf("GPU tool path %q must be absolute", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.WrapIff(err, "GPU tool path %q is not accessible", path)
+	}
+	if !info.Mode().IsRegular() {
+		return errors.Errorf("GPU tool path %q is not a regular file", path)
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		return errors.Errorf("GPU tool path %q is not executable", path)
+	}
+	return nil
+}
+
+// ParseNvidiaQueryFields parses a comma- or newline-separated list of extra
+// nvidia-smi --query-gpu properties. Duplicates and fields that are always
+// queried are dropped; names that nvidia-smi could never accept are rejected.
+func ParseNvidiaQueryFields(value string) ([]string, error) {
+	base := strings.Split(nvidiaBaseQueryFields, ",")
+	var fields []string
+	for raw := range strings.FieldsFuncSeq(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		field := strings.ToLower(strings.TrimSpace(raw))
+		if field == "" || slices.Contains(base, field) || slices.Contains(fields, field) {
+			continue
+		}
+		if !nvidiaQueryFieldPattern.MatchString(field) {
+			return nil, errors.Errorf("invalid nvidia-smi query field %q", raw)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
 // HasAMDGPU reports whether a card with mem_info_vram_total exists under AMDGPUSysfsPath.
 func HasAMDGPU() bool {
 	entries, err := os.ReadDir(AMDGPUSysfsPath)
@@ -193,22 +412,50 @@ func readSysfsValueInternal(path string) (uint64, error) {
 	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
 }
 
-func getNvidiaStatsInternal(ctx context.Context) ([]systemtypes.GPUStats, error) {
+// getNvidiaStatsInternal queries nvidia-smi at toolPath. nvidia-smi rejects the whole
+// query when any field is unknown to the installed driver, so a failure with extra
+// fields is retried with the base query to keep the core metrics flowing.
+func getNvidiaStatsInternal(ctx context.Context, toolPath string, extraFields []string) ([]systemtypes.GPUStats, error) {
+	output, err := runNvidiaQueryInternal(ctx, toolPath, extraFields)
+	if err != nil && len(extraFields) > 0 {
+		slog.WarnContext(ctx, "nvidia-smi rejected extra query fields, retrying without them", "fields", strings.Join(extraFields, ","), "error", err)
+		extraFields = nil
+		output, err = runNvidiaQueryInternal(ctx, toolPath, nil)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to execute nvidia-smi", "path", toolPath, "error", err)
+		return nil, errors.WrapIf(err, "nvidia-smi execution failed")
+	}
+
+	stats, err := parseNvidiaOutputInternal(ctx, output, extraFields)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Collected NVIDIA GPU stats", "gpu_count", len(stats))
+	return stats, nil
+}
+
+func runNvidiaQueryInternal(ctx context.Context, toolPath string, extraFields []string) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "nvidia-smi",
-		"--query-gpu=index,name,memory.used,memory.total",
-		"--format=csv,noheader,nounits")
-
-	output, err := cmd.Output()
-	if err != nil {
-		slog.WarnContext(ctx, "Failed to execute nvidia-smi", "error", err)
-		return nil, errors.WrapIf(err, "nvidia-smi execution failed")
+	query := nvidiaBaseQueryFields
+	if len(extraFields) > 0 {
+		query += "," + strings.Join(extraFields, ",")
 	}
+	return exec.CommandContext(ctx, toolPath, "--query-gpu="+query, "--format=csv,noheader,nounits").Output()
+}
 
+// parseNvidiaOutputInternal parses nvidia-smi CSV rows of
+// index,name,memory.used,memory.total[,utilization.gpu,temperature.gpu[,extraFields...]].
+// Utilization and temperature are optional: older drivers and some datacenter
+// cards report "[N/A]" or omit them, in which case the fields stay nil. Extra
+// columns are kept verbatim in Extra, keyed by field name, skipping "[N/A]".
+func parseNvidiaOutputInternal(ctx context.Context, output []byte, extraFields []string) ([]systemtypes.GPUStats, error) {
 	reader := csv.NewReader(bytes.NewReader(output))
 	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
 	records, err := reader.ReadAll()
 	if err != nil {
 		slog.WarnContext(ctx, "Failed to parse nvidia-smi CSV output", "error", err)
@@ -235,22 +482,50 @@ func getNvidiaStatsInternal(ctx context.Context) ([]systemtypes.GPUStats, error)
 			slog.WarnContext(ctx, "Failed to parse memory total", "value", record[3])
 			continue
 		}
-		stats = append(stats, systemtypes.GPUStats{
+		gpu := systemtypes.GPUStats{
 			Name:        strings.TrimSpace(record[1]),
 			Index:       index,
 			MemoryUsed:  memUsed * 1024 * 1024,
 			MemoryTotal: memTotal * 1024 * 1024,
-		})
+		}
+		if len(record) > 4 {
+			gpu.UtilizationPercent = parseOptionalGPUMetricInternal(record[4])
+		}
+		if len(record) > 5 {
+			gpu.TemperatureC = parseOptionalGPUMetricInternal(record[5])
+		}
+		for i, field := range extraFields {
+			if len(record) <= 6+i {
+				break
+			}
+			value := strings.TrimSpace(record[6+i])
+			if value == "" || strings.Contains(value, "N/A") {
+				continue
+			}
+			if gpu.Extra == nil {
+				gpu.Extra = make(map[string]string, len(extraFields))
+			}
+			gpu.Extra[field] = value
+		}
+		stats = append(stats, gpu)
 	}
 
 	if len(stats) == 0 {
 		return nil, errors.New("no GPU data parsed from nvidia-smi")
 	}
 
-	slog.DebugContext(ctx, "Collected NVIDIA GPU stats", "gpu_count", len(stats))
 	return stats, nil
 }
 
+// parseOptionalGPUMetricInternal returns nil for values the driver cannot report ("[N/A]", "N/A", empty).
+func parseOptionalGPUMetricInternal(raw string) *float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
 func getAMDStatsInternal(ctx context.Context) ([]systemtypes.GPUStats, error) {
 	entries, err := os.ReadDir(AMDGPUSysfsPath)
 	if err != nil {
@@ -277,12 +552,19 @@ func getAMDStatsInternal(ctx context.Context) ([]systemtypes.GPUStats, error) {
 			continue
 		}
 
-		stats = append(stats, systemtypes.GPUStats{
+		gpu := systemtypes.GPUStats{
 			Name:        fmt.Sprintf("AMD GPU %d", index),
 			Index:       index,
 			MemoryUsed:  float64(memUsedBytes),
 			MemoryTotal: float64(memTotalBytes),
-		})
+		}
+		// Utilization and temperature are exposed by amdgpu through the same sysfs tree
+		// rocm-smi reads; older kernels may lack them, so both are best-effort.
+		if busy, err := readSysfsValueInternal(devicePath + "/gpu_busy_percent"); err == nil {
+			gpu.UtilizationPercent = new(float64(busy))
+		}
+		gpu.TemperatureC = readAMDTemperatureInternal(devicePath)
+		stats = append(stats, gpu)
 		index++
 	}
 
@@ -294,10 +576,182 @@ func getAMDStatsInternal(ctx context.Context) ([]systemtypes.GPUStats, error) {
 	return stats, nil
 }
 
+// readAMDTemperatureInternal reads the edge temperature from the card's hwmon directory.
+// The value is reported in millidegrees Celsius.
+func readAMDTemperatureInternal(devicePath string) *float64 {
+	hwmonDirs, err := filepath.Glob(devicePath + "/hwmon/hwmon*")
+	if err != nil {
+		return nil
+	}
+	for _, dir := range hwmonDirs {
+		milli, err := readSysfsValueInternal(dir + "/temp1_input")
+		if err != nil {
+			continue
+		}
+		return new(float64(milli) / 1000)
+	}
+	return nil
+}
+
 func getIntelStatsInternal(ctx context.Context) ([]systemtypes.GPUStats, error) {
-	stats := []systemtypes.GPUStats{
-		{Name: "Intel GPU", Index: 0, MemoryUsed: 0, MemoryTotal: 0},
+	cards, err := readIntelVRAMInfoInternal(AMDGPUSysfsPath)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read DRM sysfs directory", "error", err)
+		return nil, errors.WrapIf(err, "failed to read sysfs")
+	}
+
+	// Integrated GPUs share system memory and expose no VRAM counters; keep reporting
+	// a single entry so the dashboard still shows that an Intel GPU is present.
+	if len(cards) == 0 {
+		slog.DebugContext(ctx, "Intel GPU detected but no discrete VRAM counters found in sysfs")
+		return []systemtypes.GPUStats{{Name: "Intel GPU", Index: 0}}, nil
 	}
-	slog.DebugContext(ctx, "Intel GPU detected but detailed stats not yet implemented")
+
+	stats := make([]systemtypes.GPUStats, 0, len(cards))
+	for index, card := range cards {
+		stats = append(stats, systemtypes.GPUStats{
+			Name:        fmt.Sprintf("Intel GPU %d", index),
+			Index:       index,
+			MemoryUsed:  float64(card.used),
+			MemoryTotal: float64(card.total),
+		})
+	}
+
+	slog.DebugContext(ctx, "Collected Intel GPU stats", "gpu_count", len(stats))
 	return stats, nil
 }
+
+// intelVRAMInfo holds the local-memory counters of one discrete Intel card.
+type intelVRAMInfo struct {
+	cardPath  string
+	cardIndex int
+	total     uint64
+	used      uint64
+}
+
+// readIntelVRAMInfoInternal scans drmRoot for Intel (PCI vendor 0x8086) cards exposing
+// local-memory counters and returns one entry per card, ordered by card number. Kernels
+// differ in which attributes they publish: the xe driver uses mem_info_vram_* on the
+// device, while i915 reports lmem_total_bytes / lmem_avail_bytes on the card itself.
+func readIntelVRAMInfoInternal(drmRoot string) ([]intelVRAMInfo, error) {
+	entries, err := os.ReadDir(drmRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []intelVRAMInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		digits, ok := strings.CutPrefix(name, "card")
+		cardIndex, err := strconv.Atoi(digits)
+		if !ok || err != nil {
+			continue
+		}
+
+		cardPath := filepath.Join(drmRoot, name)
+		vendor, err := os.ReadFile(filepath.Join(cardPath, "device", "vendor"))
+		if err != nil || strings.TrimSpace(string(vendor)) != "0x8086" {
+			continue
+		}
+
+		if total, err := readSysfsValueInternal(filepath.Join(cardPath, "device", "mem_info_vram_total")); err == nil && total > 0 {
+			used, _ := readSysfsValueInternal(filepath.Join(cardPath, "device", "mem_info_vram_used"))
+			cards = append(cards, intelVRAMInfo{cardPath: cardPath, cardIndex: cardIndex, total: total, used: used})
+			continue
+		}
+
+		total, err := readSysfsValueInternal(filepath.Join(cardPath, "lmem_total_bytes"))
+		if err != nil || total == 0 {
+			continue
+		}
+		info := intelVRAMInfo{cardPath: cardPath, cardIndex: cardIndex, total: total}
+		if avail, err := readSysfsValueInternal(filepath.Join(cardPath, "lmem_avail_bytes")); err == nil && avail <= total {
+			info.used = total - avail
+		}
+		cards = append(cards, info)
+	}
+
+	slices.SortFunc(cards, func(a, b intelVRAMInfo) int { return cmp.Compare(a.cardIndex, b.cardIndex) })
+	return cards, nil
+}
+
+// HasJetsonGPU reports whether the host is an NVIDIA Jetson (Tegra) board.
+func HasJetsonGPU() bool {
+	if _, err := os.Stat(JetsonReleasePath); err == nil {
+		return true
+	}
+	_, err := lookupGPUToolInternal(context.Background(), "", "tegrastats")
+	return err == nil
+}
+
+func (m *GPUMonitor) hasJetsonGPUInternal(ctx context.Context, tegrastatsPath string) bool {
+	if _, err := os.Stat(JetsonReleasePath); err == nil {
+		return true
+	}
+	_, err := m.resolveToolInternal(ctx, tegrastatsPath, "tegrastats", false)
+	return err == nil
+}
+
+// getJetsonStatsInternal samples a single tegrastats report from toolPath. tegrastats
+// streams until killed, so the first line is read and the process is cancelled via ctx.
+func getJetsonStatsInternal(ctx context.Context, toolPath string) ([]systemtypes.GPUStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, toolPath, "--interval", "500")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to open tegrastats output")
+	}
+	if err := cmd.Start(); err != nil {
+		slog.WarnContext(ctx, "Failed to execute tegrastats", "error", err)
+		return nil, errors.WrapIf(err, "tegrastats execution failed")
+	}
+	defer func() {
+		cancel()
+		_ = cmd.Wait()
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, errors.WrapIf(err, "failed to read tegrastats output")
+		}
+		return nil, errors.New("no output from tegrastats")
+	}
+
+	gpu := parseTegrastatsLineInternal(scanner.Text())
+	slog.DebugContext(ctx, "Collected Jetson GPU stats")
+	return []systemtypes.GPUStats{gpu}, nil
+}
+
+// parseTegrastatsLineInternal extracts GPU metrics from one tegrastats report, e.g.
+// "RAM 2658/7620MB ... GR3D_FREQ 45%@[1300] ... GPU@40.5C". Jetson GPUs share system
+// memory, so the RAM counters are reported as the GPU's memory.
+func parseTegrastatsLineInternal(line string) systemtypes.GPUStats {
+	gpu := systemtypes.GPUStats{Name: "NVIDIA Jetson GPU", Index: 0}
+
+	fields := strings.Fields(line)
+	for i, field := range fields {
+		switch {
+		case field == "RAM" && i+1 < len(fields):
+			usedRaw, totalRaw, ok := strings.Cut(strings.TrimSuffix(fields[i+1], "MB"), "/")
+			if !ok {
+				continue
+			}
+			used, usedErr := strconv.ParseFloat(usedRaw, 64)
+			total, totalErr := strconv.ParseFloat(totalRaw, 64)
+			if usedErr == nil && totalErr == nil {
+				gpu.MemoryUsed = used * 1024 * 1024
+				gpu.MemoryTotal = total * 1024 * 1024
+			}
+		case field == "GR3D_FREQ" && i+1 < len(fields):
+			load, _, _ := strings.Cut(fields[i+1], "%")
+			gpu.UtilizationPercent = parseOptionalGPUMetricInternal(load)
+		case strings.HasPrefix(strings.ToLower(field), "gpu@") && strings.HasSuffix(field, "C"):
+			gpu.TemperatureC = parseOptionalGPUMetricInternal(strings.TrimSuffix(field[len("gpu@"):], "C"))
+		}
+	}
+
+	return gpu
+}
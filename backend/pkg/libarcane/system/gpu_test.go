@@ -0,0 +1,207 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNvidiaOutputInternal(t *testing.T) {
+	output := []byte("0, NVIDIA GeForce RTX 4090, 1024, 24564, 87, 65\n1, Tesla K80, 512, 11441, [N/A], [N/A]\n")
+
+	stats, err := parseNvidiaOutputInternal(context.Background(), output, nil)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	require.Equal(t, "NVIDIA GeForce RTX 4090", stats[0].Name)
+	require.Equal(t, float64(1024*1024*1024), stats[0].MemoryUsed)
+	require.NotNil(t, stats[0].UtilizationPercent)
+	require.InDelta(t, 87, *stats[0].UtilizationPercent, 0.001)
+	require.NotNil(t, stats[0].TemperatureC)
+	require.InDelta(t, 65, *stats[0].TemperatureC, 0.001)
+
+	require.Equal(t, 1, stats[1].Index)
+	require.Nil(t, stats[1].UtilizationPercent)
+	require.Nil(t, stats[1].TemperatureC)
+}
+
+func TestParseNvidiaOutputInternal_AcceptsLegacyColumns(t *testing.T) {
+	stats, err := parseNvidiaOutputInternal(context.Background(), []byte("0, Quadro P400, 100, 2000\n"), nil)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	require.Nil(t, stats[0].UtilizationPercent)
+}
+
+func TestParseNvidiaOutputInternal_ExtraFields(t *testing.T) {
+	output := []byte("0, NVIDIA A100, 1024, 40960, 12, 40, 61.25, P0\n1, NVIDIA A100, 2048, 40960, 0, 38, [N/A], P8\n")
+
+	stats, err := parseNvidiaOutputInternal(context.Background(), output, []string{"power.draw", "pstate"})
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	require.Equal(t, map[string]string{"power.draw": "61.25", "pstate": "P0"}, stats[0].Extra)
+	require.Equal(t, map[string]string{"pstate": "P8"}, stats[1].Extra)
+}
+
+func TestParseNvidiaQueryFields(t *testing.T) {
+	fields, err := ParseNvidiaQueryFields(" power.draw, Fan.Speed\nmemory.used,power.draw,,clocks.current.sm ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"power.draw", "fan.speed", "clocks.current.sm"}, fields)
+
+	fields, err = ParseNvidiaQueryFields("")
+	require.NoError(t, err)
+	require.Empty(t, fields)
+
+	_, err = ParseNvidiaQueryFields("power.draw,--format=csv")
+	require.Error(t, err)
+	_, err = ParseNvidiaQueryFields("power draw")
+	require.Error(t, err)
+}
+
+func TestValidateGPUToolPath(t *testing.T) {
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "nvidia-smi")
+	require.NoError(t, os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755))
+	plain := filepath.Join(dir, "plain")
+	require.NoError(t, os.WriteFile(plain, []byte("data"), 0o600))
+
+	require.NoError(t, ValidateGPUToolPath(tool))
+	require.Error(t, ValidateGPUToolPath("nvidia-smi"))
+	require.Error(t, ValidateGPUToolPath(plain))
+	require.Error(t, ValidateGPUToolPath(dir))
+	require.Error(t, ValidateGPUToolPath(filepath.Join(dir, "missing")))
+}
+
+func TestLookupGPUToolInternal_PrefersConfiguredPath(t *testing.T) {
+	tool := filepath.Join(t.TempDir(), "tegrastats")
+	require.NoError(t, os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755))
+
+	path, err := lookupGPUToolInternal(context.Background(), tool, "arcane-missing-gpu-tool")
+	require.NoError(t, err)
+	require.Equal(t, tool, path)
+
+	_, err = lookupGPUToolInternal(context.Background(), filepath.Join(t.TempDir(), "missing"), "arcane-missing-gpu-tool")
+	require.Error(t, err)
+}
+
+func TestGPUMonitor_ResolveToolInternal_CachesUntilPathChanges(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "nvidia-smi")
+	require.NoError(t, os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755))
+	monitor := NewGPUMonitor(true, "")
+
+	path, err := monitor.resolveToolInternal(ctx, tool, "arcane-missing-gpu-tool", true)
+	require.NoError(t, err)
+	require.Equal(t, tool, path)
+
+	// Polls reuse the lookup while the configured path is unchanged.
+	require.NoError(t, os.Remove(tool))
+	path, err = monitor.resolveToolInternal(ctx, tool, "arcane-missing-gpu-tool", true)
+	require.NoError(t, err)
+	require.Equal(t, tool, path)
+
+	_, err = monitor.resolveToolInternal(ctx, filepath.Join(dir, "moved"), "arcane-missing-gpu-tool", true)
+	require.Error(t, err)
+	require.True(t, monitor.tools["arcane-missing-gpu-tool"].warned)
+}
+
+func TestGPUMonitor_ResolveToolInternal_RetriesMissesAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	tool := filepath.Join(t.TempDir(), "nvidia-smi")
+	monitor := NewGPUMonitor(true, "")
+
+	_, err := monitor.resolveToolInternal(ctx, tool, "arcane-missing-gpu-tool", true)
+	require.Error(t, err)
+
+	// A tool installed after the miss is not seen until the miss expires.
+	require.NoError(t, os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755))
+	_, err = monitor.resolveToolInternal(ctx, tool, "arcane-missing-gpu-tool", true)
+	require.Error(t, err)
+
+	resolution := monitor.tools["arcane-missing-gpu-tool"]
+	resolution.checkedAt = time.Now().Add(-gpuDetectionTTL)
+	monitor.tools["arcane-missing-gpu-tool"] = resolution
+
+	path, err := monitor.resolveToolInternal(ctx, tool, "arcane-missing-gpu-tool", true)
+	require.NoError(t, err)
+	require.Equal(t, tool, path)
+}
+
+func TestGPUMonitor_VendorOverride(t *testing.T) {
+	ctx := context.Background()
+	override := "none"
+	monitor := NewGPUMonitor(true, "nvidia").WithVendorOverride(func(context.Context) string { return override })
+
+	stats, err := monitor.Stats(ctx)
+	require.NoError(t, err)
+	require.Nil(t, stats)
+	require.Equal(t, "none", monitor.DetectedType(ctx))
+
+	override = " AMD "
+	require.Equal(t, "amd", monitor.DetectedType(ctx))
+
+	require.Equal(t, "none", NewGPUMonitor(false, "").DetectedType(ctx))
+}
+
+func TestReadIntelVRAMInfoInternal_ReturnsEveryDiscreteCard(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs := func(rel, value string) {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+	}
+
+	// Integrated Intel GPU without local memory.
+	writeSysfs("card0/device/vendor", "0x8086")
+	// Discrete card on the xe driver.
+	writeSysfs("card1/device/vendor", "0x8086")
+	writeSysfs("card1/device/mem_info_vram_total", "17179869184")
+	writeSysfs("card1/device/mem_info_vram_used", "1073741824")
+	// Discrete card on i915.
+	writeSysfs("card2/device/vendor", "0x8086")
+	writeSysfs("card2/lmem_total_bytes", "8589934592")
+	writeSysfs("card2/lmem_avail_bytes", "6442450944")
+	// Cards are ordered by number, so card10 comes after card2.
+	writeSysfs("card10/device/vendor", "0x8086")
+	writeSysfs("card10/device/mem_info_vram_total", "4294967296")
+	// Non-Intel card and connector entries are ignored.
+	writeSysfs("card3/device/vendor", "0x1002")
+	writeSysfs("card3/device/mem_info_vram_total", "1024")
+	writeSysfs("card1-DP-1/device/vendor", "0x8086")
+
+	cards, err := readIntelVRAMInfoInternal(root)
+	require.NoError(t, err)
+	require.Len(t, cards, 3)
+
+	require.Equal(t, filepath.Join(root, "card1"), cards[0].cardPath)
+	require.Equal(t, uint64(17179869184), cards[0].total)
+	require.Equal(t, uint64(1073741824), cards[0].used)
+
+	require.Equal(t, filepath.Join(root, "card2"), cards[1].cardPath)
+	require.Equal(t, uint64(8589934592), cards[1].total)
+	require.Equal(t, uint64(2147483648), cards[1].used)
+
+	require.Equal(t, filepath.Join(root, "card10"), cards[2].cardPath)
+	require.Equal(t, 10, cards[2].cardIndex)
+}
+
+func TestParseTegrastatsLineInternal(t *testing.T) {
+	gpu := parseTegrastatsLineInternal("RAM 2658/7620MB (lfb 2x4MB) SWAP 0/3810MB (cached 0MB) CPU [2%@1420,1%@1420] EMC_FREQ 0% GR3D_FREQ 45%@[1300] CPU@41C GPU@40.5C")
+
+	require.Equal(t, float64(2658*1024*1024), gpu.MemoryUsed)
+	require.Equal(t, float64(7620*1024*1024), gpu.MemoryTotal)
+	require.NotNil(t, gpu.UtilizationPercent)
+	require.InDelta(t, 45, *gpu.UtilizationPercent, 0.001)
+	require.NotNil(t, gpu.TemperatureC)
+	require.InDelta(t, 40.5, *gpu.TemperatureC, 0.001)
+
+	legacy := parseTegrastatsLineInternal("RAM 1500/3964MB GR3D_FREQ 0% gpu@30C")
+	require.NotNil(t, legacy.UtilizationPercent)
+	require.InDelta(t, 0, *legacy.UtilizationPercent, 0.001)
+	require.NotNil(t, legacy.TemperatureC)
+	require.InDelta(t, 30, *legacy.TemperatureC, 0.001)
+}
@@ -28,6 +28,10 @@ const (
 	// of their own, so this is the Arcane-side backstop that keeps a hung
 	// engine op from holding an activity slot indefinitely.
 	DefaultAutoUpdateApply = 30 * time.Minute
+	// DefaultProjectDeploy bounds a project deploy driven through another
+	// environment's streaming up endpoint, which may pull and build images
+	// before the stack starts.
+	DefaultProjectDeploy = DefaultDockerImagePull + DefaultBuildTimeout
 )
 
 func GetDuration(settingSeconds int, defaultDuration time.Duration) time.Duration {
@@ -0,0 +1,48 @@
+package pagination
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// WriteCSV writes a header row from config.CSVHeaders followed by one row per
+// item, with each of config.SearchAccessors filling one column. An accessor
+// error leaves its cell empty, and cells that a spreadsheet would evaluate as a
+// formula are prefixed with a single quote.
+func WriteCSV[T any](w io.Writer, items []T, config Config[T]) error {
+	if len(config.CSVHeaders) != len(config.SearchAccessors) {
+		return errors.Errorf("csv export has %d headers for %d columns", len(config.CSVHeaders), len(config.SearchAccessors))
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(config.CSVHeaders); err != nil {
+		return errors.WrapIf(err, "write csv header")
+	}
+
+	row := make([]string, len(config.SearchAccessors))
+	for _, item := range items {
+		for i, accessor := range config.SearchAccessors {
+			value, err := accessor(item)
+			if err != nil {
+				value = ""
+			}
+			row[i] = escapeCSVFormula(value)
+		}
+		if err := writer.Write(row); err != nil {
+			return errors.WrapIf(err, "write csv row")
+		}
+	}
+
+	writer.Flush()
+	return errors.WrapIf(writer.Error(), "flush csv")
+}
+
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
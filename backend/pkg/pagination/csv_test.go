@@ -0,0 +1,32 @@
+package pagination
+
+import (
+	"bytes"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSVUsesSearchAccessorsAsColumns(t *testing.T) {
+	type node struct{ hostname, role string }
+	config := Config[node]{
+		SearchAccessors: []SearchAccessor[node]{
+			func(n node) (string, error) { return n.hostname, nil },
+			func(n node) (string, error) {
+				if n.role == "" {
+					return "", errors.New("unknown role")
+				}
+				return n.role, nil
+			},
+		},
+		CSVHeaders: []string{"Hostname", "Role"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, []node{{"manager-1", "manager"}, {"worker, eu", ""}, {"=cmd()", "worker"}}, config))
+	require.Equal(t, "Hostname,Role\nmanager-1,manager\n\"worker, eu\",\n'=cmd(),worker\n", out.String())
+
+	config.CSVHeaders = []string{"Hostname"}
+	require.Error(t, WriteCSV(&out, []node{}, config))
+}
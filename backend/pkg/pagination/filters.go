@@ -15,13 +15,18 @@ type FilterAccessor[T any] struct {
 
 type Config[T any] struct {
 	SearchAccessors []SearchAccessor[T]
+	// CSVHeaders names the SearchAccessors, in order, when the list is
+	// exported with WriteCSV.
+	CSVHeaders      []string
 	SortBindings    []SortBinding[T]
 	FilterAccessors []FilterAccessor[T]
+	RangeBindings   []RangeBinding[T]
 }
 
 func SearchOrderAndPaginate[T any](items []T, params QueryParams, searchConfig Config[T]) FilterResult[T] {
 	totalAvailable := len(items)
 
+	items = rangeFn(items, params.RangeFilters, searchConfig.RangeBindings)
 	items = searchFn(items, params.SearchQuery, searchConfig.SearchAccessors)
 	items = filterFn(items, params.Filters, searchConfig.FilterAccessors)
 	items = sortFunction(items, params.SortParams, searchConfig.SortBindings)
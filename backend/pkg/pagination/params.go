@@ -6,4 +6,6 @@ type QueryParams struct {
 	Params
 
 	Filters map[string]string
+	// RangeFilters are applied against Config.RangeBindings before the search.
+	RangeFilters []RangeFilter
 }
@@ -0,0 +1,233 @@
+package pagination
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+type RangeOperator string
+
+const (
+	RangeEq      RangeOperator = "eq"
+	RangeGt      RangeOperator = "gt"
+	RangeGte     RangeOperator = "gte"
+	RangeLt      RangeOperator = "lt"
+	RangeLte     RangeOperator = "lte"
+	RangeBetween RangeOperator = "between"
+)
+
+// RangeFilter compares a numeric or time field declared by a RangeBinding.
+// Upper is only used by RangeBetween, which is inclusive on both ends.
+type RangeFilter struct {
+	Key   string
+	Op    RangeOperator
+	Value string
+	Upper string
+}
+
+// RangeBinding declares a numeric or time field that RangeFilters can target.
+// Exactly one of Number or Time should be set.
+type RangeBinding[T any] struct {
+	Key    string
+	Number func(T) float64
+	Time   func(T) time.Time
+}
+
+// rangeOperatorPrefixes is ordered so two-character operators match before
+// their one-character prefixes.
+var rangeOperatorPrefixes = []struct {
+	prefix string
+	op     RangeOperator
+}{
+	{">=", RangeGte},
+	{"<=", RangeLte},
+	{">", RangeGt},
+	{"<", RangeLt},
+	{"=", RangeEq},
+}
+
+// ParseRangeFilters parses a comma-separated list of range expressions such as
+// "replicas:>=3,created:>7d" or "size:100..500". A value without an operator
+// is an equality match.
+func ParseRangeFilters(raw string) ([]RangeFilter, error) {
+	var filters []RangeFilter
+	for expr := range strings.SplitSeq(raw, ",") {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(expr, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, errors.Errorf("invalid filter %q: expected key:value", expr)
+		}
+
+		filter := RangeFilter{Key: key, Op: RangeEq, Value: value}
+		if lower, upper, isRange := strings.Cut(value, ".."); isRange {
+			filter.Op = RangeBetween
+			filter.Value, filter.Upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+			if filter.Value == "" || filter.Upper == "" {
+				return nil, errors.Errorf("invalid filter %q: expected key:min..max", expr)
+			}
+		} else {
+			for _, candidate := range rangeOperatorPrefixes {
+				if rest, found := strings.CutPrefix(value, candidate.prefix); found {
+					filter.Op, filter.Value = candidate.op, strings.TrimSpace(rest)
+					break
+				}
+			}
+			if filter.Value == "" {
+				return nil, errors.Errorf("invalid filter %q: missing value", expr)
+			}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// ValidateRangeFilters reports the first filter whose key has no binding or
+// whose value doesn't parse for the bound field, so callers can reject a typo
+// instead of returning an unexpected result.
+func ValidateRangeFilters[T any](filters []RangeFilter, bindings []RangeBinding[T]) error {
+	now := time.Now()
+	for _, filter := range filters {
+		if _, err := rangePredicate(filter, bindings, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rangeFn keeps the items matching every filter. A filter whose key has no
+// binding, or whose value doesn't parse for the bound field, matches nothing.
+func rangeFn[T any](items []T, filters []RangeFilter, bindings []RangeBinding[T]) []T {
+	if len(filters) == 0 {
+		return items
+	}
+
+	now := time.Now()
+	predicates := make([]func(T) bool, 0, len(filters))
+	for _, filter := range filters {
+		predicate, err := rangePredicate(filter, bindings, now)
+		if err != nil {
+			return []T{}
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	results := []T{}
+	for _, item := range items {
+		if matchesAll(item, predicates) {
+			results = append(results, item)
+		}
+	}
+	return results
+}
+
+func matchesAll[T any](item T, predicates []func(T) bool) bool {
+	for _, predicate := range predicates {
+		if !predicate(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func rangePredicate[T any](filter RangeFilter, bindings []RangeBinding[T], now time.Time) (func(T) bool, error) {
+	for _, binding := range bindings {
+		if binding.Key != filter.Key {
+			continue
+		}
+
+		var (
+			value func(T) float64
+			parse func(string) (float64, error)
+		)
+		switch {
+		case binding.Number != nil:
+			value = binding.Number
+			parse = func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
+		case binding.Time != nil:
+			value = func(item T) float64 { return float64(binding.Time(item).Unix()) }
+			parse = func(s string) (float64, error) {
+				t, err := ParseRangeTime(s, now)
+				return float64(t.Unix()), err
+			}
+		default:
+			return nil, errors.Errorf("filter key %q has no comparable field", filter.Key)
+		}
+
+		lower, err := parse(filter.Value)
+		if err != nil {
+			return nil, errors.WrapIff(err, "invalid value for filter %q", filter.Key)
+		}
+		upper := lower
+		if filter.Op == RangeBetween {
+			if upper, err = parse(filter.Upper); err != nil {
+				return nil, errors.WrapIff(err, "invalid value for filter %q", filter.Key)
+			}
+			lower, upper = min(lower, upper), max(lower, upper)
+		}
+
+		return func(item T) bool {
+			return compareRange(filter.Op, value(item), lower, upper)
+		}, nil
+	}
+	return nil, errors.Errorf("unknown filter key %q", filter.Key)
+}
+
+func compareRange(op RangeOperator, v, lower, upper float64) bool {
+	switch op {
+	case RangeEq:
+		return v == lower
+	case RangeGt:
+		return v > lower
+	case RangeGte:
+		return v >= lower
+	case RangeLt:
+		return v < lower
+	case RangeLte:
+		return v <= lower
+	case RangeBetween:
+		return v >= lower && v <= upper
+	default:
+		return false
+	}
+}
+
+// ParseRangeTime accepts RFC 3339 timestamps, dates (2006-01-02), and
+// relative ages such as 30m, 12h, 7d or 2w, which resolve to that long before
+// now. "created:>7d" therefore matches items created within the last 7 days.
+func ParseRangeTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit > 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return time.Time{}, errors.WrapIf(err, "invalid relative time")
+		}
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, errors.WrapIf(err, "invalid time")
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRangeFilters(t *testing.T) {
+	filters, err := ParseRangeFilters("replicas:>=3, created:2024-01-01..2024-06-30,size:42")
+	require.NoError(t, err)
+	require.Equal(t, []RangeFilter{
+		{Key: "replicas", Op: RangeGte, Value: "3"},
+		{Key: "created", Op: RangeBetween, Value: "2024-01-01", Upper: "2024-06-30"},
+		{Key: "size", Op: RangeEq, Value: "42"},
+	}, filters)
+
+	filters, err = ParseRangeFilters("")
+	require.NoError(t, err)
+	require.Empty(t, filters)
+
+	for _, raw := range []string{"replicas", "replicas:", "replicas:>=", "created:..7d"} {
+		_, err := ParseRangeFilters(raw)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestSearchOrderAndPaginateAppliesRangeFiltersBeforeSearch(t *testing.T) {
+	type service struct {
+		name     string
+		replicas int
+		created  time.Time
+	}
+	now := time.Now()
+	items := []service{
+		{name: "web", replicas: 3, created: now.Add(-time.Hour)},
+		{name: "web-canary", replicas: 1, created: now.Add(-time.Hour)},
+		{name: "api", replicas: 5, created: now.Add(-30 * 24 * time.Hour)},
+	}
+	config := Config[service]{
+		SearchAccessors: []SearchAccessor[service]{func(s service) (string, error) { return s.name, nil }},
+		RangeBindings: []RangeBinding[service]{
+			{Key: "replicas", Number: func(s service) float64 { return float64(s.replicas) }},
+			{Key: "created", Time: func(s service) time.Time { return s.created }},
+		},
+	}
+	names := func(result FilterResult[service]) []string {
+		out := make([]string, 0, len(result.Items))
+		for _, item := range result.Items {
+			out = append(out, item.name)
+		}
+		return out
+	}
+
+	filters, err := ParseRangeFilters("replicas:>=3")
+	require.NoError(t, err)
+	result := SearchOrderAndPaginate(items, QueryParams{SearchQuery: SearchQuery{Search: "web"}, RangeFilters: filters}, config)
+	require.Equal(t, []string{"web"}, names(result))
+	require.EqualValues(t, 3, result.TotalAvailable)
+
+	filters, err = ParseRangeFilters("created:>7d")
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "web-canary"}, names(SearchOrderAndPaginate(items, QueryParams{RangeFilters: filters}, config)))
+
+	filters, err = ParseRangeFilters("replicas:5..2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "api"}, names(SearchOrderAndPaginate(items, QueryParams{RangeFilters: filters}, config)))
+
+	filters, err = ParseRangeFilters("cpu:>1")
+	require.NoError(t, err)
+	require.Empty(t, SearchOrderAndPaginate(items, QueryParams{RangeFilters: filters}, config).Items)
+}
+
+func TestValidateRangeFiltersRejectsUnknownKeysAndBadValues(t *testing.T) {
+	bindings := []RangeBinding[int]{
+		{Key: "replicas", Number: func(n int) float64 { return float64(n) }},
+		{Key: "created", Time: func(int) time.Time { return time.Now() }},
+	}
+
+	filters, err := ParseRangeFilters("replicas:>=3,created:>7d")
+	require.NoError(t, err)
+	require.NoError(t, ValidateRangeFilters(filters, bindings))
+
+	filters, err = ParseRangeFilters("replicas:>=3,replcas:>1")
+	require.NoError(t, err)
+	err = ValidateRangeFilters(filters, bindings)
+	require.ErrorContains(t, err, `unknown filter key "replcas"`)
+
+	filters, err = ParseRangeFilters("created:>soon")
+	require.NoError(t, err)
+	require.ErrorContains(t, ValidateRangeFilters(filters, bindings), `invalid value for filter "created"`)
+}
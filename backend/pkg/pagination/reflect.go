@@ -10,27 +10,24 @@ import (
 )
 
 func PaginateAndSortDB(params QueryParams, query *gorm.DB, result any) (Response, error) {
-	sortColumn := params.Sort
-	sortDirection := string(params.Order)
-
-	if sortDirection == "" {
-		sortDirection = "asc"
-	}
-
-	capitalizedSortColumn := stringutils.CapitalizeFirstLetter(sortColumn)
-	sortField, sortFieldFound := reflect.TypeOf(result).Elem().Elem().FieldByName(capitalizedSortColumn)
-	isSortable, _ := strconv.ParseBool(sortField.Tag.Get("sortable"))
-
-	sortDirection = normalizeSortDirection(sortDirection)
+	orderColumns := make([]clause.OrderByColumn, 0, len(params.ThenBy)+1)
+	for _, key := range params.Keys() {
+		capitalizedSortColumn := stringutils.CapitalizeFirstLetter(key.Sort)
+		sortField, sortFieldFound := reflect.TypeOf(result).Elem().Elem().FieldByName(capitalizedSortColumn)
+		isSortable, _ := strconv.ParseBool(sortField.Tag.Get("sortable"))
+		if !sortFieldFound || !isSortable {
+			continue
+		}
 
-	if sortFieldFound && isSortable {
-		columnName := stringutils.CamelCaseToSnakeCase(sortColumn)
-		query = query.Clauses(clause.OrderBy{
-			Columns: []clause.OrderByColumn{
-				{Column: clause.Column{Name: columnName}, Desc: sortDirection == "desc"},
-			},
+		columnName := stringutils.CamelCaseToSnakeCase(key.Sort)
+		orderColumns = append(orderColumns, clause.OrderByColumn{
+			Column: clause.Column{Name: columnName},
+			Desc:   normalizeSortDirection(string(key.Order)) == "desc",
 		})
 	}
+	if len(orderColumns) > 0 {
+		query = query.Clauses(clause.OrderBy{Columns: orderColumns})
+	}
 
 	limit := params.Limit
 	// limit = -1 means "show all" - skip pagination
@@ -1,6 +1,9 @@
 package pagination
 
-import "slices"
+import (
+	"slices"
+	"strings"
+)
 
 type SortOrder string
 
@@ -9,9 +12,53 @@ const (
 	SortDesc SortOrder = "desc"
 )
 
+// SortKey is a single sort column and its direction.
+type SortKey struct {
+	Sort  string
+	Order SortOrder
+}
+
 type SortParams struct {
 	Sort  string
 	Order SortOrder
+	// ThenBy lists tie-breaker keys, applied in order when the items compare
+	// equal on Sort.
+	ThenBy []SortKey
+}
+
+// Keys returns the primary sort key followed by the tie-breakers.
+func (p SortParams) Keys() []SortKey {
+	if p.Sort == "" {
+		return nil
+	}
+	return append([]SortKey{{Sort: p.Sort, Order: p.Order}}, p.ThenBy...)
+}
+
+// ParseSortParams builds SortParams from comma-separated sort columns and
+// directions, e.g. sort "state,name" with order "desc,asc". Columns without a
+// matching direction sort ascending.
+func ParseSortParams(sort, order string) SortParams {
+	var keys []SortKey
+	orders := strings.Split(order, ",")
+	for i, column := range strings.Split(sort, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		key := SortKey{Sort: column}
+		if i < len(orders) {
+			key.Order = SortOrder(strings.TrimSpace(orders[i]))
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return SortParams{Order: SortOrder(strings.TrimSpace(order))}
+	}
+	params := SortParams{Sort: keys[0].Sort, Order: keys[0].Order}
+	if len(keys) > 1 {
+		params.ThenBy = keys[1:]
+	}
+	return params
 }
 
 type (
@@ -24,22 +71,40 @@ type (
 )
 
 func sortFunction[T any](items []T, params SortParams, sorts []SortBinding[T]) []T {
-	for _, sort := range sorts {
-		if sort.Key == params.Sort {
-			fn := sort.Fn
-			if params.Order == SortDesc {
-				if sort.DescFn != nil {
-					fn = sort.DescFn
-				} else {
-					fn = reverSortFn(fn)
-				}
+	var comparators []SortOption[T]
+	for _, key := range params.Keys() {
+		for _, sort := range sorts {
+			if sort.Key == key.Sort {
+				comparators = append(comparators, sortBindingFn(sort, key.Order))
+				break
 			}
-			slices.SortStableFunc(items, fn)
 		}
 	}
+	if len(comparators) == 0 {
+		return items
+	}
+
+	slices.SortStableFunc(items, func(a, b T) int {
+		for _, fn := range comparators {
+			if c := fn(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
 	return items
 }
 
+func sortBindingFn[T any](sort SortBinding[T], order SortOrder) SortOption[T] {
+	if order != SortDesc {
+		return sort.Fn
+	}
+	if sort.DescFn != nil {
+		return sort.DescFn
+	}
+	return reverSortFn(sort.Fn)
+}
+
 func reverSortFn[T any](fn SortOption[T]) SortOption[T] {
 	return func(a, b T) int {
 		return -1 * fn(a, b)
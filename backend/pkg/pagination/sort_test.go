@@ -1,6 +1,7 @@
 package pagination
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -45,3 +46,28 @@ func TestSortFunctionUsesCustomDescendingComparatorWhenProvided(t *testing.T) {
 
 	require.Equal(t, []int{2, 1, 0}, sorted)
 }
+
+func TestSortFunctionAppliesTieBreakersInOrder(t *testing.T) {
+	type service struct {
+		name     string
+		replicas int
+	}
+	items := []service{{"web", 2}, {"api", 3}, {"db", 2}, {"cache", 3}}
+
+	sorted := sortFunction(items, ParseSortParams("replicas, name", "desc"), []SortBinding[service]{
+		{Key: "name", Fn: func(a, b service) int { return strings.Compare(a.name, b.name) }},
+		{Key: "replicas", Fn: func(a, b service) int { return a.replicas - b.replicas }},
+	})
+
+	require.Equal(t, []service{{"api", 3}, {"cache", 3}, {"db", 2}, {"web", 2}}, sorted)
+}
+
+func TestParseSortParams(t *testing.T) {
+	require.Equal(t, SortParams{Sort: "name", Order: SortDesc}, ParseSortParams("name", "desc"))
+	require.Equal(t, SortParams{
+		Sort:   "state",
+		Order:  SortDesc,
+		ThenBy: []SortKey{{Sort: "name", Order: SortAsc}, {Sort: "created"}},
+	}, ParseSortParams("state,name,created", "desc,asc"))
+	require.Equal(t, SortParams{Order: SortAsc}, ParseSortParams(" ", "asc"))
+}
@@ -68,7 +68,7 @@ func ComposeUp(ctx context.Context, proj *types.Project, services []string, remo
 	composeCtx, cancel := detachFromHTTPContextInternal(ctx)
 	defer cancel()
 
-	c, err := NewClient(composeCtx, authConfigs)
+	c, err := NewClient(withDeployEventProcessorInternal(composeCtx, proj), authConfigs)
 	if err != nil {
 		return err
 	}
@@ -76,6 +76,16 @@ func ComposeUp(ctx context.Context, proj *types.Project, services []string, remo
 
 	upOptions, startOptions := composeUpOptions(proj, services, removeOrphans, forceRecreate)
 
+	waves, err := composeStartupWavesInternal(proj, services)
+	if err != nil {
+		return err
+	}
+	// Health-gated dependencies get an explicit, bounded wait per gate so the
+	// deploy can report what it is waiting on and fail instead of hanging.
+	if gates := composeHealthGatesInternal(proj, waves); len(gates) > 0 {
+		return c.upHealthGatedInternal(composeCtx, proj, waves, gates, upOptions, startOptions)
+	}
+
 	return c.svc.Up(composeCtx, proj, api.UpOptions{Create: upOptions, Start: startOptions})
 }
 
@@ -23,6 +23,9 @@ type Client struct {
 	svc       api.Compose
 	dockerCli command.Cli
 	logWriter io.WriteCloser
+	// events receives progress arcane reports itself, such as health gates,
+	// through the same processors compose reports to.
+	events api.EventProcessor
 }
 
 func NewClient(ctx context.Context, authConfigs map[string]registry.AuthConfig) (*Client, error) {
@@ -51,14 +54,27 @@ func NewClient(ctx context.Context, authConfigs map[string]registry.AuthConfig)
 	// When the caller streams operation output, render compose's own progress
 	// events exactly as `docker compose --progress=plain` prints them.
 	var logWriter io.WriteCloser
+	var eventProcessors composeEventFanout
 	if progressWriter, ok := ctx.Value(dockerutils.ProgressWriterKey{}).(io.Writer); ok && progressWriter != nil {
 		logWriter = dockerutils.NewLogLineWriter(progressWriter)
+		eventProcessors = append(eventProcessors, display.Plain(logWriter))
 		serviceOptions = append(serviceOptions,
-			compose.WithEventProcessor(display.Plain(logWriter)),
 			compose.WithOutputStream(logWriter),
 			compose.WithErrorStream(logWriter),
 		)
 	}
+	// Structured deploy progress (see WithDeployObserver) rides alongside the
+	// plain renderer.
+	if processor, ok := ctx.Value(composeEventProcessorKey{}).(api.EventProcessor); ok && processor != nil {
+		eventProcessors = append(eventProcessors, processor)
+	}
+	switch len(eventProcessors) {
+	case 0:
+	case 1:
+		serviceOptions = append(serviceOptions, compose.WithEventProcessor(eventProcessors[0]))
+	default:
+		serviceOptions = append(serviceOptions, compose.WithEventProcessor(eventProcessors))
+	}
 
 	svc, err := compose.NewComposeService(composeCLI, serviceOptions...)
 	if err != nil {
@@ -68,7 +84,7 @@ func NewClient(ctx context.Context, authConfigs map[string]registry.AuthConfig)
 		return nil, err
 	}
 
-	return &Client{svc: svc, dockerCli: composeCLI, logWriter: logWriter}, nil
+	return &Client{svc: svc, dockerCli: composeCLI, logWriter: logWriter, events: eventProcessors}, nil
 }
 
 func buildComposeAuthConfigsInternal(authConfigs map[string]registry.AuthConfig) map[string]clitypes.AuthConfig {
@@ -0,0 +1,179 @@
+package projects
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+
+	projecttypes "github.com/getarcaneapp/arcane/types/v2/project"
+)
+
+// DeployObserver receives structured progress while a project deploys. Attach
+// one to the deploy context with WithDeployObserver; the deploy reports each
+// stage as it starts and ComposeUp forwards compose's resource events to it
+// alongside the plain progress writer.
+type DeployObserver interface {
+	DeployStage(stage projecttypes.DeployProgressStage)
+	DeployResource(event projecttypes.DeployProgressEvent)
+}
+
+type deployObserverKey struct{}
+
+// composeEventProcessorKey carries an extra compose event processor from
+// ComposeUp to NewClient.
+type composeEventProcessorKey struct{}
+
+// WithDeployObserver returns a copy of ctx that reports deploy progress to observer.
+func WithDeployObserver(ctx context.Context, observer DeployObserver) context.Context {
+	return context.WithValue(ctx, deployObserverKey{}, observer)
+}
+
+// ReportDeployStage tells the observer attached to ctx, if any, that a deploy stage started.
+func ReportDeployStage(ctx context.Context, stage projecttypes.DeployProgressStage) {
+	if observer, ok := ctx.Value(deployObserverKey{}).(DeployObserver); ok && observer != nil {
+		observer.DeployStage(stage)
+	}
+}
+
+// withDeployEventProcessorInternal attaches a compose event processor that feeds
+// proj's resource events to the observer in ctx. ctx is returned unchanged when
+// no observer is attached.
+func withDeployEventProcessorInternal(ctx context.Context, proj *types.Project) context.Context {
+	observer, ok := ctx.Value(deployObserverKey{}).(DeployObserver)
+	if !ok || observer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, composeEventProcessorKey{}, &deployEventProcessor{
+		observer: observer,
+		service:  newDeployServiceResolverInternal(proj),
+	})
+}
+
+// deployEventProcessor adapts compose's event stream to a DeployObserver.
+type deployEventProcessor struct {
+	observer DeployObserver
+	service  func(resourceID string) string
+}
+
+func (p *deployEventProcessor) Start(context.Context, string) {}
+
+func (p *deployEventProcessor) Done(string, bool) {}
+
+func (p *deployEventProcessor) On(events ...api.Resource) {
+	for _, event := range events {
+		// Child events are per-layer pull progress; the plain stream already
+		// prints them and the parent event carries the resource's state.
+		if event.ParentID != "" {
+			continue
+		}
+		p.observer.DeployResource(projecttypes.DeployProgressEvent{
+			Stage:          projecttypes.DeployProgressStageUp,
+			Status:         projecttypes.DeployProgressStatusRunning,
+			Resource:       event.ID,
+			Service:        p.service(event.ID),
+			ResourceStatus: deployResourceStatusInternal(event.Status),
+			Text:           event.Text,
+			Details:        event.Details,
+			Percent:        event.Percent,
+		})
+	}
+}
+
+func deployResourceStatusInternal(status api.EventStatus) projecttypes.DeployResourceStatus {
+	switch status {
+	case api.Done:
+		return projecttypes.DeployResourceStatusDone
+	case api.Warning:
+		return projecttypes.DeployResourceStatusWarning
+	case api.Error:
+		return projecttypes.DeployResourceStatusError
+	default:
+		return projecttypes.DeployResourceStatusWorking
+	}
+}
+
+// composeEventFanout forwards compose events to several processors.
+type composeEventFanout []api.EventProcessor
+
+func (f composeEventFanout) Start(ctx context.Context, operation string) {
+	for _, p := range f {
+		p.Start(ctx, operation)
+	}
+}
+
+func (f composeEventFanout) On(events ...api.Resource) {
+	for _, p := range f {
+		p.On(events...)
+	}
+}
+
+func (f composeEventFanout) Done(operation string, success bool) {
+	for _, p := range f {
+		p.Done(operation, success)
+	}
+}
+
+// newDeployServiceResolverInternal returns a func that maps a compose resource
+// ID ("Container shop-web-1", "Image nginx:1.27" or a bare service name) to the
+// project service it belongs to, or "" for project-wide resources such as
+// networks and volumes.
+func newDeployServiceResolverInternal(proj *types.Project) func(resourceID string) string {
+	containerNames := make(map[string]string)
+	images := make(map[string]string)
+	for name, svc := range proj.Services {
+		if svc.ContainerName != "" {
+			containerNames[svc.ContainerName] = name
+		}
+		if svc.Image == "" {
+			continue
+		}
+		// An image shared by several services cannot be attributed to one.
+		if _, shared := images[svc.Image]; shared {
+			images[svc.Image] = ""
+		} else {
+			images[svc.Image] = name
+		}
+	}
+
+	return func(resourceID string) string {
+		kind, name, found := strings.Cut(resourceID, " ")
+		if !found {
+			kind, name = "", resourceID
+		}
+		switch kind {
+		case "Container":
+			if service, ok := containerNames[name]; ok {
+				return service
+			}
+			return composeContainerServiceInternal(proj, name)
+		case "Image":
+			return images[name]
+		case "":
+			if _, ok := proj.Services[name]; ok {
+				return name
+			}
+		}
+		return ""
+	}
+}
+
+// composeContainerServiceInternal recovers the service from a compose-generated
+// container name of the form <project>-<service>-<replica>.
+func composeContainerServiceInternal(proj *types.Project, containerName string) string {
+	name, ok := strings.CutPrefix(containerName, proj.Name+api.Separator)
+	if !ok {
+		return ""
+	}
+	if idx := strings.LastIndex(name, api.Separator); idx > 0 {
+		if _, err := strconv.Atoi(name[idx+1:]); err == nil {
+			name = name[:idx]
+		}
+	}
+	if _, ok := proj.Services[name]; ok {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package projects
+
+import (
+	"context"
+	"testing"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/stretchr/testify/require"
+
+	projecttypes "github.com/getarcaneapp/arcane/types/v2/project"
+)
+
+type recordingDeployObserver struct {
+	stages []projecttypes.DeployProgressStage
+	events []projecttypes.DeployProgressEvent
+}
+
+func (o *recordingDeployObserver) DeployStage(stage projecttypes.DeployProgressStage) {
+	o.stages = append(o.stages, stage)
+}
+
+func (o *recordingDeployObserver) DeployResource(event projecttypes.DeployProgressEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestNewDeployServiceResolverInternal(t *testing.T) {
+	proj := &composetypes.Project{
+		Name: "shop",
+		Services: composetypes.Services{
+			"web":       {Name: "web", Image: "nginx:1.27"},
+			"api-proxy": {Name: "api-proxy", Image: "traefik:v3"},
+			"db":        {Name: "db", Image: "postgres:17", ContainerName: "shop-database"},
+			"worker":    {Name: "worker", Image: "shop/app:latest"},
+			"cron":      {Name: "cron", Image: "shop/app:latest"},
+		},
+	}
+	resolve := newDeployServiceResolverInternal(proj)
+
+	require.Equal(t, "web", resolve("Container shop-web-1"))
+	require.Equal(t, "api-proxy", resolve("Container shop-api-proxy-2"))
+	require.Equal(t, "db", resolve("Container shop-database"))
+	require.Equal(t, "web", resolve("Image nginx:1.27"))
+	require.Equal(t, "web", resolve("web"))
+	require.Empty(t, resolve("Image shop/app:latest"), "shared image is not attributed")
+	require.Empty(t, resolve("Network shop_default"))
+	require.Empty(t, resolve("Container other-web-1"))
+}
+
+func TestDeployEventProcessorForwardsTopLevelEvents(t *testing.T) {
+	proj := &composetypes.Project{
+		Name:     "shop",
+		Services: composetypes.Services{"web": {Name: "web", Image: "nginx:1.27"}},
+	}
+	observer := &recordingDeployObserver{}
+	ctx := withDeployEventProcessorInternal(WithDeployObserver(context.Background(), observer), proj)
+
+	processor, ok := ctx.Value(composeEventProcessorKey{}).(api.EventProcessor)
+	require.True(t, ok)
+
+	processor.On(
+		api.Resource{ID: "Container shop-web-1", Status: api.Error, Text: "Error", Details: "port is already allocated"},
+		api.Resource{ID: "3f4e1a", ParentID: "Image nginx:1.27", Status: api.Working, Text: "Downloading"},
+	)
+
+	require.Len(t, observer.events, 1)
+	require.Equal(t, "web", observer.events[0].Service)
+	require.Equal(t, projecttypes.DeployResourceStatusError, observer.events[0].ResourceStatus)
+	require.Equal(t, "port is already allocated", observer.events[0].Details)
+
+	ReportDeployStage(ctx, projecttypes.DeployProgressStageUp)
+	require.Equal(t, []projecttypes.DeployProgressStage{projecttypes.DeployProgressStageUp}, observer.stages)
+}
+
+func TestWithDeployEventProcessorInternalWithoutObserver(t *testing.T) {
+	ctx := withDeployEventProcessorInternal(context.Background(), &composetypes.Project{Name: "shop"})
+	require.Nil(t, ctx.Value(composeEventProcessorKey{}))
+
+	// Reporting a stage without an observer is a no-op.
+	ReportDeployStage(ctx, projecttypes.DeployProgressStageImages)
+}
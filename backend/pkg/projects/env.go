@@ -554,6 +554,12 @@ func readOptionalProjectFileInternal(projectPath, fileName string) (content stri
 	return "", false, false, errors.WrapIff(readErr, "read %s", fileName)
 }
 
+// FormatEnvContent serializes envMap into Arcane's canonical generated .env
+// format, quoting and escaping values as needed.
+func FormatEnvContent(envMap EnvMap) string {
+	return formatEnvMapInternal(envMap)
+}
+
 // formatEnvMapInternal serializes env maps into Arcane's canonical generated
 // format. This is intentionally lossy: comments are omitted and keys are sorted
 // alphabetically to keep persisted merge output stable.
@@ -0,0 +1,291 @@
+package projects
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+
+	"github.com/getarcaneapp/arcane/backend/v2/pkg/libarcane"
+)
+
+// Docker's healthcheck defaults, used for the parts of a dependency's
+// healthcheck the compose file leaves unset.
+const (
+	composeHealthcheckDefaultInterval = 30 * time.Second
+	composeHealthcheckDefaultRetries  = 3
+)
+
+// composeDependencyHealthTimeoutCap bounds how long a deploy waits for a single
+// service_healthy dependency, however generous its healthcheck. A dependency
+// that needs longer than this usually has a broken healthcheck.
+const composeDependencyHealthTimeoutCap = 10 * time.Minute
+
+// composeDependencyPollInterval is how often a health gate re-checks its dependency.
+const composeDependencyPollInterval = time.Second
+
+// composeHealthGate is a depends_on edge with condition service_healthy:
+// Service must not start until Dependency reports healthy.
+type composeHealthGate struct {
+	Service    string
+	Dependency string
+}
+
+// serviceHealthFunc reports the aggregate health of a service's containers.
+type serviceHealthFunc func(ctx context.Context, service string) (container.HealthStatus, error)
+
+// composeStartupWavesInternal groups the services to deploy into start waves
+// ordered by depends_on, so every service's dependencies sit in an earlier
+// wave. With names empty every service in proj is included; otherwise only
+// names and their transitive dependencies are. Services within a wave are
+// sorted by name.
+//
+// Optional dependencies missing from the project are ignored. Returns an error
+// for a required dependency that is not in the project or a dependency cycle.
+func composeStartupWavesInternal(proj *types.Project, names []string) ([][]string, error) {
+	if len(names) == 0 {
+		names = slices.Sorted(maps.Keys(proj.Services))
+	}
+
+	selected := make(map[string]struct{}, len(proj.Services))
+	pending := slices.Clone(names)
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if _, seen := selected[name]; seen {
+			continue
+		}
+		svc, ok := proj.Services[name]
+		if !ok {
+			return nil, errors.Errorf("service %s is not defined in the project", name)
+		}
+		selected[name] = struct{}{}
+		for dep, cfg := range svc.DependsOn {
+			if _, ok := proj.Services[dep]; !ok {
+				if cfg.Required {
+					return nil, errors.Errorf("service %s depends on undefined service %s", name, dep)
+				}
+				continue
+			}
+			pending = append(pending, dep)
+		}
+	}
+
+	remaining := make(map[string]int, len(selected))
+	dependents := make(map[string][]string, len(selected))
+	for name := range selected {
+		remaining[name] = 0
+		for dep := range proj.Services[name].DependsOn {
+			if _, ok := selected[dep]; !ok {
+				continue
+			}
+			remaining[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var waves [][]string
+	for len(remaining) > 0 {
+		var wave []string
+		for name, count := range remaining {
+			if count == 0 {
+				wave = append(wave, name)
+			}
+		}
+		if len(wave) == 0 {
+			return nil, errors.Errorf("dependency cycle between services %s", strings.Join(slices.Sorted(maps.Keys(remaining)), ", "))
+		}
+		slices.Sort(wave)
+		for _, name := range wave {
+			delete(remaining, name)
+			for _, dependent := range dependents[name] {
+				remaining[dependent]--
+			}
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// composeHealthGatesInternal returns the required service_healthy gates between
+// the services in waves, keyed by the gated service and sorted by dependency.
+func composeHealthGatesInternal(proj *types.Project, waves [][]string) map[string][]composeHealthGate {
+	gates := make(map[string][]composeHealthGate)
+	for _, wave := range waves {
+		for _, name := range wave {
+			for _, dep := range slices.Sorted(maps.Keys(proj.Services[name].DependsOn)) {
+				cfg := proj.Services[name].DependsOn[dep]
+				if cfg.Condition != types.ServiceConditionHealthy || !cfg.Required {
+					continue
+				}
+				if _, ok := proj.Services[dep]; !ok {
+					continue
+				}
+				gates[name] = append(gates[name], composeHealthGate{Service: name, Dependency: dep})
+			}
+		}
+	}
+	return gates
+}
+
+// composeDependencyHealthTimeoutInternal returns how long to wait for svc to
+// become healthy: its start period plus one interval per allowed retry and one
+// more for the first check, as Docker would take to mark it unhealthy. Unset
+// values use Docker's defaults, and the result is capped at
+// composeDependencyHealthTimeoutCap.
+func composeDependencyHealthTimeoutInternal(svc types.ServiceConfig) time.Duration {
+	interval := composeHealthcheckDefaultInterval
+	retries := uint64(composeHealthcheckDefaultRetries)
+	var startPeriod time.Duration
+	if hc := svc.HealthCheck; hc != nil {
+		if hc.Interval != nil && *hc.Interval > 0 {
+			interval = time.Duration(*hc.Interval)
+		}
+		if hc.Retries != nil && *hc.Retries > 0 {
+			retries = *hc.Retries
+		}
+		if hc.StartPeriod != nil && *hc.StartPeriod > 0 {
+			startPeriod = time.Duration(*hc.StartPeriod)
+		}
+	}
+
+	if retries+1 > uint64(composeDependencyHealthTimeoutCap/interval) {
+		return composeDependencyHealthTimeoutCap
+	}
+	return min(startPeriod+interval*time.Duration(retries+1), composeDependencyHealthTimeoutCap)
+}
+
+// upHealthGatedInternal brings proj up one start wave at a time. Before a wave
+// starts, each of its services waits for its service_healthy dependencies,
+// reporting the gate through the client's event processors; a dependency that
+// turns unhealthy, stops, or is still not healthy after the timeout its
+// healthcheck implies (see composeDependencyHealthTimeoutInternal) fails the
+// deploy. The final start pass applies
+// startOptions' own wait across every service.
+func (c *Client) upHealthGatedInternal(ctx context.Context, proj *types.Project, waves [][]string, gates map[string][]composeHealthGate, createOptions api.CreateOptions, startOptions api.StartOptions) error {
+	if err := c.svc.Create(ctx, proj, createOptions); err != nil {
+		return err
+	}
+
+	health := func(ctx context.Context, service string) (container.HealthStatus, error) {
+		return c.serviceHealthInternal(ctx, proj.Name, service)
+	}
+	for _, wave := range waves {
+		for _, name := range wave {
+			for _, gate := range gates[name] {
+				timeout := composeDependencyHealthTimeoutInternal(proj.Services[gate.Dependency])
+				if err := waitForHealthGateInternal(ctx, gate, health, c.events, timeout, composeDependencyPollInterval); err != nil {
+					return err
+				}
+			}
+		}
+
+		waveOptions := startOptions
+		waveOptions.Services = wave
+		waveOptions.Wait = false
+		if err := c.svc.Start(ctx, proj.Name, waveOptions); err != nil {
+			return err
+		}
+	}
+
+	return c.svc.Start(ctx, proj.Name, startOptions)
+}
+
+// waitForHealthGateInternal polls health until gate's dependency is healthy.
+// It fails when the dependency reports unhealthy, when health returns an
+// error, or once timeout elapses, reporting the last health status seen.
+// Progress is sent to events as resource events on the gated service.
+func waitForHealthGateInternal(ctx context.Context, gate composeHealthGate, health serviceHealthFunc, events api.EventProcessor, timeout, interval time.Duration) error {
+	report := func(status api.EventStatus, text, details string) {
+		if events != nil {
+			events.On(api.Resource{ID: gate.Service, Status: status, Text: text, Details: details})
+		}
+	}
+
+	report(api.Working, "Waiting", "waiting for "+gate.Dependency+" to be healthy")
+
+	gateCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastStatus := "unknown"
+	for {
+		status, err := health(gateCtx, gate.Dependency)
+		if err == nil && status != "" {
+			lastStatus = string(status)
+		}
+		switch {
+		case err != nil && gateCtx.Err() == nil:
+			report(api.Error, "Error", err.Error())
+			return errors.WrapIff(err, "dependency %s of service %s", gate.Dependency, gate.Service)
+		case err == nil && status == container.Healthy:
+			report(api.Working, "Waiting", gate.Dependency+" is healthy")
+			return nil
+		case err == nil && status == container.Unhealthy:
+			report(api.Error, "Error", gate.Dependency+" is unhealthy")
+			return errors.Errorf("dependency %s of service %s is unhealthy", gate.Dependency, gate.Service)
+		}
+
+		select {
+		case <-gateCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			report(api.Error, "Error", gate.Dependency+" did not become healthy in time (last status: "+lastStatus+")")
+			return errors.Errorf("dependency %s of service %s did not become healthy within %s (last status: %s)", gate.Dependency, gate.Service, timeout, lastStatus)
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceHealthInternal inspects every container of service in projectName and
+// returns Healthy only when all of them are. A container that has stopped or
+// has no healthcheck is reported as an error, since it can never pass the gate.
+func (c *Client) serviceHealthInternal(ctx context.Context, projectName, service string) (container.HealthStatus, error) {
+	apiClient := c.dockerCli.Client()
+	filter := make(client.Filters).
+		Add("label", api.ProjectLabel+"="+projectName).
+		Add("label", api.ServiceLabel+"="+service).
+		Add("label", api.OneoffLabel+"=False")
+	list, err := apiClient.ContainerList(ctx, client.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to list containers")
+	}
+	if len(list.Items) == 0 {
+		return container.Starting, nil
+	}
+
+	aggregate := container.Healthy
+	for _, summary := range list.Items {
+		inspect, err := libarcane.ContainerInspectWithCompatibility(ctx, apiClient, summary.ID, client.ContainerInspectOptions{})
+		if err != nil {
+			return "", errors.WrapIf(err, "failed to inspect container")
+		}
+		state := inspect.Container.State
+		if state == nil {
+			return container.Starting, nil
+		}
+		if state.Status == container.StateExited || state.Status == container.StateDead {
+			return "", errors.Errorf("container %s exited", strings.TrimPrefix(inspect.Container.Name, "/"))
+		}
+		if state.Health == nil {
+			return "", errors.Errorf("container %s has no healthcheck configured", strings.TrimPrefix(inspect.Container.Name, "/"))
+		}
+		switch state.Health.Status {
+		case container.Unhealthy:
+			return container.Unhealthy, nil
+		case container.Healthy:
+		default:
+			aggregate = container.Starting
+		}
+	}
+	return aggregate, nil
+}
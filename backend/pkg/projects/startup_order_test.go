@@ -0,0 +1,155 @@
+package projects
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEventProcessor struct {
+	events []api.Resource
+}
+
+func (p *recordingEventProcessor) Start(context.Context, string) {}
+
+func (p *recordingEventProcessor) Done(string, bool) {}
+
+func (p *recordingEventProcessor) On(events ...api.Resource) {
+	p.events = append(p.events, events...)
+}
+
+func newStartupOrderTestProject() *composetypes.Project {
+	return &composetypes.Project{
+		Name: "shop",
+		Services: composetypes.Services{
+			"db":    {Name: "db"},
+			"cache": {Name: "cache"},
+			"api": {Name: "api", DependsOn: composetypes.DependsOnConfig{
+				"db":    {Condition: composetypes.ServiceConditionHealthy, Required: true},
+				"cache": {Condition: composetypes.ServiceConditionStarted, Required: true},
+			}},
+			"web": {Name: "web", DependsOn: composetypes.DependsOnConfig{
+				"api":     {Condition: composetypes.ServiceConditionHealthy, Required: true},
+				"metrics": {Condition: composetypes.ServiceConditionStarted, Required: false},
+			}},
+		},
+	}
+}
+
+func TestComposeStartupWavesInternal(t *testing.T) {
+	proj := newStartupOrderTestProject()
+
+	waves, err := composeStartupWavesInternal(proj, nil)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"cache", "db"}, {"api"}, {"web"}}, waves)
+
+	waves, err = composeStartupWavesInternal(proj, []string{"api"})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"cache", "db"}, {"api"}}, waves, "selected services pull in their dependencies")
+
+	waves, err = composeStartupWavesInternal(proj, []string{"cache"})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"cache"}}, waves)
+}
+
+func TestComposeStartupWavesInternalRejectsInvalidGraphs(t *testing.T) {
+	proj := newStartupOrderTestProject()
+	proj.Services["db"] = composetypes.ServiceConfig{Name: "db", DependsOn: composetypes.DependsOnConfig{
+		"web": {Condition: composetypes.ServiceConditionStarted, Required: true},
+	}}
+	_, err := composeStartupWavesInternal(proj, nil)
+	require.ErrorContains(t, err, "dependency cycle between services api, db, web")
+
+	proj = newStartupOrderTestProject()
+	proj.Services["cache"] = composetypes.ServiceConfig{Name: "cache", DependsOn: composetypes.DependsOnConfig{
+		"queue": {Condition: composetypes.ServiceConditionStarted, Required: true},
+	}}
+	_, err = composeStartupWavesInternal(proj, nil)
+	require.ErrorContains(t, err, "service cache depends on undefined service queue")
+}
+
+func TestComposeHealthGatesInternal(t *testing.T) {
+	proj := newStartupOrderTestProject()
+	waves, err := composeStartupWavesInternal(proj, nil)
+	require.NoError(t, err)
+
+	gates := composeHealthGatesInternal(proj, waves)
+	require.Equal(t, map[string][]composeHealthGate{
+		"api": {{Service: "api", Dependency: "db"}},
+		"web": {{Service: "web", Dependency: "api"}},
+	}, gates)
+
+	require.Empty(t, composeHealthGatesInternal(proj, [][]string{{"cache", "db"}}))
+}
+
+func TestWaitForHealthGateInternal(t *testing.T) {
+	gate := composeHealthGate{Service: "api", Dependency: "db"}
+
+	t.Run("waits until the dependency is healthy", func(t *testing.T) {
+		statuses := []container.HealthStatus{container.Starting, container.Starting, container.Healthy}
+		calls := 0
+		health := func(context.Context, string) (container.HealthStatus, error) {
+			status := statuses[calls]
+			calls++
+			return status, nil
+		}
+		events := &recordingEventProcessor{}
+
+		err := waitForHealthGateInternal(context.Background(), gate, health, events, time.Second, time.Millisecond)
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+		require.Len(t, events.events, 2)
+		require.Equal(t, "api", events.events[0].ID)
+		require.Equal(t, "waiting for db to be healthy", events.events[0].Details)
+		require.Equal(t, "db is healthy", events.events[1].Details)
+	})
+
+	t.Run("fails when the dependency is unhealthy", func(t *testing.T) {
+		health := func(context.Context, string) (container.HealthStatus, error) {
+			return container.Unhealthy, nil
+		}
+		events := &recordingEventProcessor{}
+
+		err := waitForHealthGateInternal(context.Background(), gate, health, events, time.Second, time.Millisecond)
+		require.ErrorContains(t, err, "dependency db of service api is unhealthy")
+		require.Equal(t, api.Error, events.events[len(events.events)-1].Status)
+	})
+
+	t.Run("fails after the timeout", func(t *testing.T) {
+		health := func(context.Context, string) (container.HealthStatus, error) {
+			return container.Starting, nil
+		}
+
+		err := waitForHealthGateInternal(context.Background(), gate, health, nil, 20*time.Millisecond, time.Millisecond)
+		require.ErrorContains(t, err, "dependency db of service api did not become healthy within 20ms (last status: starting)")
+	})
+}
+
+func TestComposeDependencyHealthTimeoutInternal(t *testing.T) {
+	duration := func(d time.Duration) *composetypes.Duration {
+		return new(composetypes.Duration(d))
+	}
+
+	// Docker's defaults: 30s interval, 3 retries, no start period.
+	require.Equal(t, 2*time.Minute, composeDependencyHealthTimeoutInternal(composetypes.ServiceConfig{}))
+
+	require.Equal(t, 75*time.Second, composeDependencyHealthTimeoutInternal(composetypes.ServiceConfig{
+		HealthCheck: &composetypes.HealthCheckConfig{
+			Interval:    duration(5 * time.Second),
+			Retries:     new(uint64(10)),
+			StartPeriod: duration(20 * time.Second),
+		},
+	}))
+
+	require.Equal(t, composeDependencyHealthTimeoutCap, composeDependencyHealthTimeoutInternal(composetypes.ServiceConfig{
+		HealthCheck: &composetypes.HealthCheckConfig{
+			Interval: duration(time.Minute),
+			Retries:  new(uint64(1 << 40)),
+		},
+	}))
+}
@@ -58,6 +58,11 @@ func (j *AutoUpdateJob) Run(ctx context.Context) {
 		return
 	}
 
+	if err := j.settingsService.CheckMaintenance(ctx, false); err != nil {
+		slog.InfoContext(ctx, "auto-update run skipped; environment is in maintenance mode", "reason", err)
+		return
+	}
+
 	if !j.running.CompareAndSwap(false, true) {
 		slog.WarnContext(ctx, "auto-update run still in progress; skipping overlapping run")
 		return
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/getarcaneapp/arcane/types/v2/environment"
 	"github.com/getarcaneapp/arcane/types/v2/updater"
 	"github.com/stretchr/testify/require"
 )
@@ -76,3 +77,26 @@ func TestAutoUpdateJob_OverlappingRunIsSkippedInternal(t *testing.T) {
 	job.Run(ctx)
 	require.Equal(t, int32(2), applier.calls.Load())
 }
+
+func TestAutoUpdateJob_SkipsRunInMaintenanceInternal(t *testing.T) {
+	ctx := context.Background()
+	_, settingsSvc, _ := setupAnalyticsStateServicesInternal(t)
+	require.NoError(t, settingsSvc.SetBoolSetting(ctx, "autoUpdate", true))
+	_, err := settingsSvc.SetMaintenance(ctx, environment.MaintenanceUpdate{Enabled: true, Reason: "disk swap"})
+	require.NoError(t, err)
+
+	applier := &blockingApplierFakeInternal{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	close(applier.release)
+	job := &AutoUpdateJob{updaterService: applier, settingsService: settingsSvc}
+
+	job.Run(ctx)
+	require.Equal(t, int32(0), applier.calls.Load())
+
+	_, err = settingsSvc.SetMaintenance(ctx, environment.MaintenanceUpdate{Enabled: false})
+	require.NoError(t, err)
+	job.Run(ctx)
+	require.Equal(t, int32(1), applier.calls.Load())
+}
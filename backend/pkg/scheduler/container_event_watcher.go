@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
+	"github.com/moby/moby/api/types/events"
+	"go.getarcane.app/streams/bus"
+)
+
+const (
+	// containerEventStopGrace is how long after a kill event a die is treated
+	// as a requested stop rather than a crash.
+	containerEventStopGrace = 30 * time.Second
+	// containerEventRestartLoopThreshold is how many crashes within
+	// containerEventRestartLoopWindow are reported as a restart loop.
+	containerEventRestartLoopThreshold = 3
+	containerEventRestartLoopWindow    = 5 * time.Minute
+)
+
+// containerEventStopSignals are the kill signals that mark a requested stop:
+// SIGKILL and SIGTERM, plus SIGINT and SIGQUIT, the usual configured stop
+// signals. Reload signals such as SIGHUP and SIGUSR1 do not stop a container,
+// so a crash after one is still reported. Docker reports the signal number.
+var containerEventStopSignals = map[string]struct{}{
+	"2": {}, "3": {}, "9": {}, "15": {},
+	"SIGINT": {}, "SIGQUIT": {}, "SIGKILL": {}, "SIGTERM": {},
+}
+
+type containerEventNotifierInternal interface {
+	SendContainerEventNotification(ctx context.Context, eventType models.NotificationEventType, containerName, containerID, detail string) error
+}
+
+type containerEventNotificationInternal struct {
+	eventType     models.NotificationEventType
+	containerName string
+	containerID   string
+	detail        string
+}
+
+// ContainerEventWatcher turns Docker container events into lifecycle
+// notifications: unexpected exits, OOM kills, failing health checks, and
+// containers that keep crashing.
+type ContainerEventWatcher struct {
+	notificationService containerEventNotifierInternal
+	dockerService       dockerEventBusProviderInternal
+	now                 func() time.Time
+
+	// Per-container state, only touched by the Start listener goroutine.
+	stoppedAt        map[string]time.Time
+	oomAt            map[string]time.Time
+	crashes          map[string][]time.Time
+	restartLoopUntil map[string]time.Time
+}
+
+// NewContainerEventWatcher constructs the container event watcher from the existing services.
+func NewContainerEventWatcher(notificationService *services.NotificationService, dockerService *services.DockerClientService) *ContainerEventWatcher {
+	return &ContainerEventWatcher{
+		notificationService: notificationService,
+		dockerService:       dockerService,
+		now:                 time.Now,
+		stoppedAt:           make(map[string]time.Time),
+		oomAt:               make(map[string]time.Time),
+		crashes:             make(map[string][]time.Time),
+		restartLoopUntil:    make(map[string]time.Time),
+	}
+}
+
+// Name identifies the watcher in scheduler lifecycle logs.
+func (w *ContainerEventWatcher) Name() string {
+	return "container-event-notifications"
+}
+
+// Start subscribes to Docker container events and sends notifications until ctx is canceled.
+func (w *ContainerEventWatcher) Start(ctx context.Context) error {
+	if w == nil || w.dockerService == nil || w.dockerService.EventBus() == nil {
+		return errors.New("docker event bus unavailable")
+	}
+	if w.notificationService == nil {
+		return errors.New("notification service unavailable")
+	}
+
+	eventCh, unsubscribe := w.dockerService.EventBus().Subscribe(events.ContainerEventType, bus.WithSubscriberBuffer(64))
+	defer unsubscribe()
+
+	slog.InfoContext(ctx, "container event watcher started")
+	var senders sync.WaitGroup
+	defer func() {
+		senders.Wait()
+		slog.InfoContext(ctx, "container event watcher stopped")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-eventCh:
+			if !ok {
+				return nil
+			}
+			notification, ok := w.handleEventInternal(msg)
+			if !ok {
+				continue
+			}
+			// Deliver off the listener so slow providers do not back up the event stream.
+			senders.Go(func() {
+				if err := w.notificationService.SendContainerEventNotification(ctx, notification.eventType, notification.containerName, notification.containerID, notification.detail); err != nil && !errors.Is(err, context.Canceled) {
+					slog.WarnContext(ctx, "failed to send container event notification", "event", notification.eventType, "container", notification.containerName, "error", err)
+				}
+			})
+		}
+	}
+}
+
+// RunNow is a no-op; container events are only reported as they happen.
+func (w *ContainerEventWatcher) RunNow(context.Context) error {
+	return nil
+}
+
+// handleEventInternal updates per-container state for msg and returns the
+// notification it should produce, if any.
+func (w *ContainerEventWatcher) handleEventInternal(msg events.Message) (containerEventNotificationInternal, bool) {
+	containerID := msg.Actor.ID
+	if containerID == "" {
+		return containerEventNotificationInternal{}, false
+	}
+	notification := containerEventNotificationInternal{
+		containerName: strings.TrimPrefix(cmp.Or(msg.Actor.Attributes["name"], containerID), "/"),
+		containerID:   containerID,
+	}
+	now := w.now()
+
+	switch msg.Action {
+	case events.ActionKill:
+		if _, ok := containerEventStopSignals[strings.ToUpper(msg.Actor.Attributes["signal"])]; ok {
+			w.stoppedAt[containerID] = now
+		}
+		return containerEventNotificationInternal{}, false
+	case events.ActionDestroy:
+		w.forgetContainerInternal(containerID)
+		return containerEventNotificationInternal{}, false
+	case events.ActionOOM:
+		w.oomAt[containerID] = now
+		if w.restartLoopUntil[containerID].After(now) {
+			return containerEventNotificationInternal{}, false
+		}
+		notification.eventType = models.NotificationEventContainerOOM
+		notification.detail = "Killed after running out of memory."
+		return notification, true
+	case events.ActionHealthStatusUnhealthy:
+		notification.eventType = models.NotificationEventContainerUnhealthy
+		notification.detail = "Health check is failing."
+		return notification, true
+	case events.ActionDie:
+		return w.handleDieInternal(msg, notification, now)
+	default:
+		return containerEventNotificationInternal{}, false
+	}
+}
+
+func (w *ContainerEventWatcher) handleDieInternal(msg events.Message, notification containerEventNotificationInternal, now time.Time) (containerEventNotificationInternal, bool) {
+	containerID := notification.containerID
+	exitCode := msg.Actor.Attributes["exitCode"]
+
+	if stoppedAt, ok := w.stoppedAt[containerID]; ok {
+		delete(w.stoppedAt, containerID)
+		if now.Sub(stoppedAt) <= containerEventStopGrace {
+			return containerEventNotificationInternal{}, false
+		}
+	}
+	if exitCode == "" || exitCode == "0" {
+		return containerEventNotificationInternal{}, false
+	}
+
+	crashes := w.recordCrashInternal(containerID, now)
+	if w.restartLoopUntil[containerID].After(now) {
+		// Already reported as a restart loop; stay quiet until the window passes.
+		return containerEventNotificationInternal{}, false
+	}
+	if crashes >= containerEventRestartLoopThreshold {
+		w.restartLoopUntil[containerID] = now.Add(containerEventRestartLoopWindow)
+		notification.eventType = models.NotificationEventContainerRestartLoop
+		notification.detail = fmt.Sprintf("Exited %d times in the last %s (last exit code %s).", crashes, containerEventRestartLoopWindow, exitCode)
+		return notification, true
+	}
+	// The oom event that caused this exit was already reported.
+	if oomAt, ok := w.oomAt[containerID]; ok {
+		delete(w.oomAt, containerID)
+		if now.Sub(oomAt) <= containerEventStopGrace {
+			return containerEventNotificationInternal{}, false
+		}
+	}
+
+	notification.eventType = models.NotificationEventContainerDie
+	notification.detail = fmt.Sprintf("Exited unexpectedly with exit code %s.", exitCode)
+	return notification, true
+}
+
+// recordCrashInternal records a crash at now and returns how many crashes fall
+// within the restart-loop window.
+func (w *ContainerEventWatcher) recordCrashInternal(containerID string, now time.Time) int {
+	cutoff := now.Add(-containerEventRestartLoopWindow)
+	recent := w.crashes[containerID][:0]
+	for _, crashedAt := range w.crashes[containerID] {
+		if crashedAt.After(cutoff) {
+			recent = append(recent, crashedAt)
+		}
+	}
+	recent = append(recent, now)
+	w.crashes[containerID] = recent
+	return len(recent)
+}
+
+func (w *ContainerEventWatcher) forgetContainerInternal(containerID string) {
+	delete(w.stoppedAt, containerID)
+	delete(w.oomAt, containerID)
+	delete(w.crashes, containerID)
+	delete(w.restartLoopUntil, containerID)
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/moby/moby/api/types/events"
+	"github.com/stretchr/testify/require"
+	"go.getarcane.app/streams/bus"
+)
+
+type containerEventNotifierFakeInternal struct {
+	sent chan containerEventNotificationInternal
+}
+
+func (n *containerEventNotifierFakeInternal) SendContainerEventNotification(_ context.Context, eventType models.NotificationEventType, containerName, containerID, detail string) error {
+	select {
+	case n.sent <- containerEventNotificationInternal{eventType: eventType, containerName: containerName, containerID: containerID, detail: detail}:
+	default:
+	}
+	return nil
+}
+
+func containerEventMessageInternal(action events.Action, attributes map[string]string) events.Message {
+	attrs := map[string]string{"name": "web"}
+	maps.Copy(attrs, attributes)
+	return events.Message{
+		Type:   events.ContainerEventType,
+		Action: action,
+		Actor:  events.Actor{ID: "abc123", Attributes: attrs},
+	}
+}
+
+func TestContainerEventWatcher_HandleEventReportsCrashesButNotRequestedStops(t *testing.T) {
+	watcher := NewContainerEventWatcher(nil, nil)
+	now := time.Unix(0, 0)
+	watcher.now = func() time.Time { return now }
+
+	// docker stop: kill then die with a non-zero exit code.
+	_, ok := watcher.handleEventInternal(containerEventMessageInternal(events.ActionKill, map[string]string{"signal": "15"}))
+	require.False(t, ok)
+	_, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "143"}))
+	require.False(t, ok)
+
+	_, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "0"}))
+	require.False(t, ok)
+
+	notification, ok := watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "1"}))
+	require.True(t, ok)
+	require.Equal(t, models.NotificationEventContainerDie, notification.eventType)
+	require.Equal(t, "web", notification.containerName)
+	require.Contains(t, notification.detail, "exit code 1")
+
+	// OOM is reported once; the die that follows it is not.
+	notification, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionOOM, nil))
+	require.True(t, ok)
+	require.Equal(t, models.NotificationEventContainerOOM, notification.eventType)
+	now = now.Add(time.Second)
+	_, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "137"}))
+	require.False(t, ok)
+
+	// Third crash inside the window is a restart loop, reported once.
+	now = now.Add(time.Second)
+	notification, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "1"}))
+	require.True(t, ok)
+	require.Equal(t, models.NotificationEventContainerRestartLoop, notification.eventType)
+	now = now.Add(time.Second)
+	_, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "1"}))
+	require.False(t, ok)
+
+	// After the window the container is reported again.
+	now = now.Add(containerEventRestartLoopWindow + time.Second)
+	notification, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "1"}))
+	require.True(t, ok)
+	require.Equal(t, models.NotificationEventContainerDie, notification.eventType)
+}
+
+func TestContainerEventWatcher_HandleEventReportsCrashAfterReloadSignal(t *testing.T) {
+	watcher := NewContainerEventWatcher(nil, nil)
+	now := time.Unix(0, 0)
+	watcher.now = func() time.Time { return now }
+
+	// SIGHUP asks the process to reload; it does not stop the container.
+	_, ok := watcher.handleEventInternal(containerEventMessageInternal(events.ActionKill, map[string]string{"signal": "1"}))
+	require.False(t, ok)
+	now = now.Add(time.Second)
+	notification, ok := watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "1"}))
+	require.True(t, ok)
+	require.Equal(t, models.NotificationEventContainerDie, notification.eventType)
+
+	// SIGQUIT is a common configured stop signal, so the exit is not a crash.
+	_, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionKill, map[string]string{"signal": "3"}))
+	require.False(t, ok)
+	_, ok = watcher.handleEventInternal(containerEventMessageInternal(events.ActionDie, map[string]string{"exitCode": "131"}))
+	require.False(t, ok)
+}
+
+func TestContainerEventWatcher_StartSendsUnhealthyNotification(t *testing.T) {
+	eventBus := bus.NewDockerEventBus()
+	notifier := &containerEventNotifierFakeInternal{sent: make(chan containerEventNotificationInternal, 1)}
+	watcher := NewContainerEventWatcher(nil, nil)
+	watcher.notificationService = notifier
+	watcher.dockerService = dockerEventBusProviderFakeInternal{eventBus: eventBus}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		eventBus.Publish(containerEventMessageInternal(events.ActionHealthStatusUnhealthy, nil))
+		select {
+		case notification := <-notifier.sent:
+			require.Equal(t, models.NotificationEventContainerUnhealthy, notification.eventType)
+			require.Equal(t, "abc123", notification.containerID)
+			return true
+		default:
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -5,22 +5,25 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
 	"github.com/getarcaneapp/arcane/backend/v2/internal/services"
 )
 
 const EventCleanupJobName = "event-cleanup"
 
 type EventCleanupJob struct {
-	eventService    *services.EventService
-	activityService *services.ActivityService
-	settingsService *services.SettingsService
+	eventService        *services.EventService
+	activityService     *services.ActivityService
+	notificationService *services.NotificationService
+	settingsService     *services.SettingsService
 }
 
-func NewEventCleanupJob(eventService *services.EventService, activityService *services.ActivityService, settingsService *services.SettingsService) *EventCleanupJob {
+func NewEventCleanupJob(eventService *services.EventService, activityService *services.ActivityService, notificationService *services.NotificationService, settingsService *services.SettingsService) *EventCleanupJob {
 	return &EventCleanupJob{
-		eventService:    eventService,
-		activityService: activityService,
-		settingsService: settingsService,
+		eventService:        eventService,
+		activityService:     activityService,
+		notificationService: notificationService,
+		settingsService:     settingsService,
 	}
 }
 
@@ -39,16 +42,35 @@ func (j *EventCleanupJob) Schedule(ctx context.Context) string {
 func (j *EventCleanupJob) Run(ctx context.Context) {
 	slog.InfoContext(ctx, "Running event cleanup job", "jobName", EventCleanupJobName)
 
-	// Delete events older than 36 hours
-	olderThan := 36 * time.Hour
-	if err := j.eventService.DeleteOldEvents(ctx, olderThan); err != nil {
-		slog.ErrorContext(ctx, "Failed to delete old events", "jobName", EventCleanupJobName, "olderThan", olderThan.String(), "error", err)
-		return
+	// A retention of 0 keeps events indefinitely, e.g. for audit purposes.
+	// Notification delivery logs are trimmed by notificationLogRetentionDays.
+	if retentionHours := j.settingsService.GetIntSetting(ctx, "eventRetentionHours", 36); retentionHours > 0 {
+		olderThan := time.Duration(retentionHours) * time.Hour
+		if err := j.eventService.DeleteOldEvents(ctx, olderThan, models.EventTypeNotificationSend); err != nil {
+			slog.ErrorContext(ctx, "Failed to delete old events", "jobName", EventCleanupJobName, "olderThan", olderThan.String(), "error", err)
+			return
+		}
+
+		slog.InfoContext(ctx, "Event cleanup job completed successfully",
+			"jobName", EventCleanupJobName,
+			"olderThan", olderThan.String())
 	}
 
-	slog.InfoContext(ctx, "Event cleanup job completed successfully",
-		"jobName", EventCleanupJobName,
-		"olderThan", olderThan.String())
+	if j.notificationService != nil {
+		if retentionDays := j.settingsService.GetIntSetting(ctx, "notificationLogRetentionDays", 7); retentionDays > 0 {
+			olderThan := time.Duration(retentionDays) * 24 * time.Hour
+			deleted, err := j.notificationService.DeleteOldNotificationLogs(ctx, olderThan)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to delete old notification logs", "jobName", EventCleanupJobName, "olderThan", olderThan.String(), "error", err)
+				return
+			}
+
+			slog.InfoContext(ctx, "Notification log cleanup completed successfully",
+				"jobName", EventCleanupJobName,
+				"retentionDays", retentionDays,
+				"deleted", deleted)
+		}
+	}
 
 	if j.activityService != nil {
 		retentionDays := j.settingsService.GetIntSetting(ctx, "activityHistoryRetentionDays", 30)
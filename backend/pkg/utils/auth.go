@@ -8,5 +8,10 @@ const (
 	HeaderAgentToken      = "X-Arcane-Agent-Token" // #nosec G101: header name, not a credential
 	HeaderApiKey          = "X-Api-Key"            // #nosec G101: header name, not a credential
 	HeaderActivityBatchID = "X-Arcane-Batch-Id"
-	AgentPairingPrefix    = "/api/environments/0/agent/pair"
+	// HeaderMaintenanceOverride lets a global admin run one mutating request while
+	// the environment is in maintenance mode.
+	HeaderMaintenanceOverride = "X-Arcane-Maintenance-Override"
+	// HeaderControllerURL tells an agent which controller is pairing with it.
+	HeaderControllerURL = "X-Arcane-Controller-Url"
+	AgentPairingPrefix  = "/api/environments/0/agent/pair"
 )
@@ -3,6 +3,7 @@ package notifications
 import (
 	"context"
 	"net/mail"
+	"time"
 
 	"emperror.dev/errors"
 
@@ -33,6 +34,10 @@ type Content struct {
 
 	// ValidatePushoverUser preserves the per-event pushover token/user validation.
 	ValidatePushoverUser bool
+
+	// Event is the structured event posted by the webhook provider. Its
+	// Title, Message and Timestamp are filled in at delivery when empty.
+	Event Event
 }
 
 type delivererFunc func(ctx context.Context, config models.JSON, c Content) error
@@ -48,6 +53,28 @@ var providerDeliverers = map[models.NotificationProvider]delivererFunc{
 	models.NotificationProviderGotify:   deliverGotify,
 	models.NotificationProviderMatrix:   deliverMatrix,
 	models.NotificationProviderGeneric:  deliverGeneric,
+	models.NotificationProviderWebhook:  deliverWebhook,
+}
+
+// providerMessageFormats records which Content.Text format each deliverer
+// sends. Email is absent because it renders its own HTML via RenderEmail.
+var providerMessageFormats = map[models.NotificationProvider]MessageFormat{
+	models.NotificationProviderDiscord:  MessageFormatMarkdown,
+	models.NotificationProviderTelegram: MessageFormatHTML,
+	models.NotificationProviderSignal:   MessageFormatPlain,
+	models.NotificationProviderSlack:    MessageFormatSlack,
+	models.NotificationProviderNtfy:     MessageFormatPlain,
+	models.NotificationProviderPushover: MessageFormatPlain,
+	models.NotificationProviderGotify:   MessageFormatPlain,
+	models.NotificationProviderMatrix:   MessageFormatPlain,
+	models.NotificationProviderGeneric:  MessageFormatPlain,
+	models.NotificationProviderWebhook:  MessageFormatPlain,
+}
+
+// ProviderMessageFormat returns the message format provider is sent in.
+func ProviderMessageFormat(provider models.NotificationProvider) (MessageFormat, bool) {
+	format, ok := providerMessageFormats[provider]
+	return format, ok
 }
 
 // Deliver sends c to a single provider. handled is false for unknown providers.
@@ -245,6 +272,34 @@ func deliverGeneric(ctx context.Context, config models.JSON, c Content) error {
 	return nil
 }
 
+func deliverWebhook(ctx context.Context, config models.JSON, c Content) error {
+	webhookConfig, err := DecodeConfig[models.WebhookConfig](config, "Webhook")
+	if err != nil {
+		return err
+	}
+	if webhookConfig.URL == "" {
+		return errors.New("webhook URL not configured")
+	}
+	if err := DecryptStringCredential(&webhookConfig.Secret); err != nil {
+		return err
+	}
+
+	event := c.Event
+	if event.Title == "" {
+		event.Title = c.Title
+	}
+	if event.Message == "" {
+		event.Message = c.Text[MessageFormatPlain]
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if err := SendWebhook(ctx, webhookConfig, event); err != nil {
+		return errors.WrapIf(err, "failed to send webhook notification")
+	}
+	return nil
+}
+
 // TextByFormat builds the per-format message map for Content.Text from a
 // single messages.go builder closure.
 func TextByFormat(build func(MessageFormat) string) map[MessageFormat]string {
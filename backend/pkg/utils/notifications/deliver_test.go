@@ -27,6 +27,7 @@ func TestProviderDeliverers_CoverAllValidProviders(t *testing.T) {
 		models.NotificationProviderGotify,
 		models.NotificationProviderMatrix,
 		models.NotificationProviderGeneric,
+		models.NotificationProviderWebhook,
 	} {
 		require.Contains(t, providerDeliverers, provider)
 	}
@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+
+	"emperror.dev/errors"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/types/v2/imageupdate"
+)
+
+// MessageTemplateConfigKey is the NotificationSettings.Config key holding a
+// provider's custom image update message template.
+//
+// The template uses Go text/template syntax (html/template for email, so
+// values are escaped) and can reference:
+//
+//	{{.environment}}     environment name
+//	{{.imageRef}}        image reference, e.g. nginx:latest
+//	{{.currentDigest}}   digest currently in use
+//	{{.latestDigest}}    digest available in the registry
+//	{{.currentVersion}}  version currently in use
+//	{{.latestVersion}}   version available in the registry
+//	{{.updateType}}      kind of update, e.g. digest or tag
+//
+// Batched notifications render the template once per image.
+const MessageTemplateConfigKey = "messageTemplate"
+
+// ImageUpdateTemplateData returns the variables an image update message template can reference.
+func ImageUpdateTemplateData(environmentName, imageRef string, updateInfo *imageupdate.Response) map[string]string {
+	data := map[string]string{
+		"environment":    environmentName,
+		"imageRef":       imageRef,
+		"currentDigest":  "",
+		"latestDigest":   "",
+		"currentVersion": "",
+		"latestVersion":  "",
+		"updateType":     "",
+	}
+	if updateInfo != nil {
+		data["currentDigest"] = updateInfo.CurrentDigest
+		data["latestDigest"] = updateInfo.LatestDigest
+		data["currentVersion"] = updateInfo.CurrentVersion
+		data["latestVersion"] = updateInfo.LatestVersion
+		data["updateType"] = updateInfo.UpdateType
+	}
+	return data
+}
+
+// MessageTemplate returns the custom message template in config, or "" when none is set.
+func MessageTemplate(config models.JSON) string {
+	tmpl, _ := config[MessageTemplateConfigKey].(string)
+	return strings.TrimSpace(tmpl)
+}
+
+// ValidateMessageTemplate checks that the template in config parses and only
+// references documented variables.
+func ValidateMessageTemplate(config models.JSON) error {
+	if raw, ok := config[MessageTemplateConfigKey]; ok && raw != nil {
+		if _, isString := raw.(string); !isString {
+			return common.Classify(common.ErrNotificationMessageTemplateInvalid, errors.New("message template must be a string"))
+		}
+	}
+	tmpl := MessageTemplate(config)
+	if tmpl == "" {
+		return nil
+	}
+
+	sample := ImageUpdateTemplateData("Local Docker", "nginx:latest", &imageupdate.Response{
+		UpdateType:     "digest",
+		CurrentVersion: "1.27",
+		LatestVersion:  "1.27",
+		CurrentDigest:  "sha256:current",
+		LatestDigest:   "sha256:latest",
+	})
+	if _, err := renderTextMessageTemplateInternal(tmpl, []map[string]string{sample}); err != nil {
+		return common.Classify(common.ErrNotificationMessageTemplateInvalid, err)
+	}
+	if _, err := renderHTMLMessageTemplateInternal(tmpl, []map[string]string{sample}); err != nil {
+		return common.Classify(common.ErrNotificationMessageTemplateInvalid, err)
+	}
+	return nil
+}
+
+// ApplyMessageTemplate returns c with its text and email body replaced by the
+// template in config rendered for each entry in data. c is returned unchanged
+// when config has no template.
+func ApplyMessageTemplate(config models.JSON, c Content, data []map[string]string) (Content, error) {
+	tmpl := MessageTemplate(config)
+	if tmpl == "" {
+		return c, nil
+	}
+
+	text, err := renderTextMessageTemplateInternal(tmpl, data)
+	if err != nil {
+		return Content{}, err
+	}
+	c.Text = TextByFormat(func(MessageFormat) string { return text })
+
+	renderEmail := c.RenderEmail
+	if renderEmail != nil {
+		c.RenderEmail = func() (string, string, error) {
+			subject, _, err := renderEmail()
+			if err != nil {
+				return "", "", err
+			}
+			body, err := renderHTMLMessageTemplateInternal(tmpl, data)
+			if err != nil {
+				return "", "", err
+			}
+			return subject, `<div style="white-space: pre-wrap">` + body + `</div>`, nil
+		}
+	}
+	return c, nil
+}
+
+func renderTextMessageTemplateInternal(tmpl string, data []map[string]string) (string, error) {
+	parsed, err := texttemplate.New("message").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to parse message template")
+	}
+	return executeMessageTemplateInternal(parsed, data)
+}
+
+func renderHTMLMessageTemplateInternal(tmpl string, data []map[string]string) (string, error) {
+	parsed, err := htmltemplate.New("message").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to parse message template")
+	}
+	return executeMessageTemplateInternal(parsed, data)
+}
+
+// executeMessageTemplateInternal renders parsed once per data entry and joins
+// the results with blank lines.
+func executeMessageTemplateInternal(parsed interface{ Execute(io.Writer, any) error }, data []map[string]string) (string, error) {
+	parts := make([]string, 0, len(data))
+	for _, item := range data {
+		var out bytes.Buffer
+		if err := parsed.Execute(&out, item); err != nil {
+			return "", errors.WrapIf(err, "failed to render message template")
+		}
+		parts = append(parts, strings.TrimSpace(out.String()))
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
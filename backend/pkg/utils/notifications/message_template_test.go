@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/common"
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+	"github.com/getarcaneapp/arcane/types/v2/imageupdate"
+)
+
+func TestValidateMessageTemplate(t *testing.T) {
+	require.NoError(t, ValidateMessageTemplate(models.JSON{}))
+	require.NoError(t, ValidateMessageTemplate(models.JSON{MessageTemplateConfigKey: "<@here> {{.imageRef}} -> {{.latestDigest}}"}))
+
+	require.ErrorIs(t, ValidateMessageTemplate(models.JSON{MessageTemplateConfigKey: "{{.imageRef"}), common.ErrNotificationMessageTemplateInvalid)
+	require.ErrorIs(t, ValidateMessageTemplate(models.JSON{MessageTemplateConfigKey: "{{.image}}"}), common.ErrNotificationMessageTemplateInvalid)
+	require.ErrorIs(t, ValidateMessageTemplate(models.JSON{MessageTemplateConfigKey: 42}), common.ErrNotificationMessageTemplateInvalid)
+}
+
+func TestApplyMessageTemplate_RendersEachImageAndEscapesEmail(t *testing.T) {
+	content := Content{
+		Text: TextByFormat(func(MessageFormat) string { return "default" }),
+		RenderEmail: func() (string, string, error) {
+			return "subject", "<p>default</p>", nil
+		},
+	}
+	data := []map[string]string{
+		ImageUpdateTemplateData("Local", "nginx:latest", &imageupdate.Response{UpdateType: "digest", LatestDigest: "sha256:new"}),
+		ImageUpdateTemplateData("Local", "<redis>", &imageupdate.Response{UpdateType: "tag"}),
+	}
+
+	unchanged, err := ApplyMessageTemplate(models.JSON{}, content, data)
+	require.NoError(t, err)
+	require.Equal(t, "default", unchanged.Text[MessageFormatSlack])
+
+	templated, err := ApplyMessageTemplate(models.JSON{MessageTemplateConfigKey: "*{{.imageRef}}* ({{.updateType}})"}, content, data)
+	require.NoError(t, err)
+	require.Equal(t, "*nginx:latest* (digest)\n\n*<redis>* (tag)", templated.Text[MessageFormatSlack])
+	require.Equal(t, "default", content.Text[MessageFormatSlack])
+
+	subject, body, err := templated.RenderEmail()
+	require.NoError(t, err)
+	require.Equal(t, "subject", subject)
+	require.Contains(t, body, "*&lt;redis&gt;* (tag)")
+}
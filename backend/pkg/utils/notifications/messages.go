@@ -203,6 +203,17 @@ func BuildAutoHealNotificationMessage(format MessageFormat, environmentName, con
 	return message.String()
 }
 
+func BuildContainerEventNotificationMessage(format MessageFormat, environmentName, title, containerName, detail string) string {
+	var message strings.Builder
+	fmt.Fprintf(&message, "%s\n\n", formatNotificationTitleInternal(format, title))
+	fmt.Fprintf(&message, "%s %s\n", formatNotificationLabelInternal(format, "Environment"), environmentName)
+	fmt.Fprintf(&message, "%s %s\n", formatNotificationLabelInternal(format, "Container"), containerName)
+	if detail != "" {
+		fmt.Fprintf(&message, "%s %s\n", formatNotificationLabelInternal(format, "Status"), detail)
+	}
+	return message.String()
+}
+
 func FormatBytes(bytes uint64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	json "encoding/json/v2"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+)
+
+const (
+	// WebhookEventHeader carries the event type so receivers can route without parsing the body.
+	WebhookEventHeader = "X-Arcane-Event"
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of the body when a secret is configured.
+	WebhookSignatureHeader = "X-Arcane-Signature"
+
+	webhookErrorBodyLimit = 4 << 10
+)
+
+// Event is the structured description of a notification posted by the
+// webhook provider.
+type Event struct {
+	EventType   string           `json:"eventType"`
+	Resource    string           `json:"resource"`
+	Before      any              `json:"before,omitempty"`
+	After       any              `json:"after,omitempty"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Environment EventEnvironment `json:"environment"`
+	Title       string           `json:"title,omitempty"`
+	Message     string           `json:"message,omitempty"`
+}
+
+// EventEnvironment identifies the environment an Event happened in.
+type EventEnvironment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SignWebhookPayload returns the X-Arcane-Signature header value for body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendWebhook POSTs event as JSON to the configured URL, signing the body
+// when a secret is configured.
+func SendWebhook(ctx context.Context, config models.WebhookConfig, event Event) error {
+	target, err := url.Parse(strings.TrimSpace(config.URL))
+	if err != nil {
+		return errors.WrapIf(err, "invalid webhook URL")
+	}
+	switch strings.ToLower(target.Scheme) {
+	case "http", "https":
+	default:
+		return errors.Errorf("invalid webhook URL scheme: %s", target.Scheme)
+	}
+	if target.Host == "" {
+		return errors.New("invalid webhook URL: missing host")
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.WrapIf(err, "failed to marshal webhook payload")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return errors.WrapIf(err, "failed to create webhook request")
+	}
+	for key, value := range config.CustomHeaders {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event.EventType)
+	if config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(config.Secret, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.WrapIf(err, "failed to send webhook request")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+		return errors.Errorf("webhook returned HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"context"
+	json "encoding/json/v2"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/backend/v2/internal/models"
+)
+
+func TestDeliverWebhook_PostsEventAndSignsWithSecret(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotSignature string
+		gotEvent     string
+		gotHeader    string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotEvent = r.Header.Get(WebhookEventHeader)
+		gotHeader = r.Header.Get("X-Team")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	content := Content{
+		Title: "Container Image Update",
+		Text:  TextByFormat(func(MessageFormat) string { return "nginx:latest has an update" }),
+		Event: Event{
+			EventType:   string(models.NotificationEventImageUpdate),
+			Resource:    "nginx:latest",
+			Before:      map[string]any{"digest": "sha256:old"},
+			After:       map[string]any{"digest": "sha256:new"},
+			Environment: EventEnvironment{ID: "0", Name: "Local"},
+		},
+	}
+	config := models.JSON{
+		"url":           server.URL + "/hooks/arcane",
+		"customHeaders": map[string]any{"X-Team": "ops"},
+	}
+
+	handled, err := Deliver(context.Background(), models.NotificationProviderWebhook, config, content)
+	require.True(t, handled)
+	require.NoError(t, err)
+
+	require.Empty(t, gotSignature)
+	require.Equal(t, "image_update", gotEvent)
+	require.Equal(t, "ops", gotHeader)
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(gotBody, &event))
+	require.Equal(t, "image_update", event["eventType"])
+	require.Equal(t, "nginx:latest", event["resource"])
+	require.Equal(t, map[string]any{"digest": "sha256:new"}, event["after"])
+	require.Equal(t, map[string]any{"id": "0", "name": "Local"}, event["environment"])
+	require.Equal(t, "Container Image Update", event["title"])
+	require.Equal(t, "nginx:latest has an update", event["message"])
+	require.NotEmpty(t, event["timestamp"])
+
+	require.NoError(t, SendWebhook(context.Background(), models.WebhookConfig{URL: server.URL, Secret: "s3cret"}, content.Event))
+	require.Equal(t, SignWebhookPayload("s3cret", gotBody), gotSignature)
+}
+
+func TestSendWebhook_ReturnsErrorOnNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := SendWebhook(context.Background(), models.WebhookConfig{URL: server.URL}, Event{EventType: "test"})
+	require.ErrorContains(t, err, "HTTP 502")
+
+	require.ErrorContains(t, SendWebhook(context.Background(), models.WebhookConfig{URL: "ftp://example.com"}, Event{}), "scheme")
+}
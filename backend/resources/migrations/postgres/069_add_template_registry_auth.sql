@@ -0,0 +1,12 @@
+-- +goose Up
+-- Optional credentials for private template registries.
+-- auth_type: none, bearer or basic.
+-- credential: encrypted bearer token or basic auth password.
+ALTER TABLE template_registries ADD COLUMN auth_type TEXT NOT NULL DEFAULT 'none';
+ALTER TABLE template_registries ADD COLUMN username TEXT NOT NULL DEFAULT '';
+ALTER TABLE template_registries ADD COLUMN credential TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE template_registries DROP COLUMN credential;
+ALTER TABLE template_registries DROP COLUMN username;
+ALTER TABLE template_registries DROP COLUMN auth_type;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE compose_templates ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE compose_templates DROP COLUMN category;
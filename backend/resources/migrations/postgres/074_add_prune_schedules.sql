@@ -0,0 +1,40 @@
+-- +goose Up
+CREATE TABLE prune_schedules (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ,
+    environment_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    schedule TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    policy TEXT NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_run_status TEXT,
+    last_space_reclaimed BIGINT NOT NULL DEFAULT 0,
+    total_space_reclaimed BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_prune_schedules_environment_id ON prune_schedules(environment_id);
+
+CREATE TABLE prune_schedule_runs (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ,
+    schedule_id TEXT NOT NULL REFERENCES prune_schedules(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    space_reclaimed BIGINT NOT NULL DEFAULT 0,
+    containers_pruned INTEGER NOT NULL DEFAULT 0,
+    images_deleted INTEGER NOT NULL DEFAULT 0,
+    volumes_deleted INTEGER NOT NULL DEFAULT 0,
+    networks_deleted INTEGER NOT NULL DEFAULT 0,
+    errors TEXT,
+    activity_id TEXT
+);
+
+CREATE INDEX idx_prune_schedule_runs_schedule_started ON prune_schedule_runs(schedule_id, started_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS prune_schedule_runs;
+DROP TABLE IF EXISTS prune_schedules;
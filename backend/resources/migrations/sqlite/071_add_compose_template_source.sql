@@ -0,0 +1,11 @@
+-- +goose Up
+-- Upstream source of templates downloaded from a registry, used to detect
+-- and pull upstream changes.
+ALTER TABLE compose_templates ADD COLUMN source_url TEXT;
+ALTER TABLE compose_templates ADD COLUMN source_registry_id TEXT;
+ALTER TABLE compose_templates ADD COLUMN source_digest TEXT;
+
+-- +goose Down
+ALTER TABLE compose_templates DROP COLUMN source_digest;
+ALTER TABLE compose_templates DROP COLUMN source_registry_id;
+ALTER TABLE compose_templates DROP COLUMN source_url;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Encrypted agent bootstrap token used to re-pair an environment whose agent
+-- rejects the stored access token.
+ALTER TABLE environments ADD COLUMN bootstrap_token TEXT;
+
+-- +goose Down
+ALTER TABLE environments DROP COLUMN bootstrap_token;
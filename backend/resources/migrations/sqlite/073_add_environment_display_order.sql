@@ -0,0 +1,8 @@
+-- +goose Up
+-- Curated environment layout: pinned environments sort first, then by display_order.
+ALTER TABLE environments ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE environments ADD COLUMN display_order INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE environments DROP COLUMN display_order;
+ALTER TABLE environments DROP COLUMN pinned;
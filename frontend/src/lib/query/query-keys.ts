@@ -42,6 +42,7 @@ export const queryKeys = {
 		switcher: (options: SearchPaginationSortRequest) => ['environments', 'switcher', stableSerialize(options)] as const,
 		detail: (environmentId: string) => ['environment', environmentId] as const,
 		settings: (environmentId: string) => ['environment-settings', environmentId] as const,
+		maintenance: (environmentId: string) => ['environment', 'maintenance', environmentId] as const,
 		deploymentSnippets: (environmentId: string) => ['environment', 'deployment-snippets', environmentId] as const
 	},
 	gitRepositories: {
@@ -116,7 +117,8 @@ export const queryKeys = {
 			['project', 'check-updates', environmentId, projectId] as const,
 		statusCounts: (environmentId: string) => ['projects', 'status-counts', environmentId] as const,
 		detail: (environmentId: string, projectId: string) => ['project', environmentId, projectId] as const,
-		files: (environmentId: string, projectId: string) => ['project', environmentId, projectId, 'files'] as const
+		files: (environmentId: string, projectId: string) => ['project', environmentId, projectId, 'files'] as const,
+		orphans: (environmentId: string, projectId: string) => ['project', environmentId, projectId, 'orphans'] as const
 	},
 	networks: {
 		all: ['networks'] as const,
@@ -2,6 +2,7 @@ import BaseAPIService from './api-service';
 import type {
 	ContainerRegistryCreateDto,
 	ContainerRegistryPullUsageResponse,
+	ContainerRegistryTestResult,
 	ContainerRegistryUpdateDto
 } from '#lib/types/docker';
 import type { ContainerRegistry } from '#lib/types/docker';
@@ -35,7 +36,7 @@ class ContainerRegistryService extends BaseAPIService {
 		return this.handleResponse(this.api.delete(`/container-registries/${id}`));
 	}
 
-	async testRegistry(id: string): Promise<any> {
+	async testRegistry(id: string): Promise<ContainerRegistryTestResult> {
 		return this.handleResponse(this.api.post(`/container-registries/${id}/test`));
 	}
 }
@@ -1,22 +1,54 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '#lib/stores/environment.store.svelte';
+import { downloadBlob } from '#lib/utils/browser-download';
 import type {
 	ContainerStatusCounts,
 	ContainerSummaryDto,
 	ContainerSummaryGroupDto,
 	ContainerCreateRequest,
+	ContainerCreatedDto,
 	ContainerDetailsDto,
+	ContainerHealthDto,
 	ContainerCommitRequest,
-	ContainerCommitResult
+	ContainerCommitResult,
+	ContainerResourceUpdate,
+	ContainerResourceUpdateResult,
+	ContainerRecreateRequest,
+	ContainerEnvVar,
+	ContainerEnvUpdate,
+	ContainerLogSearchRequest,
+	ContainerLogSearchResult,
+	ContainerTop,
+	ContainerChanges,
+	ContainerExecRunRequest,
+	ContainerExecRunResult,
+	ContainerBatchAction,
+	ContainerBatchActionResponse,
+	ImagePullProgress
 } from '#lib/types/docker';
-import type { SearchPaginationSortRequest, Paginated } from '#lib/types/shared';
+import type { SearchPaginationSortRequest, Paginated, FileEntry } from '#lib/types/shared';
 import { transformPaginationParams } from '#lib/utils/tables';
+import { readNdjsonStream } from '#lib/utils/streaming';
+import { m } from '#lib/paraglide/messages';
+
+export type ContainerStopOptions = {
+	/** Seconds to wait before the container is killed. */
+	timeout?: number;
+	/** Signal sent instead of the container's stop signal, e.g. SIGINT. */
+	signal?: string;
+};
+
+function stopBody(options?: ContainerStopOptions): ContainerStopOptions | undefined {
+	if (!options || (options.timeout === undefined && !options.signal)) return undefined;
+	return { timeout: options.timeout, signal: options.signal || undefined };
+}
 
 export type ContainersPaginatedResponse = Paginated<ContainerSummaryDto, ContainerStatusCounts> & {
 	groups?: ContainerSummaryGroupDto[];
 };
 export type ContainerListRequestOptions = SearchPaginationSortRequest & {
 	groupByProject?: boolean;
+	includeHealth?: boolean;
 };
 
 class ContainerService extends BaseAPIService {
@@ -37,6 +69,9 @@ class ContainerService extends BaseAPIService {
 		if (options?.groupByProject) {
 			params['groupBy'] = 'project';
 		}
+		if (options?.includeHealth) {
+			params['includeHealth'] = 'true';
+		}
 		const res = await this.api.get(`/environments/${environmentId}/containers`, { params });
 		return res.data;
 	}
@@ -60,6 +95,12 @@ class ContainerService extends BaseAPIService {
 		return this.handleResponse(this.api.get(`/environments/${environmentId}/containers/${containerId}`));
 	}
 
+	async getContainerHealth(containerId: string, limit?: number): Promise<ContainerHealthDto> {
+		const envId = await this.resolveEnvironmentId();
+		const params = limit !== undefined ? { limit } : undefined;
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${containerId}/health`, { params }));
+	}
+
 	async startContainer(containerId: string): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/start`));
@@ -70,14 +111,55 @@ class ContainerService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/containers`, options));
 	}
 
-	async stopContainer(containerId: string): Promise<any> {
+	async createContainerStream(
+		options: ContainerCreateRequest,
+		environmentId?: string,
+		onProgress?: (progress: ImagePullProgress) => void
+	): Promise<ContainerCreatedDto> {
+		const envId = await this.resolveEnvironmentId(environmentId);
+		const response = await fetch(`/api/environments/${envId}/containers/create/stream`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(options)
+		});
+		if (!response.ok || !response.body) {
+			const errorData = await response.json().catch(() => ({}));
+			throw new Error(errorData.detail || errorData.message || `${m.containers_create_failed()}: HTTP ${response.status}`);
+		}
+
+		let created: ContainerCreatedDto | undefined;
+		await readNdjsonStream(response.body, (frame) => {
+			if (frame?.error) {
+				throw new Error(frame.error);
+			}
+			if (frame?.type === 'pull') {
+				onProgress?.(frame);
+				return false;
+			}
+			if (frame?.done === true) {
+				created = frame.data;
+				return true;
+			}
+			return false;
+		});
+		if (!created) {
+			throw new Error(m.containers_create_failed());
+		}
+		return created;
+	}
+
+	async stopContainer(containerId: string, options?: ContainerStopOptions): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/stop`));
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/containers/${containerId}/stop`, stopBody(options))
+		);
 	}
 
-	async restartContainer(containerId: string): Promise<any> {
+	async restartContainer(containerId: string, options?: ContainerStopOptions): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/restart`));
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/containers/${containerId}/restart`, stopBody(options))
+		);
 	}
 
 	async killContainer(containerId: string, signal?: string): Promise<any> {
@@ -102,6 +184,62 @@ class ContainerService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/commit`, request));
 	}
 
+	async updateContainerResources(containerId: string, update: ContainerResourceUpdate): Promise<ContainerResourceUpdateResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.patch(`/environments/${envId}/containers/${containerId}`, update));
+	}
+
+	async listContainerDirectoryPage(
+		containerId: string,
+		path: string = '/',
+		options: { filter?: string; offset?: number; limit?: number } = {}
+	): Promise<{ entries: FileEntry[]; total: number }> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/${containerId}/files`, {
+			params: { path, ...options }
+		});
+		const entries: FileEntry[] = res.data.data;
+		const total = Number(res.headers['x-total-count'] ?? entries.length);
+		return { entries, total };
+	}
+
+	async writeContainerFile(containerId: string, path: string, content: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.put(`/environments/${envId}/containers/${containerId}/files`, { content }, { params: { path } })
+		);
+	}
+
+	async uploadContainerFile(containerId: string, path: string, file: File): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.postFile(`/environments/${envId}/containers/${containerId}/files/upload`, file, { path });
+	}
+
+	async exportContainerLogs(containerId: string, containerName: string, opts?: { tail?: string; since?: string }): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const params: Record<string, string> = {};
+		if (opts?.tail) params['tail'] = opts.tail;
+		if (opts?.since) params['since'] = opts.since;
+
+		const res = await this.api.get(`/environments/${envId}/containers/${containerId}/logs/export`, {
+			params,
+			responseType: 'blob'
+		});
+
+		downloadBlob(res.data, `${containerName || containerId}.log`);
+	}
+
+	async batchContainerAction(
+		action: ContainerBatchAction,
+		ids: string[],
+		opts?: { force?: boolean }
+	): Promise<ContainerBatchActionResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/containers/batch/${action}`, { ids, force: opts?.force ?? false })
+		);
+	}
+
 	async deleteContainer(containerId: string, opts?: { force?: boolean; volumes?: boolean }): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const params: Record<string, string> = {};
@@ -121,6 +259,41 @@ class ContainerService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/redeploy`));
 	}
 
+	async recreateContainer(containerId: string, overrides?: ContainerRecreateRequest): Promise<ContainerDetailsDto> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/recreate`, overrides ?? {}));
+	}
+
+	async getContainerEnv(containerId: string, reveal = false): Promise<ContainerEnvVar[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${containerId}/env`, { params: reveal ? { reveal } : {} }));
+	}
+
+	async setContainerEnv(containerId: string, update: ContainerEnvUpdate): Promise<ContainerDetailsDto> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/containers/${containerId}/env`, update));
+	}
+
+	async getContainerTop(containerId: string, psArgs?: string): Promise<ContainerTop> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${containerId}/top`, { params: psArgs ? { psArgs } : {} }));
+	}
+
+	async getContainerChanges(containerId: string): Promise<ContainerChanges> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${containerId}/changes`));
+	}
+
+	async runContainerExec(containerId: string, request: ContainerExecRunRequest): Promise<ContainerExecRunResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/exec/run`, request));
+	}
+
+	async searchContainerLogs(containerId: string, request: ContainerLogSearchRequest): Promise<ContainerLogSearchResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/logs/search`, request));
+	}
+
 	async setAutoUpdate(containerId: string, enabled: boolean): Promise<{ success: boolean; data: { message: string } }> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.put(`/environments/${envId}/containers/${containerId}/auto-update`, { enabled }));
@@ -1,5 +1,13 @@
 import BaseAPIService from './api-service';
-import type { CreateEnvironmentDTO, DeploymentSnippets, Environment, UpdateEnvironmentDTO } from '#lib/types/environment';
+import type {
+	CreateEnvironmentDTO,
+	DeploymentSnippets,
+	Environment,
+	EnvironmentConnectionTest,
+	EnvironmentMaintenance,
+	EnvironmentRefreshResult,
+	UpdateEnvironmentDTO
+} from '#lib/types/environment';
 import type { Paginated, SearchPaginationSortRequest } from '#lib/types/shared';
 import type { AppVersionInformation } from '#lib/types/settings';
 import { transformPaginationParams } from '#lib/utils/tables';
@@ -30,9 +38,23 @@ class EnvironmentManagementService extends BaseAPIService {
 		await this.api.delete(`/environments/${environmentId}`);
 	}
 
-	async testConnection(environmentId: string, apiUrl?: string): Promise<{ status: 'online' | 'offline'; message?: string }> {
+	async testConnection(environmentId: string, apiUrl?: string): Promise<EnvironmentConnectionTest> {
 		const res = await this.api.post(`/environments/${environmentId}/test`, apiUrl ? { apiUrl } : undefined);
-		return res.data.data as { status: 'online' | 'offline'; message?: string };
+		return res.data.data as EnvironmentConnectionTest;
+	}
+
+	async reorder(ids: string[]): Promise<void> {
+		await this.api.patch('/environments/reorder', { ids });
+	}
+
+	async repairAgent(environmentId: string): Promise<EnvironmentConnectionTest> {
+		const res = await this.api.post(`/environments/${environmentId}/agent/repair`);
+		return res.data.data as EnvironmentConnectionTest;
+	}
+
+	async refreshAll(): Promise<EnvironmentRefreshResult> {
+		const res = await this.api.post('/environments/refresh');
+		return res.data.data as EnvironmentRefreshResult;
 	}
 
 	async sync(environmentId: string): Promise<void> {
@@ -48,6 +70,16 @@ class EnvironmentManagementService extends BaseAPIService {
 		const res = await this.api.get(`/environments/${environmentId}/version`);
 		return res.data.data as AppVersionInformation;
 	}
+
+	async getMaintenance(environmentId: string): Promise<EnvironmentMaintenance> {
+		const res = await this.api.get(`/environments/${environmentId}/maintenance`);
+		return res.data.data as EnvironmentMaintenance;
+	}
+
+	async setMaintenance(environmentId: string, maintenance: EnvironmentMaintenance): Promise<EnvironmentMaintenance> {
+		const res = await this.api.post(`/environments/${environmentId}/maintenance`, maintenance);
+		return res.data.data as EnvironmentMaintenance;
+	}
 }
 
 export const environmentManagementService = new EnvironmentManagementService();
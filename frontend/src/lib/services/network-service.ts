@@ -6,7 +6,8 @@ import type {
 	NetworkCreateRequest,
 	NetworkCreateOptions,
 	NetworkInspectDto,
-	NetworkTopologyDto
+	NetworkTopologyDto,
+	NetworkUsageDto
 } from '#lib/types/docker';
 import type { SearchPaginationSortRequest, Paginated } from '#lib/types/shared';
 import { transformPaginationParams } from '#lib/utils/tables';
@@ -52,6 +53,11 @@ class NetworkService extends BaseAPIService {
 		return this.handleResponse(this.api.get(`/environments/${environmentId}/networks/${networkId}`, { params }));
 	}
 
+	async getNetworkUsage(networkId: string): Promise<NetworkUsageDto> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/networks/${networkId}/usage`));
+	}
+
 	async getNetworkTopology(environmentId?: string): Promise<NetworkTopologyDto> {
 		const envId = await this.resolveEnvironmentId(environmentId);
 		return this.handleResponse(this.api.get(`/environments/${envId}/networks/topology`));
@@ -1,5 +1,5 @@
 import BaseAPIService from './api-service';
-import type { NotificationSettings, TestNotificationResponse } from '#lib/types/notifications';
+import type { NotificationProvider, NotificationProviderTestResult, NotificationSettings, TestNotificationResponse } from '#lib/types/notifications';
 import { environmentStore } from '#lib/stores/environment.store.svelte';
 
 class NotificationService extends BaseAPIService {
@@ -22,6 +22,10 @@ class NotificationService extends BaseAPIService {
 		const encodedType = encodeURIComponent(type);
 		return this.handleResponse(this.api.post(`/environments/${envId}/notifications/test/${provider}?type=${encodedType}`));
 	}
+
+	async testProvider(provider: NotificationProvider): Promise<NotificationProviderTestResult> {
+		return this.handleResponse(this.api.post(`/notifications/${provider}/test`));
+	}
 }
 
 export const notificationService = new NotificationService();
@@ -1,7 +1,7 @@
 import { m } from '#lib/paraglide/messages';
 import { environmentStore } from '#lib/stores/environment.store.svelte';
 import type { Paginated, SearchPaginationSortRequest } from '#lib/types/shared';
-import type { IncludeFile, Project, ProjectStatusCounts } from '#lib/types/swarm';
+import type { IncludeFile, Project, ProjectOrphans, ProjectOrphansPruneResult, ProjectStatusCounts } from '#lib/types/swarm';
 import type { ProjectFileChange, ProjectFileDraft } from '#lib/types/project-files';
 import { readNdjsonStream } from '#lib/utils/streaming';
 import { transformPaginationParams } from '#lib/utils/tables';
@@ -228,6 +228,16 @@ class ProjectService extends BaseAPIService {
 		await this.handleResponse(this.api.post(`/environments/${envId}/projects/${projectId}/unarchive`));
 	}
 
+	async getProjectOrphans(environmentId: string, projectId: string): Promise<ProjectOrphans> {
+		return this.handleResponse<ProjectOrphans>(this.api.get(`/environments/${environmentId}/projects/${projectId}/orphans`));
+	}
+
+	async pruneProjectOrphans(environmentId: string, projectId: string): Promise<ProjectOrphansPruneResult> {
+		return this.handleResponse<ProjectOrphansPruneResult>(
+			this.api.post(`/environments/${environmentId}/projects/${projectId}/orphans/prune`)
+		);
+	}
+
 	async redeployProject(projectId: string, onLine?: (data: any) => void): Promise<Project> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const url = `/api/environments/${envId}/projects/${projectId}/redeploy`;
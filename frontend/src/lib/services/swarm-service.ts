@@ -2,29 +2,38 @@ import BaseAPIService from './api-service';
 import { environmentStore } from '#lib/stores/environment.store.svelte';
 import type { SearchPaginationSortRequest, Paginated } from '#lib/types/shared';
 import { transformPaginationParams } from '#lib/utils/tables';
+import { downloadBlob } from '#lib/utils/browser-download';
 import type {
 	SwarmServiceSummary,
 	SwarmNodeSummary,
 	SwarmTaskSummary,
 	SwarmStackSummary,
 	SwarmInfo,
+	SwarmClusterResources,
 	SwarmRuntimeStatus,
 	SwarmNodeAgentDeployment,
 	SwarmNodeAgentBindingRequest,
 	SwarmNodeAgentReconcileResponse,
 	SwarmServiceCreateRequest,
+	SwarmServiceFromComposeRequest,
 	SwarmServiceUpdateRequest,
 	SwarmServiceCreateResponse,
 	SwarmServiceUpdateResponse,
+	SwarmServiceImagePinResponse,
 	SwarmServiceInspect,
 	SwarmStackDeployRequest,
 	SwarmStackDeployResponse,
 	SwarmServiceScaleRequest,
+	SwarmServiceResourcesRequest,
 	SwarmNodeUpdateRequest,
+	SwarmNodeLabelsPatchRequest,
+	SwarmNodeLabelsResponse,
 	SwarmStackInspect,
 	SwarmStackRenderConfigRequest,
 	SwarmStackRenderConfigResponse,
+	SwarmStackRemovalPlan,
 	SwarmStackSource,
+	SwarmStackImportResponse,
 	SwarmStackSourceUpdateRequest,
 	SwarmInitRequest,
 	SwarmInitResponse,
@@ -41,6 +50,9 @@ import type {
 	SwarmConfigUpdateRequest,
 	SwarmSecretCreateRequest,
 	SwarmSecretUpdateRequest,
+	SwarmSecretRotateRequest,
+	SwarmSecretRotateResponse,
+	SwarmServiceMountUsage,
 	SwarmJoinCandidate,
 	SwarmJoinEnvironmentsRequest,
 	SwarmJoinEnvironmentsResponse
@@ -50,6 +62,8 @@ export type SwarmServicesPaginatedResponse = Paginated<SwarmServiceSummary>;
 export type SwarmNodesPaginatedResponse = Paginated<SwarmNodeSummary>;
 export type SwarmTasksPaginatedResponse = Paginated<SwarmTaskSummary>;
 export type SwarmStacksPaginatedResponse = Paginated<SwarmStackSummary>;
+export type SwarmConfigsPaginatedResponse = Paginated<SwarmConfigSummary>;
+export type SwarmSecretsPaginatedResponse = Paginated<SwarmSecretSummary>;
 
 class SwarmService extends BaseAPIService {
 	async getServices(options?: SearchPaginationSortRequest): Promise<SwarmServicesPaginatedResponse> {
@@ -69,6 +83,11 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/services`, request));
 	}
 
+	async createServiceFromCompose(request: SwarmServiceFromComposeRequest): Promise<SwarmServiceCreateResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/services/from-compose`, request));
+	}
+
 	async updateService(serviceId: string, request: SwarmServiceUpdateRequest): Promise<SwarmServiceUpdateResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.put(`/environments/${envId}/swarm/services/${serviceId}`, request));
@@ -86,11 +105,21 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/services/${serviceId}/rollback`, {}));
 	}
 
+	async pinServiceImage(serviceId: string): Promise<SwarmServiceImagePinResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/services/${serviceId}/pin`, {}));
+	}
+
 	async scaleService(serviceId: string, request: SwarmServiceScaleRequest): Promise<SwarmServiceUpdateResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/services/${serviceId}/scale`, request));
 	}
 
+	async updateServiceResources(serviceId: string, request: SwarmServiceResourcesRequest): Promise<SwarmServiceUpdateResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.patch(`/environments/${envId}/swarm/services/${serviceId}/resources`, request));
+	}
+
 	async removeService(serviceId: string): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		await this.handleResponse(this.api.delete(`/environments/${envId}/swarm/services/${serviceId}`));
@@ -138,6 +167,11 @@ class SwarmService extends BaseAPIService {
 		await this.handleResponse(this.api.patch(`/environments/${envId}/swarm/nodes/${nodeId}`, request));
 	}
 
+	async patchNodeLabels(nodeId: string, request: SwarmNodeLabelsPatchRequest): Promise<SwarmNodeLabelsResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.patch(`/environments/${envId}/swarm/nodes/${nodeId}/labels`, request));
+	}
+
 	async removeNode(nodeId: string, force = false): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		await this.handleResponse(this.api.delete(`/environments/${envId}/swarm/nodes/${nodeId}`, { params: { force } }));
@@ -153,6 +187,15 @@ class SwarmService extends BaseAPIService {
 		await this.handleResponse(this.api.post(`/environments/${envId}/swarm/nodes/${nodeId}/demote`, {}));
 	}
 
+	async getTaskLogs(taskId: string, options: { tail?: string; timestamps?: boolean } = {}): Promise<string> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/swarm/tasks/${taskId}/logs`, {
+			params: { tail: options.tail ?? '500', timestamps: options.timestamps ?? false },
+			responseType: 'text'
+		});
+		return (res.data ?? '') as string;
+	}
+
 	async getNodeTasks(nodeId: string, options?: SearchPaginationSortRequest): Promise<SwarmTasksPaginatedResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const params = transformPaginationParams(options);
@@ -194,6 +237,11 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.put(`/environments/${envId}/swarm/stacks/${name}/source`, request));
 	}
 
+	async getStackRemovalPlan(name: string): Promise<SwarmStackRemovalPlan> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/stacks/${name}/removal-plan`));
+	}
+
 	async removeStack(name: string): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		await this.handleResponse(this.api.delete(`/environments/${envId}/swarm/stacks/${name}`));
@@ -218,11 +266,29 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/stacks/config/render`, request));
 	}
 
+	async exportStacks(): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/swarm/stacks/export`, { responseType: 'blob' });
+		downloadBlob(res.data, 'swarm-stacks.tar.gz');
+	}
+
+	async importStacks(file: File, dryRun = false): Promise<SwarmStackImportResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const formData = new FormData();
+		formData.append('file', file);
+		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/stacks/import`, formData, { params: { dryRun } }));
+	}
+
 	async getSwarmInfo(): Promise<SwarmInfo> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/info`));
 	}
 
+	async getClusterResources(): Promise<SwarmClusterResources> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/resources`));
+	}
+
 	async getSwarmStatus(): Promise<SwarmRuntimeStatus> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/status`));
@@ -278,9 +344,11 @@ class SwarmService extends BaseAPIService {
 		await this.handleResponse(this.api.put(`/environments/${envId}/swarm/spec`, request));
 	}
 
-	async getConfigs(): Promise<SwarmConfigSummary[]> {
+	async getConfigs(options?: SearchPaginationSortRequest): Promise<SwarmConfigsPaginatedResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/configs`));
+		const params = transformPaginationParams(options);
+		const res = await this.api.get(`/environments/${envId}/swarm/configs`, { params });
+		return res.data;
 	}
 
 	async getConfig(configId: string): Promise<SwarmConfigSummary> {
@@ -288,6 +356,11 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/configs/${configId}`));
 	}
 
+	async getConfigUsage(configId: string): Promise<SwarmServiceMountUsage[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/configs/${configId}/usage`));
+	}
+
 	async createConfig(request: SwarmConfigCreateRequest): Promise<SwarmConfigSummary> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/configs`, request));
@@ -303,9 +376,11 @@ class SwarmService extends BaseAPIService {
 		await this.handleResponse(this.api.delete(`/environments/${envId}/swarm/configs/${configId}`));
 	}
 
-	async getSecrets(): Promise<SwarmSecretSummary[]> {
+	async getSecrets(options?: SearchPaginationSortRequest): Promise<SwarmSecretsPaginatedResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/secrets`));
+		const params = transformPaginationParams(options);
+		const res = await this.api.get(`/environments/${envId}/swarm/secrets`, { params });
+		return res.data;
 	}
 
 	async getSecret(secretId: string): Promise<SwarmSecretSummary> {
@@ -313,6 +388,11 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/secrets/${secretId}`));
 	}
 
+	async getSecretUsage(secretId: string): Promise<SwarmServiceMountUsage[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/swarm/secrets/${secretId}/usage`));
+	}
+
 	async createSecret(request: SwarmSecretCreateRequest): Promise<SwarmSecretSummary> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/secrets`, request));
@@ -323,6 +403,11 @@ class SwarmService extends BaseAPIService {
 		return this.handleResponse(this.api.put(`/environments/${envId}/swarm/secrets/${secretId}`, request));
 	}
 
+	async rotateSecret(secretId: string, request: SwarmSecretRotateRequest): Promise<SwarmSecretRotateResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/swarm/secrets/${secretId}/rotate`, request));
+	}
+
 	async removeSecret(secretId: string): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		await this.handleResponse(this.api.delete(`/environments/${envId}/swarm/secrets/${secretId}`));
@@ -1,7 +1,13 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '#lib/stores/environment.store.svelte';
-import type { DockerInfo } from '#lib/types/docker';
-import type { SystemPruneRequest } from '#lib/types/automation';
+import type { DiskUsage, DockerInfo } from '#lib/types/docker';
+import type {
+	PruneSchedule,
+	PruneScheduleCreateDto,
+	PruneScheduleRun,
+	PruneScheduleUpdateDto,
+	SystemPruneRequest
+} from '#lib/types/automation';
 
 type ConvertedDockerRun = {
 	dockerCompose: string;
@@ -19,6 +25,32 @@ class SystemService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${environmentId}/system/prune`, options));
 	}
 
+	async listPruneSchedules(environmentId: string): Promise<PruneSchedule[]> {
+		return this.handleResponse(this.api.get(`/environments/${environmentId}/system/prune/schedules`));
+	}
+
+	async createPruneSchedule(environmentId: string, dto: PruneScheduleCreateDto): Promise<PruneSchedule> {
+		return this.handleResponse(this.api.post(`/environments/${environmentId}/system/prune/schedules`, dto));
+	}
+
+	async updatePruneSchedule(environmentId: string, scheduleId: string, dto: PruneScheduleUpdateDto): Promise<PruneSchedule> {
+		return this.handleResponse(this.api.put(`/environments/${environmentId}/system/prune/schedules/${scheduleId}`, dto));
+	}
+
+	async deletePruneSchedule(environmentId: string, scheduleId: string) {
+		return this.handleResponse(this.api.delete(`/environments/${environmentId}/system/prune/schedules/${scheduleId}`));
+	}
+
+	async runPruneSchedule(environmentId: string, scheduleId: string): Promise<PruneScheduleRun> {
+		return this.handleResponse(this.api.post(`/environments/${environmentId}/system/prune/schedules/${scheduleId}/run`));
+	}
+
+	async listPruneScheduleRuns(environmentId: string, scheduleId: string, limit = 20): Promise<PruneScheduleRun[]> {
+		return this.handleResponse(
+			this.api.get(`/environments/${environmentId}/system/prune/schedules/${scheduleId}/runs`, { params: { limit } })
+		);
+	}
+
 	async startAllStoppedContainers() {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/system/containers/start-stopped`));
@@ -38,6 +70,11 @@ class SystemService extends BaseAPIService {
 		return this.handleResponse(this.api.get(`/environments/${environmentId}/system/docker/info`));
 	}
 
+	async getDiskUsage(refresh = false): Promise<DiskUsage> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/system/df`, { params: { refresh } }));
+	}
+
 	async convert(dockerRunCommand: string): Promise<ConvertedDockerRun> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(
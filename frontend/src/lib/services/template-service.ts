@@ -1,5 +1,5 @@
 import BaseAPIService from './api-service';
-import type { TemplateRegistry, Template, RemoteRegistry, TemplateContentData } from '#lib/types/swarm';
+import type { TemplateRegistry, Template, RemoteRegistry, TemplateContentData, TemplateValidationResult, TemplateRegistryAuth, TemplateDeployRequest, TemplateDeployResult, TemplateUpdateStatus } from '#lib/types/swarm';
 import type { SearchPaginationSortRequest, Paginated } from '#lib/types/shared';
 import { transformPaginationParams } from '#lib/utils/tables';
 
@@ -15,12 +15,37 @@ class TemplateService extends BaseAPIService {
 		return response.data?.data ?? [];
 	}
 
+	async getCategories(): Promise<string[]> {
+		const response = await this.api.get('/templates/categories');
+		return response.data?.data ?? [];
+	}
+
 	async getTemplateContent(id: string): Promise<TemplateContentData> {
 		const encodedId = encodeURIComponent(id);
 		const response = await this.api.get(`/templates/${encodedId}/content`);
 		return response.data?.data;
 	}
 
+	async validateTemplate(id: string, variables?: Record<string, string>): Promise<TemplateValidationResult> {
+		const response = await this.api.post(`/templates/${encodeURIComponent(id)}/validate`, { variables });
+		return response.data?.data;
+	}
+
+	async checkUpdates(): Promise<TemplateUpdateStatus[]> {
+		const response = await this.api.get('/templates/updates');
+		return response.data?.data ?? [];
+	}
+
+	async refresh(id: string): Promise<Template> {
+		const response = await this.api.post(`/templates/${encodeURIComponent(id)}/refresh`);
+		return response.data?.data;
+	}
+
+	async deployTemplate(id: string, request: TemplateDeployRequest): Promise<TemplateDeployResult> {
+		const response = await this.api.post(`/templates/${encodeURIComponent(id)}/deploy`, request);
+		return response.data?.data;
+	}
+
 	async download(id: string): Promise<Template> {
 		const response = await this.api.post(`/templates/${encodeURIComponent(id)}/download`);
 		return response.data?.data;
@@ -55,7 +80,9 @@ class TemplateService extends BaseAPIService {
 		return Array.isArray(out) ? out : [];
 	}
 
-	async addRegistry(registry: { name: string; url: string; description?: string; enabled: boolean }): Promise<TemplateRegistry> {
+	async addRegistry(
+		registry: { name: string; url: string; description?: string; enabled: boolean } & TemplateRegistryAuth
+	): Promise<TemplateRegistry> {
 		const response = await this.api.post('/templates/registries', registry);
 		return response.data?.data ?? response.data;
 	}
@@ -67,13 +94,19 @@ class TemplateService extends BaseAPIService {
 			url: string;
 			description?: string;
 			enabled: boolean;
-		}
+		} & TemplateRegistryAuth
 	): Promise<void> {
 		await this.api.put(`/templates/registries/${id}`, registry);
 	}
 
-	async fetchRegistry(url: string): Promise<RemoteRegistry> {
-		const response = await this.api.get(`/templates/fetch?url=${encodeURIComponent(url)}`);
+	async fetchRegistry(url: string, auth?: TemplateRegistryAuth): Promise<RemoteRegistry> {
+		const headers: Record<string, string> = {};
+		if (auth?.authType && auth.authType !== 'none') {
+			headers['X-Registry-Auth-Type'] = auth.authType;
+			if (auth.username) headers['X-Registry-Username'] = auth.username;
+			if (auth.token) headers['X-Registry-Token'] = auth.token;
+		}
+		const response = await this.api.get(`/templates/fetch?url=${encodeURIComponent(url)}`, { headers });
 		const manifest = response.data?.data ?? response.data;
 		if (!manifest || typeof manifest !== 'object' || !manifest.name || !Array.isArray(manifest.templates)) {
 			throw new Error('Invalid registry format: missing required fields (name, templates)');
@@ -92,13 +125,17 @@ class TemplateService extends BaseAPIService {
 			description?: string;
 			content: string;
 			envContent?: string;
+			category?: string;
+			tags?: string[];
 		}
 	): Promise<Template> {
 		const response = await this.api.put(`/templates/${encodeURIComponent(id)}`, {
 			name: template.name,
 			description: template.description || '',
 			content: template.content,
-			envContent: template.envContent || ''
+			envContent: template.envContent || '',
+			category: template.category || '',
+			tags: template.tags
 		});
 		return response.data?.data;
 	}
@@ -112,12 +149,16 @@ class TemplateService extends BaseAPIService {
 		description?: string;
 		content: string;
 		envContent?: string;
+		category?: string;
+		tags?: string[];
 	}): Promise<Template> {
 		const response = await this.api.post('/templates', {
 			name: template.name,
 			description: template.description || '',
 			content: template.content,
-			envContent: template.envContent || ''
+			envContent: template.envContent || '',
+			category: template.category || '',
+			tags: template.tags ?? []
 		});
 		return response.data?.data;
 	}
@@ -5,6 +5,7 @@ import type { SearchPaginationSortRequest, Paginated } from '#lib/types/shared';
 import { transformPaginationParams } from '#lib/utils/tables';
 
 export type VolumeBackupListResponse = Paginated<BackupEntry> & { warnings?: string[] };
+export type VolumeArchiveRestoreResponse = { data?: { message?: string; activityId?: string }; warnings?: string[] };
 
 class VolumeBackupService extends BaseAPIService {
 	async createBackup(volumeName: string): Promise<BackupEntry> {
@@ -72,6 +73,32 @@ class VolumeBackupService extends BaseAPIService {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.postFile(`/environments/${envId}/volumes/${volumeName}/backups/upload`, file);
 	}
+
+	async exportVolumeArchive(volumeName: string): Promise<string | null> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/backup`, {
+			responseType: 'blob'
+		});
+
+		const url = window.URL.createObjectURL(new Blob([res.data]));
+		const link = document.createElement('a');
+		link.href = url;
+		link.setAttribute('download', `${volumeName}.tar`);
+		document.body.appendChild(link);
+		link.click();
+		link.remove();
+		window.URL.revokeObjectURL(url);
+
+		return (res.headers?.['x-arcane-warning'] as string | undefined) ?? null;
+	}
+
+	async restoreVolumeArchive(volumeName: string, file: File): Promise<VolumeArchiveRestoreResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const formData = new FormData();
+		formData.append('file', file);
+		const res = await this.api.post(`/environments/${envId}/volumes/${volumeName}/restore`, formData);
+		return res.data;
+	}
 }
 
 export const volumeBackupService = new VolumeBackupService();
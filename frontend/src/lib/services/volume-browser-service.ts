@@ -12,6 +12,20 @@ class VolumeBrowserService extends BaseAPIService {
 		return res.data.data;
 	}
 
+	async listDirectoryPage(
+		volumeName: string,
+		path: string = '/',
+		options: { filter?: string; offset?: number; limit?: number } = {}
+	): Promise<{ entries: FileEntry[]; total: number }> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse`, {
+			params: { path, ...options }
+		});
+		const entries: FileEntry[] = res.data.data;
+		const total = Number(res.headers['x-total-count'] ?? entries.length);
+		return { entries, total };
+	}
+
 	async getFileContent(volumeName: string, path: string): Promise<FileContentResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse/content`, {
@@ -72,10 +72,54 @@ export interface SystemPruneRequest {
 	volumes?: PruneVolumesOptions;
 	networks?: PruneNetworksOptions;
 	buildCache?: PruneBuildCacheOptions;
+	labelFilters?: Record<string, string>;
+	until?: string;
 }
 
 export type PruneType = 'containers' | 'images' | 'networks' | 'volumes' | 'buildCache';
 
+export type PruneScheduleRunStatus = 'success' | 'partial' | 'failed' | 'skipped';
+
+export interface PruneSchedule {
+	id: string;
+	environmentId: string;
+	name: string;
+	schedule: string;
+	enabled: boolean;
+	policy: SystemPruneRequest;
+	nextRunAt?: string;
+	lastRunAt?: string;
+	lastRunStatus?: PruneScheduleRunStatus;
+	lastSpaceReclaimed: number;
+	totalSpaceReclaimed: number;
+	createdAt: string;
+	updatedAt?: string;
+}
+
+export interface PruneScheduleRun {
+	id: string;
+	scheduleId: string;
+	status: PruneScheduleRunStatus;
+	startedAt: string;
+	finishedAt?: string;
+	spaceReclaimed: number;
+	containersPruned: number;
+	imagesDeleted: number;
+	volumesDeleted: number;
+	networksDeleted: number;
+	errors?: string[];
+	activityId?: string;
+}
+
+export interface PruneScheduleCreateDto {
+	name: string;
+	schedule: string;
+	enabled?: boolean;
+	policy: SystemPruneRequest;
+}
+
+export type PruneScheduleUpdateDto = Partial<PruneScheduleCreateDto>;
+
 // --- GitOps: repositories, syncs, browsing ---
 
 export interface GitRepositoryCreateDto {
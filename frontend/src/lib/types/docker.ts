@@ -45,6 +45,13 @@ export interface NetworkingConfig {
 	endpointsConfig?: Record<string, { aliases?: string[] }>;
 }
 
+export interface NetworkAttachmentCreate {
+	name: string;
+	aliases?: string[];
+	ipv4Address?: string;
+	ipv6Address?: string;
+}
+
 export interface ContainerCreateRequest {
 	name: string;
 	image: string;
@@ -54,6 +61,7 @@ export interface ContainerCreateRequest {
 	exposedPorts?: Record<string, {}>;
 	hostConfig?: HostConfigCreate;
 	networkingConfig?: NetworkingConfig;
+	networkAttachments?: NetworkAttachmentCreate[];
 	labels?: Record<string, string>;
 	workingDir?: string;
 	user?: string;
@@ -66,6 +74,22 @@ export interface ContainerCreateRequest {
 	tty?: boolean;
 	openStdin?: boolean;
 	stdinOnce?: boolean;
+	skipPortCheck?: boolean;
+}
+
+export interface ContainerCreatedDto {
+	id: string;
+	name: string;
+	image: string;
+	status: string;
+	created: string;
+}
+
+export interface ImagePullProgress {
+	id?: string;
+	status: string;
+	current?: number;
+	total?: number;
 }
 
 export interface ContainerSummaryDto extends BaseContainer {
@@ -75,6 +99,15 @@ export interface ContainerSummaryDto extends BaseContainer {
 	mounts: ContainerMounts[];
 	updateInfo?: ImageUpdateInfoDto;
 	redeployDisabled?: boolean;
+	gpus?: ContainerGPUAssignment;
+	health?: ContainerHealthDto;
+}
+
+export interface ContainerGPUAssignment {
+	count: number;
+	deviceIds?: string[];
+	capabilities?: string[];
+	drivers?: string[];
 }
 
 export interface ContainerSummaryGroupDto {
@@ -202,6 +235,11 @@ export interface ContainerDetailsDto {
 	redeployDisabled?: boolean;
 }
 
+export interface ContainerRecreateRequest {
+	image?: string;
+	env?: Record<string, string>;
+}
+
 export interface ContainerCommitRequest {
 	repository?: string;
 	tag?: string;
@@ -215,6 +253,92 @@ export interface ContainerCommitResult {
 	id: string;
 }
 
+export interface ContainerEnvVar {
+	key: string;
+	value: string;
+	masked?: boolean;
+}
+
+export interface ContainerEnvUpdate {
+	env: ContainerEnvVar[];
+}
+
+export interface ContainerTop {
+	titles: string[];
+	processes: string[][];
+}
+
+export interface ContainerChanges {
+	added: string[];
+	changed: string[];
+	deleted: string[];
+}
+
+export interface ContainerExecRunRequest {
+	cmd: string[];
+	env?: string[];
+	workingDir?: string;
+	user?: string;
+	timeoutSeconds?: number;
+}
+
+export interface ContainerExecRunResult {
+	exitCode: number;
+	stdout: string;
+	stderr: string;
+	truncated: boolean;
+	durationMs: number;
+}
+
+export interface ContainerLogSearchRequest {
+	pattern: string;
+	caseInsensitive?: boolean;
+	since?: string;
+	until?: string;
+	maxMatches?: number;
+}
+
+export interface ContainerLogSearchMatch {
+	line: number;
+	timestamp?: string;
+	text: string;
+}
+
+export interface ContainerLogSearchResult {
+	matches: ContainerLogSearchMatch[];
+	linesScanned: number;
+	truncated: boolean;
+	timedOut: boolean;
+}
+
+export interface ContainerResourceUpdate {
+	restartPolicy?: { name: 'no' | 'always' | 'unless-stopped' | 'on-failure'; maximumRetryCount?: number };
+	memory?: number;
+	memorySwap?: number;
+	memoryReservation?: number;
+	nanoCpus?: number;
+	cpuShares?: number;
+}
+
+export interface ContainerResourceUpdateResult {
+	warnings?: string[];
+}
+
+export type ContainerBatchAction = 'start' | 'stop' | 'restart' | 'remove';
+
+export interface ContainerBatchActionResult {
+	id: string;
+	success: boolean;
+	error?: string;
+}
+
+export interface ContainerBatchActionResponse {
+	action: ContainerBatchAction;
+	succeeded: number;
+	failed: number;
+	results: ContainerBatchActionResult[];
+}
+
 // --- Container stats ---
 
 export interface BlkioStatEntry {
@@ -667,6 +791,21 @@ export interface NetworkTopologyDto {
 	edges: TopologyEdgeDto[];
 }
 
+export interface NetworkServiceAttachmentDto {
+	serviceId: string;
+	serviceName: string;
+	aliases?: string[];
+}
+
+export interface NetworkUsageDto {
+	networkId: string;
+	networkName: string;
+	scope: string;
+	containers: ContainerEndpointDto[];
+	services: NetworkServiceAttachmentDto[];
+	inUse: boolean;
+}
+
 // --- Volumes ---
 
 export interface VolumeUsageData {
@@ -740,6 +879,7 @@ export interface DockerInfo {
 	os: string;
 	arch: string;
 	buildTime: string;
+	gpuType?: string;
 
 	ID: string;
 	Containers: number;
@@ -870,6 +1010,15 @@ export interface ContainerRegistry {
 	updatedAt?: string;
 }
 
+export interface ContainerRegistryTestResult {
+	message: string;
+	authType?: 'none' | 'basic' | 'bearer';
+	service?: string;
+	scope?: string[];
+	expiresIn?: number;
+	probeError?: string;
+}
+
 export interface ContainerRegistryPullUsageResponse {
 	registries: ContainerRegistryPullUsage[];
 }
@@ -891,3 +1040,58 @@ export interface ContainerRegistryPullUsage {
 	checkedAt: string;
 	error?: string;
 }
+
+// --- Docker disk usage (docker system df) ---
+
+export interface DiskUsageCategory {
+	totalCount: number;
+	activeCount: number;
+	totalSize: number;
+	reclaimable: number;
+}
+
+export interface ImageDiskUsage {
+	id: string;
+	repoTags?: string[];
+	size: number;
+	sharedSize: number;
+	uniqueSize: number;
+	containers: number;
+	created: number;
+}
+
+export interface VolumeDiskUsage {
+	name: string;
+	driver: string;
+	size: number;
+	refCount: number;
+}
+
+export interface DiskUsage {
+	images: DiskUsageCategory;
+	containers: DiskUsageCategory;
+	volumes: DiskUsageCategory;
+	buildCache: DiskUsageCategory;
+	totalSize: number;
+	totalReclaimable: number;
+	imageItems: ImageDiskUsage[];
+	volumeItems: VolumeDiskUsage[];
+	collectedAt: string;
+}
+
+// --- Docker engine events ---
+
+export interface DockerEvent {
+	time: string;
+	type: string;
+	action: string;
+	actorId?: string;
+	actorName?: string;
+	scope?: string;
+	attributes?: Record<string, string>;
+}
+
+export interface DockerEventFilter {
+	types?: string[];
+	labels?: string[];
+}
@@ -1,6 +1,6 @@
 // --- Environments & deployment snippets ---
 
-export type EnvironmentStatus = 'online' | 'standby' | 'offline' | 'error' | 'pending';
+export type EnvironmentStatus = 'online' | 'standby' | 'offline' | 'error' | 'pending' | 'auth_failed';
 
 export type EdgeMTLSCertificate = {
 	commonName?: string;
@@ -17,6 +17,8 @@ export type Environment = {
 	status: EnvironmentStatus;
 	enabled: boolean;
 	isEdge: boolean;
+	pinned: boolean;
+	displayOrder: number;
 	edgeTransport?: 'grpc' | 'websocket';
 	lastEdgeTransport?: 'grpc' | 'websocket';
 	edgeSecurityMode?: 'token' | 'mtls';
@@ -27,6 +29,31 @@ export type Environment = {
 	lastSeen?: string;
 	edgeMTLSCertificate?: EdgeMTLSCertificate;
 	apiKey?: string;
+	latencyMs?: number;
+	agentVersion?: string;
+	circuitBreaker?: EnvironmentCircuitBreaker;
+};
+
+export type EnvironmentCircuitBreaker = {
+	state: 'closed' | 'open' | 'half_open';
+	consecutiveFailures: number;
+	openUntil?: string;
+};
+
+export type EnvironmentConnectionTest = {
+	status: 'online' | 'offline' | 'error' | 'auth_failed';
+	message?: string;
+	latencyMs?: number;
+	agentVersion?: string;
+};
+
+export type EnvironmentRefreshResult = {
+	statuses: Record<string, EnvironmentConnectionTest['status']>;
+};
+
+export type EnvironmentMaintenance = {
+	enabled: boolean;
+	reason?: string;
 };
 
 export interface CreateEnvironmentDTO {
@@ -44,6 +71,7 @@ export interface UpdateEnvironmentDTO {
 	isEdge?: boolean;
 	bootstrapToken?: string;
 	regenerateApiKey?: boolean;
+	pinned?: boolean;
 }
 
 export interface DeploymentSnippetFile {
@@ -10,7 +10,8 @@ export type NotificationProvider =
 	| 'pushover'
 	| 'gotify'
 	| 'matrix'
-	| 'generic';
+	| 'generic'
+	| 'webhook';
 export type EmailTLSMode = 'none' | 'starttls' | 'ssl';
 export type EmailAuthMode = 'none' | 'auto' | 'plain' | 'login' | 'crammd5';
 
@@ -30,6 +31,16 @@ export interface TestNotificationResponse {
 	error?: string;
 }
 
+export interface NotificationProviderTestResult {
+	provider: NotificationProvider;
+	success: boolean;
+	error?: string;
+	warning?: string;
+	title?: string;
+	body: string;
+	html?: string;
+}
+
 // --- Provider keys (source of truth, alphabetical) ---
 
 export const NOTIFICATION_PROVIDER_KEYS = [
@@ -42,7 +53,8 @@ export const NOTIFICATION_PROVIDER_KEYS = [
 	'pushover',
 	'signal',
 	'slack',
-	'telegram'
+	'telegram',
+	'webhook'
 ] as const;
 export type NotificationProviderKey = (typeof NOTIFICATION_PROVIDER_KEYS)[number];
 
@@ -55,6 +67,11 @@ export interface BaseProviderFormValues {
 	eventVulnerabilityFound: boolean;
 	eventPruneReport: boolean;
 	eventAutoHeal: boolean;
+	eventContainerDie: boolean;
+	eventContainerOom: boolean;
+	eventContainerUnhealthy: boolean;
+	eventContainerRestartLoop: boolean;
+	messageTemplate: string;
 }
 
 export interface DiscordFormValues extends BaseProviderFormValues {
@@ -156,6 +173,12 @@ export interface GenericFormValues extends BaseProviderFormValues {
 	customHeaders: string;
 }
 
+export interface WebhookFormValues extends BaseProviderFormValues {
+	url: string;
+	secret: string;
+	customHeaders: string;
+}
+
 export type ProviderFormValuesMap = {
 	discord: DiscordFormValues;
 	email: EmailFormValues;
@@ -167,14 +190,27 @@ export type ProviderFormValuesMap = {
 	gotify: GotifyFormValues;
 	matrix: MatrixFormValues;
 	generic: GenericFormValues;
+	webhook: WebhookFormValues;
 };
 
 // --- Settings <-> form-values conversion ---
 
 type ProviderConfig = Record<string, unknown>;
 type ProviderEvents = Partial<
-	Record<'image_update' | 'container_update' | 'vulnerability_found' | 'prune_report' | 'auto_heal', boolean>
+	Record<
+		| 'image_update'
+		| 'container_update'
+		| 'vulnerability_found'
+		| 'prune_report'
+		| 'auto_heal'
+		| 'container_die'
+		| 'container_oom'
+		| 'container_unhealthy'
+		| 'container_restart_loop',
+		boolean
+	>
 >;
+type EventFlagFormValues = Omit<BaseProviderFormValues, 'enabled' | 'messageTemplate'>;
 
 function getConfig(settings?: NotificationSettings): ProviderConfig {
 	return (settings?.config ?? {}) as ProviderConfig;
@@ -212,18 +248,55 @@ function getStringRecord(config: ProviderConfig, key: string): Record<string, st
 	);
 }
 
-function eventFlagsToFormValues(
-	events: ProviderEvents
-): Pick<
-	BaseProviderFormValues,
-	'eventImageUpdate' | 'eventContainerUpdate' | 'eventVulnerabilityFound' | 'eventPruneReport' | 'eventAutoHeal'
-> {
+// Custom headers are edited as a comma-separated list of `Key:Value` pairs.
+function formatCustomHeaders(headers: Record<string, string>): string {
+	return Object.entries(headers)
+		.map(([key, value]) => `${key}:${value}`)
+		.join(', ');
+}
+
+function parseCustomHeaders(value: string): Record<string, string> {
+	const customHeaders: Record<string, string> = {};
+	if (!value) return customHeaders;
+	const headerPairs = value
+		.split(',')
+		.map((h) => h.trim())
+		.filter((h) => h.length > 0);
+	for (const pair of headerPairs) {
+		const [key, ...valueParts] = pair.split(':');
+		if (key && valueParts.length > 0) {
+			customHeaders[key.trim()] = valueParts.join(':').trim();
+		}
+	}
+	return customHeaders;
+}
+
+function eventFlagsToFormValues(events: ProviderEvents): EventFlagFormValues {
 	return {
 		eventImageUpdate: events['image_update'] ?? true,
 		eventContainerUpdate: events['container_update'] ?? true,
 		eventVulnerabilityFound: events['vulnerability_found'] ?? true,
 		eventPruneReport: events['prune_report'] ?? true,
-		eventAutoHeal: events['auto_heal'] ?? true
+		eventAutoHeal: events['auto_heal'] ?? true,
+		// Container lifecycle events are opt-in.
+		eventContainerDie: events['container_die'] ?? false,
+		eventContainerOom: events['container_oom'] ?? false,
+		eventContainerUnhealthy: events['container_unhealthy'] ?? false,
+		eventContainerRestartLoop: events['container_restart_loop'] ?? false
+	};
+}
+
+function formValuesToEventFlags(values: EventFlagFormValues): ProviderEvents {
+	return {
+		image_update: values.eventImageUpdate,
+		container_update: values.eventContainerUpdate,
+		vulnerability_found: values.eventVulnerabilityFound,
+		prune_report: values.eventPruneReport,
+		auto_heal: values.eventAutoHeal,
+		container_die: values.eventContainerDie,
+		container_oom: values.eventContainerOom,
+		container_unhealthy: values.eventContainerUnhealthy,
+		container_restart_loop: values.eventContainerRestartLoop
 	};
 }
 
@@ -236,7 +309,8 @@ export function discordSettingsToFormValues(settings?: NotificationSettings): Di
 		token: getString(cfg, 'token'),
 		username: getString(cfg, 'username', 'Arcane'),
 		avatarUrl: getString(cfg, 'avatarUrl'),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -253,7 +327,8 @@ export function emailSettingsToFormValues(settings?: NotificationSettings): Emai
 		toAddresses: getStringArray(cfg, 'toAddresses').join(', '),
 		tlsMode: getString(cfg, 'tlsMode', 'starttls') as EmailTLSMode,
 		authMode: getString(cfg, 'authMode', 'auto') as EmailAuthMode,
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -267,7 +342,8 @@ export function telegramSettingsToFormValues(settings?: NotificationSettings): T
 		preview: getBoolean(cfg, 'preview', true),
 		notification: getBoolean(cfg, 'notification', true),
 		title: getString(cfg, 'title'),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -284,7 +360,8 @@ export function signalSettingsToFormValues(settings?: NotificationSettings): Sig
 		source: getString(cfg, 'source'),
 		recipients: getStringArray(cfg, 'recipients').join(', '),
 		disableTls: getBoolean(cfg, 'disableTls', false),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -300,7 +377,8 @@ export function slackSettingsToFormValues(settings?: NotificationSettings): Slac
 		title: getString(cfg, 'title'),
 		channel: getString(cfg, 'channel'),
 		threadTs: getString(cfg, 'threadTs'),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -313,13 +391,8 @@ export function discordFormValuesToSettings(values: DiscordFormValues): Notifica
 			token: values.token,
 			username: values.username,
 			avatarUrl: values.avatarUrl,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -340,13 +413,8 @@ export function emailFormValuesToSettings(values: EmailFormValues): Notification
 				.filter((addr) => addr.length > 0),
 			tlsMode: values.tlsMode,
 			authMode: values.authMode,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -364,13 +432,8 @@ export function telegramFormValuesToSettings(values: TelegramFormValues): Notifi
 			preview: values.preview,
 			notification: values.notification,
 			title: values.title,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -391,13 +454,8 @@ export function signalFormValuesToSettings(values: SignalFormValues): Notificati
 				.map((recipient) => recipient.trim())
 				.filter((recipient) => recipient.length > 0),
 			disableTls: values.disableTls,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -414,13 +472,8 @@ export function slackFormValuesToSettings(values: SlackFormValues): Notification
 			title: values.title,
 			channel: values.channel,
 			threadTs: values.threadTs,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -443,7 +496,8 @@ export function ntfySettingsToFormValues(settings?: NotificationSettings): NtfyF
 		firebase: getBoolean(cfg, 'firebase', true),
 		disableTls: getBoolean(cfg, 'disableTls', false),
 		disableTlsVerification: getBoolean(cfg, 'disableTlsVerification', false),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -457,7 +511,8 @@ export function pushoverSettingsToFormValues(settings?: NotificationSettings): P
 		devices: getStringArray(cfg, 'devices').join(', '),
 		priority: Number(cfg['priority'] ?? 0),
 		title: getString(cfg, 'title'),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -473,7 +528,8 @@ export function gotifySettingsToFormValues(settings?: NotificationSettings): Got
 		priority: Number(cfg['priority'] ?? 0),
 		title: getString(cfg, 'title'),
 		disableTls: getBoolean(cfg, 'disableTls', false),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -488,19 +544,14 @@ export function matrixSettingsToFormValues(settings?: NotificationSettings): Mat
 		username: getString(cfg, 'username'),
 		password: getString(cfg, 'password'),
 		disableTlsVerification: getBoolean(cfg, 'disableTlsVerification', false),
-		...eventFlagsToFormValues(events)
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
 export function genericSettingsToFormValues(settings?: NotificationSettings): GenericFormValues {
 	const cfg = getConfig(settings);
 	const events = getEvents(cfg);
-	const customHeaders = getStringRecord(cfg, 'customHeaders');
-
-	const customHeadersStr = Object.entries(customHeaders)
-		.map(([key, value]) => `${key}:${value}`)
-		.join(', ');
-
 	return {
 		enabled: settings?.enabled ?? false,
 		webhookUrl: getString(cfg, 'webhookUrl'),
@@ -508,8 +559,9 @@ export function genericSettingsToFormValues(settings?: NotificationSettings): Ge
 		contentType: getString(cfg, 'contentType', 'application/json'),
 		titleKey: getString(cfg, 'titleKey', 'title'),
 		messageKey: getString(cfg, 'messageKey', 'message'),
-		customHeaders: customHeadersStr,
-		...eventFlagsToFormValues(events)
+		customHeaders: formatCustomHeaders(getStringRecord(cfg, 'customHeaders')),
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
 	};
 }
 
@@ -534,13 +586,8 @@ export function ntfyFormValuesToSettings(values: NtfyFormValues): NotificationSe
 			firebase: values.firebase,
 			disableTls: values.disableTls,
 			disableTlsVerification: values.disableTlsVerification,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -558,13 +605,8 @@ export function pushoverFormValuesToSettings(values: PushoverFormValues): Notifi
 				.filter((device) => device.length > 0),
 			priority: values.priority,
 			title: values.title,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -581,13 +623,8 @@ export function gotifyFormValuesToSettings(values: GotifyFormValues): Notificati
 			priority: values.priority,
 			title: values.title,
 			disableTls: values.disableTls,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -603,32 +640,26 @@ export function matrixFormValuesToSettings(values: MatrixFormValues): Notificati
 			username: values.username,
 			password: values.password,
 			disableTlsVerification: values.disableTlsVerification,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
 
-export function genericFormValuesToSettings(values: GenericFormValues): NotificationSettings {
-	const customHeaders: Record<string, string> = {};
-	if (values.customHeaders) {
-		const headerPairs = values.customHeaders
-			.split(',')
-			.map((h) => h.trim())
-			.filter((h) => h.length > 0);
-		for (const pair of headerPairs) {
-			const [key, ...valueParts] = pair.split(':');
-			if (key && valueParts.length > 0) {
-				customHeaders[key.trim()] = valueParts.join(':').trim();
-			}
-		}
-	}
+export function webhookSettingsToFormValues(settings?: NotificationSettings): WebhookFormValues {
+	const cfg = getConfig(settings);
+	const events = getEvents(cfg);
+	return {
+		enabled: settings?.enabled ?? false,
+		url: getString(cfg, 'url'),
+		secret: getString(cfg, 'secret'),
+		customHeaders: formatCustomHeaders(getStringRecord(cfg, 'customHeaders')),
+		...eventFlagsToFormValues(events),
+		messageTemplate: getString(cfg, 'messageTemplate')
+	};
+}
 
+export function genericFormValuesToSettings(values: GenericFormValues): NotificationSettings {
 	return {
 		provider: 'generic',
 		enabled: values.enabled,
@@ -638,14 +669,23 @@ export function genericFormValuesToSettings(values: GenericFormValues): Notifica
 			contentType: values.contentType,
 			titleKey: values.titleKey,
 			messageKey: values.messageKey,
-			customHeaders: customHeaders,
-			events: {
-				image_update: values.eventImageUpdate,
-				container_update: values.eventContainerUpdate,
-				vulnerability_found: values.eventVulnerabilityFound,
-				prune_report: values.eventPruneReport,
-				auto_heal: values.eventAutoHeal
-			}
+			customHeaders: parseCustomHeaders(values.customHeaders),
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
+		}
+	};
+}
+
+export function webhookFormValuesToSettings(values: WebhookFormValues): NotificationSettings {
+	return {
+		provider: 'webhook',
+		enabled: values.enabled,
+		config: {
+			url: values.url,
+			secret: values.secret,
+			customHeaders: parseCustomHeaders(values.customHeaders),
+			messageTemplate: values.messageTemplate,
+			events: formValuesToEventFlags(values)
 		}
 	};
 }
@@ -10,7 +10,13 @@ export type Settings = {
 	templatesDirectory: string;
 	followProjectSymlinks: boolean;
 	swarmStackSourcesDirectory: string;
+	swarmStackSourceHistoryLimit: number;
 	diskUsagePath: string;
+	systemGpuVendor?: 'auto' | 'nvidia' | 'amd' | 'intel' | 'jetson' | 'none';
+	systemNvidiaSmiPath?: string;
+	systemNvidiaSmiExtraFields?: string;
+	systemIntelGpuTopPath?: string;
+	systemTegrastatsPath?: string;
 	autoUpdate: boolean;
 	autoUpdateInterval: number;
 	autoUpdateExcludedContainers?: string;
@@ -20,15 +26,14 @@ export type Settings = {
 	environmentHealthInterval: number;
 	activityHistoryRetentionDays: number;
 	activityHistoryMaxEntries: number;
+	eventRetentionHours: number;
+	notificationLogRetentionDays: number;
 	maxConcurrentActivities: number;
 	defaultDeployPullPolicy: 'missing' | 'always' | 'never';
-	scheduledPruneEnabled?: boolean;
-	scheduledPruneInterval?: number;
-	scheduledPruneContainers?: boolean;
-	scheduledPruneImages?: boolean;
-	scheduledPruneVolumes?: boolean;
-	scheduledPruneNetworks?: boolean;
-	scheduledPruneBuildCache?: boolean;
+	defaultContainerCpuLimit?: number;
+	defaultContainerMemoryLimitMb?: number;
+	defaultContainerRestartPolicy?: '' | 'no' | 'always' | 'unless-stopped' | 'on-failure';
+	registryMirrors?: string;
 	pruneContainerMode?: 'none' | 'stopped' | 'olderThan';
 	pruneContainerUntil?: string;
 	pruneImageMode?: 'none' | 'dangling' | 'all' | 'olderThan';
@@ -45,6 +50,7 @@ export type Settings = {
 	autoHealMaxRestarts?: number;
 	autoHealRestartWindow?: number;
 	volumeBrowserHelperIdleTimeout?: number;
+	imageUpdateNotificationCooldown?: number;
 	maxImageUploadSize: number;
 	gitSyncMaxFiles: number;
 	gitSyncMaxTotalSizeMb: number;
@@ -154,7 +160,6 @@ export type JobSchedules = {
 	autoUpdateInterval: string;
 	dockerClientRefreshInterval: string;
 	pollingInterval: string;
-	scheduledPruneInterval: string;
 	vulnerabilityScanInterval: string;
 	autoHealInterval: string;
 };
@@ -69,8 +69,17 @@ export interface SystemStats {
 	cpuUsage: number;
 	memoryUsage: number;
 	memoryTotal: number;
+	swapUsed?: number;
+	swapTotal?: number;
+	load1?: number;
+	load5?: number;
+	load15?: number;
 	diskUsage?: number;
 	diskTotal?: number;
+	diskReadBytesPerSec?: number;
+	diskWriteBytesPerSec?: number;
+	netRxBytesPerSec?: number;
+	netTxBytesPerSec?: number;
 	cpuCount: number;
 	architecture: string;
 	platform: string;
@@ -84,6 +93,7 @@ export interface GPUStats {
 	index: number;
 	memoryUsed: number;
 	memoryTotal: number;
+	extra?: Record<string, string>;
 }
 
 // --- File browser ---
@@ -97,6 +107,10 @@ export interface FileEntry {
 	mode: string;
 	isSymlink: boolean;
 	linkTarget?: string;
+	owner?: string;
+	group?: string;
+	uid?: number;
+	gid?: number;
 }
 
 export interface FileContentResponse {
@@ -155,6 +155,18 @@ export interface SwarmServiceInspect {
 	nodes?: string[];
 	networkDetails?: Record<string, ServiceNetworkDetail>;
 	mounts?: SwarmServiceMount[];
+	updateConfig?: SwarmServiceRolloutConfig;
+	rollbackConfig?: SwarmServiceRolloutConfig;
+	updateOrder: 'stop-first' | 'start-first';
+}
+
+export interface SwarmServiceRolloutConfig {
+	parallelism?: number;
+	delay?: string;
+	failureAction?: 'pause' | 'continue' | 'rollback';
+	monitor?: string;
+	maxFailureRatio?: number;
+	order?: 'stop-first' | 'start-first';
 }
 
 export interface SwarmServiceCreateOptions {
@@ -209,16 +221,33 @@ export interface SwarmServiceCreateRequest {
 	options?: SwarmServiceCreateOptions;
 }
 
+export interface SwarmServiceFromComposeRequest {
+	compose: string;
+	name?: string;
+}
+
 export interface SwarmServiceUpdateRequest {
 	version: number;
 	spec: Record<string, unknown>;
 	options?: SwarmServiceUpdateOptions;
+	updateConfig?: SwarmServiceRolloutConfig;
+	rollbackConfig?: SwarmServiceRolloutConfig;
 }
 
 export interface SwarmServiceScaleRequest {
 	replicas: number;
 }
 
+export interface SwarmServiceResourceValues {
+	nanoCpus?: number;
+	memoryBytes?: number;
+}
+
+export interface SwarmServiceResourcesRequest {
+	limits?: SwarmServiceResourceValues;
+	reservations?: SwarmServiceResourceValues;
+}
+
 export interface SwarmServiceCreateResponse {
 	id: string;
 	warnings?: string[];
@@ -228,6 +257,12 @@ export interface SwarmServiceUpdateResponse {
 	warnings?: string[];
 }
 
+export interface SwarmServiceImagePinResponse {
+	image: string;
+	digest: string;
+	warnings?: string[];
+}
+
 export interface SwarmTaskSummary {
 	id: string;
 	name: string;
@@ -319,6 +354,16 @@ export interface SwarmNodeUpdateRequest {
 	availability?: 'active' | 'pause' | 'drain';
 }
 
+export interface SwarmNodeLabelsPatchRequest {
+	set?: Record<string, string>;
+	remove?: string[];
+}
+
+export interface SwarmNodeLabelsResponse {
+	nodeId: string;
+	labels: Record<string, string>;
+}
+
 export interface SwarmStackSummary {
 	id: string;
 	name: string;
@@ -336,6 +381,25 @@ export interface SwarmStackInspect {
 	updatedAt: string;
 }
 
+export interface SwarmStackRemovalResource {
+	id: string;
+	name: string;
+}
+
+export interface SwarmStackKeptNetwork extends SwarmStackRemovalResource {
+	reason: 'ingress' | 'shared';
+	usedBy?: string[];
+}
+
+export interface SwarmStackRemovalPlan {
+	stackName: string;
+	services: SwarmStackRemovalResource[];
+	configs: SwarmStackRemovalResource[];
+	secrets: SwarmStackRemovalResource[];
+	networks: SwarmStackRemovalResource[];
+	keptNetworks: SwarmStackKeptNetwork[];
+}
+
 export interface SwarmStackDeployRequest {
 	name: string;
 	composeContent: string;
@@ -347,6 +411,15 @@ export interface SwarmStackDeployRequest {
 
 export interface SwarmStackDeployResponse {
 	name: string;
+	images?: SwarmStackServiceImage[];
+}
+
+export interface SwarmStackServiceImage {
+	service: string;
+	image: string;
+	resolvedImage: string;
+	digest?: string;
+	pinned?: boolean;
 }
 
 export interface SwarmStackRenderConfigRequest {
@@ -377,6 +450,38 @@ export interface SwarmStackSourceUpdateRequest {
 	envContent?: string;
 }
 
+export interface SwarmStackImportResult {
+	name: string;
+	deployed: boolean;
+	error?: string;
+}
+
+export interface SwarmStackImportResponse {
+	dryRun: boolean;
+	stacks: SwarmStackImportResult[];
+}
+
+export interface SwarmResourceCapacity {
+	total: number;
+	allocated: number;
+	available: number;
+}
+
+export interface SwarmNodeResources {
+	nodeId: string;
+	hostname: string;
+	schedulable: boolean;
+	nanoCpus: SwarmResourceCapacity;
+	memoryBytes: SwarmResourceCapacity;
+	runningTasks: number;
+}
+
+export interface SwarmClusterResources {
+	nanoCpus: SwarmResourceCapacity;
+	memoryBytes: SwarmResourceCapacity;
+	nodes: SwarmNodeResources[];
+}
+
 export interface SwarmInfo {
 	id: string;
 	createdAt: string;
@@ -510,6 +615,27 @@ export interface SwarmSecretUpdateRequest {
 	spec: Record<string, unknown>;
 }
 
+export interface SwarmServiceMountUsage {
+	serviceId: string;
+	serviceName: string;
+	target?: string;
+	uid?: string;
+	gid?: string;
+	mode?: number;
+}
+
+export interface SwarmSecretRotateRequest {
+	/** Base64-encoded secret value. */
+	data: string;
+}
+
+export interface SwarmSecretRotateResponse {
+	secret: SwarmSecretSummary;
+	previousSecretId: string;
+	previousSecretRemoved: boolean;
+	updatedServices: { id: string; name: string }[];
+}
+
 // --- Compose projects ---
 
 export interface ServicePort {
@@ -628,6 +754,33 @@ export interface ProjectStatusCounts {
 	archivedProjects: number;
 }
 
+export interface ProjectOrphanContainer {
+	id: string;
+	name: string;
+	service: string;
+	image?: string;
+	state: string;
+}
+
+export interface ProjectOrphanNetwork {
+	id: string;
+	name: string;
+	network?: string;
+}
+
+export interface ProjectOrphans {
+	projectId: string;
+	projectName: string;
+	containers: ProjectOrphanContainer[];
+	networks: ProjectOrphanNetwork[];
+}
+
+export interface ProjectOrphansPruneResult {
+	containersRemoved: string[];
+	networksRemoved: string[];
+	errors?: string[];
+}
+
 // --- Compose templates ---
 
 export interface TemplateRegistry {
@@ -636,15 +789,27 @@ export interface TemplateRegistry {
 	url: string;
 	enabled: boolean;
 	description: string;
+	authType: TemplateRegistryAuthType;
+	username?: string;
+	hasCredential: boolean;
 	createdAt?: string;
 	updatedAt?: string;
 	lastFetchError?: string;
 }
 
+export type TemplateRegistryAuthType = 'none' | 'bearer' | 'basic';
+
+export interface TemplateRegistryAuth {
+	authType?: TemplateRegistryAuthType;
+	username?: string;
+	token?: string;
+}
+
 export interface Template {
 	id: string;
 	name: string;
 	description: string;
+	category?: string;
 	content: string;
 	envContent?: string;
 	isCustom: boolean;
@@ -661,6 +826,7 @@ export interface Template {
 		iconUrl?: string;
 		updatedAt?: string;
 	};
+	sourceUrl?: string;
 	createdAt: string;
 	updatedAt: string;
 }
@@ -678,6 +844,33 @@ export interface TemplateContentData {
 	envVariables: EnvVariable[];
 }
 
+export interface TemplateValidationResult {
+	valid: boolean;
+	variables: string[];
+	missing: string[];
+	unused: string[];
+}
+
+export interface TemplateUpdateStatus {
+	templateId: string;
+	name: string;
+	sourceUrl: string;
+	hasUpdate: boolean;
+	error?: string;
+}
+
+export interface TemplateDeployRequest {
+	environmentId?: string;
+	stackName?: string;
+	variables?: Record<string, string>;
+}
+
+export interface TemplateDeployResult {
+	projectId: string;
+	projectName: string;
+	environmentId: string;
+}
+
 export interface RemoteTemplate {
 	id: string;
 	name: string;
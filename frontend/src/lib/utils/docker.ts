@@ -433,6 +433,7 @@ export function getEnvironmentStatusVariant(status: EnvironmentStatus): 'green'
 		case 'standby':
 			return 'blue';
 		case 'pending':
+		case 'auth_failed':
 			return 'amber';
 		default:
 			return 'red';
@@ -0,0 +1,36 @@
+import { m } from '#lib/paraglide/messages';
+import type { SwarmStackRemovalPlan, SwarmStackRemovalResource } from '#lib/types/swarm';
+
+function names(resources: SwarmStackRemovalResource[]): string {
+	return resources.map((resource) => resource.name).join(', ');
+}
+
+// describeStackRemovalPlan renders a removal plan as the confirm dialog message,
+// listing what will be deleted and which networks are kept.
+export function describeStackRemovalPlan(plan: SwarmStackRemovalPlan): string {
+	const lines = [m.swarm_stack_removal_intro()];
+	const sections: [string, SwarmStackRemovalResource[]][] = [
+		[m.services(), plan.services],
+		[m.swarm_configs_title(), plan.configs],
+		[m.swarm_secrets_title(), plan.secrets],
+		[m.resource_networks_cap(), plan.networks]
+	];
+	for (const [label, resources] of sections) {
+		if (resources.length > 0) {
+			lines.push(`• ${label} (${resources.length}): ${names(resources)}`);
+		}
+	}
+	if (lines.length === 1) {
+		lines.push(m.swarm_stack_removal_source_only());
+	}
+
+	for (const network of plan.keptNetworks) {
+		lines.push(
+			network.reason === 'shared'
+				? m.swarm_stack_removal_kept_shared({ name: network.name, usedBy: (network.usedBy ?? []).join(', ') })
+				: m.swarm_stack_removal_kept_ingress({ name: network.name })
+		);
+	}
+
+	return lines.join('\n');
+}
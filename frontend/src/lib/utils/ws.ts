@@ -1,5 +1,6 @@
 import type { SystemStats } from '#lib/types/shared';
 import type { Diagnostics, LogEntry } from '#lib/types/diagnostics';
+import type { DockerEvent, DockerEventFilter } from '#lib/types/docker';
 
 export interface ReconnectWSOptions<T> {
 	buildUrl: () => string | Promise<string>;
@@ -11,6 +12,8 @@ export interface ReconnectWSOptions<T> {
 	maxBackoff?: number;
 	autoConnect?: boolean;
 	shouldReconnect?: () => boolean;
+	/** Send "background"/"foreground" when the page visibility changes so the server can slow updates. */
+	reportVisibility?: boolean;
 }
 
 export class ReconnectingWebSocket<T = unknown> {
@@ -21,6 +24,7 @@ export class ReconnectingWebSocket<T = unknown> {
 	private opts: ReconnectWSOptions<T>;
 	private connecting = false;
 	private reconnectTimer: ReturnType<typeof setTimeout> | null = null;
+	private visibilityListener: (() => void) | null = null;
 
 	constructor(opts: ReconnectWSOptions<T>) {
 		this.opts = opts;
@@ -34,9 +38,33 @@ export class ReconnectingWebSocket<T = unknown> {
 		}
 		this.closed = false;
 		this.attempt = 0;
+		this.watchVisibility();
 		await this.connectOnce();
 	}
 
+	send(data: string) {
+		if (this.ws?.readyState === WebSocket.OPEN) {
+			this.ws.send(data);
+		}
+	}
+
+	private sendVisibility() {
+		if (typeof document === 'undefined') return;
+		this.send(document.visibilityState === 'hidden' ? 'background' : 'foreground');
+	}
+
+	private watchVisibility() {
+		if (!this.opts.reportVisibility || this.visibilityListener || typeof document === 'undefined') return;
+		this.visibilityListener = () => this.sendVisibility();
+		document.addEventListener('visibilitychange', this.visibilityListener);
+	}
+
+	private unwatchVisibility() {
+		if (!this.visibilityListener) return;
+		document.removeEventListener('visibilitychange', this.visibilityListener);
+		this.visibilityListener = null;
+	}
+
 	async connectOnce() {
 		if (this.closed || this.connecting) return;
 
@@ -74,6 +102,9 @@ export class ReconnectingWebSocket<T = unknown> {
 			if (socket !== this.ws) return;
 			this.attempt = 0;
 			this.connecting = false;
+			if (this.opts.reportVisibility && document.visibilityState === 'hidden') {
+				this.sendVisibility();
+			}
 			this.opts.onOpen?.();
 		};
 
@@ -132,6 +163,7 @@ export class ReconnectingWebSocket<T = unknown> {
 	close() {
 		this.closed = true;
 		this.attempt = 0;
+		this.unwatchVisibility();
 
 		if (this.reconnectTimer) {
 			clearTimeout(this.reconnectTimer);
@@ -147,6 +179,7 @@ export class ReconnectingWebSocket<T = unknown> {
 	closeAndWait(timeoutMs = 2000) {
 		this.closed = true;
 		this.attempt = 0;
+		this.unwatchVisibility();
 
 		if (this.reconnectTimer) {
 			clearTimeout(this.reconnectTimer);
@@ -208,6 +241,37 @@ export function createStatsWebSocket(opts: {
 		onOpen: opts.onOpen,
 		onClose: opts.onClose,
 		onError: opts.onError,
+		maxBackoff: opts.maxBackoff,
+		reportVisibility: true
+	});
+}
+
+export function createDockerEventsWebSocket(opts: {
+	getEnvId: () => string;
+	filter?: DockerEventFilter;
+	onMessage: (data: DockerEvent) => void;
+	onOpen?: () => void;
+	onClose?: () => void;
+	onError?: (err: Event | Error) => void;
+	maxBackoff?: number;
+}) {
+	const buildUrl = () => {
+		const envId = opts.getEnvId() || '0';
+		const protocol = location.protocol === 'https:' ? 'wss' : 'ws';
+		const params = new URLSearchParams();
+		for (const type of opts.filter?.types ?? []) params.append('type', type);
+		for (const label of opts.filter?.labels ?? []) params.append('label', label);
+		const query = params.toString();
+		return `${protocol}://${location.host}/api/environments/${envId}/ws/system/events${query ? `?${query}` : ''}`;
+	};
+
+	return new ReconnectingWebSocket<DockerEvent>({
+		buildUrl,
+		parseMessage: (evt) => JSON.parse(evt.data as string) as DockerEvent,
+		onMessage: opts.onMessage,
+		onOpen: opts.onOpen,
+		onClose: opts.onClose,
+		onError: opts.onError,
 		maxBackoff: opts.maxBackoff
 	});
 }
@@ -11,6 +11,10 @@ export const environmentFormSchema = z
 		autoInjectEnv: z.boolean(),
 		followProjectSymlinks: z.boolean(),
 		defaultDeployPullPolicy: z.enum(['missing', 'always', 'never']),
+		defaultContainerCpuLimit: z.coerce.number().nonnegative(),
+		defaultContainerMemoryLimitMb: z.coerce.number().int().nonnegative(),
+		defaultContainerRestartPolicy: z.enum(['', 'no', 'always', 'unless-stopped', 'on-failure']),
+		registryMirrors: z.string(),
 		defaultShell: z.string(),
 		projectsDirectory: z.string(),
 		templatesDirectory: z.string(),
@@ -21,7 +25,6 @@ export const environmentFormSchema = z
 		gitSyncMaxTotalSizeMb: z.coerce.number().int().nonnegative(),
 		gitSyncMaxBinarySizeMb: z.coerce.number().int().nonnegative(),
 		baseServerUrl: z.string(),
-		scheduledPruneEnabled: z.boolean(),
 		pruneContainerMode: z.enum(['none', 'stopped', 'olderThan']),
 		pruneContainerUntil: z.string(),
 		pruneImageMode: z.enum(['none', 'dangling', 'all', 'olderThan']),
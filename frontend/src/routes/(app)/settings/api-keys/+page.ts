@@ -1,10 +1,16 @@
 import { apiKeyService } from '#lib/services/api-key-service';
 import { roleService } from '#lib/services/role-service';
+import { environmentManagementService } from '#lib/services/env-mgmt-service';
 import { queryKeys } from '#lib/query/query-keys';
 import type { SearchPaginationSortRequest } from '#lib/types/shared';
 import { resolveInitialTableRequest } from '#lib/utils/tables';
 import type { PageLoad } from './$types';
 
+const environmentListOptions: SearchPaginationSortRequest = {
+	pagination: { page: 1, limit: 1000 },
+	sort: { column: 'name', direction: 'asc' }
+};
+
 export const load: PageLoad = async ({ parent }) => {
 	const { queryClient } = await parent();
 
@@ -19,7 +25,7 @@ export const load: PageLoad = async ({ parent }) => {
 		}
 	} satisfies SearchPaginationSortRequest);
 
-	const [apiKeys, permissionsManifest] = await Promise.all([
+	const [apiKeys, permissionsManifest, environmentsPage] = await Promise.all([
 		queryClient.fetchQuery({
 			queryKey: queryKeys.apiKeys.list(apiKeyRequestOptions),
 			queryFn: () => apiKeyService.getApiKeys(apiKeyRequestOptions)
@@ -28,12 +34,17 @@ export const load: PageLoad = async ({ parent }) => {
 			queryKey: ['permissions', 'manifest'],
 			queryFn: () => roleService.getPermissionsManifest(),
 			staleTime: Infinity
+		}),
+		queryClient.fetchQuery({
+			queryKey: queryKeys.environments.list(environmentListOptions),
+			queryFn: () => environmentManagementService.getEnvironments(environmentListOptions)
 		})
 	]);
 
 	return {
 		apiKeys,
 		apiKeyRequestOptions,
-		permissionsManifest
+		permissionsManifest,
+		environments: environmentsPage.data
 	};
 };
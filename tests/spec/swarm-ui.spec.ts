@@ -1,26 +1,28 @@
 import { test, expect, type Page } from '@playwright/test';
 
 async function mockConfigs(page: Page) {
-	await page.route('**/api/environments/*/swarm/configs', async (route) => {
+	await page.route(/\/api\/environments\/[^/]+\/swarm\/configs(\?.*)?$/, async (route) => {
 		await route.fulfill({
 			status: 200,
 			contentType: 'application/json',
 			body: JSON.stringify({
 				success: true,
-				data: []
+				data: [],
+				pagination: { totalPages: 0, totalItems: 0, currentPage: 1, itemsPerPage: -1 }
 			})
 		});
 	});
 }
 
 async function mockSecrets(page: Page) {
-	await page.route('**/api/environments/*/swarm/secrets', async (route) => {
+	await page.route(/\/api\/environments\/[^/]+\/swarm\/secrets(\?.*)?$/, async (route) => {
 		await route.fulfill({
 			status: 200,
 			contentType: 'application/json',
 			body: JSON.stringify({
 				success: true,
-				data: []
+				data: [],
+				pagination: { totalPages: 0, totalItems: 0, currentPage: 1, itemsPerPage: -1 }
 			})
 		});
 	});
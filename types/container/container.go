@@ -2,6 +2,7 @@ package container
 
 import (
 	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -109,6 +110,30 @@ type EndpointSettingsCreate struct {
 	Aliases []string `json:"aliases,omitempty"`
 }
 
+// NetworkAttachmentCreate attaches a new container to one network, like an
+// entry under a compose service's networks key.
+type NetworkAttachmentCreate struct {
+	// Name of the network to attach to.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Aliases for the container on this network.
+	//
+	// Required: false
+	Aliases []string `json:"aliases,omitempty"`
+
+	// IPv4Address is a static IPv4 address on this network.
+	//
+	// Required: false
+	IPv4Address string `json:"ipv4Address,omitempty"`
+
+	// IPv6Address is a static IPv6 address on this network.
+	//
+	// Required: false
+	IPv6Address string `json:"ipv6Address,omitempty"`
+}
+
 // NetworkingConfigCreate represents network configuration for container creation.
 type NetworkingConfigCreate struct {
 	// EndpointsConfig maps network names to endpoint settings.
@@ -244,6 +269,13 @@ type Create struct {
 	// Required: false
 	Networks []string `json:"networks,omitempty"`
 
+	// NetworkAttachments lists the networks to connect to with per-network
+	// aliases and static addresses. The first entry is the primary network. It
+	// takes precedence over NetworkingConfig and Networks.
+	//
+	// Required: false
+	NetworkAttachments []NetworkAttachmentCreate `json:"networkAttachments,omitempty"`
+
 	// RestartPolicy for the container.
 	//
 	// Required: false
@@ -273,6 +305,12 @@ type Create struct {
 	//
 	// Required: false
 	Credentials []containerregistry.Credential `json:"credentials,omitempty"`
+
+	// SkipPortCheck skips the check that published host ports are free before
+	// the image is pulled, e.g. when binding to an address the check cannot see.
+	//
+	// Required: false
+	SkipPortCheck bool `json:"skipPortCheck,omitempty"`
 }
 
 // CommitRequest is used to create an image from a container's current filesystem.
@@ -308,6 +346,329 @@ type CommitRequest struct {
 	NoPause bool `json:"noPause,omitempty" doc:"Do not pause the container during commit"`
 }
 
+// WriteFileRequest replaces a text file inside a container.
+type WriteFileRequest struct {
+	// Content is the new file content.
+	//
+	// Required: true
+	Content string `json:"content" doc:"New file content"`
+}
+
+// BatchActionRequest lists the containers a batch action applies to.
+type BatchActionRequest struct {
+	// IDs are the IDs of the containers to act on.
+	//
+	// Required: true
+	IDs []string `json:"ids" minItems:"1" doc:"Container IDs to act on"`
+
+	// Force removes running containers when the action is remove.
+	//
+	// Required: false
+	Force bool `json:"force,omitempty" doc:"Force removal of running containers (remove only)"`
+}
+
+// BatchActionResult is the outcome of a batch action for one container.
+type BatchActionResult struct {
+	// ID is the container ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Success reports whether the action succeeded for this container.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// Error describes why the action failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// BatchActionResponse summarizes a batch action across all requested containers.
+type BatchActionResponse struct {
+	// Action is the action that was run.
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// Succeeded is the number of containers the action succeeded for.
+	//
+	// Required: true
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of containers the action failed for.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Results holds one entry per requested container, in request order.
+	//
+	// Required: true
+	Results []BatchActionResult `json:"results"`
+}
+
+// RecreateRequest lists the overrides applied when recreating a container from its
+// existing configuration.
+type RecreateRequest struct {
+	// Image replaces the container's image reference, such as a new tag.
+	//
+	// Required: false
+	Image string `json:"image,omitempty" doc:"Image reference to recreate the container with (defaults to the current image)"`
+
+	// Env sets environment variables, replacing existing values of the same name.
+	//
+	// Required: false
+	Env map[string]string `json:"env,omitempty" doc:"Environment variables to set on the new container"`
+}
+
+// StopRequest overrides the grace period and signal used to stop or restart a
+// container.
+type StopRequest struct {
+	// Timeout is the number of seconds to wait before killing the container.
+	//
+	// Required: false
+	Timeout *int `json:"timeout,omitempty" minimum:"0" doc:"Seconds to wait before killing the container. Omit to keep the default (30s for stop, the daemon default for restart)."`
+
+	// Signal is sent instead of the container's configured stop signal.
+	//
+	// Required: false
+	Signal string `json:"signal,omitempty" doc:"Signal to send instead of the container's stop signal (for example SIGINT, SIGQUIT)"`
+}
+
+// EnvVar is one environment variable of a container.
+type EnvVar struct {
+	// Key is the variable name.
+	//
+	// Required: true
+	Key string `json:"key" doc:"Variable name"`
+
+	// Value is the variable value, or a mask when Masked is set.
+	//
+	// Required: true
+	Value string `json:"value" doc:"Variable value, masked for secret-looking names unless revealed"`
+
+	// Masked indicates Value was replaced because the name looks like a secret.
+	//
+	// Required: false
+	Masked bool `json:"masked,omitempty" doc:"True when the value is masked"`
+}
+
+// EnvUpdate replaces a container's environment. A masked value sent back
+// unchanged keeps the container's current value for that key.
+type EnvUpdate struct {
+	// Env is the complete set of variables for the recreated container, in order.
+	//
+	// Required: true
+	Env []EnvVar `json:"env" doc:"Complete environment for the recreated container"`
+}
+
+// Top is the process list of a running container, as reported by ps on the
+// Docker host. PIDs are host PIDs, not the PIDs seen inside the container.
+type Top struct {
+	// Titles are the ps column headers.
+	//
+	// Required: true
+	Titles []string `json:"titles" doc:"ps column headers"`
+
+	// Processes holds one row per process, with a value for each title.
+	//
+	// Required: true
+	Processes [][]string `json:"processes" doc:"One row per process, aligned with titles"`
+}
+
+// Changes lists the paths a container has added, changed or deleted in its
+// writable layer relative to its image. Volumes and bind mounts are not
+// included.
+type Changes struct {
+	// Added are paths created in the container layer.
+	//
+	// Required: true
+	Added []string `json:"added" doc:"Paths created in the container layer"`
+
+	// Changed are image paths modified in the container layer.
+	//
+	// Required: true
+	Changed []string `json:"changed" doc:"Image paths modified in the container layer"`
+
+	// Deleted are image paths removed in the container layer.
+	//
+	// Required: true
+	Deleted []string `json:"deleted" doc:"Image paths removed in the container layer"`
+}
+
+// ExecRunRequest runs a single command in a container without a TTY.
+type ExecRunRequest struct {
+	// Cmd is the command and its arguments. It is executed directly, not
+	// through a shell.
+	//
+	// Required: true
+	Cmd []string `json:"cmd" minItems:"1" doc:"Command and arguments, executed without a shell"`
+
+	// Env sets extra environment variables as KEY=value pairs.
+	//
+	// Required: false
+	Env []string `json:"env,omitempty" doc:"Extra environment variables as KEY=value"`
+
+	// WorkingDir is the directory the command runs in. Defaults to the
+	// container's working directory.
+	//
+	// Required: false
+	WorkingDir string `json:"workingDir,omitempty" doc:"Working directory for the command"`
+
+	// User runs the command as this user or UID. Defaults to the container's user.
+	//
+	// Required: false
+	User string `json:"user,omitempty" doc:"User or UID to run the command as"`
+
+	// TimeoutSeconds bounds how long to wait for the command. Defaults to 30.
+	//
+	// Required: false
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" minimum:"0" maximum:"300" doc:"Seconds to wait for the command to finish (default 30, max 300)"`
+}
+
+// ExecRunResult is the captured outcome of a non-interactive exec.
+type ExecRunResult struct {
+	// ExitCode is the command's exit status.
+	//
+	// Required: true
+	ExitCode int `json:"exitCode" doc:"Exit status of the command"`
+
+	// Stdout is the captured standard output.
+	//
+	// Required: true
+	Stdout string `json:"stdout" doc:"Captured standard output"`
+
+	// Stderr is the captured standard error.
+	//
+	// Required: true
+	Stderr string `json:"stderr" doc:"Captured standard error"`
+
+	// Truncated reports that stdout or stderr exceeded the capture limit and
+	// was cut short.
+	//
+	// Required: true
+	Truncated bool `json:"truncated" doc:"True when stdout or stderr exceeded the capture limit"`
+
+	// DurationMs is how long the command ran, in milliseconds.
+	//
+	// Required: true
+	DurationMs int64 `json:"durationMs" doc:"Command run time in milliseconds"`
+}
+
+// LogSearchRequest searches a container's full log for lines matching a
+// regular expression.
+type LogSearchRequest struct {
+	// Pattern is the RE2 regular expression matched against each log message.
+	//
+	// Required: true
+	Pattern string `json:"pattern" minLength:"1" doc:"RE2 regular expression matched against each log message"`
+
+	// CaseInsensitive matches the pattern regardless of case.
+	//
+	// Required: false
+	CaseInsensitive bool `json:"caseInsensitive,omitempty" doc:"Match regardless of case"`
+
+	// Since limits the search to lines logged at or after this time.
+	//
+	// Required: false
+	Since string `json:"since,omitempty" doc:"Only search lines logged since this timestamp or relative duration"`
+
+	// Until limits the search to lines logged before this time.
+	//
+	// Required: false
+	Until string `json:"until,omitempty" doc:"Only search lines logged before this timestamp or relative duration"`
+
+	// MaxMatches caps the number of matches returned. Zero uses the server default.
+	//
+	// Required: false
+	MaxMatches int `json:"maxMatches,omitempty" minimum:"0" maximum:"10000" doc:"Maximum number of matches to return"`
+}
+
+// LogSearchMatch is one log line that matched a search.
+type LogSearchMatch struct {
+	// Line is the 1-based position of the line within the searched range.
+	//
+	// Required: true
+	Line int `json:"line" doc:"1-based line number within the searched range"`
+
+	// Timestamp is when Docker recorded the line.
+	//
+	// Required: false
+	Timestamp string `json:"timestamp,omitempty" doc:"Time Docker recorded the line"`
+
+	// Text is the log message, truncated when very long.
+	//
+	// Required: true
+	Text string `json:"text" doc:"Log message"`
+}
+
+// LogSearchResult holds the matches of a log search.
+type LogSearchResult struct {
+	// Matches lists matching lines in log order.
+	//
+	// Required: true
+	Matches []LogSearchMatch `json:"matches" doc:"Matching lines in log order"`
+
+	// LinesScanned is the number of lines read before the search stopped.
+	//
+	// Required: true
+	LinesScanned int `json:"linesScanned" doc:"Number of lines read before the search stopped"`
+
+	// Truncated reports that the search stopped at the match cap.
+	//
+	// Required: true
+	Truncated bool `json:"truncated" doc:"True when the search stopped at the match cap"`
+
+	// TimedOut reports that the search stopped at its time limit.
+	//
+	// Required: true
+	TimedOut bool `json:"timedOut" doc:"True when the search stopped at its time limit"`
+}
+
+// ResourceUpdate lists the settings changed in place on an existing container
+// through Docker's container update API, without recreating it. Nil fields are
+// left unchanged.
+type ResourceUpdate struct {
+	// RestartPolicy replaces the container's restart policy.
+	//
+	// Required: false
+	RestartPolicy *RestartPolicyCreate `json:"restartPolicy,omitempty" doc:"Restart policy (no, always, unless-stopped or on-failure)"`
+
+	// Memory is the memory limit in bytes.
+	//
+	// Required: false
+	Memory *int64 `json:"memory,omitempty" doc:"Memory limit in bytes (at least 6 MiB)"`
+
+	// MemorySwap limits memory plus swap in bytes; -1 allows unlimited swap.
+	//
+	// Required: false
+	MemorySwap *int64 `json:"memorySwap,omitempty" doc:"Memory plus swap limit in bytes (-1 for unlimited swap)"`
+
+	// MemoryReservation is the soft memory limit in bytes.
+	//
+	// Required: false
+	MemoryReservation *int64 `json:"memoryReservation,omitempty" doc:"Soft memory limit in bytes (at least 6 MiB)"`
+
+	// NanoCPUs is the CPU quota in units of 1e-9 CPUs.
+	//
+	// Required: false
+	NanoCPUs *int64 `json:"nanoCpus,omitempty" doc:"CPU quota in billionths of a CPU"`
+
+	// CPUShares is the relative CPU weight.
+	//
+	// Required: false
+	CPUShares *int64 `json:"cpuShares,omitempty" doc:"Relative CPU weight (at least 2)"`
+}
+
+// ResourceUpdateResult is the outcome of an in-place container update.
+type ResourceUpdateResult struct {
+	// Warnings are the warnings Docker reported while applying the update.
+	//
+	// Required: false
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 // CommitResult identifies the image created by a container commit.
 type CommitResult struct {
 	ID string `json:"id"`
@@ -779,6 +1140,42 @@ type Summary struct {
 	//
 	// Required: false
 	RedeployDisabled bool `json:"redeployDisabled,omitempty"`
+
+	// GPUs describes the GPU device requests of the container. Only populated when
+	// the list is requested with GPU details, since it requires inspecting each container.
+	//
+	// Required: false
+	GPUs *GPUAssignment `json:"gpus,omitempty"`
+
+	// Health is the container's health-check status, without the probe log. Only
+	// populated when the list is requested with health details and the container
+	// defines a HEALTHCHECK, since it requires inspecting each container.
+	//
+	// Required: false
+	Health *Health `json:"health,omitempty"`
+}
+
+// GPUAssignment summarizes the GPU device requests from a container's HostConfig.
+type GPUAssignment struct {
+	// Count is the number of GPUs requested, or -1 when all GPUs are requested.
+	//
+	// Required: true
+	Count int `json:"count"`
+
+	// DeviceIDs lists the specific GPU device IDs or UUIDs requested.
+	//
+	// Required: false
+	DeviceIDs []string `json:"deviceIds,omitempty"`
+
+	// Capabilities lists the distinct device capabilities requested (for example: gpu, compute, utility).
+	//
+	// Required: false
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Drivers lists the distinct device drivers requested (for example: nvidia, cdi).
+	//
+	// Required: false
+	Drivers []string `json:"drivers,omitempty"`
 }
 
 // ComposeInfo contains Docker Compose project information extracted from container labels.
@@ -993,6 +1390,60 @@ func NewSummary(c container.Summary) Summary {
 	}
 }
 
+// NewGPUAssignment aggregates the GPU device requests of a container. Requests are
+// considered GPU requests when they use the nvidia driver or ask for the "gpu"
+// capability. Returns nil when the container holds no GPU requests.
+func NewGPUAssignment(requests []container.DeviceRequest) *GPUAssignment {
+	var assignment *GPUAssignment
+	for _, req := range requests {
+		if !isGPUDeviceRequest(req) {
+			continue
+		}
+		if assignment == nil {
+			assignment = &GPUAssignment{}
+		}
+
+		switch {
+		case req.Count == -1 || assignment.Count == -1:
+			assignment.Count = -1
+		case len(req.DeviceIDs) > 0:
+			assignment.Count += len(req.DeviceIDs)
+		default:
+			assignment.Count += req.Count
+		}
+
+		assignment.DeviceIDs = append(assignment.DeviceIDs, req.DeviceIDs...)
+		for _, capSet := range req.Capabilities {
+			for _, capability := range capSet {
+				if !slices.Contains(assignment.Capabilities, capability) {
+					assignment.Capabilities = append(assignment.Capabilities, capability)
+				}
+			}
+		}
+		if req.Driver != "" && !slices.Contains(assignment.Drivers, req.Driver) {
+			assignment.Drivers = append(assignment.Drivers, req.Driver)
+		}
+	}
+
+	if assignment != nil {
+		slices.Sort(assignment.Capabilities)
+		slices.Sort(assignment.Drivers)
+	}
+	return assignment
+}
+
+func isGPUDeviceRequest(req container.DeviceRequest) bool {
+	if strings.EqualFold(req.Driver, "nvidia") {
+		return true
+	}
+	for _, capSet := range req.Capabilities {
+		if slices.Contains(capSet, "gpu") {
+			return true
+		}
+	}
+	return false
+}
+
 // NewDetails creates a Details from a docker container.InspectResponse.
 func NewDetails(c *container.InspectResponse) Details {
 	cfg, labels, imageName := mapInspectConfig(c.Config)
@@ -1146,6 +1597,15 @@ func mapInspectState(state *container.State) State {
 	return mappedState
 }
 
+// NewHealth maps a Docker health state, including its probe log. Returns nil when
+// the container has no health check.
+func NewHealth(health *container.Health) *Health {
+	if health == nil {
+		return nil
+	}
+	return mapInspectHealth(health)
+}
+
 func mapInspectHealth(health *container.Health) *Health {
 	log := make([]HealthLogEntry, 0, len(health.Log))
 	for _, entry := range health.Log {
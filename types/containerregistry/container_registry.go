@@ -144,6 +144,40 @@ type PullUsage struct {
 	Error string `json:"error,omitempty"`
 }
 
+// TestResult is the outcome of testing a registry's credentials.
+type TestResult struct {
+	// Message summarizes the test outcome.
+	//
+	// Required: true
+	Message string `json:"message"`
+
+	// AuthType is the auth scheme the registry challenged with (none, basic or bearer).
+	//
+	// Required: false
+	AuthType string `json:"authType,omitempty"`
+
+	// Service is the token service named in the registry's bearer challenge.
+	//
+	// Required: false
+	Service string `json:"service,omitempty"`
+
+	// Scope lists the access the registry reported for the issued token.
+	//
+	// Required: false
+	Scope []string `json:"scope,omitempty"`
+
+	// ExpiresIn is the issued token's lifetime in seconds, when reported.
+	//
+	// Required: false
+	ExpiresIn int `json:"expiresIn,omitempty"`
+
+	// ProbeError explains why the direct token check failed after the daemon
+	// login succeeded.
+	//
+	// Required: false
+	ProbeError string `json:"probeError,omitempty"`
+}
+
 type Sync struct {
 	// ID of the container registry.
 	//
@@ -42,4 +42,10 @@ type Info struct {
 	//
 	// Required: true
 	BuildTime string `json:"buildTime"`
+
+	// GPUType is the GPU vendor Arcane monitors on this host ("nvidia", "amd", "intel", "jetson"),
+	// "none" when GPU monitoring is disabled, or empty when no supported GPU was detected.
+	//
+	// Required: false
+	GPUType string `json:"gpuType,omitempty"`
 }
@@ -23,6 +23,12 @@ type Create struct {
 	// Required: false
 	AccessToken *string `json:"accessToken,omitempty"`
 
+	// BootstrapToken is the agent's AGENT_TOKEN. It is stored encrypted and
+	// used to re-pair the environment if the agent rejects its access token.
+	//
+	// Required: false
+	BootstrapToken *string `json:"bootstrapToken,omitempty"`
+
 	// UseApiKey indicates if an API key should be generated for pairing.
 	//
 	// Required: false
@@ -55,10 +61,30 @@ type Update struct {
 	// Required: false
 	AccessToken *string `json:"accessToken,omitempty"`
 
+	// BootstrapToken is the agent's AGENT_TOKEN. It is stored encrypted and
+	// used to re-pair the environment if the agent rejects its access token.
+	//
+	// Required: false
+	BootstrapToken *string `json:"bootstrapToken,omitempty"`
+
 	// RegenerateApiKey indicates whether to regenerate the API key.
 	//
 	// Required: false
 	RegenerateApiKey *bool `json:"regenerateApiKey,omitempty"`
+
+	// Pinned keeps the environment ahead of unpinned ones in listings.
+	//
+	// Required: false
+	Pinned *bool `json:"pinned,omitempty"`
+}
+
+// ReorderRequest is the request body for reordering environments.
+type ReorderRequest struct {
+	// IDs lists environment IDs in their new display order. Environments not
+	// listed keep their relative order after the listed ones.
+	//
+	// Required: true
+	IDs []string `json:"ids" minItems:"1"`
 }
 
 type Test struct {
@@ -71,6 +97,18 @@ type Test struct {
 	//
 	// Required: false
 	Message *string `json:"message,omitempty"`
+
+	// LatencyMs is the round-trip time of the health check in milliseconds.
+	// It is always 0 for the local environment.
+	//
+	// Required: false
+	LatencyMs *int64 `json:"latencyMs,omitempty"`
+
+	// AgentVersion is the Arcane version reported by the environment, when
+	// the agent reports one.
+	//
+	// Required: false
+	AgentVersion *string `json:"agentVersion,omitempty"`
 }
 
 // TestConnectionRequest is the request body for testing a connection.
@@ -81,6 +119,14 @@ type TestConnectionRequest struct {
 	ApiUrl *string `json:"apiUrl,omitempty"`
 }
 
+// RefreshResult is the response of a bulk connection refresh.
+type RefreshResult struct {
+	// Statuses maps each refreshed environment ID to its connection status.
+	//
+	// Required: true
+	Statuses map[string]string `json:"statuses"`
+}
+
 type EdgeMTLSCertificate struct {
 	// CommonName is the certificate subject common name.
 	//
@@ -140,6 +186,16 @@ type Environment struct {
 	// Required: false
 	IsEdge bool `json:"isEdge"`
 
+	// Pinned environments always sort ahead of unpinned ones.
+	//
+	// Required: true
+	Pinned bool `json:"pinned"`
+
+	// DisplayOrder is the environment's position in the curated layout.
+	//
+	// Required: true
+	DisplayOrder int `json:"displayOrder"`
+
 	// LastSeen is the last successful manager-side health/contact timestamp.
 	//
 	// Required: false
@@ -210,6 +266,43 @@ type Environment struct {
 	//
 	// Required: false
 	ApiKey *string `json:"apiKey,omitempty"`
+
+	// LatencyMs is the round-trip time of the last successful connection test
+	// in milliseconds, if one ran since the server started.
+	//
+	// Required: false
+	LatencyMs *int64 `json:"latencyMs,omitempty"`
+
+	// AgentVersion is the Arcane version reported by the last successful
+	// connection test.
+	//
+	// Required: false
+	AgentVersion *string `json:"agentVersion,omitempty"`
+
+	// CircuitBreaker reports recent request failures to the environment.
+	// An "open" breaker means the environment is degraded and requests to it
+	// fail fast until the cooldown ends.
+	//
+	// Required: false
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty"`
+}
+
+// CircuitBreaker is the state of an environment's request circuit breaker.
+type CircuitBreaker struct {
+	// State is "closed", "open" or "half_open".
+	//
+	// Required: true
+	State string `json:"state"`
+
+	// ConsecutiveFailures is the number of failed requests since the last success.
+	//
+	// Required: true
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// OpenUntil is when an open breaker lets the next trial request through.
+	//
+	// Required: false
+	OpenUntil *time.Time `json:"openUntil,omitempty"`
 }
 
 // AgentPairRequest is the request body for pairing with an agent.
@@ -227,3 +320,32 @@ type AgentPairResponse struct {
 	// Required: true
 	Token string `json:"token"`
 }
+
+// AgentInfo describes the local agent's pairing state.
+type AgentInfo struct {
+	// Paired indicates if the agent has a token a controller can authenticate with.
+	//
+	// Required: true
+	Paired bool `json:"paired"`
+
+	// TokenCreatedAt is when the current token was generated. It is unknown for
+	// tokens supplied through AGENT_TOKEN.
+	//
+	// Required: false
+	TokenCreatedAt *time.Time `json:"tokenCreatedAt,omitempty"`
+
+	// TokenAgeSeconds is the age of the current token.
+	//
+	// Required: false
+	TokenAgeSeconds *int64 `json:"tokenAgeSeconds,omitempty"`
+
+	// ControllerURL is the controller that last paired with the agent.
+	//
+	// Required: false
+	ControllerURL string `json:"controllerUrl,omitempty"`
+
+	// LastPairedAt is when a controller last paired with the agent.
+	//
+	// Required: false
+	LastPairedAt *time.Time `json:"lastPairedAt,omitempty"`
+}
@@ -0,0 +1,28 @@
+package environment
+
+// Maintenance is the maintenance-mode state of an environment. While enabled,
+// mutating operations such as deploys and container removal are rejected.
+type Maintenance struct {
+	// Enabled indicates if the environment is in maintenance mode.
+	//
+	// Required: true
+	Enabled bool `json:"enabled"`
+
+	// Reason is shown to users whose operations are rejected.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty"`
+}
+
+// MaintenanceUpdate turns maintenance mode on or off.
+type MaintenanceUpdate struct {
+	// Enabled turns maintenance mode on or off.
+	//
+	// Required: true
+	Enabled bool `json:"enabled"`
+
+	// Reason explains why the environment is in maintenance. Ignored when disabling.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty" maxLength:"500"`
+}
@@ -0,0 +1,24 @@
+package image
+
+// PullProgress is one progress update from a Docker image pull.
+type PullProgress struct {
+	// ID is the layer the update refers to; empty for image-level status lines.
+	//
+	// Required: false
+	ID string `json:"id,omitempty"`
+
+	// Status is Docker's status text, e.g. Downloading, Extracting or Pull complete.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// Current is the number of bytes handled so far for the layer.
+	//
+	// Required: false
+	Current int64 `json:"current,omitempty"`
+
+	// Total is the layer size in bytes when Docker reports it.
+	//
+	// Required: false
+	Total int64 `json:"total,omitempty"`
+}
@@ -13,7 +13,6 @@ type Config struct {
 	AutoUpdateInterval             string `json:"autoUpdateInterval"`
 	DockerClientRefreshInterval    string `json:"dockerClientRefreshInterval"`
 	PollingInterval                string `json:"pollingInterval"`
-	ScheduledPruneInterval         string `json:"scheduledPruneInterval"`
 	VulnerabilityScanInterval      string `json:"vulnerabilityScanInterval"`
 	AutoHealInterval               string `json:"autoHealInterval"`
 }
@@ -28,7 +27,6 @@ type Update struct {
 	AutoUpdateInterval             *string `json:"autoUpdateInterval,omitempty"`
 	DockerClientRefreshInterval    *string `json:"dockerClientRefreshInterval,omitempty"`
 	PollingInterval                *string `json:"pollingInterval,omitempty"`
-	ScheduledPruneInterval         *string `json:"scheduledPruneInterval,omitempty"`
 	VulnerabilityScanInterval      *string `json:"vulnerabilityScanInterval,omitempty"`
 	AutoHealInterval               *string `json:"autoHealInterval,omitempty"`
 }
@@ -128,24 +128,6 @@ var jobMetadataRegistry = map[string]JobMetadata{
 			},
 		},
 	},
-	"scheduled-prune": {
-		ID:             "scheduled-prune",
-		Name:           "Scheduled Prune",
-		Description:    "Removes unused containers, images, volumes, and networks",
-		Category:       "maintenance",
-		SettingsKey:    "scheduledPruneInterval",
-		EnabledKey:     "scheduledPruneEnabled",
-		ManagerOnly:    false,
-		IsContinuous:   false,
-		CanRunManually: true,
-		Prerequisites: []JobPrerequisiteMetadata{
-			{
-				SettingKey:  "scheduledPruneEnabled",
-				Label:       "Scheduled prune enabled",
-				SettingsURL: "/settings/general",
-			},
-		},
-	},
 	"filesystem-watcher": {
 		ID:             "filesystem-watcher",
 		Name:           "Filesystem Watcher",
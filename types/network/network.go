@@ -88,6 +88,59 @@ type ContainerEndpoint struct {
 	MacAddress string `json:"macAddress"`
 }
 
+// ServiceAttachment is a swarm service whose task template or virtual IPs
+// reference a network.
+type ServiceAttachment struct {
+	// ServiceID is the unique identifier of the swarm service.
+	//
+	// Required: true
+	ServiceID string `json:"serviceId"`
+
+	// ServiceName is the name of the swarm service.
+	//
+	// Required: true
+	ServiceName string `json:"serviceName"`
+
+	// Aliases are the network aliases the service declares for the network.
+	//
+	// Required: false
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Usage lists the workloads that still reference a network.
+type Usage struct {
+	// NetworkID is the unique identifier of the network.
+	//
+	// Required: true
+	NetworkID string `json:"networkId"`
+
+	// NetworkName is the name of the network.
+	//
+	// Required: true
+	NetworkName string `json:"networkName"`
+
+	// Scope is the network scope (local or swarm).
+	//
+	// Required: true
+	Scope string `json:"scope"`
+
+	// Containers are the containers attached to the network on this node.
+	//
+	// Required: true
+	Containers []ContainerEndpoint `json:"containers"`
+
+	// Services are the swarm services that reference the network. It is only
+	// populated for swarm-scoped networks when this node is a swarm manager.
+	//
+	// Required: true
+	Services []ServiceAttachment `json:"services"`
+
+	// InUse reports whether any container or service references the network.
+	//
+	// Required: true
+	InUse bool `json:"inUse"`
+}
+
 type UsageCounts struct {
 	// Inuse is the number of networks currently in use.
 	//
@@ -398,3 +451,21 @@ func NewSummary(s network.Summary) Summary {
 		IsDefault: s.Name == "bridge" || s.Name == "host" || s.Name == "none",
 	}
 }
+
+// NewContainerEndpoint creates a ContainerEndpoint from the endpoint Docker
+// reports for the container with the given ID.
+func NewContainerEndpoint(containerID string, endpoint network.EndpointResource) ContainerEndpoint {
+	entry := ContainerEndpoint{
+		ID:         containerID,
+		Name:       endpoint.Name,
+		EndpointID: endpoint.EndpointID,
+		MacAddress: endpoint.MacAddress.String(),
+	}
+	if endpoint.IPv4Address.IsValid() {
+		entry.IPv4Address = endpoint.IPv4Address.String()
+	}
+	if endpoint.IPv6Address.IsValid() {
+		entry.IPv6Address = endpoint.IPv6Address.String()
+	}
+	return entry
+}
@@ -1,6 +1,8 @@
 package notification
 
 import (
+	"time"
+
 	"github.com/getarcaneapp/arcane/types/v2/base"
 	"github.com/getarcaneapp/arcane/types/v2/imageupdate"
 	"github.com/getarcaneapp/arcane/types/v2/system"
@@ -36,6 +38,9 @@ const (
 
 	// NotificationProviderGeneric is the builtin Generic webhook notification provider.
 	NotificationProviderGeneric Provider = "generic"
+
+	// NotificationProviderWebhook is the builtin event webhook provider that posts structured JSON events.
+	NotificationProviderWebhook Provider = "webhook"
 )
 
 type Update struct {
@@ -77,6 +82,55 @@ type Response struct {
 	Config base.JsonObject `json:"config"`
 }
 
+// LogEntry is a single recorded notification delivery attempt.
+type LogEntry struct {
+	// ID is the unique identifier of the log entry.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Provider is the notification provider the attempt was sent through.
+	//
+	// Required: true
+	Provider Provider `json:"provider"`
+
+	// Status is the delivery outcome: success, failed or skipped (duplicate).
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// Title is the rendered summary of the attempt.
+	//
+	// Required: true
+	Title string `json:"title"`
+
+	// Subject identifies what the notification was about, such as an image
+	// reference or a test send.
+	//
+	// Required: false
+	Subject string `json:"subject,omitempty"`
+
+	// Error is the provider error of a failed attempt.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// EnvironmentID is the environment the notification was sent for.
+	//
+	// Required: false
+	EnvironmentID string `json:"environmentId,omitempty"`
+
+	// Metadata holds the event-specific details recorded with the attempt.
+	//
+	// Required: false
+	Metadata base.JsonObject `json:"metadata,omitempty"`
+
+	// SentAt is when the attempt was made.
+	//
+	// Required: true
+	SentAt time.Time `json:"sentAt"`
+}
+
 // TestResponse is the result of a test notification send.
 type TestResponse struct {
 	// Message describes the test outcome.
@@ -87,6 +141,46 @@ type TestResponse struct {
 	Warning string `json:"warning,omitempty"`
 }
 
+// ProviderTestResult is the outcome of a test send to a single provider,
+// including the message that was rendered for it.
+type ProviderTestResult struct {
+	// Provider is the notification provider that was tested.
+	//
+	// Required: true
+	Provider Provider `json:"provider"`
+
+	// Success indicates if the provider accepted the test notification.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// Error describes why the send failed. Empty on success.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// Warning explains why real notifications would not send even though the
+	// test did (for example, the provider is disabled).
+	//
+	// Required: false
+	Warning string `json:"warning,omitempty"`
+
+	// Title is the rendered title, or the subject for email.
+	//
+	// Required: false
+	Title string `json:"title,omitempty"`
+
+	// Body is the rendered message in the format the provider receives.
+	//
+	// Required: true
+	Body string `json:"body"`
+
+	// HTML is the rendered email body. Only set for the email provider.
+	//
+	// Required: false
+	HTML string `json:"html,omitempty"`
+}
+
 type DispatchKind string
 
 const (
@@ -96,6 +190,7 @@ const (
 	DispatchKindVulnerabilityFound DispatchKind = "vulnerability_found"
 	DispatchKindPruneReport        DispatchKind = "prune_report"
 	DispatchKindAutoHeal           DispatchKind = "auto_heal"
+	DispatchKindContainerEvent     DispatchKind = "container_event"
 )
 
 type DispatchImageUpdate struct {
@@ -133,6 +228,13 @@ type DispatchAutoHeal struct {
 	ContainerID   string `json:"containerId"`
 }
 
+type DispatchContainerEvent struct {
+	EventType     string `json:"eventType"`
+	ContainerName string `json:"containerName"`
+	ContainerID   string `json:"containerId"`
+	Detail        string `json:"detail,omitempty"`
+}
+
 type DispatchRequest struct {
 	Kind               DispatchKind                `json:"kind"`
 	ImageUpdate        *DispatchImageUpdate        `json:"imageUpdate,omitempty"`
@@ -141,6 +243,7 @@ type DispatchRequest struct {
 	VulnerabilityFound *DispatchVulnerabilityFound `json:"vulnerabilityFound,omitempty"`
 	PruneReport        *DispatchPruneReport        `json:"pruneReport,omitempty"`
 	AutoHeal           *DispatchAutoHeal           `json:"autoHeal,omitempty"`
+	ContainerEvent     *DispatchContainerEvent     `json:"containerEvent,omitempty"`
 }
 
 type DispatchResponse struct {
@@ -0,0 +1,118 @@
+package project
+
+// DeployProgressStage identifies which part of a project deploy a progress event refers to.
+type DeployProgressStage string
+
+const (
+	DeployProgressStageHooks  DeployProgressStage = "hooks"
+	DeployProgressStageImages DeployProgressStage = "images"
+	DeployProgressStageUp     DeployProgressStage = "up"
+	DeployProgressStageDone   DeployProgressStage = "done"
+)
+
+// DeployProgressStatus is the state of a deploy stage.
+type DeployProgressStatus string
+
+const (
+	DeployProgressStatusRunning   DeployProgressStatus = "running"
+	DeployProgressStatusCompleted DeployProgressStatus = "completed"
+	DeployProgressStatusFailed    DeployProgressStatus = "failed"
+)
+
+// DeployResourceStatus is the state compose reports for a single resource
+// (container, network, volume or image) while a project comes up.
+type DeployResourceStatus string
+
+const (
+	DeployResourceStatusWorking DeployResourceStatus = "working"
+	DeployResourceStatusDone    DeployResourceStatus = "done"
+	DeployResourceStatusWarning DeployResourceStatus = "warning"
+	DeployResourceStatusError   DeployResourceStatus = "error"
+)
+
+// DeployServiceProgress is the latest compose status of one service.
+type DeployServiceProgress struct {
+	// Service is the compose service name.
+	//
+	// Required: true
+	Service string `json:"service"`
+
+	// Status is the state of the service's most recent resource event.
+	//
+	// Required: true
+	Status DeployResourceStatus `json:"status"`
+
+	// Text is compose's short status text, such as "Started" or "Healthy".
+	//
+	// Required: false
+	Text string `json:"text,omitempty"`
+
+	// Details carries extra context compose attached to the event, such as an error message.
+	//
+	// Required: false
+	Details string `json:"details,omitempty"`
+}
+
+// DeployProgressEvent is emitted while a project deploys so clients can show
+// staged, per-service feedback instead of a flat log.
+type DeployProgressEvent struct {
+	// Stage is the deploy stage the event belongs to, or "done" for the final event.
+	//
+	// Required: true
+	Stage DeployProgressStage `json:"stage"`
+
+	// Status is the state of the stage.
+	//
+	// Required: true
+	Status DeployProgressStatus `json:"status"`
+
+	// Line is a plain progress line, as the HTTP deploy stream would print it.
+	//
+	// Required: false
+	Line string `json:"line,omitempty"`
+
+	// Resource is the compose resource a resource event refers to, e.g. "Container web-1".
+	//
+	// Required: false
+	Resource string `json:"resource,omitempty"`
+
+	// Service is the compose service the resource belongs to, when it can be attributed.
+	//
+	// Required: false
+	Service string `json:"service,omitempty"`
+
+	// ResourceStatus is the state compose reported for Resource.
+	//
+	// Required: false
+	ResourceStatus DeployResourceStatus `json:"resourceStatus,omitempty"`
+
+	// Text is compose's short status text for Resource.
+	//
+	// Required: false
+	Text string `json:"text,omitempty"`
+
+	// Details carries extra context for Resource, such as an error message.
+	//
+	// Required: false
+	Details string `json:"details,omitempty"`
+
+	// Percent is the completion percentage compose reported for Resource (0-100).
+	//
+	// Required: false
+	Percent int `json:"percent,omitempty"`
+
+	// Services is the final per-service status, sorted by service name. Only set on the "done" event.
+	//
+	// Required: false
+	Services []DeployServiceProgress `json:"services,omitempty"`
+
+	// FailedServices names the services that ended in an error. Only set on the "done" event.
+	//
+	// Required: false
+	FailedServices []string `json:"failedServices,omitempty"`
+
+	// Error is the deploy error, set when the deploy failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,92 @@
+package project
+
+// OrphanContainer is a container labelled with the project whose service is no
+// longer defined in the compose file.
+type OrphanContainer struct {
+	// ID is the container ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the container name without the leading slash.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Service is the compose service the container was created for.
+	//
+	// Required: true
+	Service string `json:"service"`
+
+	// Image is the image the container runs.
+	//
+	// Required: false
+	Image string `json:"image,omitempty"`
+
+	// State is the container state, such as "running" or "exited".
+	//
+	// Required: true
+	State string `json:"state"`
+}
+
+// OrphanNetwork is a network labelled with the project that the compose file no
+// longer declares.
+type OrphanNetwork struct {
+	// ID is the network ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the network name.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Network is the network's key in the compose file it was created from.
+	//
+	// Required: false
+	Network string `json:"network,omitempty"`
+}
+
+// Orphans lists the resources of a project left behind by an earlier revision
+// of its compose file.
+type Orphans struct {
+	// ProjectID is the ID of the project.
+	//
+	// Required: true
+	ProjectID string `json:"projectId"`
+
+	// ProjectName is the compose project name the resources are labelled with.
+	//
+	// Required: true
+	ProjectName string `json:"projectName"`
+
+	// Containers are the orphaned containers, sorted by name.
+	//
+	// Required: true
+	Containers []OrphanContainer `json:"containers"`
+
+	// Networks are the orphaned networks, sorted by name.
+	//
+	// Required: true
+	Networks []OrphanNetwork `json:"networks"`
+}
+
+// PruneOrphansResult reports what pruning a project's orphans removed.
+type PruneOrphansResult struct {
+	// ContainersRemoved are the names of the removed containers.
+	//
+	// Required: true
+	ContainersRemoved []string `json:"containersRemoved"`
+
+	// NetworksRemoved are the names of the removed networks.
+	//
+	// Required: true
+	NetworksRemoved []string `json:"networksRemoved"`
+
+	// Errors describe orphans that could not be removed, such as a network still
+	// in use by a container outside the project.
+	//
+	// Required: false
+	Errors []string `json:"errors,omitempty"`
+}
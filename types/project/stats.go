@@ -0,0 +1,70 @@
+package project
+
+import "time"
+
+// ContainerStats is the latest resource usage of one running project container.
+type ContainerStats struct {
+	// ID is the container ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the container name without the leading slash.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Service is the compose service the container belongs to.
+	//
+	// Required: false
+	Service string `json:"service,omitempty"`
+
+	// CPUPercent is the container's share of host CPU, from 0 to 100.
+	//
+	// Required: true
+	CPUPercent float64 `json:"cpuPercent"`
+
+	// MemoryUsage is the memory in use in bytes, excluding the inactive page cache.
+	//
+	// Required: true
+	MemoryUsage uint64 `json:"memoryUsage"`
+
+	// MemoryLimit is the container's memory limit in bytes; without a limit
+	// Docker reports the host's total memory.
+	//
+	// Required: true
+	MemoryLimit uint64 `json:"memoryLimit"`
+}
+
+// Stats is a combined resource snapshot across a project's running containers.
+type Stats struct {
+	// ProjectID is the ID of the project.
+	//
+	// Required: true
+	ProjectID string `json:"projectId"`
+
+	// ProjectName is the compose project name the containers are labelled with.
+	//
+	// Required: true
+	ProjectName string `json:"projectName"`
+
+	// Timestamp is when the snapshot was taken.
+	//
+	// Required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// CPUPercent is the summed host CPU share of all project containers.
+	//
+	// Required: true
+	CPUPercent float64 `json:"cpuPercent"`
+
+	// MemoryUsage is the summed memory usage of all project containers in bytes.
+	//
+	// Required: true
+	MemoryUsage uint64 `json:"memoryUsage"`
+
+	// Containers is the per-container breakdown, sorted by name.
+	//
+	// Required: true
+	Containers []ContainerStats `json:"containers"`
+}
@@ -54,11 +54,42 @@ type Update struct {
 	// Required: false
 	SwarmStackSourcesDirectory *string `json:"swarmStackSourcesDirectory,omitempty"`
 
+	// SwarmStackSourceHistoryLimit is the number of previous swarm stack source versions to retain.
+	//
+	// Required: false
+	SwarmStackSourceHistoryLimit *string `json:"swarmStackSourceHistoryLimit,omitempty"`
+
 	// DiskUsagePath is the path to monitor for disk usage.
 	//
 	// Required: false
 	DiskUsagePath *string `json:"diskUsagePath,omitempty"`
 
+	// SystemGpuVendor pins the GPU vendor used for monitoring, or disables GPU polling with "none".
+	//
+	// Required: false
+	SystemGpuVendor *string `json:"systemGpuVendor,omitempty" binding:"omitempty,oneof=auto nvidia amd intel jetson none"`
+
+	// SystemNvidiaSmiPath is an absolute path to nvidia-smi, used before searching PATH.
+	//
+	// Required: false
+	SystemNvidiaSmiPath *string `json:"systemNvidiaSmiPath,omitempty"`
+
+	// SystemNvidiaSmiExtraFields is a comma-separated list of extra nvidia-smi
+	// --query-gpu fields reported with each GPU's stats.
+	//
+	// Required: false
+	SystemNvidiaSmiExtraFields *string `json:"systemNvidiaSmiExtraFields,omitempty"`
+
+	// SystemIntelGpuTopPath is an absolute path to intel_gpu_top, used before searching PATH.
+	//
+	// Required: false
+	SystemIntelGpuTopPath *string `json:"systemIntelGpuTopPath,omitempty"`
+
+	// SystemTegrastatsPath is an absolute path to tegrastats, used before searching PATH.
+	//
+	// Required: false
+	SystemTegrastatsPath *string `json:"systemTegrastatsPath,omitempty"`
+
 	// AutoUpdate indicates if automatic updates are enabled.
 	//
 	// Required: false
@@ -104,6 +135,16 @@ type Update struct {
 	// Required: false
 	ActivityHistoryMaxEntries *string `json:"activityHistoryMaxEntries,omitempty"`
 
+	// EventRetentionHours is the age in hours after which events are deleted (0 = keep indefinitely).
+	//
+	// Required: false
+	EventRetentionHours *string `json:"eventRetentionHours,omitempty"`
+
+	// NotificationLogRetentionDays is the age in days after which notification delivery logs are deleted (0 = keep indefinitely).
+	//
+	// Required: false
+	NotificationLogRetentionDays *string `json:"notificationLogRetentionDays,omitempty"`
+
 	// MaxConcurrentActivities is the maximum long-running activities per environment before new ones queue (0 = unlimited).
 	//
 	// Required: false
@@ -114,17 +155,27 @@ type Update struct {
 	// Required: false
 	DefaultDeployPullPolicy *string `json:"defaultDeployPullPolicy,omitempty" binding:"omitempty,oneof=missing always never"`
 
-	// ScheduledPruneEnabled indicates if scheduled pruning is enabled.
+	// DefaultContainerCpuLimit is the CPU limit in cores applied to new containers that set none (0 = no default).
+	//
+	// Required: false
+	DefaultContainerCpuLimit *string `json:"defaultContainerCpuLimit,omitempty"`
+
+	// DefaultContainerMemoryLimitMb is the memory limit in MB applied to new containers that set none (0 = no default).
+	//
+	// Required: false
+	DefaultContainerMemoryLimitMb *string `json:"defaultContainerMemoryLimitMb,omitempty"`
+
+	// DefaultContainerRestartPolicy is the restart policy applied to new containers that set none (empty = Docker default).
 	//
 	// Required: false
-	ScheduledPruneEnabled *string `json:"scheduledPruneEnabled,omitempty"`
+	DefaultContainerRestartPolicy *string `json:"defaultContainerRestartPolicy,omitempty" binding:"omitempty,oneof=no always unless-stopped on-failure"`
 
-	// ScheduledPruneInterval is the interval in minutes between prune operations.
+	// RegistryMirrors maps registry hosts to mirror hosts that image pulls go through.
 	//
 	// Required: false
-	ScheduledPruneInterval *string `json:"scheduledPruneInterval,omitempty"`
+	RegistryMirrors *string `json:"registryMirrors,omitempty"`
 
-	// PruneContainerMode controls how containers are pruned during scheduled prune.
+	// PruneContainerMode controls how containers are pruned by default.
 	//
 	// Required: false
 	PruneContainerMode *string `json:"pruneContainerMode,omitempty" binding:"omitempty,oneof=none stopped olderThan"`
@@ -134,7 +185,7 @@ type Update struct {
 	// Required: false
 	PruneContainerUntil *string `json:"pruneContainerUntil,omitempty"`
 
-	// PruneImageMode controls how images are pruned during scheduled prune.
+	// PruneImageMode controls how images are pruned by default.
 	//
 	// Required: false
 	PruneImageMode *string `json:"pruneImageMode,omitempty" binding:"omitempty,oneof=none dangling all olderThan"`
@@ -144,12 +195,12 @@ type Update struct {
 	// Required: false
 	PruneImageUntil *string `json:"pruneImageUntil,omitempty"`
 
-	// PruneVolumeMode controls how volumes are pruned during scheduled prune.
+	// PruneVolumeMode controls how volumes are pruned by default.
 	//
 	// Required: false
 	PruneVolumeMode *string `json:"pruneVolumeMode,omitempty" binding:"omitempty,oneof=none anonymous all"`
 
-	// PruneNetworkMode controls how networks are pruned during scheduled prune.
+	// PruneNetworkMode controls how networks are pruned by default.
 	//
 	// Required: false
 	PruneNetworkMode *string `json:"pruneNetworkMode,omitempty" binding:"omitempty,oneof=none unused olderThan"`
@@ -159,7 +210,7 @@ type Update struct {
 	// Required: false
 	PruneNetworkUntil *string `json:"pruneNetworkUntil,omitempty"`
 
-	// PruneBuildCacheMode controls how build cache is pruned during scheduled prune.
+	// PruneBuildCacheMode controls how build cache is pruned by default.
 	//
 	// Required: false
 	PruneBuildCacheMode *string `json:"pruneBuildCacheMode,omitempty" binding:"omitempty,oneof=none unused all olderThan"`
@@ -459,6 +510,12 @@ type Update struct {
 	// Required: false
 	VolumeBrowserHelperIdleTimeout *string `json:"volumeBrowserHelperIdleTimeout,omitempty"`
 
+	// ImageUpdateNotificationCooldown is the number of hours an identical image
+	// update notification is suppressed per provider (0 disables).
+	//
+	// Required: false
+	ImageUpdateNotificationCooldown *string `json:"imageUpdateNotificationCooldown,omitempty"`
+
 	// BuildProvider is the default build provider (local|depot).
 	//
 	// Required: false
@@ -41,6 +41,33 @@ type SecretUpdateRequest struct {
 	Spec    json.RawMessage `json:"spec" doc:"Updated secret specification"`
 }
 
+// ServiceMountUsage describes how a service mounts a config or secret.
+type ServiceMountUsage struct {
+	ServiceID   string `json:"serviceId"`
+	ServiceName string `json:"serviceName"`
+	Target      string `json:"target,omitempty" doc:"File name inside the container; empty for runtime config references such as credential specs"`
+	UID         string `json:"uid,omitempty"`
+	GID         string `json:"gid,omitempty"`
+	Mode        uint32 `json:"mode,omitempty" doc:"File mode bits"`
+}
+
+type SecretRotateRequest struct {
+	Data []byte `json:"data" doc:"New secret value, base64-encoded"`
+}
+
+// SecretRotationService identifies a service re-pointed at the rotated secret.
+type SecretRotationService struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type SecretRotateResponse struct {
+	Secret                SecretSummary           `json:"secret"`
+	PreviousSecretID      string                  `json:"previousSecretId"`
+	PreviousSecretRemoved bool                    `json:"previousSecretRemoved" doc:"False when tasks still referenced the previous secret and it was left in place"`
+	UpdatedServices       []SecretRotationService `json:"updatedServices"`
+}
+
 // NewConfigSummary converts a Docker swarm config into the API-facing ConfigSummary shape.
 //
 // It copies the config identity, version, timestamps, and spec from the Docker
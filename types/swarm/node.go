@@ -153,6 +153,55 @@ type NodeUpdateRequest struct {
 	Availability *swarm.NodeAvailability `json:"availability,omitempty"`
 }
 
+// NodeLabelsPatchRequest adds, changes and removes individual node labels while
+// leaving every other label untouched.
+type NodeLabelsPatchRequest struct {
+	// Set maps label keys to the values to add or overwrite.
+	//
+	// Required: false
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove lists label keys to delete. Keys the node does not have are ignored.
+	//
+	// Required: false
+	Remove []string `json:"remove,omitempty"`
+}
+
+type NodeLabelsResponse struct {
+	// NodeID is the node ID.
+	//
+	// Required: true
+	NodeID string `json:"nodeId"`
+
+	// Labels are the node's labels after the patch was applied.
+	//
+	// Required: true
+	Labels map[string]string `json:"labels"`
+}
+
+type NodeDrainResponse struct {
+	// NodeID is the drained node ID.
+	//
+	// Required: true
+	NodeID string `json:"nodeId"`
+
+	// TasksMoved is the number of tasks that left the node while it drained: the
+	// active tasks when draining started minus RemainingTasks.
+	//
+	// Required: true
+	TasksMoved int `json:"tasksMoved"`
+
+	// RemainingTasks is the number of tasks still active on the node when the wait ended.
+	//
+	// Required: true
+	RemainingTasks int `json:"remainingTasks"`
+
+	// Drained reports whether every task left the node before the timeout.
+	//
+	// Required: true
+	Drained bool `json:"drained"`
+}
+
 // NewNodeSummary converts a Docker swarm node into the API-facing NodeSummary shape.
 //
 // It derives manager role labels, reachability, platform strings, and default
@@ -0,0 +1,75 @@
+package swarm
+
+// ResourceCapacity splits one resource into what nodes provide, what running
+// tasks reserve, and what is left to schedule.
+type ResourceCapacity struct {
+	// Total is the capacity reported by the nodes.
+	//
+	// Required: true
+	Total int64 `json:"total"`
+
+	// Allocated is the sum of the reservations of running tasks.
+	//
+	// Required: true
+	Allocated int64 `json:"allocated"`
+
+	// Available is Total minus Allocated, never below zero. For the cluster it
+	// is the sum of each schedulable node's Available.
+	//
+	// Required: true
+	Available int64 `json:"available"`
+}
+
+// NodeResources is the capacity breakdown of a single swarm node.
+type NodeResources struct {
+	// NodeID is the unique identifier of the node.
+	//
+	// Required: true
+	NodeID string `json:"nodeId"`
+
+	// Hostname is the node hostname.
+	//
+	// Required: true
+	Hostname string `json:"hostname"`
+
+	// Schedulable reports whether the node is ready and active, and therefore
+	// counted in the cluster totals.
+	//
+	// Required: true
+	Schedulable bool `json:"schedulable"`
+
+	// NanoCPUs is the CPU capacity in units of 10^-9 CPUs.
+	//
+	// Required: true
+	NanoCPUs ResourceCapacity `json:"nanoCpus"`
+
+	// MemoryBytes is the memory capacity in bytes.
+	//
+	// Required: true
+	MemoryBytes ResourceCapacity `json:"memoryBytes"`
+
+	// RunningTasks is the number of running tasks on the node.
+	//
+	// Required: true
+	RunningTasks int `json:"runningTasks"`
+}
+
+// ClusterResources aggregates the capacity of the schedulable nodes in the
+// swarm.
+type ClusterResources struct {
+	// NanoCPUs is the cluster CPU capacity in units of 10^-9 CPUs.
+	//
+	// Required: true
+	NanoCPUs ResourceCapacity `json:"nanoCpus"`
+
+	// MemoryBytes is the cluster memory capacity in bytes.
+	//
+	// Required: true
+	MemoryBytes ResourceCapacity `json:"memoryBytes"`
+
+	// Nodes is the per-node breakdown, including nodes that are drained, paused
+	// or down.
+	//
+	// Required: true
+	Nodes []NodeResources `json:"nodes"`
+}
@@ -9,6 +9,10 @@ import (
 
 const StackNamespaceLabel = "com.docker.stack.namespace"
 
+// ServiceImagePinnedLabel holds the digest a service was pinned to. Stack
+// deploys keep a pinned service on that digest while its compose image is unchanged.
+const ServiceImagePinnedLabel = "io.getarcane.swarm.image.pinned"
+
 type ServicePort struct {
 	// Protocol is the transport protocol used by the port.
 	//
@@ -162,6 +166,22 @@ type ServiceInspect struct {
 	// UpdateStatus is the current update status, if any.
 	UpdateStatus *swarm.UpdateStatus `json:"updateStatus,omitempty"`
 
+	// UpdateConfig is how rolling updates replace the service's tasks, if configured.
+	//
+	// Required: false
+	UpdateConfig *ServiceRolloutConfig `json:"updateConfig,omitempty"`
+
+	// RollbackConfig is how a rollback replaces the service's tasks, if configured.
+	//
+	// Required: false
+	RollbackConfig *ServiceRolloutConfig `json:"rollbackConfig,omitempty"`
+
+	// UpdateOrder is the order rolling updates use, stop-first or start-first,
+	// falling back to Docker's stop-first default when the service sets none.
+	//
+	// Required: true
+	UpdateOrder string `json:"updateOrder"`
+
 	// Nodes is a list of node hostnames running this service.
 	Nodes []string `json:"nodes,omitempty"`
 
@@ -221,6 +241,21 @@ type ServiceCreateRequest struct {
 	Options *ServiceCreateOptions `json:"options,omitempty" doc:"Additional create options"`
 }
 
+// ServiceFromComposeRequest creates a standalone swarm service from a compose
+// fragment.
+type ServiceFromComposeRequest struct {
+	// Compose is a compose file declaring exactly one service. Networks,
+	// configs and secrets it references must be external.
+	//
+	// Required: true
+	Compose string `json:"compose" minLength:"1" doc:"Compose content declaring one service"`
+
+	// Name overrides the service name. Defaults to the compose service key.
+	//
+	// Required: false
+	Name string `json:"name,omitempty" doc:"Service name override"`
+}
+
 type ServiceUpdateRequest struct {
 	// Version is the service version index to update.
 	//
@@ -236,6 +271,52 @@ type ServiceUpdateRequest struct {
 	//
 	// Required: false
 	Options *ServiceUpdateOptions `json:"options,omitempty"`
+
+	// UpdateConfig overrides individual rolling-update settings in Spec. Fields
+	// left unset keep the value from Spec.
+	//
+	// Required: false
+	UpdateConfig *ServiceRolloutConfig `json:"updateConfig,omitempty"`
+
+	// RollbackConfig overrides individual rollback settings in Spec. Fields left
+	// unset keep the value from Spec. FailureAction cannot be "rollback".
+	//
+	// Required: false
+	RollbackConfig *ServiceRolloutConfig `json:"rollbackConfig,omitempty"`
+}
+
+// ServiceRolloutConfig describes how a service's tasks are replaced during a
+// rolling update or a rollback.
+type ServiceRolloutConfig struct {
+	// Parallelism is how many tasks are replaced at once; 0 replaces all of them together.
+	//
+	// Required: false
+	Parallelism *int64 `json:"parallelism,omitempty"`
+
+	// Delay is the wait between replacing batches of tasks, as a duration such as "10s".
+	//
+	// Required: false
+	Delay *string `json:"delay,omitempty"`
+
+	// FailureAction is what happens when a replaced task fails: pause, continue or rollback.
+	//
+	// Required: false
+	FailureAction *string `json:"failureAction,omitempty"`
+
+	// Monitor is how long each replaced task is watched for failure, as a duration such as "5s".
+	//
+	// Required: false
+	Monitor *string `json:"monitor,omitempty"`
+
+	// MaxFailureRatio is the fraction of tasks, from 0 to 1, allowed to fail before FailureAction applies.
+	//
+	// Required: false
+	MaxFailureRatio *float32 `json:"maxFailureRatio,omitempty"`
+
+	// Order is whether old tasks are stopped before new ones start (stop-first) or after (start-first).
+	//
+	// Required: false
+	Order *string `json:"order,omitempty"`
 }
 
 type ServiceCreateResponse struct {
@@ -257,6 +338,24 @@ type ServiceUpdateResponse struct {
 	Warnings []string `json:"warnings,omitempty"`
 }
 
+// ServiceImagePinResponse is returned after pinning a service to its image digest.
+type ServiceImagePinResponse struct {
+	// Image is the digest-qualified image reference the service now runs.
+	//
+	// Required: true
+	Image string `json:"image"`
+
+	// Digest is the pinned image digest.
+	//
+	// Required: true
+	Digest string `json:"digest"`
+
+	// Warnings are any warnings returned by the Docker API.
+	//
+	// Required: false
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 type ServiceCreateOptions struct {
 	// EncodedRegistryAuth is the encoded registry authorization credentials.
 	//
@@ -298,6 +397,102 @@ type ServiceScaleRequest struct {
 	Replicas uint64 `json:"replicas"`
 }
 
+type ServicePlacementPreference struct {
+	// Spread is the label descriptor tasks are spread over (e.g. node.labels.zone).
+	//
+	// Required: true
+	Spread string `json:"spread"`
+}
+
+type ServicePlacementRequest struct {
+	// Constraints replaces the service placement constraints (e.g. node.labels.zone==eu).
+	// An empty list removes all constraints; omitting the field keeps the current ones.
+	//
+	// Required: false
+	Constraints *[]string `json:"constraints,omitempty"`
+
+	// Preferences replaces the service placement preferences.
+	// An empty list removes all preferences; omitting the field keeps the current ones.
+	//
+	// Required: false
+	Preferences *[]ServicePlacementPreference `json:"preferences,omitempty"`
+}
+
+// ServiceResourceValues sets the CPU and memory of a service's limits or
+// reservations. Omitting a field keeps the current value; 0 removes it.
+type ServiceResourceValues struct {
+	// NanoCPUs is the CPU amount in units of 10^-9 CPUs; at least 1000000 (0.001 CPU) when set.
+	//
+	// Required: false
+	NanoCPUs *int64 `json:"nanoCpus,omitempty"`
+
+	// MemoryBytes is the memory amount in bytes; at least 4 MiB when set.
+	//
+	// Required: false
+	MemoryBytes *int64 `json:"memoryBytes,omitempty"`
+}
+
+type ServiceResourcesRequest struct {
+	// Limits caps what each task may use.
+	//
+	// Required: false
+	Limits *ServiceResourceValues `json:"limits,omitempty"`
+
+	// Reservations is what the scheduler sets aside on a node for each task.
+	// A task is only placed on a node with enough unreserved capacity.
+	//
+	// Required: false
+	Reservations *ServiceResourceValues `json:"reservations,omitempty"`
+}
+
+type ServiceUpdateStatus struct {
+	// ServiceID is the ID of the inspected service.
+	//
+	// Required: true
+	ServiceID string `json:"serviceId"`
+
+	// State is the rolling update state reported by Docker (e.g. updating, paused, completed).
+	// Empty when the service has never been updated.
+	//
+	// Required: false
+	State string `json:"state,omitempty"`
+
+	// StartedAt is when the current or last update started.
+	//
+	// Required: false
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is when the current or last update completed.
+	//
+	// Required: false
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	// Message is the human-readable update status message from Docker.
+	//
+	// Required: false
+	Message string `json:"message,omitempty"`
+
+	// DesiredReplicas is the number of tasks the service should be running.
+	//
+	// Required: true
+	DesiredReplicas uint64 `json:"desiredReplicas"`
+
+	// RunningReplicas is the number of tasks currently in the Running state.
+	//
+	// Required: true
+	RunningReplicas uint64 `json:"runningReplicas"`
+
+	// Paused reports whether the update or rollback is paused and needs attention.
+	//
+	// Required: true
+	Paused bool `json:"paused"`
+
+	// Converged reports whether no update is in progress and all desired replicas are running.
+	//
+	// Required: true
+	Converged bool `json:"converged"`
+}
+
 // NewServiceSummary converts a Docker swarm service into the API-facing ServiceSummary shape.
 //
 // It derives the service mode, replica counts, running task counts, published
@@ -432,12 +627,51 @@ func NewServiceSummary(service swarm.Service, nodeNames []string, networkNameByI
 // Returns the base inspection payload for service.
 func NewServiceInspect(service swarm.Service) ServiceInspect {
 	return ServiceInspect{
-		ID:           service.ID,
-		Version:      service.Version,
-		CreatedAt:    service.CreatedAt,
-		UpdatedAt:    service.UpdatedAt,
-		Spec:         service.Spec,
-		Endpoint:     service.Endpoint,
-		UpdateStatus: service.UpdateStatus,
+		ID:             service.ID,
+		Version:        service.Version,
+		CreatedAt:      service.CreatedAt,
+		UpdatedAt:      service.UpdatedAt,
+		Spec:           service.Spec,
+		Endpoint:       service.Endpoint,
+		UpdateStatus:   service.UpdateStatus,
+		UpdateConfig:   NewServiceRolloutConfig(service.Spec.UpdateConfig),
+		RollbackConfig: NewServiceRolloutConfig(service.Spec.RollbackConfig),
+		UpdateOrder:    EffectiveUpdateOrder(service.Spec.UpdateConfig),
+	}
+}
+
+// EffectiveUpdateOrder returns the task order cfg applies, defaulting to
+// stop-first as Docker does when cfg is nil or leaves the order empty.
+func EffectiveUpdateOrder(cfg *swarm.UpdateConfig) string {
+	if cfg == nil || cfg.Order == "" {
+		return string(swarm.UpdateOrderStopFirst)
+	}
+	return string(cfg.Order)
+}
+
+// effectiveFailureActionInternal returns the failure action cfg applies,
+// defaulting to pause as Docker does when cfg leaves it empty. An empty action
+// would fail validation when the config is sent back unchanged.
+func effectiveFailureActionInternal(cfg *swarm.UpdateConfig) string {
+	if cfg.FailureAction == "" {
+		return string(swarm.UpdateFailureActionPause)
+	}
+	return string(cfg.FailureAction)
+}
+
+// NewServiceRolloutConfig converts a Docker update or rollback config, returning
+// nil when the service has none.
+func NewServiceRolloutConfig(cfg *swarm.UpdateConfig) *ServiceRolloutConfig {
+	if cfg == nil {
+		return nil
+	}
+	parallelism := int64(cfg.Parallelism) //nolint:gosec // a task count, far below the int64 range
+	return &ServiceRolloutConfig{
+		Parallelism:     &parallelism,
+		Delay:           new(cfg.Delay.String()),
+		FailureAction:   new(effectiveFailureActionInternal(cfg)),
+		Monitor:         new(cfg.Monitor.String()),
+		MaxFailureRatio: new(cfg.MaxFailureRatio),
+		Order:           new(EffectiveUpdateOrder(cfg)),
 	}
 }
@@ -0,0 +1,72 @@
+package swarm
+
+import "time"
+
+// Service event scopes.
+const (
+	// ServiceEventScopeService marks an event about the service object itself
+	// (create, update, remove).
+	ServiceEventScopeService = "service"
+	// ServiceEventScopeTask marks an event about a container started for one of
+	// the service's tasks (start, die, oom, health_status, ...).
+	ServiceEventScopeTask = "task"
+)
+
+// ServiceEvent is a Docker event concerning a swarm service or one of its tasks.
+type ServiceEvent struct {
+	// Time is when Docker reported the event.
+	//
+	// Required: true
+	Time time.Time `json:"time"`
+
+	// Scope is either "service" or "task".
+	//
+	// Required: true
+	Scope string `json:"scope"`
+
+	// Action is the Docker event action, e.g. update or die.
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// ServiceID is the ID of the service the event belongs to.
+	//
+	// Required: true
+	ServiceID string `json:"serviceId"`
+
+	// ServiceName is the name of the service the event belongs to.
+	//
+	// Required: false
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// TaskID is the task whose container produced the event.
+	//
+	// Required: false
+	TaskID string `json:"taskId,omitempty"`
+
+	// TaskName is the name of the task whose container produced the event.
+	//
+	// Required: false
+	TaskName string `json:"taskName,omitempty"`
+
+	// NodeID is the node running the task.
+	//
+	// Required: false
+	NodeID string `json:"nodeId,omitempty"`
+
+	// ContainerID is the container backing the task.
+	//
+	// Required: false
+	ContainerID string `json:"containerId,omitempty"`
+
+	// ExitCode is the container exit code reported by die events.
+	//
+	// Required: false
+	ExitCode string `json:"exitCode,omitempty"`
+
+	// Attributes carries the remaining Docker event attributes for service
+	// events, such as replicas.old/replicas.new or updatestate.new.
+	//
+	// Required: false
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
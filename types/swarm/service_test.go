@@ -0,0 +1,19 @@
+package swarm
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/swarm"
+)
+
+func TestNewServiceRolloutConfigDefaultsFailureAction(t *testing.T) {
+	cfg := NewServiceRolloutConfig(&swarm.UpdateConfig{Parallelism: 1})
+	if cfg.FailureAction == nil || *cfg.FailureAction != string(swarm.UpdateFailureActionPause) {
+		t.Fatalf("failureAction = %v, want %q", cfg.FailureAction, swarm.UpdateFailureActionPause)
+	}
+
+	cfg = NewServiceRolloutConfig(&swarm.UpdateConfig{FailureAction: swarm.UpdateFailureActionContinue})
+	if cfg.FailureAction == nil || *cfg.FailureAction != string(swarm.UpdateFailureActionContinue) {
+		t.Fatalf("failureAction = %v, want %q", cfg.FailureAction, swarm.UpdateFailureActionContinue)
+	}
+}
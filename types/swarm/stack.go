@@ -61,6 +61,75 @@ type StackInspect struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// StackRemovalResource is a swarm object that removing a stack would delete.
+type StackRemovalResource struct {
+	// ID is the Docker object ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the Docker object name.
+	//
+	// Required: true
+	Name string `json:"name"`
+}
+
+// Reasons a stack-labeled network is kept when the stack is removed.
+const (
+	StackNetworkKeptIngress = "ingress"
+	StackNetworkKeptShared  = "shared"
+)
+
+// StackKeptNetwork is a stack-labeled network that stack removal leaves in place.
+type StackKeptNetwork struct {
+	StackRemovalResource
+
+	// Reason is "ingress" for the swarm routing mesh network or "shared" when
+	// services outside the stack are attached to it.
+	//
+	// Required: true
+	Reason string `json:"reason"`
+
+	// UsedBy lists the names of services outside the stack attached to the network.
+	//
+	// Required: false
+	UsedBy []string `json:"usedBy,omitempty"`
+}
+
+// StackRemovalPlan lists exactly what removing a stack deletes and which of its
+// networks are kept.
+type StackRemovalPlan struct {
+	// StackName is the stack the plan was built for.
+	//
+	// Required: true
+	StackName string `json:"stackName"`
+
+	// Services are the stack services that will be removed.
+	//
+	// Required: true
+	Services []StackRemovalResource `json:"services"`
+
+	// Configs are the stack-labeled configs that will be removed.
+	//
+	// Required: true
+	Configs []StackRemovalResource `json:"configs"`
+
+	// Secrets are the stack-labeled secrets that will be removed.
+	//
+	// Required: true
+	Secrets []StackRemovalResource `json:"secrets"`
+
+	// Networks are the stack-labeled networks that will be removed.
+	//
+	// Required: true
+	Networks []StackRemovalResource `json:"networks"`
+
+	// KeptNetworks are stack-labeled networks that will not be removed.
+	//
+	// Required: true
+	KeptNetworks []StackKeptNetwork `json:"keptNetworks"`
+}
+
 type StackRenderConfigRequest struct {
 	// Name is the stack name (namespace).
 	//
@@ -154,6 +223,25 @@ type StackSource struct {
 	Files []SyncFile `json:"files,omitempty"`
 }
 
+// OrphanedStackSource is a saved stack source with no matching stack deployed
+// in the swarm.
+type OrphanedStackSource struct {
+	// Name is the stack name the source directory is stored under.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Path is the source directory inside the Arcane container.
+	//
+	// Required: true
+	Path string `json:"path"`
+
+	// UpdatedAt is the latest modification time of the saved source files.
+	//
+	// Required: true
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
 type StackSourceUpdateRequest struct {
 	// ComposeContent is the Docker Compose YAML content to persist for the stack.
 	//
@@ -176,3 +264,78 @@ type StackSourceUpdateRequest struct {
 	// Required: false
 	Files []SyncFile `json:"files,omitempty"`
 }
+
+type StackScaleRequest struct {
+	// Services maps stack service names to their desired replica counts.
+	// Names may be given with or without the stack namespace prefix.
+	//
+	// Required: true
+	Services map[string]uint64 `json:"services"`
+}
+
+type StackScaleResult struct {
+	// Service is the service name as given in the request.
+	//
+	// Required: true
+	Service string `json:"service"`
+
+	// ServiceID is the resolved swarm service ID.
+	//
+	// Required: true
+	ServiceID string `json:"serviceId"`
+
+	// Replicas is the requested replica count.
+	//
+	// Required: true
+	Replicas uint64 `json:"replicas"`
+
+	// Success reports whether the scale was applied.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// Error is the failure message when Success is false.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// Warnings are any warnings returned by the Docker API.
+	//
+	// Required: false
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type StackSourceVersion struct {
+	// ID identifies the snapshot; pass it to the diff endpoint.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// CreatedAt is when the snapshot was taken.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Files lists the source files captured in the snapshot (compose.yaml, compose.override.yaml, .env).
+	//
+	// Required: true
+	Files []string `json:"files"`
+}
+
+type StackSourceDiff struct {
+	// From is the base version ID ("current" for the live source).
+	//
+	// Required: true
+	From string `json:"from"`
+
+	// To is the compared version ID ("current" for the live source).
+	//
+	// Required: true
+	To string `json:"to"`
+
+	// Diff is the unified diff of the compose, override, and env files.
+	// Empty when the versions are identical.
+	//
+	// Required: true
+	Diff string `json:"diff"`
+}
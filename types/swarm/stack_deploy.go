@@ -47,6 +47,12 @@ type StackDeployRequest struct {
 	//
 	// Required: false
 	WorkingDir string `json:"workingDir,omitempty"`
+
+	// DryRun computes the changes the deployment would make and returns them as a plan
+	// without modifying Docker or the saved stack source.
+	//
+	// Required: false
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // SyncFile represents a file to be synced to the target environment.
@@ -68,4 +74,145 @@ type StackDeployResponse struct {
 	//
 	// Required: true
 	Name string `json:"name"`
+
+	// Plan describes the changes the deployment would make. Only set for dry runs.
+	//
+	// Required: false
+	Plan *StackDeployPlan `json:"plan,omitempty"`
+
+	// Images reports the image each stack service runs after the deployment,
+	// sorted by service name. Not set for dry runs.
+	//
+	// Required: false
+	Images []StackServiceImage `json:"images,omitempty"`
+}
+
+// StackServiceImage reports which image reference a stack service's tag resolved to.
+type StackServiceImage struct {
+	// Service is the full swarm service name.
+	//
+	// Required: true
+	Service string `json:"service"`
+
+	// Image is the image reference as written in the compose file.
+	//
+	// Required: true
+	Image string `json:"image"`
+
+	// ResolvedImage is the image reference in the service spec, including the
+	// digest when swarm resolved one.
+	//
+	// Required: true
+	ResolvedImage string `json:"resolvedImage"`
+
+	// Digest is the resolved image digest, e.g. "sha256:…". Empty when the tag was not resolved.
+	//
+	// Required: false
+	Digest string `json:"digest,omitempty"`
+
+	// Pinned reports whether the service is pinned to its digest, so stack
+	// deploys keep it instead of resolving the tag again.
+	//
+	// Required: false
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// Stack service plan actions.
+const (
+	StackServicePlanCreate    = "create"
+	StackServicePlanUpdate    = "update"
+	StackServicePlanUnchanged = "unchanged"
+	StackServicePlanRemove    = "remove"
+	StackServicePlanOrphaned  = "orphaned"
+)
+
+// StackDeployPlan describes what a stack deployment would change.
+type StackDeployPlan struct {
+	// Services lists the planned action for every desired and currently running stack service.
+	//
+	// Required: true
+	Services []StackServicePlan `json:"services"`
+
+	// HasDestructiveChanges reports whether applying the plan would remove any service.
+	//
+	// Required: true
+	HasDestructiveChanges bool `json:"hasDestructiveChanges"`
+}
+
+// StackServicePlan is the planned action for one stack service.
+type StackServicePlan struct {
+	// Name is the full swarm service name (<stack>_<service>).
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Action is one of create, update, unchanged, remove, or orphaned.
+	// Orphaned services are no longer declared but are kept because prune is disabled.
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// Destructive reports whether the action removes the service.
+	//
+	// Required: true
+	Destructive bool `json:"destructive"`
+
+	// Changes lists the compared fields that differ for updates.
+	//
+	// Required: false
+	Changes []StackServiceFieldChange `json:"changes,omitempty"`
+}
+
+// StackServiceFieldChange is a single field difference between the running and desired service.
+type StackServiceFieldChange struct {
+	// Field is the compared field (image, replicas, mode, or networks).
+	//
+	// Required: true
+	Field string `json:"field"`
+
+	// Current is the running value.
+	//
+	// Required: true
+	Current string `json:"current"`
+
+	// Desired is the value the deployment would apply.
+	//
+	// Required: true
+	Desired string `json:"desired"`
+}
+
+// StackImportResponse reports the outcome of importing a stack bundle.
+type StackImportResponse struct {
+	// DryRun reports whether the import only planned the deployments.
+	//
+	// Required: true
+	DryRun bool `json:"dryRun"`
+
+	// Stacks lists the result for each stack in the bundle, ordered by name.
+	//
+	// Required: true
+	Stacks []StackImportResult `json:"stacks"`
+}
+
+// StackImportResult is the outcome of importing a single stack from a bundle.
+type StackImportResult struct {
+	// Name is the stack name taken from the bundle directory.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Deployed reports whether the stack was deployed and its source saved.
+	//
+	// Required: true
+	Deployed bool `json:"deployed"`
+
+	// Plan describes the changes the deployment would make. Only set for dry runs.
+	//
+	// Required: false
+	Plan *StackDeployPlan `json:"plan,omitempty"`
+
+	// Error is the reason the stack could not be planned or deployed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
 }
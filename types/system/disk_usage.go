@@ -0,0 +1,136 @@
+package system
+
+import "time"
+
+// DiskUsageCategory summarizes Docker's disk consumption for one resource kind.
+type DiskUsageCategory struct {
+	// TotalCount is the number of resources of this kind.
+	//
+	// Required: true
+	TotalCount int `json:"totalCount"`
+
+	// ActiveCount is the number of resources currently in use.
+	//
+	// Required: true
+	ActiveCount int `json:"activeCount"`
+
+	// TotalSize is the disk space used by this kind, in bytes.
+	//
+	// Required: true
+	TotalSize int64 `json:"totalSize"`
+
+	// Reclaimable is the disk space a prune of this kind could free, in bytes.
+	//
+	// Required: true
+	Reclaimable int64 `json:"reclaimable"`
+}
+
+// ImageDiskUsage is the disk usage of a single image.
+type ImageDiskUsage struct {
+	// ID is the image ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// RepoTags lists the image's repository tags.
+	//
+	// Required: false
+	RepoTags []string `json:"repoTags,omitempty"`
+
+	// Size is the total size of the image including shared layers, in bytes.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+
+	// SharedSize is the size of layers shared with other images, in bytes.
+	//
+	// Required: true
+	SharedSize int64 `json:"sharedSize"`
+
+	// UniqueSize is the size removing this image alone would free, in bytes.
+	//
+	// Required: true
+	UniqueSize int64 `json:"uniqueSize"`
+
+	// Containers is the number of containers using the image.
+	//
+	// Required: true
+	Containers int64 `json:"containers"`
+
+	// Created is the Unix timestamp when the image was created.
+	//
+	// Required: true
+	Created int64 `json:"created"`
+}
+
+// VolumeDiskUsage is the disk usage of a single volume.
+type VolumeDiskUsage struct {
+	// Name is the volume name.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Driver is the volume driver.
+	//
+	// Required: true
+	Driver string `json:"driver"`
+
+	// Size is the disk space used by the volume in bytes, or -1 when the driver cannot report it.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+
+	// RefCount is the number of containers referencing the volume, or -1 when unknown.
+	//
+	// Required: true
+	RefCount int64 `json:"refCount"`
+}
+
+// DiskUsage is the equivalent of `docker system df -v`: Docker's own disk
+// consumption per resource kind plus per-image and per-volume breakdowns.
+type DiskUsage struct {
+	// Images summarizes image disk usage.
+	//
+	// Required: true
+	Images DiskUsageCategory `json:"images"`
+
+	// Containers summarizes container writable-layer disk usage.
+	//
+	// Required: true
+	Containers DiskUsageCategory `json:"containers"`
+
+	// Volumes summarizes volume disk usage.
+	//
+	// Required: true
+	Volumes DiskUsageCategory `json:"volumes"`
+
+	// BuildCache summarizes build cache disk usage.
+	//
+	// Required: true
+	BuildCache DiskUsageCategory `json:"buildCache"`
+
+	// TotalSize is the combined disk usage of all kinds, in bytes.
+	//
+	// Required: true
+	TotalSize int64 `json:"totalSize"`
+
+	// TotalReclaimable is the combined reclaimable space of all kinds, in bytes.
+	//
+	// Required: true
+	TotalReclaimable int64 `json:"totalReclaimable"`
+
+	// ImageItems lists each image, largest first.
+	//
+	// Required: true
+	ImageItems []ImageDiskUsage `json:"imageItems"`
+
+	// VolumeItems lists each volume, largest first.
+	//
+	// Required: true
+	VolumeItems []VolumeDiskUsage `json:"volumeItems"`
+
+	// CollectedAt is when the usage was read from the Docker daemon.
+	//
+	// Required: true
+	CollectedAt time.Time `json:"collectedAt"`
+}
@@ -0,0 +1,58 @@
+package system
+
+import "time"
+
+// DockerEvent is a normalized Docker engine event streamed by the system events
+// WebSocket.
+type DockerEvent struct {
+	// Time is when the daemon emitted the event.
+	//
+	// Required: true
+	Time time.Time `json:"time"`
+
+	// Type is the kind of object the event concerns, such as "container",
+	// "image", "network" or "volume".
+	//
+	// Required: true
+	Type string `json:"type"`
+
+	// Action is what happened, such as "start", "die" or "pull".
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// ActorID is the ID of the object the event concerns.
+	//
+	// Required: false
+	ActorID string `json:"actorId,omitempty"`
+
+	// ActorName is the object's name when the daemon reports one.
+	//
+	// Required: false
+	ActorName string `json:"actorName,omitempty"`
+
+	// Scope is "local" for engine events and "swarm" for cluster events.
+	//
+	// Required: false
+	Scope string `json:"scope,omitempty"`
+
+	// Attributes are the remaining actor attributes, including container labels.
+	//
+	// Required: false
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// DockerEventFilter narrows the system events stream. Empty fields match every
+// event.
+type DockerEventFilter struct {
+	// Types limits events to these object types.
+	//
+	// Required: false
+	Types []string `json:"types,omitempty"`
+
+	// Labels limits events to actors carrying every listed label, given as
+	// "key" or "key=value".
+	//
+	// Required: false
+	Labels []string `json:"labels,omitempty"`
+}
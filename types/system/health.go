@@ -6,4 +6,61 @@ type HealthResponse struct {
 	//
 	// Required: true
 	Status string `json:"status"`
+
+	// Version is the running Arcane version. It is only reported to
+	// authenticated callers; managers read it from agents when testing
+	// environment connections.
+	//
+	// Required: false
+	Version string `json:"version,omitempty"`
+}
+
+// Readiness check statuses.
+const (
+	ReadinessStatusOK       = "ok"
+	ReadinessStatusDegraded = "degraded"
+	ReadinessStatusFail     = "fail"
+	ReadinessStatusSkipped  = "skipped"
+)
+
+// ReadinessCheck is the result of probing one subsystem.
+type ReadinessCheck struct {
+	// Name identifies the subsystem: docker, database, disk or swarm.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Status is one of ok, degraded, fail or skipped.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// Critical reports whether a failure of this check makes the environment
+	// not ready.
+	//
+	// Required: true
+	Critical bool `json:"critical"`
+
+	// Message explains a non-ok status.
+	//
+	// Required: false
+	Message string `json:"message,omitempty"`
+}
+
+// ReadinessResponse aggregates the subsystem checks for an environment.
+type ReadinessResponse struct {
+	// Ready is false when any critical check failed.
+	//
+	// Required: true
+	Ready bool `json:"ready"`
+
+	// Failing lists the names of the critical checks that failed.
+	//
+	// Required: false
+	Failing []string `json:"failing,omitempty"`
+
+	// Checks holds the result of every subsystem probe.
+	//
+	// Required: true
+	Checks []ReadinessCheck `json:"checks"`
 }
@@ -78,6 +78,18 @@ type PruneAllRequest struct {
 	Volumes    *PruneVolumesOptions    `json:"volumes,omitempty"`
 	Networks   *PruneNetworksOptions   `json:"networks,omitempty"`
 	BuildCache *PruneBuildCacheOptions `json:"buildCache,omitempty"`
+
+	// LabelFilters restricts every selected category to resources whose labels match.
+	// A key maps to the required value, or to "" to match any resource carrying the key.
+	// Prefix a key with "!" to exclude matching resources instead. Build cache entries
+	// carry no labels, so label filters cannot be combined with build cache pruning.
+	LabelFilters map[string]string `json:"labelFilters,omitempty"`
+
+	// Until limits containers, images, networks and build cache to resources created
+	// before this Docker timestamp or duration (for example "24h"). A category's own
+	// until value takes precedence in olderThan mode. Docker cannot filter volumes by
+	// age, so until cannot be combined with volume pruning.
+	Until string `json:"until,omitempty"`
 }
 
 type pruneAllRequestWireInternal struct {
@@ -87,6 +99,9 @@ type pruneAllRequestWireInternal struct {
 	Networks   stdjson.RawMessage `json:"networks,omitempty"`
 	BuildCache stdjson.RawMessage `json:"buildCache,omitempty"`
 	Dangling   *bool              `json:"dangling,omitempty"`
+
+	LabelFilters map[string]string `json:"labelFilters,omitempty"`
+	Until        string            `json:"until,omitempty"`
 }
 
 func (r *PruneAllRequest) UnmarshalJSON(data []byte) error {
@@ -95,7 +110,10 @@ func (r *PruneAllRequest) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*r = PruneAllRequest{}
+	*r = PruneAllRequest{
+		LabelFilters: wire.LabelFilters,
+		Until:        wire.Until,
+	}
 
 	containers, err := decodePruneContainersOptionsInternal(wire.Containers)
 	if err != nil {
@@ -304,3 +322,68 @@ type PruneAllResult struct {
 	// Required: false
 	ActivityID *string `json:"activityId,omitempty"`
 }
+
+// PruneProgressStage identifies which part of a prune operation a progress event refers to.
+type PruneProgressStage string
+
+const (
+	PruneProgressStageContainers PruneProgressStage = "containers"
+	PruneProgressStageImages     PruneProgressStage = "images"
+	PruneProgressStageBuildCache PruneProgressStage = "buildCache"
+	PruneProgressStageVolumes    PruneProgressStage = "volumes"
+	PruneProgressStageNetworks   PruneProgressStage = "networks"
+	PruneProgressStageDone       PruneProgressStage = "done"
+)
+
+// PruneProgressStatus is the state of a prune stage.
+type PruneProgressStatus string
+
+const (
+	PruneProgressStatusRunning   PruneProgressStatus = "running"
+	PruneProgressStatusCompleted PruneProgressStatus = "completed"
+	PruneProgressStatusFailed    PruneProgressStatus = "failed"
+)
+
+// PruneProgressEvent is emitted while a prune operation runs so clients can show
+// incremental feedback instead of waiting for the final result.
+type PruneProgressEvent struct {
+	// Stage is the resource category being pruned, or "done" for the final event.
+	//
+	// Required: true
+	Stage PruneProgressStage `json:"stage"`
+
+	// Status is the state of the stage.
+	//
+	// Required: true
+	Status PruneProgressStatus `json:"status"`
+
+	// Message is a human-readable description of the event.
+	//
+	// Required: false
+	Message string `json:"message,omitempty"`
+
+	// Progress is the approximate overall completion percentage (0-100).
+	//
+	// Required: true
+	Progress int `json:"progress"`
+
+	// ItemsRemoved is the number of resources removed by a completed stage.
+	//
+	// Required: false
+	ItemsRemoved int `json:"itemsRemoved,omitempty"`
+
+	// SpaceReclaimed is the space reclaimed by a completed stage in bytes.
+	//
+	// Required: false
+	SpaceReclaimed uint64 `json:"spaceReclaimed,omitempty"`
+
+	// Error is the failure reason for a failed stage.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// Result is the full prune result, set only on the final "done" event.
+	//
+	// Required: false
+	Result *PruneAllResult `json:"result,omitempty"`
+}
@@ -0,0 +1,194 @@
+package system
+
+import "time"
+
+// PruneScheduleRunStatus is the outcome of a single scheduled prune run.
+type PruneScheduleRunStatus string
+
+const (
+	// PruneScheduleRunStatusSuccess means every selected category was pruned without errors.
+	PruneScheduleRunStatusSuccess PruneScheduleRunStatus = "success"
+	// PruneScheduleRunStatusPartial means the run finished but some categories reported errors.
+	PruneScheduleRunStatusPartial PruneScheduleRunStatus = "partial"
+	// PruneScheduleRunStatusFailed means the run could not be carried out.
+	PruneScheduleRunStatusFailed PruneScheduleRunStatus = "failed"
+	// PruneScheduleRunStatusSkipped means another prune was already running in the environment.
+	PruneScheduleRunStatusSkipped PruneScheduleRunStatus = "skipped"
+)
+
+// PruneSchedule is a prune policy that runs on a cron schedule in one environment.
+type PruneSchedule struct {
+	// ID is the unique identifier of the schedule.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// EnvironmentID is the environment the schedule prunes.
+	//
+	// Required: true
+	EnvironmentID string `json:"environmentId"`
+
+	// Name is a human-readable label for the schedule.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Schedule is a six-field cron expression with seconds, or a descriptor such as @daily.
+	//
+	// Required: true
+	Schedule string `json:"schedule"`
+
+	// Enabled reports whether the schedule is installed in the scheduler.
+	//
+	// Required: true
+	Enabled bool `json:"enabled"`
+
+	// Policy is the prune request issued on every run.
+	//
+	// Required: true
+	Policy PruneAllRequest `json:"policy"`
+
+	// NextRunAt is when the schedule fires next. Empty while the schedule is disabled.
+	//
+	// Required: false
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+
+	// LastRunAt is when the schedule last ran.
+	//
+	// Required: false
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+
+	// LastRunStatus is the outcome of the most recent run.
+	//
+	// Required: false
+	LastRunStatus PruneScheduleRunStatus `json:"lastRunStatus,omitempty"`
+
+	// LastSpaceReclaimed is the space reclaimed by the most recent run in bytes.
+	//
+	// Required: true
+	LastSpaceReclaimed uint64 `json:"lastSpaceReclaimed"`
+
+	// TotalSpaceReclaimed is the space reclaimed across every run in bytes.
+	//
+	// Required: true
+	TotalSpaceReclaimed uint64 `json:"totalSpaceReclaimed"`
+
+	// CreatedAt is when the schedule was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the schedule was last changed.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// PruneScheduleRun records what a single scheduled prune run removed.
+type PruneScheduleRun struct {
+	// ID is the unique identifier of the run.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// ScheduleID is the schedule that produced the run.
+	//
+	// Required: true
+	ScheduleID string `json:"scheduleId"`
+
+	// Status is the outcome of the run.
+	//
+	// Required: true
+	Status PruneScheduleRunStatus `json:"status"`
+
+	// StartedAt is when the run started.
+	//
+	// Required: true
+	StartedAt time.Time `json:"startedAt"`
+
+	// FinishedAt is when the run finished.
+	//
+	// Required: false
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// SpaceReclaimed is the space reclaimed by the run in bytes.
+	//
+	// Required: true
+	SpaceReclaimed uint64 `json:"spaceReclaimed"`
+
+	// ContainersPruned is the number of containers removed.
+	//
+	// Required: true
+	ContainersPruned int `json:"containersPruned"`
+
+	// ImagesDeleted is the number of images removed.
+	//
+	// Required: true
+	ImagesDeleted int `json:"imagesDeleted"`
+
+	// VolumesDeleted is the number of volumes removed.
+	//
+	// Required: true
+	VolumesDeleted int `json:"volumesDeleted"`
+
+	// NetworksDeleted is the number of networks removed.
+	//
+	// Required: true
+	NetworksDeleted int `json:"networksDeleted"`
+
+	// Errors lists the errors reported by the run.
+	//
+	// Required: false
+	Errors []string `json:"errors,omitempty"`
+
+	// ActivityID is the activity that tracked the prune.
+	//
+	// Required: false
+	ActivityID *string `json:"activityId,omitempty"`
+}
+
+// CreatePruneScheduleRequest creates a prune schedule.
+type CreatePruneScheduleRequest struct {
+	// Name is a human-readable label for the schedule.
+	//
+	// Required: true
+	Name string `json:"name" minLength:"1" maxLength:"255"`
+
+	// Schedule is a six-field cron expression with seconds, or a descriptor such as @daily.
+	//
+	// Required: true
+	Schedule string `json:"schedule" minLength:"1"`
+
+	// Enabled installs the schedule straight away. Defaults to true.
+	//
+	// Required: false
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Policy is the prune request issued on every run.
+	//
+	// Required: true
+	Policy PruneAllRequest `json:"policy"`
+}
+
+// UpdatePruneScheduleRequest changes a prune schedule. Omitted fields keep their value.
+type UpdatePruneScheduleRequest struct {
+	// Name is a human-readable label for the schedule.
+	//
+	// Required: false
+	Name *string `json:"name,omitempty" minLength:"1" maxLength:"255"`
+
+	// Schedule is a six-field cron expression with seconds, or a descriptor such as @daily.
+	//
+	// Required: false
+	Schedule *string `json:"schedule,omitempty" minLength:"1"`
+
+	// Enabled installs or removes the schedule.
+	//
+	// Required: false
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Policy replaces the prune request issued on every run.
+	//
+	// Required: false
+	Policy *PruneAllRequest `json:"policy,omitempty"`
+}
@@ -18,6 +18,20 @@ type GPUStats struct {
 	//
 	// Required: true
 	MemoryTotal float64 `json:"memoryTotal"`
+	// UtilizationPercent is the GPU core utilization percentage, when the driver reports it.
+	//
+	// Required: false
+	UtilizationPercent *float64 `json:"utilizationPercent,omitempty"`
+	// TemperatureC is the GPU temperature in degrees Celsius, when the driver reports it.
+	//
+	// Required: false
+	TemperatureC *float64 `json:"temperatureC,omitempty"`
+	// Extra holds the raw values of additional nvidia-smi query fields configured in
+	// settings, keyed by field name (for example "power.draw"). Values the driver
+	// reports as N/A are omitted.
+	//
+	// Required: false
+	Extra map[string]string `json:"extra,omitempty"`
 }
 
 // SystemStats represents system resource statistics for WebSocket streaming.
@@ -34,10 +48,32 @@ type SystemStats struct {
 	//
 	// Required: true
 	MemoryTotal uint64 `json:"memoryTotal"`
+	// SwapUsed is the used swap space, in bytes.
+	SwapUsed uint64 `json:"swapUsed,omitempty"`
+	// SwapTotal is the total swap space, in bytes. Omitted when swap is disabled.
+	SwapTotal uint64 `json:"swapTotal,omitempty"`
+	// Load1 is the 1-minute load average. Omitted on platforms without load averages.
+	Load1 *float64 `json:"load1,omitempty"`
+	// Load5 is the 5-minute load average. Omitted on platforms without load averages.
+	Load5 *float64 `json:"load5,omitempty"`
+	// Load15 is the 15-minute load average. Omitted on platforms without load averages.
+	Load15 *float64 `json:"load15,omitempty"`
 	// DiskUsage is the used disk space, in bytes.
 	DiskUsage uint64 `json:"diskUsage,omitempty"`
 	// DiskTotal is the total disk space, in bytes.
 	DiskTotal uint64 `json:"diskTotal,omitempty"`
+	// DiskReadBytesPerSec is the rate of bytes read from physical disks since the
+	// previous sample. Omitted on the first sample and where counters are unavailable.
+	DiskReadBytesPerSec *float64 `json:"diskReadBytesPerSec,omitempty"`
+	// DiskWriteBytesPerSec is the rate of bytes written to physical disks since the
+	// previous sample. Omitted on the first sample and where counters are unavailable.
+	DiskWriteBytesPerSec *float64 `json:"diskWriteBytesPerSec,omitempty"`
+	// NetRxBytesPerSec is the rate of bytes received on non-loopback interfaces since
+	// the previous sample. Omitted on the first sample and where counters are unavailable.
+	NetRxBytesPerSec *float64 `json:"netRxBytesPerSec,omitempty"`
+	// NetTxBytesPerSec is the rate of bytes sent on non-loopback interfaces since the
+	// previous sample. Omitted on the first sample and where counters are unavailable.
+	NetTxBytesPerSec *float64 `json:"netTxBytesPerSec,omitempty"`
 	// CPUCount is the number of CPUs available to the system.
 	//
 	// Required: true
@@ -10,6 +10,12 @@ const (
 	WSKindContainerExec  = "container_exec"
 	WSKindSystemStats    = "system_stats"
 	WSKindServiceLogs    = "service_logs"
+	WSKindSystemPrune    = "system_prune"
+	WSKindServiceEvents  = "service_events"
+	WSKindImagePull      = "image_pull"
+	WSKindProjectStats   = "project_stats"
+	WSKindProjectDeploy  = "project_deploy"
+	WSKindDockerEvents   = "docker_events"
 )
 
 // WebSocketConnectionInfo describes a single active WebSocket connection.
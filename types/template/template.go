@@ -21,6 +21,11 @@ type BaseTemplate struct {
 	//
 	// Required: true
 	Description string `json:"description"`
+
+	// Category groups the template in the catalog.
+	//
+	// Required: false
+	Category string `json:"category,omitempty"`
 }
 
 // RemoteTemplate represents a template from a remote registry.
@@ -115,6 +120,21 @@ type TemplateRegistry struct {
 	// Required: true
 	Enabled bool `json:"enabled"`
 
+	// AuthType is the authentication method used to fetch the registry (none, bearer, basic).
+	//
+	// Required: true
+	AuthType string `json:"authType"`
+
+	// Username for basic authentication.
+	//
+	// Required: false
+	Username string `json:"username,omitempty"`
+
+	// HasCredential indicates a token or password is stored. The secret itself is never returned.
+	//
+	// Required: true
+	HasCredential bool `json:"hasCredential"`
+
 	// LastFetchError is the error message from the most recent failed fetch, if any.
 	//
 	// Required: false
@@ -187,6 +207,12 @@ type Template struct {
 	//
 	// Required: false
 	Metadata *meta.TemplateMeta `json:"metadata,omitempty"`
+
+	// SourceURL is the upstream compose URL of a template downloaded from a
+	// registry. Only such templates can be checked for updates and refreshed.
+	//
+	// Required: false
+	SourceURL *string `json:"sourceUrl,omitempty"`
 }
 
 // CreateRequest represents the request to create a template.
@@ -210,6 +236,16 @@ type CreateRequest struct {
 	//
 	// Required: false
 	EnvContent string `json:"envContent"`
+
+	// Category groups the template in the catalog.
+	//
+	// Required: false
+	Category string `json:"category,omitempty"`
+
+	// Tags is a list of tags associated with the template.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty"`
 }
 
 // UpdateRequest represents the request to update a template.
@@ -233,6 +269,17 @@ type UpdateRequest struct {
 	//
 	// Required: false
 	EnvContent string `json:"envContent"`
+
+	// Category groups the template in the catalog.
+	//
+	// Required: false
+	Category string `json:"category,omitempty"`
+
+	// Tags replaces the template's tags. Omit it to keep the current tags; send
+	// an empty list to clear them.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty" doc:"Template tags; omit to keep the current tags, send an empty list to clear them"`
 }
 
 // DefaultTemplatesResponse contains the default compose, swarm stack, and env templates.
@@ -292,6 +339,21 @@ type CreateRegistryRequest struct {
 	//
 	// Required: false
 	Enabled bool `json:"enabled"`
+
+	// AuthType is the authentication method (none, bearer, basic). Defaults to none.
+	//
+	// Required: false
+	AuthType string `json:"authType,omitempty"`
+
+	// Username for basic authentication.
+	//
+	// Required: false
+	Username string `json:"username,omitempty"`
+
+	// Token is the bearer token, or the password for basic authentication.
+	//
+	// Required: false
+	Token string `json:"token,omitempty"`
 }
 
 // UpdateRegistryRequest represents the request to update a template registry.
@@ -315,4 +377,119 @@ type UpdateRegistryRequest struct {
 	//
 	// Required: false
 	Enabled bool `json:"enabled"`
+
+	// AuthType is the authentication method (none, bearer, basic). When omitted
+	// the stored authentication is left unchanged.
+	//
+	// Required: false
+	AuthType *string `json:"authType,omitempty"`
+
+	// Username for basic authentication.
+	//
+	// Required: false
+	Username *string `json:"username,omitempty"`
+
+	// Token is the bearer token, or the password for basic authentication.
+	// When omitted or empty the stored secret is kept.
+	//
+	// Required: false
+	Token *string `json:"token,omitempty"`
+}
+
+// ValidateRequest represents the request to validate a template's variables.
+type ValidateRequest struct {
+	// Variables are the values the caller intends to deploy the template with.
+	//
+	// Required: false
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ValidationResult reports how a template's variable placeholders resolve.
+type ValidationResult struct {
+	// Valid is true when no required variable is missing a value.
+	//
+	// Required: true
+	Valid bool `json:"valid"`
+
+	// Variables lists every variable referenced by the template, sorted by name.
+	//
+	// Required: true
+	Variables []string `json:"variables"`
+
+	// Missing lists referenced variables without a default that have no value
+	// from global variables, the template's env content or the request.
+	//
+	// Required: true
+	Missing []string `json:"missing"`
+
+	// Unused lists requested variables the template never references.
+	//
+	// Required: true
+	Unused []string `json:"unused"`
+}
+
+// DeployRequest represents the request to deploy a template as a new stack.
+type DeployRequest struct {
+	// EnvironmentID is the environment to create the stack in. Defaults to the
+	// local environment.
+	//
+	// Required: false
+	EnvironmentID string `json:"environmentId,omitempty"`
+
+	// StackName is the name of the stack to create. Defaults to the template name.
+	//
+	// Required: false
+	StackName string `json:"stackName,omitempty"`
+
+	// Variables override or extend the template's env content for this stack.
+	//
+	// Required: false
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// DeployResult references the stack created from a template.
+type DeployResult struct {
+	// ProjectID is the ID of the created stack.
+	//
+	// Required: true
+	ProjectID string `json:"projectId"`
+
+	// ProjectName is the name of the created stack.
+	//
+	// Required: true
+	ProjectName string `json:"projectName"`
+
+	// EnvironmentID is the environment the stack was created in.
+	//
+	// Required: true
+	EnvironmentID string `json:"environmentId"`
+}
+
+// UpdateStatus reports whether a downloaded template's upstream source changed.
+type UpdateStatus struct {
+	// TemplateID is the ID of the local template.
+	//
+	// Required: true
+	TemplateID string `json:"templateId"`
+
+	// Name is the name of the local template.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// SourceURL is the upstream compose URL that was checked.
+	//
+	// Required: true
+	SourceURL string `json:"sourceUrl"`
+
+	// HasUpdate is true when the upstream content differs from the content
+	// last downloaded or refreshed.
+	//
+	// Required: true
+	HasUpdate bool `json:"hasUpdate"`
+
+	// Error describes why the upstream source could not be checked.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
 }
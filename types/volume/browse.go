@@ -11,6 +11,10 @@ type FileEntry struct {
 	Size        int64     `json:"size" doc:"Size of the file in bytes"`
 	IsDirectory bool      `json:"isDirectory" doc:"Whether this entry is a directory"`
 	IsSymlink   bool      `json:"isSymlink" doc:"Whether this entry is a symbolic link"`
+	Owner       string    `json:"owner,omitempty" doc:"Owning user name, or the numeric UID when it has no name"`
+	Group       string    `json:"group,omitempty" doc:"Owning group name, or the numeric GID when it has no name"`
+	UID         *int      `json:"uid,omitempty" doc:"Numeric owner ID"`
+	GID         *int      `json:"gid,omitempty" doc:"Numeric group ID"`
 }
 
 type FileMetadata struct {
@@ -20,3 +24,13 @@ type FileMetadata struct {
 	IsText   bool   `json:"isText" doc:"Whether the file is a text file"`
 	IsBinary bool   `json:"isBinary" doc:"Whether the file is a binary file"`
 }
+
+// ListDirectoryOptions narrows and pages a directory listing.
+type ListDirectoryOptions struct {
+	// Filter is a shell glob matched against entry names (e.g. "*.conf"). Empty matches everything.
+	Filter string
+	// Offset is the number of matching entries to skip.
+	Offset int
+	// Limit caps the number of entries returned; 0 returns every remaining entry.
+	Limit int
+}